@@ -0,0 +1,119 @@
+package cmd
+
+import (
+	"encoding/json"
+	"strings"
+	"testing"
+)
+
+func TestAudit(t *testing.T) {
+	t.Run("lists versions across documents", func(t *testing.T) {
+		env := newTestEnv(t)
+		env.runStdin("one", "write", "docs/readme", "-a", "alice", "-m", "Initial")
+		env.runStdin("two", "write", "notes/todo", "-a", "bob")
+		env.runStdin("three", "write", "docs/readme", "-a", "carol", "-m", "Update")
+
+		out := env.run("audit")
+		env.contains(out, "docs/readme v1")
+		env.contains(out, "notes/todo v1")
+		env.contains(out, "docs/readme v2")
+		env.contains(out, "carol")
+		env.contains(out, `"Update"`)
+		if strings.Index(out, "docs/readme v1") > strings.Index(out, "docs/readme v2") {
+			t.Errorf("audit should list oldest first:\n%s", out)
+		}
+	})
+
+	t.Run("prefix scope", func(t *testing.T) {
+		env := newTestEnv(t)
+		env.runStdin("one", "write", "docs/readme")
+		env.runStdin("two", "write", "notes/todo")
+
+		out := env.run("audit", "docs/")
+		env.contains(out, "docs/readme")
+		if strings.Contains(out, "notes/todo") {
+			t.Errorf("audit docs/ should not include notes/todo:\n%s", out)
+		}
+	})
+
+	t.Run("deleted documents are flagged", func(t *testing.T) {
+		env := newTestEnv(t)
+		env.runStdin("gone", "write", "docs/old")
+		env.run("rm", "docs/old")
+
+		out := env.run("audit")
+		env.contains(out, "docs/old v1")
+		env.contains(out, "(deleted)")
+	})
+
+	t.Run("time range", func(t *testing.T) {
+		env := newTestEnv(t)
+		env.runStdin("one", "write", "docs/readme")
+
+		out := env.run("audit", "--since", "1h")
+		env.contains(out, "docs/readme v1")
+
+		out = env.run("audit", "--until", "1h")
+		if strings.Contains(out, "docs/readme") {
+			t.Errorf("audit --until 1h should exclude a version written now:\n%s", out)
+		}
+	})
+
+	t.Run("since after until", func(t *testing.T) {
+		env := newTestEnv(t)
+		env.runStdin("one", "write", "docs/readme")
+
+		out, err := env.runErr("audit", "--since", "2024-02-01", "--until", "2024-01-01")
+		if err == nil {
+			t.Fatal("expected error when --since is after --until")
+		}
+		env.contains(out, "--since must be before --until")
+	})
+
+	t.Run("invalid time", func(t *testing.T) {
+		env := newTestEnv(t)
+		env.runStdin("one", "write", "docs/readme")
+
+		if _, err := env.runErr("audit", "--since", "yesterday-ish"); err == nil {
+			t.Fatal("expected error for invalid --since")
+		}
+	})
+
+	t.Run("JSON output", func(t *testing.T) {
+		env := newTestEnv(t)
+		env.runStdin("one", "write", "docs/readme", "-a", "alice", "-m", "Initial")
+
+		out := env.run("audit", "-o", "json")
+		var entries []struct {
+			Path    string `json:"path"`
+			Version int    `json:"version"`
+			Author  string `json:"author"`
+			Message string `json:"message"`
+			Size    int64  `json:"size"`
+		}
+		if err := json.Unmarshal([]byte(out), &entries); err != nil {
+			t.Fatalf("audit -o json: %v\n%s", err, out)
+		}
+		if len(entries) != 1 {
+			t.Fatalf("audit -o json = %d entries, want 1", len(entries))
+		}
+		e := entries[0]
+		if e.Path != "docs/readme" || e.Version != 1 || e.Author != "alice" || e.Message != "Initial" || e.Size != 3 {
+			t.Errorf("audit -o json entry = %+v", e)
+		}
+	})
+
+	t.Run("CSV output", func(t *testing.T) {
+		env := newTestEnv(t)
+		env.runStdin("one", "write", "docs/readme", "-a", "Smith, Bob")
+
+		out := env.run("audit", "-o", "csv")
+		lines := strings.Split(strings.TrimSpace(out), "\n")
+		if len(lines) != 2 {
+			t.Fatalf("audit -o csv = %d lines, want 2:\n%s", len(lines), out)
+		}
+		env.equals(lines[0], "created_at,path,version,key,author,message,size,deleted")
+		env.contains(lines[1], `docs/readme,1,`)
+		env.contains(lines[1], `"Smith, Bob",,3,false`)
+	})
+}
@@ -0,0 +1,58 @@
+package cmd
+
+import (
+	"encoding/json"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestCheckLinks(t *testing.T) {
+	env := newTestEnv(t)
+	env.runStdin("# API\n", "write", "docs/api")
+	env.runStdin("# Readme\n\nSee [the API](docs/api.md).\nAnd [install](docs/install).\n", "write", "docs/readme")
+	env.runStdin("# Notes\n\nNothing linked, [web](https://example.com).\n", "write", "notes/todo")
+
+	t.Run("reports missing targets", func(t *testing.T) {
+		out, err := env.runErr("check-links")
+		require.Error(t, err)
+		env.contains(out, "docs/readme:4 -> docs/install")
+		assert.NotContains(t, out, "docs/api\n")
+	})
+
+	t.Run("prefix", func(t *testing.T) {
+		env.run("check-links", "notes/")
+	})
+
+	t.Run("unlinked", func(t *testing.T) {
+		env.runStdin("# Readme\n\nSee [the API](docs/api.md).\n", "write", "docs/readme")
+
+		out, err := env.runErr("check-links", "--unlinked")
+		require.Error(t, err)
+		env.contains(out, "docs/readme:3 -> docs/api (unlinked)")
+
+		env.run("link", "docs/api", "docs/readme")
+		env.run("check-links", "--unlinked")
+	})
+
+	t.Run("json", func(t *testing.T) {
+		env.runStdin("[gone](docs/gone)\n", "write", "docs/broken")
+		out := env.run("check-links", "-o", "json")
+		var r struct {
+			OK     bool `json:"ok"`
+			Issues []struct {
+				Source  string `json:"source"`
+				Line    int    `json:"line"`
+				Target  string `json:"target"`
+				Problem string `json:"problem"`
+			} `json:"issues"`
+		}
+		require.NoError(t, json.Unmarshal([]byte(out), &r))
+		assert.False(t, r.OK)
+		require.Len(t, r.Issues, 1)
+		assert.Equal(t, "docs/broken", r.Issues[0].Source)
+		assert.Equal(t, "docs/gone", r.Issues[0].Target)
+		assert.Equal(t, "missing", r.Issues[0].Problem)
+	})
+}
@@ -32,8 +32,13 @@ func TestConfig_Set(t *testing.T) {
 	}{
 		{"author name", "author.name", "New Name"},
 		{"author email", "author.email", "new@example.com"},
+		{"author required", "author.required", "true"},
 		{"sync files true", "sync.files", "true"},
 		{"sync files false", "sync.files", "false"},
+		{"busy timeout", "store.busy_timeout", "30000"},
+		{"search tokenizer", "search.tokenizer", "unicode61 remove_diacritics 0"},
+		{"mcp max write", "mcp.max_write", "4096"},
+		{"mcp writes per minute", "mcp.writes_per_minute", "30"},
 	}
 
 	for _, tc := range tests {
@@ -66,4 +71,69 @@ func TestConfig_Errors(t *testing.T) {
 			t.Error("Config(invalid value) = nil, want error")
 		}
 	})
+
+	t.Run("busy timeout out of range", func(t *testing.T) {
+		env := newTestEnv(t)
+
+		_, err := env.runErr("config", "store.busy_timeout", "0")
+		if err == nil {
+			t.Error("Config(store.busy_timeout 0) = nil, want error")
+		}
+	})
+
+	t.Run("quoted search tokenizer", func(t *testing.T) {
+		env := newTestEnv(t)
+
+		_, err := env.runErr("config", "search.tokenizer", "unicode61'")
+		if err == nil {
+			t.Error("Config(search.tokenizer with a quote) = nil, want error")
+		}
+	})
+
+	t.Run("negative mcp writes per minute", func(t *testing.T) {
+		env := newTestEnv(t)
+
+		_, err := env.runErr("config", "mcp.writes_per_minute", "-1")
+		if err == nil {
+			t.Error("Config(mcp.writes_per_minute -1) = nil, want error")
+		}
+	})
+}
+
+func TestConfig_Author(t *testing.T) {
+	env := newTestEnv(t)
+
+	env.run("config", "author", "Jane Doe <jane@example.com>")
+	env.equals(env.run("config", "author.name"), "Jane Doe")
+	env.equals(env.run("config", "author.email"), "jane@example.com")
+	env.equals(env.run("config", "author"), "Jane Doe <jane@example.com>")
+
+	// A bare name replaces the email too
+	env.run("config", "author", "CI Bot")
+	env.equals(env.run("config", "author"), "CI Bot")
+	env.equals(env.run("config", "author.email"), "")
+
+	for _, v := range []string{"", "<jane@example.com>", "Jane <not an email>"} {
+		if _, err := env.runErr("config", "author", v); err == nil {
+			t.Errorf("Config(author %q) = nil, want error", v)
+		}
+	}
+}
+
+func TestWhoami(t *testing.T) {
+	env := newTestEnv(t)
+
+	// newTestEnv configures the author locally
+	env.contains(env.run("whoami"), "test (local config")
+
+	env.run("config", "author", "Jane Doe <jane@example.com>")
+	env.contains(env.run("whoami"), "Jane Doe <jane@example.com> (local config")
+
+	out := env.run("whoami", "-a", "reviewer", "-o", "json")
+	env.equals(out, `{"name":"reviewer","source":"flag"}`)
+
+	env.run("config", "author.name", "")
+	if _, err := env.runErr("whoami"); err == nil {
+		t.Error("Whoami(no author) = nil, want error")
+	}
 }
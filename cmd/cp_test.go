@@ -0,0 +1,86 @@
+package cmd
+
+import (
+	"strings"
+	"testing"
+)
+
+func TestCp(t *testing.T) {
+	t.Run("single", func(t *testing.T) {
+		env := newTestEnv(t)
+		env.runStdin("v1", "write", "docs/a")
+		env.runStdin("v2", "write", "docs/a")
+
+		out := env.run("cp", "docs/a", "docs/b")
+		env.contains(out, "Copied docs/a -> docs/b")
+		env.equals(env.run("cat", "docs/b"), "v2")
+		env.equals(env.run("cat", "docs/a"), "v2")
+
+		// The copy starts a fresh history
+		out = env.run("history", "docs/b")
+		env.contains(out, "Copied from docs/a")
+		if strings.Contains(out, " v2 ") {
+			t.Errorf("copy should start at version 1, got history:\n%s", out)
+		}
+	})
+
+	t.Run("into prefix", func(t *testing.T) {
+		env := newTestEnv(t)
+		env.runStdin("a", "write", "docs/a")
+
+		env.run("cp", "docs/a", "archive/")
+		env.equals(env.run("cat", "archive/a"), "a")
+	})
+
+	t.Run("existing destination", func(t *testing.T) {
+		env := newTestEnv(t)
+		env.runStdin("a", "write", "docs/a")
+		env.runStdin("b", "write", "docs/b")
+
+		if _, err := env.runErr("cp", "docs/a", "docs/b"); err == nil {
+			t.Error("Cp to existing path = nil, want error")
+		}
+		env.equals(env.run("cat", "docs/b"), "b")
+	})
+}
+
+func TestCp_Recursive(t *testing.T) {
+	t.Run("subtree", func(t *testing.T) {
+		env := newTestEnv(t)
+		env.runStdin("a", "write", "docs/a")
+		env.runStdin("b", "write", "docs/api/b")
+		env.runStdin("other", "write", "notes/c")
+
+		out := env.run("cp", "-r", "docs/", "archive/")
+		env.contains(out, "Copied 2 document(s) from docs/ to archive/")
+		env.equals(env.run("cat", "archive/a"), "a")
+		env.equals(env.run("cat", "archive/api/b"), "b")
+		env.equals(env.run("cat", "docs/a"), "a")
+		if _, err := env.runErr("cat", "archive/c"); err == nil {
+			t.Error("Cp -r copied a document outside the prefix")
+		}
+	})
+
+	t.Run("collision copies nothing", func(t *testing.T) {
+		env := newTestEnv(t)
+		env.runStdin("a", "write", "docs/a")
+		env.runStdin("b", "write", "docs/b")
+		env.runStdin("old", "write", "archive/b")
+
+		if _, err := env.runErr("cp", "-r", "docs/", "archive/"); err == nil {
+			t.Fatal("Cp -r onto existing path = nil, want error")
+		}
+		if _, err := env.runErr("cat", "archive/a"); err == nil {
+			t.Error("Cp -r left a partial copy")
+		}
+		env.equals(env.run("cat", "archive/b"), "old")
+	})
+
+	t.Run("JSON output", func(t *testing.T) {
+		env := newTestEnv(t)
+		env.runStdin("a", "write", "docs/a")
+
+		out := env.run("cp", "-r", "docs", "snap", "-o", "json")
+		env.contains(out, `"count":1`)
+	})
+}
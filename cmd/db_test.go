@@ -1,11 +1,16 @@
 package cmd
 
 import (
+	"encoding/json"
+	"fmt"
+	"net/http"
 	"os"
 	"os/exec"
 	"path/filepath"
+	"strings"
 	"testing"
 
+	"github.com/jpl-au/llmd/internal/store"
 	"github.com/stretchr/testify/assert"
 	"github.com/stretchr/testify/require"
 )
@@ -176,3 +181,208 @@ func TestDB(t *testing.T) {
 		assert.Contains(t, string(gitignore), "llmd.db")
 	})
 }
+
+func TestEphemeral(t *testing.T) {
+	t.Run("no store required", func(t *testing.T) {
+		dir := t.TempDir()
+		binary := buildBinary(t)
+
+		cmd := exec.Command(binary, "--ephemeral", "write", "notes/scratch", "-a", "tester")
+		cmd.Dir = dir
+		cmd.Stdin = strings.NewReader("scratch content")
+		out, err := cmd.CombinedOutput()
+		require.NoError(t, err, "ephemeral write failed: %s", out)
+
+		_, err = os.Stat(filepath.Join(dir, ".llmd"))
+		assert.True(t, os.IsNotExist(err), "ephemeral mode must not create a store")
+	})
+
+	t.Run("does not touch the real store", func(t *testing.T) {
+		env := newTestEnv(t)
+		env.runStdin("kept", "write", "docs/kept")
+		env.runStdin("scratch", "--ephemeral", "write", "docs/scratch")
+
+		out := env.run("--ephemeral", "ls", "-R")
+		assert.NotContains(t, out, "docs/kept")
+
+		out = env.run("ls", "-R")
+		assert.Contains(t, out, "docs/kept")
+		assert.NotContains(t, out, "docs/scratch")
+	})
+
+	t.Run("serve", func(t *testing.T) {
+		dir := t.TempDir()
+		env := &testEnv{t: t, dir: dir, binary: buildBinary(t)}
+		addr := freeAddr(t)
+		startServe(t, env, addr, "--ephemeral", "--transport", "streamable-http", "--addr", addr)
+		call := mcpSession(t, addr)
+
+		out := call(`{"jsonrpc":"2.0","id":2,"method":"tools/call","params":{"name":"llmd_write","arguments":{"path":"docs/a","content":"in memory","author":"tester"}}}`)
+		assert.NotContains(t, out, `"isError":true`)
+		out = call(`{"jsonrpc":"2.0","id":3,"method":"tools/call","params":{"name":"llmd_read","arguments":{"paths":["docs/a"]}}}`)
+		assert.Contains(t, out, "in memory")
+
+		_, err := os.Stat(filepath.Join(dir, ".llmd"))
+		assert.True(t, os.IsNotExist(err), "ephemeral server must not create a store")
+	})
+}
+
+func TestReadOnly(t *testing.T) {
+	t.Run("cli", func(t *testing.T) {
+		env := newTestEnv(t)
+		env.runStdin("hello", "write", "docs/readme")
+
+		out := env.run("--read-only", "cat", "docs/readme")
+		assert.Contains(t, out, "hello")
+
+		for _, args := range [][]string{
+			{"--read-only", "rm", "docs/readme"},
+			{"--read-only", "mv", "docs/readme", "docs/moved"},
+			{"--read-only", "tag", "add", "docs/readme", "t"},
+			{"--read-only", "vacuum", "--force"},
+		} {
+			out, err := env.runErr(args...)
+			assert.Error(t, err, "%v", args)
+			assert.Contains(t, out, "read-only", "%v", args)
+		}
+
+		out, err := env.runStdinErr("changed", "--read-only", "write", "docs/readme")
+		assert.Error(t, err)
+		assert.Contains(t, out, "read-only")
+		env.equals(env.run("cat", "docs/readme"), "hello")
+	})
+
+	t.Run("ephemeral conflict", func(t *testing.T) {
+		env := newTestEnv(t)
+		_, err := env.runErr("--read-only", "--ephemeral", "ls")
+		assert.Error(t, err)
+	})
+
+	t.Run("serve withholds mutating tools", func(t *testing.T) {
+		env := newTestEnv(t)
+		env.runStdin("hello", "write", "docs/readme")
+
+		addr := freeAddr(t)
+		startServe(t, env, addr, "--read-only", "--transport", "streamable-http", "--addr", addr)
+		call := mcpSession(t, addr)
+
+		out := call(`{"jsonrpc":"2.0","id":2,"method":"tools/list"}`)
+		assert.Contains(t, out, `"name":"llmd_read"`)
+		assert.Contains(t, out, `"name":"llmd_search"`)
+		assert.NotContains(t, out, `"name":"llmd_write"`)
+		assert.NotContains(t, out, `"name":"llmd_init"`)
+
+		out = call(`{"jsonrpc":"2.0","id":3,"method":"tools/call","params":{"name":"llmd_write","arguments":{"path":"docs/readme","content":"changed","author":"tester"}}}`)
+		assert.Contains(t, out, `"error"`)
+	})
+
+	t.Run("rest rejects changes", func(t *testing.T) {
+		env := newTestEnv(t)
+		addr := freeAddr(t)
+		startServe(t, env, addr, "--read-only", "--http", addr)
+
+		req, err := http.NewRequest(http.MethodPut, "http://"+addr+"/documents/docs/readme", strings.NewReader("changed"))
+		require.NoError(t, err)
+		req.Header.Set("X-Llmd-Author", "tester")
+		resp, err := http.DefaultClient.Do(req)
+		require.NoError(t, err)
+		resp.Body.Close()
+		assert.Equal(t, http.StatusForbidden, resp.StatusCode)
+	})
+}
+
+func TestVerbose(t *testing.T) {
+	env := newTestEnv(t)
+	env.runStdin("hello world", "write", "docs/readme")
+
+	cmd := exec.Command(env.binary, "--verbose", "-o", "json", "grep", "hello")
+	cmd.Dir = env.dir
+	var stdout, stderr strings.Builder
+	cmd.Stdout = &stdout
+	cmd.Stderr = &stderr
+	require.NoError(t, cmd.Run(), "stderr: %s", stderr.String())
+
+	assert.Contains(t, stderr.String(), "service:list-containing read")
+	assert.Contains(t, stderr.String(), "search:grep match")
+	assert.NotContains(t, stdout.String(), "llmd:")
+	assert.True(t, json.Valid([]byte(stdout.String())), "stdout: %s", stdout.String())
+
+	out := env.run("grep", "hello")
+	assert.NotContains(t, out, "service:")
+}
+
+func TestDB_Verify(t *testing.T) {
+	env := newTestEnv(t)
+	env.runStdin("content", "write", "docs/a")
+
+	out := env.run("db", "verify")
+	assert.Contains(t, out, "OK: no issues found")
+
+	out = env.run("db", "verify", "-o", "json")
+	assert.Contains(t, out, `"ok":true`)
+	assert.Contains(t, out, `"issues":[]`)
+
+	// A link left pointing at a deleted document
+	env.runStdin("content", "write", "docs/b")
+	env.run("link", "docs/a", "docs/b")
+	s, err := store.OpenWithOptions(filepath.Join(env.dir, ".llmd", "llmd.db"), store.OpenOptions{})
+	require.NoError(t, err)
+	_, err = s.DB().Exec(`UPDATE documents SET deleted_at = 1 WHERE path = 'docs/b'`)
+	require.NoError(t, err)
+	require.NoError(t, s.Close())
+
+	_, err = env.runErr("db", "verify")
+	assert.Error(t, err)
+	out, err = env.runErr("db", "verify", "-o", "json")
+	assert.Error(t, err, "JSON mode exits non-zero like text mode")
+	assert.Contains(t, out, `"ok":false`)
+}
+
+func TestDB_Reindex(t *testing.T) {
+	env := newTestEnv(t)
+	env.runStdin("alpha content", "write", "docs/a")
+	env.runStdin("alpha again", "write", "docs/a")
+	env.runStdin("bravo content", "write", "docs/b")
+
+	out := env.run("db", "reindex")
+	assert.Contains(t, out, "Reindexed 3 document version(s)")
+
+	out = env.run("db", "reindex", "-o", "json")
+	assert.Contains(t, out, `"reindexed":3`)
+
+	out = env.run("find", "bravo")
+	assert.Contains(t, out, "docs/b")
+}
+
+func TestDB_Migrate(t *testing.T) {
+	env := newTestEnv(t)
+	env.runStdin("hello", "write", "docs/a")
+
+	latest := store.LatestSchemaVersion()
+	out := env.run("db", "migrate")
+	assert.Contains(t, out, fmt.Sprintf("Schema version %d (latest)", latest))
+	assert.NotContains(t, out, "Applied")
+
+	// Rewind the recorded version, as for a store from before the last migration
+	s, err := store.OpenWithOptions(filepath.Join(env.dir, ".llmd", "llmd.db"), store.OpenOptions{NoMigrate: true})
+	require.NoError(t, err)
+	_, err = s.DB().Exec(`DELETE FROM schema_version WHERE version > 1`)
+	require.NoError(t, err)
+	require.NoError(t, s.Close())
+
+	out = env.run("db", "migrate", "--dry-run")
+	assert.Contains(t, out, "Pending 2:")
+	assert.Contains(t, out, fmt.Sprintf("Schema version 1 of %d", latest))
+
+	out = env.run("db", "migrate", "2", "-o", "json")
+	assert.Contains(t, out, `"from":1,"to":2`)
+	assert.Contains(t, out, `"applied":[{"version":2,`)
+
+	_, err = env.runErr("db", "migrate", "1")
+	assert.Error(t, err, "migrations cannot be undone")
+
+	out = env.run("db", "migrate")
+	assert.Contains(t, out, fmt.Sprintf("Applied %d:", latest))
+	assert.Contains(t, out, fmt.Sprintf("Schema version %d (latest)", latest))
+	env.equals(env.run("cat", "docs/a"), "hello")
+}
@@ -0,0 +1,47 @@
+package cmd
+
+import (
+	"strings"
+	"testing"
+)
+
+func TestDedup(t *testing.T) {
+	t.Run("exact", func(t *testing.T) {
+		env := newTestEnv(t)
+		env.runStdin("# Setup\nInstall it.\n", "write", "docs/setup")
+		env.runStdin("# Setup\nInstall it.\n", "write", "notes/setup-copy")
+		env.runStdin("# Other\n", "write", "docs/other")
+
+		out := env.run("dedup")
+		env.contains(out, "Identical:\n  docs/setup\n  notes/setup-copy\n")
+		if strings.Contains(out, "docs/other") {
+			t.Errorf("Dedup() reported a unique document:\n%s", out)
+		}
+
+		env.contains(env.run("dedup", "docs/"), "No duplicates found")
+	})
+
+	t.Run("similar", func(t *testing.T) {
+		env := newTestEnv(t)
+		text := "Run the installer, accept the licence, choose a folder and wait for it to finish."
+		env.runStdin(text, "write", "docs/install")
+		env.runStdin(text+" Then restart.", "write", "docs/install-old")
+
+		out := env.run("dedup")
+		env.contains(out, "No duplicates found")
+
+		out = env.run("dedup", "--similar", "0.8")
+		env.contains(out, "Similar (")
+		env.contains(out, "  docs/install\n  docs/install-old\n")
+
+		out = env.run("dedup", "--similar", "0.8", "-o", "json")
+		env.contains(out, `"near":[{"paths":["docs/install","docs/install-old"]`)
+	})
+
+	t.Run("invalid similarity", func(t *testing.T) {
+		env := newTestEnv(t)
+		if _, err := env.runErr("dedup", "--similar", "2"); err == nil {
+			t.Error("Dedup(--similar 2) = nil, want error")
+		}
+	})
+}
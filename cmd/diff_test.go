@@ -1,7 +1,9 @@
 package cmd
 
 import (
+	"errors"
 	"os"
+	"os/exec"
 	"path/filepath"
 	"testing"
 
@@ -63,6 +65,111 @@ func TestDiff_FilesystemFile(t *testing.T) {
 	env.contains(out, "readme")
 }
 
+func TestDiff_File(t *testing.T) {
+	env := newTestEnv(t)
+	env.runStdin("alpha\n", "write", "docs/readme")
+	env.runStdin("bravo\n", "write", "docs/readme")
+
+	local := filepath.Join(env.dir, "local.md")
+	require.NoError(t, os.WriteFile(local, []byte("charlie\n"), 0644))
+
+	t.Run("latest", func(t *testing.T) {
+		out := env.run("diff", "docs/readme", "--file", local, "--raw")
+		env.contains(out, "docs/readme (v2)")
+		env.contains(out, "- bravo")
+		env.contains(out, "+ charlie")
+	})
+
+	t.Run("version", func(t *testing.T) {
+		out := env.run("diff", "docs/readme", "--file", local, "-v", "1", "--raw")
+		env.contains(out, "docs/readme (v1)")
+		env.contains(out, "- alpha")
+	})
+
+	t.Run("empty file", func(t *testing.T) {
+		empty := filepath.Join(env.dir, "empty.md")
+		require.NoError(t, os.WriteFile(empty, nil, 0644))
+		out := env.run("diff", "docs/readme", "--file", empty, "--raw")
+		env.contains(out, "- bravo")
+	})
+
+	t.Run("missing file", func(t *testing.T) {
+		out, err := env.runErr("diff", "docs/readme", "--file", filepath.Join(env.dir, "nope.md"))
+		require.Error(t, err)
+		assert.Contains(t, out, "file not found")
+	})
+
+	t.Run("version range rejected", func(t *testing.T) {
+		_, err := env.runErr("diff", "docs/readme", "--file", local, "-v", "1:2")
+		assert.Error(t, err)
+	})
+}
+
+func TestDiff_Check(t *testing.T) {
+	env := newTestEnv(t)
+	env.runStdin("alpha\n", "write", "docs/readme")
+	env.runStdin("alpha\n", "write", "docs/same")
+	env.runStdin("bravo\n", "write", "docs/other")
+
+	exitCode := func(err error) int {
+		var ee *exec.ExitError
+		if errors.As(err, &ee) {
+			return ee.ExitCode()
+		}
+		require.NoError(t, err)
+		return 0
+	}
+
+	t.Run("identical", func(t *testing.T) {
+		out, err := env.runErr("diff", "docs/readme", "docs/same", "--check")
+		assert.Equal(t, 0, exitCode(err))
+		assert.Empty(t, out)
+	})
+
+	t.Run("differ", func(t *testing.T) {
+		out, err := env.runErr("diff", "docs/readme", "docs/other", "--check")
+		assert.Equal(t, 2, exitCode(err))
+		assert.Empty(t, out)
+	})
+
+	t.Run("quiet alias with file", func(t *testing.T) {
+		local := filepath.Join(env.dir, "local.md")
+		require.NoError(t, os.WriteFile(local, []byte("alpha\n"), 0644))
+		_, err := env.runErr("diff", "docs/readme", "-f", local, "--quiet")
+		assert.Equal(t, 0, exitCode(err))
+		_, err = env.runErr("diff", "docs/other", "-f", local, "--quiet")
+		assert.Equal(t, 2, exitCode(err))
+	})
+
+	t.Run("error", func(t *testing.T) {
+		_, err := env.runErr("diff", "docs/missing", "--check")
+		assert.Equal(t, 1, exitCode(err))
+	})
+}
+
+func TestDiff_Color(t *testing.T) {
+	env := newTestEnv(t)
+	env.runStdin("alpha\n", "write", "docs/readme")
+	env.runStdin("bravo\n", "write", "docs/readme")
+
+	// Test output is a pipe, so auto means plain
+	out := env.run("diff", "docs/readme")
+	assert.NotContains(t, out, "\033[")
+
+	out = env.run("diff", "docs/readme", "--color", "always")
+	env.contains(out, "\033[32m+ bravo")
+	env.contains(out, "\033[31m- alpha")
+
+	out = env.run("diff", "docs/readme", "--color", "always", "--raw")
+	assert.NotContains(t, out, "\033[")
+
+	out = env.run("diff", "docs/readme", "--color", "always", "-o", "json")
+	assert.NotContains(t, out, "\\u001b")
+
+	_, err := env.runErr("diff", "docs/readme", "--color", "sometimes")
+	assert.Error(t, err)
+}
+
 func TestDiff_NoChanges(t *testing.T) {
 	env := newTestEnv(t)
 	env.runStdin("same content", "write", "docs/readme")
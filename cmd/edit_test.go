@@ -133,6 +133,191 @@ More content in the replaced section.`
 	})
 }
 
+func TestEdit_LineRangeDryRun(t *testing.T) {
+	t.Run("does not write a version", func(t *testing.T) {
+		env := newTestEnv(t)
+		content := "line 1\nline 2\nline 3"
+		env.runStdin(content, "write", "docs/lines")
+
+		out := env.runStdin("replaced", "edit", "docs/lines", "-l", "2:2", "--dry-run")
+		env.contains(out, "- line 2")
+		env.contains(out, "+ replaced")
+
+		env.equals(env.run("cat", "docs/lines"), content)
+		history := env.run("history", "docs/lines")
+		if strings.Contains(history, " v2 ") {
+			t.Errorf("dry run should not create a version, got history:\n%s", history)
+		}
+	})
+
+	t.Run("JSON output", func(t *testing.T) {
+		env := newTestEnv(t)
+		env.runStdin("line 1\nline 2\nline 3", "write", "docs/lines")
+
+		out := env.runStdin("new 2\nnew 3", "edit", "docs/lines", "-l", "2:3", "-n", "-o", "json")
+		env.contains(out, `"dry_run":true`)
+		env.contains(out, `"old":"line 2\nline 3"`)
+		env.contains(out, `"new":"new 2\nnew 3"`)
+		env.contains(out, `"diff"`)
+	})
+
+	t.Run("requires line range", func(t *testing.T) {
+		env := newTestEnv(t)
+		env.runStdin("content", "write", "docs/lines")
+
+		_, err := env.runErr("edit", "docs/lines", "content", "other", "--dry-run")
+		if err == nil {
+			t.Error("--dry-run without -l should fail")
+		}
+	})
+}
+
+func TestEdit_AppendPrepend(t *testing.T) {
+	t.Run("append", func(t *testing.T) {
+		env := newTestEnv(t)
+		env.runStdin("# Notes\n\nFirst entry.", "write", "docs/notes")
+
+		env.runStdin("Second entry.\n", "edit", "docs/notes", "--append")
+
+		out := env.run("cat", "docs/notes")
+		env.contains(out, "First entry.\nSecond entry.")
+	})
+
+	t.Run("prepend", func(t *testing.T) {
+		env := newTestEnv(t)
+		env.runStdin("Body text.\n", "write", "docs/notes")
+
+		env.runStdin("# Title", "edit", "docs/notes", "--prepend")
+
+		out := env.run("cat", "docs/notes")
+		env.contains(out, "# Title\nBody text.")
+	})
+
+	t.Run("creates a version", func(t *testing.T) {
+		env := newTestEnv(t)
+		env.runStdin("one\n", "write", "docs/notes")
+
+		env.runStdin("two\n", "edit", "docs/notes", "--append", "-m", "Add two")
+
+		out := env.run("history", "docs/notes")
+		env.contains(out, "Add two")
+	})
+
+	t.Run("cannot combine with line range", func(t *testing.T) {
+		env := newTestEnv(t)
+		env.runStdin("one\n", "write", "docs/notes")
+
+		_, err := env.runStdinErr("two", "edit", "docs/notes", "--append", "-l", "1:1")
+		if err == nil {
+			t.Error("--append with -l should fail")
+		}
+	})
+}
+
+func TestEdit_InsertAt(t *testing.T) {
+	t.Run("inserts before line", func(t *testing.T) {
+		env := newTestEnv(t)
+		env.runStdin("line 1\nline 2\nline 3\n", "write", "docs/notes")
+
+		env.runStdin("inserted\n", "edit", "docs/notes", "--insert-at", "2")
+
+		out := env.run("cat", "docs/notes")
+		env.contains(out, "line 1\ninserted\nline 2\nline 3")
+	})
+
+	t.Run("last line plus one appends", func(t *testing.T) {
+		env := newTestEnv(t)
+		env.runStdin("line 1\nline 2\n", "write", "docs/notes")
+
+		env.runStdin("line 3\n", "edit", "docs/notes", "--insert-at", "3")
+
+		out := env.run("cat", "docs/notes")
+		env.contains(out, "line 1\nline 2\nline 3")
+	})
+
+	t.Run("out of range", func(t *testing.T) {
+		env := newTestEnv(t)
+		env.runStdin("line 1\nline 2\n", "write", "docs/notes")
+
+		for _, at := range []string{"0", "4"} {
+			if _, err := env.runStdinErr("x", "edit", "docs/notes", "--insert-at", at); err == nil {
+				t.Errorf("--insert-at %s should fail", at)
+			}
+		}
+	})
+
+	t.Run("dry run", func(t *testing.T) {
+		env := newTestEnv(t)
+		env.runStdin("line 1\nline 2\n", "write", "docs/notes")
+
+		env.runStdin("inserted\n", "edit", "docs/notes", "--insert-at", "1", "--dry-run")
+
+		out := env.run("cat", "docs/notes")
+		env.equals(out, "line 1\nline 2\n")
+	})
+}
+
+func TestEdit_Section(t *testing.T) {
+	t.Run("replaces section", func(t *testing.T) {
+		env := newTestEnv(t)
+		env.runStdin(editDoc, "write", "docs/guide")
+
+		env.runStdin("## Installation\n\nRun go install.\n\n", "edit", "docs/guide", "--section", "## Installation")
+
+		out := env.run("cat", "docs/guide")
+		env.contains(out, "## Installation\n\nRun go install.\n\n## Usage")
+		if strings.Contains(out, "Download the package") {
+			t.Error("old section body should be replaced")
+		}
+		env.contains(out, "## Troubleshooting")
+	})
+
+	t.Run("last section", func(t *testing.T) {
+		env := newTestEnv(t)
+		env.runStdin(editDoc, "write", "docs/guide")
+
+		env.runStdin("## Troubleshooting\n\nAsk for help.\n", "edit", "docs/guide", "--section", "Troubleshooting")
+
+		out := env.run("cat", "docs/guide")
+		env.contains(out, "## Troubleshooting\n\nAsk for help.\n")
+		if strings.Contains(out, "check the logs") {
+			t.Error("old section body should be replaced")
+		}
+	})
+
+	t.Run("dry run", func(t *testing.T) {
+		env := newTestEnv(t)
+		env.runStdin(editDoc, "write", "docs/guide")
+
+		env.runStdin("## Usage\n", "edit", "docs/guide", "--section", "## Usage", "--dry-run")
+
+		out := env.run("cat", "docs/guide")
+		env.equals(out, editDoc)
+	})
+
+	t.Run("not found", func(t *testing.T) {
+		env := newTestEnv(t)
+		env.runStdin(editDoc, "write", "docs/guide")
+
+		out, err := env.runStdinErr("x", "edit", "docs/guide", "--section", "## Missing")
+		if err == nil {
+			t.Fatal("missing heading should fail")
+		}
+		env.contains(out, "section not found")
+	})
+
+	t.Run("ambiguous", func(t *testing.T) {
+		env := newTestEnv(t)
+		env.runStdin("## Notes\na\n## Notes\nb\n", "write", "docs/notes")
+
+		out, err := env.runStdinErr("x", "edit", "docs/notes", "--section", "## Notes")
+		if err == nil {
+			t.Fatal("ambiguous heading should fail")
+		}
+		env.contains(out, "ambiguous section")
+	})
+}
+
 func TestEdit_WithAuthor(t *testing.T) {
 	env := newTestEnv(t)
 	env.runStdin(editDoc, "write", "docs/guide")
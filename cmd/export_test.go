@@ -1,6 +1,13 @@
 package cmd
 
 import (
+	"archive/tar"
+	"archive/zip"
+	"compress/gzip"
+	"crypto/sha256"
+	"encoding/hex"
+	"encoding/json"
+	"io"
 	"os"
 	"path/filepath"
 	"strings"
@@ -66,6 +73,24 @@ func TestExport_Directory(t *testing.T) {
 	assert.NoFileExists(t, filepath.Join(dst, "meeting.md"))
 }
 
+func TestExport_ContentType(t *testing.T) {
+	env := newTestEnv(t)
+	env.runStdin(`{"a": 1}`, "write", "config/ci", "--content-type", "application/json")
+	env.runStdin("# Notes", "write", "config/notes")
+
+	// Files are named for their content type
+	dst := filepath.Join(env.dir, "output")
+	env.run("export", "config/", dst)
+	assert.FileExists(t, filepath.Join(dst, "ci.json"))
+	assert.FileExists(t, filepath.Join(dst, "notes.md"))
+
+	single := filepath.Join(env.dir, "ci")
+	env.run("export", "config/ci", single)
+	data, err := os.ReadFile(single + ".json")
+	require.NoError(t, err)
+	assert.Equal(t, `{"a": 1}`, string(data))
+}
+
 func TestExport_All(t *testing.T) {
 	env := newTestEnv(t)
 	env.runStdin("readme", "write", "docs/readme")
@@ -187,3 +212,181 @@ func TestExport_ContentIntegrity(t *testing.T) {
 			"content mismatch for %s", name)
 	}
 }
+
+func TestExport_Manifest(t *testing.T) {
+	env := newTestEnv(t)
+	env.runStdin("first", "write", "docs/a")
+	env.runStdin("second", "write", "docs/b")
+	env.runStdin("second v2", "write", "docs/b")
+
+	dst := filepath.Join(env.dir, "out")
+	manifest := filepath.Join(env.dir, "manifest.json")
+	env.run("export", "docs/", dst, "--manifest", manifest)
+
+	data, err := os.ReadFile(manifest)
+	require.NoError(t, err, "manifest not written")
+
+	var m struct {
+		ExportedAt string `json:"exported_at"`
+		Documents  []struct {
+			Path    string `json:"path"`
+			File    string `json:"file"`
+			Key     string `json:"key"`
+			Version int    `json:"version"`
+			SHA256  string `json:"sha256"`
+		} `json:"documents"`
+	}
+	require.NoError(t, json.Unmarshal(data, &m))
+	assert.NotEmpty(t, m.ExportedAt)
+	require.Len(t, m.Documents, 2)
+
+	for _, d := range m.Documents {
+		content, err := os.ReadFile(d.File)
+		require.NoError(t, err, "manifest file %q missing", d.File)
+		sum := sha256.Sum256(content)
+		assert.Equal(t, hex.EncodeToString(sum[:]), d.SHA256, d.Path)
+		assert.Len(t, d.Key, 8)
+		if d.Path == "docs/b" {
+			assert.Equal(t, 2, d.Version)
+		}
+	}
+
+	t.Run("existing manifest needs force", func(t *testing.T) {
+		_, err := env.runErr("export", "docs/", dst, "--manifest", manifest)
+		assert.Error(t, err)
+
+		env.run("export", "docs/", dst, "--manifest", manifest, "--force")
+	})
+}
+
+func TestExport_Verify(t *testing.T) {
+	env := newTestEnv(t)
+	env.runStdin("# Verified\n\nÜnïcödé content\n", "write", "docs/readme")
+
+	dst := filepath.Join(env.dir, "out")
+	out := env.run("export", "docs/", dst, "--verify")
+	assert.Contains(t, out, "Verified 1 file(s)")
+}
+
+func TestExport_AsOf(t *testing.T) {
+	env := newTestEnv(t)
+	env.runStdin("first", "write", "docs/a")
+	env.runStdin("first v2", "write", "docs/a")
+	env.runStdin("second", "write", "docs/b")
+
+	t.Run("future time exports latest", func(t *testing.T) {
+		dst := filepath.Join(env.dir, "future")
+		env.run("export", "docs/", dst, "--as-of", "2999-01-01")
+
+		data, err := os.ReadFile(filepath.Join(dst, "a.md"))
+		require.NoError(t, err)
+		assert.Equal(t, "first v2", string(data))
+		_, err = os.Stat(filepath.Join(dst, "b.md"))
+		assert.NoError(t, err)
+	})
+
+	t.Run("before any document fails", func(t *testing.T) {
+		dst := filepath.Join(env.dir, "past")
+		_, err := env.runErr("export", "docs/", dst, "--as-of", "2000-01-01")
+		assert.Error(t, err)
+	})
+
+	t.Run("deleted document is skipped", func(t *testing.T) {
+		env.run("rm", "docs/b")
+		dst := filepath.Join(env.dir, "after-rm")
+		env.run("export", "docs/", dst, "--as-of", "2999-01-01")
+
+		_, err := os.Stat(filepath.Join(dst, "b.md"))
+		assert.True(t, os.IsNotExist(err), "deleted doc should not be exported")
+	})
+
+	t.Run("invalid time", func(t *testing.T) {
+		_, err := env.runErr("export", "docs/", env.dir, "--as-of", "yesterday")
+		assert.Error(t, err)
+	})
+
+	t.Run("rejects version", func(t *testing.T) {
+		_, err := env.runErr("export", "docs/a", filepath.Join(env.dir, "x.md"), "--as-of", "2999-01-01", "-v", "1")
+		assert.Error(t, err)
+	})
+}
+
+func TestExport_Archive(t *testing.T) {
+	env := newTestEnv(t)
+	env.runStdin("# Readme", "write", "docs/readme")
+	env.runStdin("# Auth", "write", "docs/api/auth")
+
+	t.Run("tar.gz", func(t *testing.T) {
+		dst := filepath.Join(env.dir, "backup.tar.gz")
+		out := env.run("export", "--archive", dst)
+		assert.Contains(t, out, "Archive: "+dst)
+
+		files := readTarGz(t, dst)
+		assert.Equal(t, "# Readme", files["docs/readme.md"])
+		assert.Equal(t, "# Auth", files["docs/api/auth.md"])
+
+		var m struct {
+			Documents []struct {
+				Path string `json:"path"`
+				File string `json:"file"`
+			} `json:"documents"`
+		}
+		require.NoError(t, json.Unmarshal([]byte(files["manifest.json"]), &m))
+		assert.Len(t, m.Documents, 2)
+	})
+
+	t.Run("zip with prefix", func(t *testing.T) {
+		dst := filepath.Join(env.dir, "api.zip")
+		env.run("export", "docs/api/", "--archive", dst)
+
+		zr, err := zip.OpenReader(dst)
+		require.NoError(t, err)
+		defer zr.Close()
+		var names []string
+		for _, f := range zr.File {
+			names = append(names, f.Name)
+		}
+		assert.ElementsMatch(t, []string{"auth.md", "manifest.json"}, names)
+	})
+
+	t.Run("existing archive needs force", func(t *testing.T) {
+		dst := filepath.Join(env.dir, "exists.zip")
+		require.NoError(t, os.WriteFile(dst, []byte("keep"), 0644))
+
+		_, err := env.runErr("export", "--archive", dst)
+		assert.Error(t, err)
+		data, _ := os.ReadFile(dst)
+		assert.Equal(t, "keep", string(data))
+
+		env.run("export", "--archive", dst, "--force")
+	})
+
+	t.Run("unsupported extension", func(t *testing.T) {
+		_, err := env.runErr("export", "--archive", filepath.Join(env.dir, "backup.rar"))
+		assert.Error(t, err)
+	})
+}
+
+// readTarGz returns the regular files in a .tar.gz keyed by entry name.
+func readTarGz(t *testing.T, name string) map[string]string {
+	t.Helper()
+	f, err := os.Open(name)
+	require.NoError(t, err)
+	defer f.Close()
+	gz, err := gzip.NewReader(f)
+	require.NoError(t, err)
+	tr := tar.NewReader(gz)
+
+	files := map[string]string{}
+	for {
+		hdr, err := tr.Next()
+		if err == io.EOF {
+			break
+		}
+		require.NoError(t, err)
+		data, err := io.ReadAll(tr)
+		require.NoError(t, err)
+		files[hdr.Name] = string(data)
+	}
+	return files
+}
@@ -119,3 +119,98 @@ func TestFind_Deleted(t *testing.T) {
 		env.contains(out, "docs/other")
 	})
 }
+
+func TestFind_CountOnly(t *testing.T) {
+	env := newTestEnv(t)
+	env.runStdin("apples and pears", "write", "fruit/a")
+	env.runStdin("more apples", "write", "fruit/b")
+	env.runStdin("carrots", "write", "veg/c")
+
+	env.equals(env.run("find", "apples", "--count-only"), "2")
+	env.equals(env.run("find", "apples", "--count-only", "-o", "json"), `{"count":2}`)
+}
+
+func TestFind_Literal(t *testing.T) {
+	env := newTestEnv(t)
+	env.runStdin("Written in C++ with a foo-bar helper", "write", "docs/cpp")
+	env.runStdin(`Set key:value and say "hello"`, "write", "docs/kv")
+	env.runStdin("Plain prose only", "write", "docs/plain")
+
+	for _, q := range []string{"C++", "foo-bar", "key:value"} {
+		out, err := env.runErr("find", q)
+		if err == nil {
+			t.Errorf("find %q without --literal succeeded, want FTS5 error", q)
+		}
+		env.contains(out, "--literal")
+	}
+
+	out := env.run("find", "C++", "--literal", "-l")
+	env.equals(out, "docs/cpp")
+
+	out = env.run("find", "foo-bar", "--literal", "-l")
+	env.equals(out, "docs/cpp")
+
+	out = env.run("find", "key:value", "--literal", "-l")
+	env.equals(out, "docs/kv")
+
+	out = env.run("find", `"hello"`, "--literal", "-l")
+	env.equals(out, "docs/kv")
+}
+
+func TestFind_Accents(t *testing.T) {
+	env := newTestEnv(t)
+	env.runStdin("Meet at the Café\nthen walk on", "write", "docs/fr")
+	env.runStdin("Tiếng Việt", "write", "docs/vi")
+
+	out := env.run("find", "cafe")
+	env.contains(out, "docs/fr:1: Meet at the Café")
+
+	out = env.run("find", "viet")
+	env.contains(out, "docs/vi:1: Tiếng Việt")
+
+	out = env.run("find", "VIỆT", "-l")
+	env.equals(out, "docs/vi")
+}
+
+func TestFind_Exclude(t *testing.T) {
+	env := newTestEnv(t)
+	env.runStdin("Authentication with JWT", "write", "docs/auth")
+	env.runStdin("Authentication with sessions", "write", "docs/sessions")
+	env.runStdin("Old authentication notes", "write", "docs/archive/auth")
+	env.runStdin("Archived authentication plan", "write", "docs/archived")
+
+	out := env.run("find", "authentication", "--exclude", "docs/archive/", "-l")
+	env.equals(out, "docs/auth\ndocs/sessions\ndocs/archived")
+
+	out = env.run("find", "authentication", "--exclude", "docs/archive", "--exclude", "docs/archived", "-o", "json")
+	env.contains(out, "docs/auth")
+	if strings.Contains(out, "docs/archive") {
+		t.Errorf("find --exclude returned excluded documents: %s", out)
+	}
+
+	out = env.run("find", "authentication", "--not", "jwt", "-l")
+	env.equals(out, "docs/sessions\ndocs/archive/auth\ndocs/archived")
+
+	// Terms are quoted, so punctuation cannot break the query
+	out = env.run("find", "authentication", "--not", "old-notes:x", "--not", "sessions", "-l", "--exclude", "docs/archive")
+	env.equals(out, "docs/auth\ndocs/archived")
+}
+
+func TestFind_Tokenizer(t *testing.T) {
+	env := newTestEnv(t)
+	env.runStdin("Meet at the Café", "write", "docs/fr")
+
+	// Without accent folding the index keeps "café" whole
+	env.run("config", "search.tokenizer", "unicode61 remove_diacritics 0")
+	out, _ := env.runErr("find", "cafe", "-l")
+	if strings.Contains(out, "docs/fr") {
+		t.Errorf("find cafe with remove_diacritics 0 = %q, want no match", out)
+	}
+	out = env.run("find", "café", "-l")
+	env.equals(out, "docs/fr")
+
+	// Back to the default, the index is rebuilt and folds accents again
+	env.run("config", "search.tokenizer", "unicode61 remove_diacritics 2")
+	out = env.run("find", "cafe", "-l")
+	env.equals(out, "docs/fr")
+}
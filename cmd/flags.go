@@ -20,20 +20,28 @@ import (
 	"fmt"
 	"io"
 	"os"
+	"reflect"
 
 	"github.com/jpl-au/llmd/internal/config"
+	"github.com/jpl-au/llmd/internal/document"
 	"github.com/spf13/cobra"
+	"golang.org/x/term"
+	"gopkg.in/yaml.v3"
 )
 
-var validOutputFormats = []string{"json"}
+var validOutputFormats = []string{"json", "jsonl", "yaml", "csv", "tsv"}
 
 var (
-	output  string
-	author  string
-	message string
-	force   bool
-	db      string
-	dir     string
+	output        string
+	author        string
+	message       string
+	force         bool
+	db            string
+	dir           string
+	ephemeral     bool
+	readOnly      bool
+	requireAuthor bool
+	verbose       bool
 )
 
 // out is the output writer for commands. Defaults to os.Stdout.
@@ -76,18 +84,102 @@ func Dir() string {
 	return os.Getenv("LLMD_DIR")
 }
 
+// Passphrase returns the passphrase of an encrypted store: LLMD_PASSPHRASE
+// if set, otherwise read from the terminal without echo. With confirm it is
+// asked for twice, for a new store. Without a terminal to prompt on it is
+// an error.
+func Passphrase(confirm bool) (string, error) {
+	if p := os.Getenv(document.PassphraseEnv); p != "" {
+		return p, nil
+	}
+	fd := int(os.Stdin.Fd())
+	if !term.IsTerminal(fd) {
+		return "", fmt.Errorf("passphrase required: set %s", document.PassphraseEnv)
+	}
+	p, err := readPassphrase(fd, "Passphrase: ")
+	if err != nil {
+		return "", err
+	}
+	if p == "" {
+		return "", fmt.Errorf("passphrase cannot be empty")
+	}
+	if confirm {
+		again, err := readPassphrase(fd, "Confirm passphrase: ")
+		if err != nil {
+			return "", err
+		}
+		if again != p {
+			return "", fmt.Errorf("passphrases do not match")
+		}
+	}
+	return p, nil
+}
+
+// readPassphrase prompts on stderr, keeping stdout clean for output.
+func readPassphrase(fd int, prompt string) (string, error) {
+	fmt.Fprint(os.Stderr, prompt)
+	b, err := term.ReadPassword(fd)
+	fmt.Fprintln(os.Stderr)
+	if err != nil {
+		return "", fmt.Errorf("read passphrase: %w", err)
+	}
+	return string(b), nil
+}
+
 // SetOut sets the output writer (for testing).
 func SetOut(w io.Writer) { out = w }
 
-// JSON returns true if JSON output is requested.
-func JSON() bool { return output == "json" }
+// Ephemeral reports whether commands should run against a throwaway
+// in-memory store instead of the discovered database.
+func Ephemeral() bool { return ephemeral }
+
+// ReadOnly reports whether the store should be opened read-only, rejecting
+// every command that would change it.
+func ReadOnly() bool { return readOnly }
+
+// RequireAuthor reports whether writes without an author should be rejected
+// rather than attributed to the default author.
+func RequireAuthor() bool { return requireAuthor }
+
+// Verbose reports whether operation timings should be printed to stderr.
+func Verbose() bool { return verbose }
+
+// JSON returns true if structured output is requested: json, jsonl or yaml.
+// Commands use this to suppress human-readable output; PrintJSON picks the
+// encoding.
+func JSON() bool { return output == "json" || output == "jsonl" || output == "yaml" }
+
+// Delimiter returns the field separator for tabular output: ',' for csv,
+// '\t' for tsv, or 0 when neither is requested. Only commands with a
+// natural table shape (ls, history, audit) honour it; the rest reject csv
+// and tsv before running (see delimitedCommands).
+func Delimiter() rune {
+	switch output {
+	case "csv":
+		return ','
+	case "tsv":
+		return '\t'
+	}
+	return 0
+}
 
 // PrintJSON marshals v to JSON and writes it to the output writer.
-// Returns nil if output format is not JSON.
+// In jsonl mode a slice is written one compact object per line so consumers
+// can stream results; anything else is written as a single line.
+// In yaml mode the same structure is written as YAML.
+// Returns nil if output format is not structured.
 func PrintJSON(v any) error {
-	if output != "json" {
+	if !JSON() {
 		return nil
 	}
+	if output == "yaml" {
+		return printYAML(v)
+	}
+	if output == "jsonl" {
+		if rv := reflect.ValueOf(v); rv.Kind() == reflect.Slice || rv.Kind() == reflect.Array {
+			return printJSONLines(rv)
+		}
+	}
 	b, err := json.Marshal(v)
 	if err != nil {
 		return fmt.Errorf("marshal json: %w", err)
@@ -96,10 +188,64 @@ func PrintJSON(v any) error {
 	return nil
 }
 
+// PrintCount writes n as a bare integer, or as {"count": n} when the output
+// is structured. Used by the --count-only flag of the listing commands.
+func PrintCount(n int) error {
+	if JSON() {
+		return PrintJSON(map[string]int{"count": n})
+	}
+	fmt.Fprintln(out, n)
+	return nil
+}
+
+// printJSONLines writes each element of rv as its own line of JSON.
+func printJSONLines(rv reflect.Value) error {
+	enc := json.NewEncoder(out)
+	for i := range rv.Len() {
+		if err := enc.Encode(rv.Index(i).Interface()); err != nil {
+			return fmt.Errorf("marshal json: %w", err)
+		}
+	}
+	return nil
+}
+
+// printYAML writes v as YAML. It goes via JSON so the json struct tags that
+// define llmd's output shape (field names, omitempty) apply unchanged, and
+// decodes into a yaml.Node rather than a map so field order is preserved.
+func printYAML(v any) error {
+	b, err := json.Marshal(v)
+	if err != nil {
+		return fmt.Errorf("marshal json: %w", err)
+	}
+	var doc yaml.Node
+	if err := yaml.Unmarshal(b, &doc); err != nil {
+		return fmt.Errorf("convert yaml: %w", err)
+	}
+	blockStyle(&doc)
+
+	enc := yaml.NewEncoder(out)
+	enc.SetIndent(2)
+	if err := enc.Encode(&doc); err != nil {
+		return fmt.Errorf("marshal yaml: %w", err)
+	}
+	return enc.Close()
+}
+
+// blockStyle clears the flow and quoting styles inherited from the JSON
+// source so the encoder picks idiomatic YAML (block collections, literal
+// blocks for multi-line strings). Tags are kept, so a string such as "true"
+// is still quoted and reads back as a string.
+func blockStyle(n *yaml.Node) {
+	n.Style = 0
+	for _, c := range n.Content {
+		blockStyle(c)
+	}
+}
+
 // PrintJSONError prints an error in JSON format if output is JSON.
 // Returns nil if error was printed (suppressing Cobra error), or the original error if not.
 func PrintJSONError(err error) error {
-	if output != "json" || err == nil {
+	if !JSON() || err == nil {
 		return err
 	}
 	// We ignore the error from PrintJSON here because if we can't print the error,
@@ -118,12 +264,16 @@ func detectAuthor() string {
 }
 
 func init() {
-	rootCmd.PersistentFlags().StringVarP(&output, "output", "o", "", "Output format: json")
+	rootCmd.PersistentFlags().StringVarP(&output, "output", "o", "", "Output format: json, jsonl, yaml, csv, tsv")
 	rootCmd.PersistentFlags().StringVarP(&author, "author", "a", "", "Version attribution")
 	rootCmd.PersistentFlags().StringVarP(&message, "message", "m", "", "Version message")
 	rootCmd.PersistentFlags().BoolVar(&force, "force", false, "Skip confirmations")
 	rootCmd.PersistentFlags().StringVar(&db, "db", "", "Database name (e.g., docs for llmd-docs.db)")
 	rootCmd.PersistentFlags().StringVar(&dir, "dir", "", "Database directory (skip discovery, use explicit path)")
+	rootCmd.PersistentFlags().BoolVar(&ephemeral, "ephemeral", false, "Use an in-memory store that is discarded on exit")
+	rootCmd.PersistentFlags().BoolVar(&readOnly, "read-only", false, "Open the store read-only and reject changes")
+	rootCmd.PersistentFlags().BoolVar(&requireAuthor, "require-author", false, "Reject writes that do not name an author")
+	rootCmd.PersistentFlags().BoolVarP(&verbose, "verbose", "V", false, "Print operation timings to stderr")
 
 	_ = rootCmd.RegisterFlagCompletionFunc("output", func(_ *cobra.Command, _ []string, _ string) ([]string, cobra.ShellCompDirective) {
 		return validOutputFormats, cobra.ShellCompDirectiveNoFileComp
@@ -1,8 +1,13 @@
 package cmd
 
 import (
+	"encoding/json"
 	"strings"
 	"testing"
+
+	"github.com/jpl-au/llmd/internal/grep"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
 )
 
 const apiDoc = `# API Reference
@@ -84,6 +89,24 @@ func TestGrep_Recursive(t *testing.T) {
 		}
 	})
 
+	t.Run("prefix matches whole directory names only", func(t *testing.T) {
+		env := newTestEnv(t)
+		env.runStdin(apiDoc, "write", "docs/api")
+		env.runStdin(apiDoc, "write", "docs2/api")
+
+		for _, args := range [][]string{
+			{"grep", "authentication", "docs/"},
+			{"grep", "-r", "authentication", "docs/"},
+			{"grep", "-r", "authentication", "docs"},
+		} {
+			out := env.run(args...)
+			env.contains(out, "docs/api")
+			if strings.Contains(out, "docs2/api") {
+				t.Errorf("%v matched docs2/api:\n%s", args, out)
+			}
+		}
+	})
+
 	t.Run("with -r and path prefix searches all under prefix", func(t *testing.T) {
 		env := newTestEnv(t)
 		env.runStdin(apiDoc, "write", "docs/api")
@@ -143,6 +166,23 @@ func TestGrep(t *testing.T) {
 		env.contains(out, `"path"`)
 		env.contains(out, "docs/api")
 	})
+
+	t.Run("JSON lines output", func(t *testing.T) {
+		env := newTestEnv(t)
+		env.runStdin(apiDoc, "write", "docs/api")
+		env.runStdin(apiDoc, "write", "docs/api2")
+
+		out := env.run("grep", "-r", "authentication", "-o", "jsonl")
+		lines := strings.Split(strings.TrimSpace(out), "\n")
+		if len(lines) != 2 {
+			t.Fatalf("grep -o jsonl = %d lines, want 2:\n%s", len(lines), out)
+		}
+		for _, line := range lines {
+			if !strings.HasPrefix(line, `{"key"`) && !strings.HasPrefix(line, `{"path"`) {
+				t.Errorf("grep -o jsonl line %q is not a JSON object", line)
+			}
+		}
+	})
 }
 
 func TestGrep_Scope(t *testing.T) {
@@ -168,6 +208,38 @@ func TestGrep_Scope(t *testing.T) {
 		env.contains(out, "docs/api")
 		env.contains(out, "notes/meeting")
 	})
+
+	t.Run("glob path matches document paths", func(t *testing.T) {
+		env := newTestEnv(t)
+		env.runStdin("TODO v1", "write", "docs/v1/api/auth")
+		env.runStdin("TODO v2", "write", "docs/v2/api/users")
+		env.runStdin("TODO guide", "write", "docs/v1/guide/intro")
+		env.runStdin("TODO other", "write", "notes/api/todo")
+
+		out := env.run("grep", "-l", "TODO", "docs/**/api/*")
+		env.contains(out, "docs/v1/api/auth")
+		env.contains(out, "docs/v2/api/users")
+		for _, p := range []string{"docs/v1/guide/intro", "notes/api/todo"} {
+			if strings.Contains(out, p) {
+				t.Errorf("Grep(docs/**/api/*) contains %s, want excluded", p)
+			}
+		}
+
+		// ? and * match within a single path segment
+		out = env.run("grep", "-l", "TODO", "docs/v?/api/*")
+		env.contains(out, "docs/v1/api/auth")
+		env.contains(out, "docs/v2/api/users")
+	})
+
+	t.Run("invalid glob path", func(t *testing.T) {
+		env := newTestEnv(t)
+		env.runStdin("TODO", "write", "docs/a")
+
+		_, err := env.runErr("grep", "TODO", "docs/[")
+		if err == nil {
+			t.Error("Grep(docs/[) = nil, want error")
+		}
+	})
 }
 
 func TestGrep_PathsOnly(t *testing.T) {
@@ -270,6 +342,17 @@ func TestGrep_Count(t *testing.T) {
 		env.contains(out, "notes/meeting:")
 		env.contains(out, "docs/api:")
 	})
+
+	t.Run("count-only counts matching documents", func(t *testing.T) {
+		env := newTestEnv(t)
+		env.runStdin(notesDoc, "write", "notes/meeting")
+		env.runStdin("TODO: one\nTODO: two", "write", "notes/todo")
+		env.runStdin("nothing to do", "write", "notes/done")
+
+		env.equals(env.run("grep", "-r", "--count-only", "TODO"), "2")
+		env.equals(env.run("grep", "-r", "--count-only", "TODO", "-o", "json"), `{"count":2}`)
+		env.equals(env.run("grep", "-r", "--count-only", "nomatch"), "0")
+	})
 }
 
 func TestGrep_Context(t *testing.T) {
@@ -364,3 +447,87 @@ func TestGrep_Regex(t *testing.T) {
 		env.contains(out, "invalid regex")
 	})
 }
+
+func TestGrep_AsOf(t *testing.T) {
+	env := newTestEnv(t)
+	env.runStdin("TODO: write docs", "write", "docs/a")
+
+	out := env.run("grep", "TODO", "docs/", "--as-of", "2999-01-01")
+	env.contains(out, "docs/a:1:TODO")
+
+	out = env.run("grep", "TODO", "docs/", "--as-of", "2000-01-01", "--count-only")
+	env.equals(strings.TrimSpace(out), "0")
+}
+
+func TestGrep_ByteOffset(t *testing.T) {
+	env := newTestEnv(t)
+	// Offsets count raw bytes, including the CR of a CRLF line ending
+	env.runStdin("release v1 and v22\r\nnothing here\nthen v3", "write", "docs/a")
+
+	out := env.run("grep", "-b", `v[0-9]+`, "docs/")
+	env.equals(strings.TrimSpace(out), "docs/a:1:0:release v1 and v22\ndocs/a:3:33:then v3")
+
+	out = env.run("grep", "--only-matching", `v[0-9]+`, "docs/")
+	env.equals(strings.TrimSpace(out), "docs/a:1:v1\ndocs/a:1:v22\ndocs/a:3:v3")
+
+	out = env.run("grep", "-b", "--only-matching", `v[0-9]+`, "docs/")
+	env.equals(strings.TrimSpace(out), "docs/a:1:8:v1\ndocs/a:1:15:v22\ndocs/a:3:38:v3")
+
+	out = env.run("grep", "-b", `v[0-9]+`, "docs/", "-o", "json")
+	var spans []grep.Span
+	require.NoError(t, json.Unmarshal([]byte(out), &spans))
+	assert.Equal(t, []grep.Span{
+		{Path: "docs/a", Line: 1, EndLine: 1, Offset: 8, Match: "v1"},
+		{Path: "docs/a", Line: 1, EndLine: 1, Offset: 15, Match: "v22"},
+		{Path: "docs/a", Line: 3, EndLine: 3, Offset: 38, Match: "v3"},
+	}, spans)
+
+	out = env.run("grep", "-b", "-C", "1", "nothing", "docs/")
+	env.contains(out, "docs/a-1-0-release")
+	env.contains(out, "docs/a:2:20:nothing here")
+	env.contains(out, "docs/a-3-33-then v3")
+
+	out, err := env.runErr("grep", "--only-matching", "-v", "v1", "docs/")
+	require.Error(t, err)
+	env.contains(out, "--only-matching cannot be combined with -v")
+}
+
+func TestGrep_Multiline(t *testing.T) {
+	env := newTestEnv(t)
+	doc := "# Setup\n\n```go\nfunc main() {}\n```\n\nText\n\n```sh\nmake\n```\n"
+	env.runStdin(doc, "write", "docs/a")
+
+	// Without --multiline a pattern cannot cross a newline
+	out, _ := env.runErr("grep", "main.*make", "docs/")
+	env.equals(strings.TrimSpace(out), "")
+
+	fence := "(?m)^```[a-z]+$.*?^```$"
+	out = env.run("grep", "--multiline", fence, "docs/")
+	env.equals(strings.TrimSpace(out), strings.Join([]string{
+		"docs/a:3:```go",
+		"docs/a:4:func main() {}",
+		"docs/a:5:```",
+		"--",
+		"docs/a:9:```sh",
+		"docs/a:10:make",
+		"docs/a:11:```",
+	}, "\n"))
+
+	out = env.run("grep", "--multiline", "-c", fence, "docs/")
+	env.equals(strings.TrimSpace(out), "docs/a:6")
+
+	out = env.run("grep", "--multiline", "-C", "1", "main.*?```", "docs/")
+	env.equals(strings.TrimSpace(out), "docs/a-3-```go\ndocs/a:4:func main() {}\ndocs/a:5:```\ndocs/a-6-")
+
+	out = env.run("grep", "--multiline", fence, "docs/", "-o", "json")
+	var spans []grep.Span
+	require.NoError(t, json.Unmarshal([]byte(out), &spans))
+	require.Len(t, spans, 2)
+	assert.Equal(t, grep.Span{Path: "docs/a", Line: 3, EndLine: 5, Offset: 9, Match: "```go\nfunc main() {}\n```"}, spans[0])
+	assert.Equal(t, 9, spans[1].Line)
+	assert.Equal(t, 11, spans[1].EndLine)
+
+	out, err := env.runErr("grep", "--multiline", "-v", "x", "docs/")
+	require.Error(t, err)
+	env.contains(out, "--multiline cannot be combined with -v")
+}
@@ -1,6 +1,9 @@
 package cmd
 
-import "testing"
+import (
+	"strings"
+	"testing"
+)
 
 func TestGuide(t *testing.T) {
 	t.Run("main guide", func(t *testing.T) {
@@ -50,3 +53,42 @@ func TestGuide_NotFound(t *testing.T) {
 		t.Error("Guide(nonexistent) = nil, want error")
 	}
 }
+
+func TestGuide_List(t *testing.T) {
+	env := newTestEnv(t)
+
+	out := env.run("guide", "--list")
+	env.contains(out, "write")
+	env.contains(out, "Write content to a document.")
+	if strings.Contains(out, "\nguide ") {
+		t.Error("guide --list should not include the main guide page")
+	}
+
+	out = env.run("guide", "--list", "-o", "json")
+	env.contains(out, `"name":"sync"`)
+	env.contains(out, `"summary":`)
+}
+
+func TestGuide_Search(t *testing.T) {
+	env := newTestEnv(t)
+
+	out := env.run("guide", "--search", "DEBOUNCE")
+	env.contains(out, "sync:")
+	if strings.Contains(out, "write:") {
+		t.Errorf("unexpected topic in search results: %s", out)
+	}
+
+	out = env.run("guide", "-s", "debounce", "-o", "json")
+	env.contains(out, `"topic":"sync"`)
+	env.contains(out, `"line":`)
+
+	_, err := env.runErr("guide", "--search", "no-such-term-anywhere")
+	if err == nil {
+		t.Error("guide --search with no matches = nil, want error")
+	}
+
+	_, err = env.runErr("guide", "write", "--list")
+	if err == nil {
+		t.Error("guide write --list = nil, want error")
+	}
+}
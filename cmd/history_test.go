@@ -1,6 +1,9 @@
 package cmd
 
-import "testing"
+import (
+	"strings"
+	"testing"
+)
 
 func TestHistory(t *testing.T) {
 	t.Run("basic history", func(t *testing.T) {
@@ -44,6 +47,21 @@ func TestHistory(t *testing.T) {
 		env.contains(out, `"author"`)
 		env.contains(out, "alice")
 	})
+
+	t.Run("CSV output", func(t *testing.T) {
+		env := newTestEnv(t)
+		env.runStdin("one", "write", "docs/readme", "-a", "alice")
+		env.runStdin("two!", "write", "docs/readme", "-a", "Smith, Bob")
+
+		out := env.run("history", "docs/readme", "-o", "csv")
+		lines := strings.Split(strings.TrimSpace(out), "\n")
+		if len(lines) != 3 {
+			t.Fatalf("history -o csv = %d lines, want 3:\n%s", len(lines), out)
+		}
+		env.equals(lines[0], "key,path,version,author,size,created_at")
+		env.contains(lines[1], `,docs/readme,2,"Smith, Bob",4,`)
+		env.contains(lines[2], ",docs/readme,1,alice,3,")
+	})
 }
 
 func TestHistory_Limit(t *testing.T) {
@@ -58,6 +76,39 @@ func TestHistory_Limit(t *testing.T) {
 	env.contains(out, "v8")
 }
 
+func TestHistory_Reverse(t *testing.T) {
+	env := newTestEnv(t)
+	for range 5 {
+		env.runStdin("version", "write", "docs/readme")
+	}
+
+	t.Run("oldest first", func(t *testing.T) {
+		out := env.run("history", "docs/readme", "-r")
+		first, last := strings.Index(out, "v1"), strings.Index(out, "v5")
+		if first < 0 || last < 0 || first > last {
+			t.Errorf("history -r listed v5 before v1:\n%s", out)
+		}
+	})
+
+	t.Run("applies after limit", func(t *testing.T) {
+		out := env.run("history", "docs/readme", "-r", "-n", "2")
+		if strings.Contains(out, "v3") {
+			t.Errorf("history -r -n 2 included v3:\n%s", out)
+		}
+		if strings.Index(out, "v4") > strings.Index(out, "v5") {
+			t.Errorf("history -r -n 2 listed v5 before v4:\n%s", out)
+		}
+	})
+
+	t.Run("JSON order", func(t *testing.T) {
+		out := env.run("history", "docs/readme", "-r", "-o", "json")
+		first, last := strings.Index(out, `"version":1`), strings.Index(out, `"version":5`)
+		if first < 0 || last < 0 || first > last {
+			t.Errorf("history -r -o json listed v5 before v1:\n%s", out)
+		}
+	})
+}
+
 func TestHistory_WithDiff(t *testing.T) {
 	env := newTestEnv(t)
 	env.runStdin("line one", "write", "docs/readme")
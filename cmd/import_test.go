@@ -1,6 +1,8 @@
 package cmd
 
 import (
+	"archive/tar"
+	"compress/gzip"
 	"os"
 	"path/filepath"
 	"strings"
@@ -164,9 +166,262 @@ func TestImport_Flat(t *testing.T) {
 	assert.NotContains(t, out, "docs/api/v2")
 }
 
+func TestImport_Extensions(t *testing.T) {
+	setup := func(t *testing.T) (*testEnv, string) {
+		env := newTestEnv(t)
+		src := filepath.Join(env.dir, "source")
+		require.NoError(t, os.MkdirAll(src, 0755))
+		for _, name := range []string{"a.md", "b.markdown", "c.MDX", "d.txt"} {
+			require.NoError(t, os.WriteFile(filepath.Join(src, name), []byte(name), 0644))
+		}
+		return env, src
+	}
+
+	t.Run("default is md only", func(t *testing.T) {
+		env, src := setup(t)
+
+		env.run("import", src)
+
+		env.equals(env.run("cat", "a"), "a.md")
+		for _, path := range []string{"b", "c", "d"} {
+			_, err := env.runErr("cat", path)
+			assert.Error(t, err, "%s should not be imported", path)
+		}
+	})
+
+	t.Run("repeatable and strips matched extension", func(t *testing.T) {
+		env, src := setup(t)
+
+		env.run("import", src, "--ext", ".md", "--ext", ".markdown", "--ext", ".mdx")
+
+		env.equals(env.run("cat", "b"), "b.markdown")
+		env.equals(env.run("cat", "c"), "c.MDX")
+		_, err := env.runErr("cat", "d")
+		assert.Error(t, err, "d.txt should not be imported")
+	})
+
+	t.Run("records content type from extension", func(t *testing.T) {
+		env, src := setup(t)
+
+		env.run("import", src, "--ext", ".md", "--ext", ".txt")
+
+		env.contains(env.run("cat", "a", "-o", "json"), `"content_type":"text/markdown"`)
+		env.contains(env.run("cat", "d", "-o", "json"), `"content_type":"text/plain"`)
+	})
+
+	t.Run("rejects invalid extensions", func(t *testing.T) {
+		env, src := setup(t)
+
+		for _, ext := range []string{"", "mdx", "."} {
+			out, err := env.runErr("import", src, "--ext", ext)
+			if err == nil {
+				t.Errorf("import --ext %q should fail", ext)
+			}
+			env.contains(out, "invalid extension")
+		}
+	})
+}
+
+func TestImport_Update(t *testing.T) {
+	setup := func(t *testing.T) (*testEnv, string) {
+		env := newTestEnv(t)
+		src := filepath.Join(env.dir, "source")
+		require.NoError(t, os.MkdirAll(src, 0755))
+		require.NoError(t, os.WriteFile(filepath.Join(src, "same.md"), []byte("same"), 0644))
+		require.NoError(t, os.WriteFile(filepath.Join(src, "changed.md"), []byte("before"), 0644))
+		env.run("import", src)
+		return env, src
+	}
+
+	t.Run("versions only changed files", func(t *testing.T) {
+		env, src := setup(t)
+		require.NoError(t, os.WriteFile(filepath.Join(src, "changed.md"), []byte("after"), 0644))
+		require.NoError(t, os.WriteFile(filepath.Join(src, "new.md"), []byte("new"), 0644))
+
+		out := env.run("import", src, "-u")
+		env.contains(out, "1 created, 1 updated, 1 unchanged")
+
+		out = env.run("history", "same")
+		assert.NotContains(t, out, " v2 ")
+		out = env.run("cat", "changed")
+		env.equals(out, "after")
+		out = env.run("history", "changed")
+		env.contains(out, " v2 ")
+	})
+
+	t.Run("repeat import is idempotent", func(t *testing.T) {
+		env, src := setup(t)
+
+		out := env.run("import", src, "--update")
+		env.contains(out, "0 created, 0 updated, 2 unchanged")
+	})
+
+	t.Run("dry run previews", func(t *testing.T) {
+		env, src := setup(t)
+		require.NoError(t, os.WriteFile(filepath.Join(src, "changed.md"), []byte("after"), 0644))
+
+		out := env.run("import", src, "-u", "-n")
+		env.contains(out, "Would update:")
+		env.contains(out, "Unchanged:")
+
+		out = env.run("cat", "changed")
+		env.equals(out, "before")
+	})
+}
+
+func TestImport_Frontmatter(t *testing.T) {
+	t.Run("path and tags", func(t *testing.T) {
+		env := newTestEnv(t)
+
+		src := filepath.Join(env.dir, "source")
+		require.NoError(t, os.MkdirAll(src, 0755))
+
+		content := "---\npath: guides/setup\ntags: [onboarding, draft]\n---\n# Setup\n"
+		require.NoError(t, os.WriteFile(filepath.Join(src, "notes.md"), []byte(content), 0644))
+
+		env.run("import", src, "--frontmatter", "-t", "team")
+
+		out := env.run("cat", "team/guides/setup")
+		env.equals(out, content)
+
+		out = env.run("tag", "ls", "team/guides/setup")
+		env.contains(out, "onboarding")
+		env.contains(out, "draft")
+	})
+
+	t.Run("comma separated tags", func(t *testing.T) {
+		env := newTestEnv(t)
+
+		src := filepath.Join(env.dir, "source")
+		require.NoError(t, os.MkdirAll(src, 0755))
+
+		content := "---\ntags: alpha, beta\n---\nbody\n"
+		require.NoError(t, os.WriteFile(filepath.Join(src, "notes.md"), []byte(content), 0644))
+
+		env.run("import", src, "--frontmatter")
+
+		out := env.run("tag", "ls", "notes")
+		env.contains(out, "alpha")
+		env.contains(out, "beta")
+	})
+
+	t.Run("no frontmatter uses filename", func(t *testing.T) {
+		env := newTestEnv(t)
+
+		src := filepath.Join(env.dir, "source")
+		require.NoError(t, os.MkdirAll(src, 0755))
+		require.NoError(t, os.WriteFile(filepath.Join(src, "plain.md"), []byte("# Plain"), 0644))
+
+		env.run("import", src, "--frontmatter")
+
+		out := env.run("cat", "plain")
+		env.equals(out, "# Plain")
+	})
+
+	t.Run("ignored without flag", func(t *testing.T) {
+		env := newTestEnv(t)
+
+		src := filepath.Join(env.dir, "source")
+		require.NoError(t, os.MkdirAll(src, 0755))
+
+		content := "---\npath: guides/setup\n---\n# Setup\n"
+		require.NoError(t, os.WriteFile(filepath.Join(src, "notes.md"), []byte(content), 0644))
+
+		env.run("import", src)
+
+		out := env.run("ls", "-R")
+		env.contains(out, "notes")
+		assert.NotContains(t, out, "guides/setup")
+	})
+
+	t.Run("dry run shows frontmatter path", func(t *testing.T) {
+		env := newTestEnv(t)
+
+		src := filepath.Join(env.dir, "source")
+		require.NoError(t, os.MkdirAll(src, 0755))
+
+		content := "---\npath: guides/setup\n---\n# Setup\n"
+		require.NoError(t, os.WriteFile(filepath.Join(src, "notes.md"), []byte(content), 0644))
+
+		out := env.run("import", src, "--frontmatter", "-n")
+		env.contains(out, "-> guides/setup")
+	})
+
+	t.Run("malformed frontmatter", func(t *testing.T) {
+		env := newTestEnv(t)
+
+		src := filepath.Join(env.dir, "source")
+		require.NoError(t, os.MkdirAll(src, 0755))
+
+		content := "---\npath: [unclosed\n---\nbody\n"
+		require.NoError(t, os.WriteFile(filepath.Join(src, "bad.md"), []byte(content), 0644))
+
+		_, err := env.runErr("import", src, "--frontmatter")
+		assert.Error(t, err)
+	})
+}
+
 func TestImport_NotFound(t *testing.T) {
 	env := newTestEnv(t)
 
 	_, err := env.runErr("import", "/nonexistent/path")
 	assert.Error(t, err)
 }
+
+func TestImport_Archive(t *testing.T) {
+	t.Run("round trip with export", func(t *testing.T) {
+		env := newTestEnv(t)
+		env.runStdin("# Readme", "write", "docs/readme")
+		env.runStdin("# Auth", "write", "docs/api/auth")
+
+		dst := filepath.Join(env.dir, "backup.zip")
+		env.run("export", "--archive", dst)
+
+		out := env.run("import", dst, "-t", "restored")
+		assert.Contains(t, out, "Imported 2 file(s)")
+
+		out = env.run("cat", "restored/docs/api/auth")
+		assert.Equal(t, "# Auth", out)
+		_, err := env.runErr("cat", "restored/manifest")
+		assert.Error(t, err, "manifest.json should not be imported")
+	})
+
+	t.Run("rejects traversal", func(t *testing.T) {
+		env := newTestEnv(t)
+		dst := filepath.Join(env.dir, "evil.tar.gz")
+		writeTarGz(t, dst, map[string]string{"../../escape.md": "# Evil"})
+
+		_, err := env.runErr("import", dst)
+		assert.Error(t, err)
+	})
+
+	t.Run("skips hidden entries", func(t *testing.T) {
+		env := newTestEnv(t)
+		dst := filepath.Join(env.dir, "hidden.tar.gz")
+		writeTarGz(t, dst, map[string]string{
+			"docs/visible.md":       "# Visible",
+			"docs/.secret/notes.md": "# Hidden",
+		})
+
+		out := env.run("import", dst)
+		assert.Contains(t, out, "docs/visible")
+		assert.NotContains(t, out, "secret")
+	})
+}
+
+// writeTarGz creates a .tar.gz holding files keyed by entry name.
+func writeTarGz(t *testing.T, name string, files map[string]string) {
+	t.Helper()
+	f, err := os.Create(name)
+	require.NoError(t, err)
+	defer f.Close()
+	gz := gzip.NewWriter(f)
+	tw := tar.NewWriter(gz)
+	for entry, content := range files {
+		require.NoError(t, tw.WriteHeader(&tar.Header{Name: entry, Mode: 0644, Size: int64(len(content)), Typeflag: tar.TypeReg}))
+		_, err := tw.Write([]byte(content))
+		require.NoError(t, err)
+	}
+	require.NoError(t, tw.Close())
+	require.NoError(t, gz.Close())
+}
@@ -15,13 +15,17 @@ Copyright © 2026 James Lawson (jpl-au) <hello@caelisco.net>
 package cmd
 
 import (
+	"errors"
 	"fmt"
+	"os"
 	"sync"
 
 	"github.com/jpl-au/llmd/extension"
 	"github.com/jpl-au/llmd/internal/config"
 	"github.com/jpl-au/llmd/internal/document"
 	"github.com/jpl-au/llmd/internal/log"
+	"github.com/jpl-au/llmd/internal/store"
+	"golang.org/x/term"
 )
 
 // noStoreCommands lists commands that bypass automatic store initialisation.
@@ -32,11 +36,16 @@ var noStoreCommands map[string]bool
 // These are commands that write or modify document data.
 var authorRequiredCommands = map[string]bool{
 	"write":   true,
+	"new":     true,
 	"edit":    true,
 	"sed":     true,
 	"rm":      true,
 	"mv":      true,
+	"cp":      true,
+	"split":   true,
+	"join":    true,
 	"revert":  true,
+	"undo":    true,
 	"restore": true,
 	"import":  true,
 	"sync":    true,
@@ -46,6 +55,11 @@ var authorRequiredCommands = map[string]bool{
 	"vacuum":  true,
 }
 
+// delimitedCommands lists the commands that honour -o csv and -o tsv (see
+// Delimiter). Any other command rejects them rather than printing text that
+// a script would then misparse.
+var delimitedCommands = []string{"audit", "history", "ls"}
+
 // buildNoStoreCommands creates the set of commands that skip store initialisation.
 //
 // Why this exists: Most commands need the document store, but some must work
@@ -101,7 +115,7 @@ var (
 // message. Other errors (permissions, corruption) are returned immediately.
 func initExtensions() error {
 	initOnce.Do(func() {
-		svc, err := document.New(DB())
+		svc, err := OpenService()
 		if err != nil {
 			initErr = fmt.Errorf("opening database: %w", err)
 			return
@@ -134,6 +148,46 @@ func initExtensions() error {
 	return initErr
 }
 
+// OpenService opens the store selected by the global flags: an in-memory
+// store with --ephemeral, otherwise the discovered database, read-only with
+// --read-only. --require-author makes it reject writes without an author.
+// An encrypted store is unlocked with LLMD_PASSPHRASE, or a passphrase read
+// from the terminal.
+// Commands that manage their own service lifecycle use this so they honour
+// the same flags as everything else.
+func OpenService() (*document.Service, error) {
+	return OpenServiceWith(document.Options{})
+}
+
+// OpenServiceWith is OpenService with extra options for the discovered
+// database, such as NoMigrate for db migrate. --read-only still applies.
+func OpenServiceWith(opts document.Options) (*document.Service, error) {
+	var svc *document.Service
+	var err error
+	if Ephemeral() {
+		if ReadOnly() {
+			return nil, fmt.Errorf("--ephemeral and --read-only cannot be combined")
+		}
+		svc, err = document.NewMemory()
+	} else {
+		opts.ReadOnly = opts.ReadOnly || ReadOnly()
+		svc, err = document.NewWithOptions(DB(), opts)
+		if errors.Is(err, store.ErrEncrypted) && opts.Passphrase == "" && term.IsTerminal(int(os.Stdin.Fd())) {
+			if opts.Passphrase, err = Passphrase(false); err != nil {
+				return nil, err
+			}
+			svc, err = document.NewWithOptions(DB(), opts)
+		}
+	}
+	if err != nil {
+		return nil, err
+	}
+	if RequireAuthor() {
+		svc.SetRequireAuthor(true)
+	}
+	return svc, nil
+}
+
 var extensionsOnce sync.Once
 
 // registerExtensions adds commands from all registered extensions.
@@ -231,3 +231,35 @@ func TestInit_DB(t *testing.T) {
 		assert.Contains(t, string(gitignore), "llmd-notes.db")
 	})
 }
+
+func TestInit_Encrypt(t *testing.T) {
+	t.Setenv("LLMD_PASSPHRASE", "hunter2")
+	env := newTestEnv(t)
+	env.run("init", "--encrypt", "--force")
+	env.run("config", "sync.files", "true", "--local")
+
+	env.runStdin("The launch codes are 0000.", "write", "secret/plan")
+	env.equals(env.run("cat", "secret/plan"), "The launch codes are 0000.")
+	env.contains(env.run("grep", "-r", "launch"), "secret/plan")
+
+	out, err := env.runErr("find", "launch")
+	assert.Error(t, err)
+	assert.Contains(t, out, "encrypted")
+
+	// Neither the database nor a mirror holds the plaintext
+	env.run("db", "migrate") // checkpoints the WAL on close
+	db, err := os.ReadFile(filepath.Join(env.dir, ".llmd", "llmd.db"))
+	require.NoError(t, err)
+	assert.NotContains(t, string(db), "launch")
+	assert.NoFileExists(t, filepath.Join(env.dir, ".llmd", "secret", "plan.md"))
+
+	t.Setenv("LLMD_PASSPHRASE", "wrong")
+	out, err = env.runErr("cat", "secret/plan")
+	assert.Error(t, err)
+	assert.Contains(t, out, "incorrect passphrase")
+
+	t.Setenv("LLMD_PASSPHRASE", "")
+	out, err = env.runErr("cat", "secret/plan")
+	assert.Error(t, err)
+	assert.Contains(t, out, "passphrase required")
+}
@@ -0,0 +1,92 @@
+package cmd
+
+import (
+	"fmt"
+	"strings"
+	"testing"
+)
+
+func TestJoin(t *testing.T) {
+	setup := func(t *testing.T) *testEnv {
+		env := newTestEnv(t)
+		env.runStdin("alpha", "write", "docs/parts/a")
+		env.runStdin("beta\n", "write", "docs/parts/b")
+		env.runStdin("gamma", "write", "docs/parts/c")
+		return env
+	}
+
+	t.Run("explicit order", func(t *testing.T) {
+		env := setup(t)
+		env.run("join", "docs/all", "docs/parts/c", "docs/parts/a")
+		env.equals(env.run("cat", "docs/all"), "gamma\n\nalpha")
+	})
+
+	t.Run("glob", func(t *testing.T) {
+		env := setup(t)
+		out := env.run("join", "docs/all", "docs/parts/*")
+		env.contains(out, "Joined 3 documents into docs/all")
+		env.equals(env.run("cat", "docs/all"), "alpha\n\nbeta\n\ngamma")
+	})
+
+	t.Run("headers", func(t *testing.T) {
+		env := setup(t)
+		env.run("join", "docs/all", "docs/parts/a", "docs/parts/b", "--headers")
+		env.equals(env.run("cat", "docs/all"), "## a\n\nalpha\n\n## b\n\nbeta")
+	})
+
+	t.Run("delete sources", func(t *testing.T) {
+		env := setup(t)
+		env.run("join", "docs/all", "docs/parts/*", "--delete")
+		out := env.run("ls", "-R")
+		env.contains(out, "docs/all")
+		if _, err := env.runErr("cat", "docs/parts/a"); err == nil {
+			t.Error("cat docs/parts/a after join --delete = nil, want error")
+		}
+	})
+
+	t.Run("existing destination", func(t *testing.T) {
+		env := setup(t)
+		env.runStdin("old", "write", "docs/all")
+
+		out, err := env.runErr("join", "docs/all", "docs/parts/a")
+		if err == nil {
+			t.Fatal("join onto existing document = nil, want error")
+		}
+		env.contains(out, "already exists")
+
+		env.run("join", "docs/all", "docs/parts/a", "--force")
+		env.equals(env.run("cat", "docs/all"), "alpha")
+	})
+
+	t.Run("errors", func(t *testing.T) {
+		env := setup(t)
+		if _, err := env.runErr("join", "docs/all", "docs/missing"); err == nil {
+			t.Error("join with missing source = nil, want error")
+		}
+		if _, err := env.runErr("join", "docs/all", "nothing/*"); err == nil {
+			t.Error("join with unmatched glob = nil, want error")
+		}
+		if _, err := env.runErr("join", "docs/parts/a", "docs/parts/a", "docs/parts/b"); err == nil {
+			t.Error("join with dest as source = nil, want error")
+		}
+	})
+
+	t.Run("split round trip", func(t *testing.T) {
+		env := newTestEnv(t)
+		var doc strings.Builder
+		for i := 1; i <= 12; i++ {
+			fmt.Fprintf(&doc, "## Part %d\ntext %d\n", i, i)
+		}
+		env.runStdin(doc.String(), "write", "docs/big")
+
+		env.run("split", "docs/big")
+		env.run("join", "docs/whole", "docs/big/section-*")
+		env.equals(env.run("cat", "docs/whole"), strings.ReplaceAll(strings.TrimSuffix(doc.String(), "\n"), "\n## ", "\n\n## "))
+	})
+
+	t.Run("JSON output", func(t *testing.T) {
+		env := setup(t)
+		out := env.run("join", "docs/all", "docs/parts/a", "docs/parts/b", "-o", "json")
+		env.contains(out, `"sources":["docs/parts/a","docs/parts/b"]`)
+	})
+}
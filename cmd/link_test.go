@@ -201,3 +201,217 @@ func TestLink(t *testing.T) {
 		env.contains(out, "docs/two")
 	})
 }
+
+func TestLink_Graph(t *testing.T) {
+	setup := func(t *testing.T) *testEnv {
+		env := newTestEnv(t)
+		env.runStdin("a", "write", "docs/a")
+		env.runStdin("b", "write", "docs/b")
+		env.runStdin("c", "write", "docs/c")
+		env.runStdin("lonely", "write", "docs/lonely")
+		env.run("link", "--tag", "depends-on", "docs/a", "docs/b")
+		env.run("link", "docs/b", "docs/c")
+		return env
+	}
+
+	t.Run("dot", func(t *testing.T) {
+		env := setup(t)
+		out := env.run("link", "--graph")
+		env.contains(out, "digraph links {")
+		env.contains(out, `"docs/a" -> "docs/b" [label="depends-on"];`)
+		env.contains(out, `"docs/b" -> "docs/c";`)
+		if strings.Contains(out, "docs/lonely") {
+			t.Errorf("graph without --orphan includes unlinked document:\n%s", out)
+		}
+	})
+
+	t.Run("orphans", func(t *testing.T) {
+		env := setup(t)
+		env.contains(env.run("link", "--graph", "--orphan"), `"docs/lonely";`)
+	})
+
+	t.Run("mermaid", func(t *testing.T) {
+		env := setup(t)
+		out := env.run("link", "--graph", "--format", "mermaid")
+		env.contains(out, "graph LR")
+		env.contains(out, `n0["docs/a"]`)
+		env.contains(out, `n0 -->|"depends-on"| n1`)
+		env.contains(out, "n1 --> n2")
+	})
+
+	t.Run("tag filter", func(t *testing.T) {
+		env := setup(t)
+		out := env.run("link", "--graph", "--tag", "depends-on")
+		env.contains(out, `"docs/a" -> "docs/b"`)
+		if strings.Contains(out, "docs/c") {
+			t.Errorf("graph --tag depends-on includes untagged link:\n%s", out)
+		}
+	})
+
+	t.Run("json", func(t *testing.T) {
+		env := setup(t)
+		out := env.run("link", "--graph", "-o", "json")
+		env.contains(out, `"nodes":["docs/a","docs/b","docs/c"]`)
+		env.contains(out, `"from":"docs/a","to":"docs/b","tag":"depends-on"`)
+	})
+
+	t.Run("unknown format", func(t *testing.T) {
+		env := setup(t)
+		out, err := env.runErr("link", "--graph", "--format", "svg")
+		if err == nil {
+			t.Fatal("link --graph --format svg = nil, want error")
+		}
+		env.contains(out, `unknown graph format "svg"`)
+	})
+}
+
+func TestLink_Reachable(t *testing.T) {
+	setup := func(t *testing.T) *testEnv {
+		env := newTestEnv(t)
+		for _, p := range []string{"a", "b", "c", "d", "e", "island"} {
+			env.runStdin(p, "write", "docs/"+p)
+		}
+		// a - b - c - d, with a cycle back from d to b and a tagged spur to e
+		env.run("link", "docs/a", "docs/b")
+		env.run("link", "docs/b", "docs/c")
+		env.run("link", "docs/c", "docs/d")
+		env.run("link", "docs/d", "docs/b")
+		env.run("link", "--tag", "depends-on", "docs/a", "docs/e")
+		return env
+	}
+
+	t.Run("all hops", func(t *testing.T) {
+		env := setup(t)
+		env.equals(env.run("link", "--reachable", "docs/a"), "1  docs/b\n1  docs/e\n2  docs/c\n2  docs/d")
+	})
+
+	t.Run("depth", func(t *testing.T) {
+		env := setup(t)
+		env.equals(env.run("link", "--reachable", "docs/a", "--depth", "1"), "1  docs/b\n1  docs/e")
+	})
+
+	t.Run("tag", func(t *testing.T) {
+		env := setup(t)
+		env.equals(env.run("link", "--reachable", "docs/e", "--tag", "depends-on"), "1  docs/a")
+	})
+
+	t.Run("json", func(t *testing.T) {
+		env := setup(t)
+		out := env.run("link", "--reachable", "docs/c", "--depth", "1", "-o", "json")
+		env.equals(out, `{"path":"docs/c","reached":[{"path":"docs/b","distance":1},{"path":"docs/d","distance":1}]}`)
+	})
+
+	t.Run("none", func(t *testing.T) {
+		env := setup(t)
+		env.equals(env.run("link", "--reachable", "docs/island", "-o", "json"), `{"path":"docs/island","reached":[]}`)
+	})
+
+	t.Run("errors", func(t *testing.T) {
+		env := setup(t)
+		if _, err := env.runErr("link", "--reachable"); err == nil {
+			t.Error("link --reachable without a document = nil, want error")
+		}
+		if _, err := env.runErr("link", "--reachable", "docs/a", "--depth", "-1"); err == nil {
+			t.Error("link --reachable --depth -1 = nil, want error")
+		}
+	})
+}
+
+func TestLink_Direction(t *testing.T) {
+	env := newTestEnv(t)
+	for _, p := range []string{"app", "lib", "util"} {
+		env.runStdin(p, "write", "docs/"+p)
+	}
+	// app depends on lib, lib depends on util
+	env.run("link", "--tag", "depends-on", "docs/app", "docs/lib")
+	env.run("link", "--tag", "depends-on", "docs/lib", "docs/util")
+
+	out := env.run("link", "--list", "docs/lib", "--direction", "out")
+	env.contains(out, "docs/util")
+	if strings.Contains(out, "docs/app") {
+		t.Errorf("--direction out lists incoming link:\n%s", out)
+	}
+
+	out = env.run("link", "--list", "docs/lib", "--direction", "in")
+	env.contains(out, "docs/app")
+	if strings.Contains(out, "docs/util") {
+		t.Errorf("--direction in lists outgoing link:\n%s", out)
+	}
+
+	out = env.run("link", "--list", "docs/lib")
+	env.contains(out, "docs/app")
+	env.contains(out, "docs/util")
+
+	// Everything that depends on util, directly or indirectly
+	env.equals(env.run("link", "--reachable", "docs/util", "--direction", "in"), "1  docs/lib\n2  docs/app")
+	env.equals(env.run("link", "--reachable", "docs/util", "--direction", "out"), "")
+
+	out, err := env.runErr("link", "--list", "docs/lib", "--direction", "sideways")
+	if err == nil {
+		t.Fatal("link --direction sideways = nil, want error")
+	}
+	env.contains(out, "invalid link direction")
+}
+
+func TestLink_NoteWeight(t *testing.T) {
+	env := newTestEnv(t)
+	for _, p := range []string{"spec", "old", "new", "misc"} {
+		env.runStdin(p, "write", "docs/"+p)
+	}
+	env.run("link", "docs/spec", "docs/misc")
+	env.run("link", "--note", "supersedes", "--weight", "10", "docs/spec", "docs/new")
+	env.run("link", "--weight", "5", "docs/spec", "docs/old")
+
+	out := env.run("link", "--list", "docs/spec")
+	lines := strings.Split(strings.TrimSpace(out), "\n")
+	if len(lines) != 3 {
+		t.Fatalf("link --list = %d lines, want 3:\n%s", len(lines), out)
+	}
+	for i, want := range []string{"docs/new  # supersedes", "docs/old", "docs/misc"} {
+		if !strings.HasSuffix(lines[i], want) {
+			t.Errorf("line %d = %q, want suffix %q", i, lines[i], want)
+		}
+	}
+
+	out = env.run("link", "--list", "docs/spec", "-o", "json")
+	env.contains(out, `"to_path":"docs/new","to_source":"documents","note":"supersedes","weight":10`)
+}
+
+func TestLink_FromContent(t *testing.T) {
+	env := newTestEnv(t)
+	env.runStdin("# API", "write", "docs/api")
+	env.runStdin("# Auth", "write", "docs/auth")
+	env.runStdin("# Readme\n\nSee [API](docs/api), [auth](./auth.md) and [API again](docs/api).\n[gone](docs/gone) and [web](https://example.com)\n", "write", "docs/readme")
+
+	out := env.run("link", "--from-content", "docs/readme")
+	env.contains(out, "docs/readme -> docs/api [markdown]")
+	env.contains(out, "docs/readme -> docs/auth [markdown]")
+	env.contains(out, "skipped  docs/readme:4 -> docs/gone (missing)")
+	if n := strings.Count(out, "-> docs/api"); n != 1 {
+		t.Errorf("docs/api linked %d times, want 1:\n%s", n, out)
+	}
+
+	out = env.run("link", "--list", "docs/readme", "--direction", "out")
+	env.contains(out, "docs/api [markdown]")
+	env.contains(out, "docs/auth [markdown]")
+
+	t.Run("idempotent", func(t *testing.T) {
+		env.run("link", "--from-content", "docs/readme")
+		out := env.run("link", "--list", "docs/readme")
+		if n := len(strings.Split(strings.TrimSpace(out), "\n")); n != 2 {
+			t.Errorf("link --list = %d lines after re-run, want 2:\n%s", n, out)
+		}
+	})
+
+	t.Run("custom tag and json", func(t *testing.T) {
+		out := env.run("link", "--from-content", "docs/readme", "--tag", "refs", "-o", "json")
+		env.contains(out, `"tag":"refs"`)
+		env.contains(out, `"target":"docs/gone"`)
+	})
+
+	t.Run("requires one document", func(t *testing.T) {
+		if _, err := env.runErr("link", "--from-content"); err == nil {
+			t.Error("link --from-content with no document should fail")
+		}
+	})
+}
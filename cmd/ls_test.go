@@ -54,6 +54,25 @@ func TestLs_Recursive(t *testing.T) {
 		env.contains(out, "docs/api")
 		env.contains(out, "docs/notes/meeting")
 	})
+
+	t.Run("prefix matches whole directory names only", func(t *testing.T) {
+		env := newTestEnv(t)
+		env.runStdin("api content", "write", "docs/api")
+		env.runStdin("other content", "write", "docs2/api")
+
+		for _, args := range [][]string{
+			{"ls", "docs/"},
+			{"ls", "-R", "docs/"},
+			{"ls", "-R", "docs"},
+			{"ls", "-l", "-R", "docs"},
+		} {
+			out := env.run(args...)
+			env.contains(out, "docs/api")
+			if strings.Contains(out, "docs2/api") {
+				t.Errorf("%v listed docs2/api:\n%s", args, out)
+			}
+		}
+	})
 }
 
 func TestLs(t *testing.T) {
@@ -100,6 +119,53 @@ func TestLs(t *testing.T) {
 		env.contains(out, `"path"`)
 		env.contains(out, "docs/readme")
 	})
+
+	t.Run("TSV output", func(t *testing.T) {
+		env := newTestEnv(t)
+		env.runStdin("content", "write", "docs/readme", "-a", "alice")
+
+		out := env.run("ls", "-R", "-o", "tsv")
+		lines := strings.Split(strings.TrimSpace(out), "\n")
+		if len(lines) != 2 {
+			t.Fatalf("ls -o tsv = %d lines, want 2:\n%s", len(lines), out)
+		}
+		env.equals(lines[0], "key\tpath\tversion\tauthor\tsize\tcreated_at")
+		env.contains(lines[1], "\tdocs/readme\t1\talice\t7\t")
+	})
+
+	t.Run("delimited output elsewhere is rejected", func(t *testing.T) {
+		env := newTestEnv(t)
+		env.runStdin("content", "write", "docs/readme")
+
+		out, err := env.runErr("cat", "docs/readme", "-o", "csv")
+		if err == nil {
+			t.Fatalf("cat -o csv = nil, want error\noutput: %s", out)
+		}
+		env.contains(out, "cat does not support -o csv (supported by: audit, history, ls)")
+
+		out, err = env.runErr("tag", "ls", "-o", "tsv")
+		if err == nil {
+			t.Fatalf("tag ls -o tsv = nil, want error\noutput: %s", out)
+		}
+		env.contains(out, "tag ls does not support -o tsv")
+	})
+
+	t.Run("JSON lines output", func(t *testing.T) {
+		env := newTestEnv(t)
+		env.runStdin("content", "write", "docs/readme")
+		env.runStdin("content", "write", "docs/api")
+
+		out := env.run("ls", "-R", "-o", "jsonl")
+		lines := strings.Split(strings.TrimSpace(out), "\n")
+		if len(lines) != 2 {
+			t.Fatalf("ls -o jsonl = %d lines, want 2:\n%s", len(lines), out)
+		}
+		for _, line := range lines {
+			if !strings.HasPrefix(line, "{") || !strings.HasSuffix(line, "}") {
+				t.Errorf("ls -o jsonl line %q is not a JSON object", line)
+			}
+		}
+	})
 }
 
 func TestLs_Formats(t *testing.T) {
@@ -285,6 +351,25 @@ func TestLs_Sort(t *testing.T) {
 		env.contains(out, "ccc")
 	})
 
+	t.Run("sort by size", func(t *testing.T) {
+		env := newTestEnv(t)
+		env.runStdin("mid size", "write", "medium")
+		env.runStdin("s", "write", "small")
+		env.runStdin("the largest of the three", "write", "large")
+
+		out := env.run("ls", "-s", "size", "--files-only")
+		env.equals(out, "large\nmedium\nsmall")
+
+		out = env.run("ls", "-s", "size", "-r", "--files-only")
+		env.equals(out, "small\nmedium\nlarge")
+
+		// Without -l the plain KEY  PATH format is kept
+		lines := strings.Split(strings.TrimSpace(env.run("ls", "-s", "size")), "\n")
+		if len(lines) != 3 || !strings.HasSuffix(lines[0], "  large") {
+			t.Errorf("Ls(-s size) = %q, want large first in KEY  PATH format", lines)
+		}
+	})
+
 	t.Run("invalid sort field rejected", func(t *testing.T) {
 		env := newTestEnv(t)
 		env.runStdin("content", "write", "test")
@@ -295,3 +380,184 @@ func TestLs_Sort(t *testing.T) {
 		}
 	})
 }
+
+func TestLs_DirsFilesOnly(t *testing.T) {
+	setup := func(t *testing.T) *testEnv {
+		env := newTestEnv(t)
+		env.runStdin("content", "write", "readme")
+		env.runStdin("content", "write", "docs/api")
+		env.runStdin("content", "write", "docs/notes/meeting")
+		env.runStdin("content", "write", "docs/notes/retro")
+		return env
+	}
+
+	t.Run("dirs-only lists directories with counts", func(t *testing.T) {
+		env := setup(t)
+		out := env.run("ls", "-R", "--dirs-only")
+		env.equals(out, "docs/ (3)\ndocs/notes/ (2)")
+	})
+
+	t.Run("dirs-only tree", func(t *testing.T) {
+		env := setup(t)
+		out := env.run("ls", "-R", "-t", "--dirs-only")
+		env.equals(out, "└── docs/ (3)\n    └── notes/ (2)")
+	})
+
+	t.Run("dirs-only respects prefix", func(t *testing.T) {
+		env := setup(t)
+		out := env.run("ls", "-R", "--dirs-only", "docs/notes")
+		env.equals(out, "docs/notes/ (2)")
+	})
+
+	t.Run("files-only prints paths", func(t *testing.T) {
+		env := setup(t)
+		out := env.run("ls", "-R", "-t", "--files-only")
+		env.equals(out, "docs/api\ndocs/notes/meeting\ndocs/notes/retro\nreadme")
+	})
+
+	t.Run("files-only without -R", func(t *testing.T) {
+		env := setup(t)
+		out := env.run("ls", "--files-only", "docs")
+		env.equals(out, "docs/api")
+	})
+
+	t.Run("dirs-only JSON", func(t *testing.T) {
+		env := setup(t)
+		out := env.run("ls", "-R", "--dirs-only", "-o", "json")
+		env.contains(out, `{"path":"docs/notes","count":2}`)
+	})
+
+	t.Run("both flags rejected", func(t *testing.T) {
+		env := setup(t)
+		env.runErr("ls", "--dirs-only", "--files-only")
+	})
+}
+
+func TestLs_ModifiedSince(t *testing.T) {
+	env := newTestEnv(t)
+	env.runStdin("content", "write", "docs/recent")
+
+	for _, args := range [][]string{
+		{"ls", "-R", "--modified-since", "24h"},
+		{"ls", "-R", "-l", "--modified-since", "7d"},
+		{"ls", "-R", "-o", "json", "--modified-since", "2000-01-01T00:00:00Z"},
+	} {
+		env.contains(env.run(args...), "docs/recent")
+	}
+
+	out := env.run("ls", "-R", "-l", "--modified-since", "2999-01-01T00:00:00Z")
+	if strings.Contains(out, "docs/recent") {
+		t.Errorf("ls --modified-since in the future = %q, want no documents", out)
+	}
+	env.equals(env.run("ls", "-R", "-o", "json", "--modified-since", "2999-01-01"), "[]")
+
+	env.runErr("ls", "--modified-since", "yesterday")
+}
+
+func TestLs_Size(t *testing.T) {
+	env := newTestEnv(t)
+	env.runStdin("todo", "write", "stub")
+	env.runStdin(strings.Repeat("x", 2048), "write", "big")
+
+	out := env.run("ls", "--min-size", "1000")
+	env.contains(out, "big")
+	if strings.Contains(out, "stub") {
+		t.Errorf("ls --min-size 1000 = %q, want stub excluded", out)
+	}
+
+	out = env.run("ls", "-l", "--min-size", "4")
+	env.contains(out, "stub")
+
+	out = env.run("ls", "-l", "--max-size", "10")
+	env.contains(out, "stub")
+	if strings.Contains(out, "big") {
+		t.Errorf("ls -l --max-size 10 = %q, want big excluded", out)
+	}
+
+	t.Run("long format shows bytes unless --human", func(t *testing.T) {
+		env.contains(env.run("ls", "-l", "big"), " 2048 ")
+		env.contains(env.run("ls", "-l", "--human", "big"), " 2.0K ")
+	})
+
+	t.Run("JSON keeps raw bytes", func(t *testing.T) {
+		env.contains(env.run("ls", "-l", "--human", "-o", "json", "big"), `"size":2048`)
+	})
+
+	t.Run("min above max rejected", func(t *testing.T) {
+		env.runErr("ls", "--min-size", "10", "--max-size", "5")
+	})
+}
+
+func TestLs_CountOnly(t *testing.T) {
+	env := newTestEnv(t)
+	env.runStdin("content", "write", "readme")
+	env.runStdin("content", "write", "docs/api")
+	env.runStdin("content", "write", "docs/guide")
+	env.runStdin("content", "write", "gone")
+	env.run("rm", "gone")
+
+	env.equals(env.run("ls", "--count-only"), "1")
+	env.equals(env.run("ls", "-R", "--count-only"), "3")
+	env.equals(env.run("ls", "-R", "-D", "--count-only"), "1")
+	env.equals(env.run("ls", "-R", "-A", "--count-only"), "4")
+	env.equals(env.run("ls", "--count-only", "docs"), "2")
+	env.equals(env.run("ls", "-R", "--dirs-only", "--count-only"), "1")
+	env.equals(env.run("ls", "-R", "--count-only", "-o", "json"), `{"count":3}`)
+}
+
+func TestLs_AsOf(t *testing.T) {
+	env := newTestEnv(t)
+	env.runStdin("a", "write", "docs/a")
+	env.runStdin("b", "write", "docs/b")
+	env.run("rm", "docs/b")
+
+	out := env.run("ls", "docs/", "--as-of", "2999-01-01")
+	env.contains(out, "docs/a")
+	if strings.Contains(out, "docs/b") {
+		t.Errorf("ls --as-of after rm listed deleted doc:\n%s", out)
+	}
+
+	out = env.run("ls", "docs/", "--as-of", "2000-01-01", "-l")
+	if strings.Contains(out, "docs/a") {
+		t.Errorf("ls --as-of before creation listed doc:\n%s", out)
+	}
+
+	if _, err := env.runErr("ls", "--as-of", "2999-01-01", "-A"); err == nil {
+		t.Error("ls --as-of -A = nil, want error")
+	}
+}
+
+func TestLs_Depth(t *testing.T) {
+	env := newTestEnv(t)
+	env.runStdin("a", "write", "docs/a")
+	env.runStdin("b", "write", "docs/api/b")
+	env.runStdin("c", "write", "docs/api/v1/c")
+
+	out := env.run("ls", "-R", "--depth", "2", "docs/")
+	env.contains(out, "docs/a")
+	env.contains(out, "docs/api/b")
+	if strings.Contains(out, "docs/api/v1/c") {
+		t.Errorf("ls --depth 2 listed a document three levels down:\n%s", out)
+	}
+
+	// Depth 1 is the same as a plain listing; --depth implies -R
+	out = env.run("ls", "--depth", "1", "docs/", "-o", "json")
+	env.contains(out, `"docs/a"`)
+	if strings.Contains(out, "docs/api/b") {
+		t.Errorf("ls --depth 1 -o json listed a nested document:\n%s", out)
+	}
+
+	// Tree output is pruned at the same depth
+	out = env.run("ls", "-t", "--depth", "2", "docs/")
+	env.contains(out, "api")
+	if strings.Contains(out, "v1") {
+		t.Errorf("ls -t --depth 2 shows directories below the limit:\n%s", out)
+	}
+
+	out = env.run("ls", "--depth", "2", "docs/", "--count-only")
+	env.equals(out, "2")
+
+	if _, err := env.runErr("ls", "--depth", "-1"); err == nil {
+		t.Error("ls --depth -1 = nil, want error")
+	}
+}
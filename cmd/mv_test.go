@@ -215,3 +215,110 @@ func TestMv_MultipleFiles(t *testing.T) {
 		}
 	})
 }
+
+// TestMv_UpdatesLinks guards against moves orphaning links: both endpoints
+// must follow the document to its new path.
+func TestMv_UpdatesLinks(t *testing.T) {
+	env := newTestEnv(t)
+	env.runStdin("a", "write", "docs/a")
+	env.runStdin("b", "write", "docs/b")
+	env.run("link", "docs/a", "docs/b")
+
+	env.run("mv", "docs/a", "docs/c")
+
+	out := env.run("link", "--list", "docs/c")
+	env.contains(out, "docs/b")
+
+	out = env.run("link", "--list", "docs/b")
+	env.contains(out, "docs/c")
+	if strings.Contains(out, "docs/a") {
+		t.Errorf("link still references old path, got: %s", out)
+	}
+
+	out, _ = env.runErr("link", "--list", "docs/a")
+	if strings.Contains(out, "docs/b") {
+		t.Errorf("old path should have no links, got: %s", out)
+	}
+}
+
+func TestMv_Atomic(t *testing.T) {
+	env := newTestEnv(t)
+	env.runStdin("a", "write", "docs/a")
+	env.runStdin("b", "write", "docs/b")
+	env.runStdin("c", "write", "docs/c")
+	env.runStdin("taken", "write", "archive/b")
+
+	// The second source collides, so none of the sources move
+	out, err := env.runErr("mv", "docs/a", "docs/b", "docs/c", "archive/")
+	if err == nil {
+		t.Fatal("mv with a colliding source = nil, want error")
+	}
+	env.contains(out, "already exists")
+
+	env.equals(env.run("cat", "docs/a"), "a")
+	env.equals(env.run("cat", "docs/c"), "c")
+	if _, err := env.runErr("cat", "archive/a"); err == nil {
+		t.Error("archive/a exists, want first move rolled back")
+	}
+}
+
+func TestMv_DryRun(t *testing.T) {
+	env := newTestEnv(t)
+	env.runStdin("a", "write", "docs/a")
+	env.runStdin("b", "write", "docs/b")
+	env.runStdin("taken", "write", "archive/b")
+
+	out := env.run("mv", "docs/a", "docs/b", "archive/", "--dry-run")
+	env.contains(out, "Would move: docs/a -> archive/a")
+	env.contains(out, "Would fail: docs/b -> archive/b (destination exists)")
+	env.contains(out, "1 of 2 moves would fail")
+
+	// Nothing was moved
+	env.equals(env.run("cat", "docs/a"), "a")
+	env.equals(env.run("cat", "archive/b"), "taken")
+
+	out = env.run("mv", "docs/a", "docs/b", "archive/", "-n", "-o", "json")
+	env.contains(out, `"moves":[{"from":"docs/a","to":"archive/a"},{"from":"docs/b","to":"archive/b"}]`)
+	env.contains(out, `"collisions":[{"from":"docs/b","to":"archive/b","reason":"destination exists"}]`)
+}
+
+// mvCases are the move forms the CLI and the MCP llmd_move tool must treat
+// alike: each starts from documents a, c and d.
+var mvCases = []struct {
+	name    string
+	sources []string
+	dest    string
+	want    []string // Paths after the move
+	gone    []string // Paths moved away
+}{
+	{"rename", []string{"a"}, "b", []string{"b", "c", "d"}, []string{"a"}},
+	{"into prefix", []string{"a"}, "b/", []string{"b/a", "c", "d"}, []string{"a"}},
+	{"many into prefix", []string{"a", "c", "d"}, "dest/", []string{"dest/a", "dest/c", "dest/d"}, []string{"a", "c", "d"}},
+}
+
+// checkMoved asserts the documents of a mvCase after it ran.
+func checkMoved(t *testing.T, env *testEnv, want, gone []string) {
+	t.Helper()
+	for _, p := range want {
+		env.equals(env.run("cat", p), "content")
+	}
+	for _, p := range gone {
+		if _, err := env.runErr("cat", p); err == nil {
+			t.Errorf("cat %s after move = nil, want error", p)
+		}
+	}
+}
+
+func TestMv_Semantics(t *testing.T) {
+	for _, tc := range mvCases {
+		t.Run(tc.name, func(t *testing.T) {
+			env := newTestEnv(t)
+			for _, p := range []string{"a", "c", "d"} {
+				env.runStdin("content", "write", p)
+			}
+
+			env.run(append(append([]string{"mv"}, tc.sources...), tc.dest)...)
+			checkMoved(t, env, tc.want, tc.gone)
+		})
+	}
+}
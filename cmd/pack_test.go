@@ -0,0 +1,64 @@
+package cmd
+
+import (
+	"strings"
+	"testing"
+)
+
+func TestPack(t *testing.T) {
+	t.Run("wraps documents in order", func(t *testing.T) {
+		env := newTestEnv(t)
+		env.runStdin("# A\nFirst.", "write", "docs/a")
+		env.runStdin("# B\nSecond.", "write", "docs/b")
+
+		out := env.run("pack", "docs/b", "docs/a", "--budget", "1000")
+		env.contains(out, "<document path=\"docs/b\">\n<![CDATA[\n# B\nSecond.\n]]>\n</document>\n\n<document path=\"docs/a\">")
+		env.contains(out, "Packed 2 document(s), about ")
+		env.contains(out, " of 1000 tokens")
+	})
+
+	t.Run("search adds matches", func(t *testing.T) {
+		env := newTestEnv(t)
+		env.runStdin("deploy steps", "write", "docs/deploy")
+		env.runStdin("unrelated", "write", "docs/other")
+
+		out := env.run("pack", "--search", "deploy", "--budget", "1000")
+		env.contains(out, `<document path="docs/deploy">`)
+		if strings.Contains(out, "docs/other") {
+			t.Errorf("pack --search included a non-matching document:\n%s", out)
+		}
+	})
+
+	t.Run("over budget", func(t *testing.T) {
+		env := newTestEnv(t)
+		env.runStdin("# Intro\nShort.\n## Detail\n"+strings.Repeat("word ", 200), "write", "docs/long")
+
+		out := env.run("pack", "docs/long", "--budget", "40")
+		env.contains(out, "Packed 0 document(s)")
+		env.contains(out, "Skipped (over budget): docs/long")
+
+		out = env.run("pack", "docs/long", "--budget", "40", "--truncate")
+		env.contains(out, "<document path=\"docs/long\" truncated=\"true\">\n<![CDATA[\n# Intro\nShort.\n]]>\n</document>")
+		env.contains(out, "1 truncated")
+	})
+
+	t.Run("JSON output", func(t *testing.T) {
+		env := newTestEnv(t)
+		env.runStdin("Hello", "write", "docs/a")
+
+		out := env.run("pack", "docs/a", "--budget", "100", "-o", "json")
+		env.contains(out, `"budget":100`)
+		env.contains(out, `"path":"docs/a"`)
+		env.contains(out, `"skipped":[]`)
+		env.contains(out, `"content":"`)
+	})
+
+	t.Run("budget required", func(t *testing.T) {
+		env := newTestEnv(t)
+		env.runStdin("Hello", "write", "docs/a")
+
+		if _, err := env.runErr("pack", "docs/a"); err == nil {
+			t.Error("Pack() without --budget = nil, want error")
+		}
+	})
+}
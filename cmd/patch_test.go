@@ -0,0 +1,51 @@
+package cmd
+
+import "testing"
+
+func TestPatch(t *testing.T) {
+	t.Run("applies hunk", func(t *testing.T) {
+		env := newTestEnv(t)
+		env.runStdin(sampleDoc, "write", "docs/api")
+
+		diff := "--- a/api\n+++ b/api\n@@ -9,2 +9,2 @@\n-All requests require a Bearer token in the Authorisation header.\n+All requests require a JWT in the Authorisation header.\n The token should be obtained from the /auth/login endpoint.\n"
+		env.runStdin(diff, "patch", "docs/api")
+
+		out := env.run("cat", "docs/api")
+		env.contains(out, "require a JWT in the")
+		env.contains(out, "/auth/login endpoint")
+	})
+
+	t.Run("creates a version", func(t *testing.T) {
+		env := newTestEnv(t)
+		env.runStdin("one\ntwo\n", "write", "docs/notes")
+
+		env.runStdin("@@\n one\n-two\n+three\n", "patch", "docs/notes", "-m", "Fix two")
+
+		out := env.run("history", "docs/notes")
+		env.contains(out, "Fix two")
+		out = env.run("cat", "docs/notes")
+		env.equals(out, "one\nthree")
+	})
+
+	t.Run("failing hunk writes nothing", func(t *testing.T) {
+		env := newTestEnv(t)
+		env.runStdin("one\ntwo\n", "write", "docs/notes")
+
+		out, err := env.runStdinErr("@@ -1 +1 @@\n-one\n+ONE\n@@ -2 +2 @@\n-missing\n+x\n", "patch", "docs/notes")
+		if err == nil {
+			t.Fatal("patch with failing hunk should fail")
+		}
+		env.contains(out, "hunk 2")
+
+		out = env.run("cat", "docs/notes")
+		env.equals(out, "one\ntwo")
+	})
+
+	t.Run("json output", func(t *testing.T) {
+		env := newTestEnv(t)
+		env.runStdin("one\ntwo\n", "write", "docs/notes")
+
+		out := env.runStdin("@@\n-two\n+three\n", "patch", "docs/notes", "-o", "json")
+		env.contains(out, `"hunks":1`)
+	})
+}
@@ -0,0 +1,74 @@
+package cmd
+
+import (
+	"encoding/json"
+	"testing"
+)
+
+func TestResolve(t *testing.T) {
+	type result struct {
+		Input         string `json:"input"`
+		ResolvedPath  string `json:"resolved_path"`
+		Key           string `json:"key"`
+		Version       int    `json:"version"`
+		InterpretedAs string `json:"interpreted_as"`
+		Shadows       string `json:"shadows"`
+	}
+	resolve := func(env *testEnv, input string) result {
+		t.Helper()
+		var r result
+		out := env.run("resolve", input, "-o", "json")
+		if err := json.Unmarshal([]byte(out), &r); err != nil {
+			t.Fatalf("Resolve(%s) = %s, want JSON: %v", input, out, err)
+		}
+		return r
+	}
+
+	t.Run("path", func(t *testing.T) {
+		env := newTestEnv(t)
+		env.runStdin("v1", "write", "docs/readme")
+		env.runStdin("v2", "write", "docs/readme")
+
+		r := resolve(env, "docs/readme")
+		if r.InterpretedAs != "path" || r.ResolvedPath != "docs/readme" || r.Version != 2 {
+			t.Errorf("Resolve(docs/readme) = %+v, want docs/readme v2 as path", r)
+		}
+
+		out := env.run("resolve", "docs/readme")
+		env.contains(out, "docs/readme -> docs/readme v2")
+	})
+
+	t.Run("key", func(t *testing.T) {
+		env := newTestEnv(t)
+		env.runStdin("v1", "write", "docs/readme")
+		key := resolve(env, "docs/readme").Key
+		env.runStdin("v2", "write", "docs/readme")
+
+		r := resolve(env, key)
+		if r.InterpretedAs != "key" || r.ResolvedPath != "docs/readme" || r.Version != 1 {
+			t.Errorf("Resolve(%s) = %+v, want docs/readme v1 as key", key, r)
+		}
+	})
+
+	t.Run("path shadows key", func(t *testing.T) {
+		env := newTestEnv(t)
+		env.runStdin("target", "write", "docs/api")
+		key := resolve(env, "docs/api").Key
+		env.runStdin("shadow", "write", key)
+
+		r := resolve(env, key)
+		if r.InterpretedAs != "path" || r.ResolvedPath != key || r.Shadows != "docs/api" {
+			t.Errorf("Resolve(%s) = %+v, want path shadowing docs/api", key, r)
+		}
+
+		out := env.run("resolve", key)
+		env.contains(out, "shadows key of docs/api v1")
+	})
+
+	t.Run("not found", func(t *testing.T) {
+		env := newTestEnv(t)
+		if _, err := env.runErr("resolve", "docs/missing"); err == nil {
+			t.Error("Resolve(docs/missing) = nil, want error")
+		}
+	})
+}
@@ -198,3 +198,30 @@ func TestRestore_MultipleFiles(t *testing.T) {
 		}
 	})
 }
+
+func TestRestore_Links(t *testing.T) {
+	env := newTestEnv(t)
+	env.runStdin("one", "write", "docs/one")
+	env.runStdin("two", "write", "docs/two")
+	env.runStdin("three", "write", "docs/three")
+	env.run("link", "docs/one", "docs/two")
+	env.run("link", "docs/three", "docs/one")
+
+	env.run("rm", "docs/one")
+	env.run("rm", "docs/three")
+	out := env.run("link", "--list", "docs/two")
+	if strings.Contains(out, "docs/one") {
+		t.Errorf("links should be removed with the document, got: %s", out)
+	}
+
+	env.run("restore", "docs/one")
+	out = env.run("link", "--list", "docs/one")
+	env.contains(out, "docs/two")
+	if strings.Contains(out, "docs/three") {
+		t.Errorf("link to deleted docs/three should stay removed, got: %s", out)
+	}
+
+	env.run("restore", "docs/three")
+	out = env.run("link", "--list", "docs/one")
+	env.contains(out, "docs/three")
+}
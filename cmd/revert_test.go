@@ -218,6 +218,32 @@ func TestRevert_KeyFlag(t *testing.T) {
 	// Verify content
 	out = env.run("cat", "docs/readme")
 	env.equals(out, "version 1")
+
+	// Default message names the key rather than an empty target
+	out = env.run("history", "docs/readme")
+	env.contains(out, "Revert to "+key)
+}
+
+func TestRevert_VersionFlag(t *testing.T) {
+	env := newTestEnv(t)
+	env.runStdin("version 1", "write", "docs/readme")
+	env.runStdin("version 2", "write", "docs/readme")
+
+	out := env.run("revert", "docs/readme", "-v", "1")
+	env.contains(out, "Reverted docs/readme to v1")
+
+	out = env.run("history", "docs/readme")
+	env.contains(out, "Revert to v1")
+
+	_, err := env.runErr("revert", "docs/readme", "1", "-v", "2")
+	if err == nil {
+		t.Error("Revert(positional and -v) = nil, want error")
+	}
+
+	_, err = env.runErr("revert", "docs/readme", "-v", "0")
+	if err == nil {
+		t.Error("Revert(-v 0) = nil, want error")
+	}
 }
 
 func TestRevert_VersionValidation(t *testing.T) {
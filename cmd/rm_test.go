@@ -1,6 +1,7 @@
 package cmd
 
 import (
+	"fmt"
 	"strings"
 	"testing"
 )
@@ -114,6 +115,30 @@ func TestRm_KeyFlag(t *testing.T) {
 	env.contains(out, "v2")
 }
 
+func TestRm_LatestVersion(t *testing.T) {
+	env := newTestEnv(t)
+	env.runStdin("v1", "write", "docs/readme")
+	env.runStdin("v2", "write", "docs/readme")
+	env.runStdin("v3", "write", "docs/readme")
+
+	env.run("rm", "docs/readme", "--version", "3")
+
+	// The previous version becomes the latest
+	out := env.run("cat", "docs/readme")
+	env.equals(out, "v2")
+
+	out = env.run("history", "docs/readme")
+	env.contains(out, "v2")
+	if strings.Contains(out, "v3") {
+		t.Errorf("history should hide deleted v3, got: %s", out)
+	}
+
+	// A new write continues after the deleted version
+	env.runStdin("v4", "write", "docs/readme")
+	out = env.run("cat", "docs/readme", "-o", "json")
+	env.contains(out, `"version":4`)
+}
+
 func TestRm_MultipleFiles(t *testing.T) {
 	t.Run("delete multiple paths", func(t *testing.T) {
 		env := newTestEnv(t)
@@ -195,3 +220,76 @@ func TestRm_MultipleFiles(t *testing.T) {
 		}
 	})
 }
+
+func TestRm_Bulk(t *testing.T) {
+	t.Run("glob", func(t *testing.T) {
+		env := newTestEnv(t)
+		env.runStdin("a", "write", "docs/tmp/a")
+		env.runStdin("b", "write", "docs/tmp/sub/b")
+		env.runStdin("keep", "write", "docs/keep")
+
+		out := env.run("rm", "--glob", "docs/tmp/**")
+		env.contains(out, "Deleted docs/tmp/a")
+		env.contains(out, "Deleted docs/tmp/sub/b")
+		env.contains(out, "2 document(s) deleted")
+
+		out = env.run("ls", "-R")
+		env.contains(out, "docs/keep")
+		if strings.Contains(out, "docs/tmp") {
+			t.Errorf("Rm --glob left matches:\n%s", out)
+		}
+	})
+
+	t.Run("tag", func(t *testing.T) {
+		env := newTestEnv(t)
+		env.runStdin("a", "write", "docs/a")
+		env.runStdin("b", "write", "docs/b")
+		env.run("tag", "add", "docs/a", "obsolete")
+
+		out := env.run("rm", "--tag", "obsolete", "-o", "json")
+		env.contains(out, `"count":1`)
+		env.contains(out, `"docs/a"`)
+
+		out = env.run("ls", "-R")
+		env.contains(out, "docs/b")
+		if strings.Contains(out, "docs/a") {
+			t.Errorf("Rm --tag left docs/a:\n%s", out)
+		}
+	})
+
+	t.Run("dry run", func(t *testing.T) {
+		env := newTestEnv(t)
+		env.runStdin("a", "write", "docs/tmp/a")
+
+		out := env.run("rm", "--glob", "docs/tmp/**", "--dry-run")
+		env.contains(out, "Would delete docs/tmp/a")
+		env.contains(out, "1 document(s) would be deleted")
+		env.contains(env.run("ls", "-R"), "docs/tmp/a")
+	})
+
+	t.Run("threshold requires yes", func(t *testing.T) {
+		env := newTestEnv(t)
+		for i := range 11 {
+			env.runStdin("x", "write", fmt.Sprintf("docs/tmp/%02d", i))
+		}
+
+		out, err := env.runErr("rm", "--glob", "docs/tmp/*")
+		if err == nil {
+			t.Fatal("Rm --glob over threshold without --yes = nil, want error")
+		}
+		env.contains(out, "--yes")
+		env.contains(env.run("ls", "-R"), "docs/tmp/00")
+
+		out = env.run("rm", "--glob", "docs/tmp/*", "--yes")
+		env.contains(out, "11 document(s) deleted")
+	})
+
+	t.Run("paths rejected", func(t *testing.T) {
+		env := newTestEnv(t)
+		env.runStdin("a", "write", "docs/a")
+
+		if _, err := env.runErr("rm", "--glob", "docs/*", "docs/a"); err == nil {
+			t.Error("Rm --glob with a path = nil, want error")
+		}
+	})
+}
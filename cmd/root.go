@@ -15,9 +15,12 @@ Copyright © 2026 James Lawson (jpl-au) <hello@caelisco.net>
 package cmd
 
 import (
+	"errors"
 	"fmt"
 	"os"
 	"slices"
+	"strconv"
+	"strings"
 
 	"github.com/jpl-au/llmd/internal/config"
 	"github.com/jpl-au/llmd/internal/log"
@@ -35,6 +38,15 @@ var rootCmd = &cobra.Command{
 		if output != "" && !slices.Contains(validOutputFormats, output) {
 			return fmt.Errorf("invalid output format: %s (valid: %v)", output, validOutputFormats)
 		}
+		if Delimiter() != 0 && (cmd.Parent() != cmd.Root() || !slices.Contains(delimitedCommands, cmd.Name())) {
+			return fmt.Errorf("%s does not support -o %s (supported by: %s)",
+				strings.TrimPrefix(cmd.CommandPath(), "llmd "), output, strings.Join(delimitedCommands, ", "))
+		}
+
+		// Timings go to stderr so stdout stays parseable (JSON, MCP stdio)
+		if verbose {
+			log.SetVerbose(os.Stderr)
+		}
 
 		// Detect author if not explicitly set
 		if author == "" {
@@ -96,10 +108,32 @@ func Execute() {
 	}
 
 	if err != nil {
+		var ee *ExitError
+		if errors.As(err, &ee) {
+			os.Exit(ee.Code)
+		}
 		os.Exit(1)
 	}
 }
 
+// ExitError ends the process with Code and no error message. Commands use it
+// to report a result through the exit status alone, e.g. "diff --check".
+type ExitError struct {
+	Code int
+}
+
+func (e *ExitError) Error() string {
+	return "exit status " + strconv.Itoa(e.Code)
+}
+
+// Exit returns an ExitError for c, silencing cobra's error and usage output
+// so the status code is the only signal.
+func Exit(c *cobra.Command, code int) error {
+	c.SilenceErrors = true
+	c.SilenceUsage = true
+	return &ExitError{Code: code}
+}
+
 // RootCmd returns the root command for testing and extension access.
 func RootCmd() *cobra.Command {
 	return rootCmd
@@ -0,0 +1,59 @@
+package cmd
+
+import (
+	"encoding/json"
+	"strings"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestSections(t *testing.T) {
+	env := newTestEnv(t)
+	env.runStdin(editDoc, "write", "docs/guide")
+
+	t.Run("text", func(t *testing.T) {
+		out := env.run("sections", "docs/guide")
+		env.contains(out, "# User Guide (lines 1-23)")
+		env.contains(out, "  ## Installation (lines 7-13)")
+		env.contains(out, "  ## Troubleshooting (lines 21-23)")
+		// The "bash" fence content is not a heading
+		assert.Equal(t, 5, strings.Count(out, "\n"))
+	})
+
+	t.Run("json", func(t *testing.T) {
+		out := env.run("sections", "docs/guide", "-o", "json")
+		var r struct {
+			Path     string `json:"path"`
+			Sections []struct {
+				Heading  string `json:"heading"`
+				Level    int    `json:"level"`
+				Sections []struct {
+					Heading   string `json:"heading"`
+					StartLine int    `json:"start_line"`
+					EndLine   int    `json:"end_line"`
+				} `json:"sections"`
+			} `json:"sections"`
+		}
+		require.NoError(t, json.Unmarshal([]byte(out), &r))
+		assert.Equal(t, "docs/guide", r.Path)
+		require.Len(t, r.Sections, 1)
+		assert.Equal(t, "User Guide", r.Sections[0].Heading)
+		require.Len(t, r.Sections[0].Sections, 4)
+		assert.Equal(t, "Installation", r.Sections[0].Sections[1].Heading)
+		assert.Equal(t, 7, r.Sections[0].Sections[1].StartLine)
+		assert.Equal(t, 13, r.Sections[0].Sections[1].EndLine)
+	})
+
+	t.Run("no headings", func(t *testing.T) {
+		env.runStdin("plain text\n", "write", "docs/plain")
+		out := env.run("sections", "docs/plain", "-o", "json")
+		env.contains(out, `"sections":[]`)
+	})
+
+	t.Run("not found", func(t *testing.T) {
+		_, err := env.runErr("sections", "docs/missing")
+		assert.Error(t, err)
+	})
+}
@@ -0,0 +1,578 @@
+package cmd
+
+import (
+	"bufio"
+	"bytes"
+	"encoding/json"
+	"net"
+	"net/http"
+	"os"
+	"os/exec"
+	"runtime"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+// freeAddr returns a loopback address with a port nothing is listening on.
+func freeAddr(t *testing.T) string {
+	t.Helper()
+	ln, err := net.Listen("tcp", "127.0.0.1:0")
+	require.NoError(t, err)
+	addr := ln.Addr().String()
+	require.NoError(t, ln.Close())
+	return addr
+}
+
+// startServe runs "llmd serve" with args and waits until addr accepts
+// connections. The server is interrupted and must exit cleanly at cleanup.
+func startServe(t *testing.T, env *testEnv, addr string, args ...string) {
+	t.Helper()
+	if runtime.GOOS == "windows" {
+		t.Skip("interrupt signals are not supported on windows")
+	}
+
+	var out bytes.Buffer
+	srv := exec.Command(env.binary, append([]string{"serve"}, args...)...)
+	srv.Dir = env.dir
+	srv.Stdout = &out
+	srv.Stderr = &out
+	require.NoError(t, srv.Start())
+
+	t.Cleanup(func() {
+		_ = srv.Process.Signal(os.Interrupt)
+		if err := srv.Wait(); err != nil {
+			t.Errorf("serve did not exit cleanly: %v\noutput: %s", err, out.String())
+		}
+	})
+
+	deadline := time.Now().Add(5 * time.Second)
+	for {
+		conn, err := net.Dial("tcp", addr)
+		if err == nil {
+			conn.Close()
+			return
+		}
+		if time.Now().After(deadline) {
+			t.Fatalf("server did not listen on %s", addr)
+		}
+		time.Sleep(50 * time.Millisecond)
+	}
+}
+
+const initializeRequest = `{"jsonrpc":"2.0","id":1,"method":"initialize","params":{"protocolVersion":"2025-03-26","capabilities":{},"clientInfo":{"name":"test","version":"1"}}}`
+
+// mcpSession initialises a streamable HTTP session against addr and returns
+// a function that sends a JSON-RPC request within it and returns the body.
+func mcpSession(t *testing.T, addr string) func(body string) string {
+	t.Helper()
+	url := "http://" + addr + "/mcp"
+	send := func(session, body string) (*http.Response, string) {
+		req, err := http.NewRequest(http.MethodPost, url, strings.NewReader(body))
+		require.NoError(t, err)
+		req.Header.Set("Content-Type", "application/json")
+		if session != "" {
+			req.Header.Set("Mcp-Session-Id", session)
+		}
+		resp, err := http.DefaultClient.Do(req)
+		require.NoError(t, err)
+		defer resp.Body.Close()
+		var out bytes.Buffer
+		_, _ = out.ReadFrom(resp.Body)
+		return resp, out.String()
+	}
+
+	resp, _ := send("", initializeRequest)
+	require.Equal(t, http.StatusOK, resp.StatusCode)
+	session := resp.Header.Get("Mcp-Session-Id")
+	return func(body string) string {
+		_, out := send(session, body)
+		return out
+	}
+}
+
+func TestServe_Transports(t *testing.T) {
+	t.Run("streamable http", func(t *testing.T) {
+		env := newTestEnv(t)
+		addr := freeAddr(t)
+		startServe(t, env, addr, "--transport", "streamable-http", "--addr", addr)
+
+		resp, err := http.Post("http://"+addr+"/mcp", "application/json", strings.NewReader(initializeRequest))
+		require.NoError(t, err)
+		defer resp.Body.Close()
+
+		assert.Equal(t, http.StatusOK, resp.StatusCode)
+		var body bytes.Buffer
+		_, _ = body.ReadFrom(resp.Body)
+		assert.Contains(t, body.String(), `"name":"llmd"`)
+	})
+
+	t.Run("sse", func(t *testing.T) {
+		env := newTestEnv(t)
+		addr := freeAddr(t)
+		startServe(t, env, addr, "--transport", "sse", "--addr", addr)
+
+		resp, err := http.Get("http://" + addr + "/sse")
+		require.NoError(t, err)
+		defer resp.Body.Close()
+
+		assert.Equal(t, "text/event-stream", resp.Header.Get("Content-Type"))
+		line, err := bufio.NewReader(resp.Body).ReadString('\n')
+		require.NoError(t, err)
+		assert.Equal(t, "event: endpoint\n", line)
+	})
+
+	t.Run("unknown transport", func(t *testing.T) {
+		env := newTestEnv(t)
+		out, err := env.runErr("serve", "--transport", "carrier-pigeon")
+		assert.Error(t, err)
+		assert.Contains(t, out, "unknown transport")
+	})
+
+	t.Run("http and transport conflict", func(t *testing.T) {
+		env := newTestEnv(t)
+		_, err := env.runErr("serve", "--http", freeAddr(t), "--transport", "sse")
+		assert.Error(t, err)
+	})
+}
+
+func TestServe_HistoryResource(t *testing.T) {
+	env := newTestEnv(t)
+	env.runStdin("first", "write", "docs/readme", "-a", "tester")
+	env.runStdin("second", "write", "docs/readme", "-a", "tester", "-m", "update")
+
+	addr := freeAddr(t)
+	startServe(t, env, addr, "--transport", "streamable-http", "--addr", addr)
+	call := mcpSession(t, addr)
+
+	out := call(`{"jsonrpc":"2.0","id":2,"method":"resources/read","params":{"uri":"llmd://history/docs/readme"}}`)
+	assert.Contains(t, out, `"mimeType":"application/json"`)
+	assert.Contains(t, out, `\"version\":2`)
+	assert.Contains(t, out, `\"message\":\"update\"`)
+	assert.NotContains(t, out, "second", "history omits content")
+
+	out = call(`{"jsonrpc":"2.0","id":3,"method":"resources/read","params":{"uri":"llmd://history/docs/missing"}}`)
+	assert.Contains(t, out, `"error"`)
+
+	// Clients that escape the template variable's slashes
+	out = call(`{"jsonrpc":"2.0","id":4,"method":"resources/read","params":{"uri":"llmd://history/docs%2Freadme"}}`)
+	assert.Contains(t, out, `\"version\":2`)
+	out = call(`{"jsonrpc":"2.0","id":5,"method":"resources/read","params":{"uri":"llmd://documents/docs%2Freadme/v/1"}}`)
+	assert.NotContains(t, out, `"error"`)
+	assert.Contains(t, out, `first`)
+}
+
+func TestServe_ListResource(t *testing.T) {
+	env := newTestEnv(t)
+	env.runStdin("a", "write", "docs/a", "-a", "tester")
+	env.runStdin("b", "write", "docs/api/b", "-a", "tester")
+	env.runStdin("c", "write", "notes/c", "-a", "tester")
+
+	addr := freeAddr(t)
+	startServe(t, env, addr, "--transport", "streamable-http", "--addr", addr)
+	call := mcpSession(t, addr)
+
+	tests := []struct {
+		name string
+		uri  string
+		want []string
+		not  []string
+	}{
+		{"everything", "llmd://list/", []string{"docs/a", "docs/api/b", "notes/c"}, nil},
+		{"prefix", "llmd://list/docs", []string{"docs/a", "docs/api/b"}, []string{"notes/c"}},
+		{"encoded prefix", "llmd://list/docs%2Fapi", []string{"docs/api/b"}, []string{"docs/a", "notes/c"}},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			out := call(`{"jsonrpc":"2.0","id":2,"method":"resources/read","params":{"uri":"` + tt.uri + `"}}`)
+			assert.Contains(t, out, `"mimeType":"application/json"`)
+			for _, p := range tt.want {
+				assert.Contains(t, out, `\"path\":\"`+p+`\"`)
+			}
+			for _, p := range tt.not {
+				assert.NotContains(t, out, `\"path\":\"`+p+`\"`)
+			}
+		})
+	}
+}
+
+func TestServe_Tools(t *testing.T) {
+	t.Run("allow list", func(t *testing.T) {
+		env := newTestEnv(t)
+		addr := freeAddr(t)
+		startServe(t, env, addr, "--transport", "streamable-http", "--addr", addr,
+			"--tools", "llmd_read,llmd_search", "--tools", "llmd_list")
+		call := mcpSession(t, addr)
+
+		out := call(`{"jsonrpc":"2.0","id":2,"method":"tools/list"}`)
+		for _, name := range []string{"llmd_read", "llmd_search", "llmd_list"} {
+			assert.Contains(t, out, `"name":"`+name+`"`)
+		}
+		assert.NotContains(t, out, `"name":"llmd_write"`)
+		assert.NotContains(t, out, `"name":"llmd_delete"`)
+
+		out = call(`{"jsonrpc":"2.0","id":3,"method":"tools/call","params":{"name":"llmd_delete","arguments":{"paths":["docs/a"]}}}`)
+		assert.Contains(t, out, "not found")
+	})
+
+	t.Run("unknown tool", func(t *testing.T) {
+		env := newTestEnv(t)
+		out, err := env.runErr("serve", "--tools", "llmd_read,llmd_nuke")
+		assert.Error(t, err)
+		assert.Contains(t, out, `unknown tool "llmd_nuke"`)
+	})
+
+	t.Run("http conflict", func(t *testing.T) {
+		env := newTestEnv(t)
+		_, err := env.runErr("serve", "--http", freeAddr(t), "--tools", "llmd_read")
+		assert.Error(t, err)
+	})
+}
+
+func TestServe_WriteGuards(t *testing.T) {
+	write := func(id, content string) string {
+		return `{"jsonrpc":"2.0","id":` + id + `,"method":"tools/call","params":{"name":"llmd_write","arguments":{"path":"docs/a","content":"` + content + `","author":"tester"}}}`
+	}
+
+	t.Run("max write", func(t *testing.T) {
+		env := newTestEnv(t)
+		env.run("config", "--local", "mcp.max_write", "8")
+		addr := freeAddr(t)
+		startServe(t, env, addr, "--transport", "streamable-http", "--addr", addr)
+		call := mcpSession(t, addr)
+
+		out := call(write("2", "small"))
+		assert.NotContains(t, out, `"isError":true`)
+
+		out = call(write("3", "far too large"))
+		assert.Contains(t, out, `"isError":true`)
+		assert.Contains(t, out, "over the 8 byte limit (mcp.max_write)")
+		env.equals(env.run("cat", "docs/a"), "small")
+	})
+
+	t.Run("writes per minute", func(t *testing.T) {
+		env := newTestEnv(t)
+		env.run("config", "--local", "mcp.writes_per_minute", "2")
+		addr := freeAddr(t)
+		startServe(t, env, addr, "--transport", "streamable-http", "--addr", addr)
+		call := mcpSession(t, addr)
+
+		assert.NotContains(t, call(write("2", "one")), `"isError":true`)
+		assert.NotContains(t, call(write("3", "two")), `"isError":true`)
+		out := call(write("4", "three"))
+		assert.Contains(t, out, "rate limit of 2 writes per minute reached")
+		assert.Contains(t, out, "retry in")
+
+		// The limit is per session; a new client starts afresh
+		other := mcpSession(t, addr)
+		assert.NotContains(t, other(write("2", "four")), `"isError":true`)
+	})
+}
+
+func TestServe_ReadExpand(t *testing.T) {
+	env := newTestEnv(t)
+	env.runStdin("MIT licence\n", "write", "shared/licence")
+	env.runStdin("# Readme\n{{include:shared/licence}}\n", "write", "docs/readme")
+	addr := freeAddr(t)
+	startServe(t, env, addr, "--transport", "streamable-http", "--addr", addr)
+	call := mcpSession(t, addr)
+
+	read := func(id, expand string) string {
+		return call(`{"jsonrpc":"2.0","id":` + id + `,"method":"tools/call","params":{"name":"llmd_read","arguments":{"paths":["docs/readme"],"expand":` + expand + `}}}`)
+	}
+
+	out := read("2", "false")
+	assert.Contains(t, out, "{{include:shared/licence}}")
+
+	out = read("3", "true")
+	assert.NotContains(t, out, `"isError":true`)
+	assert.NotContains(t, out, "{{include:")
+	assert.Contains(t, out, "MIT licence")
+}
+
+func TestServe_EditDryRun(t *testing.T) {
+	env := newTestEnv(t)
+	env.runStdin("# Notes\none\ntwo\nthree\n", "write", "docs/notes")
+	addr := freeAddr(t)
+	startServe(t, env, addr, "--transport", "streamable-http", "--addr", addr)
+	call := mcpSession(t, addr)
+
+	edit := func(id, args string) string {
+		return call(`{"jsonrpc":"2.0","id":` + id + `,"method":"tools/call","params":{"name":"llmd_edit","arguments":{"path":"docs/notes","author":"tester",` + args + `}}}`)
+	}
+
+	out := edit("2", `"lines":"2:3","new":"ONE\nTWO","dry_run":true`)
+	assert.NotContains(t, out, `"isError":true`)
+	assert.Contains(t, out, `\"dry_run\": true`)
+	assert.Contains(t, out, `- one`)
+	assert.Contains(t, out, `+ ONE`)
+	assert.Equal(t, "# Notes\none\ntwo\nthree\n", env.run("cat", "docs/notes"))
+
+	out = call(`{"jsonrpc":"2.0","id":3,"method":"tools/call","params":{"name":"llmd_append","arguments":{"path":"docs/notes","author":"tester","content":"four","dry_run":true}}}`)
+	assert.NotContains(t, out, `"isError":true`)
+	assert.Contains(t, out, `+ four`)
+	assert.Equal(t, "# Notes\none\ntwo\nthree\n", env.run("cat", "docs/notes"))
+
+	// Like the CLI, search/replace has no preview
+	out = edit("4", `"old":"one","new":"ONE","dry_run":true`)
+	assert.Contains(t, out, "dry_run requires lines or section")
+
+	out = edit("5", `"old":"one","lines":"2"`)
+	assert.Contains(t, out, "give exactly one of old, lines or section")
+
+	out = edit("6", `"lines":"2:3","new":"ONE\nTWO"`)
+	assert.NotContains(t, out, `"isError":true`)
+	assert.Equal(t, "# Notes\nONE\nTWO\nthree\n", env.run("cat", "docs/notes"))
+}
+
+func TestServe_Redact(t *testing.T) {
+	env := newTestEnv(t)
+	env.runStdin("Contact ops@example.com\n", "write", "contact")
+	env.runStdin("Contact ops@example.com or dev@example.com\n", "write", "contact")
+	env.run("config", "--local", "redact.mcp", "true")
+	addr := freeAddr(t)
+	startServe(t, env, addr, "--transport", "streamable-http", "--addr", addr)
+	call := mcpSession(t, addr)
+
+	out := call(`{"jsonrpc":"2.0","id":2,"method":"tools/call","params":{"name":"llmd_read","arguments":{"paths":["contact"]}}}`)
+	assert.NotContains(t, out, "ops@example.com")
+	assert.Contains(t, out, "[REDACTED]")
+
+	out = call(`{"jsonrpc":"2.0","id":3,"method":"resources/read","params":{"uri":"llmd://documents/contact"}}`)
+	assert.NotContains(t, out, "ops@example.com")
+	assert.Contains(t, out, "[REDACTED]")
+
+	out = call(`{"jsonrpc":"2.0","id":4,"method":"tools/call","params":{"name":"llmd_read_matching","arguments":{"path":"contact","pattern":"example"}}}`)
+	assert.NotContains(t, out, "ops@example.com")
+	assert.Contains(t, out, `\"matches\": 0`)
+
+	out = call(`{"jsonrpc":"2.0","id":5,"method":"tools/call","params":{"name":"llmd_grep","arguments":{"pattern":"Contact"}}}`)
+	assert.NotContains(t, out, "example.com")
+	assert.Contains(t, out, "[REDACTED]")
+
+	// A pattern matching only the secret finds nothing
+	out = call(`{"jsonrpc":"2.0","id":6,"method":"tools/call","params":{"name":"llmd_grep","arguments":{"pattern":"ops@"}}}`)
+	assert.NotContains(t, out, "example.com")
+	assert.Contains(t, out, `"text":"[]"`)
+
+	out = call(`{"jsonrpc":"2.0","id":7,"method":"tools/call","params":{"name":"llmd_search","arguments":{"query":"Contact"}}}`)
+	assert.NotContains(t, out, "example.com")
+	assert.Contains(t, out, "[REDACTED]")
+
+	out = call(`{"jsonrpc":"2.0","id":8,"method":"tools/call","params":{"name":"llmd_diff","arguments":{"path":"contact"}}}`)
+	assert.NotContains(t, out, "example.com")
+	assert.Contains(t, out, "[REDACTED]")
+}
+
+func TestServe_Move(t *testing.T) {
+	for _, tc := range mvCases {
+		t.Run(tc.name, func(t *testing.T) {
+			env := newTestEnv(t)
+			for _, p := range []string{"a", "c", "d"} {
+				env.runStdin("content", "write", p)
+			}
+			addr := freeAddr(t)
+			startServe(t, env, addr, "--transport", "streamable-http", "--addr", addr)
+			call := mcpSession(t, addr)
+
+			sources, err := json.Marshal(tc.sources)
+			require.NoError(t, err)
+			out := call(`{"jsonrpc":"2.0","id":2,"method":"tools/call","params":{"name":"llmd_move","arguments":{"sources":` +
+				string(sources) + `,"dest":"` + tc.dest + `","author":"tester"}}}`)
+			assert.NotContains(t, out, `"isError":true`)
+			checkMoved(t, env, tc.want, tc.gone)
+		})
+	}
+}
+
+func TestServe_Pprof(t *testing.T) {
+	env := newTestEnv(t)
+	addr, pprofAddr := freeAddr(t), freeAddr(t)
+	startServe(t, env, addr, "--http", addr, "--pprof", pprofAddr)
+
+	resp, err := http.Get("http://" + pprofAddr + "/debug/pprof/")
+	require.NoError(t, err)
+	resp.Body.Close()
+	assert.Equal(t, http.StatusOK, resp.StatusCode)
+
+	// Profiling stays off the API's address
+	resp, err = http.Get("http://" + addr + "/debug/pprof/")
+	require.NoError(t, err)
+	resp.Body.Close()
+	assert.Equal(t, http.StatusNotFound, resp.StatusCode)
+}
+
+func TestServe_HTTPLocalhost(t *testing.T) {
+	env := newTestEnv(t)
+	addr := freeAddr(t)
+	_, port, err := net.SplitHostPort(addr)
+	require.NoError(t, err)
+	// A bare port listens on localhost
+	startServe(t, env, addr, "--http", ":"+port)
+
+	resp, err := http.Get("http://" + addr + "/documents")
+	require.NoError(t, err)
+	resp.Body.Close()
+	assert.Equal(t, http.StatusOK, resp.StatusCode)
+	assertLoopbackOnly(t, port)
+}
+
+func TestServe_MCPLocalhost(t *testing.T) {
+	env := newTestEnv(t)
+	addr := freeAddr(t)
+	_, port, err := net.SplitHostPort(addr)
+	require.NoError(t, err)
+	// A bare port listens on localhost
+	startServe(t, env, addr, "--transport", "streamable-http", "--addr", ":"+port)
+
+	call := mcpSession(t, addr)
+	assert.Contains(t, call(`{"jsonrpc":"2.0","id":2,"method":"tools/list"}`), "llmd_read")
+	assertLoopbackOnly(t, port)
+}
+
+// assertLoopbackOnly checks that a server on port is not listening on the
+// host's other interfaces, by claiming the port on one of them.
+func assertLoopbackOnly(t *testing.T, port string) {
+	t.Helper()
+	addrs, err := net.InterfaceAddrs()
+	require.NoError(t, err)
+	for _, a := range addrs {
+		ipnet, ok := a.(*net.IPNet)
+		if !ok || ipnet.IP.IsLoopback() || ipnet.IP.To4() == nil {
+			continue
+		}
+		ln, err := net.Listen("tcp", net.JoinHostPort(ipnet.IP.String(), port))
+		require.NoError(t, err, "port %s is taken on %s", port, ipnet.IP)
+		require.NoError(t, ln.Close())
+		return
+	}
+	t.Skip("no non-loopback IPv4 interface to check")
+}
+
+func TestServe_Schema(t *testing.T) {
+	env := newTestEnv(t)
+	addr := freeAddr(t)
+	startServe(t, env, addr, "--transport", "streamable-http", "--addr", addr)
+	call := mcpSession(t, addr)
+
+	out := call(`{"jsonrpc":"2.0","id":2,"method":"tools/call","params":{"name":"llmd_schema","arguments":{"type":"document"}}}`)
+	assert.NotContains(t, out, `"isError":true`)
+	assert.Contains(t, out, `\"title\": \"document\"`)
+	assert.Contains(t, out, `\"content_type\": {`)
+	assert.Contains(t, out, `\"required\": [`)
+
+	out = call(`{"jsonrpc":"2.0","id":3,"method":"tools/call","params":{"name":"llmd_schema","arguments":{}}}`)
+	for _, name := range []string{"document", "history", "link", "matching", "meta", "page", "tagged"} {
+		assert.Contains(t, out, `\"title\": \"`+name+`\"`)
+	}
+
+	out = call(`{"jsonrpc":"2.0","id":4,"method":"tools/call","params":{"name":"llmd_schema","arguments":{"type":"widget"}}}`)
+	assert.Contains(t, out, `"isError":true`)
+	assert.Contains(t, out, `unknown result type`)
+}
+
+func TestServe_Paging(t *testing.T) {
+	env := newTestEnv(t)
+	for _, p := range []string{"docs/a", "docs/b", "docs/c"} {
+		env.runStdin("deploy notes for "+p, "write", p)
+	}
+	addr := freeAddr(t)
+	startServe(t, env, addr, "--transport", "streamable-http", "--addr", addr)
+	call := mcpSession(t, addr)
+
+	out := call(`{"jsonrpc":"2.0","id":2,"method":"tools/call","params":{"name":"llmd_list","arguments":{"prefix":"docs/","limit":2}}}`)
+	assert.Contains(t, out, `\"path\": \"docs/a\"`)
+	assert.Contains(t, out, `\"path\": \"docs/b\"`)
+	assert.NotContains(t, out, `\"path\": \"docs/c\"`)
+	assert.Contains(t, out, `\"total\": 3`)
+	assert.Contains(t, out, `\"has_more\": true`)
+
+	out = call(`{"jsonrpc":"2.0","id":3,"method":"tools/call","params":{"name":"llmd_list","arguments":{"prefix":"docs/","limit":2,"offset":2}}}`)
+	assert.Contains(t, out, `\"path\": \"docs/c\"`)
+	assert.NotContains(t, out, `\"path\": \"docs/a\"`)
+	assert.Contains(t, out, `\"offset\": 2`)
+	assert.Contains(t, out, `\"has_more\": false`)
+
+	out = call(`{"jsonrpc":"2.0","id":4,"method":"tools/call","params":{"name":"llmd_search","arguments":{"query":"deploy","limit":1}}}`)
+	assert.Contains(t, out, `\"total\": 3`)
+	assert.Contains(t, out, `\"has_more\": true`)
+	assert.Equal(t, 1, strings.Count(out, `\"path\":`))
+
+	// Without limit or offset the result is still a bare array
+	out = call(`{"jsonrpc":"2.0","id":5,"method":"tools/call","params":{"name":"llmd_search","arguments":{"query":"deploy"}}}`)
+	assert.NotContains(t, out, `\"has_more\"`)
+	assert.Equal(t, 3, strings.Count(out, `\"path\":`))
+}
+
+func TestServe_ReadMatching(t *testing.T) {
+	env := newTestEnv(t)
+	env.runStdin("# API\none\ntwo\nTODO first\nthree\nfour\nfive\nsix\nTODO second\nseven\n", "write", "docs/api")
+	addr := freeAddr(t)
+	startServe(t, env, addr, "--transport", "streamable-http", "--addr", addr)
+	call := mcpSession(t, addr)
+
+	out := call(`{"jsonrpc":"2.0","id":2,"method":"tools/call","params":{"name":"llmd_read_matching","arguments":{"path":"docs/api","pattern":"todo","ignore_case":true,"context":1}}}`)
+	assert.NotContains(t, out, `"isError":true`)
+	assert.Contains(t, out, `\"lines\": 10`)
+	assert.Contains(t, out, `\"matches\": 2`)
+	assert.Contains(t, out, `\"start\": 3`)
+	assert.Contains(t, out, `\"start\": 8`)
+	assert.Contains(t, out, `\"TODO first\"`)
+	assert.NotContains(t, out, `\"one\"`)
+	assert.NotContains(t, out, `\"five\"`)
+
+	out = call(`{"jsonrpc":"2.0","id":3,"method":"tools/call","params":{"name":"llmd_read_matching","arguments":{"path":"docs/missing","pattern":"x"}}}`)
+	assert.Contains(t, out, `"isError":true`)
+
+	out = call(`{"jsonrpc":"2.0","id":4,"method":"tools/call","params":{"name":"llmd_read_matching","arguments":{"path":"docs/api","pattern":"("}}}`)
+	assert.Contains(t, out, "invalid regex")
+}
+
+func TestServe_Recent(t *testing.T) {
+	env := newTestEnv(t)
+	env.runStdin("a", "write", "docs/a", "--author", "alice", "-m", "add a")
+	env.runStdin("b", "write", "docs/b", "--author", "bob", "-m", "add b")
+	addr := freeAddr(t)
+	startServe(t, env, addr, "--transport", "streamable-http", "--addr", addr)
+	call := mcpSession(t, addr)
+
+	out := call(`{"jsonrpc":"2.0","id":2,"method":"tools/call","params":{"name":"llmd_recent","arguments":{}}}`)
+	assert.NotContains(t, out, `"isError":true`)
+	assert.Contains(t, out, `\"path\": \"docs/a\"`)
+	assert.Contains(t, out, `\"author\": \"bob\"`)
+	assert.Contains(t, out, `\"message\": \"add b\"`)
+	assert.Contains(t, out, `\"size\": 1`)
+
+	out = call(`{"jsonrpc":"2.0","id":3,"method":"tools/call","params":{"name":"llmd_recent","arguments":{"limit":1,"prefix":"docs/a"}}}`)
+	assert.Equal(t, 1, strings.Count(out, `\"path\":`))
+	assert.Contains(t, out, `\"path\": \"docs/a\"`)
+
+	out = call(`{"jsonrpc":"2.0","id":4,"method":"tools/call","params":{"name":"llmd_recent","arguments":{"since":"2999-01-01"}}}`)
+	assert.Contains(t, out, `"text":"[]"`)
+
+	out = call(`{"jsonrpc":"2.0","id":5,"method":"tools/call","params":{"name":"llmd_recent","arguments":{"since":"whenever"}}}`)
+	assert.Contains(t, out, `"isError":true`)
+}
+
+func TestServe_RestoreLinks(t *testing.T) {
+	env := newTestEnv(t)
+	env.runStdin("a", "write", "docs/a")
+	env.runStdin("b", "write", "docs/b")
+	env.run("link", "docs/a", "docs/b")
+	addr := freeAddr(t)
+	startServe(t, env, addr, "--transport", "streamable-http", "--addr", addr)
+	call := mcpSession(t, addr)
+
+	out := call(`{"jsonrpc":"2.0","id":2,"method":"tools/call","params":{"name":"llmd_delete","arguments":{"paths":["docs/a"],"author":"mcp"}}}`)
+	assert.NotContains(t, out, `"isError":true`)
+	out = call(`{"jsonrpc":"2.0","id":3,"method":"tools/call","params":{"name":"llmd_link","arguments":{"list":true,"from":"docs/b"}}}`)
+	assert.NotContains(t, out, `docs/a`)
+
+	out = call(`{"jsonrpc":"2.0","id":4,"method":"tools/call","params":{"name":"llmd_restore","arguments":{"paths":["docs/a"],"author":"mcp"}}}`)
+	assert.NotContains(t, out, `"isError":true`)
+	out = call(`{"jsonrpc":"2.0","id":5,"method":"tools/call","params":{"name":"llmd_link","arguments":{"list":true,"from":"docs/b"}}}`)
+	assert.Contains(t, out, `\"from_path\": \"docs/a\"`)
+}
@@ -0,0 +1,76 @@
+package cmd
+
+import (
+	"strings"
+	"testing"
+)
+
+func TestSplit(t *testing.T) {
+	doc := "# Guide\nintro\n\n## Install\nsteps\n\n## Usage\nrun it\n"
+
+	t.Run("split at level 2", func(t *testing.T) {
+		env := newTestEnv(t)
+		env.runStdin(doc, "write", "docs/guide")
+
+		out := env.run("split", "docs/guide")
+		env.equals(out, "docs/guide/section-1\ndocs/guide/section-2\ndocs/guide/section-3")
+
+		env.equals(env.run("cat", "docs/guide/section-2"), "## Install\nsteps")
+		env.contains(env.run("link", "--list", "docs/guide/section-3"), "docs/guide")
+		env.contains(env.run("cat", "docs/guide"), "## Usage")
+	})
+
+	t.Run("custom prefix and delete", func(t *testing.T) {
+		env := newTestEnv(t)
+		env.runStdin(doc, "write", "docs/guide")
+
+		env.run("split", "docs/guide", "--to", "parts/guide", "--delete")
+		env.contains(env.run("cat", "parts/guide/section-3"), "run it")
+
+		if _, err := env.runErr("cat", "docs/guide"); err == nil {
+			t.Error("cat docs/guide after split --delete = nil, want error")
+		}
+
+		// The sections stay chained to each other in order
+		out := env.run("link", "--list", "parts/guide/section-2")
+		env.contains(out, "parts/guide/section-1")
+		env.contains(out, "parts/guide/section-3")
+		if strings.Contains(out, "docs/guide\n") || strings.Contains(out, "part-of") {
+			t.Errorf("link to deleted original still listed:\n%s", out)
+		}
+
+		// Restoring the original brings its part-of links back
+		env.run("restore", "docs/guide")
+		env.contains(env.run("link", "--list", "parts/guide/section-1"), "docs/guide")
+	})
+
+	t.Run("dry run", func(t *testing.T) {
+		env := newTestEnv(t)
+		env.runStdin(doc, "write", "docs/guide")
+
+		out := env.run("split", "docs/guide", "-n")
+		env.contains(out, "Would write docs/guide/section-1")
+		if _, err := env.runErr("cat", "docs/guide/section-1"); err == nil {
+			t.Error("dry run wrote a section")
+		}
+	})
+
+	t.Run("JSON output", func(t *testing.T) {
+		env := newTestEnv(t)
+		env.runStdin(doc, "write", "docs/guide")
+
+		out := env.run("split", "docs/guide", "-o", "json")
+		env.contains(out, `"path":"docs/guide/section-2","heading":"Install"`)
+	})
+
+	t.Run("no headings", func(t *testing.T) {
+		env := newTestEnv(t)
+		env.runStdin("just text", "write", "docs/plain")
+
+		out, err := env.runErr("split", "docs/plain")
+		if err == nil {
+			t.Fatal("split without headings = nil, want error")
+		}
+		env.contains(out, "no level 2 headings")
+	})
+}
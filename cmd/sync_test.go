@@ -1,11 +1,16 @@
 package cmd
 
 import (
+	"bytes"
 	"errors"
 	"io/fs"
 	"os"
+	"os/exec"
 	"path/filepath"
+	"runtime"
+	"strings"
 	"testing"
+	"time"
 )
 
 func TestSync(t *testing.T) {
@@ -86,3 +91,183 @@ func TestSync_DryRun(t *testing.T) {
 	}
 	env.contains(content, "llmd Guide")
 }
+
+func TestSync_Prune(t *testing.T) {
+	setup := func(t *testing.T) *testEnv {
+		env := newTestEnv(t)
+		env.run("config", "sync.files", "true")
+		env.runStdin("keep", "write", "docs/keep")
+		env.runStdin("gone", "write", "docs/gone")
+
+		mirror := filepath.Join(env.dir, ".llmd", "docs", "gone.md")
+		if err := os.Remove(mirror); err != nil {
+			t.Fatalf("failed to remove mirror file: %v", err)
+		}
+		return env
+	}
+
+	t.Run("without prune warns and keeps", func(t *testing.T) {
+		env := setup(t)
+
+		out := env.run("sync")
+		env.contains(out, "Missing: docs/gone")
+		env.contains(out, "--prune")
+
+		env.equals(env.run("cat", "docs/gone"), "gone")
+	})
+
+	t.Run("dry run keeps", func(t *testing.T) {
+		env := setup(t)
+
+		out := env.run("sync", "--prune", "-n")
+		env.contains(out, "Would delete: docs/gone")
+
+		env.equals(env.run("cat", "docs/gone"), "gone")
+	})
+
+	t.Run("prune soft-deletes", func(t *testing.T) {
+		env := setup(t)
+
+		out := env.run("sync", "--prune")
+		env.contains(out, "Deleted: docs/gone")
+
+		_, err := env.runErr("cat", "docs/gone")
+		if err == nil {
+			t.Error("cat of pruned document succeeded, want error")
+		}
+		env.equals(env.run("cat", "docs/keep"), "keep")
+
+		env.run("restore", "docs/gone")
+		env.equals(env.run("cat", "docs/gone"), "gone")
+	})
+
+	t.Run("prune without mirror fails and keeps", func(t *testing.T) {
+		env := newTestEnv(t)
+		env.runStdin("keep", "write", "docs/keep")
+		env.runStdin("other", "write", "docs/other")
+
+		// A lone file a user dropped in, not a mirror of the store
+		note := filepath.Join(env.dir, ".llmd", "notes", "todo.md")
+		if err := os.MkdirAll(filepath.Dir(note), 0755); err != nil {
+			t.Fatalf("failed to create notes directory: %v", err)
+		}
+		if err := os.WriteFile(note, []byte("todo"), 0644); err != nil {
+			t.Fatalf("failed to write file: %v", err)
+		}
+
+		out, err := env.runErr("sync", "--prune")
+		if err == nil {
+			t.Fatalf("sync --prune without sync.files succeeded, want error\n%s", out)
+		}
+		env.contains(out, "sync.files")
+
+		env.equals(env.run("cat", "docs/keep"), "keep")
+		env.equals(env.run("cat", "docs/other"), "other")
+
+		out = env.run("sync")
+		env.contains(out, "Added: notes/todo")
+		if strings.Contains(out, "Missing:") {
+			t.Errorf("sync without mirror reported missing documents\n%s", out)
+		}
+	})
+}
+
+func TestSync_Conflict(t *testing.T) {
+	setup := func(t *testing.T) (*testEnv, string) {
+		env := newTestEnv(t)
+		env.run("config", "sync.files", "true")
+		env.runStdin("base", "write", "docs/readme")
+
+		// A database write the mirror never sees
+		env.run("config", "sync.files", "false")
+		env.runStdin("db edit", "write", "docs/readme")
+		env.run("config", "sync.files", "true")
+
+		mirror := filepath.Join(env.dir, ".llmd", "docs", "readme.md")
+		if err := os.WriteFile(mirror, []byte("disk edit"), 0644); err != nil {
+			t.Fatalf("failed to modify mirror file: %v", err)
+		}
+		return env, mirror
+	}
+
+	t.Run("safe skips", func(t *testing.T) {
+		env, _ := setup(t)
+
+		out := env.run("sync", "--safe")
+		env.contains(out, "Conflict: docs/readme")
+
+		env.equals(env.run("cat", "docs/readme"), "db edit")
+	})
+
+	t.Run("default imports and reports", func(t *testing.T) {
+		env, _ := setup(t)
+
+		out := env.run("sync")
+		env.contains(out, "conflict")
+
+		env.equals(env.run("cat", "docs/readme"), "disk edit")
+	})
+
+	t.Run("disk-only change is not a conflict", func(t *testing.T) {
+		env := newTestEnv(t)
+		env.run("config", "sync.files", "true")
+		env.runStdin("base", "write", "docs/readme")
+
+		mirror := filepath.Join(env.dir, ".llmd", "docs", "readme.md")
+		_ = os.WriteFile(mirror, []byte("disk edit"), 0644)
+
+		out := env.run("sync", "--safe")
+		env.contains(out, "Updated: docs/readme")
+		env.equals(env.run("cat", "docs/readme"), "disk edit")
+
+		// The import becomes the new base, so a second edit is clean too
+		_ = os.WriteFile(mirror, []byte("second edit"), 0644)
+		out = env.run("sync", "--safe")
+		env.contains(out, "Updated: docs/readme")
+	})
+}
+
+func TestSync_Watch(t *testing.T) {
+	if runtime.GOOS == "windows" {
+		t.Skip("interrupt signals are not supported on windows")
+	}
+	env := newTestEnv(t)
+	env.run("config", "sync.files", "true")
+	env.runStdin("original", "write", "docs/readme")
+
+	var out bytes.Buffer
+	watch := exec.Command(env.binary, "sync", "--watch", "--debounce", "50ms")
+	watch.Dir = env.dir
+	watch.Stdout = &out
+	watch.Stderr = &out
+	if err := watch.Start(); err != nil {
+		t.Fatalf("starting watch: %v", err)
+	}
+
+	// Give the watcher time to register before editing
+	time.Sleep(300 * time.Millisecond)
+	mirror := filepath.Join(env.dir, ".llmd", "docs", "readme.md")
+	if err := os.WriteFile(mirror, []byte("edited while watching"), 0644); err != nil {
+		t.Fatalf("failed to modify mirror file: %v", err)
+	}
+
+	deadline := time.Now().Add(5 * time.Second)
+	for {
+		if got, err := env.runErr("cat", "docs/readme"); err == nil && strings.TrimSpace(got) == "edited while watching" {
+			break
+		}
+		if time.Now().After(deadline) {
+			_ = watch.Process.Kill()
+			t.Fatalf("change not synced within deadline\noutput: %s", out.String())
+		}
+		time.Sleep(50 * time.Millisecond)
+	}
+
+	if err := watch.Process.Signal(os.Interrupt); err != nil {
+		t.Fatalf("interrupting watch: %v", err)
+	}
+	if err := watch.Wait(); err != nil {
+		t.Errorf("watch did not exit cleanly: %v\noutput: %s", err, out.String())
+	}
+	env.contains(out.String(), "Updated: docs/readme")
+}
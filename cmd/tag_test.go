@@ -60,6 +60,79 @@ func TestTag_Remove(t *testing.T) {
 	env.contains(out, "wip")
 }
 
+func TestTag_AddMany(t *testing.T) {
+	t.Run("glob", func(t *testing.T) {
+		env := newTestEnv(t)
+		env.runStdin("content", "write", "docs/api/auth")
+		env.runStdin("content", "write", "docs/api/v1/users")
+		env.runStdin("content", "write", "docs/guide")
+
+		out := env.run("tag", "add", "--glob", "docs/api/**", "important")
+		env.contains(out, "Tagged 2 documents")
+
+		out = env.run("ls", "-R", "--tag", "important")
+		env.contains(out, "docs/api/auth")
+		env.contains(out, "docs/api/v1/users")
+		if strings.Contains(out, "docs/guide") {
+			t.Error("Tag(--glob docs/api/**) tagged docs/guide, want excluded")
+		}
+	})
+
+	t.Run("from search", func(t *testing.T) {
+		env := newTestEnv(t)
+		env.runStdin("this API is deprecated", "write", "docs/old")
+		env.runStdin("current API", "write", "docs/new")
+
+		out := env.run("tag", "add", "--from-search", "deprecated", "obsolete", "-o", "json")
+		env.contains(out, `"count":1`)
+		env.contains(out, `"docs/old"`)
+
+		out = env.run("tag", "ls", "docs/new")
+		if strings.Contains(out, "obsolete") {
+			t.Error("Tag(--from-search) tagged docs/new, want excluded")
+		}
+	})
+
+	t.Run("no matches", func(t *testing.T) {
+		env := newTestEnv(t)
+		env.runStdin("content", "write", "docs/a")
+
+		out := env.run("tag", "add", "--glob", "notes/**", "x")
+		env.contains(out, "Tagged 0 documents")
+	})
+
+	t.Run("path with glob is an error", func(t *testing.T) {
+		env := newTestEnv(t)
+		env.runStdin("content", "write", "docs/a")
+
+		if _, err := env.runErr("tag", "add", "--glob", "docs/*", "docs/a", "x"); err == nil {
+			t.Error("Tag(--glob with path) = nil, want error")
+		}
+	})
+}
+
+func TestTag_Rename(t *testing.T) {
+	env := newTestEnv(t)
+	env.runStdin("content", "write", "docs/a")
+	env.runStdin("content", "write", "docs/b")
+	env.run("tag", "add", "docs/a", "reveiw")
+	env.run("tag", "add", "docs/b", "reveiw")
+	env.run("tag", "add", "docs/b", "review")
+
+	out := env.run("tag", "rename", "reveiw", "review")
+	env.contains(out, "on 2 documents")
+
+	out = env.run("tag", "ls")
+	env.equals(out, "review")
+
+	out = env.run("tag", "rename", "review", "reviewed", "-o", "json")
+	env.contains(out, `"count":2`)
+
+	if _, err := env.runErr("tag", "rename", "reviewed", "reviewed"); err == nil {
+		t.Error("Tag(rename same) = nil, want error")
+	}
+}
+
 func TestTag_List(t *testing.T) {
 	t.Run("list all", func(t *testing.T) {
 		env := newTestEnv(t)
@@ -93,6 +166,26 @@ func TestTag_List(t *testing.T) {
 	})
 }
 
+func TestTag_ListCount(t *testing.T) {
+	env := newTestEnv(t)
+	for _, p := range []string{"docs/a", "docs/b", "docs/c"} {
+		env.runStdin("content", "write", p)
+		env.run("tag", "add", p, "common")
+	}
+	env.run("tag", "add", "docs/a", "rare")
+	env.run("tag", "add", "docs/b", "beta")
+
+	out := env.run("tag", "ls", "--count")
+	env.equals(out, "common\t3\nbeta\t1\nrare\t1")
+
+	out = env.run("tag", "ls", "--count", "-o", "json")
+	env.equals(out, `[{"tag":"common","count":3},{"tag":"beta","count":1},{"tag":"rare","count":1}]`)
+
+	if _, err := env.runErr("tag", "ls", "--count", "docs/a"); err == nil {
+		t.Error("Tag(ls --count with path) = nil, want error")
+	}
+}
+
 func TestTag_JSONOutput(t *testing.T) {
 	t.Run("ls JSON", func(t *testing.T) {
 		env := newTestEnv(t)
@@ -0,0 +1,61 @@
+package cmd
+
+import (
+	"testing"
+	"time"
+)
+
+func TestTemplate(t *testing.T) {
+	setup := func(t *testing.T) *testEnv {
+		env := newTestEnv(t)
+		env.runStdin("# {{title}}\n\nDate: {{date}}\nOwner: {{owner}}\n", "write", ".templates/meeting")
+		env.runStdin("- [ ] ", "write", ".templates/todo")
+		return env
+	}
+
+	t.Run("ls", func(t *testing.T) {
+		env := setup(t)
+		env.equals(env.run("template", "ls"), "meeting\ntodo")
+	})
+
+	t.Run("ls json", func(t *testing.T) {
+		env := setup(t)
+		env.equals(env.run("template", "ls", "-o", "json"), `{"templates":["meeting","todo"]}`)
+	})
+
+	t.Run("new", func(t *testing.T) {
+		env := setup(t)
+		out := env.run("new", "docs/notes/today", "--template", "meeting")
+		env.contains(out, "Created docs/notes/today from template meeting")
+
+		want := "# today\n\nDate: " + time.Now().Format(time.DateOnly) + "\nOwner: {{owner}}"
+		env.equals(env.run("cat", "docs/notes/today"), want)
+	})
+
+	t.Run("existing document", func(t *testing.T) {
+		env := setup(t)
+		env.runStdin("old", "write", "docs/list")
+
+		out, err := env.runErr("new", "docs/list", "--template", "todo")
+		if err == nil {
+			t.Fatal("new onto existing document = nil, want error")
+		}
+		env.contains(out, "already exists")
+
+		env.run("new", "docs/list", "--template", "todo", "--force")
+		env.equals(env.run("cat", "docs/list"), "- [ ]")
+	})
+
+	t.Run("errors", func(t *testing.T) {
+		env := setup(t)
+		out, err := env.runErr("new", "docs/x", "--template", "missing")
+		if err == nil {
+			t.Fatal("new with missing template = nil, want error")
+		}
+		env.contains(out, `template "missing" not found`)
+
+		if _, err := env.runErr("new", "docs/x"); err == nil {
+			t.Error("new without --template = nil, want error")
+		}
+	})
+}
@@ -0,0 +1,52 @@
+package cmd
+
+import (
+	"testing"
+)
+
+func TestTokens(t *testing.T) {
+	t.Run("single", func(t *testing.T) {
+		env := newTestEnv(t)
+		env.runStdin("Hello, world!", "write", "docs/a")
+
+		env.equals(env.run("tokens", "docs/a"), "6 docs/a")
+		env.equals(env.run("tokens", "--method", "chars", "docs/a"), "4 docs/a")
+	})
+
+	t.Run("total and budget", func(t *testing.T) {
+		env := newTestEnv(t)
+		env.runStdin("Hello, world!", "write", "docs/a")
+		env.runStdin("Hello again", "write", "docs/b")
+
+		out := env.run("tokens", "docs/", "--budget", "10")
+		env.contains(out, "       6 docs/a\n")
+		env.contains(out, "       4 docs/b\n")
+		env.contains(out, "      10 total\n")
+		env.contains(out, "Fits in 10 tokens (0 to spare)")
+
+		out = env.run("tokens", "docs/*", "--budget", "8")
+		env.contains(out, "Exceeds 8 tokens by 2")
+	})
+
+	t.Run("JSON output", func(t *testing.T) {
+		env := newTestEnv(t)
+		env.runStdin("Hello, world!", "write", "docs/a")
+
+		out := env.run("tokens", "docs/a", "--budget", "100", "-o", "json")
+		env.contains(out, `"method":"text"`)
+		env.contains(out, `"documents":[{"path":"docs/a","tokens":6}]`)
+		env.contains(out, `"total":6`)
+		env.contains(out, `"fits":true`)
+	})
+
+	t.Run("unknown method", func(t *testing.T) {
+		env := newTestEnv(t)
+		env.runStdin("x", "write", "docs/a")
+
+		out, err := env.runErr("tokens", "--method", "exact", "docs/a")
+		if err == nil {
+			t.Fatal("Tokens(--method exact) = nil, want error")
+		}
+		env.contains(out, "available: chars, text, words")
+	})
+}
@@ -0,0 +1,66 @@
+package cmd
+
+import "testing"
+
+func TestUndo(t *testing.T) {
+	t.Run("reverts latest change", func(t *testing.T) {
+		env := newTestEnv(t)
+		env.runStdin("version 1", "write", "docs/readme")
+		env.runStdin("version 2", "write", "docs/readme")
+		env.runStdin("version 3", "write", "docs/readme")
+
+		out := env.run("undo", "docs/readme")
+		env.contains(out, "Reverted docs/readme to v2")
+		env.contains(out, "now v4")
+
+		out = env.run("cat", "docs/readme")
+		env.equals(out, "version 2")
+
+		out = env.run("history", "docs/readme")
+		env.contains(out, "Revert to v2")
+	})
+
+	t.Run("undo of undo", func(t *testing.T) {
+		env := newTestEnv(t)
+		env.runStdin("original", "write", "docs/readme")
+		env.runStdin("oops", "write", "docs/readme")
+
+		env.run("undo", "docs/readme")
+		env.run("undo", "docs/readme")
+
+		out := env.run("cat", "docs/readme")
+		env.equals(out, "oops")
+	})
+
+	t.Run("JSON output", func(t *testing.T) {
+		env := newTestEnv(t)
+		env.runStdin("v1", "write", "docs/readme")
+		env.runStdin("v2", "write", "docs/readme")
+
+		out := env.run("undo", "docs/readme", "-o", "json")
+		env.contains(out, `"reverted_to":1`)
+		env.contains(out, `"new_version":3`)
+	})
+}
+
+func TestUndo_Errors(t *testing.T) {
+	t.Run("single version", func(t *testing.T) {
+		env := newTestEnv(t)
+		env.runStdin("only", "write", "docs/readme")
+
+		_, err := env.runErr("undo", "docs/readme")
+		if err == nil {
+			t.Error("Undo(single version) = nil, want error")
+		}
+	})
+
+	t.Run("not found", func(t *testing.T) {
+		env := newTestEnv(t)
+		env.runStdin("content", "write", "docs/other")
+
+		_, err := env.runErr("undo", "docs/missing")
+		if err == nil {
+			t.Error("Undo(missing) = nil, want error")
+		}
+	})
+}
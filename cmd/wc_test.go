@@ -0,0 +1,52 @@
+package cmd
+
+import (
+	"testing"
+)
+
+func TestWc(t *testing.T) {
+	t.Run("single", func(t *testing.T) {
+		env := newTestEnv(t)
+		env.runStdin("# Title\nSome words here\nlast line", "write", "docs/a")
+
+		env.equals(env.run("wc", "docs/a"), "      3       7      33 docs/a")
+		env.equals(env.run("wc", "-l", "docs/a"), "      3 docs/a")
+		env.equals(env.run("wc", "-w", "-c", "docs/a"), "      7      33 docs/a")
+	})
+
+	t.Run("characters not bytes", func(t *testing.T) {
+		env := newTestEnv(t)
+		env.runStdin("café\n", "write", "docs/cafe")
+
+		env.equals(env.run("wc", "-c", "docs/cafe"), "      5 docs/cafe")
+	})
+
+	t.Run("prefix and glob with total", func(t *testing.T) {
+		env := newTestEnv(t)
+		env.runStdin("one two\n", "write", "docs/a")
+		env.runStdin("three\nfour\n", "write", "docs/b")
+		env.runStdin("five\n", "write", "notes/c")
+
+		out := env.run("wc", "docs/", "docs/*", "notes/c")
+		env.equals(out, "      1       2       8 docs/a\n      2       2      11 docs/b\n      1       1       5 notes/c\n      4       5      24 total")
+	})
+
+	t.Run("JSON output", func(t *testing.T) {
+		env := newTestEnv(t)
+		env.runStdin("one two\n", "write", "docs/a")
+		env.runStdin("three\n", "write", "docs/b")
+
+		out := env.run("wc", "docs/a", "-o", "json")
+		env.contains(out, `{"path":"docs/a","lines":1,"words":2,"chars":8}`)
+
+		out = env.run("wc", "docs/", "-o", "json")
+		env.contains(out, `[{"path":"docs/a"`)
+	})
+
+	t.Run("missing", func(t *testing.T) {
+		env := newTestEnv(t)
+		if _, err := env.runErr("wc", "docs/missing"); err == nil {
+			t.Error("Wc(missing) = nil, want error")
+		}
+	})
+}
@@ -126,6 +126,62 @@ func TestWrite_LargeContent(t *testing.T) {
 	env.contains(out, "This is line number")
 }
 
+func TestWrite_ContentLimit(t *testing.T) {
+	env := newTestEnv(t)
+	env.run("config", "--local", "limits.max_content", "10")
+
+	env.runStdin("0123456789", "write", "docs/limit")
+
+	out, err := env.runStdinErr("0123456789x", "write", "docs/limit")
+	if err == nil {
+		t.Fatal("Write(11 bytes, limit 10) = nil, want error")
+	}
+	env.contains(out, "11 bytes exceeds the 10 byte limit by 1")
+}
+
+func TestWrite_Set(t *testing.T) {
+	t.Run("expands variables and builtins", func(t *testing.T) {
+		env := newTestEnv(t)
+		env.runStdin("v{{version}} by {{author}} for {{who}}", "write", "docs/rel",
+			"--set", "version=1.4", "--set", "who=a, b")
+		env.equals(env.run("cat", "docs/rel"), "v1.4 by test for a, b")
+	})
+
+	t.Run("overrides builtins", func(t *testing.T) {
+		env := newTestEnv(t)
+		env.runStdin("{{date}}", "write", "docs/d", "--set", "date=yesterday")
+		env.equals(env.run("cat", "docs/d"), "yesterday")
+	})
+
+	t.Run("no expansion without set", func(t *testing.T) {
+		env := newTestEnv(t)
+		env.runStdin("{{date}} {{x}}", "write", ".templates/t")
+		env.equals(env.run("cat", ".templates/t"), "{{date}} {{x}}")
+	})
+
+	t.Run("unresolved kept unless strict", func(t *testing.T) {
+		env := newTestEnv(t)
+		env.runStdin("{{a}} {{b}}", "write", "docs/s", "--set", "a=1")
+		env.equals(env.run("cat", "docs/s"), "1 {{b}}")
+
+		out, err := env.runStdinErr("{{a}} {{b}} {{c}}", "write", "docs/s", "--set", "a=1", "--strict")
+		if err == nil {
+			t.Fatal("write --strict with unresolved placeholders = nil, want error")
+		}
+		env.contains(out, "unresolved placeholders: b, c")
+		env.equals(env.run("cat", "docs/s"), "1 {{b}}")
+	})
+
+	t.Run("invalid assignment", func(t *testing.T) {
+		env := newTestEnv(t)
+		out, err := env.runStdinErr("x", "write", "docs/s", "--set", "novalue")
+		if err == nil {
+			t.Fatal("write --set novalue = nil, want error")
+		}
+		env.contains(out, "expected key=value")
+	})
+}
+
 // Advanced write tests for LLM use cases
 
 func TestWrite_LLM_VeryLargeDocument(t *testing.T) {
@@ -423,3 +479,38 @@ func TestWrite_LLM_CodeHeavyDocument(t *testing.T) {
 	grepOut := env.run("grep", "pipefail", "docs/code")
 	env.contains(grepOut, "docs/code")
 }
+
+func TestWrite_Author(t *testing.T) {
+	env := newTestEnv(t)
+
+	out, err := env.runStdinErr("content", "write", "-a", "   ", "docs/blank")
+	if err == nil {
+		t.Error("Write(-a blank) = nil, want error")
+	}
+	env.contains(out, "invalid author")
+
+	// Named writes are unaffected by author.required
+	env.run("config", "author.required", "true", "--local")
+	env.runStdin("content", "write", "-a", "alice", "docs/named")
+	env.contains(env.run("history", "docs/named"), "alice")
+}
+
+func TestWrite_ContentType(t *testing.T) {
+	env := newTestEnv(t)
+
+	env.runStdin(`{"a": 1}`, "write", "config/ci", "--content-type", "application/json")
+	env.contains(env.run("cat", "config/ci", "-o", "json"), `"content_type":"application/json"`)
+
+	// A later write without the flag keeps the type
+	env.runStdin(`{"a": 2}`, "write", "config/ci")
+	env.contains(env.run("cat", "config/ci", "-o", "json"), `"content_type":"application/json"`)
+
+	env.runStdin("# Notes", "write", "docs/notes")
+	env.contains(env.run("cat", "docs/notes", "-o", "json"), `"content_type":"text/markdown"`)
+
+	out, err := env.runStdinErr("x", "write", "docs/bad", "--content-type", "json")
+	if err == nil {
+		t.Error("write --content-type json = nil, want error")
+	}
+	env.contains(out, "invalid content type")
+}
@@ -29,6 +29,7 @@ func newConfigCmd() *cobra.Command {
   llmd config                 # show config
   llmd config sync.files      # show sync.files value
   llmd config sync.files true # set sync.files
+  llmd config author "Jane Doe <jane@example.com>"  # set author name and email
 
 Configuration locations:
   Global: ~/.llmd/config.yaml
@@ -31,6 +31,9 @@ func newDBCmd() *cobra.Command {
   llmd db notes --local      # mark notes database as local
   llmd db notes --share      # mark as shared
   llmd db --dir /path        # list databases in external directory
+  llmd db verify             # check database integrity
+  llmd db reindex            # rebuild the full-text search index
+  llmd db migrate            # upgrade the database schema
 
 Local databases are not committed. Shared databases are.
 If no name is given with --local or --share, operates on the default database.`,
@@ -40,6 +43,9 @@ If no name is given with --local or --share, operates on the default database.`,
 	c.Flags().BoolP(extension.FlagLocal, "l", false, "Mark database as local")
 	c.Flags().BoolP(extension.FlagShare, "s", false, "Mark database as shared")
 	c.MarkFlagsMutuallyExclusive(extension.FlagLocal, extension.FlagShare)
+	c.AddCommand(newDBVerifyCmd())
+	c.AddCommand(newDBReindexCmd())
+	c.AddCommand(newDBMigrateCmd())
 	return c
 }
 
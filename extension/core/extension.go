@@ -1,5 +1,5 @@
 // Package core provides the core extension for llmd.
-// It registers commands: init, config, serve, guide, vacuum, llm, db.
+// It registers commands: init, config, serve, guide, vacuum, llm, db, version, whoami.
 package core
 
 import (
@@ -35,6 +35,7 @@ func (e *Extension) Commands() []*cobra.Command {
 		newLlmCmd(),
 		newDBCmd(),
 		newVersionCmd(),
+		newWhoamiCmd(),
 	}
 }
 
@@ -49,6 +50,7 @@ func (e *Extension) MCPTools() []extension.MCPTool {
 // vacuum: Must work with --dry-run without requiring a store.
 // db: Manages gitignore, doesn't need database connection.
 // version: Displays build info, doesn't need database connection.
+// whoami: Reads only the config and flags.
 func (e *Extension) NoStoreCommands() []string {
-	return []string{"serve", "vacuum", "db", "version"}
+	return []string{"serve", "vacuum", "db", "version", "whoami"}
 }
@@ -6,7 +6,9 @@
 // Design: Guides are embedded in the binary via the guide package, ensuring
 // documentation is always available without external files. Terminal output
 // gets glamour rendering for readability; pipe/redirect gets raw markdown
-// for machine consumption and LLM context loading.
+// for machine consumption and LLM context loading. --list and --search
+// print plain tables of contents and grep-style matches so topics can be
+// discovered before they are read.
 
 package core
 
@@ -17,22 +19,37 @@ import (
 
 	"github.com/charmbracelet/glamour"
 	"github.com/jpl-au/llmd/cmd"
+	"github.com/jpl-au/llmd/extension"
 	"github.com/jpl-au/llmd/guide"
 	"github.com/spf13/cobra"
 	"golang.org/x/term"
 )
 
 func newGuideCmd() *cobra.Command {
-	return &cobra.Command{
+	c := &cobra.Command{
 		Use:   "guide [command]",
 		Short: "Show the llmd usage guide",
 		Long: `Outputs the llmd guide for LLMs and humans.
 
-  llmd guide           # main guide
-  llmd guide init      # detailed init guide
-  llmd guide write     # detailed write guide`,
+  llmd guide                 # main guide
+  llmd guide init            # detailed init guide
+  llmd guide write           # detailed write guide
+  llmd guide --list          # list topics with a summary of each
+  llmd guide --search sync   # find topics mentioning "sync"`,
 		Args: cobra.MaximumNArgs(1),
-		RunE: func(_ *cobra.Command, args []string) error {
+		RunE: func(c *cobra.Command, args []string) error {
+			list, _ := c.Flags().GetBool(extension.FlagList)
+			search, _ := c.Flags().GetString(extension.FlagSearch)
+			if (list || search != "") && len(args) > 0 {
+				return cmd.PrintJSONError(fmt.Errorf("cannot combine a topic with --list or --search"))
+			}
+			switch {
+			case list:
+				return listGuides()
+			case search != "":
+				return searchGuides(search)
+			}
+
 			name := ""
 			if len(args) > 0 {
 				name = args[0]
@@ -59,4 +76,50 @@ func newGuideCmd() *cobra.Command {
 			return nil
 		},
 	}
+
+	c.Flags().BoolP(extension.FlagList, "l", false, "List available topics")
+	c.Flags().StringP(extension.FlagSearch, "s", "", "List topics containing a term (case-insensitive)")
+	c.MarkFlagsMutuallyExclusive(extension.FlagList, extension.FlagSearch)
+	return c
+}
+
+// listGuides prints each topic with its summary, aligned for reading.
+func listGuides() error {
+	topics, err := guide.Topics()
+	if err != nil {
+		return cmd.PrintJSONError(fmt.Errorf("listing guides: %w", err))
+	}
+	if cmd.JSON() {
+		return cmd.PrintJSON(topics)
+	}
+
+	width := 0
+	for _, t := range topics {
+		width = max(width, len(t.Name))
+	}
+	for _, t := range topics {
+		fmt.Fprintf(cmd.Out(), "%-*s  %s\n", width, t.Name, t.Summary)
+	}
+	return nil
+}
+
+// searchGuides prints matching lines as topic:line:text, like grep -n.
+func searchGuides(term string) error {
+	matches, err := guide.Search(term)
+	if err != nil {
+		return cmd.PrintJSONError(fmt.Errorf("searching guides: %w", err))
+	}
+	if cmd.JSON() {
+		return cmd.PrintJSON(matches)
+	}
+	if len(matches) == 0 {
+		return cmd.PrintJSONError(fmt.Errorf("no guide topics mention %q", term))
+	}
+
+	for _, m := range matches {
+		for _, l := range m.Lines {
+			fmt.Fprintf(cmd.Out(), "%s:%d:%s\n", m.Topic, l.Number, l.Text)
+		}
+	}
+	return nil
 }
@@ -36,15 +36,25 @@ Use --dir to create in a different directory:
 Use --local to exclude from git:
   llmd init --db notes --local    # creates llmd-notes.db, not committed
 
+Use --encrypt to encrypt document content at rest:
+  LLMD_PASSPHRASE=... llmd init --encrypt
+
+The passphrase comes from LLMD_PASSPHRASE or is prompted for, and every
+later command needs it. Paths, authors, tags and links stay readable;
+full-text search (find) is unavailable and grep scans every document.
+Encryption cannot be added to, or removed from, an existing store.
+
 Note: init does not create config. Use "llmd config" to set up configuration.`,
 		RunE: runInit,
 	}
 	c.Flags().BoolP(extension.FlagLocal, "l", false, "Mark database as local (gitignored)")
+	c.Flags().Bool(extension.FlagEncrypt, false, "Encrypt document content with a passphrase")
 	return c
 }
 
 func runInit(c *cobra.Command, _ []string) error {
 	local, _ := c.Flags().GetBool(extension.FlagLocal)
+	encrypt, _ := c.Flags().GetBool(extension.FlagEncrypt)
 	db, dir := cmd.DB(), cmd.Dir()
 
 	// Validate flag combinations.
@@ -58,13 +68,24 @@ func runInit(c *cobra.Command, _ []string) error {
 		return cmd.PrintJSONError(fmt.Errorf("cannot use --local with --dir: --local modifies the current project's .gitignore, but --dir creates the database elsewhere"))
 	}
 
-	err := document.Init(cmd.Force(), db, local, dir)
+	// Ask for the passphrase before creating anything, so a mistyped
+	// confirmation leaves no half-made store behind.
+	var passphrase string
+	if encrypt {
+		var err error
+		if passphrase, err = cmd.Passphrase(true); err != nil {
+			return cmd.PrintJSONError(fmt.Errorf("init: %w", err))
+		}
+	}
+
+	err := document.InitEncrypted(cmd.Force(), db, local, dir, passphrase)
 
 	log.Event("core:init", "init").
 		Author(cmd.Author()).
 		Detail("db", db).
 		Detail("dir", dir).
 		Detail("local", local).
+		Detail("encrypt", encrypt).
 		Write(err)
 
 	if err != nil {
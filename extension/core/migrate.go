@@ -0,0 +1,121 @@
+// migrate.go implements the "llmd db migrate" command for schema upgrades.
+//
+// Separated from db.go because, like verify and reindex, migrate opens the
+// database rather than only managing gitignore entries.
+//
+// Design: Every other command migrates the store to the latest schema when
+// it opens it. migrate opens it with NoMigrate instead, so it can report the
+// pending migrations (--dry-run) and apply them up to a chosen version.
+
+package core
+
+import (
+	"fmt"
+	"strconv"
+
+	"github.com/jpl-au/llmd/cmd"
+	"github.com/jpl-au/llmd/extension"
+	"github.com/jpl-au/llmd/internal/document"
+	"github.com/jpl-au/llmd/internal/log"
+	"github.com/jpl-au/llmd/internal/store"
+	"github.com/spf13/cobra"
+)
+
+// migrateResult is the JSON output of db migrate.
+type migrateResult struct {
+	From    int               `json:"from"`
+	To      int               `json:"to"`
+	Latest  int               `json:"latest"`
+	Applied []store.Migration `json:"applied"`
+	Pending []store.Migration `json:"pending"`
+}
+
+func newDBMigrateCmd() *cobra.Command {
+	c := &cobra.Command{
+		Use:   "migrate [version]",
+		Short: "Upgrade the database schema",
+		Long: `Apply pending schema migrations, up to the latest version or the given one.
+
+Stores are upgraded automatically whenever llmd opens them for writing, so
+this is mostly useful to see where a store stands or to upgrade one step at
+a time. Migrations cannot be undone.
+
+  llmd db migrate              # apply all pending migrations
+  llmd db migrate --dry-run    # show the version and pending migrations
+  llmd db migrate 2            # apply migrations up to version 2`,
+		Args: cobra.MaximumNArgs(1),
+		RunE: runDBMigrate,
+	}
+	c.Flags().BoolP(extension.FlagDryRun, "n", false, "Show pending migrations without applying them")
+	return c
+}
+
+func runDBMigrate(c *cobra.Command, args []string) error {
+	dryRun, _ := c.Flags().GetBool(extension.FlagDryRun)
+
+	target := store.LatestSchemaVersion()
+	if len(args) > 0 {
+		v, err := strconv.Atoi(args[0])
+		if err != nil {
+			return cmd.PrintJSONError(fmt.Errorf("invalid schema version %q", args[0]))
+		}
+		target = v
+	}
+
+	svc, err := cmd.OpenServiceWith(document.Options{NoMigrate: true})
+	if err != nil {
+		return cmd.PrintJSONError(fmt.Errorf("open store: %w", err))
+	}
+	defer svc.Close()
+
+	ctx := c.Context()
+	result := migrateResult{Latest: store.LatestSchemaVersion(), Applied: []store.Migration{}}
+	result.From, err = svc.SchemaVersion(ctx)
+	if err == nil {
+		result.Pending, err = svc.PendingMigrations(ctx)
+	}
+	if err == nil && !dryRun {
+		result.Applied, err = svc.MigrateTo(ctx, target)
+		if result.Applied == nil {
+			result.Applied = []store.Migration{}
+		}
+		result.Pending = result.Pending[len(result.Applied):]
+	}
+	if result.Pending == nil {
+		result.Pending = []store.Migration{}
+	}
+	result.To = result.From + len(result.Applied)
+	if len(result.Applied) > 0 {
+		result.To = result.Applied[len(result.Applied)-1].Version
+	}
+
+	log.Event("core:db", "migrate").
+		Author(cmd.Author()).
+		Detail("from", result.From).
+		Detail("to", result.To).
+		Detail("dry_run", dryRun).
+		Write(err)
+
+	if err != nil {
+		return cmd.PrintJSONError(fmt.Errorf("db migrate: %w", err))
+	}
+	if cmd.JSON() {
+		return cmd.PrintJSON(result)
+	}
+
+	w := cmd.Out()
+	for _, m := range result.Applied {
+		fmt.Fprintf(w, "Applied %d: %s\n", m.Version, m.Description)
+	}
+	if dryRun {
+		for _, m := range result.Pending {
+			fmt.Fprintf(w, "Pending %d: %s\n", m.Version, m.Description)
+		}
+	}
+	if result.To == result.Latest {
+		fmt.Fprintf(w, "Schema version %d (latest)\n", result.To)
+	} else {
+		fmt.Fprintf(w, "Schema version %d of %d\n", result.To, result.Latest)
+	}
+	return nil
+}
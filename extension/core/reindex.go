@@ -0,0 +1,56 @@
+// reindex.go implements the "llmd db reindex" command for rebuilding the
+// full-text search index.
+//
+// Separated from verify.go because reindex writes: it is the repair for
+// one kind of damage rather than a check.
+//
+// Design: like verify, it opens its own service because db is a
+// NoStoreCommand, so --db, --dir and --read-only apply as usual.
+
+package core
+
+import (
+	"fmt"
+
+	"github.com/jpl-au/llmd/cmd"
+	"github.com/jpl-au/llmd/internal/log"
+	"github.com/spf13/cobra"
+)
+
+func newDBReindexCmd() *cobra.Command {
+	return &cobra.Command{
+		Use:   "reindex",
+		Short: "Rebuild the full-text search index",
+		Long: `Rebuild the full-text search index from the stored documents.
+
+Use this when "llmd find" returns stale or missing results, for example
+after rows were inserted into the database directly. Every version,
+including deleted ones, is reindexed.`,
+		Args: cobra.NoArgs,
+		RunE: runDBReindex,
+	}
+}
+
+func runDBReindex(c *cobra.Command, _ []string) error {
+	svc, err := cmd.OpenService()
+	if err != nil {
+		return cmd.PrintJSONError(fmt.Errorf("open store: %w", err))
+	}
+	defer svc.Close()
+
+	n, err := svc.Reindex(c.Context())
+
+	log.Event("core:db", "reindex").
+		Author(cmd.Author()).
+		Detail("documents", n).
+		Write(err)
+
+	if err != nil {
+		return cmd.PrintJSONError(fmt.Errorf("db reindex: %w", err))
+	}
+	if cmd.JSON() {
+		return cmd.PrintJSON(map[string]int64{"reindexed": n})
+	}
+	fmt.Fprintf(cmd.Out(), "Reindexed %d document version(s)\n", n)
+	return nil
+}
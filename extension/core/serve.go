@@ -2,7 +2,8 @@
 //
 // Separated from extension.go because serve has unique lifecycle requirements.
 // Unlike other commands that run and exit, serve blocks indefinitely handling
-// MCP requests over stdio.
+// MCP requests over stdio (or SSE/HTTP with --transport), or REST requests
+// over HTTP with --http.
 //
 // Design: Serve is a NoStoreCommand - it manages its own service lifecycle
 // instead of using the shared service from root.go. This is necessary because
@@ -12,23 +13,94 @@
 package core
 
 import (
+	"fmt"
+	"os"
+	"os/signal"
+	"strings"
+	"syscall"
+
 	"github.com/jpl-au/llmd/cmd"
+	"github.com/jpl-au/llmd/extension"
 	"github.com/jpl-au/llmd/internal/mcp"
+	"github.com/jpl-au/llmd/internal/profile"
+	"github.com/jpl-au/llmd/internal/rest"
 	"github.com/spf13/cobra"
 )
 
 func newServeCmd() *cobra.Command {
-	return &cobra.Command{
+	c := &cobra.Command{
 		Use:   "serve",
 		Short: "Start MCP server",
 		Long: `Start an MCP (Model Context Protocol) server over stdio for LLM integration.
 
 Use --db to serve a specific database:
-  llmd serve --db docs    # serve llmd-docs.db`,
+  llmd serve --db docs    # serve llmd-docs.db
+
+For MCP clients that connect over the network rather than launching llmd,
+use --transport sse or --transport streamable-http with --addr:
+  llmd serve --transport sse --addr localhost:8080
+
+Use --http to serve a JSON REST API instead of MCP. It has no
+authentication, and an address without a host listens on localhost only:
+  llmd serve --http localhost:8080
+
+Use the global --ephemeral flag for a scratch store held in memory:
+  llmd --ephemeral serve  # nothing is written to disk
+
+Use --tools to offer an assistant only some tools:
+  llmd serve --tools llmd_read,llmd_search,llmd_list
+
+Use the global --read-only flag to let clients read and search but never
+change anything; mutating tools are not offered:
+  llmd --read-only serve
+
+Use --pprof to serve Go's profiling endpoints on a separate address, for
+CPU and heap profiles of a long-running server. Off by default:
+  llmd serve --pprof localhost:6060
+  go tool pprof http://localhost:6060/debug/pprof/heap`,
 		RunE: runServe,
 	}
+	c.Flags().String(extension.FlagHTTP, "", "Serve an unauthenticated REST API on this address (e.g. localhost:8080) instead of MCP over stdio")
+	c.Flags().String(extension.FlagTransport, mcp.TransportStdio, "MCP transport: "+strings.Join(mcp.Transports, ", "))
+	c.Flags().String(extension.FlagAddr, mcp.DefaultAddr, "Listen address for the unauthenticated sse and streamable-http transports (a bare :port means localhost)")
+	c.Flags().StringSlice(extension.FlagTools, nil, "Offer only these MCP tools (comma-separated or repeated)")
+	c.Flags().String(extension.FlagPprof, "", "Serve pprof profiling endpoints on this address (a bare :port means localhost)")
+	return c
 }
 
-func runServe(_ *cobra.Command, _ []string) error {
-	return mcp.Serve(cmd.DB())
+func runServe(c *cobra.Command, _ []string) error {
+	ctx, stop := signal.NotifyContext(c.Context(), os.Interrupt, syscall.SIGTERM)
+	defer stop()
+
+	// Profiling gets its own listener, never the stdio transport or the
+	// API's address, so it cannot interfere with JSON-RPC or REST clients.
+	if pprofAddr, _ := c.Flags().GetString(extension.FlagPprof); pprofAddr != "" {
+		if err := profile.Start(ctx, pprofAddr); err != nil {
+			return fmt.Errorf("pprof: %w", err)
+		}
+	}
+
+	transport, _ := c.Flags().GetString(extension.FlagTransport)
+	addr, _ := c.Flags().GetString(extension.FlagHTTP)
+	if addr != "" {
+		if c.Flags().Changed(extension.FlagTransport) {
+			return fmt.Errorf("--http serves REST, not MCP, and cannot be combined with --transport")
+		}
+		if c.Flags().Changed(extension.FlagTools) {
+			return fmt.Errorf("--tools selects MCP tools and cannot be combined with --http")
+		}
+		return rest.Serve(ctx, addr, cmd.DB(), rest.ServeOptions{
+			Ephemeral: cmd.Ephemeral(),
+			ReadOnly:  cmd.ReadOnly(),
+		})
+	}
+
+	opts := mcp.ServeOptions{
+		Transport: transport,
+		Ephemeral: cmd.Ephemeral(),
+		ReadOnly:  cmd.ReadOnly(),
+	}
+	opts.Addr, _ = c.Flags().GetString(extension.FlagAddr)
+	opts.Tools, _ = c.Flags().GetStringSlice(extension.FlagTools)
+	return mcp.Serve(ctx, cmd.DB(), opts)
 }
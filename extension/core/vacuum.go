@@ -20,7 +20,6 @@ import (
 	"github.com/jpl-au/llmd/cmd"
 	"github.com/jpl-au/llmd/extension"
 	"github.com/jpl-au/llmd/internal/config"
-	"github.com/jpl-au/llmd/internal/document"
 	"github.com/jpl-au/llmd/internal/duration"
 	"github.com/jpl-au/llmd/internal/log"
 	"github.com/jpl-au/llmd/internal/vacuum"
@@ -46,7 +45,7 @@ Duration formats: 7d (days), 4w (weeks), 3m (months)`,
 
 func runVacuum(c *cobra.Command, _ []string) error {
 	var ctx context.Context = c.Context()
-	svc, err := document.New(cmd.DB())
+	svc, err := cmd.OpenService()
 	if err != nil {
 		return cmd.PrintJSONError(fmt.Errorf("open store: %w", err))
 	}
@@ -0,0 +1,67 @@
+// verify.go implements the "llmd db verify" command for store health checks.
+//
+// Separated from db.go because, unlike the rest of db, verify opens the
+// database rather than only managing gitignore entries.
+//
+// Design: db is a NoStoreCommand, so verify opens its own service the way
+// vacuum does. It honours --db, --dir and --read-only like any command.
+
+package core
+
+import (
+	"fmt"
+	"io"
+
+	"github.com/jpl-au/llmd/cmd"
+	"github.com/jpl-au/llmd/internal/log"
+	"github.com/jpl-au/llmd/internal/verify"
+	"github.com/spf13/cobra"
+)
+
+func newDBVerifyCmd() *cobra.Command {
+	return &cobra.Command{
+		Use:   "verify",
+		Short: "Check database integrity",
+		Long: `Check the database for corruption and broken references.
+
+Runs SQLite's integrity check, reports links whose endpoints no longer
+exist and versions whose content no longer matches its recorded hash. Exits non-zero when issues are found; with -o json the result has an
+"ok" field and the list of issues, for use in CI.`,
+		Args: cobra.NoArgs,
+		RunE: runDBVerify,
+	}
+}
+
+func runDBVerify(c *cobra.Command, _ []string) error {
+	svc, err := cmd.OpenService()
+	if err != nil {
+		return cmd.PrintJSONError(fmt.Errorf("open store: %w", err))
+	}
+	defer svc.Close()
+
+	w := cmd.Out()
+	if cmd.JSON() {
+		w = io.Discard
+	}
+
+	result, err := verify.Run(c.Context(), w, svc)
+
+	log.Event("core:db", "verify").
+		Author(cmd.Author()).
+		Detail("issues", len(result.Issues)).
+		Write(err)
+
+	if err != nil {
+		return cmd.PrintJSONError(fmt.Errorf("db verify: %w", err))
+	}
+	if cmd.JSON() {
+		if err := cmd.PrintJSON(result); err != nil {
+			return err
+		}
+	}
+	// Fail in JSON mode too, so scripts and CI see the exit status
+	if !result.OK {
+		return fmt.Errorf("%d issue(s) found", len(result.Issues))
+	}
+	return nil
+}
@@ -0,0 +1,86 @@
+// whoami.go implements the "llmd whoami" command, which shows the author
+// writes would be attributed to.
+//
+// Separated from config.go because the answer does not come from one config
+// file: --author overrides the config, and the config itself may be local
+// or global. Showing where the author came from makes those rules visible.
+
+package core
+
+import (
+	"errors"
+	"fmt"
+
+	"github.com/jpl-au/llmd/cmd"
+	"github.com/jpl-au/llmd/internal/config"
+	"github.com/jpl-au/llmd/internal/log"
+	"github.com/spf13/cobra"
+)
+
+// Where the effective author came from.
+const (
+	authorFromFlag   = "flag"
+	authorFromLocal  = "local"
+	authorFromGlobal = "global"
+)
+
+// whoami is the effective author and its source.
+type whoami struct {
+	Name   string `json:"name"`
+	Email  string `json:"email,omitempty"`
+	Source string `json:"source"`           // flag, local or global
+	Config string `json:"config,omitempty"` // Config file the author was read from
+}
+
+func newWhoamiCmd() *cobra.Command {
+	return &cobra.Command{
+		Use:   "whoami",
+		Short: "Show the author writes are attributed to",
+		Long: `Show the author that writes would be attributed to, and where it came
+from: --author first, then author.name in the local config if it exists,
+otherwise the global config.
+
+  llmd whoami
+  llmd whoami -a reviewer          # --author always wins
+  llmd config author "Jane Doe <jane@example.com>"   # set name and email`,
+		Args: cobra.NoArgs,
+		RunE: runWhoami,
+	}
+}
+
+func runWhoami(c *cobra.Command, _ []string) error {
+	l := log.Event("core:whoami", "whoami").Author(cmd.Author())
+
+	var w whoami
+	if c.Flags().Changed("author") {
+		w = whoami{Name: cmd.Author(), Source: authorFromFlag}
+	} else {
+		cfg, err := config.Load()
+		if err != nil {
+			l.Write(err)
+			return cmd.PrintJSONError(fmt.Errorf("config load: %w", err))
+		}
+		w = whoami{Name: cfg.Author.Name, Email: cfg.Author.Email, Source: authorFromGlobal, Config: config.GlobalPath()}
+		if cfg.Scope() == config.ScopeLocal {
+			w.Source, w.Config = authorFromLocal, config.LocalPath()
+		}
+	}
+
+	if w.Name == "" {
+		err := errors.New("no author configured; writes will be rejected\n\nRun: llmd config author \"Your Name <you@example.com>\"")
+		l.Write(err)
+		return cmd.PrintJSONError(err)
+	}
+	l.Detail("source", w.Source).Write(nil)
+
+	if cmd.JSON() {
+		return cmd.PrintJSON(w)
+	}
+	a := config.Author{Name: w.Name, Email: w.Email}
+	if w.Config != "" {
+		fmt.Fprintf(cmd.Out(), "%s (%s config %s)\n", a, w.Source, w.Config)
+	} else {
+		fmt.Fprintf(cmd.Out(), "%s (--author)\n", a)
+	}
+	return nil
+}
@@ -0,0 +1,98 @@
+// audit.go implements the "llmd audit" command, a change ledger across
+// documents.
+//
+// Separated from history.go because it answers a different question: not
+// how one document evolved, but every version written across the store in
+// a time range, with author and size, for compliance reviews.
+
+package document
+
+import (
+	"fmt"
+	"io"
+	"time"
+
+	"github.com/jpl-au/llmd/cmd"
+	"github.com/jpl-au/llmd/extension"
+	"github.com/jpl-au/llmd/internal/audit"
+	"github.com/jpl-au/llmd/internal/duration"
+	"github.com/jpl-au/llmd/internal/log"
+	"github.com/jpl-au/llmd/internal/store"
+	"github.com/spf13/cobra"
+)
+
+func (e *Extension) newAuditCmd() *cobra.Command {
+	c := &cobra.Command{
+		Use:   "audit [path]",
+		Short: "List every version written in a time range",
+		Long: `List every version written across documents, oldest first, with path,
+version, author, message and size.
+
+  llmd audit --since 2024-01-01 --until 2024-04-01   # one quarter
+  llmd audit docs/ --since 7d -o csv                 # last week, as CSV
+
+The ledger is read from the stored versions themselves, so it is complete
+even if the audit event log was unavailable.`,
+		Args: cobra.MaximumNArgs(1),
+		RunE: e.runAudit,
+	}
+	c.Flags().String(extension.FlagSince, "", "Include versions written at or after this time (e.g., 7d, 2024-01-15)")
+	c.Flags().String(extension.FlagUntil, "", "Include versions written before this time (e.g., 2024-02-01)")
+	return c
+}
+
+func (e *Extension) runAudit(c *cobra.Command, args []string) error {
+	ctx := c.Context()
+	prefix := ""
+	if len(args) > 0 {
+		prefix = args[0]
+	}
+
+	opts := audit.Options{
+		Prefix:    prefix,
+		Delimiter: cmd.Delimiter(),
+	}
+
+	now := time.Now()
+	if s, _ := c.Flags().GetString(extension.FlagSince); s != "" {
+		t, err := duration.ParseTime(s, now)
+		if err != nil {
+			return cmd.PrintJSONError(err)
+		}
+		opts.Since = t
+	}
+	if s, _ := c.Flags().GetString(extension.FlagUntil); s != "" {
+		t, err := duration.ParseTime(s, now)
+		if err != nil {
+			return cmd.PrintJSONError(err)
+		}
+		opts.Until = t
+	}
+
+	w := cmd.Out()
+	if cmd.JSON() {
+		w = io.Discard
+	}
+
+	l := log.Event("document:audit", "audit").
+		Author(cmd.Author()).
+		Path(prefix)
+
+	result, err := audit.Run(ctx, w, e.svc, opts)
+	if err != nil {
+		l.Write(err)
+		return cmd.PrintJSONError(fmt.Errorf("audit: %w", err))
+	}
+
+	l.Detail("count", len(result.Entries)).
+		Write(nil)
+
+	if cmd.JSON() {
+		out := make([]store.AuditEntryJSON, len(result.Entries))
+		for i := range result.Entries {
+			out[i] = result.Entries[i].ToJSON()
+		}
+		return cmd.PrintJSON(out)
+	}
+	return nil
+}
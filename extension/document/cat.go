@@ -4,8 +4,11 @@
 // line numbering, line range extraction, and terminal rendering with glamour.
 //
 // Design: Cat behaves like Unix cat with enhancements for versioned documents.
-// Terminal output gets glamour markdown rendering; pipe/redirect gets raw
-// markdown. The -l flag uses colon syntax (10:20) matching sed/awk conventions.
+// Piped or redirected output is byte-exact, so "cat | write" round-trips.
+// Terminal output is for reading: markdown is rendered with glamour and each
+// document ends with a newline so the prompt starts on its own line. --raw
+// gives byte-exact output on a terminal too. The -l flag uses colon syntax
+// (10:20) matching sed/awk conventions.
 
 package document
 
@@ -15,12 +18,16 @@ import (
 	"io"
 	"os"
 	"strings"
+	"time"
 
 	"github.com/charmbracelet/glamour"
 	"github.com/jpl-au/llmd/cmd"
 	"github.com/jpl-au/llmd/extension"
 	"github.com/jpl-au/llmd/internal/cat"
+	"github.com/jpl-au/llmd/internal/contenttype"
+	"github.com/jpl-au/llmd/internal/duration"
 	"github.com/jpl-au/llmd/internal/log"
+	"github.com/jpl-au/llmd/internal/redact"
 	"github.com/spf13/cobra"
 	"golang.org/x/term"
 )
@@ -29,35 +36,85 @@ func (e *Extension) newCatCmd() *cobra.Command {
 	c := &cobra.Command{
 		Use:   "cat <path|key>...",
 		Short: "Read a document",
-		Long:  `Output the contents of one or more documents to stdout.`,
-		Args:  cobra.MinimumNArgs(1),
-		RunE:  e.runCat,
+		Long: `Output the contents of one or more documents to stdout.
+
+With --section '## Heading', only that section is shown: the heading up to
+the next heading of the same or higher level (see "llmd sections").
+
+With --expand, each {{include:path}} directive is replaced by the current
+content of that document, recursively. Include cycles are an error.
+
+With --redact, anything matching the redact.patterns config (by default
+email addresses, private keys and common API tokens) is shown as
+[REDACTED]. The stored document is unchanged.
+
+Output is written exactly as stored, with no newline added or removed,
+when piped or redirected, so "llmd cat a | llmd write b" copies a document
+byte for byte. On a terminal, markdown is rendered and a missing trailing
+newline is added for readability; --raw turns both off.
+
+-v takes a single version, a list (1,3,5) or a range (1:5). With more than
+one version, each is printed under a "==> path (vN) <==" header, or as an
+array of versions with -o json.`,
+		Args: cobra.MinimumNArgs(1),
+		RunE: e.runCat,
 	}
-	c.Flags().IntP(extension.FlagVersion, "v", 0, "Read specific version")
+	c.Flags().StringP(extension.FlagVersion, "v", "", "Read specific version(s) (e.g., 3, 1,3,5, 1:5)")
 	c.Flags().BoolP(extension.FlagDeleted, "D", false, "Read a deleted document")
 	c.Flags().BoolP(extension.FlagNumber, "n", false, "Number all output lines")
 	c.Flags().StringP(extension.FlagLines, "l", "", "Line range (e.g., 10:20, 5:, :15)")
-	c.Flags().Bool(extension.FlagRaw, false, "Output raw markdown without rendering")
+	c.Flags().String(extension.FlagSection, "", "Show only the section under this heading (e.g., '## Usage')")
+	c.Flags().Bool(extension.FlagRaw, false, "Output content exactly as stored, even on a terminal")
+	c.Flags().Bool(extension.FlagExpand, false, "Inline {{include:path}} directives")
+	c.Flags().Bool(extension.FlagRedact, false, "Mask secrets matching the redact.patterns config")
+	c.Flags().String(extension.FlagAsOf, "", "Read the version current at this time (e.g., 7d, 2024-01-15)")
 	return c
 }
 
 func (e *Extension) runCat(c *cobra.Command, args []string) error {
 	ctx := c.Context()
-	ver, _ := c.Flags().GetInt(extension.FlagVersion)
+	verSpec, _ := c.Flags().GetString(extension.FlagVersion)
 	del, _ := c.Flags().GetBool(extension.FlagDeleted)
 	lineNums, _ := c.Flags().GetBool(extension.FlagNumber)
 	lineRange, _ := c.Flags().GetString(extension.FlagLines)
 	raw, _ := c.Flags().GetBool(extension.FlagRaw)
+	expand, _ := c.Flags().GetBool(extension.FlagExpand)
+	section, _ := c.Flags().GetString(extension.FlagSection)
+	redacted, _ := c.Flags().GetBool(extension.FlagRedact)
 
-	if ver < 0 {
-		return cmd.PrintJSONError(fmt.Errorf("version must be >= 0, got %d", ver))
+	// Version 0 has always meant the latest version, as if -v were omitted
+	var versions []int
+	if verSpec != "" && verSpec != "0" {
+		var err error
+		versions, err = cat.ParseVersions(verSpec)
+		if err != nil {
+			return cmd.PrintJSONError(err)
+		}
 	}
 
 	opts := cat.Options{
-		Version:        ver,
 		IncludeDeleted: del,
 		LineNumbers:    lineNums,
+		Expand:         expand,
+		Section:        section,
 		MaxLineLength:  e.cfg.MaxLineLength(),
+		MaxContent:     e.cfg.MaxContent(),
+	}
+
+	if redacted {
+		r, err := redact.New(e.cfg.RedactPatterns())
+		if err != nil {
+			return cmd.PrintJSONError(err)
+		}
+		opts.Redact = r
+	}
+
+	if asOf, _ := c.Flags().GetString(extension.FlagAsOf); asOf != "" {
+		t, err := duration.ParseTime(asOf, time.Now())
+		if err != nil {
+			return cmd.PrintJSONError(err)
+		}
+		opts.AsOf = t
 	}
 
 	// Parse line range (e.g., "10:20", "5:", ":15")
@@ -81,6 +138,40 @@ func (e *Extension) runCat(c *cobra.Command, args []string) error {
 		l.Detail("count", len(paths)).Write(nil)
 	}()
 
+	if len(versions) == 1 {
+		opts.Version = versions[0]
+	}
+
+	// Several versions: each document's versions in turn, under headers
+	if len(versions) > 1 {
+		w := cmd.Out()
+		if cmd.JSON() {
+			w = io.Discard
+		}
+		var docs []any
+		for i, path := range args {
+			if i > 0 {
+				fmt.Fprintln(w)
+			}
+			results, err := cat.Versions(ctx, w, e.svc, path, versions, opts)
+			if err != nil {
+				return cmd.PrintJSONError(fmt.Errorf("cat %q: %w", path, err))
+			}
+			paths = append(paths, results[0].Document.Path)
+			for _, r := range results {
+				if r.Section != nil {
+					docs = append(docs, r.Section)
+					continue
+				}
+				docs = append(docs, r.Document.ToJSON(true))
+			}
+		}
+		if cmd.JSON() {
+			return cmd.PrintJSON(docs)
+		}
+		return nil
+	}
+
 	// JSON mode: return array of documents
 	if cmd.JSON() {
 		var docs []any
@@ -90,6 +181,10 @@ func (e *Extension) runCat(c *cobra.Command, args []string) error {
 				return cmd.PrintJSONError(fmt.Errorf("cat %q: %w", path, err))
 			}
 			paths = append(paths, result.Document.Path)
+			if result.Section != nil {
+				docs = append(docs, result.Section)
+				continue
+			}
 			docs = append(docs, result.Document.ToJSON(true))
 		}
 		// Return single object for single file, array for multiple
@@ -99,34 +194,63 @@ func (e *Extension) runCat(c *cobra.Command, args []string) error {
 		return cmd.PrintJSON(docs)
 	}
 
+	// Only output a person reads is adjusted; anything else stays exact
+	human := !raw && term.IsTerminal(int(os.Stdout.Fd()))
+
 	// Single file with TTY: use glamour rendering
-	if len(args) == 1 && !raw && term.IsTerminal(int(os.Stdout.Fd())) {
+	if len(args) == 1 && human {
 		var buf bytes.Buffer
 		result, err := cat.Run(ctx, &buf, e.svc, args[0], opts)
 		if err != nil {
 			return cmd.PrintJSONError(fmt.Errorf("cat %q: %w", args[0], err))
 		}
 		paths = append(paths, result.Document.Path)
+		// Only markdown is rendered; JSON or plain text prints unrendered
+		if !contenttype.IsMarkdown(result.Document.ContentType) {
+			fmt.Fprint(cmd.Out(), withNewline(buf.String()))
+			return nil
+		}
 		rendered, renderErr := glamour.Render(buf.String(), "dark")
 		if renderErr == nil {
 			fmt.Fprint(cmd.Out(), rendered)
 			return nil
 		}
-		// Rendering failed, fall back to raw output with warning
+		// Rendering failed, fall back to unrendered output with warning
 		fmt.Fprintln(os.Stderr, "warning: markdown rendering failed, showing raw output")
+		fmt.Fprint(cmd.Out(), withNewline(buf.String()))
+		return nil
 	}
 
 	// Multiple files or raw mode: concatenate output
 	for _, path := range args {
-		result, err := cat.Run(ctx, cmd.Out(), e.svc, path, opts)
+		if !human {
+			result, err := cat.Run(ctx, cmd.Out(), e.svc, path, opts)
+			if err != nil {
+				return cmd.PrintJSONError(fmt.Errorf("cat %q: %w", path, err))
+			}
+			paths = append(paths, result.Document.Path)
+			continue
+		}
+		var buf strings.Builder
+		result, err := cat.Run(ctx, &buf, e.svc, path, opts)
 		if err != nil {
 			return cmd.PrintJSONError(fmt.Errorf("cat %q: %w", path, err))
 		}
 		paths = append(paths, result.Document.Path)
+		fmt.Fprint(cmd.Out(), withNewline(buf.String()))
 	}
 	return nil
 }
 
+// withNewline returns s ending in a newline, for terminal output. Empty
+// output stays empty.
+func withNewline(s string) string {
+	if s == "" || strings.HasSuffix(s, "\n") {
+		return s
+	}
+	return s + "\n"
+}
+
 // parseLineRange parses a line range string like "10:20", "5:", or ":15".
 // Returns start and end line numbers (1-indexed), where 0 means unspecified.
 func parseLineRange(s string) (start, end int, err error) {
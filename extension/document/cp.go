@@ -0,0 +1,69 @@
+// cp.go implements the "llmd cp" command for copying documents.
+//
+// Separated from mv.go because a copy creates new documents rather than
+// renaming existing ones.
+//
+// Design: Follows Unix cp. `cp source dest` copies one document, and a dest
+// ending in / copies it under that prefix. `cp -r prefix/ dest/` copies a
+// whole subtree in one transaction, failing without copying anything if any
+// destination already exists. Copies start at version 1; history stays with
+// the source.
+
+package document
+
+import (
+	"fmt"
+	"io"
+
+	"github.com/jpl-au/llmd/cmd"
+	"github.com/jpl-au/llmd/extension"
+	"github.com/jpl-au/llmd/internal/cp"
+	"github.com/jpl-au/llmd/internal/log"
+	"github.com/spf13/cobra"
+)
+
+func (e *Extension) newCpCmd() *cobra.Command {
+	c := &cobra.Command{
+		Use:   "cp <source> <dest>",
+		Short: "Copy documents",
+		Long: `Copy a document, or with -r every document under a prefix.
+
+Single document: cp source dest
+Into a prefix:   cp source dest/
+Subtree:         cp -r docs/ archive/
+
+Each copy starts at version 1 with the source's latest content. A recursive
+copy is a single transaction: if any destination already exists, nothing
+is copied.`,
+		Args: cobra.ExactArgs(2),
+		RunE: e.runCp,
+	}
+	c.Flags().BoolP(extension.FlagRecursive, "r", false, "Copy all documents under the source prefix")
+	return c
+}
+
+func (e *Extension) runCp(c *cobra.Command, args []string) error {
+	from, to := args[0], args[1]
+	opts := cp.Options{Author: cmd.Author()}
+	opts.Recursive, _ = c.Flags().GetBool(extension.FlagRecursive)
+
+	w := cmd.Out()
+	if cmd.JSON() {
+		w = io.Discard
+	}
+
+	l := log.Event("document:cp", "copy").
+		Author(opts.Author).
+		Path(from).
+		Detail("dest", to).
+		Detail("recursive", opts.Recursive)
+
+	result, err := cp.Run(c.Context(), w, e.svc, from, to, opts)
+	if err != nil {
+		l.Write(err)
+		return cmd.PrintJSONError(fmt.Errorf("cp: %w", err))
+	}
+
+	l.Detail("count", result.Count).Write(nil)
+	return cmd.PrintJSON(result)
+}
@@ -0,0 +1,68 @@
+// dedup.go implements the "llmd dedup" command for finding duplicate
+// documents.
+//
+// Separated from document.go to isolate duplicate detection.
+//
+// Design: Dedup only reports; consolidating is left to rm, mv and edit, so
+// nothing changes without a deliberate step. Exact duplicates are always
+// reported; --similar adds near-duplicates, found by shingling in
+// internal/dedup.
+
+package document
+
+import (
+	"fmt"
+	"io"
+
+	"github.com/jpl-au/llmd/cmd"
+	"github.com/jpl-au/llmd/extension"
+	"github.com/jpl-au/llmd/internal/dedup"
+	"github.com/jpl-au/llmd/internal/log"
+	"github.com/spf13/cobra"
+)
+
+func (e *Extension) newDedupCmd() *cobra.Command {
+	c := &cobra.Command{
+		Use:   "dedup [prefix]",
+		Short: "Find duplicate documents",
+		Long: `Report groups of documents with identical content, optionally under a
+path prefix.
+
+--similar also reports near-duplicates: documents sharing at least that
+fraction of their wording (0-1, e.g. 0.8), compared in overlapping runs of
+words regardless of case and line breaks.`,
+		Args: cobra.MaximumNArgs(1),
+		RunE: e.runDedup,
+	}
+	c.Flags().Float64(extension.FlagSimilar, 0, "Also report near-duplicates at or above this similarity (0-1)")
+	return c
+}
+
+func (e *Extension) runDedup(c *cobra.Command, args []string) error {
+	opts := dedup.Options{}
+	if len(args) > 0 {
+		opts.Prefix = args[0]
+	}
+	opts.Similar, _ = c.Flags().GetFloat64(extension.FlagSimilar)
+
+	w := cmd.Out()
+	if cmd.JSON() {
+		w = io.Discard
+	}
+
+	l := log.Event("document:dedup", "list").
+		Author(cmd.Author()).
+		Path(opts.Prefix).
+		Detail("similar", opts.Similar)
+
+	result, err := dedup.Run(c.Context(), w, e.svc, opts)
+	if err != nil {
+		l.Write(err)
+		return cmd.PrintJSONError(fmt.Errorf("dedup: %w", err))
+	}
+
+	l.Detail("exact", len(result.Exact)).
+		Detail("near", len(result.Near)).
+		Write(nil)
+	return cmd.PrintJSON(result)
+}
@@ -6,15 +6,19 @@
 // Design: Diff supports multiple comparison modes for flexibility:
 // - Version to version (within same document)
 // - Document to document (different paths)
-// - Filesystem file to document (for sync verification)
+// - Document to filesystem file (for reconciling external edits)
 // Output uses unified diff format compatible with patch tools.
 
 package document
 
 import (
+	"errors"
 	"fmt"
 	"io"
+	"io/fs"
 	"os"
+	"strconv"
+	"strings"
 
 	"github.com/jpl-au/llmd/cmd"
 	"github.com/jpl-au/llmd/extension"
@@ -23,6 +27,10 @@ import (
 	"github.com/spf13/cobra"
 )
 
+// diffExitChanged is the --check exit status when the two sides differ.
+// Distinct from 1 so scripts can tell drift apart from a failed diff.
+const diffExitChanged = 2
+
 func (e *Extension) newDiffCmd() *cobra.Command {
 	c := &cobra.Command{
 		Use:   "diff <path|key> [doc-path]",
@@ -33,29 +41,59 @@ Examples:
   llmd diff docs/readme              # Compare latest with previous version
   llmd diff docs/readme -v 3:5       # Compare version 3 with version 5
   llmd diff docs/readme docs/other   # Compare two different documents
-  llmd diff -f ./local.md docs/readme    # Compare filesystem file with stored document`,
+  llmd diff docs/readme -f ./local.md     # Compare stored document with a local file
+  llmd diff docs/readme -f ./local.md -v 3  # ...against version 3
+  llmd diff docs/readme -f ./gen.md --check # Exit 2 if they differ, print nothing`,
 		Args: cobra.RangeArgs(1, 2),
 		RunE: e.runDiff,
 	}
-	c.Flags().StringP(extension.FlagVersions, "v", "", "Version range (e.g., 3:5)")
+	c.Flags().StringP(extension.FlagVersions, "v", "", "Version range (e.g., 3:5), or a single version with --file")
 	c.Flags().BoolP(extension.FlagDeleted, "D", false, "Allow diffing deleted documents")
-	c.Flags().BoolP(extension.FlagFile, "f", false, "Treat first path as filesystem file")
-	c.Flags().Bool(extension.FlagRaw, false, "Output without colour")
+	c.Flags().StringP(extension.FlagFile, "f", "", "Compare with a filesystem file")
+	c.Flags().String(extension.FlagColor, string(diff.ColourAuto), "Colour output: auto, always or never")
+	c.Flags().Bool(extension.FlagRaw, false, "Output without colour (same as --color never)")
+	c.Flags().Bool(extension.FlagCheck, false, "Print nothing; exit 2 if there are differences")
+	c.Flags().Bool(extension.FlagQuiet, false, "Alias for --check")
 	return c
 }
 
 func (e *Extension) runDiff(c *cobra.Command, args []string) error {
 	verRange, _ := c.Flags().GetString(extension.FlagVersions)
 	del, _ := c.Flags().GetBool(extension.FlagDeleted)
-	isFile, _ := c.Flags().GetBool(extension.FlagFile)
+	file, _ := c.Flags().GetString(extension.FlagFile)
+	colour, _ := c.Flags().GetString(extension.FlagColor)
 	raw, _ := c.Flags().GetBool(extension.FlagRaw)
+	check, _ := c.Flags().GetBool(extension.FlagCheck)
+	quiet, _ := c.Flags().GetBool(extension.FlagQuiet)
+	check = check || quiet
 	path := args[0]
 
 	var opts diff.Options
 	var err error
 	opts.IncludeDeleted = del
 
-	if verRange != "" {
+	mode, err := diff.ParseColourMode(colour)
+	if err != nil {
+		return cmd.PrintJSONError(err)
+	}
+	if raw {
+		mode = diff.ColourNever
+	}
+
+	if file != "" && len(args) == 2 {
+		return cmd.PrintJSONError(fmt.Errorf("--file compares with a single document: llmd diff <path|key> --file <file>"))
+	}
+
+	switch {
+	case file != "" && verRange != "" && !strings.Contains(verRange, ":"):
+		// With --file only the stored side needs a version
+		opts.Version1, err = strconv.Atoi(verRange)
+		if err != nil || opts.Version1 < 1 {
+			return cmd.PrintJSONError(fmt.Errorf("invalid version %q (expected a number >= 1)", verRange))
+		}
+	case file != "" && verRange != "":
+		return cmd.PrintJSONError(fmt.Errorf("--file takes a single version (-v N), not a range"))
+	case verRange != "":
 		opts.Version1, opts.Version2, err = diff.ParseVersionRange(verRange)
 		if err != nil {
 			return cmd.PrintJSONError(err)
@@ -66,34 +104,31 @@ func (e *Extension) runDiff(c *cobra.Command, args []string) error {
 		opts.Path2 = args[1]
 	}
 
-	// Read file content if -f flag is set
-	if isFile {
-		if opts.Path2 == "" {
-			return cmd.PrintJSONError(fmt.Errorf("-f/--file requires two arguments: llmd diff -f <file> <doc-path>"))
+	if file != "" {
+		b, err := os.ReadFile(file)
+		if errors.Is(err, fs.ErrNotExist) {
+			return cmd.PrintJSONError(fmt.Errorf("file not found: %s", file))
 		}
-		b, err := os.ReadFile(path)
 		if err != nil {
-			return cmd.PrintJSONError(fmt.Errorf("reading file %s: %w", path, err))
+			return cmd.PrintJSONError(fmt.Errorf("reading file %s: %w", file, err))
 		}
+		opts.File = file
 		opts.FileContent = string(b)
 	}
 
 	ctx := c.Context()
 
 	// Resolve path or key to get actual document path for logging
-	// (only when not using -f flag, where path is a filesystem file)
 	logPath := path
-	if !isFile {
-		if doc, _, resolveErr := e.svc.Resolve(ctx, path, del); resolveErr == nil {
-			logPath = doc.Path
-		}
+	if doc, _, resolveErr := e.svc.Resolve(ctx, path, del); resolveErr == nil {
+		logPath = doc.Path
 	}
 
 	w := cmd.Out()
-	if cmd.JSON() {
+	if cmd.JSON() || check {
 		w = io.Discard
 	}
-	opts.Colour = !raw
+	opts.Colour = mode.Enabled(w)
 
 	r, err := diff.Run(ctx, w, e.svc, path, opts)
 
@@ -106,6 +141,13 @@ func (e *Extension) runDiff(c *cobra.Command, args []string) error {
 		return cmd.PrintJSONError(fmt.Errorf("diff %q: %w", path, err))
 	}
 
+	if check {
+		if r.Changed {
+			return cmd.Exit(c, diffExitChanged)
+		}
+		return nil
+	}
+
 	return cmd.PrintJSON(map[string]string{
 		"old":  r.Old,
 		"new":  r.New,
@@ -1,5 +1,6 @@
 // Package document provides the document extension for core CRUD operations.
-// Registers commands: cat, ls, write, rm, restore, revert, mv, history, diff.
+// Registers commands: cat, ls, write, rm, restore, revert, undo, mv, history, diff, sections,
+// split, join, new, template, resolve.
 //
 // These commands mirror Unix filesystem utilities to provide familiar semantics
 // for LLM and human users. Each command file is separated to isolate its
@@ -45,14 +46,27 @@ func (e *Extension) Init(ctx extension.Context) error {
 func (e *Extension) Commands() []*cobra.Command {
 	return []*cobra.Command{
 		e.newCatCmd(),
+		e.newWcCmd(),
+		e.newTokensCmd(),
+		e.newPackCmd(),
 		e.newLsCmd(),
 		e.newWriteCmd(),
 		e.newRmCmd(),
 		e.newRestoreCmd(),
 		e.newRevertCmd(),
+		e.newUndoCmd(),
 		e.newMvCmd(),
+		e.newCpCmd(),
+		e.newDedupCmd(),
 		e.newHistoryCmd(),
+		e.newAuditCmd(),
 		e.newDiffCmd(),
+		e.newSectionsCmd(),
+		e.newSplitCmd(),
+		e.newJoinCmd(),
+		e.newNewCmd(),
+		e.newTemplateCmd(),
+		e.newResolveCmd(),
 	}
 }
 
@@ -34,6 +34,7 @@ func (e *Extension) newHistoryCmd() *cobra.Command {
 	c.Flags().IntP(extension.FlagLimit, "n", 0, "Limit number of versions shown")
 	c.Flags().BoolP(extension.FlagDeleted, "D", false, "Include deleted versions")
 	c.Flags().BoolP(extension.FlagDiff, "d", false, "Show diffs between versions")
+	c.Flags().BoolP(extension.FlagReverse, "r", false, "Show oldest versions first")
 	return c
 }
 
@@ -42,6 +43,7 @@ func (e *Extension) runHistory(c *cobra.Command, args []string) error {
 	limit, _ := c.Flags().GetInt(extension.FlagLimit)
 	del, _ := c.Flags().GetBool(extension.FlagDeleted)
 	showDiff, _ := c.Flags().GetBool(extension.FlagDiff)
+	reverse, _ := c.Flags().GetBool(extension.FlagReverse)
 	path := args[0]
 
 	if limit < 0 {
@@ -53,6 +55,8 @@ func (e *Extension) runHistory(c *cobra.Command, args []string) error {
 		IncludeDeleted: del,
 		ShowDiff:       showDiff,
 		Colour:         term.IsTerminal(int(os.Stdout.Fd())),
+		Delimiter:      cmd.Delimiter(),
+		Reverse:        reverse,
 	}
 
 	w := cmd.Out()
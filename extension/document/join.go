@@ -0,0 +1,77 @@
+// join.go implements the "llmd join" command for assembling documents.
+//
+// Separated from document.go to isolate the join flags and output.
+//
+// Design: Join is the inverse of split. Sources may be glob patterns, which
+// are expanded against the store rather than the shell, so quote them.
+
+package document
+
+import (
+	"fmt"
+	"io"
+
+	"github.com/jpl-au/llmd/cmd"
+	"github.com/jpl-au/llmd/extension"
+	"github.com/jpl-au/llmd/internal/join"
+	"github.com/jpl-au/llmd/internal/log"
+	"github.com/spf13/cobra"
+)
+
+func (e *Extension) newJoinCmd() *cobra.Command {
+	c := &cobra.Command{
+		Use:   "join <dest> <source>...",
+		Short: "Concatenate documents into one",
+		Long: `Concatenate the content of several documents, in the order given, into a
+new document. Sources may be paths, keys or quoted glob patterns; a pattern
+expands to its matches in path order.
+
+  llmd join docs/guide docs/intro docs/usage
+  llmd join docs/combined 'docs/parts/*'
+  llmd join docs/combined 'docs/parts/*' --headers --delete
+
+Fails if the destination exists, unless --force is given, in which case the
+joined content becomes a new version of it.`,
+		Args: cobra.MinimumNArgs(2),
+		RunE: e.runJoin,
+	}
+	c.Flags().Bool(extension.FlagHeaders, false, "Add a ## heading named after each source")
+	c.Flags().Bool(extension.FlagDelete, false, "Soft-delete the sources after joining")
+	return c
+}
+
+func (e *Extension) runJoin(c *cobra.Command, args []string) error {
+	ctx := c.Context()
+	dest, sources := args[0], args[1:]
+
+	opts := join.Options{
+		Force:   cmd.Force(),
+		Author:  cmd.Author(),
+		Message: cmd.Message(),
+	}
+	opts.Headers, _ = c.Flags().GetBool(extension.FlagHeaders)
+	opts.Delete, _ = c.Flags().GetBool(extension.FlagDelete)
+
+	w := cmd.Out()
+	if cmd.JSON() {
+		w = io.Discard
+	}
+
+	l := log.Event("document:join", "join").
+		Author(opts.Author).
+		Path(dest).
+		Detail("sources", sources)
+
+	result, err := join.Run(ctx, w, e.svc, dest, sources, opts)
+	if err != nil {
+		l.Write(err)
+		return cmd.PrintJSONError(fmt.Errorf("join %q: %w", dest, err))
+	}
+
+	l.Resolved(result.Path).
+		Detail("count", len(result.Sources)).
+		Detail("deleted", result.Deleted).
+		Write(nil)
+
+	return cmd.PrintJSON(result)
+}
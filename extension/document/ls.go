@@ -11,9 +11,11 @@ package document
 import (
 	"fmt"
 	"io"
+	"time"
 
 	"github.com/jpl-au/llmd/cmd"
 	"github.com/jpl-au/llmd/extension"
+	"github.com/jpl-au/llmd/internal/duration"
 	"github.com/jpl-au/llmd/internal/log"
 	"github.com/jpl-au/llmd/internal/ls"
 	"github.com/spf13/cobra"
@@ -32,9 +34,18 @@ func (e *Extension) newLsCmd() *cobra.Command {
 	c.Flags().BoolP(extension.FlagTree, "t", false, "Display as tree")
 	c.Flags().BoolP(extension.FlagLong, "l", false, "Long format with metadata")
 	c.Flags().String(extension.FlagTag, "", "Filter by tag")
-	c.Flags().StringP(extension.FlagSort, "s", "", "Sort by: name, time")
+	c.Flags().StringP(extension.FlagSort, "s", "", "Sort by: name, time, size")
 	c.Flags().BoolP(extension.FlagRecursive, "R", false, "List subdirectories recursively")
+	c.Flags().Int(extension.FlagDepth, 0, "Recurse at most this many levels below the prefix (implies -R)")
 	c.Flags().BoolP(extension.FlagReverse, "r", false, "Reverse sort order")
+	c.Flags().Bool(extension.FlagDirsOnly, false, "Show only directories, with document counts")
+	c.Flags().Bool(extension.FlagFilesOnly, false, "Show only document paths")
+	c.Flags().Bool(extension.FlagCountOnly, false, "Print only the number of entries")
+	c.Flags().Int64(extension.FlagMinSize, 0, "Only documents of at least this many bytes")
+	c.Flags().Int64(extension.FlagMaxSize, 0, "Only documents of at most this many bytes")
+	c.Flags().Bool(extension.FlagHuman, false, "Show sizes as 1.2K, 3.4M in long format")
+	c.Flags().String(extension.FlagModifiedSince, "", "Only documents changed after this time (e.g., 24h, 7d, 2024-01-15)")
+	c.Flags().String(extension.FlagAsOf, "", "List documents as they were at this time (e.g., 7d, 2024-01-15)")
 	return c
 }
 
@@ -45,19 +56,57 @@ func (e *Extension) runLs(c *cobra.Command, args []string) error {
 		opts.Prefix = args[0]
 	}
 	opts.Recursive, _ = c.Flags().GetBool(extension.FlagRecursive)
+	opts.Depth, _ = c.Flags().GetInt(extension.FlagDepth)
+	if opts.Depth > 0 {
+		opts.Recursive = true
+	}
 	opts.IncludeAll, _ = c.Flags().GetBool(extension.FlagAll)
 	opts.DeletedOnly, _ = c.Flags().GetBool(extension.FlagDeleted)
 	opts.Tree, _ = c.Flags().GetBool(extension.FlagTree)
 	opts.Long, _ = c.Flags().GetBool(extension.FlagLong)
 	opts.Tag, _ = c.Flags().GetString(extension.FlagTag)
 	opts.Reverse, _ = c.Flags().GetBool(extension.FlagReverse)
+	opts.DirsOnly, _ = c.Flags().GetBool(extension.FlagDirsOnly)
+	opts.FilesOnly, _ = c.Flags().GetBool(extension.FlagFilesOnly)
+	opts.MinSize, _ = c.Flags().GetInt64(extension.FlagMinSize)
+	opts.MaxSize, _ = c.Flags().GetInt64(extension.FlagMaxSize)
+	opts.Human, _ = c.Flags().GetBool(extension.FlagHuman)
+	opts.Delimiter = cmd.Delimiter()
+
+	if since, _ := c.Flags().GetString(extension.FlagModifiedSince); since != "" {
+		t, err := duration.ParseTime(since, time.Now())
+		if err != nil {
+			return cmd.PrintJSONError(err)
+		}
+		opts.Since = t
+	}
+
+	if asOf, _ := c.Flags().GetString(extension.FlagAsOf); asOf != "" {
+		t, err := duration.ParseTime(asOf, time.Now())
+		if err != nil {
+			return cmd.PrintJSONError(err)
+		}
+		opts.AsOf = t
+	}
 
 	sortBy, _ := c.Flags().GetString(extension.FlagSort)
-	if sortBy != "" && sortBy != "name" && sortBy != "time" {
-		return cmd.PrintJSONError(fmt.Errorf("invalid sort field %q: must be 'name' or 'time'", sortBy))
+	if sortBy != "" && sortBy != "name" && sortBy != "time" && sortBy != "size" {
+		return cmd.PrintJSONError(fmt.Errorf("invalid sort field %q: must be 'name', 'time' or 'size'", sortBy))
 	}
 	opts.Sort = ls.SortField(sortBy)
 
+	if countOnly, _ := c.Flags().GetBool(extension.FlagCountOnly); countOnly {
+		n, err := ls.Count(ctx, e.svc, opts)
+		log.Event("document:ls", "count").
+			Author(cmd.Author()).
+			Path(opts.Prefix).
+			Write(err)
+		if err != nil {
+			return cmd.PrintJSONError(fmt.Errorf("ls %q: %w", opts.Prefix, err))
+		}
+		return cmd.PrintCount(n)
+	}
+
 	w := cmd.Out()
 	if cmd.JSON() {
 		w = io.Discard
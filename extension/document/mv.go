@@ -10,28 +10,25 @@
 //
 // The trailing slash on destination signals "move into" rather than "rename to",
 // consistent with how Unix mv interprets directory destinations. References in
-// tags and links are automatically updated to maintain consistency.
+// tags and links are automatically updated to maintain consistency. The moves
+// are applied together: if one fails, none happen. Target mapping and the
+// --dry-run collision check live in internal/mv, shared with the MCP
+// llmd_move tool so both move the same sources to the same places.
 
 package document
 
 import (
 	"fmt"
-	"path"
-	"strings"
 
 	"github.com/jpl-au/llmd/cmd"
+	"github.com/jpl-au/llmd/extension"
 	"github.com/jpl-au/llmd/internal/log"
+	"github.com/jpl-au/llmd/internal/mv"
 	"github.com/spf13/cobra"
 )
 
-// mvResult contains the outcome of a single move operation.
-type mvResult struct {
-	From string `json:"from"`
-	To   string `json:"to"`
-}
-
 func (e *Extension) newMvCmd() *cobra.Command {
-	return &cobra.Command{
+	c := &cobra.Command{
 		Use:   "mv <source>... <dest>",
 		Short: "Move/rename documents",
 		Long: `Rename a document or move multiple documents to a new prefix.
@@ -40,10 +37,15 @@ Single source: mv source dest
 Multiple sources: mv source1 source2 ... dest/
 
 When destination ends with /, sources are moved under that prefix
-preserving their base names (e.g., docs/a -> archive/a).`,
+preserving their base names (e.g., docs/a -> archive/a).
+
+--dry-run lists every planned rename and any that would collide with an
+existing document, without moving anything.`,
 		Args: cobra.MinimumNArgs(2),
 		RunE: e.runMv,
 	}
+	c.Flags().BoolP(extension.FlagDryRun, "n", false, "Show planned moves and collisions without moving")
+	return c
 }
 
 func (e *Extension) runMv(c *cobra.Command, args []string) error {
@@ -53,17 +55,12 @@ func (e *Extension) runMv(c *cobra.Command, args []string) error {
 	sources := args[:len(args)-1]
 	dest := args[len(args)-1]
 
-	if dest == "" {
-		return cmd.PrintJSONError(fmt.Errorf("destination path cannot be empty"))
+	dryRun, _ := c.Flags().GetBool(extension.FlagDryRun)
+	if dryRun {
+		return e.runMvDryRun(c, sources, dest)
 	}
 
-	// Determine if this is a "move into prefix" operation:
-	// - Multiple sources always require prefix mode
-	// - Trailing slash signals prefix mode even with single source
-	prefixMode := len(sources) > 1 || strings.HasSuffix(dest, "/")
-	destPrefix := strings.TrimSuffix(dest, "/")
-
-	var results []mvResult
+	var results []mv.Move
 	l := log.Event("document:mv", "move").Author(cmd.Author())
 	if len(sources) == 1 {
 		l.Path(sources[0])
@@ -73,30 +70,17 @@ func (e *Extension) runMv(c *cobra.Command, args []string) error {
 	l.Detail("dest", dest)
 	defer func() { l.Detail("count", len(results)).Write(nil) }()
 
-	for _, src := range sources {
-		if src == "" {
-			return cmd.PrintJSONError(fmt.Errorf("source path cannot be empty"))
-		}
-
-		var target string
-		if prefixMode {
-			// Move into prefix: docs/readme -> archive/readme
-			base := path.Base(src)
-			target = path.Join(destPrefix, base)
-		} else {
-			// Direct rename: source -> dest
-			target = dest
-		}
-
-		err := e.svc.Move(ctx, src, target)
-		if err != nil {
-			return cmd.PrintJSONError(fmt.Errorf("mv %q to %q: %w", src, target, err))
-		}
-
-		results = append(results, mvResult{From: src, To: target})
+	// All moves apply in one transaction, so a collision part way through
+	// leaves every source where it was.
+	moves, err := mv.Run(ctx, e.svc, sources, dest)
+	if err != nil {
+		return cmd.PrintJSONError(fmt.Errorf("mv: %w", err))
+	}
+	results = moves
 
-		if !cmd.JSON() {
-			fmt.Fprintf(cmd.Out(), "Moved %s -> %s\n", src, target)
+	if !cmd.JSON() {
+		for _, m := range moves {
+			fmt.Fprintf(cmd.Out(), "Moved %s -> %s\n", m.From, m.To)
 		}
 	}
 
@@ -106,3 +90,30 @@ func (e *Extension) runMv(c *cobra.Command, args []string) error {
 	}
 	return cmd.PrintJSON(results)
 }
+
+// runMvDryRun reports the planned moves and their collisions.
+func (e *Extension) runMvDryRun(c *cobra.Command, sources []string, dest string) error {
+	plan, err := mv.NewPlan(c.Context(), e.svc, sources, dest)
+	if err != nil {
+		return cmd.PrintJSONError(err)
+	}
+
+	if !cmd.JSON() {
+		reasons := make(map[mv.Move]string, len(plan.Collisions))
+		for _, col := range plan.Collisions {
+			reasons[mv.Move{From: col.From, To: col.To}] = col.Reason
+		}
+		w := cmd.Out()
+		for _, m := range plan.Moves {
+			if r, ok := reasons[m]; ok {
+				fmt.Fprintf(w, "Would fail: %s -> %s (%s)\n", m.From, m.To, r)
+				continue
+			}
+			fmt.Fprintf(w, "Would move: %s -> %s\n", m.From, m.To)
+		}
+		if n := len(plan.Collisions); n > 0 {
+			fmt.Fprintf(w, "%d of %d moves would fail\n", n, len(plan.Moves))
+		}
+	}
+	return cmd.PrintJSON(plan)
+}
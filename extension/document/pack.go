@@ -0,0 +1,101 @@
+// pack.go implements the "llmd pack" command for assembling context under a
+// token budget.
+//
+// Separated from document.go to isolate packing logic.
+//
+// Design: The bundle goes to stdout on its own, so it can be piped or
+// pasted straight into an assistant; what was included, truncated or left
+// out goes to stderr. Selection composes the existing read primitives:
+// paths, keys, globs and prefixes as wc and tokens take them, and
+// full-text matches ranked by relevance.
+
+package document
+
+import (
+	"fmt"
+	"io"
+	"os"
+	"strings"
+
+	"github.com/jpl-au/llmd/cmd"
+	"github.com/jpl-au/llmd/extension"
+	"github.com/jpl-au/llmd/internal/log"
+	"github.com/jpl-au/llmd/internal/pack"
+	"github.com/jpl-au/llmd/internal/tokens"
+	"github.com/spf13/cobra"
+)
+
+func (e *Extension) newPackCmd() *cobra.Command {
+	c := &cobra.Command{
+		Use:   "pack [path|key|pattern|prefix/]... --budget <tokens>",
+		Short: "Bundle documents as LLM context under a token budget",
+		Long: `Concatenate documents into one bundle that fits a token budget, ready
+to hand to an assistant. Each document is wrapped in
+<document path="..."><![CDATA[ ... ]]></document>, with the path escaped,
+so no content can end its wrapper early.
+
+Documents named as arguments come first, in order, then those matching
+--search, most relevant first. A document that does not fit is skipped;
+with --truncate it is cut at a heading instead, keeping its leading
+sections. Token counts are estimates (see "llmd tokens").`,
+		Args: cobra.ArbitraryArgs,
+		RunE: e.runPack,
+	}
+	c.Flags().Int(extension.FlagBudget, 0, "Token limit for the bundle (required)")
+	c.Flags().String(extension.FlagSearch, "", "Add documents matching this full-text query, most relevant first")
+	c.Flags().Bool(extension.FlagTruncate, false, "Cut documents that do not fit at a section boundary")
+	c.Flags().String(extension.FlagMethod, tokens.DefaultMethod, "Estimation method: "+strings.Join(tokens.Methods(), ", "))
+	return c
+}
+
+func (e *Extension) runPack(c *cobra.Command, args []string) error {
+	var opts pack.Options
+	opts.Budget, _ = c.Flags().GetInt(extension.FlagBudget)
+	opts.Search, _ = c.Flags().GetString(extension.FlagSearch)
+	opts.Truncate, _ = c.Flags().GetBool(extension.FlagTruncate)
+	opts.Method, _ = c.Flags().GetString(extension.FlagMethod)
+
+	w := cmd.Out()
+	if cmd.JSON() {
+		w = io.Discard
+	}
+
+	l := log.Event("document:pack", "read").
+		Author(cmd.Author()).
+		Detail("targets", args).
+		Detail("search", opts.Search).
+		Detail("budget", opts.Budget)
+
+	result, err := pack.Run(c.Context(), w, e.svc, args, opts)
+	if err != nil {
+		l.Write(err)
+		return cmd.PrintJSONError(fmt.Errorf("pack: %w", err))
+	}
+
+	l.Detail("count", len(result.Documents)).
+		Detail("tokens", result.Tokens).
+		Write(nil)
+
+	if !cmd.JSON() {
+		summarise(os.Stderr, result)
+	}
+	return cmd.PrintJSON(result)
+}
+
+// summarise reports what went into the bundle.
+func summarise(w io.Writer, r pack.Result) {
+	truncated := 0
+	for _, p := range r.Documents {
+		if p.Truncated {
+			truncated++
+		}
+	}
+	fmt.Fprintf(w, "Packed %d document(s), about %d of %d tokens", len(r.Documents), r.Tokens, r.Budget)
+	if truncated > 0 {
+		fmt.Fprintf(w, ", %d truncated", truncated)
+	}
+	fmt.Fprintln(w)
+	if len(r.Skipped) > 0 {
+		fmt.Fprintf(w, "Skipped (over budget): %s\n", strings.Join(r.Skipped, ", "))
+	}
+}
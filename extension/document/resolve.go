@@ -0,0 +1,63 @@
+// resolve.go implements the "llmd resolve" command, which shows how a
+// path-or-key argument is interpreted.
+//
+// Separated from cat.go because it reads no content: it exists to debug the
+// case where an 8-character path shadows a version key.
+
+package document
+
+import (
+	"fmt"
+	"io"
+
+	"github.com/jpl-au/llmd/cmd"
+	"github.com/jpl-au/llmd/extension"
+	"github.com/jpl-au/llmd/internal/log"
+	"github.com/jpl-au/llmd/internal/resolve"
+	"github.com/spf13/cobra"
+)
+
+func (e *Extension) newResolveCmd() *cobra.Command {
+	c := &cobra.Command{
+		Use:   "resolve <path|key>",
+		Short: "Show how a path or key is interpreted",
+		Long: `Show how an argument given as <path|key> is interpreted: the document
+and version it selects, and whether it was taken as a path or a key.
+
+An 8-character input could be either. The path wins, so a document whose
+path looks like a key hides that key; resolve reports when this happens.
+
+  llmd resolve docs/readme
+  llmd resolve a1b2c3d4 -o json`,
+		Args: cobra.ExactArgs(1),
+		RunE: e.runResolve,
+	}
+	c.Flags().BoolP(extension.FlagDeleted, "D", false, "Resolve deleted documents too")
+	return c
+}
+
+func (e *Extension) runResolve(c *cobra.Command, args []string) error {
+	input := args[0]
+	del, _ := c.Flags().GetBool(extension.FlagDeleted)
+
+	w := cmd.Out()
+	if cmd.JSON() {
+		w = io.Discard
+	}
+
+	l := log.Event("document:resolve", "resolve").
+		Author(cmd.Author()).
+		Path(input)
+
+	result, err := resolve.Run(c.Context(), w, e.svc, input, del)
+	if err != nil {
+		l.Write(err)
+		return cmd.PrintJSONError(fmt.Errorf("resolve %q: %w", input, err))
+	}
+
+	l.Resolved(result.ResolvedPath).
+		Detail("interpreted_as", result.InterpretedAs).
+		Write(nil)
+
+	return cmd.PrintJSON(result)
+}
@@ -31,11 +31,12 @@ This is a forward-moving operation - it preserves history by creating a new vers
 rather than deleting versions.
 
 The target can be specified as:
-  - A path and version number: llmd revert docs/api 3
+  - A path and version number: llmd revert docs/api 3 (or -v 3)
   - A key (8-char identifier): llmd revert --key abc12345`,
 		Args: cobra.MaximumNArgs(2),
 		RunE: e.runRevert,
 	}
+	c.Flags().IntP(extension.FlagVersion, "v", 0, "Version number to revert to")
 	c.Flags().StringP(extension.FlagKey, "k", "", "Revert to version by key (8-char identifier)")
 	return c
 }
@@ -43,6 +44,7 @@ The target can be specified as:
 func (e *Extension) runRevert(c *cobra.Command, args []string) error {
 	ctx := c.Context()
 	keyFlag, _ := c.Flags().GetString(extension.FlagKey)
+	version, _ := c.Flags().GetInt(extension.FlagVersion)
 
 	if len(args) == 0 && keyFlag == "" {
 		return cmd.PrintJSONError(fmt.Errorf("requires either a path argument or --key flag"))
@@ -52,16 +54,18 @@ func (e *Extension) runRevert(c *cobra.Command, args []string) error {
 	if len(args) > 0 {
 		target = args[0]
 	}
-	version := 0
 
 	if len(args) == 2 {
+		if c.Flags().Changed(extension.FlagVersion) {
+			return cmd.PrintJSONError(fmt.Errorf("version given both as argument and --version"))
+		}
 		_, err := fmt.Sscanf(args[1], "%d", &version)
 		if err != nil {
 			return cmd.PrintJSONError(fmt.Errorf("invalid version %q: must be a number", args[1]))
 		}
-		if version < 1 {
-			return cmd.PrintJSONError(fmt.Errorf("version must be >= 1, got %d", version))
-		}
+	}
+	if (len(args) == 2 || c.Flags().Changed(extension.FlagVersion)) && version < 1 {
+		return cmd.PrintJSONError(fmt.Errorf("version must be >= 1, got %d", version))
 	}
 
 	opts := revert.Options{
@@ -9,6 +9,11 @@
 //
 // Supports Unix rm semantics with multiple path arguments. The --key and
 // --version flags are restricted to single-path operations to avoid ambiguity.
+//
+// --glob and --tag select documents instead of paths and delete them in one
+// transaction. Because a loose pattern can match far more than intended,
+// selections over rm.ConfirmThreshold need --yes, and --dry-run lists them
+// without deleting.
 
 package document
 
@@ -30,13 +35,21 @@ func (e *Extension) newRmCmd() *cobra.Command {
 		Long: `Soft-delete one or more documents (recoverable via restore).
 
 Multiple paths can be specified to delete several documents at once.
-The --key and --version flags only work with a single path.`,
+The --key and --version flags only work with a single path.
+
+--glob and --tag delete every matching document in one transaction.
+Deleting more than ` + fmt.Sprint(rm.ConfirmThreshold) + ` documents this way requires --yes;
+use --dry-run to list them first.`,
 		Args: cobra.ArbitraryArgs,
 		RunE: e.runRm,
 	}
 	c.Flags().BoolP(extension.FlagRecursive, "r", false, "Delete all documents under path")
 	c.Flags().Int(extension.FlagVersion, 0, "Delete only this specific version")
 	c.Flags().StringP(extension.FlagKey, "k", "", "Delete by version key (8-char identifier)")
+	c.Flags().String(extension.FlagGlob, "", "Delete all documents matching a path glob")
+	c.Flags().String(extension.FlagTag, "", "Delete all documents with a tag")
+	c.Flags().BoolP(extension.FlagDryRun, "n", false, "List the documents --glob or --tag would delete")
+	c.Flags().BoolP(extension.FlagYes, "y", false, "Confirm a --glob or --tag delete over the threshold")
 	return c
 }
 
@@ -45,6 +58,15 @@ func (e *Extension) runRm(c *cobra.Command, args []string) error {
 	recursive, _ := c.Flags().GetBool(extension.FlagRecursive)
 	version, _ := c.Flags().GetInt(extension.FlagVersion)
 	keyFlag, _ := c.Flags().GetString(extension.FlagKey)
+	globFlag, _ := c.Flags().GetString(extension.FlagGlob)
+	tagFlag, _ := c.Flags().GetString(extension.FlagTag)
+
+	if globFlag != "" || tagFlag != "" {
+		if len(args) > 0 || keyFlag != "" || version > 0 || recursive {
+			return cmd.PrintJSONError(fmt.Errorf("--glob and --tag cannot be used with paths, --key, --version or --recursive"))
+		}
+		return e.runRmBulk(c, globFlag, tagFlag)
+	}
 
 	if len(args) == 0 && keyFlag == "" {
 		return cmd.PrintJSONError(fmt.Errorf("requires either a path argument or --key flag"))
@@ -116,3 +138,30 @@ func (e *Extension) runRm(c *cobra.Command, args []string) error {
 
 	return cmd.PrintJSON(results)
 }
+
+// runRmBulk deletes the documents selected by --glob and --tag.
+func (e *Extension) runRmBulk(c *cobra.Command, glob, tag string) error {
+	opts := rm.BulkOptions{Glob: glob, Tag: tag}
+	opts.DryRun, _ = c.Flags().GetBool(extension.FlagDryRun)
+	opts.Yes, _ = c.Flags().GetBool(extension.FlagYes)
+
+	w := cmd.Out()
+	if cmd.JSON() {
+		w = io.Discard
+	}
+
+	l := log.Event("document:rm", "delete").
+		Author(cmd.Author()).
+		Detail("glob", glob).
+		Detail("tag", tag).
+		Detail("dry_run", opts.DryRun)
+
+	result, err := rm.RunBulk(c.Context(), w, e.svc, opts)
+	if err != nil {
+		l.Write(err)
+		return cmd.PrintJSONError(fmt.Errorf("rm: %w", err))
+	}
+
+	l.Detail("count", result.Count).Write(nil)
+	return cmd.PrintJSON(result)
+}
@@ -0,0 +1,56 @@
+// sections.go implements the "llmd sections" command for listing a
+// document's heading outline.
+//
+// Separated from cat.go because it returns structure rather than content:
+// headings and line ranges only, so the output stays small however large
+// the document is.
+
+package document
+
+import (
+	"io"
+
+	"github.com/jpl-au/llmd/cmd"
+	"github.com/jpl-au/llmd/internal/log"
+	"github.com/jpl-au/llmd/internal/outline"
+	"github.com/spf13/cobra"
+)
+
+func (e *Extension) newSectionsCmd() *cobra.Command {
+	return &cobra.Command{
+		Use:   "sections <path|key>",
+		Short: "List a document's headings with line ranges",
+		Long: `List a document's markdown headings as an outline, with the lines each
+section spans. Use it to find a section before "cat --section",
+"edit --section" or a line-range edit.
+
+  llmd sections docs/readme
+  llmd sections docs/readme -o json   # nested outline`,
+		Args: cobra.ExactArgs(1),
+		RunE: e.runSections,
+	}
+}
+
+func (e *Extension) runSections(c *cobra.Command, args []string) error {
+	ctx := c.Context()
+	path := args[0]
+
+	w := cmd.Out()
+	if cmd.JSON() {
+		w = io.Discard
+	}
+
+	l := log.Event("document:sections", "sections").
+		Author(cmd.Author()).
+		Path(path)
+
+	result, err := outline.Run(ctx, w, e.svc, path)
+	if err != nil {
+		l.Write(err)
+		return cmd.PrintJSONError(err)
+	}
+
+	l.Resolved(result.Path).Write(nil)
+
+	return cmd.PrintJSON(result)
+}
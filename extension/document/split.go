@@ -0,0 +1,81 @@
+// split.go implements the "llmd split" command for breaking up large documents.
+//
+// Separated from document.go to isolate the split flags and output.
+//
+// Design: The splitting itself lives in internal/split so MCP could share it.
+// Sections are ordinary documents written through the service, so they are
+// versioned, searchable and linkable like anything else.
+
+package document
+
+import (
+	"fmt"
+	"io"
+
+	"github.com/jpl-au/llmd/cmd"
+	"github.com/jpl-au/llmd/extension"
+	"github.com/jpl-au/llmd/internal/log"
+	"github.com/jpl-au/llmd/internal/split"
+	"github.com/spf13/cobra"
+)
+
+func (e *Extension) newSplitCmd() *cobra.Command {
+	c := &cobra.Command{
+		Use:   "split <path>",
+		Short: "Split a document into one document per section",
+		Long: `Split a markdown document at its headings into separate documents.
+
+Each section is written as <prefix>/section-N (the prefix defaults to the
+document's own path) and linked to the original with the tag "part-of".
+Text before the first heading becomes a section of its own.
+
+  llmd split docs/big                 # split at ## headings
+  llmd split docs/big --level 1       # split at # headings
+  llmd split docs/big --to docs/parts # write docs/parts/section-N
+  llmd split docs/big --delete        # soft-delete the original afterwards`,
+		Args: cobra.ExactArgs(1),
+		RunE: e.runSplit,
+	}
+	c.Flags().Int(extension.FlagLevel, split.DefaultLevel, "Split at headings of this level or higher (1-6)")
+	c.Flags().String(extension.FlagTo, "", "Prefix for the section documents (default: the document's path)")
+	c.Flags().Bool(extension.FlagDelete, false, "Soft-delete the original after splitting")
+	c.Flags().BoolP(extension.FlagDryRun, "n", false, "Show the sections without writing them")
+	return c
+}
+
+func (e *Extension) runSplit(c *cobra.Command, args []string) error {
+	ctx := c.Context()
+
+	opts := split.Options{
+		Author:  cmd.Author(),
+		Message: cmd.Message(),
+	}
+	opts.Level, _ = c.Flags().GetInt(extension.FlagLevel)
+	opts.Prefix, _ = c.Flags().GetString(extension.FlagTo)
+	opts.Delete, _ = c.Flags().GetBool(extension.FlagDelete)
+	opts.DryRun, _ = c.Flags().GetBool(extension.FlagDryRun)
+
+	w := cmd.Out()
+	if cmd.JSON() {
+		w = io.Discard
+	}
+
+	l := log.Event("document:split", "split").
+		Author(opts.Author).
+		Path(args[0]).
+		Detail("level", opts.Level).
+		Detail("dry_run", opts.DryRun)
+
+	result, err := split.Run(ctx, w, e.svc, args[0], opts)
+	if err != nil {
+		l.Write(err)
+		return cmd.PrintJSONError(fmt.Errorf("split %q: %w", args[0], err))
+	}
+
+	l.Resolved(result.Path).
+		Detail("sections", len(result.Sections)).
+		Detail("deleted", result.Deleted).
+		Write(nil)
+
+	return cmd.PrintJSON(result)
+}
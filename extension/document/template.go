@@ -0,0 +1,110 @@
+// template.go implements "llmd new" and "llmd template" for document templates.
+//
+// Separated from document.go to keep the template flags and listing together.
+//
+// Design: Templates are plain documents under .templates/, so there is no
+// "template add" - they are written, edited and removed like any other
+// document. The substitution lives in internal/template.
+
+package document
+
+import (
+	"fmt"
+	"io"
+
+	"github.com/jpl-au/llmd/cmd"
+	"github.com/jpl-au/llmd/extension"
+	"github.com/jpl-au/llmd/internal/log"
+	"github.com/jpl-au/llmd/internal/template"
+	"github.com/spf13/cobra"
+)
+
+func (e *Extension) newNewCmd() *cobra.Command {
+	c := &cobra.Command{
+		Use:   "new <path> --template <name>",
+		Short: "Create a document from a template",
+		Long: `Create a document by copying a template stored under .templates/.
+
+Placeholders in the template are filled in as the document is created:
+{{date}}, {{time}}, {{author}}, {{path}} and {{title}} (the last element
+of the path). Unknown placeholders are left as they are.
+
+  llmd write .templates/meeting < meeting.md
+  llmd new docs/notes/today --template meeting
+
+Refuses to overwrite an existing document unless --force is given.`,
+		Args: cobra.ExactArgs(1),
+		RunE: e.runNew,
+	}
+	c.Flags().String(extension.FlagTemplate, "", "Template to copy (see 'llmd template ls')")
+	return c
+}
+
+func (e *Extension) runNew(c *cobra.Command, args []string) error {
+	ctx := c.Context()
+
+	opts := template.Options{
+		Author:  cmd.Author(),
+		Message: cmd.Message(),
+		Force:   cmd.Force(),
+	}
+	opts.Template, _ = c.Flags().GetString(extension.FlagTemplate)
+
+	w := cmd.Out()
+	if cmd.JSON() {
+		w = io.Discard
+	}
+
+	l := log.Event("document:new", "new").
+		Author(opts.Author).
+		Path(args[0]).
+		Detail("template", opts.Template)
+
+	result, err := template.Run(ctx, w, e.svc, args[0], opts)
+	if err != nil {
+		l.Write(err)
+		return cmd.PrintJSONError(fmt.Errorf("new %q: %w", args[0], err))
+	}
+
+	l.Resolved(result.Path).Write(nil)
+
+	return cmd.PrintJSON(result)
+}
+
+func (e *Extension) newTemplateCmd() *cobra.Command {
+	c := &cobra.Command{
+		Use:   "template",
+		Short: "Manage document templates",
+		Long: `Templates are documents stored under .templates/. Write, edit and remove
+them with the usual commands; use 'llmd new' to create a document from one.`,
+	}
+	c.AddCommand(&cobra.Command{
+		Use:   "ls",
+		Short: "List available templates",
+		Args:  cobra.NoArgs,
+		RunE:  e.runTemplateLs,
+	})
+	return c
+}
+
+func (e *Extension) runTemplateLs(c *cobra.Command, _ []string) error {
+	ctx := c.Context()
+
+	w := cmd.Out()
+	if cmd.JSON() {
+		w = io.Discard
+	}
+
+	l := log.Event("document:template_ls", "list_templates").
+		Author(cmd.Author())
+
+	result, err := template.List(ctx, w, e.svc)
+	if err != nil {
+		l.Write(err)
+		return cmd.PrintJSONError(fmt.Errorf("template ls: %w", err))
+	}
+
+	l.Detail("count", len(result.Templates)).Write(nil)
+
+	return cmd.PrintJSON(result)
+}
@@ -0,0 +1,71 @@
+// tokens.go implements the "llmd tokens" command for estimating LLM token
+// counts.
+//
+// Separated from document.go to isolate estimation logic.
+//
+// Design: Reports an estimate per document and a total, so a user can
+// judge whether a document or a set of them fits a model's context window
+// before handing it over. --budget states the window and adds whether the
+// total fits; the command still succeeds either way, as the estimate is
+// approximate and the decision is the caller's.
+
+package document
+
+import (
+	"fmt"
+	"io"
+	"strings"
+
+	"github.com/jpl-au/llmd/cmd"
+	"github.com/jpl-au/llmd/extension"
+	"github.com/jpl-au/llmd/internal/log"
+	"github.com/jpl-au/llmd/internal/tokens"
+	"github.com/spf13/cobra"
+)
+
+func (e *Extension) newTokensCmd() *cobra.Command {
+	c := &cobra.Command{
+		Use:   "tokens <path|key|pattern|prefix/>...",
+		Short: "Estimate LLM token counts",
+		Long: `Estimate how many LLM tokens each document takes, and the total.
+Arguments may be paths, keys, glob patterns (quote them) or prefixes ending
+in /.
+
+Counts are estimates: each model's tokenizer differs. The default method,
+text, charges runs of letters and digits about one token per four
+characters and punctuation or non-ASCII characters one token each.
+--budget reports whether the total fits a context window of that size.`,
+		Args: cobra.MinimumNArgs(1),
+		RunE: e.runTokens,
+	}
+	c.Flags().String(extension.FlagMethod, tokens.DefaultMethod, "Estimation method: "+strings.Join(tokens.Methods(), ", "))
+	c.Flags().Int(extension.FlagBudget, 0, "Report whether the total fits in this many tokens")
+	return c
+}
+
+func (e *Extension) runTokens(c *cobra.Command, args []string) error {
+	var opts tokens.Options
+	opts.Method, _ = c.Flags().GetString(extension.FlagMethod)
+	opts.Budget, _ = c.Flags().GetInt(extension.FlagBudget)
+
+	w := cmd.Out()
+	if cmd.JSON() {
+		w = io.Discard
+	}
+
+	l := log.Event("document:tokens", "read").
+		Author(cmd.Author()).
+		Detail("targets", args).
+		Detail("method", opts.Method)
+
+	result, err := tokens.Run(c.Context(), w, e.svc, args, opts)
+	if err != nil {
+		l.Write(err)
+		return cmd.PrintJSONError(fmt.Errorf("tokens: %w", err))
+	}
+
+	l.Detail("count", len(result.Documents)).
+		Detail("total", result.Total).
+		Write(nil)
+	return cmd.PrintJSON(result)
+}
@@ -0,0 +1,62 @@
+// undo.go implements the "llmd undo" command for reverting the latest change.
+//
+// Separated from revert.go because undo takes no version argument - it
+// always targets the version before the latest.
+//
+// Design: Undo is a revert to latest-1, so it is forward-moving like revert.
+// Running undo twice therefore returns to the original content rather than
+// stepping further back, mirroring how most editors treat undo of an undo.
+
+package document
+
+import (
+	"io"
+
+	"github.com/jpl-au/llmd/cmd"
+	"github.com/jpl-au/llmd/internal/log"
+	"github.com/jpl-au/llmd/internal/revert"
+	"github.com/spf13/cobra"
+)
+
+func (e *Extension) newUndoCmd() *cobra.Command {
+	return &cobra.Command{
+		Use:   "undo <path|key>",
+		Short: "Revert a document's most recent change",
+		Long: `Revert a document to the version before its latest by creating a new version
+with that content. Equivalent to "llmd revert <path> <latest-1>".`,
+		Args: cobra.ExactArgs(1),
+		RunE: e.runUndo,
+	}
+}
+
+func (e *Extension) runUndo(c *cobra.Command, args []string) error {
+	ctx := c.Context()
+	target := args[0]
+
+	opts := revert.Options{
+		Author:  cmd.Author(),
+		Message: cmd.Message(),
+	}
+
+	w := cmd.Out()
+	if cmd.JSON() {
+		w = io.Discard
+	}
+
+	l := log.Event("document:undo", "undo").
+		Author(cmd.Author()).
+		Path(target)
+
+	result, err := revert.Undo(ctx, w, e.svc, target, opts)
+	if err != nil {
+		l.Write(err)
+		return cmd.PrintJSONError(err)
+	}
+
+	l.Resolved(result.Path).
+		Version(result.RevertedTo).
+		ResultVersion(result.NewVersion).
+		Write(nil)
+
+	return cmd.PrintJSON(result)
+}
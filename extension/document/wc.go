@@ -0,0 +1,71 @@
+// wc.go implements the "llmd wc" command for counting lines, words and
+// characters.
+//
+// Separated from document.go to isolate counting logic.
+//
+// Design: Mirrors Unix wc: -l, -w and -c select columns, all three by
+// default, with a total line for several documents. Counts come from the
+// stored content, so a token budget or reading time can be estimated
+// without piping cat into external tools.
+
+package document
+
+import (
+	"fmt"
+	"io"
+
+	"github.com/jpl-au/llmd/cmd"
+	"github.com/jpl-au/llmd/extension"
+	"github.com/jpl-au/llmd/internal/log"
+	"github.com/jpl-au/llmd/internal/wc"
+	"github.com/spf13/cobra"
+)
+
+func (e *Extension) newWcCmd() *cobra.Command {
+	c := &cobra.Command{
+		Use:   "wc <path|key|pattern|prefix/>...",
+		Short: "Count lines, words and characters",
+		Long: `Print line, word and character counts for each document, and a total
+for several. Arguments may be paths, keys, glob patterns (quote them) or
+prefixes ending in /.
+
+Lines are counted as cat -n numbers them, so a last line without a
+newline counts. Characters are Unicode characters, not bytes.`,
+		Args: cobra.MinimumNArgs(1),
+		RunE: e.runWc,
+	}
+	c.Flags().BoolP(extension.FlagLines, "l", false, "Print line counts")
+	c.Flags().BoolP(extension.FlagWords, "w", false, "Print word counts")
+	c.Flags().BoolP(extension.FlagChars, "c", false, "Print character counts")
+	return c
+}
+
+func (e *Extension) runWc(c *cobra.Command, args []string) error {
+	var opts wc.Options
+	opts.Lines, _ = c.Flags().GetBool(extension.FlagLines)
+	opts.Words, _ = c.Flags().GetBool(extension.FlagWords)
+	opts.Chars, _ = c.Flags().GetBool(extension.FlagChars)
+
+	w := cmd.Out()
+	if cmd.JSON() {
+		w = io.Discard
+	}
+
+	l := log.Event("document:wc", "read").
+		Author(cmd.Author()).
+		Detail("targets", args)
+
+	counts, err := wc.Run(c.Context(), w, e.svc, args, opts)
+	if err != nil {
+		l.Write(err)
+		return cmd.PrintJSONError(fmt.Errorf("wc: %w", err))
+	}
+
+	l.Detail("count", len(counts)).Write(nil)
+
+	// Return single object for one document, array for several
+	if len(counts) == 1 {
+		return cmd.PrintJSON(counts[0])
+	}
+	return cmd.PrintJSON(counts)
+}
@@ -7,17 +7,25 @@
 // 2. File flag (for existing files)
 // 3. Stdin (for piping)
 // This flexibility supports both interactive and scripted workflows.
+//
+// With --set, {{key}} placeholders are expanded before the content is stored,
+// using the same builtins as templates (internal/template). Expansion only
+// happens when asked for, so writing a template itself leaves it intact.
 
 package document
 
 import (
 	"fmt"
 	"io"
+	"maps"
 	"os"
+	"strings"
+	"time"
 
 	"github.com/jpl-au/llmd/cmd"
 	"github.com/jpl-au/llmd/extension"
 	"github.com/jpl-au/llmd/internal/log"
+	"github.com/jpl-au/llmd/internal/template"
 	"github.com/spf13/cobra"
 	"golang.org/x/term"
 )
@@ -31,11 +39,26 @@ func (e *Extension) newWriteCmd() *cobra.Command {
 	c := &cobra.Command{
 		Use:   "write <path> [content]",
 		Short: "Write a document",
-		Long:  `Create or update a document. Content from argument, stdin, or -f flag.`,
-		Args:  cobra.RangeArgs(1, 2),
-		RunE:  e.runWrite,
+		Long: `Create or update a document. Content from argument, stdin, or -f flag.
+
+--set key=value expands {{key}} in the content before it is stored. The
+builtins {{date}}, {{time}}, {{author}}, {{path}} and {{title}} are also
+available once --set or --strict is given; --set can override them.
+
+  llmd write docs/release --set version=1.4 < release.md
+  llmd write docs/release --set version=1.4 --strict < release.md
+
+--content-type records a type other than markdown. Later writes keep it
+until another type is given.
+
+  llmd write config/ci -f ci.json --content-type application/json`,
+		Args: cobra.RangeArgs(1, 2),
+		RunE: e.runWrite,
 	}
 	c.Flags().StringP(extension.FlagFile, "f", "", "Read content from file")
+	c.Flags().StringArray(extension.FlagSet, nil, "Expand {{key}} to value (key=value, repeatable)")
+	c.Flags().Bool(extension.FlagStrict, false, "Fail if any {{placeholder}} is left unexpanded")
+	c.Flags().String(extension.FlagContentType, "", "MIME type of the content (default: keep the current type, text/markdown for new documents)")
 	return c
 }
 
@@ -69,7 +92,23 @@ func (e *Extension) runWrite(c *cobra.Command, args []string) error {
 		return cmd.PrintJSONError(fmt.Errorf("content is empty"))
 	}
 
-	err := e.svc.Write(ctx, path, content, cmd.Author(), cmd.Message())
+	sets, _ := c.Flags().GetStringArray(extension.FlagSet)
+	strict, _ := c.Flags().GetBool(extension.FlagStrict)
+	if len(sets) > 0 || strict {
+		vars, err := template.ParseVars(sets)
+		if err != nil {
+			return cmd.PrintJSONError(err)
+		}
+		builtins := template.Vars(path, cmd.Author(), time.Now())
+		maps.Copy(builtins, vars)
+		content = template.Expand(content, builtins)
+		if left := template.Unresolved(content); strict && len(left) > 0 {
+			return cmd.PrintJSONError(fmt.Errorf("unresolved placeholders: %s (use --set key=value)", strings.Join(left, ", ")))
+		}
+	}
+
+	contentType, _ := c.Flags().GetString(extension.FlagContentType)
+	err := e.svc.WriteType(ctx, path, content, contentType, cmd.Author(), cmd.Message())
 
 	log.Event("document:write", "write").
 		Author(cmd.Author()).
@@ -1,5 +1,5 @@
 // Package edit provides the edit extension for llmd.
-// It registers commands: edit, sed.
+// It registers commands: edit, sed, patch.
 package edit
 
 import (
@@ -13,6 +13,7 @@ import (
 	"github.com/jpl-au/llmd/extension"
 	"github.com/jpl-au/llmd/internal/edit"
 	"github.com/jpl-au/llmd/internal/log"
+	"github.com/jpl-au/llmd/internal/patch"
 	"github.com/jpl-au/llmd/internal/sed"
 	"github.com/jpl-au/llmd/internal/service"
 	"github.com/spf13/cobra"
@@ -43,11 +44,12 @@ func (e *Extension) Init(ctx extension.Context) error {
 	return nil
 }
 
-// Commands returns edit and sed commands for document modification.
+// Commands returns edit, sed and patch commands for document modification.
 func (e *Extension) Commands() []*cobra.Command {
 	return []*cobra.Command{
 		e.newEditCmd(),
 		e.newSedCmd(),
+		e.newPatchCmd(),
 	}
 }
 
@@ -70,31 +72,73 @@ Search/replace mode (positional or flags):
   llmd edit docs/readme -i "OLD TEXT" "new text"  # case-insensitive
 
 Line range mode (replaces lines with stdin):
-  llmd edit docs/readme -l 5:10 <<< "replacement content"`,
+  llmd edit docs/readme -l 5:10 <<< "replacement content"
+  llmd edit docs/readme -l 5:10 -n <<< "replacement"  # preview only
+
+Section mode (replaces a heading and its body with stdin):
+  llmd edit docs/readme --section '## Installation' < install.md
+
+Append/prepend mode (adds stdin without resending the document):
+  llmd edit docs/readme --append <<< "## New section"
+  llmd edit docs/readme --prepend <<< "# Title"
+
+Insert mode (adds stdin before line N, replacing nothing):
+  llmd edit docs/readme --insert-at 5 <<< "New paragraph"`,
 		Args: cobra.RangeArgs(1, 3),
 		RunE: e.runEdit,
 	}
 	c.Flags().String(extension.FlagOld, "", "Text to find")
 	c.Flags().String(extension.FlagNew, "", "Text to replace with")
 	c.Flags().StringP(extension.FlagLines, "l", "", "Line range (e.g., 5:10)")
+	c.Flags().String(extension.FlagSection, "", "Replace the section under this heading (e.g., '## Install')")
 	c.Flags().BoolP(extension.FlagIgnoreCase, "i", false, "Case-insensitive matching")
+	c.Flags().Bool(extension.FlagAppend, false, "Append stdin to the end of the document")
+	c.Flags().Bool(extension.FlagPrepend, false, "Prepend stdin to the start of the document")
+	c.Flags().Int(extension.FlagInsertAt, 0, "Insert stdin before line N (1-indexed)")
+	c.Flags().BoolP(extension.FlagDryRun, "n", false, "Preview a line edit without writing")
+	c.MarkFlagsMutuallyExclusive(extension.FlagLines, extension.FlagSection, extension.FlagAppend, extension.FlagPrepend, extension.FlagInsertAt)
 	return c
 }
 
 func (e *Extension) runEdit(c *cobra.Command, args []string) error {
 	ctx := c.Context()
 	lineRange, _ := c.Flags().GetString(extension.FlagLines)
+	section, _ := c.Flags().GetString(extension.FlagSection)
+	appendMode, _ := c.Flags().GetBool(extension.FlagAppend)
+	prependMode, _ := c.Flags().GetBool(extension.FlagPrepend)
+	insertAt, _ := c.Flags().GetInt(extension.FlagInsertAt)
+	insertMode := c.Flags().Changed(extension.FlagInsertAt)
+	dryRun, _ := c.Flags().GetBool(extension.FlagDryRun)
 	path := args[0]
 
+	if dryRun && lineRange == "" && section == "" && !appendMode && !prependMode && !insertMode {
+		return cmd.PrintJSONError(errors.New("--dry-run requires -l/--lines, --section, --append, --prepend or --insert-at"))
+	}
+	if insertMode && insertAt < 1 {
+		return cmd.PrintJSONError(fmt.Errorf("--insert-at must be >= 1, got %d", insertAt))
+	}
+
 	l := log.Event("edit:edit", "edit").
 		Author(cmd.Author()).
 		Path(path)
+	if dryRun {
+		l.Detail("dry_run", true)
+	}
 
 	var result edit.Result
 	var err error
-	if lineRange != "" {
-		result, err = e.runEditLineRange(ctx, path, lineRange)
-	} else {
+	switch {
+	case appendMode:
+		result, err = e.runEditBoundary(ctx, path, edit.ModeAppend, dryRun)
+	case prependMode:
+		result, err = e.runEditBoundary(ctx, path, edit.ModePrepend, dryRun)
+	case insertMode:
+		result, err = e.runEditInsert(ctx, path, insertAt, dryRun)
+	case lineRange != "":
+		result, err = e.runEditLineRange(ctx, path, lineRange, dryRun)
+	case section != "":
+		result, err = e.runEditSection(ctx, path, section, dryRun)
+	default:
 		result, err = e.runEditReplace(ctx, c, args)
 	}
 
@@ -108,7 +152,7 @@ func (e *Extension) runEdit(c *cobra.Command, args []string) error {
 	return cmd.PrintJSON(result)
 }
 
-func (e *Extension) runEditLineRange(ctx context.Context, path, lineRange string) (edit.Result, error) {
+func (e *Extension) runEditLineRange(ctx context.Context, path, lineRange string, dryRun bool) (edit.Result, error) {
 	start, end, err := edit.ParseLineRange(lineRange)
 	if err != nil {
 		return edit.Result{}, fmt.Errorf("parse line range %q: %w", lineRange, err)
@@ -124,6 +168,7 @@ func (e *Extension) runEditLineRange(ctx context.Context, path, lineRange string
 		End:     end,
 		Author:  cmd.Author(),
 		Message: cmd.Message(),
+		DryRun:  dryRun,
 	}
 
 	w := cmd.Out()
@@ -134,6 +179,83 @@ func (e *Extension) runEditLineRange(ctx context.Context, path, lineRange string
 	return edit.RunLineRange(ctx, w, e.svc, path, string(replacement), opts)
 }
 
+// runEditSection replaces the section under a heading with stdin. The
+// heading line is part of the section, so stdin should normally start with
+// the heading (renamed or not).
+func (e *Extension) runEditSection(ctx context.Context, path, section string, dryRun bool) (edit.Result, error) {
+	replacement, err := io.ReadAll(os.Stdin)
+	if err != nil {
+		return edit.Result{}, fmt.Errorf("read stdin: %w", err)
+	}
+
+	opts := edit.LineRangeOptions{
+		Section: section,
+		Author:  cmd.Author(),
+		Message: cmd.Message(),
+		DryRun:  dryRun,
+	}
+
+	w := cmd.Out()
+	if cmd.JSON() {
+		w = io.Discard
+	}
+
+	return edit.RunLineRange(ctx, w, e.svc, path, string(replacement), opts)
+}
+
+// runEditBoundary appends or prepends stdin to the document.
+func (e *Extension) runEditBoundary(ctx context.Context, path string, mode edit.Mode, dryRun bool) (edit.Result, error) {
+	content, err := io.ReadAll(os.Stdin)
+	if err != nil {
+		return edit.Result{}, fmt.Errorf("read stdin: %w", err)
+	}
+	if len(content) == 0 {
+		return edit.Result{}, errors.New("no content provided on stdin")
+	}
+
+	opts := edit.LineRangeOptions{
+		Author:  cmd.Author(),
+		Message: cmd.Message(),
+		DryRun:  dryRun,
+	}
+
+	w := cmd.Out()
+	if cmd.JSON() {
+		w = io.Discard
+	}
+
+	if mode == edit.ModePrepend {
+		return edit.RunPrepend(ctx, w, e.svc, path, string(content), opts)
+	}
+	return edit.RunAppend(ctx, w, e.svc, path, string(content), opts)
+}
+
+// runEditInsert inserts stdin before the given line without replacing any.
+func (e *Extension) runEditInsert(ctx context.Context, path string, at int, dryRun bool) (edit.Result, error) {
+	content, err := io.ReadAll(os.Stdin)
+	if err != nil {
+		return edit.Result{}, fmt.Errorf("read stdin: %w", err)
+	}
+	if len(content) == 0 {
+		return edit.Result{}, errors.New("no content provided on stdin")
+	}
+
+	opts := edit.LineRangeOptions{
+		Start:   at,
+		Mode:    edit.ModeInsert,
+		Author:  cmd.Author(),
+		Message: cmd.Message(),
+		DryRun:  dryRun,
+	}
+
+	w := cmd.Out()
+	if cmd.JSON() {
+		w = io.Discard
+	}
+
+	return edit.RunLineRange(ctx, w, e.svc, path, string(content), opts)
+}
+
 func (e *Extension) runEditReplace(ctx context.Context, c *cobra.Command, args []string) (edit.Result, error) {
 	old, _ := c.Flags().GetString(extension.FlagOld)
 	newStr, _ := c.Flags().GetString(extension.FlagNew)
@@ -216,3 +338,57 @@ func (e *Extension) runSed(c *cobra.Command, args []string) error {
 
 	return cmd.PrintJSON(result)
 }
+
+// --- patch command ---
+
+func (e *Extension) newPatchCmd() *cobra.Command {
+	return &cobra.Command{
+		Use:   "patch <path|key>",
+		Short: "Apply a unified diff from stdin",
+		Long: `Apply a unified diff read from stdin to a document.
+
+  git diff -- notes.md | llmd patch docs/notes
+  llmd patch docs/notes < change.diff
+
+Hunks are located by their context lines, so line numbers in the "@@"
+headers may be approximate (or omitted: a bare "@@" is accepted).
+If any hunk fails to apply, nothing is written and the hunk is reported.`,
+		Args: cobra.ExactArgs(1),
+		RunE: e.runPatch,
+	}
+}
+
+func (e *Extension) runPatch(c *cobra.Command, args []string) error {
+	ctx := c.Context()
+	path := args[0]
+
+	l := log.Event("edit:patch", "edit").
+		Author(cmd.Author()).
+		Path(path)
+
+	diff, err := io.ReadAll(os.Stdin)
+	if err != nil {
+		l.Write(err)
+		return cmd.PrintJSONError(fmt.Errorf("patch %q: read stdin: %w", path, err))
+	}
+
+	opts := patch.Options{
+		Author:  cmd.Author(),
+		Message: cmd.Message(),
+	}
+
+	w := cmd.Out()
+	if cmd.JSON() {
+		w = io.Discard
+	}
+
+	result, err := patch.Run(ctx, w, e.svc, path, string(diff), opts)
+	if err != nil {
+		l.Write(err)
+		return cmd.PrintJSONError(fmt.Errorf("patch %q: %w", path, err))
+	}
+
+	l.Resolved(result.Path).Detail("hunks", result.Hunks).Write(nil)
+
+	return cmd.PrintJSON(result)
+}
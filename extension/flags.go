@@ -15,45 +15,118 @@ const (
 	// Boolean flags
 
 	FlagAll            = "all"                // Include all items (including deleted)
+	FlagAppend         = "append"             // Append stdin to the document
+	FlagByteOffset     = "byte-offset"        // Show byte offsets of matches
+	FlagChars          = "chars"              // Count or show characters
+	FlagCheck          = "check"              // Report via exit status only
 	FlagCount          = "count"              // Output count only
+	FlagCountOnly      = "count-only"         // Output a single total only
+	FlagDelete         = "delete"             // Delete the source afterwards
 	FlagDeleted        = "deleted"            // Include/show deleted items
 	FlagDiff           = "diff"               // Show diff output
+	FlagDirsOnly       = "dirs-only"          // Output directories only
 	FlagDryRun         = "dry-run"            // Preview without making changes
-	FlagFile           = "file"               // Treat path as filesystem file
+	FlagEncrypt        = "encrypt"            // Encrypt document content at rest
+	FlagExpand         = "expand"             // Expand include directives
+	FlagFile           = "file"               // Filesystem file path
+	FlagFilesOnly      = "files-only"         // Output document paths only
 	FlagFilesWithMatch = "files-with-matches" // Output matching file paths only
 	FlagFlat           = "flat"               // Flatten directory structure
+	FlagFromContent    = "from-content"       // Derive items from document content
+	FlagFrontmatter    = "frontmatter"        // Use frontmatter path and tags
+	FlagGraph          = "graph"              // Graph output
+	FlagHuman          = "human"              // Human-readable sizes
+	FlagHeaders        = "headers"            // Add a heading per joined document
 	FlagIgnoreCase     = "ignore-case"        // Case-insensitive matching
 	FlagIncludeHidden  = "include-hidden"     // Include hidden files/directories
 	FlagInPlace        = "in-place"           // Edit in place (required for sed)
 	FlagInvertMatch    = "invert-match"       // Invert match selection
 	FlagList           = "list"               // List mode
+	FlagLiteral        = "literal"            // Treat the query as plain text
 	FlagLocal          = "local"              // Use local scope (gitignored)
 	FlagLong           = "long"               // Long format output
+	FlagMultiline      = "multiline"          // Match patterns across lines
 	FlagNumber         = "number"             // Number output lines
+	FlagOnlyMatching   = "only-matching"      // Output matched text only
 	FlagOrphan         = "orphan"             // Show orphaned items
 	FlagPathsOnly      = "paths-only"         // Output paths only
+	FlagPrepend        = "prepend"            // Prepend stdin to the document
+	FlagPrune          = "prune"              // Delete items missing from the source
+	FlagQuiet          = "quiet"              // Suppress output
 	FlagRaw            = "raw"                // Raw output without formatting
+	FlagReachable      = "reachable"          // Follow links transitively
 	FlagRecursive      = "recursive"          // Recursive operation
+	FlagRedact         = "redact"             // Mask secrets in output
 	FlagReverse        = "reverse"            // Reverse sort order
+	FlagSafe           = "safe"               // Skip conflicting changes
 	FlagShare          = "share"              // Mark as shared (committed)
+	FlagStrict         = "strict"             // Fail on unresolved placeholders
 	FlagTree           = "tree"               // Tree view output
+	FlagTruncate       = "truncate"           // Cut content to fit a limit
+	FlagUnlinked       = "unlinked"           // Report references without a link
+	FlagUpdate         = "update"             // Only version changed content
+	FlagVerify         = "verify"             // Re-read output and compare
+	FlagWatch          = "watch"              // Keep running and react to changes
+	FlagWords          = "words"              // Count or show words
+	FlagYes            = "yes"                // Confirm without prompting
 
 	// String flags
 
-	FlagKey       = "key"        // Explicit version key (8-char identifier)
-	FlagLines     = "lines"      // Line range specification (e.g., "10:20")
-	FlagNew       = "new"        // New text for replacement
-	FlagOld       = "old"        // Old text to find
-	FlagOlderThan = "older-than" // Duration threshold
-	FlagPath      = "path"       // Path prefix filter
-	FlagSort      = "sort"       // Sort field
-	FlagTag       = "tag"        // Tag filter/value
-	FlagTo        = "to"         // Target path prefix
-	FlagVersions  = "versions"   // Version range (e.g., "3:5")
+	FlagAddr          = "addr"           // Network listen address
+	FlagArchive       = "archive"        // Single-file .tar.gz or .zip bundle
+	FlagAsOf          = "as-of"          // Point in time to read documents at
+	FlagColor         = "color"          // Colour mode (auto, always, never)
+	FlagContentType   = "content-type"   // MIME type recorded for the content
+	FlagDirection     = "direction"      // Link direction (out, in, both)
+	FlagExclude       = "exclude"        // Path prefix to leave out (repeatable)
+	FlagExt           = "ext"            // File extension filter (repeatable)
+	FlagFormat        = "format"         // Output format variant
+	FlagFromSearch    = "from-search"    // Full-text query selecting documents
+	FlagGlob          = "glob"           // Path glob selecting documents
+	FlagHTTP          = "http"           // HTTP listen address
+	FlagKey           = "key"            // Explicit version key (8-char identifier)
+	FlagLines         = "lines"          // Line range specification (e.g., "10:20")
+	FlagManifest      = "manifest"       // Manifest output file
+	FlagMethod        = "method"         // Estimation method
+	FlagModifiedSince = "modified-since" // Only items changed after this time
+	FlagNew           = "new"            // New text for replacement
+	FlagNot           = "not"            // Term to exclude from search (repeatable)
+	FlagNote          = "note"           // Free-text annotation
+	FlagOld           = "old"            // Old text to find
+	FlagOlderThan     = "older-than"     // Duration threshold
+	FlagPath          = "path"           // Path prefix filter
+	FlagPprof         = "pprof"          // Profiling listen address
+	FlagSearch        = "search"         // Search term
+	FlagSection       = "section"        // Markdown heading whose section to read or edit
+	FlagSet           = "set"            // Variable assignment key=value (repeatable)
+	FlagSince         = "since"          // Start of a time range (inclusive)
+	FlagSort          = "sort"           // Sort field
+	FlagTag           = "tag"            // Tag filter/value
+	FlagTemplate      = "template"       // Template name
+	FlagTo            = "to"             // Target path prefix
+	FlagTools         = "tools"          // MCP tools to offer (repeatable)
+	FlagTransport     = "transport"      // Server transport
+	FlagUntil         = "until"          // End of a time range (exclusive)
+	FlagVersions      = "versions"       // Version range (e.g., "3:5")
 
 	// Integer flags
 
-	FlagContext = "context" // Context lines around matches
-	FlagLimit   = "limit"   // Limit number of results
-	FlagVersion = "version" // Specific version number
+	FlagBudget   = "budget"    // Token budget to check against
+	FlagContext  = "context"   // Context lines around matches
+	FlagDepth    = "depth"     // Maximum depth to descend
+	FlagInsertAt = "insert-at" // Line number to insert before
+	FlagLevel    = "level"     // Heading level
+	FlagLimit    = "limit"     // Limit number of results
+	FlagMaxSize  = "max-size"  // Maximum size in bytes
+	FlagMinSize  = "min-size"  // Minimum size in bytes
+	FlagVersion  = "version"   // Specific version number
+	FlagWeight   = "weight"    // Ordering weight
+
+	// Float flags
+
+	FlagSimilar = "similar" // Minimum similarity for near-duplicates
+
+	// Duration flags
+
+	FlagDebounce = "debounce" // Quiet period before acting on changes
 )
@@ -1,15 +1,19 @@
 // Package link provides document relationship management. Links enable
 // connecting related documents for navigation and dependency tracking.
-// Registers commands: link, unlink.
+// Registers commands: link, unlink, check-links.
 package link
 
 import (
 	"context"
 	"fmt"
+	"io"
 
 	"github.com/jpl-au/llmd/cmd"
 	"github.com/jpl-au/llmd/extension"
+	"github.com/jpl-au/llmd/internal/graph"
+	"github.com/jpl-au/llmd/internal/linkcheck"
 	"github.com/jpl-au/llmd/internal/log"
+	"github.com/jpl-au/llmd/internal/mdlink"
 	"github.com/jpl-au/llmd/internal/service"
 	"github.com/jpl-au/llmd/internal/store"
 	"github.com/spf13/cobra"
@@ -41,11 +45,12 @@ func (e *Extension) Init(ctx extension.Context) error {
 	return nil
 }
 
-// Commands returns link and unlink commands for relationship management.
+// Commands returns link, unlink and check-links commands for relationship management.
 func (e *Extension) Commands() []*cobra.Command {
 	return []*cobra.Command{
 		e.newLinkCmd(),
 		e.newUnlinkCmd(),
+		e.newCheckLinksCmd(),
 	}
 }
 
@@ -88,8 +93,7 @@ func (e *Extension) HandleEvent(ctx extension.Context, evt extension.Event) erro
 // Why soft-delete instead of hard-delete? The document itself is soft-deleted,
 // meaning it can be restored via "llmd restore". If we hard-deleted the links,
 // restoring the document would leave it disconnected from its former relationships.
-// By soft-deleting links, a future enhancement could restore them alongside the
-// document (though this isn't implemented yet).
+// By soft-deleting links, restore can bring them back alongside the document.
 //
 // Performance consideration: For documents with many links, this performs one
 // database operation (DeleteLinksForPath) rather than N individual deletions.
@@ -127,19 +131,38 @@ func (e *Extension) newLinkCmd() *cobra.Command {
 	c := &cobra.Command{
 		Use:   "link <document> [documents...]",
 		Short: "Create links between documents",
-		Long: `Create bidirectional links between documents.
+		Long: `Create links between documents.
+
+A link runs from the first document to the others. Listing shows links in
+both directions unless --direction narrows it to outgoing (out) or incoming
+(in) links.
 
   llmd link doc1 doc2              # link two documents
   llmd link doc1 doc2 doc3         # link doc1 to doc2 and doc3
   llmd link --tag depends-on a b   # link with a tag
+  llmd link --note "supersedes" --weight 10 a b  # annotate and order
   llmd link --list doc             # list links for a document
-  llmd link --orphan               # find documents with no links`,
+  llmd link --list doc --direction in  # only links pointing at doc
+  llmd link --from-content doc     # link doc to documents its markdown links to
+  llmd link --orphan               # find documents with no links
+  llmd link --reachable doc        # everything linked to doc, at any distance
+  llmd link --reachable doc --depth 2 --tag depends-on
+  llmd link --graph                # whole link graph as Graphviz DOT
+  llmd link --graph --format mermaid --orphan  # Mermaid, with unlinked docs`,
 		Args: cobra.ArbitraryArgs,
 		RunE: e.runLink,
 	}
 	c.Flags().StringP(extension.FlagTag, "t", "", "Link tag (optional categorisation)")
+	c.Flags().String(extension.FlagNote, "", "Note explaining the link")
+	c.Flags().Int(extension.FlagWeight, 0, "Link weight; heavier links are listed first")
 	c.Flags().BoolP(extension.FlagList, "l", false, "List links for a document")
-	c.Flags().Bool(extension.FlagOrphan, false, "List documents with no links")
+	c.Flags().String(extension.FlagDirection, store.DirectionBoth, "With --list or --reachable: follow out, in or both")
+	c.Flags().Bool(extension.FlagFromContent, false, "Link a document to the documents its markdown links reference")
+	c.Flags().Bool(extension.FlagOrphan, false, "List documents with no links (with --graph: include them as nodes)")
+	c.Flags().Bool(extension.FlagReachable, false, "List documents reachable from a document through links")
+	c.Flags().Int(extension.FlagDepth, 0, "With --reachable: maximum hops to follow (0 = unlimited)")
+	c.Flags().Bool(extension.FlagGraph, false, "Print the link graph for a renderer")
+	c.Flags().String(extension.FlagFormat, graph.FormatDOT, "Graph format: dot or mermaid")
 	return c
 }
 
@@ -148,6 +171,36 @@ func (e *Extension) runLink(c *cobra.Command, args []string) error {
 	tag, _ := c.Flags().GetString(extension.FlagTag)
 	list, _ := c.Flags().GetBool(extension.FlagList)
 	orphan, _ := c.Flags().GetBool(extension.FlagOrphan)
+	showGraph, _ := c.Flags().GetBool(extension.FlagGraph)
+	reachable, _ := c.Flags().GetBool(extension.FlagReachable)
+	direction, _ := c.Flags().GetString(extension.FlagDirection)
+	fromContent, _ := c.Flags().GetBool(extension.FlagFromContent)
+
+	// --graph: export the whole link graph
+	if showGraph {
+		format, _ := c.Flags().GetString(extension.FlagFormat)
+		return e.printGraph(ctx, graph.Options{Format: format, Tag: tag, Orphans: orphan})
+	}
+
+	// --reachable: follow links transitively from a document
+	if reachable {
+		if len(args) != 1 {
+			return cmd.PrintJSONError(fmt.Errorf("--reachable requires exactly one document"))
+		}
+		depth, _ := c.Flags().GetInt(extension.FlagDepth)
+		return e.listReachable(ctx, args[0], graph.ReachOptions{Depth: depth, Tag: tag, Direction: direction})
+	}
+
+	// --from-content: link to the documents referenced in the body
+	if fromContent {
+		if len(args) != 1 {
+			return cmd.PrintJSONError(fmt.Errorf("--from-content requires exactly one document"))
+		}
+		if tag == "" {
+			tag = mdlink.LinkTag
+		}
+		return e.linkFromContent(ctx, args[0], tag)
+	}
 
 	// --orphan: list unlinked documents
 	if orphan {
@@ -163,7 +216,7 @@ func (e *Extension) runLink(c *cobra.Command, args []string) error {
 			}
 			return cmd.PrintJSONError(fmt.Errorf("--list requires a document path or --tag"))
 		}
-		return e.listLinks(ctx, args[0], tag)
+		return e.listLinks(ctx, args[0], tag, direction)
 	}
 
 	// Create links: need at least 2 documents
@@ -171,12 +224,23 @@ func (e *Extension) runLink(c *cobra.Command, args []string) error {
 		return cmd.PrintJSONError(fmt.Errorf("link requires at least 2 documents"))
 	}
 
-	return e.createLinks(ctx, args[0], args[1:], tag)
+	// Only flags given replace what an existing link has
+	opts := store.NewLinkOptions()
+	if c.Flags().Changed(extension.FlagNote) {
+		note, _ := c.Flags().GetString(extension.FlagNote)
+		opts = opts.WithNote(note)
+	}
+	if c.Flags().Changed(extension.FlagWeight) {
+		weight, _ := c.Flags().GetInt(extension.FlagWeight)
+		opts = opts.WithWeight(weight)
+	}
+	return e.createLinks(ctx, args[0], args[1:], tag, opts)
 }
 
-// createLinks establishes bidirectional links between a source document and multiple targets.
-// Arguments can be document paths or keys - both are resolved to paths before linking.
-func (e *Extension) createLinks(ctx context.Context, from string, targets []string, tag string) error {
+// createLinks establishes links from a source document to multiple targets,
+// each carrying the note and weight in opts. Arguments can be document paths
+// or keys - both are resolved to paths before linking.
+func (e *Extension) createLinks(ctx context.Context, from string, targets []string, tag string, opts store.LinkOptions) error {
 	// Resolve 'from' which could be a path or key
 	fromDoc, _, err := e.svc.Resolve(ctx, from, false)
 	if err != nil {
@@ -193,20 +257,26 @@ func (e *Extension) createLinks(ctx context.Context, from string, targets []stri
 			return cmd.PrintJSONError(fmt.Errorf("resolve %q: %w", to, err))
 		}
 		to = toDoc.Path
-		id, err := e.svc.Link(ctx, from, to, tag, store.NewLinkOptions())
+		id, err := e.svc.Link(ctx, from, to, tag, opts)
 		if err != nil {
 			return cmd.PrintJSONError(fmt.Errorf("link %q to %q: %w", from, to, err))
 		}
 		ids = append(ids, id)
 		resolvedTargets = append(resolvedTargets, to)
 
-		log.Event("link:create", "link").
+		l := log.Event("link:create", "link").
 			Author(cmd.Author()).
 			Path(from).
 			Detail("to", to).
 			Detail("tag", tag).
-			Detail("id", id).
-			Write(nil)
+			Detail("id", id)
+		if opts.Note != nil {
+			l.Detail("note", *opts.Note)
+		}
+		if opts.Weight != nil {
+			l.Detail("weight", *opts.Weight)
+		}
+		l.Write(nil)
 
 		if !cmd.JSON() {
 			if tag != "" {
@@ -228,9 +298,74 @@ func (e *Extension) createLinks(ctx context.Context, from string, targets []stri
 	return nil
 }
 
-// listLinks displays all links connected to a document, optionally filtered by tag.
-// The path argument can be a document path or key.
-func (e *Extension) listLinks(ctx context.Context, path, tag string) error {
+// linkFromContent creates a link from a document to each existing document
+// its markdown links reference, so the link graph reflects the prose.
+// Linking is idempotent; references that do not resolve are reported and
+// skipped rather than failing the command.
+func (e *Extension) linkFromContent(ctx context.Context, path, tag string) error {
+	doc, _, err := e.svc.Resolve(ctx, path, false)
+	if err != nil {
+		return cmd.PrintJSONError(fmt.Errorf("resolve %q: %w", path, err))
+	}
+	from := doc.Path
+
+	refs, err := linkcheck.References(ctx, e.svc, *doc)
+	if err != nil {
+		return cmd.PrintJSONError(fmt.Errorf("read references in %q: %w", from, err))
+	}
+
+	ids := []string{}
+	targets := []string{}
+	skipped := []linkcheck.Issue{}
+	seen := map[string]bool{}
+	for _, ref := range refs {
+		if ref.Problem != "" {
+			skipped = append(skipped, ref)
+			if !cmd.JSON() {
+				fmt.Fprintf(cmd.Out(), "skipped  %s:%d -> %s (%s)\n", ref.Source, ref.Line, ref.Target, ref.Problem)
+			}
+			continue
+		}
+		if ref.Target == from || seen[ref.Target] {
+			continue
+		}
+		seen[ref.Target] = true
+
+		id, err := e.svc.Link(ctx, from, ref.Target, tag, store.NewLinkOptions())
+		if err != nil {
+			return cmd.PrintJSONError(fmt.Errorf("link %q to %q: %w", from, ref.Target, err))
+		}
+		ids = append(ids, id)
+		targets = append(targets, ref.Target)
+
+		if !cmd.JSON() {
+			fmt.Fprintf(cmd.Out(), "%s  %s -> %s [%s]\n", id, from, ref.Target, tag)
+		}
+	}
+
+	log.Event("link:from_content", "link").
+		Author(cmd.Author()).
+		Path(from).
+		Detail("tag", tag).
+		Detail("count", len(ids)).
+		Detail("skipped", len(skipped)).
+		Write(nil)
+
+	if cmd.JSON() {
+		return cmd.PrintJSON(map[string]any{
+			"from":    from,
+			"targets": targets,
+			"tag":     tag,
+			"ids":     ids,
+			"skipped": skipped,
+		})
+	}
+	return nil
+}
+
+// listLinks displays the links connected to a document, optionally filtered
+// by tag and direction. The path argument can be a document path or key.
+func (e *Extension) listLinks(ctx context.Context, path, tag, direction string) error {
 	// Resolve path which could be a path or key
 	doc, _, err := e.svc.Resolve(ctx, path, false)
 	if err != nil {
@@ -238,7 +373,7 @@ func (e *Extension) listLinks(ctx context.Context, path, tag string) error {
 	}
 	path = doc.Path
 
-	links, err := e.svc.ListLinks(ctx, path, tag, store.NewLinkOptions())
+	links, err := e.svc.ListLinks(ctx, path, tag, store.NewLinkOptions().WithDirection(direction))
 	if err != nil {
 		return cmd.PrintJSONError(fmt.Errorf("list links for %q: %w", path, err))
 	}
@@ -247,6 +382,7 @@ func (e *Extension) listLinks(ctx context.Context, path, tag string) error {
 		Author(cmd.Author()).
 		Path(path).
 		Detail("tag", tag).
+		Detail("direction", direction).
 		Detail("count", len(links)).
 		Write(nil)
 
@@ -263,11 +399,14 @@ func (e *Extension) listLinks(ctx context.Context, path, tag string) error {
 		if l.ToPath == path {
 			other = l.FromPath
 		}
+		line := l.ID + "  " + other
 		if l.Tag != "" {
-			fmt.Fprintf(cmd.Out(), "%s  %s [%s]\n", l.ID, other, l.Tag)
-		} else {
-			fmt.Fprintf(cmd.Out(), "%s  %s\n", l.ID, other)
+			line += " [" + l.Tag + "]"
+		}
+		if l.Note != "" {
+			line += "  # " + l.Note
 		}
+		fmt.Fprintln(cmd.Out(), line)
 	}
 	return nil
 }
@@ -321,6 +460,59 @@ func (e *Extension) listOrphans(ctx context.Context) error {
 	return nil
 }
 
+// listReachable prints each document reachable from path with its distance
+// in hops, nearest first.
+func (e *Extension) listReachable(ctx context.Context, path string, opts graph.ReachOptions) error {
+	w := cmd.Out()
+	if cmd.JSON() {
+		w = io.Discard
+	}
+
+	l := log.Event("link:reachable", "list").
+		Author(cmd.Author()).
+		Path(path).
+		Detail("depth", opts.Depth).
+		Detail("tag", opts.Tag)
+
+	result, err := graph.Reachable(ctx, w, e.svc, path, opts)
+	if err != nil {
+		l.Write(err)
+		return cmd.PrintJSONError(fmt.Errorf("reachable from %q: %w", path, err))
+	}
+
+	l.Resolved(result.Path).
+		Detail("count", len(result.Reached)).
+		Write(nil)
+
+	return cmd.PrintJSON(result)
+}
+
+// printGraph writes the link graph as DOT or Mermaid. JSON output gives the
+// nodes and edges instead, for callers that want to draw it themselves.
+func (e *Extension) printGraph(ctx context.Context, opts graph.Options) error {
+	w := cmd.Out()
+	if cmd.JSON() {
+		w = io.Discard
+	}
+
+	l := log.Event("link:graph", "list").
+		Author(cmd.Author()).
+		Detail("format", opts.Format).
+		Detail("tag", opts.Tag)
+
+	result, err := graph.Run(ctx, w, e.svc, opts)
+	if err != nil {
+		l.Write(err)
+		return cmd.PrintJSONError(fmt.Errorf("link graph: %w", err))
+	}
+
+	l.Detail("nodes", len(result.Nodes)).
+		Detail("edges", len(result.Edges)).
+		Write(nil)
+
+	return cmd.PrintJSON(result)
+}
+
 // --- unlink command ---
 
 func (e *Extension) newUnlinkCmd() *cobra.Command {
@@ -390,3 +582,59 @@ func (e *Extension) runUnlink(c *cobra.Command, args []string) error {
 	fmt.Fprintf(cmd.Out(), "unlinked %s\n", id)
 	return nil
 }
+
+// --- check-links command ---
+
+func (e *Extension) newCheckLinksCmd() *cobra.Command {
+	c := &cobra.Command{
+		Use:   "check-links [path]",
+		Short: "Report markdown links to documents that do not exist",
+		Long: `Scan document content for markdown links to other documents and report
+each one whose target does not exist, as "source:line -> target".
+
+Targets are store paths ("docs/api/auth", a ".md" extension is ignored);
+targets starting with ./ or ../ are relative to the linking document. URLs,
+anchors, images and code are skipped. Exits non-zero when issues are found.
+
+  llmd check-links                  # whole store
+  llmd check-links docs/            # documents under docs/
+  llmd check-links --unlinked       # also report references with no link`,
+		Args: cobra.MaximumNArgs(1),
+		RunE: e.runCheckLinks,
+	}
+	c.Flags().Bool(extension.FlagUnlinked, false, "Also report references not backed by a link")
+	return c
+}
+
+func (e *Extension) runCheckLinks(c *cobra.Command, args []string) error {
+	unlinked, _ := c.Flags().GetBool(extension.FlagUnlinked)
+	opts := linkcheck.Options{Unlinked: unlinked}
+	if len(args) == 1 {
+		opts.Path = args[0]
+	}
+
+	w := cmd.Out()
+	if cmd.JSON() {
+		w = io.Discard
+	}
+
+	result, err := linkcheck.Run(c.Context(), w, e.svc, opts)
+
+	log.Event("link:check", "list").
+		Author(cmd.Author()).
+		Path(opts.Path).
+		Detail("references", result.Refs).
+		Detail("issues", len(result.Issues)).
+		Write(err)
+
+	if err != nil {
+		return cmd.PrintJSONError(fmt.Errorf("check-links: %w", err))
+	}
+	if cmd.JSON() {
+		return cmd.PrintJSON(result)
+	}
+	if !result.OK {
+		return fmt.Errorf("%d issue(s) found in %d document(s)", len(result.Issues), result.Documents)
+	}
+	return nil
+}
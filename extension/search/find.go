@@ -24,14 +24,19 @@ func (e *Extension) newFindCmd() *cobra.Command {
 		Short: "Full-text search across documents",
 		Long: `Full-text search across documents.
 
-Supports FTS5 query syntax including prefix matching with *.`,
+Supports FTS5 query syntax including prefix matching with *. Use --literal
+to search for text containing punctuation such as "C++" or "foo-bar".`,
 		Args: cobra.ExactArgs(1),
 		RunE: e.runFind,
 	}
 	c.Flags().StringP(extension.FlagPath, "p", "", "Scope search to path prefix")
 	c.Flags().BoolP(extension.FlagPathsOnly, "l", false, "Only output paths")
+	c.Flags().Bool(extension.FlagCountOnly, false, "Only print the number of matching documents")
 	c.Flags().BoolP(extension.FlagDeleted, "D", false, "Search deleted documents only")
 	c.Flags().BoolP(extension.FlagAll, "A", false, "Search all documents (including deleted)")
+	c.Flags().Bool(extension.FlagLiteral, false, "Match the query as plain text instead of FTS5 syntax")
+	c.Flags().StringArray(extension.FlagNot, nil, "Exclude documents containing this term (repeatable)")
+	c.Flags().StringArray(extension.FlagExclude, nil, "Exclude documents under this path prefix (repeatable)")
 	return c
 }
 
@@ -42,12 +47,19 @@ func (e *Extension) runFind(c *cobra.Command, args []string) error {
 	del, _ := c.Flags().GetBool(extension.FlagDeleted)
 	all, _ := c.Flags().GetBool(extension.FlagAll)
 	pathsOnly, _ := c.Flags().GetBool(extension.FlagPathsOnly)
+	countOnly, _ := c.Flags().GetBool(extension.FlagCountOnly)
+	literal, _ := c.Flags().GetBool(extension.FlagLiteral)
+	not, _ := c.Flags().GetStringArray(extension.FlagNot)
+	exclude, _ := c.Flags().GetStringArray(extension.FlagExclude)
 
 	opts := find.Options{
 		Prefix:      prefix,
 		IncludeAll:  all,
 		DeletedOnly: del,
 		PathsOnly:   pathsOnly,
+		Literal:     literal,
+		Not:         not,
+		Exclude:     exclude,
 	}
 
 	l := log.Event("search:find", "search").
@@ -58,7 +70,7 @@ func (e *Extension) runFind(c *cobra.Command, args []string) error {
 	var result find.Result
 	var err error
 
-	if cmd.JSON() {
+	if cmd.JSON() || countOnly {
 		result, err = find.Run(ctx, io.Discard, e.svc, query, opts)
 	} else {
 		result, err = find.Run(ctx, cmd.Out(), e.svc, query, opts)
@@ -66,11 +78,18 @@ func (e *Extension) runFind(c *cobra.Command, args []string) error {
 
 	if err != nil {
 		l.Write(err)
+		if !literal && store.IsQueryError(err) {
+			return cmd.PrintJSONError(fmt.Errorf("find %q: %w (use --literal to search for plain text)", query, err))
+		}
 		return cmd.PrintJSONError(fmt.Errorf("find %q: %w", query, err))
 	}
 
 	l.Detail("count", len(result.Documents)).Write(nil)
 
+	if countOnly {
+		return cmd.PrintCount(len(result.Documents))
+	}
+
 	if cmd.JSON() {
 		items := make([]store.DocJSON, len(result.Documents))
 		for i := range result.Documents {
@@ -9,9 +9,12 @@ package search
 
 import (
 	"fmt"
+	"io"
+	"time"
 
 	"github.com/jpl-au/llmd/cmd"
 	"github.com/jpl-au/llmd/extension"
+	"github.com/jpl-au/llmd/internal/duration"
 	"github.com/jpl-au/llmd/internal/grep"
 	"github.com/jpl-au/llmd/internal/log"
 	"github.com/jpl-au/llmd/internal/store"
@@ -26,8 +29,11 @@ func (e *Extension) newGrepCmd() *cobra.Command {
 
   llmd grep "TODO"              # search all documents
   llmd grep "error|warn" docs/  # search with alternation
+  llmd grep TODO 'docs/**/api/*'  # scope by glob instead of prefix
   llmd grep -i "auth.*token"    # case-insensitive regex
   llmd grep -l "func.*\("       # list matching paths only
+  llmd grep -b --only-matching "v[0-9]+"  # each match with its byte offset
+  llmd grep --multiline '(?m)^` + "```" + `go$.*?^` + "```" + `$'  # fenced Go blocks
 
 For full-text search (FTS5), use 'llmd find' instead.`,
 		Args: cobra.RangeArgs(1, 2),
@@ -37,10 +43,15 @@ For full-text search (FTS5), use 'llmd find' instead.`,
 	c.Flags().BoolP(extension.FlagIgnoreCase, "i", false, "Ignore case distinctions")
 	c.Flags().BoolP(extension.FlagInvertMatch, "v", false, "Select non-matching lines")
 	c.Flags().BoolP(extension.FlagCount, "c", false, "Only print count of matches per document")
+	c.Flags().Bool(extension.FlagCountOnly, false, "Only print the number of matching documents")
 	c.Flags().IntP(extension.FlagContext, "C", 0, "Print N lines of context around matches")
+	c.Flags().BoolP(extension.FlagByteOffset, "b", false, "Print the byte offset of each line (or match, with --only-matching)")
+	c.Flags().Bool(extension.FlagOnlyMatching, false, "Print only the matched parts of each line")
+	c.Flags().Bool(extension.FlagMultiline, false, "Match across lines against the whole document (. matches newline)")
 	c.Flags().BoolP(extension.FlagRecursive, "r", false, "Search subdirectories recursively")
 	c.Flags().BoolP(extension.FlagDeleted, "D", false, "Search deleted documents only")
 	c.Flags().BoolP(extension.FlagAll, "A", false, "Search all documents (including deleted)")
+	c.Flags().String(extension.FlagAsOf, "", "Search documents as they were at this time (e.g., 7d, 2024-01-15)")
 	return c
 }
 
@@ -58,8 +69,12 @@ func (e *Extension) runGrep(c *cobra.Command, args []string) error {
 	ignoreCase, _ := c.Flags().GetBool(extension.FlagIgnoreCase)
 	invert, _ := c.Flags().GetBool(extension.FlagInvertMatch)
 	countOnly, _ := c.Flags().GetBool(extension.FlagCount)
+	matching, _ := c.Flags().GetBool(extension.FlagCountOnly)
 	context, _ := c.Flags().GetInt(extension.FlagContext)
 	recursive, _ := c.Flags().GetBool(extension.FlagRecursive)
+	byteOffset, _ := c.Flags().GetBool(extension.FlagByteOffset)
+	onlyMatching, _ := c.Flags().GetBool(extension.FlagOnlyMatching)
+	multiline, _ := c.Flags().GetBool(extension.FlagMultiline)
 
 	if context < 0 {
 		return cmd.PrintJSONError(fmt.Errorf("context lines (-C) must be >= 0, got %d", context))
@@ -74,16 +89,33 @@ func (e *Extension) runGrep(c *cobra.Command, args []string) error {
 		IgnoreCase:    ignoreCase,
 		Invert:        invert,
 		CountOnly:     countOnly,
+		Matching:      matching,
 		Context:       context,
+		ByteOffset:    byteOffset,
+		OnlyMatching:  onlyMatching,
+		Multiline:     multiline,
 		MaxLineLength: e.cfg.MaxLineLength(),
 	}
 
+	if asOf, _ := c.Flags().GetString(extension.FlagAsOf); asOf != "" {
+		t, err := duration.ParseTime(asOf, time.Now())
+		if err != nil {
+			return cmd.PrintJSONError(err)
+		}
+		opts.AsOf = t
+	}
+
 	l := log.Event("search:grep", "search").
 		Author(cmd.Author()).
 		Path(path).
 		Detail("pattern", pattern)
 
-	result, err := grep.Run(ctx, cmd.Out(), e.svc, pattern, opts)
+	w := cmd.Out()
+	if cmd.JSON() {
+		w = io.Discard
+	}
+
+	result, err := grep.Run(ctx, w, e.svc, pattern, opts)
 	if err != nil {
 		l.Write(err)
 		return cmd.PrintJSONError(fmt.Errorf("grep %q: %w", pattern, err))
@@ -91,6 +123,14 @@ func (e *Extension) runGrep(c *cobra.Command, args []string) error {
 
 	l.Detail("count", len(result.Documents)).Write(nil)
 
+	if matching {
+		return cmd.PrintCount(len(result.Documents))
+	}
+
+	if cmd.JSON() && (byteOffset || onlyMatching || multiline) && !invert && !pathsOnly && !countOnly {
+		return cmd.PrintJSON(result.Spans())
+	}
+
 	if cmd.JSON() {
 		items := make([]store.DocJSON, len(result.Documents))
 		for i := range result.Documents {
@@ -12,15 +12,21 @@ import (
 	"fmt"
 	"io/fs"
 	"os"
+	"os/signal"
+	"strings"
+	"syscall"
+	"time"
 
 	"github.com/jpl-au/llmd/cmd"
 	"github.com/jpl-au/llmd/extension"
 	"github.com/jpl-au/llmd/internal/document"
+	"github.com/jpl-au/llmd/internal/duration"
 	"github.com/jpl-au/llmd/internal/exporter"
 	"github.com/jpl-au/llmd/internal/importer"
 	"github.com/jpl-au/llmd/internal/log"
 	"github.com/jpl-au/llmd/internal/sync"
 	"github.com/spf13/cobra"
+	"github.com/spf13/pflag"
 )
 
 func init() {
@@ -71,7 +77,22 @@ func newImportCmd() *cobra.Command {
 		Short: "Bulk import markdown files from filesystem",
 		Long: `Bulk import markdown files from filesystem into the store.
 
-Recursively scans for .md files and imports them.`,
+Recursively scans for .md files and imports them. Use --ext (repeatable)
+to choose other extensions, e.g. --ext .md --ext .markdown --ext .mdx.
+
+With --frontmatter, a leading YAML block in each file can set the store
+path and initial tags:
+
+  ---
+  path: guides/setup
+  tags: [onboarding, draft]
+  ---
+
+With --update, files whose content matches the latest version of their
+document are skipped, so repeated imports only version what changed.
+
+A .tar.gz, .tgz or .zip file is imported entry by entry, as made by
+export --archive.`,
 		Args: cobra.ExactArgs(1),
 		RunE: runImport,
 	}
@@ -79,6 +100,9 @@ Recursively scans for .md files and imports them.`,
 	c.Flags().BoolP(extension.FlagFlat, "F", false, "Flatten directory structure")
 	c.Flags().BoolP(extension.FlagDryRun, "n", false, "Show what would be imported")
 	c.Flags().BoolP(extension.FlagIncludeHidden, "H", false, "Include hidden files/dirs")
+	c.Flags().Bool(extension.FlagFrontmatter, false, "Use frontmatter path and tags")
+	c.Flags().BoolP(extension.FlagUpdate, "u", false, "Only write new versions for changed files")
+	c.Flags().StringArray(extension.FlagExt, importer.DefaultExtensions, "File extension to import (repeatable)")
 	return c
 }
 
@@ -93,11 +117,17 @@ func runImport(c *cobra.Command, args []string) error {
 	opts.Flat, _ = c.Flags().GetBool(extension.FlagFlat)
 	opts.DryRun, _ = c.Flags().GetBool(extension.FlagDryRun)
 	opts.Hidden, _ = c.Flags().GetBool(extension.FlagIncludeHidden)
+	opts.Frontmatter, _ = c.Flags().GetBool(extension.FlagFrontmatter)
+	opts.Update, _ = c.Flags().GetBool(extension.FlagUpdate)
+	// Read the raw values: GetStringArray round-trips through a string form
+	// that turns --ext '' into an empty list, hiding it from validation.
+	opts.Extensions = c.Flags().Lookup(extension.FlagExt).Value.(pflag.SliceValue).GetSlice()
 
+	// Update mode compares against the store, even when only previewing
 	var svc *document.Service
 	var err error
-	if !opts.DryRun {
-		svc, err = document.New(cmd.DB())
+	if !opts.DryRun || opts.Update {
+		svc, err = cmd.OpenService()
 		if err != nil {
 			return cmd.PrintJSONError(fmt.Errorf("open store: %w", err))
 		}
@@ -114,14 +144,22 @@ func runImport(c *cobra.Command, args []string) error {
 		return cmd.PrintJSONError(fmt.Errorf("import %q: %w", src, err))
 	}
 
-	l.Detail("count", result.Imported).Write(nil)
+	l.Detail("count", result.Imported)
+	if opts.Update {
+		l.Detail("created", result.Created).
+			Detail("updated", result.Updated).
+			Detail("unchanged", result.Unchanged)
+	}
+	l.Write(nil)
 
 	if len(result.Paths) == 0 {
-		fmt.Fprintf(cmd.Out(), "No markdown files found in %q (expected .md files)\n", src)
+		fmt.Fprintf(cmd.Out(), "No markdown files found in %q (expected %s files)\n", src, strings.Join(opts.Extensions, ", "))
 		return nil
 	}
 
-	if !opts.DryRun {
+	if opts.Update {
+		fmt.Fprintf(cmd.Out(), "\n%d created, %d updated, %d unchanged\n", result.Created, result.Updated, result.Unchanged)
+	} else if !opts.DryRun {
 		fmt.Fprintf(cmd.Out(), "\nImported %d file(s)\n", result.Imported)
 	}
 	return nil
@@ -136,21 +174,49 @@ func newExportCmd() *cobra.Command {
 		Long: `Export documents from the store to filesystem.
 
 Single document: destination can be a file path
-Multiple documents (prefix): destination must be a directory`,
-		Args: cobra.RangeArgs(1, 2),
+Multiple documents (prefix): destination must be a directory
+
+--as-of exports each document as it was at a point in time, for snapshotting
+the store on a given date. Accepts 2024-01-15, 2024-01-15T10:30:00Z, or a
+duration such as 7d. Documents created later or already deleted are skipped.
+
+--manifest writes a JSON record of each document's path, key, version and
+content hash. --verify re-reads every file after writing and fails if it
+does not match the stored content.
+
+--archive writes everything into one .tar.gz or .zip file instead of a
+directory, with manifest.json inside. The destination argument is dropped
+and the document path defaults to / (the whole store).`,
+		Args: cobra.RangeArgs(0, 2),
 		RunE: runExport,
 	}
 	c.Flags().IntP(extension.FlagVersion, "v", 0, "Export specific version")
 	c.Flags().StringP(extension.FlagKey, "k", "", "Export by version key (8-char identifier)")
+	c.Flags().String(extension.FlagAsOf, "", "Export documents as they were at this time")
+	c.Flags().String(extension.FlagManifest, "", "Write a JSON manifest to this file")
+	c.Flags().Bool(extension.FlagVerify, false, "Re-read exported files and compare with the store")
+	c.Flags().String(extension.FlagArchive, "", "Export into a single .tar.gz or .zip file")
 	return c
 }
 
 func runExport(c *cobra.Command, args []string) error {
 	ctx := c.Context()
 	keyFlag, _ := c.Flags().GetString(extension.FlagKey)
+	archivePath, _ := c.Flags().GetString(extension.FlagArchive)
 
 	var docPath, dest string
-	if keyFlag != "" && len(args) == 1 {
+	if archivePath != "" {
+		// --archive replaces the destination argument
+		switch {
+		case len(args) > 1 || (keyFlag != "" && len(args) > 0):
+			return cmd.PrintJSONError(fmt.Errorf("--archive takes at most a <doc-path>, not a destination"))
+		case len(args) == 1:
+			docPath = args[0]
+		case keyFlag == "":
+			docPath = "/"
+		}
+		dest = archivePath
+	} else if keyFlag != "" && len(args) == 1 {
 		// --key provided: single arg is destination
 		dest = args[0]
 	} else if len(args) == 2 {
@@ -159,21 +225,34 @@ func runExport(c *cobra.Command, args []string) error {
 	} else {
 		return cmd.PrintJSONError(fmt.Errorf("requires <doc-path> <filesystem-path>, or --key <filesystem-path>"))
 	}
-	svc, err := document.New(cmd.DB())
+	svc, err := cmd.OpenService()
 	if err != nil {
 		return cmd.PrintJSONError(fmt.Errorf("open store: %w", err))
 	}
 	defer svc.Close()
 
 	opts := exporter.Options{
-		Force: cmd.Force(),
+		Force:   cmd.Force(),
+		Archive: archivePath,
 	}
 	opts.Version, _ = c.Flags().GetInt(extension.FlagVersion)
+	opts.Manifest, _ = c.Flags().GetString(extension.FlagManifest)
+	opts.Verify, _ = c.Flags().GetBool(extension.FlagVerify)
 
 	if opts.Version < 0 {
 		return cmd.PrintJSONError(fmt.Errorf("version must be >= 0, got %d", opts.Version))
 	}
 
+	if asOf, _ := c.Flags().GetString(extension.FlagAsOf); asOf != "" {
+		if opts.Version > 0 || keyFlag != "" {
+			return cmd.PrintJSONError(fmt.Errorf("--as-of cannot be combined with --version or --key"))
+		}
+		opts.AsOf, err = duration.ParseTime(asOf, time.Now())
+		if err != nil {
+			return cmd.PrintJSONError(err)
+		}
+	}
+
 	key := ""
 	if keyFlag != "" {
 		// Explicit key provided via --key flag
@@ -184,7 +263,7 @@ func runExport(c *cobra.Command, args []string) error {
 		key = keyFlag
 		docPath = doc.Path
 		opts.Version = doc.Version
-	} else if opts.Version == 0 {
+	} else if opts.Version == 0 && opts.AsOf.IsZero() {
 		// No version specified - try to resolve as path or key
 		doc, isKey, err := svc.Resolve(ctx, docPath, false)
 		if err == nil && isKey {
@@ -202,6 +281,9 @@ func runExport(c *cobra.Command, args []string) error {
 	if key != "" {
 		l.Detail("key", key)
 	}
+	if !opts.AsOf.IsZero() {
+		l.Detail("as_of", opts.AsOf.UTC().Format(time.RFC3339))
+	}
 
 	result, err := exporter.Run(ctx, cmd.Out(), svc, docPath, dest, opts)
 	if err != nil {
@@ -216,6 +298,9 @@ func runExport(c *cobra.Command, args []string) error {
 	} else if key != "" && result.Exported == 1 {
 		fmt.Fprintf(cmd.Out(), "(from key %s)\n", key)
 	}
+	if opts.Verify {
+		fmt.Fprintf(cmd.Out(), "Verified %d file(s)\n", result.Exported)
+	}
 	return nil
 }
 
@@ -229,16 +314,31 @@ func newSyncCmd() *cobra.Command {
 those changes back into the database.
 
 This is a recovery mechanism for when files are edited directly
-(bypassing llmd commands).`,
+(bypassing llmd commands).
+
+Documents whose files have been removed are reported but kept. Use --prune
+to soft-delete them, making the filesystem the source of truth.
+
+A conflict is a file edited on disk whose document was also changed in the
+database since the last sync. Conflicts are imported and reported; with
+--safe they are skipped so they can be compared and resolved by hand.
+
+With --watch, sync keeps running and imports files as they are saved,
+waiting --debounce after the last change so one save becomes one version.
+Stop with Ctrl+C.`,
 		RunE: runSync,
 	}
 	c.Flags().BoolP(extension.FlagDryRun, "n", false, "Show what would be synced")
+	c.Flags().Bool(extension.FlagPrune, false, "Soft-delete documents whose files were removed")
+	c.Flags().Bool(extension.FlagSafe, false, "Skip files that conflict with database changes")
+	c.Flags().BoolP(extension.FlagWatch, "w", false, "Keep syncing as files change")
+	c.Flags().Duration(extension.FlagDebounce, sync.DefaultDebounce, "Wait this long after the last change before syncing")
 	return c
 }
 
 func runSync(c *cobra.Command, _ []string) error {
 	ctx := c.Context()
-	svc, err := document.New(cmd.DB())
+	svc, err := cmd.OpenService()
 	if err != nil {
 		return cmd.PrintJSONError(fmt.Errorf("open store: %w", err))
 	}
@@ -261,10 +361,18 @@ func runSync(c *cobra.Command, _ []string) error {
 	}
 
 	opts := sync.Options{
+		Mirror: svc.Mirrored(),
 		Author: cmd.Author(),
 		Msg:    cmd.Message(),
 	}
 	opts.DryRun, _ = c.Flags().GetBool(extension.FlagDryRun)
+	opts.Prune, _ = c.Flags().GetBool(extension.FlagPrune)
+	opts.Safe, _ = c.Flags().GetBool(extension.FlagSafe)
+
+	if watch, _ := c.Flags().GetBool(extension.FlagWatch); watch {
+		debounce, _ := c.Flags().GetDuration(extension.FlagDebounce)
+		return runWatch(ctx, svc, dir, opts, debounce)
+	}
 
 	l := log.Event("sync:sync", "sync").
 		Author(cmd.Author())
@@ -277,11 +385,23 @@ func runSync(c *cobra.Command, _ []string) error {
 
 	l.Detail("added", result.Added).
 		Detail("updated", result.Updated).
+		Detail("deleted", result.Deleted).
+		Detail("conflicts", len(result.Conflicts)).
 		Write(nil)
 
-	total := result.Updated + result.Added
+	if opts.Safe && len(result.Conflicts) > 0 {
+		fmt.Fprintf(os.Stderr, "warning: %d conflict(s) skipped; compare with: llmd diff <path> -f <file>\n", len(result.Conflicts))
+	}
+
+	if n := len(result.Missing); n > 0 {
+		fmt.Fprintf(os.Stderr, "warning: %d document(s) have no file on disk; use --prune to delete them\n", n)
+	}
+
+	total := result.Updated + result.Added + result.Deleted
 	if total == 0 {
-		fmt.Fprintln(cmd.Out(), "No changes detected")
+		if len(result.Missing) == 0 && len(result.Conflicts) == 0 {
+			fmt.Fprintln(cmd.Out(), "No changes detected")
+		}
 		return nil
 	}
 
@@ -290,3 +410,40 @@ func runSync(c *cobra.Command, _ []string) error {
 	}
 	return nil
 }
+
+// runWatch syncs continuously until interrupted. Failed batches are
+// reported and logged but do not stop the watch; the next change retries.
+func runWatch(ctx context.Context, svc *document.Service, dir string, opts sync.Options, debounce time.Duration) error {
+	if opts.DryRun {
+		return cmd.PrintJSONError(fmt.Errorf("--watch cannot be combined with --dry-run"))
+	}
+
+	ctx, stop := signal.NotifyContext(ctx, os.Interrupt, syscall.SIGTERM)
+	defer stop()
+
+	fmt.Fprintf(cmd.Out(), "Watching %s (Ctrl+C to stop)\n", dir)
+
+	err := sync.Watch(ctx, cmd.Out(), svc, dir, sync.WatchOptions{
+		Options:  opts,
+		Debounce: debounce,
+		OnSync: func(result sync.Result, err error) {
+			if err != nil {
+				log.Event("sync:watch", "sync").Author(opts.Author).Write(err)
+				fmt.Fprintf(os.Stderr, "sync: %v\n", err)
+				return
+			}
+			for _, p := range result.Paths {
+				log.Event("sync:watch", "sync").Author(opts.Author).Path(p).Write(nil)
+			}
+			for _, p := range result.Conflicts {
+				if opts.Safe {
+					log.Event("sync:watch", "conflict").Author(opts.Author).Path(p).Write(nil)
+				}
+			}
+		},
+	})
+	if err != nil {
+		return cmd.PrintJSONError(fmt.Errorf("watch %s: %w", dir, err))
+	}
+	return nil
+}
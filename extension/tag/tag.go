@@ -1,8 +1,9 @@
 // Package tag provides the tag extension for llmd.
-// It registers commands: tag (with subcommands add, rm, ls).
+// It registers commands: tag (with subcommands add, rm, ls, rename).
 package tag
 
 import (
+	"errors"
 	"fmt"
 	"io"
 
@@ -40,7 +41,7 @@ func (e *Extension) Init(ctx extension.Context) error {
 	return nil
 }
 
-// Commands returns the tag command with its subcommands (add, rm, ls).
+// Commands returns the tag command with its subcommands (add, rm, ls, rename).
 func (e *Extension) Commands() []*cobra.Command {
 	return []*cobra.Command{
 		e.newTagCmd(),
@@ -134,21 +135,39 @@ func (e *Extension) newTagCmd() *cobra.Command {
 	c := &cobra.Command{
 		Use:   "tag",
 		Short: "Manage document tags",
-		Long:  `Add, remove, and list tags for documents.`,
+		Long:  `Add, remove, list, and rename tags for documents.`,
 	}
 	c.AddCommand(e.newTagAddCmd())
 	c.AddCommand(e.newTagRmCmd())
 	c.AddCommand(e.newTagLsCmd())
+	c.AddCommand(e.newTagRenameCmd())
 	return c
 }
 
 func (e *Extension) newTagAddCmd() *cobra.Command {
-	return &cobra.Command{
+	c := &cobra.Command{
 		Use:   "add <path|key> <tag>",
 		Short: "Add a tag to a document",
-		Args:  cobra.ExactArgs(2),
-		RunE:  e.runTagAdd,
+		Long: `Add a tag to a document, or to every document selected by --glob or
+--from-search, in which case only the tag is given.
+
+  llmd tag add docs/api important
+  llmd tag add --glob 'docs/api/**' important
+  llmd tag add --from-search "deprecated" obsolete`,
+		Args: func(c *cobra.Command, args []string) error {
+			glob, _ := c.Flags().GetString(extension.FlagGlob)
+			query, _ := c.Flags().GetString(extension.FlagFromSearch)
+			if glob != "" || query != "" {
+				return cobra.ExactArgs(1)(c, args)
+			}
+			return cobra.ExactArgs(2)(c, args)
+		},
+		RunE: e.runTagAdd,
 	}
+	c.Flags().String(extension.FlagGlob, "", "Tag every document whose path matches this glob")
+	c.Flags().String(extension.FlagFromSearch, "", "Tag every document matching this full-text query")
+	c.MarkFlagsMutuallyExclusive(extension.FlagGlob, extension.FlagFromSearch)
+	return c
 }
 
 func (e *Extension) newTagRmCmd() *cobra.Command {
@@ -161,15 +180,32 @@ func (e *Extension) newTagRmCmd() *cobra.Command {
 }
 
 func (e *Extension) newTagLsCmd() *cobra.Command {
-	return &cobra.Command{
+	c := &cobra.Command{
 		Use:   "ls [path|key]",
 		Short: "List tags for a document (or all tags if path omitted)",
 		Args:  cobra.MaximumNArgs(1),
 		RunE:  e.runTagLs,
 	}
+	c.Flags().BoolP(extension.FlagCount, "c", false, "List every tag with the number of documents having it")
+	return c
+}
+
+func (e *Extension) newTagRenameCmd() *cobra.Command {
+	return &cobra.Command{
+		Use:   "rename <old> <new>",
+		Short: "Rename a tag on every document that has it",
+		Args:  cobra.ExactArgs(2),
+		RunE:  e.runTagRename,
+	}
 }
 
 func (e *Extension) runTagAdd(c *cobra.Command, args []string) error {
+	glob, _ := c.Flags().GetString(extension.FlagGlob)
+	query, _ := c.Flags().GetString(extension.FlagFromSearch)
+	if glob != "" || query != "" {
+		return e.runTagAddMany(c, args[0], glob, query)
+	}
+
 	ctx := c.Context()
 	path, t := args[0], args[1]
 	w := cmd.Out()
@@ -193,6 +229,37 @@ func (e *Extension) runTagAdd(c *cobra.Command, args []string) error {
 	return cmd.PrintJSON(result)
 }
 
+// runTagAddMany tags every document selected by --glob or --from-search.
+func (e *Extension) runTagAddMany(c *cobra.Command, t, glob, query string) error {
+	ctx := c.Context()
+	w := cmd.Out()
+	if cmd.JSON() {
+		w = io.Discard
+	}
+
+	l := log.Event("tag:add", "tag").
+		Author(cmd.Author()).
+		Detail("tag", t)
+
+	var result tag.BulkResult
+	var err error
+	if glob != "" {
+		l.Detail("glob", glob)
+		result, err = tag.AddGlob(ctx, w, e.svc, glob, t)
+	} else {
+		l.Detail("query", query)
+		result, err = tag.AddSearch(ctx, w, e.svc, query, t)
+	}
+	if err != nil {
+		l.Write(err)
+		return cmd.PrintJSONError(fmt.Errorf("tag add %q: %w", t, err))
+	}
+
+	l.Detail("count", result.Count).Write(nil)
+
+	return cmd.PrintJSON(result)
+}
+
 func (e *Extension) runTagRm(c *cobra.Command, args []string) error {
 	ctx := c.Context()
 	path, t := args[0], args[1]
@@ -224,6 +291,13 @@ func (e *Extension) runTagLs(c *cobra.Command, args []string) error {
 		path = args[0]
 	}
 
+	if count, _ := c.Flags().GetBool(extension.FlagCount); count {
+		if path != "" {
+			return cmd.PrintJSONError(errors.New("tag ls --count lists all tags and takes no path"))
+		}
+		return e.runTagCounts(c)
+	}
+
 	w := cmd.Out()
 	if cmd.JSON() {
 		w = io.Discard
@@ -245,3 +319,49 @@ func (e *Extension) runTagLs(c *cobra.Command, args []string) error {
 
 	return cmd.PrintJSON(result)
 }
+
+// runTagCounts lists every tag with its document count.
+func (e *Extension) runTagCounts(c *cobra.Command) error {
+	w := cmd.Out()
+	if cmd.JSON() {
+		w = io.Discard
+	}
+
+	l := log.Event("tag:ls", "list_tags").
+		Author(cmd.Author()).
+		Detail("counts", true)
+
+	counts, err := tag.Counts(c.Context(), w, e.svc)
+	if err != nil {
+		l.Write(err)
+		return cmd.PrintJSONError(fmt.Errorf("tag ls --count: %w", err))
+	}
+
+	l.Detail("count", len(counts)).Write(nil)
+
+	return cmd.PrintJSON(counts)
+}
+
+func (e *Extension) runTagRename(c *cobra.Command, args []string) error {
+	ctx := c.Context()
+	from, to := args[0], args[1]
+	w := cmd.Out()
+	if cmd.JSON() {
+		w = io.Discard
+	}
+
+	l := log.Event("tag:rename", "rename_tag").
+		Author(cmd.Author()).
+		Detail("from", from).
+		Detail("to", to)
+
+	result, err := tag.Rename(ctx, w, e.svc, from, to)
+	if err != nil {
+		l.Write(err)
+		return cmd.PrintJSONError(fmt.Errorf("tag rename %q %q: %w", from, to, err))
+	}
+
+	l.Detail("count", result.Count).Write(nil)
+
+	return cmd.PrintJSON(result)
+}
@@ -4,7 +4,9 @@ package guide
 
 import (
 	"embed"
+	"errors"
 	"runtime"
+	"strings"
 )
 
 //go:embed *.md
@@ -43,3 +45,84 @@ func List() ([]string, error) {
 	}
 	return names, nil
 }
+
+// Topic describes a guide page for tables of contents.
+type Topic struct {
+	Name    string `json:"name"`
+	Summary string `json:"summary"`
+}
+
+// Topics returns the available guide pages with a one-line summary of
+// each, taken from the first paragraph after the page heading.
+func Topics() ([]Topic, error) {
+	names, err := List()
+	if err != nil {
+		return nil, err
+	}
+	topics := make([]Topic, 0, len(names))
+	for _, name := range names {
+		data, err := files.ReadFile(name + ".md")
+		if err != nil {
+			return nil, err
+		}
+		topics = append(topics, Topic{Name: name, Summary: summary(string(data))})
+	}
+	return topics, nil
+}
+
+// summary returns the first line of prose in a page, skipping headings.
+func summary(content string) string {
+	for line := range strings.Lines(content) {
+		line = strings.TrimSpace(line)
+		if line != "" && !strings.HasPrefix(line, "#") {
+			return line
+		}
+	}
+	return ""
+}
+
+// Line is a single matching line within a guide page.
+type Line struct {
+	Number int    `json:"line"`
+	Text   string `json:"text"`
+}
+
+// Match is a guide page containing a search term and the lines it
+// appears on.
+type Match struct {
+	Topic string `json:"topic"`
+	Lines []Line `json:"lines"`
+}
+
+// Search returns the pages containing term, matched case-insensitively.
+// Unlike List, the main "guide" page is included since it holds the
+// command overview a search is likely to be looking for.
+func Search(term string) ([]Match, error) {
+	if term == "" {
+		return nil, errors.New("search term is required")
+	}
+	entries, err := files.ReadDir(".")
+	if err != nil {
+		return nil, err
+	}
+	needle := strings.ToLower(term)
+	var matches []Match
+	for _, e := range entries {
+		data, err := files.ReadFile(e.Name())
+		if err != nil {
+			return nil, err
+		}
+		var lines []Line
+		n := 0
+		for line := range strings.Lines(string(data)) {
+			n++
+			if strings.Contains(strings.ToLower(line), needle) {
+				lines = append(lines, Line{Number: n, Text: strings.TrimRight(line, "\r\n")})
+			}
+		}
+		if len(lines) > 0 {
+			matches = append(matches, Match{Topic: strings.TrimSuffix(e.Name(), ".md"), Lines: lines})
+		}
+	}
+	return matches, nil
+}
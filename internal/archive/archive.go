@@ -0,0 +1,199 @@
+// Package archive reads and writes single-file document bundles for backup
+// and transfer.
+//
+// Two formats are supported, chosen by file name: gzip-compressed tar
+// (.tar.gz, .tgz) for Unix tooling and zip (.zip) for everything else.
+// Entry names always use forward slashes so an archive made on one
+// platform reads the same on another.
+package archive
+
+import (
+	"archive/tar"
+	"archive/zip"
+	"compress/gzip"
+	"fmt"
+	"io"
+	"os"
+	"strings"
+	"time"
+)
+
+// Format identifies an archive encoding.
+type Format string
+
+const (
+	TarGz Format = "tar.gz"
+	Zip   Format = "zip"
+)
+
+// FormatOf picks the format from an archive's file name.
+func FormatOf(name string) (Format, error) {
+	lower := strings.ToLower(name)
+	switch {
+	case strings.HasSuffix(lower, ".tar.gz"), strings.HasSuffix(lower, ".tgz"):
+		return TarGz, nil
+	case strings.HasSuffix(lower, ".zip"):
+		return Zip, nil
+	}
+	return "", fmt.Errorf("unsupported archive %s (use .tar.gz, .tgz or .zip)", name)
+}
+
+// Writer adds files to an archive. Close must be called to flush the
+// archive's trailing structures; it does not close the underlying writer.
+type Writer interface {
+	Add(name, content string, modTime time.Time) error
+	Close() error
+}
+
+// NewWriter returns a Writer that encodes f to w.
+func NewWriter(w io.Writer, f Format) (Writer, error) {
+	switch f {
+	case TarGz:
+		gz := gzip.NewWriter(w)
+		return &tarWriter{gz: gz, tw: tar.NewWriter(gz)}, nil
+	case Zip:
+		return &zipWriter{zw: zip.NewWriter(w)}, nil
+	}
+	return nil, fmt.Errorf("unsupported archive format %q", f)
+}
+
+type tarWriter struct {
+	gz *gzip.Writer
+	tw *tar.Writer
+}
+
+func (t *tarWriter) Add(name, content string, modTime time.Time) error {
+	hdr := &tar.Header{
+		Name:     name,
+		Mode:     0644,
+		Size:     int64(len(content)),
+		ModTime:  modTime,
+		Typeflag: tar.TypeReg,
+	}
+	if err := t.tw.WriteHeader(hdr); err != nil {
+		return fmt.Errorf("adding %s: %w", name, err)
+	}
+	if _, err := io.WriteString(t.tw, content); err != nil {
+		return fmt.Errorf("adding %s: %w", name, err)
+	}
+	return nil
+}
+
+func (t *tarWriter) Close() error {
+	if err := t.tw.Close(); err != nil {
+		return err
+	}
+	return t.gz.Close()
+}
+
+type zipWriter struct {
+	zw *zip.Writer
+}
+
+func (z *zipWriter) Add(name, content string, modTime time.Time) error {
+	f, err := z.zw.CreateHeader(&zip.FileHeader{
+		Name:     name,
+		Method:   zip.Deflate,
+		Modified: modTime,
+	})
+	if err != nil {
+		return fmt.Errorf("adding %s: %w", name, err)
+	}
+	if _, err := io.WriteString(f, content); err != nil {
+		return fmt.Errorf("adding %s: %w", name, err)
+	}
+	return nil
+}
+
+func (z *zipWriter) Close() error {
+	return z.zw.Close()
+}
+
+// Entry is a regular file read from an archive. Name is as stored in the
+// archive and has not been validated; callers must not trust it as a path.
+type Entry struct {
+	Name    string
+	Content []byte
+}
+
+// Walk calls fn for each regular file in the archive at name, in archive
+// order. Directories, links and other special entries are skipped. The
+// format is chosen by FormatOf.
+func Walk(name string, fn func(Entry) error) error {
+	f, err := FormatOf(name)
+	if err != nil {
+		return err
+	}
+	switch f {
+	case TarGz:
+		return walkTar(name, fn)
+	default:
+		return walkZip(name, fn)
+	}
+}
+
+func walkTar(name string, fn func(Entry) error) error {
+	file, err := os.Open(name)
+	if err != nil {
+		return err
+	}
+	defer file.Close()
+
+	gz, err := gzip.NewReader(file)
+	if err != nil {
+		return fmt.Errorf("reading %s: %w", name, err)
+	}
+	defer gz.Close()
+
+	tr := tar.NewReader(gz)
+	for {
+		hdr, err := tr.Next()
+		if err == io.EOF {
+			return nil
+		}
+		if err != nil {
+			return fmt.Errorf("reading %s: %w", name, err)
+		}
+		if hdr.Typeflag != tar.TypeReg {
+			continue
+		}
+		content, err := io.ReadAll(tr)
+		if err != nil {
+			return fmt.Errorf("reading %s from %s: %w", hdr.Name, name, err)
+		}
+		if err := fn(Entry{Name: hdr.Name, Content: content}); err != nil {
+			return err
+		}
+	}
+}
+
+func walkZip(name string, fn func(Entry) error) error {
+	zr, err := zip.OpenReader(name)
+	if err != nil {
+		return fmt.Errorf("reading %s: %w", name, err)
+	}
+	defer zr.Close()
+
+	for _, zf := range zr.File {
+		if !zf.Mode().IsRegular() {
+			continue
+		}
+		content, err := readZipFile(zf)
+		if err != nil {
+			return fmt.Errorf("reading %s from %s: %w", zf.Name, name, err)
+		}
+		if err := fn(Entry{Name: zf.Name, Content: content}); err != nil {
+			return err
+		}
+	}
+	return nil
+}
+
+func readZipFile(zf *zip.File) ([]byte, error) {
+	rc, err := zf.Open()
+	if err != nil {
+		return nil, err
+	}
+	defer rc.Close()
+	return io.ReadAll(rc)
+}
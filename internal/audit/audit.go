@@ -0,0 +1,52 @@
+// Package audit provides a cross-document change ledger over a time range.
+//
+// History answers "how did this document change?"; audit answers "what
+// changed in the store, and who changed it?" for compliance reviews. It is
+// built from the stored versions rather than the audit event log, so it is
+// complete even when event logging is unavailable.
+package audit
+
+import (
+	"context"
+	"errors"
+	"io"
+	"time"
+
+	"github.com/jpl-au/llmd/internal/format"
+	"github.com/jpl-au/llmd/internal/service"
+	"github.com/jpl-au/llmd/internal/store"
+)
+
+// Options configures an audit operation.
+type Options struct {
+	Since     time.Time // Earliest version to include (zero = no lower bound)
+	Until     time.Time // Include versions written before this (zero = no upper bound)
+	Prefix    string    // Scope to a path prefix
+	Delimiter rune      // CSV/TSV output when non-zero (',' or '\t')
+}
+
+// Result contains the outcome of an audit operation.
+type Result struct {
+	Entries []store.AuditEntry
+}
+
+// Run collects the versions written in the range and writes them to w,
+// oldest first.
+func Run(ctx context.Context, w io.Writer, svc service.Service, opts Options) (Result, error) {
+	var result Result
+
+	if !opts.Since.IsZero() && !opts.Until.IsZero() && !opts.Since.Before(opts.Until) {
+		return result, errors.New("--since must be before --until")
+	}
+
+	entries, err := svc.AuditLog(ctx, opts.Since, opts.Until, opts.Prefix)
+	if err != nil {
+		return result, err
+	}
+	result.Entries = entries
+
+	if opts.Delimiter != 0 {
+		return result, format.AuditDelimited(w, entries, opts.Delimiter)
+	}
+	return result, format.Audit(w, entries)
+}
@@ -9,24 +9,42 @@ package cat
 import (
 	"bufio"
 	"context"
+	"errors"
 	"fmt"
 	"io"
 	"strconv"
 	"strings"
+	"time"
 
+	"github.com/jpl-au/llmd/internal/edit"
+	"github.com/jpl-au/llmd/internal/redact"
 	"github.com/jpl-au/llmd/internal/service"
 	"github.com/jpl-au/llmd/internal/store"
+	"github.com/jpl-au/llmd/internal/transclude"
 )
 
 // minLineNumWidth is the minimum column width for line numbers.
 // Ensures consistent formatting for typical documents.
 const minLineNumWidth = 6
 
+// MaxVersions caps how many versions one version set may name, so a range
+// like "1:999999999" fails at once instead of expanding in memory.
+const MaxVersions = 1000
+
 // Options configures a cat operation.
 type Options struct {
 	Version        int  // Specific version to read (0 = latest)
 	IncludeDeleted bool // Allow reading deleted documents
 	LineNumbers    bool // Show line numbers (-n flag)
+	Expand         bool // Inline {{include:path}} directives (see internal/transclude)
+
+	// Redact masks secrets in the content before anything else reads it,
+	// including Section and the returned Document (nil = no redaction).
+	Redact *redact.Redactor
+
+	// AsOf reads the version that was current at this time (zero = latest).
+	// Cannot be combined with Version.
+	AsOf time.Time
 
 	// StartLine and EndLine enable reading specific sections of large documents.
 	// This is critical for LLMs working with large files - they can read just the
@@ -36,14 +54,123 @@ type Options struct {
 	StartLine int // First line to show (1-indexed, 0 = start)
 	EndLine   int // Last line to show (1-indexed, 0 = end)
 
+	// Section shows only the section under this heading ("## Usage"), found
+	// the same way as "edit --section". Cannot be combined with StartLine
+	// or EndLine.
+	Section string
+
 	// MaxLineLength is the maximum line length for scanning (0 = default 10MB).
 	// Needed for documents with very long lines (minified JS, large JSON).
 	MaxLineLength int
+
+	// MaxContent bounds the content Expand may produce, in bytes (0 = the
+	// default limits.max_content).
+	MaxContent int64
 }
 
 // Result contains the outcome of a cat operation.
 type Result struct {
 	Document *store.Document
+	Section  *Section // Set when Options.Section selected part of the document
+}
+
+// Section is the part of a document read with Options.Section.
+type Section struct {
+	Path      string `json:"path"`
+	Heading   string `json:"heading"`
+	Level     int    `json:"level"`
+	Content   string `json:"content"`
+	StartLine int    `json:"start_line"`
+	EndLine   int    `json:"end_line"`
+}
+
+// ParseVersions parses the version set given to "cat -v": a single version
+// ("3"), a list ("1,3,5"), a range ("1:5"), or a list mixing both
+// ("1,3:5"). Versions are returned in the order given, without repeats. A
+// set naming more than MaxVersions versions is an error.
+func ParseVersions(s string) ([]int, error) {
+	var versions []int
+	seen := make(map[int]bool)
+	add := func(v int) {
+		if !seen[v] {
+			seen[v] = true
+			versions = append(versions, v)
+		}
+	}
+
+	for item := range strings.SplitSeq(s, ",") {
+		item = strings.TrimSpace(item)
+		from, to, isRange := strings.Cut(item, ":")
+		start, err := parseVersion(from)
+		if err != nil {
+			return nil, err
+		}
+		if !isRange {
+			add(start)
+			if len(versions) > MaxVersions {
+				return nil, fmt.Errorf("version set %q names more than %d versions", s, MaxVersions)
+			}
+			continue
+		}
+		end, err := parseVersion(to)
+		if err != nil {
+			return nil, err
+		}
+		if start > end {
+			return nil, fmt.Errorf("invalid version range %q: start is greater than end", item)
+		}
+		if end-start >= MaxVersions-len(versions) {
+			return nil, fmt.Errorf("version set %q names more than %d versions", s, MaxVersions)
+		}
+		for v := start; v <= end; v++ {
+			add(v)
+		}
+	}
+	return versions, nil
+}
+
+// parseVersion parses one version number of a version set.
+func parseVersion(s string) (int, error) {
+	v, err := strconv.Atoi(s)
+	if err != nil || v < 1 {
+		return 0, fmt.Errorf("invalid version %q (expected a number >= 1)", s)
+	}
+	return v, nil
+}
+
+// Versions reads several versions of a document as Run does for one, and
+// writes each to w under a "==> path (vN) <==" header, separated by blank
+// lines. Every version is read before anything is written, so a version
+// that does not exist fails without partial output and the error names it.
+func Versions(ctx context.Context, w io.Writer, svc service.Service, path string, versions []int, opts Options) ([]Result, error) {
+	if !opts.AsOf.IsZero() {
+		return nil, errors.New("--as-of cannot be combined with --version")
+	}
+
+	results := make([]Result, len(versions))
+	outputs := make([]string, len(versions))
+	for i, v := range versions {
+		var buf strings.Builder
+		o := opts
+		o.Version = v
+		r, err := Run(ctx, &buf, svc, path, o)
+		if err != nil {
+			return nil, fmt.Errorf("version %d: %w", v, err)
+		}
+		results[i], outputs[i] = r, buf.String()
+	}
+
+	for i, r := range results {
+		if i > 0 {
+			fmt.Fprintln(w)
+		}
+		fmt.Fprintf(w, "==> %s (v%d) <==\n", r.Document.Path, r.Document.Version)
+		fmt.Fprint(w, outputs[i])
+		if outputs[i] != "" && !strings.HasSuffix(outputs[i], "\n") {
+			fmt.Fprintln(w)
+		}
+	}
+	return results, nil
 }
 
 // Run reads a document and writes its content to w.
@@ -52,9 +179,23 @@ func Run(ctx context.Context, w io.Writer, svc service.Service, path string, opt
 	var doc *store.Document
 	var err error
 
-	if opts.Version > 0 {
+	if opts.Section != "" && (opts.StartLine > 0 || opts.EndLine > 0) {
+		return result, errors.New("--section cannot be combined with --lines")
+	}
+
+	switch {
+	case opts.Version > 0 && !opts.AsOf.IsZero():
+		return result, errors.New("--as-of cannot be combined with --version")
+	case opts.Version > 0:
 		doc, err = svc.Version(ctx, path, opts.Version)
-	} else {
+	case !opts.AsOf.IsZero():
+		// Resolve first so keys work too; the document may have been deleted
+		// since t, so deleted documents must resolve
+		doc, _, err = svc.Resolve(ctx, path, true)
+		if err == nil {
+			doc, err = svc.VersionAsOf(ctx, doc.Path, opts.AsOf)
+		}
+	default:
 		// Use Resolve to handle both paths and keys
 		doc, _, err = svc.Resolve(ctx, path, opts.IncludeDeleted)
 	}
@@ -62,8 +203,44 @@ func Run(ctx context.Context, w io.Writer, svc service.Service, path string, opt
 		return result, err
 	}
 
+	if opts.Expand {
+		content, err := transclude.Expand(ctx, svc, doc.Path, doc.Content, opts.MaxContent)
+		if err != nil {
+			return result, err
+		}
+		expanded := *doc
+		expanded.Content = content
+		doc = &expanded
+	}
+
+	if opts.Redact != nil {
+		redacted := *doc
+		redacted.Content = opts.Redact.Apply(doc.Content)
+		doc = &redacted
+	}
+
 	result.Document = doc
 
+	if opts.Section != "" {
+		sec, err := edit.FindSection(doc.Content, opts.Section)
+		if err != nil {
+			return result, err
+		}
+		content, err := edit.Lines(doc.Content, sec.Line, sec.End)
+		if err != nil {
+			return result, err
+		}
+		result.Section = &Section{
+			Path:      doc.Path,
+			Heading:   sec.Text,
+			Level:     sec.Level,
+			Content:   content,
+			StartLine: sec.Line,
+			EndLine:   sec.End,
+		}
+		opts.StartLine, opts.EndLine = sec.Line, sec.End
+	}
+
 	// Fast path: no line range and no line numbers - output content as-is
 	if opts.StartLine == 0 && opts.EndLine == 0 && !opts.LineNumbers {
 		fmt.Fprint(w, doc.Content)
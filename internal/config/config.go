@@ -8,8 +8,12 @@ import (
 	"errors"
 	"fmt"
 	"io/fs"
+	"net/mail"
 	"os"
 	"path/filepath"
+	"regexp"
+	"strings"
+	"time"
 
 	"gopkg.in/yaml.v3"
 )
@@ -35,8 +39,37 @@ const (
 
 // Author represents the author metadata stored in the repository config.
 type Author struct {
-	Name  string `yaml:"name,omitempty"`
-	Email string `yaml:"email,omitempty"`
+	Name     string `yaml:"name,omitempty"`
+	Email    string `yaml:"email,omitempty"`
+	Required *bool  `yaml:"required,omitempty"` // reject writes without an author
+}
+
+// String formats the author as "Name <email>", or just the name when no
+// email is set.
+func (a Author) String() string {
+	if a.Email == "" {
+		return a.Name
+	}
+	return a.Name + " <" + a.Email + ">"
+}
+
+// ParseAuthor parses "Name <email>" or a bare "Name" into an Author.
+func ParseAuthor(s string) (Author, error) {
+	s = strings.TrimSpace(s)
+	if !strings.Contains(s, "<") {
+		if s == "" {
+			return Author{}, fmt.Errorf("%w: author name cannot be empty", ErrInvalidValue)
+		}
+		return Author{Name: s}, nil
+	}
+	addr, err := mail.ParseAddress(s)
+	if err != nil {
+		return Author{}, fmt.Errorf("%w: author must be \"Name <email>\": %v", ErrInvalidValue, err)
+	}
+	if addr.Name == "" {
+		return Author{}, fmt.Errorf("%w: author name cannot be empty", ErrInvalidValue)
+	}
+	return Author{Name: addr.Name, Email: addr.Address}, nil
 }
 
 // Sync holds sync-related configuration options.
@@ -51,13 +84,45 @@ type Limits struct {
 	MaxLineLength *int   `yaml:"max_line_length,omitempty"`
 }
 
+// Store holds database connection options.
+type Store struct {
+	BusyTimeout *int `yaml:"busy_timeout,omitempty"` // milliseconds
+}
+
+// Search holds full-text search options.
+type Search struct {
+	Tokenizer string `yaml:"tokenizer,omitempty"` // FTS5 tokenizer; empty means the default
+}
+
+// MCP holds guards applied to MCP tool calls that change documents. Zero
+// (the default) leaves a guard off.
+type MCP struct {
+	MaxWrite        *int64 `yaml:"max_write,omitempty"`         // bytes per write or edit
+	WritesPerMinute *int   `yaml:"writes_per_minute,omitempty"` // per client session
+}
+
+// Redact holds the patterns masked by "cat --redact", and whether an MCP
+// server masks them in every document it returns.
+type Redact struct {
+	Patterns []string `yaml:"patterns,omitempty"` // regular expressions; none means the built-in set
+	MCP      *bool    `yaml:"mcp,omitempty"`
+}
+
 // Default limits applied when not configured.
 const (
 	DefaultMaxPath       = 1024
 	DefaultMaxContent    = 100 * 1024 * 1024 // 100 MB
 	DefaultMaxLineLength = 10 * 1024 * 1024  // 10 MB
+	DefaultBusyTimeout   = 5000              // 5 seconds, in milliseconds
 )
 
+// DefaultTokenizer is the FTS5 tokenizer used when search.tokenizer is not
+// set. It matches store.DefaultTokenizer.
+const DefaultTokenizer = "unicode61 remove_diacritics 2"
+
+// tokenizerRe matches an FTS5 tokenizer name followed by bare arguments.
+var tokenizerRe = regexp.MustCompile(`^[a-z0-9_]+( [a-z0-9_]+)*$`)
+
 // Validation bounds for configuration values.
 const (
 	MinMaxPath       = 1
@@ -66,6 +131,9 @@ const (
 	MaxMaxContent    = 10 * 1024 * 1024 * 1024 // 10 GB - reasonable upper bound
 	MinMaxLineLength = 1
 	MaxMaxLineLength = 1024 * 1024 * 1024 // 1 GB
+	MinBusyTimeout   = 1
+	MaxBusyTimeout   = 10 * 60 * 1000 // 10 minutes
+	MaxWritesPerMin  = 100000
 )
 
 // Config contains configuration for llmd.
@@ -73,6 +141,10 @@ type Config struct {
 	Author Author `yaml:"author,omitempty"`
 	Sync   Sync   `yaml:"sync,omitempty"`
 	Limits Limits `yaml:"limits,omitempty"`
+	Store  Store  `yaml:"store,omitempty"`
+	Search Search `yaml:"search,omitempty"`
+	MCP    MCP    `yaml:"mcp,omitempty"`
+	Redact Redact `yaml:"redact,omitempty"`
 
 	// path is the file this config was loaded from (for Save)
 	path  string
@@ -103,9 +175,50 @@ func (c *Config) Validate() error {
 				ErrInvalidValue, MinMaxLineLength, MaxMaxLineLength, v)
 		}
 	}
+	if c.Store.BusyTimeout != nil {
+		v := *c.Store.BusyTimeout
+		if v < MinBusyTimeout || v > MaxBusyTimeout {
+			return fmt.Errorf("%w: busy_timeout must be between %d and %d milliseconds, got %d",
+				ErrInvalidValue, MinBusyTimeout, MaxBusyTimeout, v)
+		}
+	}
+	// The tokenizer is spliced into the CREATE VIRTUAL TABLE statement, so
+	// only names and bare arguments are allowed, never quotes
+	if c.Search.Tokenizer != "" && !tokenizerRe.MatchString(c.Search.Tokenizer) {
+		return fmt.Errorf("%w: search.tokenizer must be a tokenizer name and arguments (e.g. %q), got %q",
+			ErrInvalidValue, DefaultTokenizer, c.Search.Tokenizer)
+	}
+	if c.MCP.MaxWrite != nil {
+		v := *c.MCP.MaxWrite
+		if v < 0 || v > MaxMaxContent {
+			return fmt.Errorf("%w: mcp.max_write must be between 0 and %d, got %d",
+				ErrInvalidValue, int64(MaxMaxContent), v)
+		}
+	}
+	if c.MCP.WritesPerMinute != nil {
+		v := *c.MCP.WritesPerMinute
+		if v < 0 || v > MaxWritesPerMin {
+			return fmt.Errorf("%w: mcp.writes_per_minute must be between 0 and %d, got %d",
+				ErrInvalidValue, MaxWritesPerMin, v)
+		}
+	}
+	for _, p := range c.Redact.Patterns {
+		if _, err := regexp.Compile(p); err != nil {
+			return fmt.Errorf("%w: redact.patterns: %q: %v", ErrInvalidValue, p, err)
+		}
+	}
 	return nil
 }
 
+// AuthorRequired returns whether writes must name an author rather than
+// fall back to the default attribution (defaults to false).
+func (c *Config) AuthorRequired() bool {
+	if c.Author.Required == nil {
+		return false
+	}
+	return *c.Author.Required
+}
+
 // SyncFiles returns whether file syncing is enabled (defaults to false)
 func (c *Config) SyncFiles() bool {
 	if c.Sync.Files == nil {
@@ -140,6 +253,61 @@ func (c *Config) MaxLineLength() int {
 	return *c.Limits.MaxLineLength
 }
 
+// BusyTimeout returns how long a database operation waits for another
+// process's lock before failing (defaults to 5 seconds). Raise it when
+// several llmd processes, such as an MCP server and the CLI, write heavily
+// to the same store.
+func (c *Config) BusyTimeout() time.Duration {
+	if c.Store.BusyTimeout == nil {
+		return DefaultBusyTimeout * time.Millisecond
+	}
+	return time.Duration(*c.Store.BusyTimeout) * time.Millisecond
+}
+
+// SearchTokenizer returns the FTS5 tokenizer full-text search indexes
+// documents with (defaults to "unicode61 remove_diacritics 2", which folds
+// case and accents). Changing it rebuilds the index the next time the store
+// is opened for writing.
+func (c *Config) SearchTokenizer() string {
+	if c.Search.Tokenizer == "" {
+		return DefaultTokenizer
+	}
+	return c.Search.Tokenizer
+}
+
+// MCPMaxWrite returns the largest content an MCP client may send in one
+// write or edit, in bytes. Zero means only limits.max_content applies.
+func (c *Config) MCPMaxWrite() int64 {
+	if c.MCP.MaxWrite == nil {
+		return 0
+	}
+	return *c.MCP.MaxWrite
+}
+
+// MCPWritesPerMinute returns how many changes one MCP client session may
+// make per minute. Zero means unlimited.
+func (c *Config) MCPWritesPerMinute() int {
+	if c.MCP.WritesPerMinute == nil {
+		return 0
+	}
+	return *c.MCP.WritesPerMinute
+}
+
+// RedactPatterns returns the configured redaction patterns. Empty means the
+// built-in set (see package redact).
+func (c *Config) RedactPatterns() []string {
+	return c.Redact.Patterns
+}
+
+// RedactMCP returns whether an MCP server redacts the documents it returns
+// (defaults to false).
+func (c *Config) RedactMCP() bool {
+	if c.Redact.MCP == nil {
+		return false
+	}
+	return *c.Redact.MCP
+}
+
 // LocalPath returns the path to the local (repository) config file.
 func LocalPath() string {
 	return filepath.Join(".llmd", "config.yaml")
@@ -21,9 +21,13 @@ import (
 // ValidKeys returns all valid configuration keys.
 func ValidKeys() []string {
 	return []string{
-		"author.name", "author.email",
+		"author.name", "author.email", "author.required",
 		"sync.files",
 		"limits.max_path", "limits.max_content", "limits.max_line_length",
+		"store.busy_timeout",
+		"search.tokenizer",
+		"mcp.max_write", "mcp.writes_per_minute",
+		"redact.patterns", "redact.mcp",
 	}
 }
 
@@ -33,12 +37,17 @@ func IsValidKey(key string) bool {
 }
 
 // Get returns the value of a configuration key as a string.
+// "author" is a shorthand for the name and email together ("Name <email>").
 func (c *Config) Get(key string) (string, error) {
 	switch key {
+	case "author":
+		return c.Author.String(), nil
 	case "author.name":
 		return c.Author.Name, nil
 	case "author.email":
 		return c.Author.Email, nil
+	case "author.required":
+		return strconv.FormatBool(c.AuthorRequired()), nil
 	case "sync.files":
 		if c.SyncFiles() {
 			return "true", nil
@@ -50,18 +59,44 @@ func (c *Config) Get(key string) (string, error) {
 		return strconv.FormatInt(c.MaxContent(), 10), nil
 	case "limits.max_line_length":
 		return strconv.Itoa(c.MaxLineLength()), nil
+	case "store.busy_timeout":
+		return strconv.FormatInt(c.BusyTimeout().Milliseconds(), 10), nil
+	case "search.tokenizer":
+		return c.SearchTokenizer(), nil
+	case "mcp.max_write":
+		return strconv.FormatInt(c.MCPMaxWrite(), 10), nil
+	case "mcp.writes_per_minute":
+		return strconv.Itoa(c.MCPWritesPerMinute()), nil
+	case "redact.patterns":
+		return strings.Join(c.RedactPatterns(), "\n"), nil
+	case "redact.mcp":
+		return strconv.FormatBool(c.RedactMCP()), nil
 	default:
 		return "", fmt.Errorf("%w: %s", ErrUnknownKey, key)
 	}
 }
 
-// Set sets the value of a configuration key.
+// Set sets the value of a configuration key. Setting "author" parses
+// "Name <email>" and replaces both the name and the email.
 func (c *Config) Set(key, value string) error {
 	switch key {
+	case "author":
+		a, err := ParseAuthor(value)
+		if err != nil {
+			return err
+		}
+		c.Author.Name, c.Author.Email = a.Name, a.Email
 	case "author.name":
 		c.Author.Name = value
 	case "author.email":
 		c.Author.Email = value
+	case "author.required":
+		v := strings.ToLower(value)
+		if v != "true" && v != "false" {
+			return fmt.Errorf("%w: author.required must be true or false", ErrInvalidValue)
+		}
+		b := v == "true"
+		c.Author.Required = &b
 	case "sync.files":
 		v := strings.ToLower(value)
 		if v != "true" && v != "false" {
@@ -87,6 +122,42 @@ func (c *Config) Set(key, value string) error {
 			return fmt.Errorf("%w: limits.max_line_length must be a positive integer", ErrInvalidValue)
 		}
 		c.Limits.MaxLineLength = &n
+	case "store.busy_timeout":
+		n, err := strconv.Atoi(value)
+		if err != nil || n <= 0 {
+			return fmt.Errorf("%w: store.busy_timeout must be a positive number of milliseconds", ErrInvalidValue)
+		}
+		c.Store.BusyTimeout = &n
+	case "search.tokenizer":
+		c.Search.Tokenizer = strings.Join(strings.Fields(value), " ")
+	case "mcp.max_write":
+		n, err := strconv.ParseInt(value, 10, 64)
+		if err != nil || n < 0 {
+			return fmt.Errorf("%w: mcp.max_write must be a number of bytes (0 for no limit)", ErrInvalidValue)
+		}
+		c.MCP.MaxWrite = &n
+	case "mcp.writes_per_minute":
+		n, err := strconv.Atoi(value)
+		if err != nil || n < 0 {
+			return fmt.Errorf("%w: mcp.writes_per_minute must be a whole number (0 for no limit)", ErrInvalidValue)
+		}
+		c.MCP.WritesPerMinute = &n
+	case "redact.patterns":
+		// One pattern per line, as a regular expression may contain commas
+		var patterns []string
+		for p := range strings.SplitSeq(value, "\n") {
+			if p = strings.TrimSpace(p); p != "" {
+				patterns = append(patterns, p)
+			}
+		}
+		c.Redact.Patterns = patterns
+	case "redact.mcp":
+		v := strings.ToLower(value)
+		if v != "true" && v != "false" {
+			return fmt.Errorf("%w: redact.mcp must be true or false", ErrInvalidValue)
+		}
+		b := v == "true"
+		c.Redact.MCP = &b
 	default:
 		return fmt.Errorf("%w: %s", ErrUnknownKey, key)
 	}
@@ -99,10 +170,17 @@ func (c *Config) All() map[string]string {
 	return map[string]string{
 		"author.name":            c.Author.Name,
 		"author.email":           c.Author.Email,
+		"author.required":        strconv.FormatBool(c.AuthorRequired()),
 		"sync.files":             strconv.FormatBool(c.SyncFiles()),
 		"limits.max_path":        strconv.Itoa(c.MaxPath()),
 		"limits.max_content":     strconv.FormatInt(c.MaxContent(), 10),
 		"limits.max_line_length": strconv.Itoa(c.MaxLineLength()),
+		"store.busy_timeout":     strconv.FormatInt(c.BusyTimeout().Milliseconds(), 10),
+		"search.tokenizer":       c.SearchTokenizer(),
+		"mcp.max_write":          strconv.FormatInt(c.MCPMaxWrite(), 10),
+		"mcp.writes_per_minute":  strconv.Itoa(c.MCPWritesPerMinute()),
+		"redact.patterns":        strings.Join(c.RedactPatterns(), "\n"),
+		"redact.mcp":             strconv.FormatBool(c.RedactMCP()),
 	}
 }
 
@@ -113,6 +191,8 @@ func (c *Config) IsSet(key string) bool {
 		return c.Author.Name != ""
 	case "author.email":
 		return c.Author.Email != ""
+	case "author.required":
+		return c.Author.Required != nil
 	case "sync.files":
 		return c.Sync.Files != nil
 	case "limits.max_path":
@@ -121,6 +201,18 @@ func (c *Config) IsSet(key string) bool {
 		return c.Limits.MaxContent != nil
 	case "limits.max_line_length":
 		return c.Limits.MaxLineLength != nil
+	case "store.busy_timeout":
+		return c.Store.BusyTimeout != nil
+	case "search.tokenizer":
+		return c.Search.Tokenizer != ""
+	case "mcp.max_write":
+		return c.MCP.MaxWrite != nil
+	case "mcp.writes_per_minute":
+		return c.MCP.WritesPerMinute != nil
+	case "redact.patterns":
+		return len(c.Redact.Patterns) > 0
+	case "redact.mcp":
+		return c.Redact.MCP != nil
 	default:
 		return false
 	}
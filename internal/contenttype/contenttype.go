@@ -0,0 +1,70 @@
+// Package contenttype maps between document content types and file names.
+//
+// llmd stores markdown by default, but a document can record another type
+// (JSON, YAML, plain text) so that import, export and MCP resources treat it
+// correctly. The mapping is a fixed table rather than the system MIME
+// database so results do not depend on the host.
+package contenttype
+
+import (
+	"path/filepath"
+	"strings"
+)
+
+// Default is the content type of documents written without one.
+const Default = "text/markdown"
+
+// types maps lower-case file extensions to content types. The first
+// extension listed for a type is the one Extension returns.
+var types = []struct{ ext, typ string }{
+	{".md", Default},
+	{".markdown", Default},
+	{".mdx", Default},
+	{".txt", "text/plain"},
+	{".text", "text/plain"},
+	{".json", "application/json"},
+	{".yaml", "application/yaml"},
+	{".yml", "application/yaml"},
+	{".toml", "application/toml"},
+	{".xml", "application/xml"},
+	{".csv", "text/csv"},
+	{".html", "text/html"},
+	{".htm", "text/html"},
+}
+
+// FromName returns the content type for a file name by its extension, or
+// Default when the extension is missing or unknown.
+func FromName(name string) string {
+	ext := strings.ToLower(filepath.Ext(name))
+	for _, t := range types {
+		if t.ext == ext {
+			return t.typ
+		}
+	}
+	return Default
+}
+
+// IsMarkdown reports whether contentType is markdown. Parameters are ignored
+// and an empty type counts as markdown, the default.
+func IsMarkdown(contentType string) bool {
+	return contentType == "" || base(contentType) == Default
+}
+
+// Extension returns the file extension, with a leading dot, for a content
+// type. Parameters such as "; charset=utf-8" are ignored. Unknown types get
+// ".md", matching how llmd has always exported documents.
+func Extension(contentType string) string {
+	typ := base(contentType)
+	for _, t := range types {
+		if t.typ == typ {
+			return t.ext
+		}
+	}
+	return ".md"
+}
+
+// base returns contentType without parameters, lower-cased.
+func base(contentType string) string {
+	typ, _, _ := strings.Cut(contentType, ";")
+	return strings.ToLower(strings.TrimSpace(typ))
+}
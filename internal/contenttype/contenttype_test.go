@@ -0,0 +1,56 @@
+package contenttype
+
+import "testing"
+
+func TestFromName(t *testing.T) {
+	tests := []struct {
+		name string
+		want string
+	}{
+		{"readme.md", Default},
+		{"README.MD", Default},
+		{"config.json", "application/json"},
+		{"ci.yml", "application/yaml"},
+		{"notes.txt", "text/plain"},
+		{"dir.v2/noext", Default},
+		{"archive.unknown", Default},
+	}
+	for _, tt := range tests {
+		if got := FromName(tt.name); got != tt.want {
+			t.Errorf("FromName(%q) = %q, want %q", tt.name, got, tt.want)
+		}
+	}
+}
+
+func TestExtension(t *testing.T) {
+	tests := []struct {
+		typ  string
+		want string
+	}{
+		{Default, ".md"},
+		{"application/json", ".json"},
+		{"application/yaml", ".yaml"},
+		{"text/plain; charset=utf-8", ".txt"},
+		{"Text/HTML", ".html"},
+		{"application/octet-stream", ".md"},
+		{"", ".md"},
+	}
+	for _, tt := range tests {
+		if got := Extension(tt.typ); got != tt.want {
+			t.Errorf("Extension(%q) = %q, want %q", tt.typ, got, tt.want)
+		}
+	}
+}
+
+func TestIsMarkdown(t *testing.T) {
+	for _, ct := range []string{"", Default, "text/markdown; charset=utf-8", "Text/Markdown"} {
+		if !IsMarkdown(ct) {
+			t.Errorf("IsMarkdown(%q) = false, want true", ct)
+		}
+	}
+	for _, ct := range []string{"text/plain", "application/json"} {
+		if IsMarkdown(ct) {
+			t.Errorf("IsMarkdown(%q) = true, want false", ct)
+		}
+	}
+}
@@ -0,0 +1,64 @@
+// Package cp provides copying of documents.
+//
+// A copy is a new document: it starts at version 1 with the latest content
+// of its source and a "Copied from" message, so history does not carry over.
+// The copier is recorded as the author, for the audit trail. A recursive
+// copy duplicates a whole prefix in one transaction, which makes it a cheap
+// snapshot or fork of a section of the store.
+package cp
+
+import (
+	"context"
+	"fmt"
+	"io"
+	"path"
+	"strings"
+
+	"github.com/jpl-au/llmd/internal/service"
+)
+
+// Options configures a copy operation.
+type Options struct {
+	Recursive bool   // Copy all documents under the source prefix
+	Author    string // Who performed the copy
+}
+
+// Result contains the outcome of a copy operation.
+type Result struct {
+	From  string `json:"from"`
+	To    string `json:"to"`
+	Count int    `json:"count"`
+}
+
+// Run copies from to to. Without Recursive, from is a single document and
+// a to ending in "/" copies it under that prefix keeping its base name, as
+// mv does. With Recursive, every document under the from prefix is copied
+// to the same relative path under to; if any destination exists nothing is
+// copied.
+func Run(ctx context.Context, w io.Writer, svc service.Service, from, to string, opts Options) (Result, error) {
+	result := Result{From: from, To: to}
+	if from == "" || to == "" {
+		return result, fmt.Errorf("source and destination cannot be empty")
+	}
+
+	if opts.Recursive {
+		n, err := svc.CopyPrefix(ctx, from, to, opts.Author)
+		if err != nil {
+			return result, err
+		}
+		result.Count = n
+		fmt.Fprintf(w, "Copied %d document(s) from %s to %s\n", n, from, to)
+		return result, nil
+	}
+
+	if strings.HasSuffix(to, "/") {
+		to = path.Join(to, path.Base(from))
+		result.To = to
+	}
+	if err := svc.Copy(ctx, from, to, opts.Author); err != nil {
+		return result, err
+	}
+	result.Count = 1
+	fmt.Fprintf(w, "Copied %s -> %s\n", from, to)
+	return result, nil
+}
@@ -0,0 +1,199 @@
+// Package dedup finds documents with identical or similar content.
+//
+// Exact duplicates come from Service.Duplicates, which groups documents by
+// the stored hash of their content. Near-duplicates are optional and found
+// by shingling: each document becomes the set of its overlapping runs of
+// ShingleSize words, and two documents are similar when the Jaccard
+// similarity of their sets (shared shingles over all shingles) reaches the
+// threshold. Documents linked by similar pairs form a group, so a group
+// may hold two documents that are only similar through a third.
+//
+// Design: Every pair is compared, which is quadratic but cheap at the
+// sizes a document store reaches; pairs whose set sizes alone rule out the
+// threshold are skipped without comparing shingles. Wording is compared
+// after folding case and whitespace, so reflowed or recapitalised copies
+// still match.
+package dedup
+
+import (
+	"context"
+	"fmt"
+	"hash/fnv"
+	"io"
+	"math"
+	"slices"
+	"strings"
+
+	"github.com/jpl-au/llmd/internal/service"
+	"github.com/jpl-au/llmd/internal/store"
+)
+
+// ShingleSize is the number of words in each shingle.
+const ShingleSize = 5
+
+// Options configures a duplicate search.
+type Options struct {
+	Prefix  string  // Only consider documents under this prefix
+	Similar float64 // If > 0, also find near-duplicates at or above this similarity (0-1)
+}
+
+// NearGroup is a set of documents with similar but not identical content.
+type NearGroup struct {
+	Paths      []string `json:"paths"`      // Sorted
+	Similarity float64  `json:"similarity"` // Highest similarity between two of Paths
+}
+
+// Result contains the duplicate groups found.
+type Result struct {
+	Exact []store.DuplicateGroup `json:"exact"`
+	Near  []NearGroup            `json:"near,omitempty"`
+}
+
+// Run finds duplicate documents and writes the groups to w.
+func Run(ctx context.Context, w io.Writer, svc service.Service, opts Options) (Result, error) {
+	if opts.Similar < 0 || opts.Similar > 1 {
+		return Result{}, fmt.Errorf("similarity must be between 0 and 1, got %g", opts.Similar)
+	}
+
+	exact, err := svc.Duplicates(ctx, opts.Prefix)
+	if err != nil {
+		return Result{}, err
+	}
+	result := Result{Exact: exact}
+	if result.Exact == nil {
+		result.Exact = []store.DuplicateGroup{}
+	}
+
+	if opts.Similar > 0 {
+		docs, err := svc.List(ctx, opts.Prefix, false, false)
+		if err != nil {
+			return result, err
+		}
+		result.Near = Near(docs, exact, opts.Similar)
+	}
+
+	for _, g := range result.Exact {
+		fmt.Fprintln(w, "Identical:")
+		for _, p := range g.Paths {
+			fmt.Fprintf(w, "  %s\n", p)
+		}
+	}
+	for _, g := range result.Near {
+		fmt.Fprintf(w, "Similar (%.0f%%):\n", g.Similarity*100)
+		for _, p := range g.Paths {
+			fmt.Fprintf(w, "  %s\n", p)
+		}
+	}
+	if len(result.Exact) == 0 && len(result.Near) == 0 {
+		fmt.Fprintln(w, "No duplicates found")
+	}
+	return result, nil
+}
+
+// Near groups docs whose shingle sets have a Jaccard similarity of at
+// least threshold. Documents in the same exact group are not compared with
+// each other, as they are reported as identical already.
+func Near(docs []store.Document, exact []store.DuplicateGroup, threshold float64) []NearGroup {
+	same := make(map[string]int, len(docs))
+	for i, g := range exact {
+		for _, p := range g.Paths {
+			same[p] = i + 1
+		}
+	}
+
+	sets := make([]map[uint64]struct{}, len(docs))
+	for i, d := range docs {
+		sets[i] = Shingles(d.Content)
+	}
+
+	// Union-find over the similar pairs, tracking each root's best score
+	parent := make([]int, len(docs))
+	for i := range parent {
+		parent[i] = i
+	}
+	var find func(int) int
+	find = func(i int) int {
+		if parent[i] != i {
+			parent[i] = find(parent[i])
+		}
+		return parent[i]
+	}
+	best := make(map[int]float64)
+
+	for i := range docs {
+		for j := i + 1; j < len(docs); j++ {
+			if g := same[docs[i].Path]; g != 0 && g == same[docs[j].Path] {
+				continue
+			}
+			// Jaccard cannot exceed the ratio of the set sizes
+			a, b := len(sets[i]), len(sets[j])
+			if float64(min(a, b)) < threshold*float64(max(a, b)) {
+				continue
+			}
+			sim := Jaccard(sets[i], sets[j])
+			if sim < threshold {
+				continue
+			}
+			ri, rj := find(i), find(j)
+			score := max(sim, best[ri], best[rj])
+			if ri != rj {
+				parent[rj] = ri
+				delete(best, rj)
+			}
+			best[ri] = score
+		}
+	}
+
+	members := make(map[int][]string)
+	for i, d := range docs {
+		if r := find(i); best[r] > 0 {
+			members[r] = append(members[r], d.Path)
+		}
+	}
+	groups := make([]NearGroup, 0, len(members))
+	for r, paths := range members {
+		slices.Sort(paths)
+		groups = append(groups, NearGroup{Paths: paths, Similarity: math.Round(best[r]*100) / 100})
+	}
+	slices.SortFunc(groups, func(a, b NearGroup) int {
+		return strings.Compare(a.Paths[0], b.Paths[0])
+	})
+	return groups
+}
+
+// Shingles returns the hashed set of ShingleSize-word runs in content,
+// compared case-insensitively. Content shorter than a shingle is one
+// shingle of all its words.
+func Shingles(content string) map[uint64]struct{} {
+	words := strings.Fields(strings.ToLower(content))
+	set := make(map[uint64]struct{})
+	n := max(len(words)-ShingleSize+1, 1)
+	for i := range n {
+		h := fnv.New64a()
+		for _, w := range words[i:min(i+ShingleSize, len(words))] {
+			h.Write([]byte(w))
+			h.Write([]byte{0})
+		}
+		set[h.Sum64()] = struct{}{}
+	}
+	return set
+}
+
+// Jaccard returns the size of the intersection of a and b over the size of
+// their union, or 0 if both are empty.
+func Jaccard(a, b map[uint64]struct{}) float64 {
+	if len(a) > len(b) {
+		a, b = b, a
+	}
+	shared := 0
+	for h := range a {
+		if _, ok := b[h]; ok {
+			shared++
+		}
+	}
+	union := len(a) + len(b) - shared
+	if union == 0 {
+		return 0
+	}
+	return float64(shared) / float64(union)
+}
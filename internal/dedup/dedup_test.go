@@ -0,0 +1,35 @@
+package dedup_test
+
+import (
+	"testing"
+
+	"github.com/jpl-au/llmd/internal/dedup"
+	"github.com/jpl-au/llmd/internal/store"
+	"github.com/stretchr/testify/assert"
+)
+
+func TestJaccard(t *testing.T) {
+	a := dedup.Shingles("the quick brown fox jumps over the lazy dog")
+	assert.Equal(t, 1.0, dedup.Jaccard(a, dedup.Shingles("The quick brown\nfox  jumps over the lazy dog")))
+	assert.Equal(t, 0.0, dedup.Jaccard(a, dedup.Shingles("something else entirely with no shared runs")))
+
+	// Changing the last word keeps four of the five shingles
+	b := dedup.Shingles("the quick brown fox jumps over the lazy cat")
+	assert.InDelta(t, 4.0/6.0, dedup.Jaccard(a, b), 0.001)
+}
+
+func TestNear(t *testing.T) {
+	base := "one two three four five six seven eight nine ten eleven twelve"
+	docs := []store.Document{
+		{Path: "docs/a", Content: base},
+		{Path: "docs/b", Content: base + " thirteen"},
+		{Path: "docs/c", Content: base},
+		{Path: "docs/d", Content: "completely different words that share nothing with the rest"},
+	}
+	exact := []store.DuplicateGroup{{Paths: []string{"docs/a", "docs/c"}}}
+
+	groups := dedup.Near(docs, exact, 0.8)
+	assert.Equal(t, []dedup.NearGroup{{Paths: []string{"docs/a", "docs/b", "docs/c"}, Similarity: 0.89}}, groups)
+
+	assert.Empty(t, dedup.Near(docs, exact, 0.95))
+}
@@ -6,10 +6,12 @@ import (
 	"context"
 	"fmt"
 	"io"
+	"os"
 	"strconv"
 	"strings"
 
 	"github.com/sergi/go-diff/diffmatchpatch"
+	"golang.org/x/term"
 )
 
 // contextLines is the number of unchanged lines shown before/after changes.
@@ -22,8 +24,13 @@ type Options struct {
 	Version1       int    // First version to compare
 	Version2       int    // Second version to compare
 	IncludeDeleted bool   // Allow diffing deleted documents
-	FileContent    string // Filesystem file content (for -f flag)
+	File           string // Filesystem file path (for --file)
+	FileContent    string // Content of File
 	Colour         bool   // Colourize output
+
+	// Redact, if set, masks both sides before they are compared, so the
+	// diff cannot show what it hides (see redact.mcp).
+	Redact func(string) string
 }
 
 // Differ is the interface for diff operations.
@@ -47,6 +54,8 @@ type Result struct {
 	Old  string // old label
 	New  string // new label
 	Diff string // plain diff text
+
+	Changed bool // old and new content differ
 }
 
 // Compute returns a diff between old and new content.
@@ -56,9 +65,10 @@ func Compute(oldContent, newContent, oldLabel, newLabel string) Result {
 	d = dmp.DiffCleanupSemantic(d)
 
 	return Result{
-		Old:  oldLabel,
-		New:  newLabel,
-		Diff: format(d),
+		Old:     oldLabel,
+		New:     newLabel,
+		Diff:    format(d),
+		Changed: oldContent != newContent,
 	}
 }
 
@@ -100,6 +110,40 @@ func format(diffs []diffmatchpatch.Diff) string {
 	return b.String()
 }
 
+// ColourMode controls when diff output is colourised.
+type ColourMode string
+
+// Colour modes accepted by --color.
+const (
+	ColourAuto   ColourMode = "auto"   // colour when writing to a terminal
+	ColourAlways ColourMode = "always" // always colour, e.g. when piping to less -R
+	ColourNever  ColourMode = "never"  // plain text
+)
+
+// ParseColourMode validates a --color value. Empty means auto.
+func ParseColourMode(s string) (ColourMode, error) {
+	switch m := ColourMode(s); m {
+	case "":
+		return ColourAuto, nil
+	case ColourAuto, ColourAlways, ColourNever:
+		return m, nil
+	}
+	return "", fmt.Errorf("invalid colour mode %q (expected auto, always or never)", s)
+}
+
+// Enabled reports whether output written to w should be colourised. Auto
+// only colours when w is a terminal, so redirected output stays plain.
+func (m ColourMode) Enabled(w io.Writer) bool {
+	switch m {
+	case ColourAlways:
+		return true
+	case ColourNever:
+		return false
+	}
+	f, ok := w.(*os.File)
+	return ok && term.IsTerminal(int(f.Fd()))
+}
+
 // Colourise adds ANSI colours to diff output.
 func Colourise(d string) string {
 	const (
@@ -1,6 +1,7 @@
 package diff
 
 import (
+	"bytes"
 	"strings"
 	"testing"
 )
@@ -130,3 +131,36 @@ func TestParseVersionRange(t *testing.T) {
 		})
 	}
 }
+
+func TestParseColourMode(t *testing.T) {
+	for in, want := range map[string]ColourMode{
+		"":       ColourAuto,
+		"auto":   ColourAuto,
+		"always": ColourAlways,
+		"never":  ColourNever,
+	} {
+		got, err := ParseColourMode(in)
+		if err != nil {
+			t.Errorf("ParseColourMode(%q) error: %v", in, err)
+		}
+		if got != want {
+			t.Errorf("ParseColourMode(%q) = %q, want %q", in, got, want)
+		}
+	}
+	if _, err := ParseColourMode("sometimes"); err == nil {
+		t.Error("ParseColourMode(\"sometimes\") should fail")
+	}
+}
+
+func TestColourMode_Enabled(t *testing.T) {
+	var buf bytes.Buffer
+	if ColourAuto.Enabled(&buf) {
+		t.Error("auto should not colour a non-terminal writer")
+	}
+	if !ColourAlways.Enabled(&buf) {
+		t.Error("always should colour any writer")
+	}
+	if ColourNever.Enabled(&buf) {
+		t.Error("never should not colour")
+	}
+}
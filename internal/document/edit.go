@@ -13,7 +13,9 @@ package document
 import (
 	"context"
 	"fmt"
+	"strings"
 
+	"github.com/jpl-au/llmd/internal/diff"
 	"github.com/jpl-au/llmd/internal/edit"
 	"github.com/jpl-au/llmd/internal/store"
 )
@@ -21,6 +23,10 @@ import (
 // Edit performs a search/replace edit on a document.
 // path can be a document path or a key.
 func (s *Service) Edit(ctx context.Context, path string, opts edit.Options) error {
+	defer trace("edit", "write", path)()
+	if err := s.writable(); err != nil {
+		return err
+	}
 	doc, _, err := s.Resolve(ctx, path, false)
 	if err != nil {
 		return fmt.Errorf("edit %q: %w", path, err)
@@ -32,9 +38,9 @@ func (s *Service) Edit(ctx context.Context, path string, opts edit.Options) erro
 		return fmt.Errorf("edit %q: %w", path, err)
 	}
 
-	author := opts.Author
-	if author == "" {
-		author = DefaultAuthor
+	author, err := s.author(opts.Author)
+	if err != nil {
+		return fmt.Errorf("edit %q: %w", path, err)
 	}
 
 	writeOpts := store.WriteOptions{
@@ -54,23 +60,56 @@ func (s *Service) Edit(ctx context.Context, path string, opts edit.Options) erro
 	return nil
 }
 
-// EditLineRange replaces a range of lines in a document.
-// path can be a document path or a key.
-func (s *Service) EditLineRange(ctx context.Context, path, replacement string, opts edit.LineRangeOptions) error {
+// EditLineRange replaces a range of lines in a document, or inserts content
+// without replacing any when opts.Mode asks for it. opts.Section selects the
+// range by heading instead of line numbers. path can be a document path or a
+// key. With opts.DryRun the edit is computed and returned as a preview
+// without writing a new version.
+func (s *Service) EditLineRange(ctx context.Context, path, replacement string, opts edit.LineRangeOptions) (edit.Result, error) {
+	defer trace("edit-lines", "write", path)()
+	if err := s.writable(); err != nil {
+		return edit.Result{Path: path}, err
+	}
 	doc, _, err := s.Resolve(ctx, path, false)
 	if err != nil {
-		return fmt.Errorf("edit lines %q: %w", path, err)
+		return edit.Result{Path: path}, fmt.Errorf("edit lines %q: %w", path, err)
 	}
 	path = doc.Path // Use resolved path
+	result := edit.Result{Path: path}
+
+	// A section is resolved against the current content, so the edit lands
+	// on the right lines even if earlier lines have shifted.
+	if opts.Section != "" {
+		opts.Start, opts.End, err = edit.SectionRange(doc.Content, opts.Section)
+		if err != nil {
+			return result, fmt.Errorf("edit lines %q: %w", path, err)
+		}
+		opts.Mode = edit.ModeReplace
+	}
 
-	content, err := edit.ReplaceLines(doc.Content, opts.Start, opts.End, replacement)
+	content, err := edit.Apply(doc.Content, replacement, opts)
 	if err != nil {
-		return fmt.Errorf("edit lines %q: %w", path, err)
+		return result, fmt.Errorf("edit lines %q: %w", path, err)
+	}
+
+	if opts.DryRun {
+		// Appends, prepends and inserts replace nothing, so only a range has old text
+		if opts.Mode == edit.ModeReplace {
+			result.Old, err = edit.Lines(doc.Content, opts.Start, opts.End)
+			if err != nil {
+				return result, fmt.Errorf("edit lines %q: %w", path, err)
+			}
+		}
+		result.DryRun = true
+		result.New = strings.TrimSuffix(replacement, "\n")
+		result.Content = content
+		result.Diff = diff.Compute(doc.Content, content, path, path).Diff
+		return result, nil
 	}
 
-	author := opts.Author
-	if author == "" {
-		author = DefaultAuthor
+	author, err := s.author(opts.Author)
+	if err != nil {
+		return result, fmt.Errorf("edit lines %q: %w", path, err)
 	}
 
 	writeOpts := store.WriteOptions{
@@ -81,11 +120,11 @@ func (s *Service) EditLineRange(ctx context.Context, path, replacement string, o
 	}
 
 	if err := s.store.Write(ctx, path, content, writeOpts); err != nil {
-		return fmt.Errorf("edit lines %q: write: %w", path, err)
+		return result, fmt.Errorf("edit lines %q: write: %w", path, err)
 	}
 
 	if err := s.syncWrite(path, content); err != nil {
-		return fmt.Errorf("sync %q: %w", path, err)
+		return result, fmt.Errorf("sync %q: %w", path, err)
 	}
-	return nil
+	return result, nil
 }
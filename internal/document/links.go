@@ -19,6 +19,9 @@ import (
 
 // Link creates a bidirectional link between two documents, returns the link ID.
 func (s *Service) Link(ctx context.Context, from, to, tag string, opts store.LinkOptions) (string, error) {
+	if err := s.writable(); err != nil {
+		return "", err
+	}
 	opts.MaxPath = s.maxPath
 	id, err := s.store.Link(ctx, from, to, tag, opts)
 	if err != nil {
@@ -30,6 +33,9 @@ func (s *Service) Link(ctx context.Context, from, to, tag string, opts store.Lin
 
 // UnlinkByID removes a link by its unique ID (soft delete).
 func (s *Service) UnlinkByID(ctx context.Context, id string) error {
+	if err := s.writable(); err != nil {
+		return err
+	}
 	if err := s.store.UnlinkByID(ctx, id); err != nil {
 		return err
 	}
@@ -40,6 +46,9 @@ func (s *Service) UnlinkByID(ctx context.Context, id string) error {
 // UnlinkByTag removes all links with a specific tag (soft delete).
 // Fires LinkEvent for each deleted link to notify extensions.
 func (s *Service) UnlinkByTag(ctx context.Context, tag string, opts store.LinkOptions) (int64, error) {
+	if err := s.writable(); err != nil {
+		return 0, err
+	}
 	// Fetch links and delete concurrently - both operations are independent
 	var links []store.Link
 	var count int64
@@ -85,6 +94,11 @@ func (s *Service) ListLinksByTag(ctx context.Context, tag string, opts store.Lin
 	return s.store.ListLinksByTag(ctx, tag, opts)
 }
 
+// ListAllLinks returns every link in the store.
+func (s *Service) ListAllLinks(ctx context.Context, opts store.LinkOptions) ([]store.Link, error) {
+	return s.store.ListAllLinks(ctx, opts)
+}
+
 // ListOrphanLinkPaths returns document paths with no links.
 func (s *Service) ListOrphanLinkPaths(ctx context.Context, opts store.LinkOptions) ([]string, error) {
 	return s.store.ListOrphanLinkPaths(ctx, opts)
@@ -94,5 +108,8 @@ func (s *Service) ListOrphanLinkPaths(ctx context.Context, opts store.LinkOption
 // cleanup when documents are removed or reorganised to maintain referential
 // integrity in the link graph.
 func (s *Service) DeleteLinksForPath(ctx context.Context, path string, opts store.LinkOptions) error {
+	if err := s.writable(); err != nil {
+		return err
+	}
 	return s.store.DeleteLinksForPath(ctx, path, opts)
 }
@@ -15,10 +15,14 @@ import (
 	"time"
 
 	"github.com/jpl-au/llmd/internal/path"
+	"github.com/jpl-au/llmd/internal/store"
 )
 
 // Vacuum permanently removes soft-deleted documents.
 func (s *Service) Vacuum(ctx context.Context, olderThan *time.Duration, prefix string) (int64, error) {
+	if err := s.writable(); err != nil {
+		return 0, err
+	}
 	if prefix != "" {
 		var err error
 		prefix, err = path.Normalise(prefix)
@@ -33,5 +37,45 @@ func (s *Service) Vacuum(ctx context.Context, olderThan *time.Duration, prefix s
 // -shm files from the filesystem, useful before backup operations or when
 // preparing the database for distribution.
 func (s *Service) Checkpoint(ctx context.Context) error {
+	if err := s.writable(); err != nil {
+		return err
+	}
 	return s.store.Checkpoint(ctx)
 }
+
+// Verify checks database integrity and link consistency. It only reads, so
+// it works on read-only stores too.
+func (s *Service) Verify(ctx context.Context) ([]store.Issue, error) {
+	return s.store.Verify(ctx)
+}
+
+// SchemaVersion returns the highest schema migration applied to the store.
+func (s *Service) SchemaVersion(ctx context.Context) (int, error) {
+	return s.store.SchemaVersion(ctx)
+}
+
+// PendingMigrations returns the schema migrations not yet applied.
+func (s *Service) PendingMigrations(ctx context.Context) ([]store.Migration, error) {
+	return s.store.PendingMigrations(ctx)
+}
+
+// MigrateTo applies pending schema migrations up to version.
+func (s *Service) MigrateTo(ctx context.Context, version int) ([]store.Migration, error) {
+	if err := s.writable(); err != nil {
+		return nil, err
+	}
+	return s.store.MigrateTo(ctx, version)
+}
+
+// Reindex rebuilds the full-text search index from stored documents.
+func (s *Service) Reindex(ctx context.Context) (int64, error) {
+	if err := s.writable(); err != nil {
+		return 0, err
+	}
+	return s.store.Reindex(ctx)
+}
+
+// Encrypted reports whether document content is encrypted at rest.
+func (s *Service) Encrypted() bool {
+	return s.store.Encrypted()
+}
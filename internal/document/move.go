@@ -12,6 +12,7 @@ package document
 
 import (
 	"context"
+	"errors"
 	"fmt"
 
 	"github.com/jpl-au/llmd/extension"
@@ -20,6 +21,10 @@ import (
 
 // Move renames a document.
 func (s *Service) Move(ctx context.Context, src, dst string) error {
+	defer trace("move", "write", src)()
+	if err := s.writable(); err != nil {
+		return err
+	}
 	opts := store.MoveOptions{
 		MaxPath: s.maxPath,
 	}
@@ -49,9 +54,51 @@ func (s *Service) Move(ctx context.Context, src, dst string) error {
 	return nil
 }
 
+// MoveMany renames several documents atomically. The database changes
+// commit together or not at all; filesystem sync and events follow for each
+// op in order once they have committed.
+func (s *Service) MoveMany(ctx context.Context, ops []store.MoveOp) error {
+	defer trace("move-many", "write", "")()
+	if err := s.writable(); err != nil {
+		return err
+	}
+	opts := store.MoveOptions{
+		MaxPath: s.maxPath,
+	}
+
+	if err := s.store.MoveMany(ctx, ops, opts); err != nil {
+		return err
+	}
+
+	for _, op := range ops {
+		if err := s.syncMove(op.From, op.To); err != nil {
+			return fmt.Errorf("move %q to %q: database updated but filesystem sync failed: %w", op.From, op.To, err)
+		}
+		doc, err := s.store.Latest(ctx, op.To, false)
+		if err != nil {
+			// A later op may have moved this document on again
+			if errors.Is(err, store.ErrNotFound) {
+				continue
+			}
+			return fmt.Errorf("move %q to %q: fetch for event: %w", op.From, op.To, err)
+		}
+		s.fireEvent(extension.DocumentWriteEvent{
+			Path:    op.To,
+			Version: doc.Version,
+			Author:  doc.Author,
+			Message: fmt.Sprintf("moved from %s", op.From),
+			Content: doc.Content,
+		})
+	}
+	return nil
+}
+
 // Copy duplicates a document to a new path. The copier parameter tracks
 // who performed the copy operation for audit purposes.
 func (s *Service) Copy(ctx context.Context, from, to, copier string) error {
+	if err := s.writable(); err != nil {
+		return err
+	}
 	opts := store.CopyOptions{
 		MaxPath: s.maxPath,
 	}
@@ -83,3 +130,40 @@ func (s *Service) Copy(ctx context.Context, from, to, copier string) error {
 	})
 	return nil
 }
+
+// CopyPrefix duplicates every document under fromPrefix to toPrefix in one
+// transaction. Filesystem sync and events follow only once all are copied.
+func (s *Service) CopyPrefix(ctx context.Context, fromPrefix, toPrefix, copier string) (int, error) {
+	defer trace("copy-prefix", "write", fromPrefix)()
+	if err := s.writable(); err != nil {
+		return 0, err
+	}
+	opts := store.CopyOptions{
+		MaxPath: s.maxPath,
+	}
+
+	copied, err := s.store.CopyPrefix(ctx, fromPrefix, toPrefix, copier, opts)
+	if err != nil {
+		return 0, fmt.Errorf("copy %q to %q: %w", fromPrefix, toPrefix, err)
+	}
+
+	for _, to := range copied {
+		doc, err := s.store.Latest(ctx, to, false)
+		if err != nil {
+			return len(copied), fmt.Errorf("copy to %q: fetch: %w", to, err)
+		}
+		if s.syncFiles {
+			if err := s.syncWrite(to, doc.Content); err != nil {
+				return len(copied), fmt.Errorf("sync %q: %w", to, err)
+			}
+		}
+		s.fireEvent(extension.DocumentWriteEvent{
+			Path:    to,
+			Version: doc.Version,
+			Author:  copier,
+			Message: doc.Message,
+			Content: doc.Content,
+		})
+	}
+	return len(copied), nil
+}
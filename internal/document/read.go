@@ -23,6 +23,7 @@ import (
 
 // Latest retrieves the latest version of a document.
 func (s *Service) Latest(ctx context.Context, path string, includeDeleted bool) (*store.Document, error) {
+	defer trace("latest", "read", path)()
 	path, err := s.normalizePath(path)
 	if err != nil {
 		return nil, err
@@ -32,6 +33,7 @@ func (s *Service) Latest(ctx context.Context, path string, includeDeleted bool)
 
 // Version retrieves a specific version of a document.
 func (s *Service) Version(ctx context.Context, path string, ver int) (*store.Document, error) {
+	defer trace("version", "read", path)()
 	path, err := s.normalizePath(path)
 	if err != nil {
 		return nil, err
@@ -39,8 +41,18 @@ func (s *Service) Version(ctx context.Context, path string, ver int) (*store.Doc
 	return s.store.Version(ctx, path, ver)
 }
 
+// VersionAsOf retrieves the version of a document that was current at t.
+func (s *Service) VersionAsOf(ctx context.Context, path string, t time.Time) (*store.Document, error) {
+	path, err := s.normalizePath(path)
+	if err != nil {
+		return nil, err
+	}
+	return s.store.VersionAsOf(ctx, path, t)
+}
+
 // ByKey retrieves a document by its unique 8-char key.
 func (s *Service) ByKey(ctx context.Context, key string) (*store.Document, error) {
+	defer trace("by-key", "read", key)()
 	return s.store.ByKey(ctx, key)
 }
 
@@ -63,6 +75,7 @@ func (s *Service) ByKey(ctx context.Context, key string) (*store.Document, error
 //
 // Returns (doc, isKey, err) where isKey indicates whether input resolved as a key.
 func (s *Service) Resolve(ctx context.Context, value string, includeDeleted bool) (*store.Document, bool, error) {
+	defer trace("resolve", "read", value)()
 	// Keys are always exactly 8 characters. Longer or shorter inputs can only
 	// be paths.
 	if len(value) != 8 {
@@ -97,6 +110,7 @@ func (s *Service) Resolve(ctx context.Context, value string, includeDeleted bool
 
 // List returns documents matching a prefix.
 func (s *Service) List(ctx context.Context, prefix string, includeDeleted, deletedOnly bool) ([]store.Document, error) {
+	defer trace("list", "read", prefix)()
 	prefix, err := s.normalizePrefix(prefix)
 	if err != nil {
 		return nil, err
@@ -104,8 +118,29 @@ func (s *Service) List(ctx context.Context, prefix string, includeDeleted, delet
 	return s.store.List(ctx, prefix, includeDeleted, deletedOnly)
 }
 
+// ListContaining returns documents matching a prefix whose content contains text.
+func (s *Service) ListContaining(ctx context.Context, prefix, text string, ignoreCase, includeDeleted, deletedOnly bool) ([]store.Document, error) {
+	defer trace("list-containing", "read", prefix)()
+	prefix, err := s.normalizePrefix(prefix)
+	if err != nil {
+		return nil, err
+	}
+	return s.store.ListContaining(ctx, prefix, text, ignoreCase, includeDeleted, deletedOnly)
+}
+
+// ListAsOf returns documents matching a prefix as they were at t.
+func (s *Service) ListAsOf(ctx context.Context, prefix string, t time.Time) ([]store.Document, error) {
+	defer trace("list-as-of", "read", prefix)()
+	prefix, err := s.normalizePrefix(prefix)
+	if err != nil {
+		return nil, err
+	}
+	return s.store.ListAsOf(ctx, prefix, t)
+}
+
 // History returns version history for a document.
 func (s *Service) History(ctx context.Context, path string, limit int, includeDeleted bool) ([]store.Document, error) {
+	defer trace("history", "read", path)()
 	path, err := s.normalizePath(path)
 	if err != nil {
 		return nil, err
@@ -142,6 +177,7 @@ func (s *Service) Meta(ctx context.Context, path string) (*store.DocumentMeta, e
 
 // Glob returns document paths matching a glob pattern.
 func (s *Service) Glob(ctx context.Context, pattern string) ([]string, error) {
+	defer trace("glob", "read", pattern)()
 	all, err := s.store.ListPaths(ctx, "")
 	if err != nil {
 		return nil, err
@@ -171,7 +207,7 @@ func (s *Service) Diff(ctx context.Context, path string, opts diff.Options) (dif
 	var err error
 
 	switch {
-	case opts.FileContent != "":
+	case opts.File != "":
 		o, n, ol, nl, err = s.diffWithFile(ctx, path, opts)
 	case opts.Path2 != "":
 		o, n, ol, nl, err = s.diffTwoPaths(ctx, path, opts)
@@ -184,17 +220,27 @@ func (s *Service) Diff(ctx context.Context, path string, opts diff.Options) (dif
 	if err != nil {
 		return diff.Result{}, err
 	}
+	if opts.Redact != nil {
+		o, n = opts.Redact(o), opts.Redact(n)
+	}
 	return diff.Compute(o, n, ol, nl), nil
 }
 
+// diffWithFile compares a stored document with a filesystem file. The
+// stored version is the old side so the diff reads as "what importing the
+// file would change". Version1, when set, selects the stored version.
 func (s *Service) diffWithFile(ctx context.Context, path string, opts diff.Options) (o, n, ol, nl string, err error) {
-	// Resolve Path2 (the document path, supports keys and paths)
-	// Note: path is a filesystem path here, used only for labels
-	doc, _, err := s.Resolve(ctx, opts.Path2, opts.IncludeDeleted)
+	doc, _, err := s.Resolve(ctx, path, opts.IncludeDeleted)
 	if err != nil {
-		return "", "", "", "", fmt.Errorf("reading %s: %w", opts.Path2, err)
+		return "", "", "", "", fmt.Errorf("reading %s: %w", path, err)
 	}
-	return opts.FileContent, doc.Content, path, doc.Path + " (v" + strconv.Itoa(doc.Version) + ")", nil
+	if opts.Version1 > 0 && opts.Version1 != doc.Version {
+		doc, err = s.store.Version(ctx, doc.Path, opts.Version1)
+		if err != nil {
+			return "", "", "", "", fmt.Errorf("reading %s v%d: %w", path, opts.Version1, err)
+		}
+	}
+	return doc.Content, opts.FileContent, doc.Path + " (v" + strconv.Itoa(doc.Version) + ")", opts.File, nil
 }
 
 func (s *Service) diffTwoPaths(ctx context.Context, path string, opts diff.Options) (o, n, ol, nl string, err error) {
@@ -298,6 +344,7 @@ func (s *Service) ListDeletedPaths(ctx context.Context, prefix string) ([]string
 // efficient batch queries for listings that need size/version info without
 // loading full document content.
 func (s *Service) ListMeta(ctx context.Context, prefix string, includeDeleted bool) ([]store.DocumentMeta, error) {
+	defer trace("list-meta", "read", prefix)()
 	prefix, err := s.normalizePrefix(prefix)
 	if err != nil {
 		return nil, err
@@ -305,6 +352,26 @@ func (s *Service) ListMeta(ctx context.Context, prefix string, includeDeleted bo
 	return s.store.ListMeta(ctx, prefix, includeDeleted)
 }
 
+// Recent returns metadata for the active documents changed after since,
+// most recently changed first.
+func (s *Service) Recent(ctx context.Context, prefix string, since time.Time, limit int) ([]store.DocumentMeta, error) {
+	defer trace("recent", "read", prefix)()
+	prefix, err := s.normalizePrefix(prefix)
+	if err != nil {
+		return nil, err
+	}
+	return s.store.Recent(ctx, prefix, since, limit)
+}
+
+// AuditLog returns every version written in [since, until) under prefix.
+func (s *Service) AuditLog(ctx context.Context, since, until time.Time, prefix string) ([]store.AuditEntry, error) {
+	prefix, err := s.normalizePrefix(prefix)
+	if err != nil {
+		return nil, err
+	}
+	return s.store.AuditLog(ctx, since, until, prefix)
+}
+
 // CountDeleted returns the count of soft-deleted documents. Enables vacuum
 // preview and trash management without loading document data.
 func (s *Service) CountDeleted(ctx context.Context, prefix string) (int64, error) {
@@ -346,3 +413,14 @@ func (s *Service) ListAuthors(ctx context.Context) ([]string, error) {
 func (s *Service) Stats(ctx context.Context) (*store.Stats, error) {
 	return s.store.Stats(ctx)
 }
+
+// Duplicates groups documents under prefix whose latest versions have
+// identical content, for consolidating redundant documentation.
+func (s *Service) Duplicates(ctx context.Context, prefix string) ([]store.DuplicateGroup, error) {
+	defer trace("duplicates", "read", prefix)()
+	prefix, err := s.normalizePrefix(prefix)
+	if err != nil {
+		return nil, err
+	}
+	return s.store.Duplicates(ctx, prefix)
+}
@@ -18,6 +18,7 @@ import (
 
 // Search performs full-text search.
 func (s *Service) Search(ctx context.Context, query, prefix string, includeDeleted, deletedOnly bool) ([]store.Document, error) {
+	defer trace("search", "read", prefix)()
 	if prefix != "" {
 		var err error
 		prefix, err = path.Normalise(prefix)
@@ -27,3 +28,30 @@ func (s *Service) Search(ctx context.Context, query, prefix string, includeDelet
 	}
 	return s.store.Search(ctx, query, prefix, includeDeleted, deletedOnly)
 }
+
+// SearchRanked searches active documents, most relevant first.
+func (s *Service) SearchRanked(ctx context.Context, query, prefix string) ([]store.Document, error) {
+	defer trace("search-ranked", "read", prefix)()
+	if prefix != "" {
+		var err error
+		prefix, err = path.Normalise(prefix)
+		if err != nil {
+			return nil, err
+		}
+	}
+	return s.store.SearchRanked(ctx, query, prefix)
+}
+
+// SearchPage returns one page of relevance-ordered search results and the
+// total number of matches.
+func (s *Service) SearchPage(ctx context.Context, query, prefix string, includeDeleted, deletedOnly bool, limit, offset int) ([]store.Document, int, error) {
+	defer trace("search-page", "read", prefix)()
+	if prefix != "" {
+		var err error
+		prefix, err = path.Normalise(prefix)
+		if err != nil {
+			return nil, 0, err
+		}
+	}
+	return s.store.SearchPage(ctx, query, prefix, includeDeleted, deletedOnly, limit, offset)
+}
@@ -7,8 +7,11 @@ package document
 import (
 	"context"
 	"database/sql"
+	"errors"
 	"fmt"
+	"os"
 	"path/filepath"
+	"strings"
 
 	"github.com/jpl-au/llmd/extension"
 	"github.com/jpl-au/llmd/internal/config"
@@ -18,8 +21,14 @@ import (
 	"github.com/jpl-au/llmd/internal/store"
 )
 
+// DefaultAuthor attributes writes made without an author, unless the
+// service requires one.
 const DefaultAuthor = "unknown"
 
+// ErrInvalidAuthor is returned by writes whose author is blank, or missing
+// when the service requires an author (config author.required).
+var ErrInvalidAuthor = errors.New("invalid author")
+
 // Service provides higher-level document operations backed by a Store.
 type Service struct {
 	store         *store.SQLiteStore
@@ -29,37 +38,99 @@ type Service struct {
 	maxPath       int
 	maxContent    int64
 	maxLineLength int
+	readOnly      bool
+	requireAuthor bool
 	extCtx        extension.Context // for firing events to extensions
 }
 
+// Options configures how New opens the store.
+type Options struct {
+	// ReadOnly opens the database read-only and makes every mutating method
+	// fail with store.ErrReadOnly before it reaches the database.
+	ReadOnly bool
+
+	// NoMigrate leaves the schema at its current version instead of
+	// migrating it to the latest on open, for MigrateTo to step through.
+	NoMigrate bool
+
+	// Passphrase unlocks an encrypted store. If empty, PassphraseEnv is
+	// used.
+	Passphrase string
+}
+
+// PassphraseEnv names the environment variable holding the passphrase of
+// an encrypted store.
+const PassphraseEnv = "LLMD_PASSPHRASE"
+
 // New creates a new Service, discovering the DB by walking up the directory tree.
 // The db parameter specifies which database to use (empty for default).
 // Returns ErrNotInitialised if no matching database is found.
 func New(db string) (*Service, error) {
+	return NewWithOptions(db, Options{})
+}
+
+// NewWithOptions is New with explicit options.
+func NewWithOptions(db string, opts Options) (*Service, error) {
 	dbPath, err := repo.Discover(db)
 	if err != nil {
 		return nil, err
 	}
 
-	s, err := store.Open(dbPath)
+	cfg, err := config.Load()
+	if err != nil {
+		return nil, err // config.Load provides detailed, actionable error messages
+	}
+
+	passphrase := opts.Passphrase
+	if passphrase == "" {
+		passphrase = os.Getenv(PassphraseEnv)
+	}
+	s, err := store.OpenWithOptions(dbPath, store.OpenOptions{
+		BusyTimeout: cfg.BusyTimeout(),
+		ReadOnly:    opts.ReadOnly,
+		NoMigrate:   opts.NoMigrate,
+		Passphrase:  passphrase,
+		Tokenizer:   cfg.SearchTokenizer(),
+	})
 	if err != nil {
 		return nil, err
 	}
 
 	filesDir := filepath.Dir(dbPath)
+
+	return &Service{
+		store:         s,
+		dbPath:        dbPath,
+		filesDir:      filesDir,
+		syncFiles:     cfg.SyncFiles() && !s.Encrypted(), // The mirror is plaintext
+		maxPath:       cfg.MaxPath(),
+		maxContent:    cfg.MaxContent(),
+		maxLineLength: cfg.MaxLineLength(),
+		readOnly:      opts.ReadOnly,
+		requireAuthor: cfg.AuthorRequired(),
+	}, nil
+}
+
+// NewMemory creates a Service over an in-memory store. Nothing is written to
+// disk: there is no filesystem mirror and the documents vanish on Close.
+// Limits still come from config so behaviour matches a persistent store.
+func NewMemory() (*Service, error) {
 	cfg, err := config.Load()
 	if err != nil {
-		return nil, err // config.Load provides detailed, actionable error messages
+		return nil, err
+	}
+
+	s, err := store.OpenMemory()
+	if err != nil {
+		return nil, err
 	}
 
 	return &Service{
 		store:         s,
-		dbPath:        dbPath,
-		filesDir:      filesDir,
-		syncFiles:     cfg.SyncFiles(),
 		maxPath:       cfg.MaxPath(),
 		maxContent:    cfg.MaxContent(),
 		maxLineLength: cfg.MaxLineLength(),
+		requireAuthor: cfg.AuthorRequired(),
 	}, nil
 }
 
@@ -73,8 +144,18 @@ func Init(force bool, db string, local bool, dir string) error {
 	return repo.Init(force, db, local, dir)
 }
 
+// InitEncrypted is Init for a store whose document content is encrypted
+// with a key derived from passphrase. Open it again with the same
+// passphrase, in Options or PassphraseEnv.
+func InitEncrypted(force bool, db string, local bool, dir, passphrase string) error {
+	return repo.InitEncrypted(force, db, local, dir, passphrase)
+}
+
 // Close checkpoints the WAL and closes the database connection.
 func (s *Service) Close() error {
+	if s.readOnly {
+		return s.store.Close()
+	}
 	if err := s.store.Checkpoint(context.Background()); err != nil {
 		log.Event("service:close", "checkpoint").
 			Detail("error", err.Error()).
@@ -83,6 +164,16 @@ func (s *Service) Close() error {
 	return s.store.Close()
 }
 
+// trace starts timing a service operation. Deferring the returned func
+// prints the elapsed time to stderr with --verbose; otherwise it does
+// nothing. kind is "read" or "write".
+//
+//	defer trace("list", "read", prefix)()
+func trace(op, kind, target string) func() {
+	b := log.Debug("service:"+op, kind).Path(target)
+	return func() { b.Write(nil) }
+}
+
 // ReloadConfig reloads configuration from disk and updates cached values.
 // Call this after modifying config to ensure the service uses new settings.
 func (s *Service) ReloadConfig() error {
@@ -90,13 +181,53 @@ func (s *Service) ReloadConfig() error {
 	if err != nil {
 		return err
 	}
-	s.syncFiles = cfg.SyncFiles()
+	// In-memory stores have no directory to mirror into, and encrypted
+	// stores must not write their content out in plaintext
+	s.syncFiles = cfg.SyncFiles() && s.dbPath != "" && !s.store.Encrypted()
 	s.maxPath = cfg.MaxPath()
 	s.maxContent = cfg.MaxContent()
 	s.maxLineLength = cfg.MaxLineLength()
+	s.requireAuthor = cfg.AuthorRequired()
+	return nil
+}
+
+// ReadOnly reports whether the service was opened read-only.
+func (s *Service) ReadOnly() bool {
+	return s.readOnly
+}
+
+// writable returns store.ErrReadOnly for a read-only service. Every mutating
+// method calls it first so a rejected change never reaches the database.
+func (s *Service) writable() error {
+	if s.readOnly {
+		return store.ErrReadOnly
+	}
 	return nil
 }
 
+// SetRequireAuthor makes writes without an author fail with
+// ErrInvalidAuthor instead of being attributed to DefaultAuthor. It
+// overrides config author.required until the config is reloaded.
+func (s *Service) SetRequireAuthor(on bool) {
+	s.requireAuthor = on
+}
+
+// author returns the attribution for a write by a. An empty author falls
+// back to DefaultAuthor unless one is required; a blank one is always an
+// error, since it would record a version nobody can be traced to.
+func (s *Service) author(a string) (string, error) {
+	if a == "" {
+		if s.requireAuthor {
+			return "", fmt.Errorf("%w: author required", ErrInvalidAuthor)
+		}
+		return DefaultAuthor, nil
+	}
+	if strings.TrimSpace(a) == "" {
+		return "", fmt.Errorf("%w: author is blank", ErrInvalidAuthor)
+	}
+	return a, nil
+}
+
 // SetExtensionContext sets the extension context for firing events.
 // Called from cmd/root.go after creating the context.
 func (s *Service) SetExtensionContext(ctx extension.Context) {
@@ -164,6 +295,11 @@ func (s *Service) FilesDir() string {
 	return s.filesDir
 }
 
+// Mirrored reports whether documents are mirrored to the files directory.
+func (s *Service) Mirrored() bool {
+	return s.syncFiles
+}
+
 // Tx runs a function within a database transaction.
 //
 // The defer Rollback pattern: We always defer Rollback(), then call Commit()
@@ -178,6 +314,9 @@ func (s *Service) FilesDir() string {
 // by the Service API. Raw transactions let them do multi-step atomic operations
 // while still benefiting from the service's connection management.
 func (s *Service) Tx(ctx context.Context, fn func(tx *sql.Tx) error) error {
+	if err := s.writable(); err != nil {
+		return err
+	}
 	tx, err := s.store.DB().BeginTx(ctx, nil)
 	if err != nil {
 		return fmt.Errorf("begin transaction: %w", err)
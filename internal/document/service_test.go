@@ -2,10 +2,12 @@ package document_test
 
 import (
 	"context"
+	"database/sql"
 	"os"
 	"testing"
 
 	"github.com/jpl-au/llmd/internal/document"
+	"github.com/jpl-au/llmd/internal/edit"
 	"github.com/jpl-au/llmd/internal/service"
 	"github.com/jpl-au/llmd/internal/store"
 	"github.com/stretchr/testify/assert"
@@ -147,3 +149,84 @@ func TestService_Tags(t *testing.T) {
 	tags, _ = svc.ListTags(ctx, path, opts)
 	assert.Equal(t, []string{"important"}, tags)
 }
+
+func TestService_ReadOnly(t *testing.T) {
+	svc, cleanup := setupService(t)
+	defer cleanup()
+	ctx := context.Background()
+
+	require.NoError(t, svc.Write(ctx, "docs/readme", "hello", "tester", ""))
+
+	ro, err := document.NewWithOptions("", document.Options{ReadOnly: true})
+	require.NoError(t, err)
+	defer ro.Close()
+	assert.True(t, ro.ReadOnly())
+
+	doc, err := ro.Latest(ctx, "docs/readme", false)
+	require.NoError(t, err)
+	assert.Equal(t, "hello", doc.Content)
+	_, err = ro.Search(ctx, "hello", "", false, false)
+	require.NoError(t, err)
+
+	tests := []struct {
+		name string
+		fn   func() error
+	}{
+		{"write", func() error { return ro.Write(ctx, "docs/new", "x", "tester", "") }},
+		{"delete", func() error { return ro.Delete(ctx, "docs/readme") }},
+		{"move", func() error { return ro.Move(ctx, "docs/readme", "docs/moved") }},
+		{"tag", func() error { return ro.Tag(ctx, "docs/readme", "t", store.NewTagOptions()) }},
+		{"link", func() error {
+			_, err := ro.Link(ctx, "docs/readme", "docs/other", "", store.NewLinkOptions())
+			return err
+		}},
+		{"tx", func() error { return ro.Tx(ctx, func(*sql.Tx) error { return nil }) }},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			assert.ErrorIs(t, tt.fn(), store.ErrReadOnly)
+		})
+	}
+
+	// Writes that bypass the service are refused by SQLite itself
+	_, err = ro.DB().ExecContext(ctx, "DELETE FROM documents")
+	assert.ErrorContains(t, err, "readonly")
+
+	doc, err = svc.Latest(ctx, "docs/readme", false)
+	require.NoError(t, err)
+	assert.Equal(t, "hello", doc.Content, "store is unchanged")
+}
+
+func TestService_Author(t *testing.T) {
+	svc, err := document.NewMemory()
+	require.NoError(t, err)
+	defer svc.Close()
+	ctx := context.Background()
+
+	// Without -a the write is attributed to the default author
+	require.NoError(t, svc.Write(ctx, "docs/a", "one", "", ""))
+	doc, err := svc.Latest(ctx, "docs/a", false)
+	require.NoError(t, err)
+	assert.Equal(t, document.DefaultAuthor, doc.Author)
+
+	// A blank author is never accepted
+	err = svc.Write(ctx, "docs/a", "two", "  \t", "")
+	assert.ErrorIs(t, err, document.ErrInvalidAuthor)
+	err = svc.Edit(ctx, "docs/a", edit.Options{Old: "one", New: "two", Author: " "})
+	assert.ErrorIs(t, err, document.ErrInvalidAuthor)
+	_, err = svc.EditLineRange(ctx, "docs/a", "two", edit.LineRangeOptions{Start: 1, End: 1, Author: " "})
+	assert.ErrorIs(t, err, document.ErrInvalidAuthor)
+
+	// Once required, a missing author is rejected too
+	svc.SetRequireAuthor(true)
+	err = svc.Write(ctx, "docs/a", "two", "", "")
+	assert.ErrorIs(t, err, document.ErrInvalidAuthor)
+	err = svc.Edit(ctx, "docs/a", edit.Options{Old: "one", New: "two"})
+	assert.ErrorIs(t, err, document.ErrInvalidAuthor)
+	require.NoError(t, svc.Write(ctx, "docs/a", "two", "alice", ""))
+
+	doc, err = svc.Latest(ctx, "docs/a", false)
+	require.NoError(t, err)
+	assert.Equal(t, 2, doc.Version)
+	assert.Equal(t, "alice", doc.Author)
+}
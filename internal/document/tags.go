@@ -23,6 +23,9 @@ import (
 // orphaned tags. Tags are metadata that persist across document versions.
 // path can be a document path or a key.
 func (s *Service) Tag(ctx context.Context, path, tag string, opts store.TagOptions) error {
+	if err := s.writable(); err != nil {
+		return err
+	}
 	opts.MaxPath = s.maxPath
 	doc, _, err := s.Resolve(ctx, path, true)
 	if err != nil {
@@ -40,6 +43,9 @@ func (s *Service) Tag(ctx context.Context, path, tag string, opts store.TagOptio
 // until vacuum permanently removes it.
 // path can be a document path or a key.
 func (s *Service) Untag(ctx context.Context, path, tag string, opts store.TagOptions) error {
+	if err := s.writable(); err != nil {
+		return err
+	}
 	opts.MaxPath = s.maxPath
 	doc, _, err := s.Resolve(ctx, path, true)
 	if err != nil {
@@ -75,6 +81,38 @@ func (s *Service) PathsWithTag(ctx context.Context, tag string, opts store.TagOp
 	return s.store.PathsWithTag(ctx, tag, opts)
 }
 
+// TagCounts returns each tag with the number of documents that have it.
+func (s *Service) TagCounts(ctx context.Context, opts store.TagOptions) (map[string]int, error) {
+	return s.store.TagCounts(ctx, opts)
+}
+
+// TagSets returns the complete tag list of every document having tag.
+func (s *Service) TagSets(ctx context.Context, tag string, opts store.TagOptions) (map[string][]string, error) {
+	return s.store.TagSets(ctx, tag, opts)
+}
+
+// RenameTag renames oldTag to newTag across all documents, merging into
+// newTag where a document already has it. Fires a removal and an addition
+// event for each document affected.
+func (s *Service) RenameTag(ctx context.Context, oldTag, newTag string, opts store.TagOptions) (int64, error) {
+	if err := s.writable(); err != nil {
+		return 0, err
+	}
+	paths, err := s.store.PathsWithTag(ctx, oldTag, opts)
+	if err != nil {
+		return 0, err
+	}
+	count, err := s.store.RenameTag(ctx, oldTag, newTag, opts)
+	if err != nil {
+		return 0, err
+	}
+	for _, p := range paths {
+		s.fireEvent(extension.TagEvent{Path: p, Tag: oldTag, Source: opts.Source, Added: false})
+		s.fireEvent(extension.TagEvent{Path: p, Tag: newTag, Source: opts.Source, Added: true})
+	}
+	return count, nil
+}
+
 // ListByTag returns documents matching both a path prefix and a tag. Combines
 // hierarchical path filtering with label-based filtering for targeted queries.
 func (s *Service) ListByTag(ctx context.Context, prefix, tag string, includeDeleted, deletedOnly bool, opts store.TagOptions) ([]store.Document, error) {
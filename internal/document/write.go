@@ -21,16 +21,29 @@ import (
 	"github.com/jpl-au/llmd/internal/sync"
 )
 
-// Write creates or updates a document.
+// Write creates or updates a document. The content type carries over from
+// the previous version, or is text/markdown for a new document.
 func (s *Service) Write(ctx context.Context, path, content, author, message string) error {
-	opts := store.WriteOptions{
-		Author:     author,
-		Message:    message,
-		MaxPath:    s.maxPath,
-		MaxContent: s.maxContent,
+	return s.WriteType(ctx, path, content, "", author, message)
+}
+
+// WriteType creates or updates a document, recording contentType as its
+// MIME type. An empty contentType behaves like Write.
+func (s *Service) WriteType(ctx context.Context, path, content, contentType, author, message string) error {
+	defer trace("write", "write", path)()
+	if err := s.writable(); err != nil {
+		return err
 	}
-	if opts.Author == "" {
-		opts.Author = DefaultAuthor
+	by, err := s.author(author)
+	if err != nil {
+		return fmt.Errorf("write %q: %w", path, err)
+	}
+	opts := store.WriteOptions{
+		Author:      by,
+		Message:     message,
+		MaxPath:     s.maxPath,
+		MaxContent:  s.maxContent,
+		ContentType: contentType,
 	}
 
 	if err := s.store.Write(ctx, path, content, opts); err != nil {
@@ -62,6 +75,10 @@ func (s *Service) Write(ctx context.Context, path, content, author, message stri
 
 // Delete soft-deletes a document.
 func (s *Service) Delete(ctx context.Context, path string) error {
+	defer trace("delete", "write", path)()
+	if err := s.writable(); err != nil {
+		return err
+	}
 	opts := store.DeleteOptions{
 		MaxPath: s.maxPath,
 	}
@@ -79,10 +96,37 @@ func (s *Service) Delete(ctx context.Context, path string) error {
 	return nil
 }
 
+// DeleteMany soft-deletes several documents in one transaction. Filesystem
+// sync and events follow only once all of them are deleted.
+func (s *Service) DeleteMany(ctx context.Context, paths []string) error {
+	defer trace("delete-many", "write", "")()
+	if err := s.writable(); err != nil {
+		return err
+	}
+	opts := store.DeleteOptions{
+		MaxPath: s.maxPath,
+	}
+
+	if err := s.store.DeleteMany(ctx, paths, opts); err != nil {
+		return err
+	}
+
+	for _, p := range paths {
+		if err := s.syncRemove(p); err != nil {
+			return fmt.Errorf("sync remove %q: %w", p, err)
+		}
+		s.fireEvent(extension.DocumentDeleteEvent{Path: p})
+	}
+	return nil
+}
+
 // DeleteVersion soft-deletes a specific version of a document.
 // Other versions remain accessible. If the deleted version was the latest,
 // the filesystem is updated to reflect the new latest version.
 func (s *Service) DeleteVersion(ctx context.Context, path string, version int) error {
+	if err := s.writable(); err != nil {
+		return err
+	}
 	opts := store.DeleteVersionOptions{
 		MaxPath: s.maxPath,
 	}
@@ -114,6 +158,9 @@ func (s *Service) DeleteVersion(ctx context.Context, path string, version int) e
 
 // Restore restores a soft-deleted document.
 func (s *Service) Restore(ctx context.Context, path string) error {
+	if err := s.writable(); err != nil {
+		return err
+	}
 	opts := store.RestoreOptions{
 		MaxPath: s.maxPath,
 	}
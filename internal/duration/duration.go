@@ -12,13 +12,14 @@ import (
 	"time"
 )
 
-// Parse parses duration strings in the format: Nd (days), Nw (weeks), Nm (months).
-// Examples: "7d" = 7 days, "4w" = 4 weeks, "3m" = 3 months (30 days).
+// Parse parses duration strings in the format: Nh (hours), Nd (days),
+// Nw (weeks), Nm (months).
+// Examples: "24h" = 24 hours, "7d" = 7 days, "4w" = 4 weeks, "3m" = 3 months (30 days).
 func Parse(s string) (time.Duration, error) {
-	re := regexp.MustCompile(`^(\d+)([dwm])$`)
+	re := regexp.MustCompile(`^(\d+)([hdwm])$`)
 	matches := re.FindStringSubmatch(s)
 	if matches == nil {
-		return 0, fmt.Errorf("invalid duration format: %s (use 7d, 4w, or 3m)", s)
+		return 0, fmt.Errorf("invalid duration format: %s (use 24h, 7d, 4w, or 3m)", s)
 	}
 
 	num, err := strconv.Atoi(matches[1])
@@ -28,6 +29,8 @@ func Parse(s string) (time.Duration, error) {
 	}
 
 	switch matches[2] {
+	case "h":
+		return time.Duration(num) * time.Hour, nil
 	case "d":
 		return time.Duration(num) * 24 * time.Hour, nil
 	case "w":
@@ -38,3 +41,29 @@ func Parse(s string) (time.Duration, error) {
 		return 0, fmt.Errorf("invalid duration unit: %s", matches[2])
 	}
 }
+
+// timeLayouts are the absolute formats accepted by ParseTime, most specific
+// first. Times without a zone are UTC, matching stored timestamps.
+var timeLayouts = []string{
+	time.RFC3339,
+	"2006-01-02T15:04:05",
+	"2006-01-02 15:04:05",
+	"2006-01-02 15:04",
+	"2006-01-02",
+}
+
+// ParseTime parses a point in time for options such as export --as-of.
+// Accepts an RFC3339 timestamp, a date with optional time ("2024-01-15",
+// "2024-01-15 10:30"), or a duration accepted by Parse meaning that long
+// before now ("7d" = a week ago). A bare date means the start of that day.
+func ParseTime(s string, now time.Time) (time.Time, error) {
+	for _, layout := range timeLayouts {
+		if t, err := time.Parse(layout, s); err == nil {
+			return t, nil
+		}
+	}
+	if d, err := Parse(s); err == nil {
+		return now.Add(-d), nil
+	}
+	return time.Time{}, fmt.Errorf("invalid time: %s (use 2024-01-15, 2024-01-15T10:30:00Z, 24h, or 7d)", s)
+}
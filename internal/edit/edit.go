@@ -8,6 +8,8 @@ import (
 	"io"
 	"strconv"
 	"strings"
+
+	"github.com/jpl-au/llmd/internal/diff"
 )
 
 var (
@@ -26,17 +28,45 @@ type Options struct {
 	Message         string // Version message
 }
 
+// Mode selects how a line-range edit applies its content.
+type Mode int
+
+const (
+	// ModeReplace replaces lines Start through End (the default).
+	ModeReplace Mode = iota
+	// ModeAppend adds content after the last line; Start and End are ignored.
+	ModeAppend
+	// ModePrepend adds content before the first line; Start and End are ignored.
+	ModePrepend
+	// ModeInsert adds content before line Start without replacing anything.
+	ModeInsert
+)
+
 // LineRangeOptions configures a line-range edit operation.
 type LineRangeOptions struct {
 	Start   int    // Start line (1-indexed)
 	End     int    // End line (inclusive)
+	Mode    Mode   // How content is applied (default: replace the range)
+	Section string // Heading whose section replaces Start/End (e.g. "## Install")
 	Author  string // Author attribution
 	Message string // Version message
+
+	// DryRun computes the edit without writing a version. Line numbers are
+	// easy to get wrong by one, and a misplaced range silently clobbers the
+	// neighbouring section; previewing lets the caller confirm the target
+	// lines before anything lands in history.
+	DryRun bool
 }
 
 // Result contains the outcome of an edit operation.
+// The preview fields are only populated for dry runs.
 type Result struct {
-	Path string `json:"path"`
+	Path    string `json:"path"`
+	DryRun  bool   `json:"dry_run,omitempty"`
+	Old     string `json:"old,omitempty"`     // Lines that would be replaced
+	New     string `json:"new,omitempty"`     // Lines that would replace them
+	Content string `json:"content,omitempty"` // Resulting document content
+	Diff    string `json:"diff,omitempty"`    // Diff from current to resulting content
 }
 
 // Editor is the interface for search/replace edit operations.
@@ -46,7 +76,7 @@ type Editor interface {
 
 // LineRangeEditor is the interface for line-range edit operations.
 type LineRangeEditor interface {
-	EditLineRange(ctx context.Context, path, replacement string, opts LineRangeOptions) error
+	EditLineRange(ctx context.Context, path, replacement string, opts LineRangeOptions) (Result, error)
 }
 
 // Run executes an edit operation (search/replace).
@@ -62,14 +92,20 @@ func Run(ctx context.Context, w io.Writer, svc Editor, path string, opts Options
 }
 
 // RunLineRange executes a line-range edit operation, replacing specified lines.
+// With opts.DryRun the diff is written instead and no version is created.
 func RunLineRange(ctx context.Context, w io.Writer, svc LineRangeEditor, path, replacement string, opts LineRangeOptions) (Result, error) {
-	r := Result{Path: path}
+	r, err := svc.EditLineRange(ctx, path, replacement, opts)
+	if err != nil {
+		return Result{Path: path}, err
+	}
 
-	if err := svc.EditLineRange(ctx, path, replacement, opts); err != nil {
-		return r, err
+	if opts.DryRun {
+		d := diff.Result{Old: r.Path, New: r.Path + " (dry run)", Diff: r.Diff}
+		fmt.Fprint(w, d.Format(false))
+		return r, nil
 	}
 
-	fmt.Fprintf(w, "Edited %s\n", path)
+	fmt.Fprintf(w, "Edited %s\n", r.Path)
 	return r, nil
 }
 
@@ -143,6 +179,100 @@ func ReplaceLines(content string, start, end int, replacement string) (string, e
 	return strings.Join(result, "\n"), nil
 }
 
+// RunAppend adds content to the end of a document without resending the
+// existing content. It is a thin wrapper over EditLineRange using ModeAppend.
+func RunAppend(ctx context.Context, w io.Writer, svc LineRangeEditor, path, content string, opts LineRangeOptions) (Result, error) {
+	opts.Mode = ModeAppend
+	return RunLineRange(ctx, w, svc, path, content, opts)
+}
+
+// RunPrepend adds content to the start of a document without resending the
+// existing content. It is a thin wrapper over EditLineRange using ModePrepend.
+func RunPrepend(ctx context.Context, w io.Writer, svc LineRangeEditor, path, content string, opts LineRangeOptions) (Result, error) {
+	opts.Mode = ModePrepend
+	return RunLineRange(ctx, w, svc, path, content, opts)
+}
+
+// Apply performs the line edit described by opts on content.
+// ModeReplace delegates to ReplaceLines; the other modes add text at a
+// document boundary.
+func Apply(content, text string, opts LineRangeOptions) (string, error) {
+	switch opts.Mode {
+	case ModeAppend:
+		return Append(content, text), nil
+	case ModePrepend:
+		return Prepend(content, text), nil
+	case ModeInsert:
+		return InsertLines(content, opts.Start, text)
+	default:
+		return ReplaceLines(content, opts.Start, opts.End, text)
+	}
+}
+
+// Append adds addition after the last line of content.
+// A newline is inserted first when content does not already end with one,
+// so the addition starts on its own line rather than joining the last line.
+func Append(content, addition string) string {
+	if content != "" && !strings.HasSuffix(content, "\n") {
+		content += "\n"
+	}
+	return content + addition
+}
+
+// Prepend adds addition before the first line of content.
+// A newline is inserted after the addition when it lacks one, so the
+// existing first line is not joined onto the new text.
+func Prepend(content, addition string) string {
+	if addition != "" && !strings.HasSuffix(addition, "\n") {
+		addition += "\n"
+	}
+	return addition + content
+}
+
+// InsertLines inserts text before line at, leaving existing lines intact.
+// Lines are 1-indexed: at == 1 prepends and at == last line + 1 appends.
+// A trailing newline terminates the last line rather than starting a new
+// one, so "a\nb\n" has two lines and accepts at in 1..3.
+func InsertLines(content string, at int, text string) (string, error) {
+	lines := strings.Split(content, "\n")
+
+	n := len(lines)
+	if content == "" || strings.HasSuffix(content, "\n") {
+		n--
+	}
+	if at < 1 || at > n+1 {
+		return "", fmt.Errorf("%w: insert line %d outside 1..%d", ErrInvalidLineRange, at, n+1)
+	}
+
+	text = strings.TrimSuffix(text, "\n")
+
+	var result []string
+	result = append(result, lines[:at-1]...)
+	result = append(result, strings.Split(text, "\n")...)
+	result = append(result, lines[at-1:]...)
+
+	return strings.Join(result, "\n"), nil
+}
+
+// Lines returns the text of lines start through end (inclusive), applying
+// the same boundary rules as ReplaceLines so a preview shows exactly the
+// lines an edit would replace.
+func Lines(content string, start, end int) (string, error) {
+	lines := strings.Split(content, "\n")
+
+	if start == 0 {
+		start = 1
+	}
+	if end == 0 || end > len(lines) {
+		end = len(lines)
+	}
+	if start < 1 || start > len(lines) || end < start {
+		return "", fmt.Errorf("%w: %d:%d", ErrInvalidLineRange, start, end)
+	}
+
+	return strings.Join(lines[start-1:end], "\n"), nil
+}
+
 // ParseLineRange parses a line range string like "5:10", "5:", or ":10".
 // Returns start and end line numbers (1-indexed), where 0 means unspecified.
 // Matches cat's parseLineRange behaviour for consistency.
@@ -128,6 +128,120 @@ func TestParseLineRange(t *testing.T) {
 	}
 }
 
+func TestLines(t *testing.T) {
+	content := "one\ntwo\nthree\nfour"
+
+	tests := []struct {
+		name       string
+		start, end int
+		want       string
+		wantErr    bool
+	}{
+		{name: "single line", start: 2, end: 2, want: "two"},
+		{name: "range", start: 2, end: 3, want: "two\nthree"},
+		{name: "open start", start: 0, end: 2, want: "one\ntwo"},
+		{name: "open end", start: 3, end: 0, want: "three\nfour"},
+		{name: "end clamped", start: 3, end: 10, want: "three\nfour"},
+		{name: "start past end of document", start: 5, end: 6, wantErr: true},
+		{name: "end before start", start: 3, end: 2, wantErr: true},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			got, err := Lines(content, tt.start, tt.end)
+			if tt.wantErr {
+				if !errors.Is(err, ErrInvalidLineRange) {
+					t.Errorf("Lines(%d, %d) error = %v, want ErrInvalidLineRange", tt.start, tt.end, err)
+				}
+				return
+			}
+			if err != nil {
+				t.Fatalf("Lines(%d, %d) = error %v", tt.start, tt.end, err)
+			}
+			if got != tt.want {
+				t.Errorf("Lines(%d, %d) = %q, want %q", tt.start, tt.end, got, tt.want)
+			}
+		})
+	}
+}
+
+func TestAppend(t *testing.T) {
+	tests := []struct {
+		name     string
+		content  string
+		addition string
+		want     string
+	}{
+		{name: "trailing newline", content: "a\nb\n", addition: "c\n", want: "a\nb\nc\n"},
+		{name: "no trailing newline", content: "a\nb", addition: "c", want: "a\nb\nc"},
+		{name: "empty document", content: "", addition: "c\n", want: "c\n"},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			if got := Append(tt.content, tt.addition); got != tt.want {
+				t.Errorf("Append(%q, %q) = %q, want %q", tt.content, tt.addition, got, tt.want)
+			}
+		})
+	}
+}
+
+func TestPrepend(t *testing.T) {
+	tests := []struct {
+		name     string
+		content  string
+		addition string
+		want     string
+	}{
+		{name: "addition with newline", content: "b\n", addition: "a\n", want: "a\nb\n"},
+		{name: "addition without newline", content: "b\n", addition: "a", want: "a\nb\n"},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			if got := Prepend(tt.content, tt.addition); got != tt.want {
+				t.Errorf("Prepend(%q, %q) = %q, want %q", tt.content, tt.addition, got, tt.want)
+			}
+		})
+	}
+}
+
+func TestInsertLines(t *testing.T) {
+	tests := []struct {
+		name    string
+		content string
+		at      int
+		text    string
+		want    string
+		wantErr bool
+	}{
+		{name: "first line prepends", content: "a\nb\n", at: 1, text: "x\n", want: "x\na\nb\n"},
+		{name: "between lines", content: "a\nb\n", at: 2, text: "x", want: "a\nx\nb\n"},
+		{name: "last plus one appends", content: "a\nb\n", at: 3, text: "x\n", want: "a\nb\nx\n"},
+		{name: "no trailing newline", content: "a\nb", at: 3, text: "x", want: "a\nb\nx"},
+		{name: "multiple lines", content: "a\nd\n", at: 2, text: "b\nc\n", want: "a\nb\nc\nd\n"},
+		{name: "empty document", content: "", at: 1, text: "x", want: "x\n"},
+		{name: "zero", content: "a\nb\n", at: 0, text: "x", wantErr: true},
+		{name: "past end", content: "a\nb\n", at: 4, text: "x", wantErr: true},
+		{name: "past end of empty document", content: "", at: 2, text: "x", wantErr: true},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			got, err := InsertLines(tt.content, tt.at, tt.text)
+			if tt.wantErr {
+				if !errors.Is(err, ErrInvalidLineRange) {
+					t.Errorf("InsertLines(%q, %d) error = %v, want ErrInvalidLineRange", tt.content, tt.at, err)
+				}
+				return
+			}
+			if err != nil {
+				t.Fatalf("InsertLines(%q, %d) error = %v", tt.content, tt.at, err)
+			}
+			if got != tt.want {
+				t.Errorf("InsertLines(%q, %d, %q) = %q, want %q", tt.content, tt.at, tt.text, got, tt.want)
+			}
+		})
+	}
+}
+
 func contains(s, substr string) bool {
 	return len(s) >= len(substr) && (s == substr || len(substr) == 0 ||
 		(len(s) > 0 && len(substr) > 0 && stringContains(s, substr)))
@@ -0,0 +1,166 @@
+package edit
+
+import (
+	"errors"
+	"fmt"
+	"strconv"
+	"strings"
+)
+
+var (
+	// ErrSectionNotFound is returned when no heading matches a section target.
+	ErrSectionNotFound = errors.New("section not found")
+	// ErrAmbiguousSection is returned when several headings match a section target.
+	ErrAmbiguousSection = errors.New("ambiguous section")
+)
+
+// Heading is an ATX heading found in a document.
+type Heading struct {
+	Line  int    // 1-indexed line number
+	Level int    // Number of leading '#' characters (1-6)
+	Text  string // Heading text without markers
+}
+
+// Headings returns the ATX headings in content, in document order.
+// Headings inside fenced code blocks are ignored, so a "# comment" in a
+// shell example is not mistaken for a section boundary.
+func Headings(content string) []Heading {
+	var hs []Heading
+	fence := ""
+	for i, line := range strings.Split(content, "\n") {
+		line = strings.TrimRight(line, "\r")
+		if f := FenceMarker(line); f != "" {
+			switch {
+			case fence == "":
+				fence = f
+			case strings.HasPrefix(f, fence):
+				fence = ""
+			}
+			continue
+		}
+		if fence != "" {
+			continue
+		}
+		if n, text := HeadingLevel(line); n > 0 {
+			hs = append(hs, Heading{Line: i + 1, Level: n, Text: text})
+		}
+	}
+	return hs
+}
+
+// Section is a heading together with the lines it spans.
+type Section struct {
+	Heading
+	End int // Last line of the section (inclusive)
+}
+
+// Sections returns every heading in content with its line range. A section
+// runs from its heading up to, but not including, the next heading of the
+// same or higher level, so subsections are nested inside their parent's range.
+func Sections(content string) []Section {
+	hs := Headings(content)
+
+	// A trailing newline ends the last line rather than starting an empty
+	// one, so it does not count towards the final section.
+	last := strings.Count(content, "\n") + 1
+	if strings.HasSuffix(content, "\n") {
+		last--
+	}
+
+	sections := make([]Section, len(hs))
+	for i, h := range hs {
+		sections[i] = Section{Heading: h, End: last}
+		for _, next := range hs[i+1:] {
+			if next.Level <= h.Level {
+				sections[i].End = next.Line - 1
+				break
+			}
+		}
+	}
+	return sections
+}
+
+// FindSection returns the section under heading. heading is written as in
+// the document ("## Installation"); bare text without '#' markers matches a
+// heading of any level. It fails if no heading matches or several do.
+func FindSection(content, heading string) (Section, error) {
+	level, text := HeadingLevel(heading)
+	if level == 0 {
+		text = strings.TrimSpace(heading)
+	}
+	if text == "" {
+		return Section{}, fmt.Errorf("%w: empty heading %q", ErrSectionNotFound, heading)
+	}
+
+	var matches []Section
+	for _, s := range Sections(content) {
+		if s.Text == text && (level == 0 || s.Level == level) {
+			matches = append(matches, s)
+		}
+	}
+	switch len(matches) {
+	case 0:
+		return Section{}, fmt.Errorf("%w: %q", ErrSectionNotFound, heading)
+	case 1:
+		return matches[0], nil
+	}
+	lines := make([]string, len(matches))
+	for i, m := range matches {
+		lines[i] = strconv.Itoa(m.Line)
+	}
+	return Section{}, fmt.Errorf("%w: %q matches headings on lines %s", ErrAmbiguousSection, heading, strings.Join(lines, ", "))
+}
+
+// SectionRange returns the lines spanned by the section under heading, as
+// matched by FindSection.
+//
+// The returned range is suitable for LineRangeOptions, so a section edit is
+// an ordinary line-range replace resolved against the current content.
+func SectionRange(content, heading string) (start, end int, err error) {
+	s, err := FindSection(content, heading)
+	if err != nil {
+		return 0, 0, err
+	}
+	return s.Line, s.End, nil
+}
+
+// HeadingLevel returns the level and text of an ATX heading, or 0 if line is
+// not one. Up to three spaces of indentation are allowed, as in CommonMark.
+func HeadingLevel(line string) (int, string) {
+	s := strings.TrimLeft(line, " ")
+	if len(line)-len(s) > 3 {
+		return 0, ""
+	}
+	n := 0
+	for n < len(s) && s[n] == '#' {
+		n++
+	}
+	if n == 0 || n > 6 {
+		return 0, ""
+	}
+	if n < len(s) && s[n] != ' ' && s[n] != '\t' {
+		return 0, ""
+	}
+	text := strings.TrimSpace(s[n:])
+	text = strings.TrimSpace(strings.TrimRight(text, "#"))
+	return n, text
+}
+
+// FenceMarker returns the run of backticks or tildes opening a fenced code
+// block on line, or "" if line is not a fence.
+func FenceMarker(line string) string {
+	s := strings.TrimLeft(line, " ")
+	if len(line)-len(s) > 3 {
+		return ""
+	}
+	for _, c := range []string{"`", "~"} {
+		n := 0
+		for n < len(s) && s[n:n+1] == c {
+			n++
+		}
+		if n >= 3 {
+			return s[:n]
+		}
+	}
+	return ""
+}
@@ -0,0 +1,47 @@
+package edit
+
+import (
+	"errors"
+	"testing"
+)
+
+const sectionDoc = "# Title\n\nIntro.\n\n## Install\n\nSteps.\n\n### Linux\n\napt install\n\n```sh\n# not a heading\n```\n\n## Usage\n\nRun it.\n"
+
+func TestSectionRange(t *testing.T) {
+	tests := []struct {
+		name       string
+		content    string
+		heading    string
+		start, end int
+		wantErr    error
+	}{
+		{name: "up to next same level", content: sectionDoc, heading: "## Install", start: 5, end: 16},
+		{name: "subsection stops at parent sibling", content: sectionDoc, heading: "### Linux", start: 9, end: 16},
+		{name: "last section keeps trailing newline", content: sectionDoc, heading: "## Usage", start: 17, end: 19},
+		{name: "top level spans document", content: sectionDoc, heading: "# Title", start: 1, end: 19},
+		{name: "bare text matches any level", content: sectionDoc, heading: "Linux", start: 9, end: 16},
+		{name: "no trailing newline", content: "# A\nx\n# B\ny", heading: "# B", start: 3, end: 4},
+		{name: "wrong level", content: sectionDoc, heading: "# Install", wantErr: ErrSectionNotFound},
+		{name: "missing", content: sectionDoc, heading: "## Nope", wantErr: ErrSectionNotFound},
+		{name: "inside code fence", content: sectionDoc, heading: "# not a heading", wantErr: ErrSectionNotFound},
+		{name: "empty", content: sectionDoc, heading: "##", wantErr: ErrSectionNotFound},
+		{name: "ambiguous", content: "## Notes\na\n## Notes\nb\n", heading: "## Notes", wantErr: ErrAmbiguousSection},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			start, end, err := SectionRange(tt.content, tt.heading)
+			if tt.wantErr != nil {
+				if !errors.Is(err, tt.wantErr) {
+					t.Errorf("SectionRange(%q) error = %v, want %v", tt.heading, err, tt.wantErr)
+				}
+				return
+			}
+			if err != nil {
+				t.Fatalf("SectionRange(%q) error = %v", tt.heading, err)
+			}
+			if start != tt.start || end != tt.end {
+				t.Errorf("SectionRange(%q) = %d:%d, want %d:%d", tt.heading, start, end, tt.start, tt.end)
+			}
+		})
+	}
+}
@@ -0,0 +1,119 @@
+// archive.go implements export into a single .tar.gz or .zip bundle.
+//
+// Separated from exporter.go because a bundle is written as one stream
+// rather than as files under an os.Root, so none of the directory handling
+// applies. Entry names follow the same mapping as a directory export.
+
+package exporter
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"io"
+	"os"
+	pathpkg "path"
+	"strings"
+	"time"
+
+	"github.com/jpl-au/llmd/internal/archive"
+	"github.com/jpl-au/llmd/internal/contenttype"
+	"github.com/jpl-au/llmd/internal/service"
+	"github.com/jpl-au/llmd/internal/store"
+)
+
+// ManifestName is the archive entry holding the export manifest.
+const ManifestName = "manifest.json"
+
+// exportArchive writes the documents selected by path into opts.Archive,
+// followed by a manifest. A partially written archive is removed on error.
+func exportArchive(ctx context.Context, w io.Writer, svc service.Service, path string, opts Options) (result Result, err error) {
+	if opts.Verify {
+		return result, errors.New("--verify is not supported with --archive")
+	}
+	format, err := archive.FormatOf(opts.Archive)
+	if err != nil {
+		return result, err
+	}
+
+	docs, names, err := archiveDocs(ctx, svc, path, opts)
+	if err != nil {
+		return result, err
+	}
+
+	flags := os.O_WRONLY | os.O_CREATE | os.O_TRUNC
+	if !opts.Force {
+		flags |= os.O_EXCL
+	}
+	f, err := os.OpenFile(opts.Archive, flags, 0644)
+	if errors.Is(err, os.ErrExist) {
+		return result, fmt.Errorf("file exists: %s (use --force to overwrite)", opts.Archive)
+	}
+	if err != nil {
+		return result, fmt.Errorf("creating archive: %w", err)
+	}
+	defer func() {
+		if cerr := f.Close(); err == nil && cerr != nil {
+			err = fmt.Errorf("closing archive: %w", cerr)
+		}
+		if err != nil {
+			os.Remove(opts.Archive)
+		}
+	}()
+
+	aw, err := archive.NewWriter(f, format)
+	if err != nil {
+		return result, err
+	}
+	for i, doc := range docs {
+		if err := aw.Add(names[i], doc.Content, time.Unix(doc.CreatedAt, 0)); err != nil {
+			return result, err
+		}
+		result.Entries = append(result.Entries, newEntry(&doc, names[i]))
+		result.Exported++
+		fmt.Fprintf(w, "Exported: %s -> %s\n", doc.Path, names[i])
+	}
+
+	manifest, err := encodeManifest(result.Entries)
+	if err != nil {
+		return result, err
+	}
+	if err := aw.Add(ManifestName, string(manifest), time.Now()); err != nil {
+		return result, err
+	}
+	if err := aw.Close(); err != nil {
+		return result, fmt.Errorf("writing archive: %w", err)
+	}
+
+	result.Paths = []string{opts.Archive}
+	fmt.Fprintf(w, "Archive: %s\n", opts.Archive)
+	return result, nil
+}
+
+// archiveDocs selects the documents for an archive export along with their
+// entry names. A prefix keeps paths relative to it, as a directory export
+// would; a single document is named by its base name.
+func archiveDocs(ctx context.Context, svc service.Service, path string, opts Options) ([]store.Document, []string, error) {
+	if strings.HasSuffix(path, "/") || path == "/" {
+		pfx := strings.TrimSuffix(path, "/")
+		docs, err := prefixDocs(ctx, svc, pfx, opts)
+		if err != nil {
+			return nil, nil, err
+		}
+		names := make([]string, len(docs))
+		for i, d := range docs {
+			names[i] = calcRelativePath(d.Path, pfx) + contenttype.Extension(d.ContentType)
+		}
+		return docs, names, nil
+	}
+
+	doc, _, err := svc.Resolve(ctx, path, !opts.AsOf.IsZero())
+	if err != nil {
+		return nil, nil, fmt.Errorf("resolving document: %w", err)
+	}
+	doc, err = getDoc(ctx, svc, doc.Path, opts)
+	if err != nil {
+		return nil, nil, fmt.Errorf("getting document: %w", err)
+	}
+	return []store.Document{*doc}, []string{pathpkg.Base(doc.Path) + contenttype.Extension(doc.ContentType)}, nil
+}
@@ -3,6 +3,9 @@ package exporter
 
 import (
 	"context"
+	"crypto/sha256"
+	"encoding/hex"
+	"encoding/json"
 	"errors"
 	"fmt"
 	"io"
@@ -10,31 +13,85 @@ import (
 	"os"
 	"path/filepath"
 	"strings"
+	"time"
 
+	"github.com/jpl-au/llmd/internal/contenttype"
 	"github.com/jpl-au/llmd/internal/progress"
 	"github.com/jpl-au/llmd/internal/service"
+	"github.com/jpl-au/llmd/internal/store"
 )
 
+// ErrVerifyFailed is returned when an exported file does not read back
+// with the content that was written.
+var ErrVerifyFailed = errors.New("verification failed")
+
 // Options configures an export operation.
 type Options struct {
-	Version int  // Specific version to export (0 = latest)
-	Force   bool // Overwrite existing files
+	Version  int       // Specific version to export (0 = latest)
+	AsOf     time.Time // Export each document as it was at this time (zero = latest)
+	Force    bool      // Overwrite existing files
+	Manifest string    // Write a JSON manifest to this filesystem path
+	Verify   bool      // Re-read each file after writing and compare
+
+	// Archive writes every document into this single .tar.gz or .zip file
+	// instead of a directory tree, with a manifest.json alongside them.
+	Archive string
 }
 
 // Result contains the outcome of an export operation.
 type Result struct {
 	Exported int      // Number of files exported
 	Paths    []string // Filesystem paths that were written
+	Entries  []Entry  // One per exported document, as recorded in a manifest
+}
+
+// Manifest records what an export wrote so a later re-import can be checked
+// against it.
+type Manifest struct {
+	ExportedAt string  `json:"exported_at"`
+	Documents  []Entry `json:"documents"`
+}
+
+// Entry describes one exported document.
+type Entry struct {
+	Path    string `json:"path"`    // Document path in the store
+	File    string `json:"file"`    // Filesystem path written
+	Key     string `json:"key"`     // Version key exported
+	Version int    `json:"version"` // Version number exported
+	SHA256  string `json:"sha256"`  // Hex SHA-256 of the content
 }
 
 // Run executes the export operation.
 // If path ends with "/" it exports all documents with that prefix.
-// Otherwise it exports a single document.
+// Otherwise it exports a single document. With opts.Archive set, dst is
+// unused and the documents go into that archive instead.
 func Run(ctx context.Context, w io.Writer, svc service.Service, path, dst string, opts Options) (Result, error) {
-	if strings.HasSuffix(path, "/") || path == "/" {
-		return exportPrefix(ctx, w, svc, strings.TrimSuffix(path, "/"), dst, opts)
+	// Refuse up front rather than after every document has been written
+	if opts.Manifest != "" && !opts.Force {
+		if _, err := os.Stat(opts.Manifest); err == nil {
+			return Result{}, fmt.Errorf("manifest exists: %s (use --force to overwrite)", opts.Manifest)
+		}
 	}
-	return exportSingle(ctx, w, svc, path, dst, opts)
+
+	var result Result
+	var err error
+	switch {
+	case opts.Archive != "":
+		result, err = exportArchive(ctx, w, svc, path, opts)
+	case strings.HasSuffix(path, "/") || path == "/":
+		result, err = exportPrefix(ctx, w, svc, strings.TrimSuffix(path, "/"), dst, opts)
+	default:
+		result, err = exportSingle(ctx, w, svc, path, dst, opts)
+	}
+	if err != nil || opts.Manifest == "" {
+		return result, err
+	}
+
+	if err := writeManifest(opts.Manifest, result.Entries); err != nil {
+		return result, err
+	}
+	fmt.Fprintf(w, "Manifest: %s\n", opts.Manifest)
+	return result, nil
 }
 
 // exportSingle exports a single document to the filesystem.
@@ -42,19 +99,20 @@ func Run(ctx context.Context, w io.Writer, svc service.Service, path, dst string
 func exportSingle(ctx context.Context, w io.Writer, svc service.Service, docPath, dst string, opts Options) (Result, error) {
 	var result Result
 
-	// Resolve path or key to get actual document path
-	doc, _, err := svc.Resolve(ctx, docPath, false)
+	// Resolve path or key to get actual document path. A point-in-time
+	// export may target a document that has since been deleted.
+	doc, _, err := svc.Resolve(ctx, docPath, !opts.AsOf.IsZero())
 	if err != nil {
 		return result, fmt.Errorf("resolving document: %w", err)
 	}
 	docPath = doc.Path
 
-	content, err := getContent(ctx, svc, docPath, opts.Version)
+	doc, err = getDoc(ctx, svc, docPath, opts)
 	if err != nil {
 		return result, fmt.Errorf("getting document: %w", err)
 	}
 
-	outPath, dir, name, err := calcSingleOutputPath(dst, docPath)
+	outPath, dir, name, err := calcSingleOutputPath(dst, docPath, contenttype.Extension(doc.ContentType))
 	if err != nil {
 		return result, fmt.Errorf("calculating output path: %w", err)
 	}
@@ -71,12 +129,18 @@ func exportSingle(ctx context.Context, w io.Writer, svc service.Service, docPath
 	}
 	defer root.Close()
 
-	if err := writeFileInRoot(root, name, content, opts.Force); err != nil {
+	if err := writeFileInRoot(root, name, doc.Content, opts.Force); err != nil {
 		return result, err
 	}
+	if opts.Verify {
+		if err := verifyFileInRoot(root, name, doc.Content); err != nil {
+			return result, err
+		}
+	}
 
 	result.Exported = 1
 	result.Paths = []string{outPath}
+	result.Entries = []Entry{newEntry(doc, outPath)}
 	fmt.Fprintf(w, "Exported: %s -> %s\n", docPath, outPath)
 
 	return result, nil
@@ -87,15 +151,12 @@ func exportSingle(ctx context.Context, w io.Writer, svc service.Service, docPath
 func exportPrefix(ctx context.Context, w io.Writer, svc service.Service, pfx, dst string, opts Options) (Result, error) {
 	var result Result
 
-	docs, err := svc.List(ctx, pfx, false, false)
+	asOf := !opts.AsOf.IsZero()
+	docs, err := prefixDocs(ctx, svc, pfx, opts)
 	if err != nil {
 		return result, err
 	}
 
-	if len(docs) == 0 {
-		return result, fmt.Errorf("no documents found with prefix: %s", pfx)
-	}
-
 	// Create destination directory
 	if err := os.MkdirAll(dst, 0755); err != nil {
 		return result, fmt.Errorf("creating destination directory: %w", err)
@@ -112,22 +173,29 @@ func exportPrefix(ctx context.Context, w io.Writer, svc service.Service, pfx, ds
 	defer prog.Done()
 
 	for _, d := range docs {
-		rel := calcRelativePath(d.Path, pfx)
-		outName := rel + ".md"
-
-		content, err := getContent(ctx, svc, d.Path, 0)
-		if err != nil {
-			return result, fmt.Errorf("getting %s: %w", d.Path, err)
+		doc := &d
+		if !asOf {
+			doc, err = getDoc(ctx, svc, d.Path, Options{})
+			if err != nil {
+				return result, fmt.Errorf("getting %s: %w", d.Path, err)
+			}
 		}
+		outName := calcRelativePath(d.Path, pfx) + contenttype.Extension(doc.ContentType)
 
-		if err := writeFileInRoot(root, outName, content, opts.Force); err != nil {
+		if err := writeFileInRoot(root, outName, doc.Content, opts.Force); err != nil {
 			return result, err
 		}
+		if opts.Verify {
+			if err := verifyFileInRoot(root, outName, doc.Content); err != nil {
+				return result, err
+			}
+		}
 
 		prog.Increment()
 		prog.Print()
 		outPath := filepath.Join(dst, outName)
 		result.Paths = append(result.Paths, outPath)
+		result.Entries = append(result.Entries, newEntry(doc, outPath))
 		result.Exported++
 		fmt.Fprintf(w, "Exported: %s -> %s\n", d.Path, outPath)
 	}
@@ -135,34 +203,136 @@ func exportPrefix(ctx context.Context, w io.Writer, svc service.Service, pfx, ds
 	return result, nil
 }
 
-// getContent retrieves document content, optionally at a specific version.
-func getContent(ctx context.Context, svc service.Service, path string, version int) (string, error) {
-	if version > 0 {
-		d, err := svc.Version(ctx, path, version)
+// prefixDocs lists the documents a prefix export covers, each at the
+// version selected by opts.AsOf. It is an error for none to match.
+func prefixDocs(ctx context.Context, svc service.Service, pfx string, opts Options) ([]store.Document, error) {
+	asOf := !opts.AsOf.IsZero()
+
+	// Documents deleted since the as-of time still belong in the snapshot
+	docs, err := svc.List(ctx, pfx, asOf, false)
+	if err != nil {
+		return nil, err
+	}
+	if asOf {
+		docs, err = versionsAsOf(ctx, svc, docs, opts.AsOf)
+		if err != nil {
+			return nil, err
+		}
+	}
+
+	if len(docs) == 0 {
+		if asOf {
+			return nil, fmt.Errorf("no documents found with prefix %s as of %s", pfx, opts.AsOf.UTC().Format(time.RFC3339))
+		}
+		return nil, fmt.Errorf("no documents found with prefix: %s", pfx)
+	}
+	return docs, nil
+}
+
+// getDoc retrieves a document at the version or time selected by opts,
+// falling back to the latest version.
+func getDoc(ctx context.Context, svc service.Service, path string, opts Options) (*store.Document, error) {
+	switch {
+	case opts.Version > 0:
+		return svc.Version(ctx, path, opts.Version)
+	case !opts.AsOf.IsZero():
+		return svc.VersionAsOf(ctx, path, opts.AsOf)
+	}
+	return svc.Latest(ctx, path, false)
+}
+
+// versionsAsOf replaces each document with its version current at t.
+// Documents that did not exist, or were deleted, at t are dropped.
+func versionsAsOf(ctx context.Context, svc service.Service, docs []store.Document, t time.Time) ([]store.Document, error) {
+	var out []store.Document
+	for _, d := range docs {
+		doc, err := svc.VersionAsOf(ctx, d.Path, t)
+		if errors.Is(err, store.ErrNotFound) {
+			continue
+		}
 		if err != nil {
-			return "", err
+			return nil, fmt.Errorf("getting %s: %w", d.Path, err)
 		}
-		return d.Content, nil
+		out = append(out, *doc)
+	}
+	return out, nil
+}
+
+// newEntry builds the manifest entry for a document written to file.
+func newEntry(doc *store.Document, file string) Entry {
+	return Entry{
+		Path:    doc.Path,
+		File:    file,
+		Key:     doc.Key,
+		Version: doc.Version,
+		SHA256:  hashContent(doc.Content),
+	}
+}
+
+// hashContent returns the hex SHA-256 of content.
+func hashContent(content string) string {
+	sum := sha256.Sum256([]byte(content))
+	return hex.EncodeToString(sum[:])
+}
+
+// writeManifest writes entries as an indented JSON manifest.
+func writeManifest(path string, entries []Entry) error {
+	b, err := encodeManifest(entries)
+	if err != nil {
+		return err
+	}
+	if err := os.WriteFile(path, b, 0644); err != nil {
+		return fmt.Errorf("writing manifest: %w", err)
 	}
-	d, err := svc.Latest(ctx, path, false)
+	return nil
+}
+
+// encodeManifest renders entries as an indented JSON manifest stamped with
+// the current time.
+func encodeManifest(entries []Entry) ([]byte, error) {
+	m := Manifest{
+		ExportedAt: time.Now().UTC().Format(time.RFC3339),
+		Documents:  entries,
+	}
+	b, err := json.MarshalIndent(m, "", "  ")
 	if err != nil {
-		return "", err
+		return nil, fmt.Errorf("encoding manifest: %w", err)
 	}
-	return d.Content, nil
+	return append(b, '\n'), nil
+}
+
+// verifyFileInRoot re-reads a file just written and checks it holds exactly
+// content, catching truncated writes or filesystems that alter bytes.
+func verifyFileInRoot(root *os.Root, name, content string) error {
+	f, err := root.Open(name)
+	if err != nil {
+		return fmt.Errorf("%w: %s: %w", ErrVerifyFailed, name, err)
+	}
+	defer f.Close()
+
+	got, err := io.ReadAll(f)
+	if err != nil {
+		return fmt.Errorf("%w: %s: %w", ErrVerifyFailed, name, err)
+	}
+	if hashContent(string(got)) != hashContent(content) {
+		return fmt.Errorf("%w: %s does not match the stored content", ErrVerifyFailed, name)
+	}
+	return nil
 }
 
 // calcSingleOutputPath determines the output path for a single document export.
+// ext is the file extension for the document's content type (".md" for markdown).
 // Returns the full path, directory, and filename for use with os.Root.
-func calcSingleOutputPath(dst, docPath string) (fullPath, dir, name string, err error) { //nolint:unparam
+func calcSingleOutputPath(dst, docPath, ext string) (fullPath, dir, name string, err error) { //nolint:unparam
 	info, statErr := os.Stat(dst)
 	switch {
 	case statErr == nil && info.IsDir():
 		// Destination is a directory - add filename inside it
-		name = filepath.Base(docPath) + ".md"
+		name = filepath.Base(docPath) + ext
 		return filepath.Join(dst, name), dst, name, nil
-	case !strings.HasSuffix(dst, ".md"):
-		// Non-existent path without .md - add extension
-		fullPath = dst + ".md"
+	case !strings.HasSuffix(dst, ext):
+		// Non-existent path without the extension - add it
+		fullPath = dst + ext
 	default:
 		fullPath = dst
 	}
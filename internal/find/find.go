@@ -10,6 +10,7 @@ import (
 	"io"
 
 	"github.com/jpl-au/llmd/internal/format"
+	"github.com/jpl-au/llmd/internal/path"
 	"github.com/jpl-au/llmd/internal/service"
 	"github.com/jpl-au/llmd/internal/store"
 )
@@ -20,6 +21,17 @@ type Options struct {
 	IncludeAll  bool   // Include deleted documents
 	DeletedOnly bool   // Search only deleted documents
 	PathsOnly   bool   // Only output paths
+	Literal     bool   // Match query terms as plain text, not FTS5 syntax
+
+	// Not excludes documents containing any of these terms. Each is quoted
+	// as plain text and added to the query with FTS5's NOT, so punctuation
+	// in a term cannot change the meaning of the query.
+	Not []string
+
+	// Exclude drops results under any of these path prefixes, matched as
+	// directories: "docs/archive" excludes docs/archive and everything
+	// beneath it, but not docs/archived.
+	Exclude []string
 }
 
 // Result contains the outcome of a search operation.
@@ -31,10 +43,30 @@ type Result struct {
 func Run(ctx context.Context, w io.Writer, svc service.Service, query string, opts Options) (Result, error) {
 	var result Result
 
-	docs, err := svc.Search(ctx, query, opts.Prefix, opts.IncludeAll, opts.DeletedOnly)
+	match := query
+	if opts.Literal {
+		match = store.LiteralQuery(query)
+	}
+	for _, term := range opts.Not {
+		match = "(" + match + ") NOT (" + store.LiteralQuery(term) + ")"
+	}
+
+	exclude := make([]string, len(opts.Exclude))
+	for i, e := range opts.Exclude {
+		p, err := path.Normalise(e)
+		if err != nil {
+			return result, err
+		}
+		exclude[i] = p
+	}
+
+	docs, err := svc.Search(ctx, match, opts.Prefix, opts.IncludeAll, opts.DeletedOnly)
 	if err != nil {
 		return result, err
 	}
+	if len(exclude) > 0 {
+		docs = excluded(docs, exclude)
+	}
 
 	result.Documents = docs
 
@@ -46,3 +78,21 @@ func Run(ctx context.Context, w io.Writer, svc service.Service, query string, op
 
 	return result, err
 }
+
+// excluded returns docs without those under any of the prefixes.
+func excluded(docs []store.Document, prefixes []string) []store.Document {
+	var kept []store.Document
+	for _, d := range docs {
+		skip := false
+		for _, p := range prefixes {
+			if path.Under(d.Path, p) {
+				skip = true
+				break
+			}
+		}
+		if !skip {
+			kept = append(kept, d)
+		}
+	}
+	return kept
+}
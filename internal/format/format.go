@@ -6,15 +6,20 @@
 package format
 
 import (
+	"encoding/csv"
 	"fmt"
 	"io"
 	"sort"
 	"strconv"
 	"strings"
 	"time"
+	"unicode"
 
 	"github.com/jpl-au/llmd/internal/diff"
 	"github.com/jpl-au/llmd/internal/store"
+	"golang.org/x/text/runes"
+	"golang.org/x/text/transform"
+	"golang.org/x/text/unicode/norm"
 )
 
 // humanSize formats a byte count as human-readable (e.g., "1.2K", "3.4M").
@@ -49,6 +54,18 @@ func List(w io.Writer, docs []store.Document) error {
 	return nil
 }
 
+// ListMeta prints document metadata in the same format as List.
+func ListMeta(w io.Writer, metas []store.DocumentMeta) error {
+	for _, m := range metas {
+		prefix := ""
+		if m.DeletedAt != nil {
+			prefix = "[deleted] "
+		}
+		fmt.Fprintf(w, "%s  %s%s\n", m.Key, prefix, m.Path)
+	}
+	return nil
+}
+
 // Long prints documents in long format with key, version, date, and author.
 func Long(w io.Writer, docs []store.Document) error {
 	if len(docs) == 0 {
@@ -87,11 +104,24 @@ func Long(w io.Writer, docs []store.Document) error {
 // come first so they align properly. Variable-length fields like AUTHOR and
 // PATH are placed at the end where their varying widths do not disrupt the
 // alignment of other columns.
-func LongMeta(w io.Writer, metas []store.DocumentMeta) error {
+//
+// Sizes are in bytes, or abbreviated (1.2K, 3.4M) when human is set.
+func LongMeta(w io.Writer, metas []store.DocumentMeta, human bool) error {
 	if len(metas) == 0 {
 		return nil
 	}
 
+	sizes := make([]string, len(metas))
+	maxSize := 6 // minimum, fits every humanSize result
+	for i, m := range metas {
+		if human {
+			sizes[i] = humanSize(m.Size)
+		} else {
+			sizes[i] = strconv.FormatInt(m.Size, 10)
+		}
+		maxSize = max(maxSize, len(sizes[i]))
+	}
+
 	// Find max author length for alignment
 	maxAuthor := 6 // minimum "AUTHOR"
 	for _, m := range metas {
@@ -105,36 +135,119 @@ func LongMeta(w io.Writer, metas []store.DocumentMeta) error {
 	}
 
 	// Print header
-	fmt.Fprintf(w, "%4s  %-8s  %6s  %-16s  %-*s  %s\n", "VER", "KEY", "SIZE", "UPDATED", maxAuthor, "AUTHOR", "PATH")
+	fmt.Fprintf(w, "%4s  %-8s  %*s  %-16s  %-*s  %s\n", "VER", "KEY", maxSize, "SIZE", "UPDATED", maxAuthor, "AUTHOR", "PATH")
 
-	for _, m := range metas {
+	for i, m := range metas {
 		updated := time.Unix(m.CreatedAt, 0).Format("2006-01-02 15:04")
 		author := m.Author
 		if author == "" {
 			author = "-"
 		}
-		size := humanSize(m.Size)
 		deleted := ""
 		if m.DeletedAt != nil {
 			deleted = " [deleted]"
 		}
-		fmt.Fprintf(w, "%4d  %s  %6s  %s  %-*s  %s%s\n", m.Version, m.Key, size, updated, maxAuthor, author, m.Path, deleted)
+		fmt.Fprintf(w, "%4d  %s  %*s  %s  %-*s  %s%s\n", m.Version, m.Key, maxSize, sizes[i], updated, maxAuthor, author, m.Path, deleted)
 	}
 	return nil
 }
 
-// Tree prints documents as a directory tree.
-func Tree(w io.Writer, docs []store.Document) error {
+// Delimited prints document metadata as CSV (comma ',') or TSV (tab '\t')
+// with a header row, for loading into spreadsheets or pandas. Fields that
+// contain the delimiter, quotes or newlines are quoted per RFC 4180.
+func Delimited(w io.Writer, metas []store.DocumentMeta, comma rune) error {
+	cw := csv.NewWriter(w)
+	cw.Comma = comma
+
+	if err := cw.Write([]string{"key", "path", "version", "author", "size", "created_at"}); err != nil {
+		return err
+	}
+	for _, m := range metas {
+		row := []string{
+			m.Key,
+			m.Path,
+			strconv.Itoa(m.Version),
+			m.Author,
+			strconv.FormatInt(m.Size, 10),
+			time.Unix(m.CreatedAt, 0).UTC().Format(time.RFC3339),
+		}
+		if err := cw.Write(row); err != nil {
+			return err
+		}
+	}
+	cw.Flush()
+	return cw.Error()
+}
+
+// TreeMode selects which nodes Tree prints.
+type TreeMode int
+
+const (
+	TreeAll   TreeMode = iota // Directories and documents
+	TreeDirs                  // Directories only, each with its document count
+	TreeFiles                 // Document paths only, one per line
+)
+
+// Dir is a directory implied by document paths, with the number of documents
+// anywhere beneath it.
+type Dir struct {
+	Path  string `json:"path"`
+	Count int    `json:"count"`
+}
+
+// Dirs returns the directories implied by the paths of docs, sorted by path.
+// Directories are never stored; "docs/api/auth" implies "docs" and "docs/api".
+func Dirs(docs []store.Document) []Dir {
+	counts := make(map[string]int)
+	for _, doc := range docs {
+		for i := range len(doc.Path) {
+			if doc.Path[i] == '/' {
+				counts[doc.Path[:i]]++
+			}
+		}
+	}
+	dirs := make([]Dir, 0, len(counts))
+	for p, n := range counts {
+		dirs = append(dirs, Dir{Path: p, Count: n})
+	}
+	sort.Slice(dirs, func(i, j int) bool { return dirs[i].Path < dirs[j].Path })
+	return dirs
+}
+
+// DirList prints directories one per line with their document counts.
+func DirList(w io.Writer, dirs []Dir) error {
+	for _, d := range dirs {
+		fmt.Fprintf(w, "%s/ (%d)\n", d.Path, d.Count)
+	}
+	return nil
+}
+
+// Tree prints documents as a directory tree. mode can limit the output to
+// the directory nodes (with counts) or to the document paths.
+func Tree(w io.Writer, docs []store.Document, mode TreeMode) error {
 	if len(docs) == 0 {
 		return nil
 	}
 
+	if mode == TreeFiles {
+		paths := make([]string, len(docs))
+		for i, doc := range docs {
+			paths[i] = doc.Path
+		}
+		sort.Strings(paths)
+		for _, p := range paths {
+			fmt.Fprintln(w, p)
+		}
+		return nil
+	}
+
 	// Build tree structure
 	type node struct {
 		name     string
 		children map[string]*node
 		isDoc    bool
 		deleted  bool
+		count    int // Documents beneath this node
 	}
 
 	root := &node{children: make(map[string]*node)}
@@ -151,6 +264,9 @@ func Tree(w io.Writer, docs []store.Document) error {
 				}
 			}
 			current = current.children[part]
+			if i < len(parts)-1 {
+				current.count++
+			}
 			if i == len(parts)-1 {
 				current.isDoc = true
 				current.deleted = doc.DeletedAt != nil
@@ -163,7 +279,10 @@ func Tree(w io.Writer, docs []store.Document) error {
 	printNode = func(n *node, prefix string) {
 		// Get sorted children
 		names := make([]string, 0, len(n.children))
-		for name := range n.children {
+		for name, child := range n.children {
+			if mode == TreeDirs && child.count == 0 {
+				continue
+			}
 			names = append(names, name)
 		}
 		sort.Strings(names)
@@ -181,7 +300,11 @@ func Tree(w io.Writer, docs []store.Document) error {
 			if !child.isDoc && len(child.children) > 0 {
 				suffix = "/"
 			}
-			if child.deleted {
+			if mode == TreeDirs {
+				// A path can be both a document and a directory; here it is
+				// shown as the directory
+				suffix = fmt.Sprintf("/ (%d)", child.count)
+			} else if child.deleted {
 				suffix += " [deleted]"
 			}
 
@@ -223,6 +346,75 @@ func History(w io.Writer, docs []store.Document) error {
 	return nil
 }
 
+// HistoryDelimited prints version history as CSV or TSV. See Delimited.
+func HistoryDelimited(w io.Writer, docs []store.Document, comma rune) error {
+	metas := make([]store.DocumentMeta, len(docs))
+	for i, doc := range docs {
+		metas[i] = store.DocumentMeta{
+			Key:       doc.Key,
+			Path:      doc.Path,
+			Version:   doc.Version,
+			Author:    doc.Author,
+			CreatedAt: doc.CreatedAt,
+			Size:      int64(len(doc.Content)),
+		}
+	}
+	return Delimited(w, metas, comma)
+}
+
+// Audit prints a change ledger, one version per line.
+func Audit(w io.Writer, entries []store.AuditEntry) error {
+	for _, e := range entries {
+		msg := "-"
+		if e.Message != "" {
+			msg = fmt.Sprintf("%q", e.Message)
+		}
+		deleted := ""
+		if e.Deleted {
+			deleted = "  (deleted)"
+		}
+		fmt.Fprintf(w, "%s  %s  %s v%d  %-16s  %6s  %s%s\n",
+			time.Unix(e.CreatedAt, 0).Format("2006-01-02 15:04"),
+			e.Key,
+			e.Path,
+			e.Version,
+			e.Author,
+			humanSize(e.Size),
+			msg,
+			deleted,
+		)
+	}
+	return nil
+}
+
+// AuditDelimited prints a change ledger as CSV or TSV with a header row.
+// See Delimited.
+func AuditDelimited(w io.Writer, entries []store.AuditEntry, comma rune) error {
+	cw := csv.NewWriter(w)
+	cw.Comma = comma
+
+	if err := cw.Write([]string{"created_at", "path", "version", "key", "author", "message", "size", "deleted"}); err != nil {
+		return err
+	}
+	for _, e := range entries {
+		row := []string{
+			time.Unix(e.CreatedAt, 0).UTC().Format(time.RFC3339),
+			e.Path,
+			strconv.Itoa(e.Version),
+			e.Key,
+			e.Author,
+			e.Message,
+			strconv.FormatInt(e.Size, 10),
+			strconv.FormatBool(e.Deleted),
+		}
+		if err := cw.Write(row); err != nil {
+			return err
+		}
+	}
+	cw.Flush()
+	return cw.Error()
+}
+
 // HistoryDiff prints version history with diffs between versions.
 func HistoryDiff(w io.Writer, docs []store.Document, colour bool) error {
 	// Docs are in descending order (newest first)
@@ -250,13 +442,15 @@ func HistoryDiff(w io.Writer, docs []store.Document, colour bool) error {
 	return nil
 }
 
-// SearchResults prints search results with matching lines.
+// SearchResults prints search results with matching lines. Lines are
+// compared with case and accents folded, as the search index does, so a
+// query for "cafe" shows the lines mentioning "Café".
 func SearchResults(w io.Writer, docs []store.Document, query string) error {
-	qLower := strings.ToLower(strings.TrimSuffix(query, "*"))
+	qFold := fold(strings.TrimSuffix(query, "*"))
 	for _, doc := range docs {
 		lines := strings.Split(doc.Content, "\n")
 		for i, line := range lines {
-			if strings.Contains(strings.ToLower(line), qLower) {
+			if strings.Contains(fold(line), qFold) {
 				display := line
 				if len(display) > 80 {
 					display = display[:77] + "..."
@@ -268,6 +462,16 @@ func SearchResults(w io.Writer, docs []store.Document, query string) error {
 	return nil
 }
 
+// fold lowercases s and strips combining marks after decomposing it, so
+// "Việt" and "viet" compare equal.
+func fold(s string) string {
+	t := transform.Chain(norm.NFD, runes.Remove(runes.In(unicode.Mn)), norm.NFC)
+	if r, _, err := transform.String(t, s); err == nil {
+		s = r
+	}
+	return strings.ToLower(s)
+}
+
 // Paths prints just document paths, one per line.
 func Paths(w io.Writer, docs []store.Document) error {
 	for _, doc := range docs {
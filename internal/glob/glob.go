@@ -68,3 +68,9 @@ func Match(pattern, path string) (bool, error) {
 	matched, err = filepath.Match(pattern, filepath.Base(path))
 	return matched, err
 }
+
+// IsPattern reports whether s contains glob metacharacters, so callers that
+// accept either a path or a pattern know which they were given.
+func IsPattern(s string) bool {
+	return strings.ContainsAny(s, "*?[")
+}
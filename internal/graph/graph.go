@@ -0,0 +1,207 @@
+// Package graph renders the document link graph for visualisation.
+//
+// Documents are nodes and links are edges, labelled with their tag. The
+// output is text for an external renderer: Graphviz DOT (dot -Tsvg) or a
+// Mermaid flowchart, which GitHub and most markdown viewers draw inline.
+package graph
+
+import (
+	"context"
+	"fmt"
+	"io"
+	"slices"
+	"strings"
+
+	"github.com/jpl-au/llmd/internal/service"
+	"github.com/jpl-au/llmd/internal/store"
+)
+
+// Output formats.
+const (
+	FormatDOT     = "dot"
+	FormatMermaid = "mermaid"
+)
+
+// Formats lists the supported output formats.
+var Formats = []string{FormatDOT, FormatMermaid}
+
+// Options configures a graph export.
+type Options struct {
+	Format  string // FormatDOT (default) or FormatMermaid
+	Tag     string // Only include links with this tag (empty = all links)
+	Orphans bool   // Include documents that have no links
+}
+
+// Edge is one link in the graph.
+type Edge struct {
+	ID   string `json:"id"`
+	From string `json:"from"`
+	To   string `json:"to"`
+	Tag  string `json:"tag,omitempty"`
+}
+
+// Result contains the graph that was rendered.
+type Result struct {
+	Nodes []string `json:"nodes"`
+	Edges []Edge   `json:"edges"`
+}
+
+// Run loads the link graph and writes it to w in opts.Format.
+func Run(ctx context.Context, w io.Writer, svc service.Service, opts Options) (Result, error) {
+	result := Result{Nodes: []string{}, Edges: []Edge{}}
+	if opts.Format == "" {
+		opts.Format = FormatDOT
+	}
+	if !slices.Contains(Formats, opts.Format) {
+		return result, fmt.Errorf("unknown graph format %q (valid: %s)", opts.Format, strings.Join(Formats, ", "))
+	}
+
+	var links []store.Link
+	var err error
+	if opts.Tag != "" {
+		links, err = svc.ListLinksByTag(ctx, opts.Tag, store.NewLinkOptions())
+	} else {
+		links, err = svc.ListAllLinks(ctx, store.NewLinkOptions())
+	}
+	if err != nil {
+		return result, err
+	}
+
+	for _, l := range links {
+		result.Edges = append(result.Edges, Edge{ID: l.ID, From: l.FromPath, To: l.ToPath, Tag: l.Tag})
+		result.Nodes = append(result.Nodes, l.FromPath, l.ToPath)
+	}
+	if opts.Orphans {
+		orphans, err := svc.ListOrphanLinkPaths(ctx, store.NewLinkOptions())
+		if err != nil {
+			return result, err
+		}
+		result.Nodes = append(result.Nodes, orphans...)
+	}
+	slices.Sort(result.Nodes)
+	result.Nodes = slices.Compact(result.Nodes)
+
+	switch opts.Format {
+	case FormatMermaid:
+		writeMermaid(w, result)
+	default:
+		writeDOT(w, result)
+	}
+	return result, nil
+}
+
+// ReachOptions configures a reachability query.
+type ReachOptions struct {
+	Depth int    // Maximum hops to follow (0 = unlimited)
+	Tag   string // Only follow links with this tag (empty = all links)
+
+	// Direction is store.DirectionOut to follow links forwards only (what
+	// this document depends on), store.DirectionIn to follow them backwards
+	// (what depends on it), or empty for both.
+	Direction string
+}
+
+// Reached is a document found by Reachable.
+type Reached struct {
+	Path     string `json:"path"`
+	Distance int    `json:"distance"` // Hops from the starting document
+}
+
+// ReachResult contains the documents reachable from Path.
+type ReachResult struct {
+	Path    string    `json:"path"`
+	Reached []Reached `json:"reached"`
+}
+
+// Reachable finds every document connected to start through links, directly
+// or indirectly, up to opts.Depth hops. Links are followed in both
+// directions unless opts.Direction says otherwise. The search is breadth-first, so each
+// document is reported once at its shortest distance and cycles end the walk
+// rather than looping. Results are ordered by distance, then path.
+func Reachable(ctx context.Context, w io.Writer, svc service.Service, start string, opts ReachOptions) (ReachResult, error) {
+	result := ReachResult{Path: start, Reached: []Reached{}}
+	if opts.Depth < 0 {
+		return result, fmt.Errorf("depth must be >= 0, got %d", opts.Depth)
+	}
+
+	doc, _, err := svc.Resolve(ctx, start, false)
+	if err != nil {
+		return result, err
+	}
+	result.Path = doc.Path
+
+	seen := map[string]bool{doc.Path: true}
+	frontier := []string{doc.Path}
+	for dist := 1; len(frontier) > 0 && (opts.Depth == 0 || dist <= opts.Depth); dist++ {
+		var next []string
+		for _, p := range frontier {
+			links, err := svc.ListLinks(ctx, p, opts.Tag, store.NewLinkOptions().WithDirection(opts.Direction))
+			if err != nil {
+				return result, err
+			}
+			for _, l := range links {
+				other := l.ToPath
+				if other == p {
+					other = l.FromPath
+				}
+				if !seen[other] {
+					seen[other] = true
+					next = append(next, other)
+				}
+			}
+		}
+		slices.Sort(next)
+		for _, p := range next {
+			result.Reached = append(result.Reached, Reached{Path: p, Distance: dist})
+			fmt.Fprintf(w, "%d  %s\n", dist, p)
+		}
+		frontier = next
+	}
+	return result, nil
+}
+
+// writeDOT writes g as a Graphviz digraph. Every node is declared so that
+// orphans appear even though no edge mentions them.
+func writeDOT(w io.Writer, g Result) {
+	fmt.Fprintln(w, "digraph links {")
+	fmt.Fprintln(w, "  node [shape=box];")
+	for _, n := range g.Nodes {
+		fmt.Fprintf(w, "  %s;\n", dotQuote(n))
+	}
+	for _, e := range g.Edges {
+		if e.Tag != "" {
+			fmt.Fprintf(w, "  %s -> %s [label=%s];\n", dotQuote(e.From), dotQuote(e.To), dotQuote(e.Tag))
+		} else {
+			fmt.Fprintf(w, "  %s -> %s;\n", dotQuote(e.From), dotQuote(e.To))
+		}
+	}
+	fmt.Fprintln(w, "}")
+}
+
+// writeMermaid writes g as a Mermaid flowchart. Mermaid node IDs cannot
+// contain "/", so nodes get positional IDs with the path as their label.
+func writeMermaid(w io.Writer, g Result) {
+	ids := make(map[string]string, len(g.Nodes))
+	fmt.Fprintln(w, "graph LR")
+	for i, n := range g.Nodes {
+		ids[n] = fmt.Sprintf("n%d", i)
+		fmt.Fprintf(w, "  %s[\"%s\"]\n", ids[n], mermaidEscape(n))
+	}
+	for _, e := range g.Edges {
+		if e.Tag != "" {
+			fmt.Fprintf(w, "  %s -->|\"%s\"| %s\n", ids[e.From], mermaidEscape(e.Tag), ids[e.To])
+		} else {
+			fmt.Fprintf(w, "  %s --> %s\n", ids[e.From], ids[e.To])
+		}
+	}
+}
+
+// dotQuote returns s as a DOT quoted string.
+func dotQuote(s string) string {
+	return `"` + strings.NewReplacer(`\`, `\\`, `"`, `\"`).Replace(s) + `"`
+}
+
+// mermaidEscape makes s safe inside a quoted Mermaid label.
+func mermaidEscape(s string) string {
+	return strings.ReplaceAll(s, `"`, "#quot;")
+}
@@ -7,12 +7,18 @@ package grep
 
 import (
 	"bufio"
+	"bytes"
 	"context"
+	"errors"
 	"fmt"
 	"io"
 	"regexp"
+	"slices"
 	"strings"
+	"time"
 
+	"github.com/jpl-au/llmd/internal/glob"
+	"github.com/jpl-au/llmd/internal/log"
 	"github.com/jpl-au/llmd/internal/path"
 	"github.com/jpl-au/llmd/internal/service"
 	"github.com/jpl-au/llmd/internal/store"
@@ -20,7 +26,7 @@ import (
 
 // Options configures a grep operation.
 type Options struct {
-	Path        string // Scope search to path prefix
+	Path        string // Scope search to path prefix, or glob pattern if it has metacharacters
 	Recursive   bool   // Search subdirectories (-r flag)
 	IncludeAll  bool   // Include deleted documents
 	DeletedOnly bool   // Search only deleted documents
@@ -42,15 +48,61 @@ type Options struct {
 	// deciding whether to dive deeper.
 	CountOnly bool // Only show count of matches (-c flag)
 
+	// ByteOffset prefixes each output line with the byte offset of the line
+	// within the document, or with OnlyMatching the offset of the match.
+	// Lets tooling locate a match exactly without re-scanning the content.
+	ByteOffset bool // Show byte offsets (-b flag)
+
+	// OnlyMatching prints each matched substring on its own line instead of
+	// the whole line. Cannot be combined with Invert or Context.
+	OnlyMatching bool // Print only the matched parts (--only-matching flag)
+
+	// Multiline runs the pattern against the whole document with (?s), so
+	// "." also matches newlines and a match can span lines, such as a fenced
+	// code block. Each match reports the lines it spans. Cannot be combined
+	// with Invert.
+	Multiline bool // Match across lines (--multiline flag)
+
+	// Matching only identifies the matching documents: each document stops
+	// at its first matching line, Hits is left empty and nothing is written.
+	// Backs --count-only, where only the number of documents is wanted.
+	Matching bool
+
+	// AsOf searches documents as they were at this time (zero = now).
+	// Cannot be combined with IncludeAll or DeletedOnly.
+	AsOf time.Time
+
 	// MaxLineLength is the maximum line length for scanning (0 = default 10MB).
 	// Needed for documents with very long lines (minified JS, large JSON).
 	MaxLineLength int
+
+	// fullScan disables the literal prefilter, for comparing against it
+	fullScan bool
 }
 
 // Match represents a single line match within a document.
 type Match struct {
 	Line    int    // 1-indexed line number
-	Content string // The matching line content
+	EndLine int    // Last line of the match; equals Line outside multiline mode
+	Offset  int    // Byte offset of the line within the document
+	Content string // The matching line content (all spanned lines when multiline)
+	Parts   []Part // Matched substrings; set with ByteOffset or OnlyMatching
+}
+
+// Part is one substring of a line matched by the pattern.
+type Part struct {
+	Offset int    // Byte offset of the match within the document
+	Text   string // The matched text
+}
+
+// Span is a located match as reported in JSON by --byte-offset and
+// --only-matching: one entry per matched substring.
+type Span struct {
+	Path    string `json:"path"`
+	Line    int    `json:"line"`
+	EndLine int    `json:"end_line"`
+	Offset  int    `json:"offset"`
+	Match   string `json:"match"`
 }
 
 // DocMatch represents all matches within a single document.
@@ -65,42 +117,88 @@ type Result struct {
 	Hits      []DocMatch       // Detailed match info with line numbers
 }
 
+// Spans flattens the matched substrings of every hit, in document order.
+// Empty unless the search ran with ByteOffset or OnlyMatching.
+func (r Result) Spans() []Span {
+	spans := []Span{}
+	for _, hit := range r.Hits {
+		for _, m := range hit.Matches {
+			for _, p := range m.Parts {
+				spans = append(spans, Span{
+					Path:    hit.Document.Path,
+					Line:    m.Line,
+					EndLine: m.EndLine,
+					Offset:  p.Offset,
+					Match:   p.Text,
+				})
+			}
+		}
+	}
+	return spans
+}
+
 // Run searches documents for a regex pattern and writes output to w.
 func Run(ctx context.Context, w io.Writer, svc service.Service, pattern string, opts Options) (Result, error) {
 	var result Result
 
-	// Compile regex
-	flags := ""
-	if opts.IgnoreCase {
-		flags = "(?i)"
+	if !opts.AsOf.IsZero() && (opts.IncludeAll || opts.DeletedOnly) {
+		return result, errors.New("--as-of cannot be combined with deleted documents")
 	}
-	re, err := regexp.Compile(flags + pattern)
+	if opts.OnlyMatching && opts.Invert {
+		return result, errors.New("--only-matching cannot be combined with -v")
+	}
+	if opts.OnlyMatching && opts.Context > 0 {
+		return result, errors.New("--only-matching cannot be combined with -C")
+	}
+	if opts.Multiline && opts.Invert {
+		return result, errors.New("--multiline cannot be combined with -v")
+	}
+
+	re, err := compile(pattern, opts)
 	if err != nil {
-		return result, fmt.Errorf("invalid regex: %w", err)
+		return result, err
+	}
+
+	// Every match of a pattern like "TODO: .*" contains "TODO: ", so only
+	// documents containing it are loaded. Inverted matches must see every
+	// document.
+	var lit string
+	var fold bool
+	if !opts.Invert && !opts.fullScan {
+		lit, fold = requiredLiteral(re.String())
 	}
 
-	// Get all documents (filtered by path prefix)
-	docs, err := svc.List(ctx, opts.Path, opts.IncludeAll, opts.DeletedOnly)
+	docs, err := candidates(ctx, svc, lit, fold, opts)
 	if err != nil {
 		return result, err
 	}
+	// With --verbose, time matching separately from loading the candidates
+	defer log.Debug("search:grep", "match").Detail("documents", len(docs)).Write(nil)
 
-	// Filter to direct children only when not recursive
-	if !opts.Recursive {
-		var filtered []store.Document
-		for _, d := range docs {
-			if path.Direct(d.Path, opts.Path) {
-				filtered = append(filtered, d)
+	if opts.Matching {
+		for _, doc := range docs {
+			if opts.Multiline {
+				if re.MatchString(doc.Content) {
+					result.Documents = append(result.Documents, doc)
+				}
+				continue
+			}
+			found, err := anyLine(re, doc.Content, opts.Invert, opts.MaxLineLength)
+			if err != nil {
+				return result, fmt.Errorf("scanning %s: %w", doc.Path, err)
+			}
+			if found {
+				result.Documents = append(result.Documents, doc)
 			}
 		}
-		docs = filtered
+		return result, nil
 	}
 
 	// Match each document
 	for _, doc := range docs {
-		matches, err := matchLines(re, doc.Content, opts.Invert, opts.MaxLineLength)
+		matches, err := match(re, doc, opts)
 		if err != nil {
-			return result, fmt.Errorf("scanning %s: %w", doc.Path, err)
+			return result, err
 		}
 		if len(matches) > 0 {
 			result.Documents = append(result.Documents, doc)
@@ -118,53 +216,59 @@ func Run(ctx context.Context, w io.Writer, svc service.Service, pattern string,
 		}
 	} else if opts.CountOnly {
 		for _, hit := range result.Hits {
-			fmt.Fprintf(w, "%s:%d\n", hit.Document.Path, len(hit.Matches))
+			fmt.Fprintf(w, "%s:%d\n", hit.Document.Path, spannedLines(hit.Matches))
+		}
+	} else if opts.OnlyMatching {
+		for _, hit := range result.Hits {
+			for _, m := range hit.Matches {
+				for _, p := range m.Parts {
+					if opts.ByteOffset {
+						fmt.Fprintf(w, "%s:%d:%d:%s\n", hit.Document.Path, m.Line, p.Offset, p.Text)
+					} else {
+						fmt.Fprintf(w, "%s:%d:%s\n", hit.Document.Path, m.Line, p.Text)
+					}
+				}
+			}
 		}
-	} else if opts.Context > 0 {
+	} else if opts.Context > 0 || opts.Multiline {
 		// Context output follows grep convention:
 		// - ":" separates path:line:content for matching lines
 		// - "-" separates path-line-content for context lines
 		// - "--" separates non-contiguous match groups
 		// This allows LLMs to distinguish matches from context at a glance.
+		// Multiline matches print every line they span as matching lines,
+		// with "--" between matches that are not adjacent.
 		for _, hit := range result.Hits {
-			lines := strings.Split(hit.Document.Content, "\n")
-			printed := make(map[int]bool) // track printed lines to avoid duplicates when matches overlap
-			needSep := false
-
-			for _, m := range hit.Matches {
-				start := m.Line - opts.Context - 1 // convert to 0-indexed
-				if start < 0 {
-					start = 0
-				}
-				end := m.Line + opts.Context // exclusive upper bound for 0-indexed loop
-				if end > len(lines) {
-					end = len(lines)
-				}
-
-				// Print separator if there's a gap from previous context
-				if needSep && !printed[start] {
+			var offsets []int
+			if opts.ByteOffset {
+				offsets = lineOffsets(hit.Document.Content)
+			}
+			for i, ex := range Excerpts(hit.Document.Content, hit.Matches, opts.Context) {
+				if i > 0 {
 					fmt.Fprintln(w, "--")
 				}
-
-				for i := start; i < end; i++ {
-					if printed[i] {
-						continue
-					}
-					printed[i] = true
-					lineNum := i + 1
+				for j, line := range ex.Lines {
+					n := ex.Start + j
 					sep := "-" // context line
-					if lineNum == m.Line {
+					if slices.Contains(ex.Matched, n) {
 						sep = ":" // matching line
 					}
-					fmt.Fprintf(w, "%s%s%d%s%s\n", hit.Document.Path, sep, lineNum, sep, lines[i])
+					if opts.ByteOffset {
+						fmt.Fprintf(w, "%s%s%d%s%d%s%s\n", hit.Document.Path, sep, n, sep, offsets[n-1], sep, line)
+					} else {
+						fmt.Fprintf(w, "%s%s%d%s%s\n", hit.Document.Path, sep, n, sep, line)
+					}
 				}
-				needSep = true
 			}
 		}
 	} else {
 		for _, hit := range result.Hits {
 			for _, m := range hit.Matches {
-				fmt.Fprintf(w, "%s:%d:%s\n", hit.Document.Path, m.Line, m.Content)
+				if opts.ByteOffset {
+					fmt.Fprintf(w, "%s:%d:%d:%s\n", hit.Document.Path, m.Line, m.Offset, m.Content)
+				} else {
+					fmt.Fprintf(w, "%s:%d:%s\n", hit.Document.Path, m.Line, m.Content)
+				}
 			}
 		}
 	}
@@ -172,30 +276,271 @@ func Run(ctx context.Context, w io.Writer, svc service.Service, pattern string,
 	return result, nil
 }
 
+// Document searches a single document for pattern, as Run searches each
+// of the documents it lists. The options that select documents (Path,
+// Recursive, IncludeAll, DeletedOnly, AsOf) and those that only shape Run's
+// output are ignored.
+func Document(doc store.Document, pattern string, opts Options) ([]Match, error) {
+	if opts.Multiline && opts.Invert {
+		return nil, errors.New("multiline cannot be combined with invert")
+	}
+	re, err := compile(pattern, opts)
+	if err != nil {
+		return nil, err
+	}
+	return match(re, doc, opts)
+}
+
+// Excerpt is a run of consecutive lines holding one or more matches and
+// their context: what -C prints between "--" separators.
+type Excerpt struct {
+	Start   int      `json:"start"`   // Line number of the first line, 1-indexed
+	Lines   []string `json:"lines"`   // The lines, in order
+	Matched []int    `json:"matched"` // Line numbers within the excerpt that matched
+}
+
+// Excerpts groups matches, which are in document order, into excerpts of
+// content with context lines either side of each. Matches whose context
+// overlaps share an excerpt.
+func Excerpts(content string, matches []Match, context int) []Excerpt {
+	lines := strings.Split(content, "\n")
+	var excerpts []Excerpt
+	end := 0 // 0-indexed, exclusive end of the last excerpt
+	for _, m := range matches {
+		start := max(m.Line-context-1, 0)
+		stop := min(m.EndLine+context, len(lines))
+		if len(excerpts) == 0 || start >= end {
+			excerpts = append(excerpts, Excerpt{Start: start + 1})
+			end = start
+		}
+		ex := &excerpts[len(excerpts)-1]
+		if stop > end {
+			ex.Lines = append(ex.Lines, lines[end:stop]...)
+			end = stop
+		}
+		for n := m.Line; n <= m.EndLine; n++ {
+			if k := len(ex.Matched); k == 0 || ex.Matched[k-1] < n {
+				ex.Matched = append(ex.Matched, n)
+			}
+		}
+	}
+	return excerpts
+}
+
+// compile builds the regex for pattern with the flags opts implies.
+func compile(pattern string, opts Options) (*regexp.Regexp, error) {
+	flags := ""
+	if opts.IgnoreCase {
+		flags = "(?i)"
+	}
+	if opts.Multiline {
+		flags += "(?s)"
+	}
+	re, err := regexp.Compile(flags + pattern)
+	if err != nil {
+		return nil, fmt.Errorf("invalid regex: %w", err)
+	}
+	return re, nil
+}
+
+// match finds the matches of re in doc.
+func match(re *regexp.Regexp, doc store.Document, opts Options) ([]Match, error) {
+	if opts.Multiline {
+		return matchContent(re, doc.Content), nil
+	}
+	parts := (opts.ByteOffset || opts.OnlyMatching) && !opts.Invert
+	matches, err := matchLines(re, doc.Content, opts.Invert, parts, opts.MaxLineLength)
+	if err != nil {
+		return nil, fmt.Errorf("scanning %s: %w", doc.Path, err)
+	}
+	return matches, nil
+}
+
+// lineOffsets returns the byte offset of each line of content.
+func lineOffsets(content string) []int {
+	offsets := []int{0}
+	for i := range len(content) {
+		if content[i] == '\n' {
+			offsets = append(offsets, i+1)
+		}
+	}
+	return offsets
+}
+
+// candidates lists the documents opts.Path scopes the search to. A plain
+// path is a directory prefix: the store matches it as a string, so keep only
+// documents under it as a directory, and only direct children unless
+// recursive. A path with glob metacharacters ("docs/**/api/*") is matched
+// against each document path as the glob command does, listing only under
+// its literal leading directories; Recursive has no effect there.
+//
+// A non-empty lit further limits the listing to documents containing it
+// (folding case with fold), except with AsOf, where the full snapshot is
+// listed.
+func candidates(ctx context.Context, svc service.Service, lit string, fold bool, opts Options) ([]store.Document, error) {
+	pattern := glob.IsPattern(opts.Path)
+	prefix := opts.Path
+	if pattern {
+		prefix = globPrefix(opts.Path)
+	}
+
+	var docs []store.Document
+	var err error
+	switch {
+	case !opts.AsOf.IsZero():
+		docs, err = svc.ListAsOf(ctx, prefix, opts.AsOf)
+	case lit != "":
+		docs, err = svc.ListContaining(ctx, prefix, lit, fold, opts.IncludeAll, opts.DeletedOnly)
+	default:
+		docs, err = svc.List(ctx, prefix, opts.IncludeAll, opts.DeletedOnly)
+	}
+	if err != nil {
+		return nil, err
+	}
+
+	var filtered []store.Document
+	for _, d := range docs {
+		if !pattern {
+			if path.InScope(d.Path, opts.Path, opts.Recursive) {
+				filtered = append(filtered, d)
+			}
+			continue
+		}
+		ok, err := glob.Match(opts.Path, d.Path)
+		if err != nil {
+			return nil, fmt.Errorf("invalid pattern %q: %w", opts.Path, err)
+		}
+		if ok {
+			filtered = append(filtered, d)
+		}
+	}
+	return filtered, nil
+}
+
+// globPrefix returns the directories of pattern before its first
+// metacharacter ("docs/" for "docs/**/api/*"), or "" if the first segment
+// is already a pattern. Every path the pattern matches lies under it.
+func globPrefix(pattern string) string {
+	i := strings.IndexAny(pattern, "*?[")
+	return pattern[:strings.LastIndexByte(pattern[:i], '/')+1]
+}
+
+// anyLine reports whether any line matches the regex (or, with invert, fails
+// to match), stopping at the first one.
+func anyLine(re *regexp.Regexp, content string, invert bool, maxLineLength int) (bool, error) {
+	if maxLineLength <= 0 {
+		maxLineLength = 10 * 1024 * 1024 // 10MB default
+	}
+	scanner := bufio.NewScanner(strings.NewReader(content))
+	scanner.Buffer(make([]byte, 64*1024), maxLineLength)
+	for scanner.Scan() {
+		if re.MatchString(scanner.Text()) != invert {
+			return true, nil
+		}
+	}
+	return false, scanner.Err()
+}
+
 // matchLines finds all lines matching the regex and returns Match structs.
-// If invert is true, returns lines that do NOT match.
+// If invert is true, returns lines that do NOT match. With parts, each match
+// also records the non-empty substrings the regex matched and their offsets.
 // Uses bufio.Scanner for memory efficiency - avoids allocating a slice of all
 // lines upfront. Important when searching many large documents where most won't match.
-func matchLines(re *regexp.Regexp, content string, invert bool, maxLineLength int) ([]Match, error) {
+func matchLines(re *regexp.Regexp, content string, invert, parts bool, maxLineLength int) ([]Match, error) {
 	var matches []Match
 	if maxLineLength <= 0 {
 		maxLineLength = 10 * 1024 * 1024 // 10MB default
 	}
 	scanner := bufio.NewScanner(strings.NewReader(content))
 	scanner.Buffer(make([]byte, 64*1024), maxLineLength)
+	scanner.Split(scanRawLines)
 	lineNum := 0
+	offset := 0
 	for scanner.Scan() {
 		lineNum++
-		line := scanner.Text()
+		raw := scanner.Text()
+		line := strings.TrimSuffix(raw, "\r")
 		if re.MatchString(line) != invert {
-			matches = append(matches, Match{
+			m := Match{
 				Line:    lineNum,
+				Offset:  offset,
 				Content: line,
-			})
+			}
+			if parts {
+				for _, loc := range re.FindAllStringIndex(line, -1) {
+					if loc[0] == loc[1] {
+						continue
+					}
+					m.Parts = append(m.Parts, Part{Offset: offset + loc[0], Text: line[loc[0]:loc[1]]})
+				}
+			}
+			m.EndLine = m.Line
+			matches = append(matches, m)
 		}
+		offset += len(raw) + 1
 	}
 	if err := scanner.Err(); err != nil {
 		return matches, err
 	}
 	return matches, nil
 }
+
+// matchContent finds every non-empty match of the regex in the whole
+// content, for multiline mode. Each match records the lines it spans, with
+// Content holding those lines in full and Parts the matched text itself.
+func matchContent(re *regexp.Regexp, content string) []Match {
+	var matches []Match
+	line, pos := 1, 0 // line number of content[pos]
+	for _, loc := range re.FindAllStringIndex(content, -1) {
+		if loc[0] == loc[1] {
+			continue
+		}
+		line += strings.Count(content[pos:loc[0]], "\n")
+		pos = loc[0]
+		// A match ending in a newline ends on the line that newline closes
+		endLine := line + strings.Count(content[loc[0]:loc[1]-1], "\n")
+
+		start := strings.LastIndexByte(content[:loc[0]], '\n') + 1
+		end := len(content)
+		if i := strings.IndexByte(content[loc[1]-1:], '\n'); i >= 0 {
+			end = loc[1] - 1 + i
+		}
+		matches = append(matches, Match{
+			Line:    line,
+			EndLine: endLine,
+			Offset:  start,
+			Content: content[start:end],
+			Parts:   []Part{{Offset: loc[0], Text: content[loc[0]:loc[1]]}},
+		})
+	}
+	return matches
+}
+
+// spannedLines counts the distinct lines covered by matches, which are in
+// document order. Outside multiline mode this is simply the number of matches.
+func spannedLines(matches []Match) int {
+	n, last := 0, 0
+	for _, m := range matches {
+		from := max(m.Line, last+1)
+		if m.EndLine >= from {
+			n += m.EndLine - from + 1
+			last = m.EndLine
+		}
+	}
+	return n
+}
+
+// scanRawLines is bufio.ScanLines without dropping a trailing '\r', so the
+// byte length of each line (and with it every offset) matches the content.
+func scanRawLines(data []byte, atEOF bool) (int, []byte, error) {
+	if atEOF && len(data) == 0 {
+		return 0, nil, nil
+	}
+	if i := bytes.IndexByte(data, '\n'); i >= 0 {
+		return i + 1, data[:i], nil
+	}
+	if atEOF {
+		return len(data), data, nil
+	}
+	return 0, nil, nil
+}
@@ -0,0 +1,56 @@
+package grep
+
+import (
+	"testing"
+
+	"github.com/jpl-au/llmd/internal/store"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestExcerpts(t *testing.T) {
+	content := "a\nmatch 1\nb\nc\nmatch 2\nmatch 3\nd\ne\nf\ng\nmatch 4"
+	doc := store.Document{Path: "docs/a", Content: content}
+	matches, err := Document(doc, "match", Options{})
+	require.NoError(t, err)
+	require.Len(t, matches, 4)
+
+	assert.Equal(t, []Excerpt{
+		{Start: 1, Lines: []string{"a", "match 1", "b", "c", "match 2", "match 3", "d", "e"}, Matched: []int{2, 5, 6}},
+		{Start: 9, Lines: []string{"f", "g", "match 4"}, Matched: []int{11}},
+	}, Excerpts(content, matches, 2))
+
+	assert.Equal(t, []Excerpt{
+		{Start: 2, Lines: []string{"match 1"}, Matched: []int{2}},
+		{Start: 5, Lines: []string{"match 2"}, Matched: []int{5}},
+		{Start: 6, Lines: []string{"match 3"}, Matched: []int{6}},
+		{Start: 11, Lines: []string{"match 4"}, Matched: []int{11}},
+	}, Excerpts(content, matches, 0), "adjacent matches without context stay separate, as -C 0 prints them")
+
+	assert.Empty(t, Excerpts(content, nil, 2))
+}
+
+func TestDocument(t *testing.T) {
+	doc := store.Document{Path: "docs/a", Content: "# Title\nSome text\n```go\nfunc main() {}\n```\n"}
+
+	matches, err := Document(doc, "^#", Options{})
+	require.NoError(t, err)
+	require.Len(t, matches, 1)
+	assert.Equal(t, 1, matches[0].Line)
+
+	matches, err = Document(doc, "TEXT", Options{IgnoreCase: true})
+	require.NoError(t, err)
+	require.Len(t, matches, 1)
+	assert.Equal(t, "Some text", matches[0].Content)
+
+	matches, err = Document(doc, "```go.*?```", Options{Multiline: true})
+	require.NoError(t, err)
+	require.Len(t, matches, 1)
+	assert.Equal(t, 3, matches[0].Line)
+	assert.Equal(t, 5, matches[0].EndLine)
+
+	_, err = Document(doc, "(", Options{})
+	assert.Error(t, err)
+	_, err = Document(doc, "x", Options{Multiline: true, Invert: true})
+	assert.Error(t, err)
+}
@@ -0,0 +1,73 @@
+package grep
+
+import (
+	"regexp/syntax"
+	"unicode"
+	"unicode/utf8"
+)
+
+// requiredLiteral returns a substring that every match of expr must
+// contain, and whether it matches case-insensitively, or "" if expr has no
+// such literal ([0-9]{3}, foo|bar). A document without the literal cannot
+// match, so grep asks the store only for documents containing it.
+//
+// The literal is only returned when the store's comparison agrees with the
+// regexp's: a case-folded literal must fold the way SQLite's LIKE does,
+// which rules out letters such as 'k', whose fold set includes the Kelvin
+// sign. Anything doubtful yields "" and a full scan, so the prefilter never
+// drops a document the regexp would match.
+func requiredLiteral(expr string) (string, bool) {
+	re, err := syntax.Parse(expr, syntax.Perl)
+	if err != nil {
+		return "", false
+	}
+	lit, fold := literal(re.Simplify())
+	if fold && !likeFolds(lit) {
+		return "", false
+	}
+	for _, r := range lit {
+		// The regexp matches invalid UTF-8 as U+FFFD; the store compares bytes
+		if r == utf8.RuneError {
+			return "", false
+		}
+	}
+	return lit, fold
+}
+
+// literal returns the longest literal that every match of re contains.
+func literal(re *syntax.Regexp) (string, bool) {
+	switch re.Op {
+	case syntax.OpLiteral:
+		return string(re.Rune), re.Flags&syntax.FoldCase != 0
+	case syntax.OpCapture, syntax.OpPlus:
+		return literal(re.Sub[0])
+	case syntax.OpRepeat:
+		if re.Min > 0 {
+			return literal(re.Sub[0])
+		}
+	case syntax.OpConcat:
+		var best string
+		var fold bool
+		for _, sub := range re.Sub {
+			if lit, f := literal(sub); len(lit) > len(best) {
+				best, fold = lit, f
+			}
+		}
+		return best, fold
+	}
+	return "", false
+}
+
+// likeFolds reports whether SQLite's LIKE, which folds ASCII letters only,
+// matches exactly the characters a case-insensitive regexp would for every
+// rune of s.
+func likeFolds(s string) bool {
+	for _, r := range s {
+		for f := unicode.SimpleFold(r); f != r; f = unicode.SimpleFold(f) {
+			if r >= utf8.RuneSelf || f >= utf8.RuneSelf {
+				return false
+			}
+		}
+	}
+	return true
+}
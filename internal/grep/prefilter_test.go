@@ -0,0 +1,138 @@
+package grep
+
+import (
+	"context"
+	"fmt"
+	"io"
+	"testing"
+
+	"github.com/jpl-au/llmd/internal/document"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestRequiredLiteral(t *testing.T) {
+	tests := []struct {
+		expr string
+		lit  string
+		fold bool
+	}{
+		{"TODO", "TODO", false},
+		{"TODO: .*", "TODO: ", false},
+		{`func \w+\(ctx`, "func ", false},
+		{"(hello)+ world", " world", false},
+		{"(?i)todo", "TODO", true},
+		{"(?i)error", "ERROR", true},
+		{"[0-9]{3}", "", false},
+		{"foo|bar", "", false},
+		{"(foo)?", "", false},
+		{"(foo)*bar", "bar", false},
+		{"(?i)kelvin", "", false}, // 'k' also folds to the Kelvin sign
+		{"(?i)café", "", false},   // LIKE does not fold 'é'
+		{"café", "café", false},
+		{"(", "", false},
+	}
+	for _, tt := range tests {
+		lit, fold := requiredLiteral(tt.expr)
+		assert.Equal(t, tt.lit, lit, tt.expr)
+		assert.Equal(t, tt.fold, fold, tt.expr)
+	}
+}
+
+func TestRun_PrefilterMatchesFullScan(t *testing.T) {
+	svc, err := document.NewMemory()
+	require.NoError(t, err)
+	defer svc.Close()
+	ctx := context.Background()
+
+	docs := map[string]string{
+		"docs/a":     "TODO: first\nnothing here",
+		"docs/b":     "todo: lower case\n100% done",
+		"docs/c":     "Kelvin Kelvin\nline with 123",
+		"docs/d":     "under_score and 50% off",
+		"notes/e":    "café au lait\nCAFÉ",
+		"notes/f":    "no matches at all",
+		"docs/crlf":  "windows TODO\r\nline two\r\n",
+		"docs/multi": "start\nblock\nend",
+	}
+	for p, content := range docs {
+		require.NoError(t, svc.Write(ctx, p, content, "tester", ""))
+	}
+
+	patterns := []struct {
+		expr string
+		opts Options
+	}{
+		{"TODO", Options{Recursive: true}},
+		{"TODO", Options{Recursive: true, IgnoreCase: true}},
+		{"todo: .*", Options{Recursive: true, IgnoreCase: true}},
+		{"kelvin", Options{Recursive: true, IgnoreCase: true}},
+		{"café", Options{Recursive: true, IgnoreCase: true}},
+		{"100%", Options{Recursive: true}},
+		{"under_score", Options{Recursive: true}},
+		{"[0-9]{3}", Options{Recursive: true}},
+		{"TODO", Options{Recursive: true, Invert: true}},
+		{"start.*end", Options{Recursive: true, Multiline: true}},
+		{"TODO", Options{Path: "docs"}},
+		{"TODO", Options{Recursive: true, Matching: true}},
+	}
+	for _, p := range patterns {
+		t.Run(p.expr, func(t *testing.T) {
+			want, err := Run(ctx, io.Discard, svc, p.expr, Options{fullScan: true, Path: p.opts.Path, Recursive: p.opts.Recursive, IgnoreCase: p.opts.IgnoreCase, Invert: p.opts.Invert, Multiline: p.opts.Multiline, Matching: p.opts.Matching})
+			require.NoError(t, err)
+			got, err := Run(ctx, io.Discard, svc, p.expr, p.opts)
+			require.NoError(t, err)
+			assert.Equal(t, paths(want), paths(got))
+			assert.Equal(t, len(want.Hits), len(got.Hits))
+		})
+	}
+}
+
+func paths(r Result) []string {
+	var ps []string
+	for _, d := range r.Documents {
+		ps = append(ps, d.Path)
+	}
+	return ps
+}
+
+// BenchmarkRun compares a recursive grep for a rare literal with and
+// without the prefilter on a store of 2,000 documents, 20 of which match.
+func BenchmarkRun(b *testing.B) {
+	svc, err := document.NewMemory()
+	require.NoError(b, err)
+	defer svc.Close()
+	ctx := context.Background()
+
+	var filler string
+	for i := range 200 {
+		filler += fmt.Sprintf("line %d of ordinary prose about nothing in particular\n", i)
+	}
+	for i := range 2000 {
+		content := filler
+		if i%100 == 0 {
+			content += "FIXME: needle\n"
+		}
+		require.NoError(b, svc.Write(ctx, fmt.Sprintf("docs/%04d", i), content, "bench", ""))
+	}
+
+	for _, bm := range []struct {
+		name string
+		opts Options
+	}{
+		{"prefilter", Options{Recursive: true}},
+		{"scan", Options{Recursive: true, fullScan: true}},
+	} {
+		b.Run(bm.name, func(b *testing.B) {
+			for b.Loop() {
+				res, err := Run(ctx, io.Discard, svc, `FIXME: \w+`, bm.opts)
+				if err != nil {
+					b.Fatal(err)
+				}
+				if len(res.Documents) != 20 {
+					b.Fatalf("got %d documents, want 20", len(res.Documents))
+				}
+			}
+		})
+	}
+}
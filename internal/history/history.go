@@ -9,6 +9,7 @@ import (
 	"context"
 	"fmt"
 	"io"
+	"slices"
 
 	"github.com/jpl-au/llmd/internal/format"
 	"github.com/jpl-au/llmd/internal/service"
@@ -21,6 +22,11 @@ type Options struct {
 	IncludeDeleted bool // Include deleted versions
 	ShowDiff       bool // Show diffs between versions
 	Colour         bool // Colourize diff output
+	Delimiter      rune // CSV/TSV output when non-zero (',' or '\t')
+
+	// Reverse lists oldest first, for reading a document's evolution in
+	// order. It applies after Limit, so -n 5 still selects the newest five.
+	Reverse bool
 }
 
 // Result contains the outcome of a history operation.
@@ -49,11 +55,25 @@ func Run(ctx context.Context, w io.Writer, svc service.Service, path string, opt
 		return result, fmt.Errorf("no history found for %s", path)
 	}
 
+	if opts.Reverse {
+		slices.Reverse(docs)
+	}
 	result.Versions = docs
 
-	if opts.ShowDiff {
+	switch {
+	case opts.Delimiter != 0:
+		err = format.HistoryDelimited(w, docs, opts.Delimiter)
+	case opts.ShowDiff && opts.Reverse:
+		// HistoryDiff expects newest first; feed it one pair at a time so
+		// the diffs still read oldest to newest
+		for i := range len(docs) - 1 {
+			if err = format.HistoryDiff(w, []store.Document{docs[i+1], docs[i]}, opts.Colour); err != nil {
+				break
+			}
+		}
+	case opts.ShowDiff:
 		err = format.HistoryDiff(w, docs, opts.Colour)
-	} else {
+	default:
 		err = format.History(w, docs)
 	}
 
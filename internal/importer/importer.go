@@ -1,16 +1,26 @@
 // Package importer provides utilities for importing markdown files into llmd.
+// Files with other accepted extensions (see Options.Extensions) are stored
+// with the content type their extension implies.
 package importer
 
 import (
 	"context"
+	"crypto/sha256"
+	"errors"
 	"fmt"
 	"io"
 	"os"
 	"path/filepath"
+	"slices"
 	"strings"
 
+	"github.com/jpl-au/llmd/internal/archive"
+	"github.com/jpl-au/llmd/internal/contenttype"
 	"github.com/jpl-au/llmd/internal/progress"
 	"github.com/jpl-au/llmd/internal/service"
+	"github.com/jpl-au/llmd/internal/store"
+	"github.com/jpl-au/llmd/internal/validate"
+	"gopkg.in/yaml.v3"
 )
 
 // Options configures an import operation.
@@ -21,26 +31,61 @@ type Options struct {
 	DryRun bool   // Show what would be imported without importing
 	Author string // Author for imported documents
 	Msg    string // Commit message for imported documents
+
+	// Extensions lists the file extensions to import, each with a leading
+	// dot (e.g. ".mdx"). Matching is case-insensitive. Defaults to ".md".
+	Extensions []string
+
+	// Update skips files whose content matches the latest version of their
+	// document, so re-importing a directory only versions what changed.
+	Update bool
+
+	// Frontmatter reads a leading YAML block from each file. A "path" field
+	// overrides the filename-derived path (the prefix still applies) and a
+	// "tags" list is applied after writing. Files without frontmatter are
+	// imported by filename as usual.
+	Frontmatter bool
 }
 
 // Result contains the outcome of an import operation.
 type Result struct {
 	Imported int      // Number of files imported
 	Paths    []string // Paths that were/would be imported
+
+	// Populated in update mode only.
+	Created   int // New documents
+	Updated   int // Existing documents given a new version
+	Unchanged int // Existing documents skipped because content matched
 }
 
+// ErrInvalidExtension is returned for an empty extension or one without a
+// leading dot.
+var ErrInvalidExtension = errors.New("invalid extension")
+
+// DefaultExtensions is used when Options.Extensions is empty.
+var DefaultExtensions = []string{".md"}
+
 // Run executes the import operation.
 // Uses os.Root for safe path traversal within the source directory.
 func Run(ctx context.Context, w io.Writer, svc service.Service, src string, opts Options) (Result, error) {
 	var result Result
 
+	exts, err := checkExtensions(opts.Extensions)
+	if err != nil {
+		return result, err
+	}
+	opts.Extensions = exts
+
 	info, err := os.Stat(src)
 	if err != nil {
 		return result, err
 	}
 
-	// Single file import
+	// Single file import, or every entry of an archive
 	if !info.IsDir() {
+		if _, err := archive.FormatOf(src); err == nil {
+			return importArchive(ctx, w, svc, src, opts)
+		}
 		return importSingleFile(ctx, w, svc, src, opts)
 	}
 
@@ -51,7 +96,7 @@ func Run(ctx context.Context, w io.Writer, svc service.Service, src string, opts
 	}
 	defer root.Close()
 
-	files, err := scanRoot(root, "", opts.Hidden)
+	files, err := scanRoot(root, "", opts.Hidden, opts.Extensions)
 	if err != nil {
 		return result, fmt.Errorf("scanning %s: %w", src, err)
 	}
@@ -64,68 +109,146 @@ func Run(ctx context.Context, w io.Writer, svc service.Service, src string, opts
 	defer prog.Done()
 
 	for _, rel := range files {
-		path := calcDocPath(rel, opts.Prefix, opts.Flat)
-		result.Paths = append(result.Paths, path)
+		path := calcDocPath(rel, opts.Prefix, opts.Flat, opts.Extensions)
 
-		if opts.DryRun {
-			fmt.Fprintf(w, "Would import: %s -> %s\n", filepath.Join(src, rel), path)
-			prog.Increment()
-			prog.Print()
-			continue
-		}
-
-		content, err := readFileInRoot(root, rel)
-		if err != nil {
-			return result, fmt.Errorf("reading %s: %w", rel, err)
+		var content string
+		if needsContent(opts) {
+			content, err = readFileInRoot(root, rel)
+			if err != nil {
+				return result, fmt.Errorf("reading %s: %w", rel, err)
+			}
 		}
 
-		if err := svc.Write(ctx, path, content, opts.Author, opts.Msg); err != nil {
-			return result, fmt.Errorf("writing %s: %w", path, err)
+		if err := importFile(ctx, w, svc, filepath.Join(src, rel), path, content, opts, &result); err != nil {
+			return result, err
 		}
 
 		prog.Increment()
 		prog.Print()
-		fmt.Fprintf(w, "Imported: %s -> %s\n", filepath.Join(src, rel), path)
-		result.Imported++
 	}
 
 	return result, nil
 }
 
-// importSingleFile imports a single markdown file.
+// importSingleFile imports a single file if it has an accepted extension.
 func importSingleFile(ctx context.Context, w io.Writer, svc service.Service, file string, opts Options) (Result, error) {
 	var result Result
 
-	if !strings.HasSuffix(strings.ToLower(file), ".md") {
+	if matchExt(file, opts.Extensions) == "" {
 		return result, nil
 	}
 
 	name := filepath.Base(file)
-	path := calcDocPath(name, opts.Prefix, opts.Flat)
-	result.Paths = append(result.Paths, path)
+	path := calcDocPath(name, opts.Prefix, opts.Flat, opts.Extensions)
 
-	if opts.DryRun {
-		fmt.Fprintf(w, "Would import: %s -> %s\n", file, path)
-		return result, nil
+	var content []byte
+	if needsContent(opts) {
+		var err error
+		content, err = os.ReadFile(file)
+		if err != nil {
+			return result, fmt.Errorf("reading %s: %w", file, err)
+		}
 	}
 
-	content, err := os.ReadFile(file)
-	if err != nil {
-		return result, fmt.Errorf("reading %s: %w", file, err)
+	err := importFile(ctx, w, svc, file, path, string(content), opts, &result)
+	return result, err
+}
+
+// importArchive imports each entry of a .tar.gz or .zip archive as if it
+// were a file in a directory import, so entry names become document paths.
+// Names come from the archive, not the filesystem, so each is validated
+// before use and the whole import fails on one that would escape the store.
+func importArchive(ctx context.Context, w io.Writer, svc service.Service, src string, opts Options) (Result, error) {
+	var result Result
+	err := archive.Walk(src, func(e archive.Entry) error {
+		if matchExt(e.Name, opts.Extensions) == "" {
+			return nil
+		}
+		if !opts.Hidden && hiddenEntry(e.Name) {
+			return nil
+		}
+		if _, err := validate.Path(e.Name, 0); err != nil {
+			return fmt.Errorf("archive entry %q: %w", e.Name, err)
+		}
+		path := calcDocPath(e.Name, opts.Prefix, opts.Flat, opts.Extensions)
+		return importFile(ctx, w, svc, src+":"+e.Name, path, string(e.Content), opts, &result)
+	})
+	return result, err
+}
+
+// hiddenEntry reports whether any component of an archive entry name is
+// hidden, matching how a directory import skips hidden files and dirs.
+func hiddenEntry(name string) bool {
+	for part := range strings.SplitSeq(name, "/") {
+		if strings.HasPrefix(part, ".") && part != "." && part != ".." {
+			return true
+		}
 	}
+	return false
+}
+
+// needsContent reports whether files must be read. A plain dry run only
+// lists paths, but frontmatter can change the path and update mode has to
+// compare content.
+func needsContent(opts Options) bool {
+	return !opts.DryRun || opts.Frontmatter || opts.Update
+}
+
+// importFile writes content read from source to path, recording the
+// outcome in result.
+func importFile(ctx context.Context, w io.Writer, svc service.Service, source, path, content string, opts Options, result *Result) error {
+	var tags []string
+	if opts.Frontmatter {
+		var err error
+		path, tags, err = applyFrontmatter(path, content, opts.Prefix, opts.Extensions)
+		if err != nil {
+			return fmt.Errorf("reading %s: %w", source, err)
+		}
+	}
+	result.Paths = append(result.Paths, path)
 
-	if err := svc.Write(ctx, path, string(content), opts.Author, opts.Msg); err != nil {
-		return result, fmt.Errorf("writing %s: %w", path, err)
+	// Labels for the dry-run and real output respectively
+	would, did := "import", "Imported"
+	count := new(int)
+	if opts.Update {
+		latest, err := svc.Latest(ctx, path, false)
+		switch {
+		case errors.Is(err, store.ErrNotFound):
+			would, did, count = "create", "Created", &result.Created
+		case err != nil:
+			return fmt.Errorf("reading %s: %w", path, err)
+		case sha256.Sum256([]byte(latest.Content)) == sha256.Sum256([]byte(content)):
+			result.Unchanged++
+			fmt.Fprintf(w, "Unchanged: %s -> %s\n", source, path)
+			return nil
+		default:
+			would, did, count = "update", "Updated", &result.Updated
+		}
 	}
 
-	fmt.Fprintf(w, "Imported: %s -> %s\n", file, path)
-	result.Imported = 1
-	return result, nil
+	if opts.DryRun {
+		fmt.Fprintf(w, "Would %s: %s -> %s\n", would, source, path)
+		*count++
+		return nil
+	}
+
+	// The file extension is dropped from the path, so record what it said
+	// about the content; unknown extensions import as markdown.
+	if err := svc.WriteType(ctx, path, content, contenttype.FromName(source), opts.Author, opts.Msg); err != nil {
+		return fmt.Errorf("writing %s: %w", path, err)
+	}
+	if err := tagDoc(ctx, svc, path, tags); err != nil {
+		return err
+	}
+	fmt.Fprintf(w, "%s: %s -> %s\n", did, source, path)
+	result.Imported++
+	*count++
+	return nil
 }
 
-// scanRoot recursively finds all markdown files within an os.Root.
+// scanRoot recursively finds files with one of exts within an os.Root.
 // Returns relative paths from the root.
-func scanRoot(root *os.Root, dir string, includeHidden bool) ([]string, error) {
+func scanRoot(root *os.Root, dir string, includeHidden bool, exts []string) ([]string, error) {
 	var files []string
 
 	path := dir
@@ -158,12 +281,12 @@ func scanRoot(root *os.Root, dir string, includeHidden bool) ([]string, error) {
 		}
 
 		if entry.IsDir() {
-			subfiles, err := scanRoot(root, rel, includeHidden)
+			subfiles, err := scanRoot(root, rel, includeHidden, exts)
 			if err != nil {
 				return nil, err
 			}
 			files = append(files, subfiles...)
-		} else if strings.HasSuffix(strings.ToLower(name), ".md") {
+		} else if matchExt(name, exts) != "" {
 			files = append(files, rel)
 		}
 	}
@@ -193,11 +316,13 @@ func readFileInRoot(root *os.Root, name string) (string, error) {
 	return string(content), nil
 }
 
-// calcDocPath calculates the document path for importing a file.
-func calcDocPath(relPath, prefix string, flat bool) string {
-	// Remove .md extension
-	path := strings.TrimSuffix(relPath, ".md")
-	path = strings.TrimSuffix(path, ".MD")
+// calcDocPath calculates the document path for importing a file, stripping
+// whichever of exts the file matched.
+func calcDocPath(relPath, prefix string, flat bool, exts []string) string {
+	path := relPath
+	if ext := matchExt(path, exts); ext != "" {
+		path = path[:len(path)-len(ext)]
+	}
 	path = filepath.ToSlash(path)
 
 	if flat {
@@ -211,3 +336,106 @@ func calcDocPath(relPath, prefix string, flat bool) string {
 
 	return path
 }
+
+// checkExtensions validates exts, returning the defaults when empty.
+func checkExtensions(exts []string) ([]string, error) {
+	if len(exts) == 0 {
+		return DefaultExtensions, nil
+	}
+	for _, ext := range exts {
+		if len(ext) < 2 || ext[0] != '.' {
+			return nil, fmt.Errorf("%w %q: must start with a dot, e.g. .mdx", ErrInvalidExtension, ext)
+		}
+	}
+	return exts, nil
+}
+
+// matchExt returns the longest of exts that name ends with, compared
+// case-insensitively, or "" if none match. Longest wins so ".md.txt" is
+// preferred over ".txt" when both are accepted.
+func matchExt(name string, exts []string) string {
+	lower := strings.ToLower(name)
+	match := ""
+	for _, ext := range exts {
+		if len(ext) > len(match) && strings.HasSuffix(lower, strings.ToLower(ext)) {
+			match = ext
+		}
+	}
+	return match
+}
+
+// frontmatter holds the fields import understands from a file's leading
+// YAML block. Other fields are ignored and stay in the document content.
+type frontmatter struct {
+	Path string  `yaml:"path"`
+	Tags tagList `yaml:"tags"`
+}
+
+// tagList accepts tags as a YAML list or as a single comma-separated
+// string, since both forms are common in static site generators.
+type tagList []string
+
+// UnmarshalYAML implements yaml.Unmarshaler.
+func (t *tagList) UnmarshalYAML(node *yaml.Node) error {
+	if node.Kind == yaml.ScalarNode {
+		for tag := range strings.SplitSeq(node.Value, ",") {
+			if tag = strings.TrimSpace(tag); tag != "" {
+				*t = append(*t, tag)
+			}
+		}
+		return nil
+	}
+	var tags []string
+	if err := node.Decode(&tags); err != nil {
+		return err
+	}
+	*t = tags
+	return nil
+}
+
+// parseFrontmatter extracts a leading "---" delimited YAML block. Content
+// without one yields an empty frontmatter and no error.
+func parseFrontmatter(content string) (frontmatter, error) {
+	var fm frontmatter
+
+	lines := strings.Split(strings.ReplaceAll(content, "\r\n", "\n"), "\n")
+	if lines[0] != "---" {
+		return fm, nil
+	}
+
+	// The block closes at the first line that is exactly "---" or "..."
+	closing := slices.IndexFunc(lines[1:], func(l string) bool { return l == "---" || l == "..." })
+	if closing < 0 {
+		return fm, nil
+	}
+	block := strings.Join(lines[1:closing+1], "\n")
+
+	if err := yaml.Unmarshal([]byte(block), &fm); err != nil {
+		return fm, fmt.Errorf("parsing frontmatter: %w", err)
+	}
+	return fm, nil
+}
+
+// applyFrontmatter returns the document path and tags for content, using
+// the frontmatter path when present and falling back to derived otherwise.
+func applyFrontmatter(derived, content, prefix string, exts []string) (string, []string, error) {
+	fm, err := parseFrontmatter(content)
+	if err != nil {
+		return "", nil, err
+	}
+	path := derived
+	if p := strings.Trim(fm.Path, "/"); p != "" {
+		path = calcDocPath(p, prefix, false, exts)
+	}
+	return path, fm.Tags, nil
+}
+
+// tagDoc applies frontmatter tags to a freshly written document.
+func tagDoc(ctx context.Context, svc service.Service, path string, tags []string) error {
+	for _, tag := range tags {
+		if err := svc.Tag(ctx, path, tag, store.NewTagOptions()); err != nil {
+			return fmt.Errorf("tagging %s with %q: %w", path, tag, err)
+		}
+	}
+	return nil
+}
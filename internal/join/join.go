@@ -0,0 +1,196 @@
+// Package join concatenates several documents into one.
+//
+// It is the inverse of split: modular pieces are kept as separate documents
+// for editing, then assembled into a single reference when something needs
+// to be read, exported or handed to an LLM whole.
+package join
+
+import (
+	"cmp"
+	"context"
+	"fmt"
+	"io"
+	"path"
+	"slices"
+	"strings"
+
+	"github.com/jpl-au/llmd/internal/glob"
+	norm "github.com/jpl-au/llmd/internal/path"
+	"github.com/jpl-au/llmd/internal/service"
+	"github.com/jpl-au/llmd/internal/store"
+)
+
+// Options configures a join operation.
+type Options struct {
+	Headers bool   // Put a "## <name>" heading before each source's content
+	Delete  bool   // Soft-delete the sources once the joined document exists
+	Force   bool   // Add a version to dest if it already exists
+	Author  string // Who is performing the join
+	Message string // Version message (default "Join of N documents")
+}
+
+// Result contains the outcome of a join operation.
+type Result struct {
+	Path    string   `json:"path"`
+	Sources []string `json:"sources"`
+	Bytes   int      `json:"bytes"`
+	Deleted bool     `json:"deleted,omitempty"`
+}
+
+// Run writes the contents of sources, in order, to dest. A source may be a
+// path, a key or a glob pattern; patterns expand to their matches in natural
+// path order, so split's section-2 comes before section-10. Each source
+// appears once, at its first position.
+func Run(ctx context.Context, w io.Writer, svc service.Service, dest string, sources []string, opts Options) (Result, error) {
+	result := Result{Path: dest}
+
+	dest, err := norm.Normalise(dest)
+	if err != nil {
+		return result, err
+	}
+	result.Path = dest
+
+	docs, err := resolve(ctx, svc, dest, sources)
+	if err != nil {
+		return result, err
+	}
+
+	if !opts.Force {
+		exists, err := svc.Exists(ctx, dest)
+		if err != nil {
+			return result, err
+		}
+		if exists {
+			return result, fmt.Errorf("%s: %w (use --force to add a version)", dest, store.ErrAlreadyExists)
+		}
+	}
+
+	var b strings.Builder
+	for i, d := range docs {
+		if i > 0 {
+			b.WriteString("\n")
+		}
+		if opts.Headers {
+			fmt.Fprintf(&b, "## %s\n\n", path.Base(d.Path))
+		}
+		b.WriteString(d.Content)
+		if !strings.HasSuffix(d.Content, "\n") {
+			b.WriteString("\n")
+		}
+		result.Sources = append(result.Sources, d.Path)
+	}
+	content := b.String()
+
+	msg := opts.Message
+	if msg == "" {
+		msg = fmt.Sprintf("Join of %d documents", len(docs))
+	}
+	if err := svc.Write(ctx, dest, content, opts.Author, msg); err != nil {
+		return result, err
+	}
+	result.Bytes = len(content)
+	fmt.Fprintf(w, "Joined %d documents into %s\n", len(docs), dest)
+
+	if opts.Delete {
+		for _, p := range result.Sources {
+			if err := svc.Delete(ctx, p); err != nil {
+				return result, fmt.Errorf("delete %s: %w", p, err)
+			}
+		}
+		result.Deleted = true
+	}
+	return result, nil
+}
+
+// resolve loads every source, expanding patterns. The destination is left
+// out of pattern matches so re-running a join does not include its own
+// output, but naming it explicitly is an error.
+func resolve(ctx context.Context, svc service.Service, dest string, sources []string) ([]*store.Document, error) {
+	var docs []*store.Document
+	var seen []string
+	add := func(d *store.Document) error {
+		if d.Path == dest {
+			return fmt.Errorf("%s cannot be both a source and the destination", dest)
+		}
+		if !slices.Contains(seen, d.Path) {
+			seen = append(seen, d.Path)
+			docs = append(docs, d)
+		}
+		return nil
+	}
+
+	for _, src := range sources {
+		if !glob.IsPattern(src) {
+			d, _, err := svc.Resolve(ctx, src, false)
+			if err != nil {
+				return nil, fmt.Errorf("%s: %w", src, err)
+			}
+			if err := add(d); err != nil {
+				return nil, err
+			}
+			continue
+		}
+
+		paths, err := svc.Glob(ctx, src)
+		if err != nil {
+			return nil, err
+		}
+		paths = slices.DeleteFunc(paths, func(p string) bool { return p == dest })
+		if len(paths) == 0 {
+			return nil, fmt.Errorf("%s: no documents match", src)
+		}
+		slices.SortFunc(paths, natural)
+		for _, p := range paths {
+			d, err := svc.Latest(ctx, p, false)
+			if err != nil {
+				return nil, fmt.Errorf("%s: %w", p, err)
+			}
+			if err := add(d); err != nil {
+				return nil, err
+			}
+		}
+	}
+
+	if len(docs) == 0 {
+		return nil, fmt.Errorf("no source documents")
+	}
+	return docs, nil
+}
+
+// natural compares paths with each run of digits taken as a number, so
+// "part-2" sorts before "part-10". Equal numbers written differently ("07"
+// and "7") fall back to comparing the text.
+func natural(a, b string) int {
+	x, y := a, b
+	for a != "" && b != "" {
+		da, db := digits(a), digits(b)
+		if da == 0 || db == 0 {
+			if c := cmp.Compare(a[0], b[0]); c != 0 {
+				return c
+			}
+			a, b = a[1:], b[1:]
+			continue
+		}
+		na, nb := strings.TrimLeft(a[:da], "0"), strings.TrimLeft(b[:db], "0")
+		if c := cmp.Compare(len(na), len(nb)); c != 0 {
+			return c
+		}
+		if c := cmp.Compare(na, nb); c != 0 {
+			return c
+		}
+		a, b = a[da:], b[db:]
+	}
+	if c := cmp.Compare(len(a), len(b)); c != 0 {
+		return c
+	}
+	return strings.Compare(x, y)
+}
+
+// digits returns the length of the run of ASCII digits at the start of s.
+func digits(s string) int {
+	n := 0
+	for n < len(s) && s[n] >= '0' && s[n] <= '9' {
+		n++
+	}
+	return n
+}
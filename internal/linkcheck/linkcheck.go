@@ -0,0 +1,152 @@
+// Package linkcheck reports markdown links to llmd documents that do not
+// resolve.
+//
+// Cross-references written in prose break quietly when a document is moved
+// or deleted: nothing in the store tracks them. Checking every document's
+// links on demand (or in CI) catches the dangling ones before a reader does.
+package linkcheck
+
+import (
+	"context"
+	"fmt"
+	"io"
+
+	"github.com/jpl-au/llmd/internal/mdlink"
+	"github.com/jpl-au/llmd/internal/service"
+	"github.com/jpl-au/llmd/internal/store"
+)
+
+// Problems reported for a reference.
+const (
+	ProblemMissing  = "missing"  // Target document does not exist
+	ProblemInvalid  = "invalid"  // Target is not a valid document path
+	ProblemUnlinked = "unlinked" // Target exists but no link connects the two
+)
+
+// Options configures a link check.
+type Options struct {
+	Path string // Only check documents under this prefix (empty = all)
+
+	// Unlinked also reports references whose target exists but has no
+	// formal link to or from the source, i.e. prose the link graph misses.
+	Unlinked bool
+}
+
+// Issue is one reference that failed the check.
+type Issue struct {
+	Source  string `json:"source"`
+	Line    int    `json:"line"`
+	Target  string `json:"target"` // Resolved path, or the raw target if invalid
+	Problem string `json:"problem"`
+}
+
+// Result reports the outcome of a link check.
+type Result struct {
+	OK        bool    `json:"ok"`
+	Documents int     `json:"documents"`  // Documents scanned
+	Refs      int     `json:"references"` // Document references found
+	Issues    []Issue `json:"issues"`
+}
+
+// Run checks the markdown links in every document under opts.Path and
+// writes one "source:line -> target" line per issue to w. Issues are
+// reported in the result; err is only set when the check could not run.
+func Run(ctx context.Context, w io.Writer, svc service.Service, opts Options) (Result, error) {
+	docs, err := svc.List(ctx, opts.Path, false, false)
+	if err != nil {
+		return Result{}, err
+	}
+
+	result := Result{Documents: len(docs), Issues: []Issue{}}
+	exists := map[string]bool{}
+	for _, doc := range docs {
+		refs, err := references(ctx, svc, doc, exists)
+		if err != nil {
+			return result, err
+		}
+		result.Refs += len(refs)
+
+		var linked map[string]bool
+		for _, ref := range refs {
+			if ref.Problem != "" {
+				result.Issues = append(result.Issues, ref)
+				continue
+			}
+			if !opts.Unlinked || ref.Target == doc.Path {
+				continue
+			}
+			if linked == nil {
+				if linked, err = linkedPaths(ctx, svc, doc.Path); err != nil {
+					return result, err
+				}
+			}
+			if !linked[ref.Target] {
+				ref.Problem = ProblemUnlinked
+				result.Issues = append(result.Issues, ref)
+			}
+		}
+	}
+
+	result.OK = len(result.Issues) == 0
+	for _, i := range result.Issues {
+		switch i.Problem {
+		case ProblemMissing:
+			fmt.Fprintf(w, "%s:%d -> %s\n", i.Source, i.Line, i.Target)
+		default:
+			fmt.Fprintf(w, "%s:%d -> %s (%s)\n", i.Source, i.Line, i.Target, i.Problem)
+		}
+	}
+	return result, nil
+}
+
+// References returns every document reference in doc's content with its
+// target resolved. References whose target exists have an empty Problem;
+// the rest are marked ProblemMissing or ProblemInvalid.
+func References(ctx context.Context, svc service.Service, doc store.Document) ([]Issue, error) {
+	return references(ctx, svc, doc, map[string]bool{})
+}
+
+// references implements References, caching existence checks in exists so
+// a target referenced from many documents is looked up once.
+func references(ctx context.Context, svc service.Service, doc store.Document, exists map[string]bool) ([]Issue, error) {
+	var refs []Issue
+	for _, ref := range mdlink.Extract(doc.Content) {
+		r := Issue{Source: doc.Path, Line: ref.Line, Target: ref.Target}
+
+		target, err := mdlink.Resolve(doc.Path, ref.Target)
+		if err != nil {
+			r.Problem = ProblemInvalid
+			refs = append(refs, r)
+			continue
+		}
+		r.Target = target
+
+		ok, seen := exists[target]
+		if !seen {
+			if ok, err = svc.Exists(ctx, target); err != nil {
+				return nil, fmt.Errorf("check %s: %w", target, err)
+			}
+			exists[target] = ok
+		}
+		if !ok {
+			r.Problem = ProblemMissing
+		}
+		refs = append(refs, r)
+	}
+	return refs, nil
+}
+
+// linkedPaths returns the documents connected to path by a link in either
+// direction.
+func linkedPaths(ctx context.Context, svc service.Service, path string) (map[string]bool, error) {
+	links, err := svc.ListLinks(ctx, path, "", store.NewLinkOptions())
+	if err != nil {
+		return nil, fmt.Errorf("list links for %s: %w", path, err)
+	}
+	m := make(map[string]bool, len(links))
+	for _, l := range links {
+		m[l.FromPath] = true
+		m[l.ToPath] = true
+	}
+	return m, nil
+}
@@ -21,12 +21,25 @@
 // The source parameter follows the format "{extension}:{command}" for CLI
 // commands or "mcp:{tool}" for MCP tools. Examples: "document:cat",
 // "search:grep", "mcp:write".
+//
+// # Debug Entries
+//
+// [Debug] builds an entry the same way for timing internal operations
+// (resolving a path, a store query). Debug entries are never stored: with
+// a verbose writer set by [SetVerbose] (the --verbose flag), Write prints
+// them with their duration; otherwise they are discarded.
+//
+//	defer log.Debug("service:list", "read").Path(prefix).Write(nil)
 package log
 
 import (
 	"database/sql"
+	"fmt"
+	"io"
+	"maps"
 	"os"
 	"path/filepath"
+	"slices"
 	"sync"
 	"time"
 
@@ -34,8 +47,9 @@ import (
 )
 
 var (
-	global *Logger
-	mu     sync.Mutex
+	global  *Logger
+	verbose io.Writer // Destination for debug entries (nil = discard)
+	mu      sync.Mutex
 )
 
 // Entry represents a single log entry.
@@ -64,6 +78,8 @@ type Entry struct {
 // to write the entry.
 type Builder struct {
 	entry Entry
+	debug bool      // Print to the verbose writer instead of storing
+	began time.Time // Precise start, for debug durations
 }
 
 // Event creates a new log entry builder for an operation.
@@ -82,15 +98,38 @@ type Builder struct {
 //		Path(p).
 //		Write(err)
 func Event(source, action string) *Builder {
+	now := time.Now()
 	return &Builder{
 		entry: Entry{
 			Source: source,
 			Action: action,
-			Start:  time.Now().Unix(),
+			Start:  now.Unix(),
 		},
+		began: now,
 	}
 }
 
+// Debug creates a builder for a diagnostic entry timing an internal
+// operation. Write prints it with its duration to the verbose writer, and
+// never records it in the audit log.
+//
+// Example:
+//
+//	defer log.Debug("service:resolve", "read").Path(value).Write(nil)
+func Debug(source, action string) *Builder {
+	b := Event(source, action)
+	b.debug = true
+	return b
+}
+
+// SetVerbose directs debug entries to w, or discards them if w is nil.
+// Pass os.Stderr: stdout carries command output, and JSON-RPC for MCP.
+func SetVerbose(w io.Writer) {
+	mu.Lock()
+	defer mu.Unlock()
+	verbose = w
+}
+
 // Author sets who performed the operation.
 //
 // For CLI commands, use cmd.Author() which returns the configured author.
@@ -189,6 +228,10 @@ func (b *Builder) Detail(key string, value any) *Builder {
 //		return err
 //	}
 func (b *Builder) Write(err error) {
+	if b.debug {
+		b.writeDebug(err)
+		return
+	}
 	b.entry.End = time.Now().Unix()
 	b.entry.Success = err == nil
 	if err != nil {
@@ -197,6 +240,31 @@ func (b *Builder) Write(err error) {
 	Log(b.entry)
 }
 
+// writeDebug prints a debug entry as a single line:
+//
+//	llmd: 1.204ms service:list read path=docs/ count=12
+func (b *Builder) writeDebug(err error) {
+	mu.Lock()
+	w := verbose
+	mu.Unlock()
+	if w == nil {
+		return
+	}
+
+	e := b.entry
+	line := fmt.Sprintf("llmd: %s %s %s", time.Since(b.began).Round(time.Microsecond), e.Source, e.Action)
+	if e.Path != "" {
+		line += " path=" + e.Path
+	}
+	for _, k := range slices.Sorted(maps.Keys(e.Detail)) {
+		line += fmt.Sprintf(" %s=%v", k, e.Detail[k])
+	}
+	if err != nil {
+		line += fmt.Sprintf(" error=%q", err.Error())
+	}
+	fmt.Fprintln(w, line)
+}
+
 // Open initialises the global logger. Safe to call multiple times.
 // Errors are returned but callers may choose to ignore them (best-effort logging).
 func Open() error {
@@ -1,9 +1,12 @@
 package log
 
 import (
+	"bytes"
 	"database/sql"
+	"errors"
 	"os"
 	"path/filepath"
+	"strings"
 	"testing"
 
 	"github.com/stretchr/testify/assert"
@@ -263,3 +266,44 @@ func TestBuilder(t *testing.T) {
 		assert.Contains(t, detail, "42")
 	})
 }
+
+func TestDebug(t *testing.T) {
+	t.Run("discarded without verbose writer", func(t *testing.T) {
+		SetVerbose(nil)
+		Debug("service:list", "list").Path("docs/").Write(nil)
+	})
+
+	t.Run("printed to verbose writer", func(t *testing.T) {
+		var buf bytes.Buffer
+		SetVerbose(&buf)
+		defer SetVerbose(nil)
+
+		Debug("service:list", "list").Path("docs/").Detail("count", 3).Write(nil)
+		Debug("service:resolve", "resolve").Path("missing").Write(errors.New("not found"))
+
+		lines := strings.Split(strings.TrimSpace(buf.String()), "\n")
+		require.Len(t, lines, 2)
+		assert.Regexp(t, `^llmd: \S+ service:list list path=docs/ count=3$`, lines[0])
+		assert.Regexp(t, `^llmd: \S+ service:resolve resolve path=missing error="not found"$`, lines[1])
+	})
+
+	t.Run("never stored", func(t *testing.T) {
+		tmpDir := t.TempDir()
+		origDBPath := dbPathFunc
+		dbPathFunc = func() string { return filepath.Join(tmpDir, "log", "test.db") }
+		defer func() { dbPathFunc = origDBPath }()
+
+		require.NoError(t, Open())
+		defer Close()
+
+		Debug("service:list", "list").Write(nil)
+
+		db, err := sql.Open("sqlite", DBPath())
+		require.NoError(t, err)
+		defer db.Close()
+
+		var count int
+		require.NoError(t, db.QueryRow("SELECT COUNT(*) FROM log").Scan(&count))
+		assert.Equal(t, 0, count)
+	})
+}
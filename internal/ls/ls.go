@@ -16,6 +16,8 @@ package ls
 
 import (
 	"context"
+	"errors"
+	"fmt"
 	"io"
 	"sort"
 	"time"
@@ -35,19 +37,31 @@ const (
 	SortNone SortField = ""
 	SortName SortField = "name"
 	SortTime SortField = "time" // newest first by default (most relevant for "what's new?")
+	SortSize SortField = "size" // largest first by default
 )
 
 // Options configures a list operation.
 type Options struct {
 	Prefix      string    // Filter by path prefix
 	Recursive   bool      // Search subdirectories (-R flag)
+	Depth       int       // With Recursive, at most this many segments below Prefix (0 = unlimited)
 	IncludeAll  bool      // Include deleted documents
 	DeletedOnly bool      // Show only deleted documents
 	Tree        bool      // Display as tree
 	Long        bool      // Long format with metadata
 	Tag         string    // Filter by tag
-	Sort        SortField // Sort field (name, time)
+	Sort        SortField // Sort field (name, time, size)
 	Reverse     bool      // Reverse sort order
+	Delimiter   rune      // CSV/TSV output when non-zero (',' or '\t')
+	Since       time.Time // Only documents whose latest version is newer (zero = all)
+	MinSize     int64     // Only documents of at least this many bytes (0 = no limit)
+	MaxSize     int64     // Only documents of at most this many bytes (0 = no limit)
+	Human       bool      // Abbreviate sizes (1.2K) in long format
+	DirsOnly    bool      // Show only directories, with document counts
+	FilesOnly   bool      // Show only document paths
+	AsOf        time.Time // List documents as they were at this time (zero = now)
+	Limit       int       // At most this many entries, after filtering and sorting (0 = all)
+	Offset      int       // Skip this many entries before applying Limit
 }
 
 // Result contains the outcome of a list operation.
@@ -58,10 +72,15 @@ type Options struct {
 type Result struct {
 	Documents []store.Document
 	Metas     []store.DocumentMeta
+	Dirs      []format.Dir // Set instead of Documents for DirsOnly
+	Total     int          // Entries before Offset and Limit were applied
 }
 
 // Count returns the number of documents in the result.
 func (r Result) Count() int {
+	if r.Dirs != nil {
+		return len(r.Dirs)
+	}
 	if len(r.Metas) > 0 {
 		return len(r.Metas)
 	}
@@ -80,21 +99,30 @@ type MetaJSON struct {
 	Deleted   bool   `json:"deleted,omitempty"`
 }
 
+// ToMetaJSON converts DocumentMeta to its API representation with an
+// RFC3339 timestamp.
+func ToMetaJSON(m store.DocumentMeta) MetaJSON {
+	return MetaJSON{
+		Key:       m.Key,
+		Path:      m.Path,
+		Version:   m.Version,
+		Author:    m.Author,
+		Message:   m.Message,
+		CreatedAt: time.Unix(m.CreatedAt, 0).UTC().Format(time.RFC3339),
+		Size:      m.Size,
+		Deleted:   m.DeletedAt != nil,
+	}
+}
+
 // ToJSON converts the result to JSON-serializable format.
 func (r Result) ToJSON() any {
+	if r.Dirs != nil {
+		return r.Dirs
+	}
 	if len(r.Metas) > 0 {
 		out := make([]MetaJSON, len(r.Metas))
 		for i, m := range r.Metas {
-			out[i] = MetaJSON{
-				Key:       m.Key,
-				Path:      m.Path,
-				Version:   m.Version,
-				Author:    m.Author,
-				Message:   m.Message,
-				CreatedAt: time.Unix(m.CreatedAt, 0).UTC().Format(time.RFC3339),
-				Size:      m.Size,
-				Deleted:   m.DeletedAt != nil,
-			}
+			out[i] = ToMetaJSON(m)
 		}
 		return out
 	}
@@ -112,25 +140,52 @@ func (r Result) ToJSON() any {
 func Run(ctx context.Context, w io.Writer, svc service.Service, opts Options) (Result, error) {
 	var result Result
 
-	if opts.Long {
+	if opts.DirsOnly && opts.FilesOnly {
+		return result, errors.New("--dirs-only and --files-only cannot be combined")
+	}
+	if (opts.DirsOnly || opts.FilesOnly) && (opts.Long || opts.Delimiter != 0) {
+		return result, errors.New("--dirs-only and --files-only cannot be combined with long or CSV/TSV output")
+	}
+
+	if !opts.AsOf.IsZero() && (opts.IncludeAll || opts.DeletedOnly) {
+		return result, errors.New("--as-of cannot be combined with deleted listings")
+	}
+
+	if opts.MinSize < 0 || opts.MaxSize < 0 {
+		return result, errors.New("size limits must be >= 0")
+	}
+	if opts.Depth < 0 {
+		return result, fmt.Errorf("--depth must be >= 0, got %d", opts.Depth)
+	}
+	if opts.Limit < 0 || opts.Offset < 0 {
+		return result, fmt.Errorf("limit and offset must be >= 0, got %d and %d", opts.Limit, opts.Offset)
+	}
+	if opts.MaxSize > 0 && opts.MinSize > opts.MaxSize {
+		return result, fmt.Errorf("--min-size %d is greater than --max-size %d", opts.MinSize, opts.MaxSize)
+	}
+
+	// Sizes come from ListMeta, so a size sort takes the metadata path even
+	// for the plain listing. Tree and directory output order by path anyway.
+	sizeSorted := opts.Sort == SortSize && !opts.Tree && !opts.DirsOnly
+	if opts.Long || opts.Delimiter != 0 || sizeSorted {
 		return runLong(ctx, w, svc, opts)
 	}
 
-	docs, err := svc.List(ctx, opts.Prefix, opts.IncludeAll, opts.DeletedOnly)
+	docs, err := list(ctx, svc, opts)
 	if err != nil {
 		return result, err
 	}
 
-	// Filter to direct children only when not recursive
-	if !opts.Recursive {
-		var filtered []store.Document
-		for _, d := range docs {
-			if path.Direct(d.Path, opts.Prefix) {
-				filtered = append(filtered, d)
-			}
+	// The store matches the prefix as a string; keep only documents under it
+	// as a directory, and only direct children unless recursive. Tree and
+	// directory output are built from what remains, so --depth prunes them too.
+	var filtered []store.Document
+	for _, d := range docs {
+		if inScope(d.Path, opts) {
+			filtered = append(filtered, d)
 		}
-		docs = filtered
 	}
+	docs = filtered
 
 	if opts.Tag != "" {
 		tagged, err := svc.PathsWithTag(ctx, opts.Tag, store.NewTagOptions())
@@ -154,6 +209,26 @@ func Run(ctx context.Context, w io.Writer, svc service.Service, opts Options) (R
 		docs = filtered
 	}
 
+	if !opts.Since.IsZero() {
+		var recent []store.Document
+		for _, d := range docs {
+			if d.CreatedAt > opts.Since.Unix() {
+				recent = append(recent, d)
+			}
+		}
+		docs = recent
+	}
+
+	if opts.MinSize > 0 || opts.MaxSize > 0 {
+		var sized []store.Document
+		for _, d := range docs {
+			if inSize(int64(len(d.Content)), opts) {
+				sized = append(sized, d)
+			}
+		}
+		docs = sized
+	}
+
 	// Sort results. Name sorting is alphabetical by path. Time sorting shows
 	// newest first by default, which is most useful for "what changed recently?"
 	// questions. When timestamps match, we use path as a tie-breaker to ensure
@@ -181,18 +256,67 @@ func Run(ctx context.Context, w io.Writer, svc service.Service, opts Options) (R
 		})
 	}
 
-	result.Documents = docs
-
-	if opts.Tree {
-		err = format.Tree(w, docs)
+	if opts.DirsOnly {
+		// Ancestors of the prefix are implied too, but their counts would
+		// only cover the part of them that was listed
+		result.Dirs = []format.Dir{}
+		for _, d := range format.Dirs(docs) {
+			if path.Under(d.Path, opts.Prefix) {
+				result.Dirs = append(result.Dirs, d)
+			}
+		}
+		result.Total = len(result.Dirs)
+		result.Dirs = page(result.Dirs, opts)
 	} else {
+		result.Total = len(docs)
+		docs = page(docs, opts)
+		result.Documents = docs
+	}
+
+	mode := format.TreeAll
+	switch {
+	case opts.DirsOnly:
+		mode = format.TreeDirs
+	case opts.FilesOnly:
+		mode = format.TreeFiles
+	}
+
+	switch {
+	case opts.Tree:
+		err = format.Tree(w, docs, mode)
+	case opts.DirsOnly:
+		err = format.DirList(w, result.Dirs)
+	case opts.FilesOnly:
+		err = format.Paths(w, docs)
+	default:
 		err = format.List(w, docs)
 	}
 
 	return result, err
 }
 
-// runLong handles long format listing.
+// Count returns how many entries Run would list for opts. A recursive count
+// of the whole store with no other filters is answered by the store's count
+// queries without loading documents; anything else lists and counts.
+func Count(ctx context.Context, svc service.Service, opts Options) (int, error) {
+	plain := opts.Prefix == "" && opts.Recursive && opts.Depth == 0 && opts.Tag == "" && opts.Since.IsZero() && opts.AsOf.IsZero() &&
+		opts.MinSize == 0 && opts.MaxSize == 0 && !opts.DirsOnly && !opts.IncludeAll
+	if plain {
+		var n int64
+		var err error
+		if opts.DeletedOnly {
+			n, err = svc.CountDeleted(ctx, "")
+		} else {
+			n, err = svc.Count(ctx, "")
+		}
+		return int(n), err
+	}
+	result, err := Run(ctx, io.Discard, svc, opts)
+	return result.Count(), err
+}
+
+// runLong handles long format listing, CSV/TSV output which carries the
+// same metadata columns, and any listing sorted by size.
 //
 // This is a separate function because long format needs document size, which
 // ListMeta provides efficiently via SQL length(). Using the standard List
@@ -206,21 +330,25 @@ func runLong(ctx context.Context, w io.Writer, svc service.Service, opts Options
 	// ListMeta has a simpler signature than List. It takes a single boolean
 	// for whether to include deleted documents, so we combine the two flags.
 	includeDeleted := opts.IncludeAll || opts.DeletedOnly
-	metas, err := svc.ListMeta(ctx, opts.Prefix, includeDeleted)
+	var metas []store.DocumentMeta
+	var err error
+	if opts.AsOf.IsZero() {
+		metas, err = svc.ListMeta(ctx, opts.Prefix, includeDeleted)
+	} else {
+		metas, err = listMetaAsOf(ctx, svc, opts.Prefix, opts.AsOf)
+	}
 	if err != nil {
 		return result, err
 	}
 
-	// Filter to direct children only when not recursive
-	if !opts.Recursive {
-		var filtered []store.DocumentMeta
-		for _, m := range metas {
-			if path.Direct(m.Path, opts.Prefix) {
-				filtered = append(filtered, m)
-			}
+	// Same scoping as Run
+	var filteredMeta []store.DocumentMeta
+	for _, m := range metas {
+		if inScope(m.Path, opts) {
+			filteredMeta = append(filteredMeta, m)
 		}
-		metas = filtered
 	}
+	metas = filteredMeta
 
 	// Filter to deleted only if requested
 	if opts.DeletedOnly {
@@ -254,6 +382,26 @@ func runLong(ctx context.Context, w io.Writer, svc service.Service, opts Options
 		metas = filtered
 	}
 
+	if !opts.Since.IsZero() {
+		var recent []store.DocumentMeta
+		for _, m := range metas {
+			if m.CreatedAt > opts.Since.Unix() {
+				recent = append(recent, m)
+			}
+		}
+		metas = recent
+	}
+
+	if opts.MinSize > 0 || opts.MaxSize > 0 {
+		var sized []store.DocumentMeta
+		for _, m := range metas {
+			if inSize(m.Size, opts) {
+				sized = append(sized, m)
+			}
+		}
+		metas = sized
+	}
+
 	// Sort results. Name sorting is alphabetical by path. Time sorting shows
 	// newest first by default, which is most useful for "what changed recently?"
 	// questions. When timestamps match, we use path as a tie-breaker to ensure
@@ -279,9 +427,92 @@ func runLong(ctx context.Context, w io.Writer, svc service.Service, opts Options
 			}
 			return metas[i].CreatedAt > metas[j].CreatedAt
 		})
+	case SortSize:
+		sort.Slice(metas, func(i, j int) bool {
+			if metas[i].Size == metas[j].Size {
+				if opts.Reverse {
+					return metas[i].Path > metas[j].Path
+				}
+				return metas[i].Path < metas[j].Path
+			}
+			if opts.Reverse {
+				return metas[i].Size < metas[j].Size
+			}
+			return metas[i].Size > metas[j].Size
+		})
 	}
 
+	result.Total = len(metas)
+	metas = page(metas, opts)
 	result.Metas = metas
-	err = format.LongMeta(w, metas)
+	switch {
+	case opts.Delimiter != 0:
+		err = format.Delimited(w, metas, opts.Delimiter)
+	case opts.Long:
+		err = format.LongMeta(w, metas, opts.Human)
+	case opts.FilesOnly:
+		for _, m := range metas {
+			fmt.Fprintln(w, m.Path)
+		}
+	default:
+		err = format.ListMeta(w, metas)
+	}
 	return result, err
 }
+
+// inScope reports whether p belongs in the listing: under opts.Prefix as
+// path.InScope decides, and no deeper than opts.Depth when it is set.
+func inScope(p string, opts Options) bool {
+	if !path.InScope(p, opts.Prefix, opts.Recursive) {
+		return false
+	}
+	return opts.Depth == 0 || path.Depth(p, opts.Prefix) <= opts.Depth
+}
+
+// inSize reports whether size is within opts.MinSize and opts.MaxSize.
+func inSize(size int64, opts Options) bool {
+	return size >= opts.MinSize && (opts.MaxSize == 0 || size <= opts.MaxSize)
+}
+
+// page returns the entries of s that opts.Offset and opts.Limit select.
+// Paging happens here rather than in the store because the tag, scope,
+// time and size filters and the sorts above all need every candidate.
+func page[T any](s []T, opts Options) []T {
+	s = s[min(opts.Offset, len(s)):]
+	if opts.Limit > 0 && opts.Limit < len(s) {
+		s = s[:opts.Limit]
+	}
+	return s
+}
+
+// list fetches the documents Run filters: the current listing, or the
+// snapshot at opts.AsOf when set.
+func list(ctx context.Context, svc service.Service, opts Options) ([]store.Document, error) {
+	if !opts.AsOf.IsZero() {
+		return svc.ListAsOf(ctx, opts.Prefix, opts.AsOf)
+	}
+	return svc.List(ctx, opts.Prefix, opts.IncludeAll, opts.DeletedOnly)
+}
+
+// listMetaAsOf builds metadata for the snapshot at t. ListMeta only knows
+// the latest versions, so this loads the historical documents and measures
+// their content instead.
+func listMetaAsOf(ctx context.Context, svc service.Service, prefix string, t time.Time) ([]store.DocumentMeta, error) {
+	docs, err := svc.ListAsOf(ctx, prefix, t)
+	if err != nil {
+		return nil, err
+	}
+	metas := make([]store.DocumentMeta, len(docs))
+	for i, d := range docs {
+		metas[i] = store.DocumentMeta{
+			Key:       d.Key,
+			Path:      d.Path,
+			Version:   d.Version,
+			Author:    d.Author,
+			Message:   d.Message,
+			CreatedAt: d.CreatedAt,
+			Size:      int64(len(d.Content)),
+		}
+	}
+	return metas, nil
+}
@@ -0,0 +1,107 @@
+// limits.go guards the tools that change documents against runaway clients.
+//
+// Separated from the tool handlers because the same two checks apply to
+// every content-bearing tool. An LLM stuck in a generation loop can otherwise
+// fill a shared store with huge or endless writes before anyone notices.
+//
+// Design: Limits come from the mcp.* config keys, read once at startup. A
+// rejected call returns an ordinary tool error whose text says which limit
+// was hit and, for the rate limit, how long to wait, so the model can back
+// off instead of retrying blindly. The rate limit is a sliding one-minute
+// window per client session; stdio has a single session.
+
+package mcp
+
+import (
+	"context"
+	"fmt"
+	"math"
+	"sync"
+	"time"
+
+	"github.com/mark3labs/mcp-go/mcp"
+	"github.com/mark3labs/mcp-go/server"
+)
+
+// rateWindow is the period writes are counted over.
+const rateWindow = time.Minute
+
+// writeGuard enforces mcp.max_write and mcp.writes_per_minute. A nil guard,
+// or one with both limits zero, allows everything.
+type writeGuard struct {
+	maxWrite int64 // bytes per call (0 = unlimited)
+	perMin   int   // calls per session per rateWindow (0 = unlimited)
+	now      func() time.Time
+
+	mu       sync.Mutex
+	sessions map[string][]time.Time // recent call times per session
+}
+
+// newWriteGuard returns a guard for the given limits.
+func newWriteGuard(maxWrite int64, perMin int) *writeGuard {
+	return &writeGuard{
+		maxWrite: maxWrite,
+		perMin:   perMin,
+		now:      time.Now,
+		sessions: make(map[string][]time.Time),
+	}
+}
+
+// allow checks a call that sends size bytes of content and records it
+// against the caller's session. It returns an error result when a limit is
+// exceeded, in the same style as requireInit.
+func (g *writeGuard) allow(ctx context.Context, size int) *mcp.CallToolResult {
+	if g == nil {
+		return nil
+	}
+	if g.maxWrite > 0 && int64(size) > g.maxWrite {
+		return mcp.NewToolResultError(fmt.Sprintf(
+			"write rejected: content is %d bytes, over the %d byte limit (mcp.max_write); split it into smaller edits",
+			size, g.maxWrite))
+	}
+	if g.perMin <= 0 {
+		return nil
+	}
+
+	g.mu.Lock()
+	defer g.mu.Unlock()
+
+	now := g.now()
+	g.prune(now)
+	id := sessionID(ctx)
+	recent := g.sessions[id]
+	if len(recent) >= g.perMin {
+		wait := recent[0].Add(rateWindow).Sub(now)
+		return mcp.NewToolResultError(fmt.Sprintf(
+			"write rejected: rate limit of %d writes per minute reached (mcp.writes_per_minute); retry in %ds",
+			g.perMin, int(math.Ceil(wait.Seconds()))))
+	}
+	g.sessions[id] = append(recent, now)
+	return nil
+}
+
+// prune drops calls older than the window, and sessions left with none, so
+// the map does not grow with every client that ever connected.
+func (g *writeGuard) prune(now time.Time) {
+	cutoff := now.Add(-rateWindow)
+	for id, times := range g.sessions {
+		i := 0
+		for i < len(times) && !times[i].After(cutoff) {
+			i++
+		}
+		if i == len(times) {
+			delete(g.sessions, id)
+		} else {
+			g.sessions[id] = times[i:]
+		}
+	}
+}
+
+// sessionID identifies the client making a call. Calls outside a session
+// share one bucket.
+func sessionID(ctx context.Context) string {
+	if s := server.ClientSessionFromContext(ctx); s != nil {
+		return s.SessionID()
+	}
+	return ""
+}
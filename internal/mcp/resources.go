@@ -7,17 +7,24 @@
 //
 // Design: Resource URIs follow the pattern llmd://documents/{path}[/v/{version}].
 // Version is optional; omitting it returns the latest version. This mirrors
-// the CLI's "cat" command behaviour.
+// the CLI's "cat" command behaviour. History is available separately at
+// llmd://history/{path} as JSON metadata, mirroring the llmd_history tool,
+// and llmd://list/{prefix} enumerates documents like "ls -lR".
 
 package mcp
 
 import (
 	"context"
+	"encoding/json"
 	"errors"
 	"fmt"
+	"io"
+	"net/url"
 	"strconv"
 	"strings"
 
+	"github.com/jpl-au/llmd/internal/ls"
+	"github.com/jpl-au/llmd/internal/store"
 	"github.com/mark3labs/mcp-go/mcp"
 )
 
@@ -41,27 +48,26 @@ func (h *handlers) readDocumentResource(ctx context.Context, uri string) ([]mcp.
 		return nil, err
 	}
 
-	var content string
+	var doc *store.Document
 	if version > 0 {
-		doc, err := h.svc.Version(ctx, path, version)
-		if err != nil {
-			return nil, err
-		}
-		content = doc.Content
+		doc, err = h.svc.Version(ctx, path, version)
 	} else {
 		// Use Resolve to support both paths and keys
-		doc, _, err := h.svc.Resolve(ctx, path, false)
-		if err != nil {
-			return nil, err
-		}
-		content = doc.Content
+		doc, _, err = h.svc.Resolve(ctx, path, false)
 	}
+	if err != nil {
+		return nil, err
+	}
+
+	text := h.mask(doc.Content)
 
+	// Report the stored type so clients can tell JSON or plain text from
+	// markdown; rows written before types were recorded have the default.
 	return []mcp.ResourceContents{
 		mcp.TextResourceContents{
 			URI:      uri,
-			MIMEType: "text/markdown",
-			Text:     content,
+			MIMEType: doc.ContentType,
+			Text:     text,
 		},
 	}, nil
 }
@@ -74,7 +80,11 @@ func parseDocumentURI(uri string) (path string, version int, err error) {
 		return "", 0, fmt.Errorf("%w: %s", ErrInvalidURI, uri)
 	}
 
-	rest := strings.TrimPrefix(uri, prefix)
+	// Clients may escape the slashes in the {path} template variable
+	rest, err := url.PathUnescape(strings.TrimPrefix(uri, prefix))
+	if err != nil {
+		return "", 0, fmt.Errorf("%w: %v", ErrInvalidURI, err)
+	}
 	if rest == "" {
 		return "", 0, ErrEmptyPath
 	}
@@ -92,3 +102,95 @@ func parseDocumentURI(uri string) (path string, version int, err error) {
 
 	return rest, 0, nil
 }
+
+// readHistoryResource returns a document's version history as JSON. Like
+// llmd_history, entries carry metadata only; content is fetched per version
+// through llmd://documents/{path}/v/{version}.
+func (h *handlers) readHistoryResource(ctx context.Context, uri string) ([]mcp.ResourceContents, error) {
+	if h.svc == nil {
+		return nil, errors.New(ErrNotInitialised)
+	}
+
+	const prefix = "llmd://history/"
+	if !strings.HasPrefix(uri, prefix) {
+		return nil, fmt.Errorf("%w: %s", ErrInvalidURI, uri)
+	}
+	// Clients may escape the slashes in the {path} template variable
+	path, err := url.PathUnescape(strings.TrimPrefix(uri, prefix))
+	if err != nil {
+		return nil, fmt.Errorf("%w: %v", ErrInvalidURI, err)
+	}
+	if path == "" {
+		return nil, ErrEmptyPath
+	}
+
+	// Use Resolve to support both paths and keys
+	doc, _, err := h.svc.Resolve(ctx, path, false)
+	if err != nil {
+		return nil, err
+	}
+
+	docs, err := h.svc.History(ctx, doc.Path, 0, false)
+	if err != nil {
+		return nil, err
+	}
+
+	history := make([]store.DocJSON, len(docs))
+	for i := range docs {
+		history[i] = docs[i].ToJSON(false)
+	}
+	data, err := json.Marshal(history)
+	if err != nil {
+		return nil, fmt.Errorf("encoding history: %w", err)
+	}
+
+	return []mcp.ResourceContents{
+		mcp.TextResourceContents{
+			URI:      uri,
+			MIMEType: "application/json",
+			Text:     string(data),
+		},
+	}, nil
+}
+
+// readListResource returns the documents under a prefix as JSON metadata.
+// The listing is recursive so a single read enumerates a whole subtree; an
+// empty prefix (llmd://list/) lists the entire store. The prefix may be
+// URL-encoded by clients that escape the slashes in template variables.
+func (h *handlers) readListResource(ctx context.Context, uri string) ([]mcp.ResourceContents, error) {
+	if h.svc == nil {
+		return nil, errors.New(ErrNotInitialised)
+	}
+
+	const scheme = "llmd://list"
+	if uri != scheme && !strings.HasPrefix(uri, scheme+"/") {
+		return nil, fmt.Errorf("%w: %s", ErrInvalidURI, uri)
+	}
+	prefix, err := url.PathUnescape(strings.TrimPrefix(strings.TrimPrefix(uri, scheme), "/"))
+	if err != nil {
+		return nil, fmt.Errorf("%w: %v", ErrInvalidURI, err)
+	}
+
+	// Run ls with io.Discard - we only need the result, not text output
+	result, err := ls.Run(ctx, io.Discard, h.svc, ls.Options{
+		Prefix:    prefix,
+		Recursive: true,
+		Long:      true,
+	})
+	if err != nil {
+		return nil, fmt.Errorf("list documents: %w", err)
+	}
+
+	data, err := json.Marshal(result.ToJSON())
+	if err != nil {
+		return nil, fmt.Errorf("encoding listing: %w", err)
+	}
+
+	return []mcp.ResourceContents{
+		mcp.TextResourceContents{
+			URI:      uri,
+			MIMEType: "application/json",
+			Text:     string(data),
+		},
+	}, nil
+}
@@ -6,10 +6,18 @@ package mcp
 import (
 	"context"
 	"errors"
+	"fmt"
 	"log/slog"
+	"net"
+	"net/http"
 	"os"
+	"slices"
+	"strings"
+	"time"
 
+	"github.com/jpl-au/llmd/internal/config"
 	"github.com/jpl-au/llmd/internal/document"
+	"github.com/jpl-au/llmd/internal/redact"
 	"github.com/jpl-au/llmd/internal/repo"
 	"github.com/mark3labs/mcp-go/mcp"
 	"github.com/mark3labs/mcp-go/server"
@@ -22,22 +30,104 @@ const Version = "1.0.0"
 // The LLM should call llmd_init to create a store before using other tools.
 const ErrNotInitialised = "store not initialised - call llmd_init first"
 
-// Serve starts the MCP server over stdio, enabling LLM integration.
-// Uses stdio transport for compatibility with Claude Desktop and other MCP clients.
+// Transports supported by Serve.
+const (
+	TransportStdio = "stdio"           // JSON-RPC over stdin/stdout (default)
+	TransportSSE   = "sse"             // Server-Sent Events with a POST message endpoint
+	TransportHTTP  = "streamable-http" // Single-endpoint streamable HTTP
+)
+
+// Transports lists the valid transport names, for flag validation and help.
+var Transports = []string{TransportStdio, TransportSSE, TransportHTTP}
+
+// DefaultAddr is the listen address for network transports.
+const DefaultAddr = "localhost:8080"
+
+// DefaultHost is the host network transports listen on when the address has
+// none (":8080").
+const DefaultHost = "localhost"
+
+// shutdownTimeout bounds how long open sessions may take to close.
+const shutdownTimeout = 5 * time.Second
+
+// ServeOptions selects how the server talks to clients.
+type ServeOptions struct {
+	Transport string   // One of Transports; empty means stdio
+	Addr      string   // Listen address for network transports (empty = DefaultAddr)
+	Ephemeral bool     // Serve an in-memory store that is discarded on exit
+	ReadOnly  bool     // Open the store read-only and withhold mutating tools
+	Tools     []string // Register only these tools (empty = all)
+}
+
+// mutatingTools are withheld in read-only mode. Export and sync are included
+// because they write to the host filesystem, and config_set because it
+// rewrites the config file, even though none of them changes documents.
+var mutatingTools = []string{
+	"llmd_init", "llmd_write", "llmd_delete", "llmd_restore", "llmd_revert",
+	"llmd_move", "llmd_edit", "llmd_append", "llmd_prepend", "llmd_sed",
+	"llmd_patch", "llmd_config_set", "llmd_import", "llmd_export", "llmd_sync",
+	"llmd_tag_add", "llmd_tag_remove", "llmd_tag_rename", "llmd_link", "llmd_unlink",
+}
+
+// Serve starts the MCP server, enabling LLM integration. stdio suits clients
+// that launch llmd themselves (Claude Desktop, Claude Code); SSE and
+// streamable HTTP suit clients that connect to a running server. Network
+// transports stop when ctx is cancelled.
 //
 // Design: The server starts successfully even if no store exists. This allows
 // LLMs to call llmd_init to create a store, rather than failing with an opaque
 // error. Tools that require a store return ErrNotInitialised with clear guidance.
-func Serve(db string) error {
+func Serve(ctx context.Context, db string, opts ServeOptions) error {
+	transport := opts.Transport
+	if transport == "" {
+		transport = TransportStdio
+	}
+	if !slices.Contains(Transports, transport) {
+		return fmt.Errorf("unknown transport %q (valid: %s)", transport, strings.Join(Transports, ", "))
+	}
+	addr := opts.Addr
+	if addr == "" {
+		addr = DefaultAddr
+	}
+	// The network transports are unauthenticated and offer tools that read
+	// and write host files, so a bare port must not mean every interface
+	if host, port, err := net.SplitHostPort(addr); err == nil && host == "" {
+		addr = net.JoinHostPort(DefaultHost, port)
+	}
+	if opts.Ephemeral && opts.ReadOnly {
+		return errors.New("an ephemeral store cannot be read-only")
+	}
+
 	// Log to stderr; stdout is reserved for MCP JSON-RPC messages
 	logger := slog.New(slog.NewTextHandler(os.Stderr, nil))
 	slog.SetDefault(logger)
 
-	h := &handlers{db: db}
+	cfg, err := config.Load()
+	if err != nil {
+		return err
+	}
+	h := &handlers{
+		db:        db,
+		guard:     newWriteGuard(cfg.MCPMaxWrite(), cfg.MCPWritesPerMinute()),
+		maxExpand: cfg.MaxContent(),
+	}
+	if cfg.RedactMCP() {
+		if h.redact, err = redact.New(cfg.RedactPatterns()); err != nil {
+			return err
+		}
+		slog.Info("redacting document content returned by tools and resources")
+	}
 
 	// Try to open existing store; nil service is OK (uninitialised mode)
-	svc, err := document.New(db)
-	if err != nil && !errors.Is(err, repo.ErrNotInitialised) {
+	var svc *document.Service
+	if opts.Ephemeral {
+		svc, err = document.NewMemory()
+	} else {
+		svc, err = document.NewWithOptions(db, document.Options{ReadOnly: opts.ReadOnly})
+	}
+	// A read-only server could never be initialised, so there is no point
+	// starting one in uninitialised mode
+	if err != nil && (opts.ReadOnly || !errors.Is(err, repo.ErrNotInitialised)) {
 		// Real error (not just uninitialised)
 		slog.Error("failed to open store", "error", err)
 		return err
@@ -58,22 +148,88 @@ func Serve(db string) error {
 
 	registerResources(s, h)
 	registerTools(s, h)
+	if len(opts.Tools) > 0 {
+		if err := allowTools(s, opts.Tools); err != nil {
+			return err
+		}
+	}
+	if opts.ReadOnly {
+		s.DeleteTools(mutatingTools...)
+		slog.Info("read-only mode, mutating tools disabled")
+	}
+
+	switch transport {
+	case TransportStdio:
+		slog.Info("llmd MCP server ready", "version", Version, "transport", transport)
+		err = server.ServeStdio(s)
+		if errors.Is(err, context.Canceled) {
+			slog.Info("server stopped")
+			return nil
+		}
+		return err
+	case TransportSSE:
+		return serveNetwork(ctx, server.NewSSEServer(s), transport, addr)
+	case TransportHTTP:
+		return serveNetwork(ctx, server.NewStreamableHTTPServer(s), transport, addr)
+	}
+	return fmt.Errorf("unknown transport %q", transport)
+}
+
+// allowTools removes every registered tool not named in allow. Each name
+// must be a real tool, so a typo fails at startup instead of silently
+// leaving the assistant without a tool the operator meant to offer.
+func allowTools(s *server.MCPServer, allow []string) error {
+	registered := s.ListTools()
+	for _, name := range allow {
+		if _, ok := registered[name]; !ok {
+			return fmt.Errorf("unknown tool %q (see 'llmd guide serve' for tool names)", name)
+		}
+	}
+	for name := range registered {
+		if !slices.Contains(allow, name) {
+			s.DeleteTools(name)
+		}
+	}
+	return nil
+}
+
+// networkServer is the lifecycle shared by mcp-go's SSE and streamable
+// HTTP servers.
+type networkServer interface {
+	Start(addr string) error
+	Shutdown(ctx context.Context) error
+}
 
-	slog.Info("llmd MCP server ready", "version", Version, "transport", "stdio")
+// serveNetwork runs srv on addr until it fails or ctx is cancelled.
+func serveNetwork(ctx context.Context, srv networkServer, transport, addr string) error {
+	errc := make(chan error, 1)
+	go func() { errc <- srv.Start(addr) }()
+	slog.Info("llmd MCP server ready", "version", Version, "transport", transport, "addr", addr)
 
-	err = server.ServeStdio(s)
-	if errors.Is(err, context.Canceled) {
-		slog.Info("server stopped")
-		return nil
+	select {
+	case err := <-errc:
+		return err
+	case <-ctx.Done():
 	}
-	return err
+
+	shutdownCtx, cancel := context.WithTimeout(context.Background(), shutdownTimeout)
+	defer cancel()
+	if err := srv.Shutdown(shutdownCtx); err != nil && !errors.Is(err, http.ErrServerClosed) {
+		return err
+	}
+	slog.Info("server stopped")
+	return nil
 }
 
 // handlers provides MCP request handlers with access to the document store.
 // The svc field may be nil if the store has not been initialised.
 type handlers struct {
-	db  string            // database name for init
-	svc *document.Service // nil if not initialised
+	db     string            // database name for init
+	svc    *document.Service // nil if not initialised
+	guard  *writeGuard       // limits on content-changing tools
+	redact *redact.Redactor  // masks secrets in documents read (nil = off)
+
+	maxExpand int64 // largest content llmd_read may expand includes into
 }
 
 // requireInit returns an error result if the store is not initialised.
@@ -108,6 +264,38 @@ func registerResources(s *server.MCPServer, h *handlers) {
 		),
 		h.readDocumentVersion,
 	)
+
+	// Version history metadata by path. Reserved expansion ({+path}) lets
+	// the variable span the slashes in nested paths.
+	s.AddResourceTemplate(
+		mcp.NewResourceTemplate(
+			"llmd://history/{+path}",
+			"Document History",
+			mcp.WithTemplateDescription("Version history of a document (metadata only)"),
+			mcp.WithTemplateMIMEType("application/json"),
+		),
+		h.readHistory,
+	)
+
+	// Recursive listing under a prefix; the bare resource lists everything
+	s.AddResource(
+		mcp.NewResource(
+			"llmd://list/",
+			"Document Listing",
+			mcp.WithResourceDescription("List all documents with metadata"),
+			mcp.WithMIMEType("application/json"),
+		),
+		h.readList,
+	)
+	s.AddResourceTemplate(
+		mcp.NewResourceTemplate(
+			"llmd://list/{+prefix}",
+			"Document Listing by Prefix",
+			mcp.WithTemplateDescription("List documents under a path prefix with metadata"),
+			mcp.WithTemplateMIMEType("application/json"),
+		),
+		h.readList,
+	)
 }
 
 // registerTools exposes llmd operations as MCP tools for LLM invocation.
@@ -130,12 +318,24 @@ func registerTools(s *server.MCPServer, h *handlers) {
 			mcp.WithBoolean("include_deleted", mcp.Description("Include soft-deleted documents")),
 			mcp.WithBoolean("deleted_only", mcp.Description("Show only deleted documents")),
 			mcp.WithString("tag", mcp.Description("Filter by tag")),
-			mcp.WithString("sort", mcp.Description("Sort by: 'name' (alphabetical) or 'time' (newest first)")),
+			mcp.WithString("sort", mcp.Description("Sort by: 'name' (alphabetical), 'time' (newest first) or 'size' (largest first)")),
 			mcp.WithBoolean("reverse", mcp.Description("Reverse sort order")),
+			mcp.WithNumber("limit", mcp.Description("Return at most this many documents, as {documents, total, offset, has_more}")),
+			mcp.WithNumber("offset", mcp.Description("Skip this many documents first; page with offset += limit while has_more")),
 		),
 		h.listDocuments,
 	)
 
+	// Resolve
+	s.AddTool(
+		mcp.NewTool("llmd_resolve",
+			mcp.WithDescription("Show how a path or key is interpreted: the document and version it selects, and whether it was taken as a path or a key. An 8-character path shadows a key of the same name"),
+			mcp.WithString("input", mcp.Required(), mcp.Description("Document path or 8-character key")),
+			mcp.WithBoolean("include_deleted", mcp.Description("Resolve deleted documents too")),
+		),
+		h.resolveInput,
+	)
+
 	// Read document(s)
 	s.AddTool(
 		mcp.NewTool("llmd_read",
@@ -143,6 +343,7 @@ func registerTools(s *server.MCPServer, h *handlers) {
 			mcp.WithArray("paths", mcp.Required(), mcp.Description("Document paths"), mcp.WithStringItems()),
 			mcp.WithNumber("version", mcp.Description("Specific version to read (default: latest)")),
 			mcp.WithBoolean("include_deleted", mcp.Description("Allow reading deleted documents")),
+			mcp.WithBoolean("expand", mcp.Description("Inline {{include:path}} directives with the referenced documents' content")),
 		),
 		h.readDocumentTool,
 	)
@@ -155,6 +356,7 @@ func registerTools(s *server.MCPServer, h *handlers) {
 			mcp.WithString("content", mcp.Required(), mcp.Description("Document content")),
 			mcp.WithString("author", mcp.Required(), mcp.Description("Author attribution")),
 			mcp.WithString("message", mcp.Description("Version message")),
+			mcp.WithString("content_type", mcp.Description("MIME type, e.g. application/json (default: keep the current type, text/markdown for new documents)")),
 		),
 		h.writeDocument,
 	)
@@ -200,6 +402,7 @@ func registerTools(s *server.MCPServer, h *handlers) {
 			mcp.WithArray("sources", mcp.Required(), mcp.Description("Source paths to move"), mcp.WithStringItems()),
 			mcp.WithString("dest", mcp.Required(), mcp.Description("Destination path or prefix (trailing / for prefix mode)")),
 			mcp.WithString("author", mcp.Required(), mcp.Description("Author attribution")),
+			mcp.WithBoolean("dry_run", mcp.Description("Return the planned moves and any collisions without moving")),
 		),
 		h.moveDocument,
 	)
@@ -212,6 +415,9 @@ func registerTools(s *server.MCPServer, h *handlers) {
 			mcp.WithString("prefix", mcp.Description("Limit search to path prefix")),
 			mcp.WithBoolean("include_deleted", mcp.Description("Include deleted documents")),
 			mcp.WithBoolean("deleted_only", mcp.Description("Search only deleted documents")),
+			mcp.WithBoolean("literal", mcp.Description("Match the query as plain text instead of FTS5 syntax (for terms like C++ or foo-bar)")),
+			mcp.WithNumber("limit", mcp.Description("Return at most this many documents, most relevant first, as {documents, total, offset, has_more}")),
+			mcp.WithNumber("offset", mcp.Description("Skip this many documents first; page with offset += limit while has_more")),
 		),
 		h.searchDocuments,
 	)
@@ -227,6 +433,17 @@ func registerTools(s *server.MCPServer, h *handlers) {
 		h.historyDocument,
 	)
 
+	// Recent
+	s.AddTool(
+		mcp.NewTool("llmd_recent",
+			mcp.WithDescription("List the most recently changed documents, newest first, with the author and message of each latest version. Call this when starting work on a store to see recent activity"),
+			mcp.WithNumber("limit", mcp.Description("Maximum documents to return (default: 10)")),
+			mcp.WithString("since", mcp.Description("Only documents changed after this time (2024-01-15, RFC3339, or 7d)")),
+			mcp.WithString("prefix", mcp.Description("Limit to path prefix")),
+		),
+		h.recentDocuments,
+	)
+
 	// Diff
 	s.AddTool(
 		mcp.NewTool("llmd_diff",
@@ -243,16 +460,45 @@ func registerTools(s *server.MCPServer, h *handlers) {
 	// Edit
 	s.AddTool(
 		mcp.NewTool("llmd_edit",
-			mcp.WithDescription("Edit a document via search/replace (replaces first occurrence)"),
+			mcp.WithDescription("Edit a document via search/replace (replaces first occurrence), or replace a line range or section"),
 			mcp.WithString("path", mcp.Required(), mcp.Description("Document path")),
-			mcp.WithString("old", mcp.Required(), mcp.Description("Text to find")),
+			mcp.WithString("old", mcp.Description("Text to find (give one of old, lines or section)")),
+			mcp.WithString("lines", mcp.Description("Line range to replace with new (e.g. 10:20)")),
+			mcp.WithString("section", mcp.Description("Heading whose section is replaced with new (e.g. ## Install)")),
 			mcp.WithString("new", mcp.Description("Text to replace with")),
+			mcp.WithBoolean("dry_run", mcp.Description("With lines or section, return the old and new lines, resulting content and diff without writing")),
 			mcp.WithString("author", mcp.Required(), mcp.Description("Author attribution")),
 			mcp.WithString("message", mcp.Description("Version message")),
 		),
 		h.editDocument,
 	)
 
+	// Append
+	s.AddTool(
+		mcp.NewTool("llmd_append",
+			mcp.WithDescription("Append content to the end of a document without resending existing content"),
+			mcp.WithString("path", mcp.Required(), mcp.Description("Document path")),
+			mcp.WithString("content", mcp.Required(), mcp.Description("Content to append")),
+			mcp.WithBoolean("dry_run", mcp.Description("Return the resulting content and diff without writing")),
+			mcp.WithString("author", mcp.Required(), mcp.Description("Author attribution")),
+			mcp.WithString("message", mcp.Description("Version message")),
+		),
+		h.appendDocument,
+	)
+
+	// Prepend
+	s.AddTool(
+		mcp.NewTool("llmd_prepend",
+			mcp.WithDescription("Prepend content to the start of a document without resending existing content"),
+			mcp.WithString("path", mcp.Required(), mcp.Description("Document path")),
+			mcp.WithString("content", mcp.Required(), mcp.Description("Content to prepend")),
+			mcp.WithBoolean("dry_run", mcp.Description("Return the resulting content and diff without writing")),
+			mcp.WithString("author", mcp.Required(), mcp.Description("Author attribution")),
+			mcp.WithString("message", mcp.Description("Version message")),
+		),
+		h.prependDocument,
+	)
+
 	// Glob
 	s.AddTool(
 		mcp.NewTool("llmd_glob",
@@ -292,6 +538,9 @@ func registerTools(s *server.MCPServer, h *handlers) {
 			mcp.WithBoolean("flat", mcp.Description("Flatten directory structure")),
 			mcp.WithBoolean("hidden", mcp.Description("Include hidden files/directories")),
 			mcp.WithBoolean("dry_run", mcp.Description("Show what would be imported without importing")),
+			mcp.WithBoolean("frontmatter", mcp.Description("Use path and tags from each file's YAML frontmatter")),
+			mcp.WithBoolean("update", mcp.Description("Skip files whose content matches the latest version; report created/updated/unchanged counts")),
+			mcp.WithArray("extensions", mcp.Description("File extensions to import, each with a leading dot (default: [\".md\"])"), mcp.WithStringItems()),
 		),
 		h.importFiles,
 	)
@@ -304,6 +553,9 @@ func registerTools(s *server.MCPServer, h *handlers) {
 			mcp.WithString("dest", mcp.Required(), mcp.Description("Filesystem destination path")),
 			mcp.WithNumber("version", mcp.Description("Export specific version (for single doc)")),
 			mcp.WithBoolean("force", mcp.Description("Overwrite existing files")),
+			mcp.WithString("as_of", mcp.Description("Export each document as it was at this time (2024-01-15, RFC3339, or 7d)")),
+			mcp.WithString("manifest", mcp.Description("Filesystem path for a JSON manifest of exported paths, keys, versions and hashes")),
+			mcp.WithBoolean("verify", mcp.Description("Re-read each exported file and fail if it does not match the store")),
 		),
 		h.exportFiles,
 	)
@@ -314,6 +566,8 @@ func registerTools(s *server.MCPServer, h *handlers) {
 			mcp.WithDescription("Sync filesystem changes back to database"),
 			mcp.WithString("author", mcp.Required(), mcp.Description("Author attribution")),
 			mcp.WithBoolean("dry_run", mcp.Description("Show what would be synced without syncing")),
+			mcp.WithBoolean("prune", mcp.Description("Soft-delete documents whose files were removed")),
+			mcp.WithBoolean("safe", mcp.Description("Skip files that were also changed in the database since the last sync")),
 			mcp.WithString("message", mcp.Description("Commit message for synced documents")),
 		),
 		h.syncFiles,
@@ -324,9 +578,21 @@ func registerTools(s *server.MCPServer, h *handlers) {
 		mcp.NewTool("llmd_guide",
 			mcp.WithDescription("Get help/guide content for llmd commands"),
 			mcp.WithString("topic", mcp.Description("Guide topic (e.g., 'write', 'ls', 'find') or empty for index")),
+			mcp.WithBoolean("list", mcp.Description("List available topics with a summary of each")),
+			mcp.WithString("search", mcp.Description("List topics containing this term, with matching lines")),
 		),
 		h.getGuide,
 	)
+
+	// Schema
+	s.AddTool(
+		mcp.NewTool("llmd_schema",
+			mcp.WithDescription("Get JSON Schemas for the results other tools return: document, history, link, matching, meta, page and tagged"),
+			mcp.WithString("type", mcp.Description("Result type to describe, or empty for all")),
+		),
+		h.getSchema,
+	)
+
 	// Tag Add
 	s.AddTool(
 		mcp.NewTool("llmd_tag_add",
@@ -349,6 +615,28 @@ func registerTools(s *server.MCPServer, h *handlers) {
 		h.tagRemove,
 	)
 
+	// Find By Tag
+	s.AddTool(
+		mcp.NewTool("llmd_find_by_tag",
+			mcp.WithDescription("List documents having a tag, each with its complete tag list"),
+			mcp.WithString("tag", mcp.Required(), mcp.Description("Tag to find")),
+			mcp.WithString("prefix", mcp.Description("Filter by path prefix")),
+			mcp.WithBoolean("include_deleted", mcp.Description("Include soft-deleted documents")),
+		),
+		h.findByTag,
+	)
+
+	// Tag Rename
+	s.AddTool(
+		mcp.NewTool("llmd_tag_rename",
+			mcp.WithDescription("Rename a tag on every document that has it, merging into the new tag where a document already has both"),
+			mcp.WithString("from", mcp.Required(), mcp.Description("Tag to rename")),
+			mcp.WithString("to", mcp.Required(), mcp.Description("New tag name")),
+			mcp.WithString("author", mcp.Required(), mcp.Description("Author attribution")),
+		),
+		h.tagRename,
+	)
+
 	// List Tags
 	s.AddTool(
 		mcp.NewTool("llmd_tags",
@@ -370,12 +658,24 @@ func registerTools(s *server.MCPServer, h *handlers) {
 		h.sedDocument,
 	)
 
+	// Patch
+	s.AddTool(
+		mcp.NewTool("llmd_patch",
+			mcp.WithDescription("Apply a unified diff to a document. Hunks are located by context, so @@ line numbers may be approximate. Fails without writing if any hunk does not apply"),
+			mcp.WithString("path", mcp.Required(), mcp.Description("Document path")),
+			mcp.WithString("patch", mcp.Required(), mcp.Description("Unified diff with @@ hunks")),
+			mcp.WithString("author", mcp.Required(), mcp.Description("Author attribution")),
+			mcp.WithString("message", mcp.Description("Version message")),
+		),
+		h.patchDocument,
+	)
+
 	// Grep
 	s.AddTool(
 		mcp.NewTool("llmd_grep",
 			mcp.WithDescription("Search documents using regex. For FTS5 full-text search, use llmd_search"),
 			mcp.WithString("pattern", mcp.Required(), mcp.Description("Regex pattern (e.g., 'error|warn', 'TODO.*fix', '[0-9]{3}')")),
-			mcp.WithString("path", mcp.Description("Limit search to path prefix")),
+			mcp.WithString("path", mcp.Description("Limit search to path prefix, or glob pattern (e.g., 'docs/**/api/*')")),
 			mcp.WithBoolean("ignore_case", mcp.Description("Case insensitive search")),
 			mcp.WithBoolean("paths_only", mcp.Description("Only return matching paths")),
 			mcp.WithBoolean("include_deleted", mcp.Description("Include deleted documents")),
@@ -384,6 +684,22 @@ func registerTools(s *server.MCPServer, h *handlers) {
 		h.grepDocuments,
 	)
 
+	// Read matching
+	s.AddTool(
+		mcp.NewTool("llmd_read_matching",
+			mcp.WithDescription("Read only the lines of one document that match a regex, with surrounding context. Cheaper than llmd_read followed by llmd_grep for a large document"),
+			mcp.WithString("path", mcp.Required(), mcp.Description("Document path or 8-character key")),
+			mcp.WithString("pattern", mcp.Required(), mcp.Description("Regex pattern (e.g., 'error|warn', '^## ')")),
+			mcp.WithNumber("context", mcp.Description("Lines of context before and after each match (default: 2)")),
+			mcp.WithBoolean("ignore_case", mcp.Description("Case insensitive search")),
+			mcp.WithBoolean("invert", mcp.Description("Return lines that do not match")),
+			mcp.WithBoolean("multiline", mcp.Description("Match across lines; '.' also matches newlines")),
+			mcp.WithNumber("version", mcp.Description("Specific version to read (default: latest)")),
+			mcp.WithBoolean("include_deleted", mcp.Description("Allow reading a deleted document")),
+		),
+		h.readMatching,
+	)
+
 	// Link
 	s.AddTool(
 		mcp.NewTool("llmd_link",
@@ -391,8 +707,11 @@ func registerTools(s *server.MCPServer, h *handlers) {
 			mcp.WithString("from", mcp.Description("Source document path (required for creating)")),
 			mcp.WithString("to", mcp.Description("Target document path (required for creating)")),
 			mcp.WithString("tag", mcp.Description("Link tag for categorisation")),
+			mcp.WithString("note", mcp.Description("Why the documents are linked (when creating)")),
+			mcp.WithNumber("weight", mcp.Description("Ordering weight; heavier links are listed first (when creating)")),
 			mcp.WithString("author", mcp.Description("Author attribution (required for creating)")),
 			mcp.WithBoolean("list", mcp.Description("List links for 'from' path")),
+			mcp.WithString("direction", mcp.Description("When listing for a path: 'out' (links from it), 'in' (links to it) or 'both' (default)")),
 			mcp.WithBoolean("orphan", mcp.Description("List documents with no links")),
 		),
 		h.linkDocuments,
@@ -419,3 +738,13 @@ func (h *handlers) readDocument(ctx context.Context, req mcp.ReadResourceRequest
 func (h *handlers) readDocumentVersion(ctx context.Context, req mcp.ReadResourceRequest) ([]mcp.ResourceContents, error) {
 	return h.readDocumentResource(ctx, req.Params.URI)
 }
+
+// readHistory handles llmd://history/{path} resource requests.
+func (h *handlers) readHistory(ctx context.Context, req mcp.ReadResourceRequest) ([]mcp.ResourceContents, error) {
+	return h.readHistoryResource(ctx, req.Params.URI)
+}
+
+// readList handles llmd://list/ and llmd://list/{prefix} resource requests.
+func (h *handlers) readList(ctx context.Context, req mcp.ReadResourceRequest) ([]mcp.ResourceContents, error) {
+	return h.readListResource(ctx, req.Params.URI)
+}
@@ -31,6 +31,9 @@ func (h *handlers) diffDocuments(ctx context.Context, req mcp.CallToolRequest) (
 		Version2:       getInt(req, "version2", 0),
 		IncludeDeleted: getBool(req, "include_deleted", false),
 	}
+	if h.redact != nil {
+		opts.Redact = h.mask
+	}
 	author := getString(req, "author", "mcp")
 
 	l := log.Event("mcp:diff", "diff").Author(author).Path(path)
@@ -26,13 +26,17 @@ import (
 	"context"
 	"fmt"
 	"io"
-	"path"
-	"strings"
+	"time"
 
+	"github.com/jpl-au/llmd/internal/duration"
 	"github.com/jpl-au/llmd/internal/edit"
 	"github.com/jpl-au/llmd/internal/log"
 	"github.com/jpl-au/llmd/internal/ls"
+	"github.com/jpl-au/llmd/internal/mv"
+	"github.com/jpl-au/llmd/internal/resolve"
+	"github.com/jpl-au/llmd/internal/revert"
 	"github.com/jpl-au/llmd/internal/store"
+	"github.com/jpl-au/llmd/internal/transclude"
 	"github.com/mark3labs/mcp-go/mcp"
 )
 
@@ -61,10 +65,12 @@ func (h *handlers) listDocuments(ctx context.Context, req mcp.CallToolRequest) (
 
 	// Validate and set sort field
 	sortBy := getString(req, "sort", "")
-	if sortBy != "" && sortBy != "name" && sortBy != "time" {
-		return mcp.NewToolResultError(fmt.Sprintf("invalid sort field %q: must be 'name' or 'time'", sortBy)), nil
+	if sortBy != "" && sortBy != "name" && sortBy != "time" && sortBy != "size" {
+		return mcp.NewToolResultError(fmt.Sprintf("invalid sort field %q: must be 'name', 'time' or 'size'", sortBy)), nil
 	}
 	opts.Sort = ls.SortField(sortBy)
+	limit, offset, paging := paged(req)
+	opts.Limit, opts.Offset = limit, offset
 
 	var err error
 	author := getString(req, "author", "mcp")
@@ -79,9 +85,37 @@ func (h *handlers) listDocuments(ctx context.Context, req mcp.CallToolRequest) (
 
 	l.Detail("count", lsResult.Count())
 
+	if paging {
+		return pageResult(lsResult.ToJSON(), lsResult.Count(), lsResult.Total, offset)
+	}
 	return jsonResult(lsResult.ToJSON())
 }
 
+// resolveInput handles llmd_resolve tool calls.
+func (h *handlers) resolveInput(ctx context.Context, req mcp.CallToolRequest) (*mcp.CallToolResult, error) {
+	if result := h.requireInit(); result != nil {
+		return result, nil
+	}
+
+	var err error
+	input, err := req.RequireString("input")
+	if err != nil {
+		return mcp.NewToolResultError("input is required"), nil
+	}
+	author := getString(req, "author", "mcp")
+
+	l := log.Event("mcp:resolve", "resolve").Author(author).Path(input)
+	defer func() { l.Write(err) }()
+
+	result, err := resolve.Run(ctx, io.Discard, h.svc, input, getBool(req, "include_deleted", false))
+	if err != nil {
+		return mcp.NewToolResultError(err.Error()), nil
+	}
+	l.Resolved(result.ResolvedPath)
+
+	return jsonResult(result)
+}
+
 // readDocumentTool handles llmd_read tool calls.
 //
 // Accepts an array of paths to support batch reads, reducing the number of
@@ -96,6 +130,7 @@ func (h *handlers) listDocuments(ctx context.Context, req mcp.CallToolRequest) (
 // Version and include_deleted parameters apply uniformly to all requested paths,
 // which matches typical use cases where you either want current versions of
 // several documents or are examining historical state at a point in time.
+// So does expand, which inlines {{include:path}} directives.
 func (h *handlers) readDocumentTool(ctx context.Context, req mcp.CallToolRequest) (*mcp.CallToolResult, error) {
 	if result := h.requireInit(); result != nil {
 		return result, nil
@@ -108,6 +143,7 @@ func (h *handlers) readDocumentTool(ctx context.Context, req mcp.CallToolRequest
 
 	version := getInt(req, "version", 0)
 	includeDeleted := getBool(req, "include_deleted", false)
+	expand := getBool(req, "expand", false)
 	author := getString(req, "author", "mcp")
 
 	l := log.Event("mcp:read", "read").Author(author)
@@ -130,7 +166,16 @@ func (h *handlers) readDocumentTool(ctx context.Context, req mcp.CallToolRequest
 		if err != nil {
 			return mcp.NewToolResultError(fmt.Sprintf("read %q: %v", path, err)), nil
 		}
-		docs = append(docs, doc.ToJSON(true))
+		if expand {
+			content, err := transclude.Expand(ctx, h.svc, doc.Path, doc.Content, h.maxExpand)
+			if err != nil {
+				return mcp.NewToolResultError(fmt.Sprintf("read %q: %v", path, err)), nil
+			}
+			expanded := *doc
+			expanded.Content = content
+			doc = &expanded
+		}
+		docs = append(docs, h.redacted(doc).ToJSON(true))
 	}
 
 	// Return single object for single path, array for multiple
@@ -172,12 +217,17 @@ func (h *handlers) writeDocument(ctx context.Context, req mcp.CallToolRequest) (
 		return mcp.NewToolResultError("author is required"), nil
 	}
 
+	if result := h.guard.allow(ctx, len(content)); result != nil {
+		return result, nil
+	}
+
 	message := getString(req, "message", "")
+	contentType := getString(req, "content_type", "")
 
 	l := log.Event("mcp:write", "write").Author(author).Path(path)
 	defer func() { l.Write(err) }()
 
-	err = h.svc.Write(ctx, path, content, author, message)
+	err = h.svc.WriteType(ctx, path, content, contentType, author, message)
 	if err != nil {
 		return mcp.NewToolResultError(fmt.Sprintf("write %q: %v", path, err)), nil
 	}
@@ -401,59 +451,19 @@ func (h *handlers) revertDocument(ctx context.Context, req mcp.CallToolRequest)
 	l := log.Event("mcp:revert", "revert").Author(author).Path(path).Version(version).Detail("key", key)
 	defer func() { l.Write(nil) }()
 
-	var doc *store.Document
-
-	if key != "" {
-		// Revert by key
-		doc, err = h.svc.ByKey(ctx, key)
-		if err != nil {
-			return mcp.NewToolResultError(fmt.Sprintf("key %q: %v", key, err)), nil
-		}
-	} else {
-		// Revert by path + version
-		doc, err = h.svc.Version(ctx, path, version)
-		if err != nil {
-			return mcp.NewToolResultError(fmt.Sprintf("version %d of %q: %v", version, path, err)), nil
-		}
-	}
-
-	// Build message if not provided
-	if message == "" {
-		if key != "" {
-			message = fmt.Sprintf("Revert to %s", key)
-		} else {
-			message = fmt.Sprintf("Revert to v%d", version)
-		}
-	}
-
-	// Write old content as new version
-	if err := h.svc.Write(ctx, doc.Path, doc.Content, author, message); err != nil {
-		return mcp.NewToolResultError(fmt.Sprintf("write reverted content: %v", err)), nil
-	}
-
-	// Get new version number
-	newDoc, err := h.svc.Latest(ctx, doc.Path, false)
+	// Shares revert.Run with the CLI so validation, the deleted-document
+	// check and the default message stay identical across both surfaces
+	result, err := revert.Run(ctx, io.Discard, h.svc, path, version, revert.Options{
+		Author:  author,
+		Message: message,
+		Key:     key,
+	})
 	if err != nil {
-		return mcp.NewToolResultError(fmt.Sprintf("get new version: %v", err)), nil
-	}
-
-	l.Resolved(doc.Path).ResultVersion(newDoc.Version)
-
-	type revertResult struct {
-		Path       string `json:"path"`
-		RevertedTo int    `json:"reverted_to"`
-		NewVersion int    `json:"new_version"`
-		Key        string `json:"key,omitempty"`
-		Message    string `json:"message"`
+		return mcp.NewToolResultError(err.Error()), nil
 	}
 
-	return jsonResult(revertResult{
-		Path:       doc.Path,
-		RevertedTo: doc.Version,
-		NewVersion: newDoc.Version,
-		Key:        doc.Key,
-		Message:    message,
-	})
+	l.Resolved(result.Path).ResultVersion(result.NewVersion)
+	return jsonResult(result)
 }
 
 // moveDocument handles llmd_move tool calls.
@@ -465,10 +475,14 @@ func (h *handlers) revertDocument(ctx context.Context, req mcp.CallToolRequest)
 // Supports Unix mv semantics: with multiple sources or a destination ending
 // in /, the destination is treated as a prefix and sources are moved under it
 // preserving their base names (docs/readme -> archive/readme). With a single
-// source and no trailing slash, it's a simple rename.
+// source and no trailing slash, it's a simple rename. All moves apply in
+// one transaction, so a collision on any source leaves every source in place.
+// The mapping and the move are mv.Run, which the CLI mv command also uses.
 //
 // The response format adapts to the request: single source returns a plain
 // object with from/to, while multiple sources return an array of results.
+// With dry_run, nothing moves and the result is the plan from mv.NewPlan:
+// every mapping plus the ones that would collide.
 func (h *handlers) moveDocument(ctx context.Context, req mcp.CallToolRequest) (*mcp.CallToolResult, error) {
 	if result := h.requireInit(); result != nil {
 		return result, nil
@@ -489,11 +503,15 @@ func (h *handlers) moveDocument(ctx context.Context, req mcp.CallToolRequest) (*
 		return mcp.NewToolResultError("author is required"), nil
 	}
 
-	// Determine if this is "move into prefix" mode:
-	// - Multiple sources always require prefix mode
-	// - Trailing slash signals prefix mode even with single source
-	prefixMode := len(sources) > 1 || strings.HasSuffix(dest, "/")
-	destPrefix := strings.TrimSuffix(dest, "/")
+	// A dry run reports every mapping and collision up front, so a batch
+	// can be checked before it fails halfway.
+	if getBool(req, "dry_run", false) {
+		plan, err := mv.NewPlan(ctx, h.svc, sources, dest)
+		if err != nil {
+			return mcp.NewToolResultError(fmt.Sprintf("move: %v", err)), nil
+		}
+		return jsonResult(plan)
+	}
 
 	l := log.Event("mcp:move", "move").Author(author)
 	if len(sources) == 1 {
@@ -504,34 +522,18 @@ func (h *handlers) moveDocument(ctx context.Context, req mcp.CallToolRequest) (*
 	l.Detail("dest", dest)
 	defer func() { l.Detail("count", len(sources)).Write(nil) }()
 
-	type moveResult struct {
-		From string `json:"from"`
-		To   string `json:"to"`
-	}
-	var results []moveResult
-
-	for _, src := range sources {
-		var target string
-		if prefixMode {
-			// Move into prefix: docs/readme -> archive/readme
-			base := path.Base(src)
-			target = path.Join(destPrefix, base)
-		} else {
-			// Direct rename
-			target = dest
-		}
-
-		if err := h.svc.Move(ctx, src, target); err != nil {
-			return mcp.NewToolResultError(fmt.Sprintf("move %s: %v", src, err)), nil
-		}
-		results = append(results, moveResult{From: src, To: target})
+	// With multiple sources or a trailing slash, sources move into dest.
+	// One transaction: a collision on any source leaves all of them in place.
+	moves, err := mv.Run(ctx, h.svc, sources, dest)
+	if err != nil {
+		return mcp.NewToolResultError(fmt.Sprintf("move: %v", err)), nil
 	}
 
 	// Return single object for single move, array for multiple
-	if len(results) == 1 {
-		return jsonResult(results[0])
+	if len(moves) == 1 {
+		return jsonResult(moves[0])
 	}
-	return jsonResult(results)
+	return jsonResult(moves)
 }
 
 // historyDocument handles llmd_history tool calls.
@@ -590,6 +592,51 @@ func (h *handlers) historyDocument(ctx context.Context, req mcp.CallToolRequest)
 	return jsonResult(historyResult)
 }
 
+// defaultRecent is how many documents llmd_recent returns without a limit.
+const defaultRecent = 10
+
+// recentDocuments handles llmd_recent tool calls.
+//
+// An assistant reconnecting to a store has no idea what changed while it
+// was away. This returns the most recently changed documents with the
+// author and message of their latest version, so it can summarise recent
+// activity in one call before deciding what to read. since narrows this to
+// changes after a point in time, such as the end of the previous session.
+func (h *handlers) recentDocuments(ctx context.Context, req mcp.CallToolRequest) (*mcp.CallToolResult, error) {
+	if result := h.requireInit(); result != nil {
+		return result, nil
+	}
+
+	var err error
+	prefix := getString(req, "prefix", "")
+	limit := getInt(req, "limit", defaultRecent)
+	if limit <= 0 {
+		return mcp.NewToolResultError(fmt.Sprintf("limit must be > 0, got %d", limit)), nil
+	}
+	var since time.Time
+	if s := getString(req, "since", ""); s != "" {
+		if since, err = duration.ParseTime(s, time.Now()); err != nil {
+			return mcp.NewToolResultError(err.Error()), nil
+		}
+	}
+	author := getString(req, "author", "mcp")
+
+	l := log.Event("mcp:recent", "list").Author(author).Path(prefix).Detail("limit", limit)
+	defer func() { l.Write(err) }()
+
+	metas, err := h.svc.Recent(ctx, prefix, since, limit)
+	if err != nil {
+		return mcp.NewToolResultError(fmt.Sprintf("recent documents: %v", err)), nil
+	}
+	l.Detail("count", len(metas))
+
+	out := make([]ls.MetaJSON, len(metas))
+	for i, m := range metas {
+		out[i] = ls.ToMetaJSON(m)
+	}
+	return jsonResult(out)
+}
+
 // editDocument handles llmd_edit tool calls.
 //
 // Provides search-and-replace editing, which is often more efficient than
@@ -603,6 +650,11 @@ func (h *handlers) historyDocument(ctx context.Context, req mcp.CallToolRequest)
 // expected text isn't found (avoiding accidental overwrites of concurrent
 // changes).
 //
+// Instead of old, lines or section replaces a line range with new, as
+// "edit -l" and "edit --section" do. Those take dry_run, which returns the
+// edit.Result preview (old and new lines, resulting content and diff)
+// without writing, so an off-by-one range can be caught first.
+//
 // The edit is delegated to internal/edit which handles the matching and
 // replacement logic, ensuring consistency with CLI edit behaviour.
 func (h *handlers) editDocument(ctx context.Context, req mcp.CallToolRequest) (*mcp.CallToolResult, error) {
@@ -616,30 +668,76 @@ func (h *handlers) editDocument(ctx context.Context, req mcp.CallToolRequest) (*
 		return mcp.NewToolResultError("path is required"), nil
 	}
 
-	old, err := req.RequireString("old")
-	if err != nil {
-		return mcp.NewToolResultError("old is required"), nil
-	}
-
 	author, err := req.RequireString("author")
 	if err != nil {
 		return mcp.NewToolResultError("author is required"), nil
 	}
 
-	opts := edit.Options{
-		Old:     old,
-		New:     getString(req, "new", ""),
-		Author:  author,
-		Message: getString(req, "message", ""),
+	old := getString(req, "old", "")
+	lines := getString(req, "lines", "")
+	section := getString(req, "section", "")
+	dryRun := getBool(req, "dry_run", false)
+	given := 0
+	for _, s := range []string{old, lines, section} {
+		if s != "" {
+			given++
+		}
+	}
+	if given != 1 {
+		return mcp.NewToolResultError("give exactly one of old, lines or section"), nil
 	}
+	if dryRun && old != "" {
+		return mcp.NewToolResultError("dry_run requires lines or section"), nil
+	}
+
+	repl := getString(req, "new", "")
+	// A dry run writes nothing, so it is not held to the write guards
+	if !dryRun {
+		if result := h.guard.allow(ctx, len(repl)); result != nil {
+			return result, nil
+		}
+	}
+	message := getString(req, "message", "")
 
-	l := log.Event("mcp:edit", "edit").Author(opts.Author).Path(path)
+	l := log.Event("mcp:edit", "edit").Author(author).Path(path)
 	defer func() { l.Write(err) }()
 
-	err = h.svc.Edit(ctx, path, opts)
+	if old != "" {
+		opts := edit.Options{
+			Old:     old,
+			New:     repl,
+			Author:  author,
+			Message: message,
+		}
+		err = h.svc.Edit(ctx, path, opts)
+		if err != nil {
+			return mcp.NewToolResultError(fmt.Sprintf("edit %q: %v", path, err)), nil
+		}
+		return mcp.NewToolResultText(fmt.Sprintf("edited %s", path)), nil
+	}
+
+	opts := edit.LineRangeOptions{
+		Section: section,
+		Author:  author,
+		Message: message,
+		DryRun:  dryRun,
+	}
+	if lines != "" {
+		if opts.Start, opts.End, err = edit.ParseLineRange(lines); err != nil {
+			return mcp.NewToolResultError(fmt.Sprintf("parse line range %q: %v", lines, err)), nil
+		}
+	}
+	if dryRun {
+		l.Detail("dry_run", true)
+	}
+
+	r, err := edit.RunLineRange(ctx, io.Discard, h.svc, path, repl, opts)
 	if err != nil {
 		return mcp.NewToolResultError(fmt.Sprintf("edit %q: %v", path, err)), nil
 	}
-
-	return mcp.NewToolResultText(fmt.Sprintf("edited %s", path)), nil
+	l.Resolved(r.Path)
+	if dryRun {
+		return h.preview(r)
+	}
+	return mcp.NewToolResultText(fmt.Sprintf("edited %s", r.Path)), nil
 }
@@ -9,7 +9,9 @@ package mcp
 import (
 	"bytes"
 	"context"
+	"time"
 
+	"github.com/jpl-au/llmd/internal/duration"
 	"github.com/jpl-au/llmd/internal/exporter"
 	"github.com/jpl-au/llmd/internal/log"
 	"github.com/mark3labs/mcp-go/mcp"
@@ -33,8 +35,19 @@ func (h *handlers) exportFiles(ctx context.Context, req mcp.CallToolRequest) (*m
 	}
 
 	opts := exporter.Options{
-		Version: getInt(req, "version", 0),
-		Force:   getBool(req, "force", false),
+		Version:  getInt(req, "version", 0),
+		Force:    getBool(req, "force", false),
+		Manifest: getString(req, "manifest", ""),
+		Verify:   getBool(req, "verify", false),
+	}
+	if asOf := getString(req, "as_of", ""); asOf != "" {
+		if opts.Version > 0 {
+			return mcp.NewToolResultError("as_of cannot be combined with version"), nil
+		}
+		opts.AsOf, err = duration.ParseTime(asOf, time.Now())
+		if err != nil {
+			return mcp.NewToolResultError(err.Error()), nil
+		}
 	}
 	author := getString(req, "author", "mcp")
 
@@ -49,8 +62,15 @@ func (h *handlers) exportFiles(ctx context.Context, req mcp.CallToolRequest) (*m
 
 	l.Detail("count", exportResult.Exported)
 
-	return jsonResult(map[string]any{
+	out := map[string]any{
 		"exported": exportResult.Exported,
 		"paths":    exportResult.Paths,
-	})
+	}
+	if opts.Manifest != "" {
+		out["manifest"] = opts.Manifest
+	}
+	if opts.Verify {
+		out["verified"] = true
+	}
+	return jsonResult(out)
 }
@@ -2,6 +2,8 @@
 //
 // The guide tool provides LLMs with documentation about llmd commands
 // and usage patterns, enabling self-service help without external lookups.
+// Listing and searching let an LLM discover which topic to read instead of
+// guessing names.
 
 package mcp
 
@@ -18,11 +20,30 @@ import (
 func (h *handlers) getGuide(ctx context.Context, req mcp.CallToolRequest) (*mcp.CallToolResult, error) { //nolint:revive // ctx for future use
 	var err error
 	topic := getString(req, "topic", "")
+	search := getString(req, "search", "")
 	author := getString(req, "author", "mcp")
 
-	l := log.Event("mcp:guide", "read").Author(author).Detail("topic", topic)
+	l := log.Event("mcp:guide", "read").Author(author).Detail("topic", topic).Detail("search", search)
 	defer func() { l.Write(err) }()
 
+	switch {
+	case getBool(req, "list", false):
+		var topics []guide.Topic
+		if topics, err = guide.Topics(); err != nil {
+			return mcp.NewToolResultError(fmt.Sprintf("listing guides: %v", err)), nil
+		}
+		return jsonResult(topics)
+	case search != "":
+		var matches []guide.Match
+		if matches, err = guide.Search(search); err != nil {
+			return mcp.NewToolResultError(fmt.Sprintf("searching guides: %v", err)), nil
+		}
+		if matches == nil {
+			matches = []guide.Match{}
+		}
+		return jsonResult(matches)
+	}
+
 	content, err := guide.Get(topic)
 	if err != nil {
 		// If topic not found, return list of available topics
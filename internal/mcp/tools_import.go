@@ -34,11 +34,14 @@ func (h *handlers) importFiles(ctx context.Context, req mcp.CallToolRequest) (*m
 	}
 
 	opts := importer.Options{
-		Prefix: getString(req, "prefix", ""),
-		Flat:   getBool(req, "flat", false),
-		Hidden: getBool(req, "hidden", false),
-		DryRun: getBool(req, "dry_run", false),
-		Author: author,
+		Prefix:      getString(req, "prefix", ""),
+		Flat:        getBool(req, "flat", false),
+		Hidden:      getBool(req, "hidden", false),
+		DryRun:      getBool(req, "dry_run", false),
+		Author:      author,
+		Frontmatter: getBool(req, "frontmatter", false),
+		Update:      getBool(req, "update", false),
+		Extensions:  getStrings(req, "extensions"),
 	}
 
 	l := log.Event("mcp:import", "import").Author(author).Detail("source", path)
@@ -52,9 +55,15 @@ func (h *handlers) importFiles(ctx context.Context, req mcp.CallToolRequest) (*m
 
 	l.Detail("count", importResult.Imported)
 
-	return jsonResult(map[string]any{
+	out := map[string]any{
 		"imported": importResult.Imported,
 		"paths":    importResult.Paths,
 		"dry_run":  opts.DryRun,
-	})
+	}
+	if opts.Update {
+		out["created"] = importResult.Created
+		out["updated"] = importResult.Updated
+		out["unchanged"] = importResult.Unchanged
+	}
+	return jsonResult(out)
 }
@@ -0,0 +1,91 @@
+// tools_lines.go implements MCP tools for line-oriented document edits.
+//
+// Separated from tools_documents.go because these tools add content at a
+// position rather than matching existing text. Appending a section with
+// llmd_write means resending the whole document; these tools transmit only
+// the new lines, which matters for large documents and token budgets.
+
+package mcp
+
+import (
+	"context"
+	"fmt"
+	"io"
+
+	"github.com/jpl-au/llmd/internal/edit"
+	"github.com/jpl-au/llmd/internal/log"
+	"github.com/mark3labs/mcp-go/mcp"
+)
+
+// appendDocument handles llmd_append tool calls.
+func (h *handlers) appendDocument(ctx context.Context, req mcp.CallToolRequest) (*mcp.CallToolResult, error) {
+	return h.boundaryEdit(ctx, req, "append", edit.ModeAppend)
+}
+
+// prependDocument handles llmd_prepend tool calls.
+func (h *handlers) prependDocument(ctx context.Context, req mcp.CallToolRequest) (*mcp.CallToolResult, error) {
+	return h.boundaryEdit(ctx, req, "prepend", edit.ModePrepend)
+}
+
+// boundaryEdit adds content at the start or end of a document. Both tools
+// share parameters and differ only in mode, so they share one handler.
+func (h *handlers) boundaryEdit(ctx context.Context, req mcp.CallToolRequest, name string, mode edit.Mode) (*mcp.CallToolResult, error) {
+	if result := h.requireInit(); result != nil {
+		return result, nil
+	}
+
+	var err error
+	path, err := req.RequireString("path")
+	if err != nil {
+		return mcp.NewToolResultError("path is required"), nil
+	}
+
+	content, err := req.RequireString("content")
+	if err != nil || content == "" {
+		return mcp.NewToolResultError("content is required"), nil
+	}
+
+	author, err := req.RequireString("author")
+	if err != nil {
+		return mcp.NewToolResultError("author is required"), nil
+	}
+
+	dryRun := getBool(req, "dry_run", false)
+	// A dry run writes nothing, so it is not held to the write guards
+	if !dryRun {
+		if result := h.guard.allow(ctx, len(content)); result != nil {
+			return result, nil
+		}
+	}
+
+	opts := edit.LineRangeOptions{
+		Mode:    mode,
+		Author:  author,
+		Message: getString(req, "message", ""),
+		DryRun:  dryRun,
+	}
+
+	l := log.Event("mcp:"+name, "edit").Author(author).Path(path)
+	defer func() { l.Write(err) }()
+	if dryRun {
+		l.Detail("dry_run", true)
+	}
+
+	r, err := edit.RunLineRange(ctx, io.Discard, h.svc, path, content, opts)
+	if err != nil {
+		return mcp.NewToolResultError(fmt.Sprintf("%s %q: %v", name, path, err)), nil
+	}
+	l.Resolved(r.Path)
+	if dryRun {
+		return h.preview(r)
+	}
+
+	return mcp.NewToolResultText(fmt.Sprintf("edited %s", r.Path)), nil
+}
+
+// preview returns the result of a dry-run line edit as JSON, masking the
+// document text it carries when redact.mcp is on.
+func (h *handlers) preview(r edit.Result) (*mcp.CallToolResult, error) {
+	r.Old, r.Content, r.Diff = h.mask(r.Old), h.mask(r.Content), h.mask(r.Diff)
+	return jsonResult(r)
+}
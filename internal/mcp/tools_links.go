@@ -30,6 +30,7 @@ func (h *handlers) linkDocuments(ctx context.Context, req mcp.CallToolRequest) (
 	tag := getString(req, "tag", "")
 	list := getBool(req, "list", false)
 	orphan := getBool(req, "orphan", false)
+	direction := getString(req, "direction", store.DirectionBoth)
 	author := getString(req, "author", "mcp") // Optional for list operations, required for create
 
 	// List orphans
@@ -71,11 +72,11 @@ func (h *handlers) linkDocuments(ctx context.Context, req mcp.CallToolRequest) (
 		}
 
 		// List for path
-		l := log.Event("mcp:link", "list").Author(author).Path(from).Detail("tag", tag)
+		l := log.Event("mcp:link", "list").Author(author).Path(from).Detail("tag", tag).Detail("direction", direction)
 		defer func() { l.Write(err) }()
 
 		var links []store.Link
-		links, err = h.svc.ListLinks(ctx, from, tag, store.NewLinkOptions())
+		links, err = h.svc.ListLinks(ctx, from, tag, store.NewLinkOptions().WithDirection(direction))
 		if err != nil {
 			return mcp.NewToolResultError(err.Error()), nil
 		}
@@ -100,9 +101,19 @@ func (h *handlers) linkDocuments(ctx context.Context, req mcp.CallToolRequest) (
 	}
 
 	l := log.Event("mcp:link", "link").Author(author).Path(from).Detail("to", to).Detail("tag", tag)
+
+	// Only arguments given replace what an existing link has
+	opts := store.NewLinkOptions()
+	if hasArg(req, "note") {
+		opts = opts.WithNote(getString(req, "note", ""))
+	}
+	if hasArg(req, "weight") {
+		opts = opts.WithWeight(getInt(req, "weight", 0))
+		l.Detail("weight", *opts.Weight)
+	}
 	defer func() { l.Write(err) }()
 
-	id, err := h.svc.Link(ctx, from, to, tag, store.NewLinkOptions())
+	id, err := h.svc.Link(ctx, from, to, tag, opts)
 	if err != nil {
 		return mcp.NewToolResultError(err.Error()), nil
 	}
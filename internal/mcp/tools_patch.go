@@ -0,0 +1,58 @@
+// tools_patch.go implements the MCP tool for applying unified diffs.
+//
+// A diff touching a few lines is far cheaper to send than the whole
+// document, and it is the format models most naturally produce for edits.
+
+package mcp
+
+import (
+	"context"
+	"fmt"
+	"io"
+
+	"github.com/jpl-au/llmd/internal/log"
+	"github.com/jpl-au/llmd/internal/patch"
+	"github.com/mark3labs/mcp-go/mcp"
+)
+
+// patchDocument handles llmd_patch tool calls.
+func (h *handlers) patchDocument(ctx context.Context, req mcp.CallToolRequest) (*mcp.CallToolResult, error) {
+	if result := h.requireInit(); result != nil {
+		return result, nil
+	}
+
+	var err error
+	path, err := req.RequireString("path")
+	if err != nil {
+		return mcp.NewToolResultError("path is required"), nil
+	}
+
+	diff, err := req.RequireString("patch")
+	if err != nil {
+		return mcp.NewToolResultError("patch is required"), nil
+	}
+
+	author, err := req.RequireString("author")
+	if err != nil {
+		return mcp.NewToolResultError("author is required"), nil
+	}
+
+	if result := h.guard.allow(ctx, len(diff)); result != nil {
+		return result, nil
+	}
+
+	opts := patch.Options{
+		Author:  author,
+		Message: getString(req, "message", ""),
+	}
+
+	l := log.Event("mcp:patch", "edit").Author(opts.Author).Path(path)
+	defer func() { l.Write(err) }()
+
+	result, err := patch.Run(ctx, io.Discard, h.svc, path, diff, opts)
+	if err != nil {
+		return mcp.NewToolResultError(err.Error()), nil
+	}
+
+	return mcp.NewToolResultText(fmt.Sprintf("patched %s (%d hunks)", result.Path, result.Hunks)), nil
+}
@@ -0,0 +1,158 @@
+// tools_schema.go implements the MCP tool that describes the shape of tool
+// results.
+//
+// Most tools return JSON built from a handful of structs (DocJSON, LinkJSON,
+// MetaJSON). Telling an LLM their exact fields up front saves it guessing
+// from examples, and lets typed clients validate what they receive.
+//
+// Design: Schemas are generated by reflection from the structs the tools
+// marshal rather than written by hand, so a field added to DocJSON shows up
+// in llmd_schema without anyone remembering to update it. Only the types
+// these structs use are supported: strings, numbers, booleans, slices,
+// pointers and nested or embedded structs. A field tagged omitempty is
+// optional; every other field is required.
+
+package mcp
+
+import (
+	"context"
+	"fmt"
+	"maps"
+	"reflect"
+	"slices"
+	"strings"
+
+	"github.com/jpl-au/llmd/internal/log"
+	"github.com/jpl-au/llmd/internal/ls"
+	"github.com/jpl-au/llmd/internal/store"
+	"github.com/jpl-au/llmd/internal/tag"
+	"github.com/mark3labs/mcp-go/mcp"
+)
+
+// resultType is a result shape llmd_schema can describe.
+type resultType struct {
+	desc string       // What it is and which tools return it
+	typ  reflect.Type // Go type marshalled as the result
+}
+
+// resultTypes are the result shapes shared between tools, by name.
+var resultTypes = map[string]resultType{
+	"document": {
+		"A document version. Returned by llmd_read (an array for several paths), llmd_search and llmd_grep with content, and by llmd_list without",
+		reflect.TypeFor[store.DocJSON](),
+	},
+	"history": {
+		"Versions of a document, newest first, without content. Returned by llmd_history and the llmd://history/{path} resource",
+		reflect.TypeFor[[]store.DocJSON](),
+	},
+	"link": {
+		"A link between two documents. llmd_link returns an array of them when listing",
+		reflect.TypeFor[store.LinkJSON](),
+	},
+	"matching": {
+		"Excerpts of one document around the lines matching a pattern. Returned by llmd_read_matching",
+		reflect.TypeFor[matchingJSON](),
+	},
+	"meta": {
+		"Document metadata with its size, without content. llmd_recent and the llmd://list/ resources return an array of them",
+		reflect.TypeFor[ls.MetaJSON](),
+	},
+	"page": {
+		"One page of results, returned by llmd_list and llmd_search when given limit or offset. documents holds what the tool returns unpaged: document objects, most relevant first for llmd_search, or meta objects for llmd_list sorted by size",
+		reflect.TypeFor[pageJSON](),
+	},
+	"tagged": {
+		"A document without content and with all its tags. llmd_find_by_tag returns an array of them",
+		reflect.TypeFor[tag.Tagged](),
+	},
+}
+
+// getSchema handles llmd_schema tool calls.
+func (h *handlers) getSchema(ctx context.Context, req mcp.CallToolRequest) (*mcp.CallToolResult, error) { //nolint:revive // ctx for future use
+	var err error
+	name := getString(req, "type", "")
+	author := getString(req, "author", "mcp")
+
+	l := log.Event("mcp:schema", "read").Author(author).Detail("type", name)
+	defer func() { l.Write(err) }()
+
+	if name == "" {
+		all := make(map[string]map[string]any, len(resultTypes))
+		for n, rt := range resultTypes {
+			all[n] = rt.schema(n)
+		}
+		return jsonResult(all)
+	}
+	rt, ok := resultTypes[name]
+	if !ok {
+		err = fmt.Errorf("unknown result type %q", name)
+		names := slices.Sorted(maps.Keys(resultTypes))
+		return mcp.NewToolResultError(fmt.Sprintf("%v (available: %s)", err, strings.Join(names, ", "))), nil
+	}
+	return jsonResult(rt.schema(name))
+}
+
+// schema returns the JSON Schema of the result type.
+func (rt resultType) schema(name string) map[string]any {
+	s := jsonSchema(rt.typ)
+	s["$schema"] = "https://json-schema.org/draft/2020-12/schema"
+	s["title"] = name
+	s["description"] = rt.desc
+	return s
+}
+
+// jsonSchema returns the JSON Schema of values of t as encoding/json
+// marshals them.
+func jsonSchema(t reflect.Type) map[string]any {
+	switch t.Kind() {
+	case reflect.Pointer:
+		return jsonSchema(t.Elem())
+	case reflect.String:
+		return map[string]any{"type": "string"}
+	case reflect.Bool:
+		return map[string]any{"type": "boolean"}
+	case reflect.Int, reflect.Int8, reflect.Int16, reflect.Int32, reflect.Int64,
+		reflect.Uint, reflect.Uint8, reflect.Uint16, reflect.Uint32, reflect.Uint64:
+		return map[string]any{"type": "integer"}
+	case reflect.Float32, reflect.Float64:
+		return map[string]any{"type": "number"}
+	case reflect.Slice, reflect.Array:
+		return map[string]any{"type": "array", "items": jsonSchema(t.Elem())}
+	case reflect.Map:
+		return map[string]any{"type": "object", "additionalProperties": jsonSchema(t.Elem())}
+	case reflect.Struct:
+		props := map[string]any{}
+		required := []string{}
+		addFields(t, props, &required)
+		return map[string]any{"type": "object", "properties": props, "required": required}
+	}
+	return map[string]any{}
+}
+
+// addFields adds the JSON properties of struct t to props, flattening
+// embedded structs as encoding/json does, and appends those without
+// omitempty to required.
+func addFields(t reflect.Type, props map[string]any, required *[]string) {
+	for i := range t.NumField() {
+		f := t.Field(i)
+		if !f.IsExported() {
+			continue
+		}
+		jsonTag := f.Tag.Get("json")
+		if jsonTag == "-" {
+			continue
+		}
+		name, opts, _ := strings.Cut(jsonTag, ",")
+		if f.Anonymous && name == "" && f.Type.Kind() == reflect.Struct {
+			addFields(f.Type, props, required)
+			continue
+		}
+		if name == "" {
+			name = f.Name
+		}
+		props[name] = jsonSchema(f.Type)
+		if !slices.Contains(strings.Split(opts, ","), "omitempty") {
+			*required = append(*required, name)
+		}
+	}
+}
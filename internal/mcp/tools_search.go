@@ -2,13 +2,18 @@
 //
 // These tools help LLMs locate content: FTS5 full-text search, glob pattern
 // matching for paths, and regex grep for content. All return results as JSON
-// arrays for easy parsing.
+// arrays for easy parsing; llmd_search given limit or offset returns a page
+// object instead, most relevant first. llmd_read_matching greps a single
+// known document, returning only the matching lines and their context so
+// an LLM need not read a large document in full to find what it wants.
 
 package mcp
 
 import (
 	"bytes"
 	"context"
+	"fmt"
+	"strings"
 
 	"github.com/jpl-au/llmd/internal/grep"
 	"github.com/jpl-au/llmd/internal/log"
@@ -31,12 +36,25 @@ func (h *handlers) searchDocuments(ctx context.Context, req mcp.CallToolRequest)
 	prefix := getString(req, "prefix", "")
 	includeDeleted := getBool(req, "include_deleted", false)
 	deletedOnly := getBool(req, "deleted_only", false)
+	literal := getBool(req, "literal", false)
 	author := getString(req, "author", "mcp")
 
 	l := log.Event("mcp:search", "search").Author(author).Path(prefix).Detail("query", query)
 	defer func() { l.Write(err) }()
 
-	docs, err := h.svc.Search(ctx, query, prefix, includeDeleted, deletedOnly)
+	match := query
+	if literal {
+		match = store.LiteralQuery(query)
+	}
+
+	limit, offset, paging := paged(req)
+	var docs []store.Document
+	total := 0
+	if paging {
+		docs, total, err = h.svc.SearchPage(ctx, match, prefix, includeDeleted, deletedOnly, limit, offset)
+	} else {
+		docs, err = h.svc.Search(ctx, match, prefix, includeDeleted, deletedOnly)
+	}
 	if err != nil {
 		return mcp.NewToolResultError(err.Error()), nil
 	}
@@ -45,9 +63,12 @@ func (h *handlers) searchDocuments(ctx context.Context, req mcp.CallToolRequest)
 
 	searchResult := make([]store.DocJSON, len(docs))
 	for i := range docs {
-		searchResult[i] = docs[i].ToJSON(true)
+		searchResult[i] = h.redacted(&docs[i]).ToJSON(true)
 	}
 
+	if paging {
+		return pageResult(searchResult, len(searchResult), total, offset)
+	}
 	return jsonResult(searchResult)
 }
 
@@ -105,12 +126,118 @@ func (h *handlers) grepDocuments(ctx context.Context, req mcp.CallToolRequest) (
 		return mcp.NewToolResultError(err.Error()), nil
 	}
 
-	l.Detail("count", len(grepResult.Documents))
-
-	docs := make([]store.DocJSON, len(grepResult.Documents))
+	// Match again after masking, as llmd_read_matching does, so a pattern
+	// cannot confirm a secret by which documents it finds.
+	docs := make([]store.DocJSON, 0, len(grepResult.Documents))
 	for i := range grepResult.Documents {
-		docs[i] = grepResult.Documents[i].ToJSON(true)
+		doc := h.redacted(&grepResult.Documents[i])
+		if h.redact != nil {
+			matches, err := grep.Document(*doc, pattern, opts)
+			if err != nil {
+				return mcp.NewToolResultError(err.Error()), nil
+			}
+			if len(matches) == 0 {
+				continue
+			}
+		}
+		docs = append(docs, doc.ToJSON(true))
 	}
 
+	l.Detail("count", len(docs))
+
 	return jsonResult(docs)
 }
+
+// matchingJSON is the result of llmd_read_matching.
+type matchingJSON struct {
+	Path     string         `json:"path"`
+	Key      string         `json:"key"`
+	Version  int            `json:"version"`
+	Lines    int            `json:"lines"`   // Lines in the whole document
+	Matches  int            `json:"matches"` // Matching lines
+	Excerpts []grep.Excerpt `json:"excerpts"`
+}
+
+// readMatching handles llmd_read_matching tool calls.
+//
+// Reading a document and then grepping it costs two round-trips and puts
+// the whole document in the LLM's context. This resolves the path (or key)
+// as llmd_read does and runs the grep engine over that one document,
+// returning only the excerpts around matches. Redaction applies before
+// matching, so a pattern cannot reveal a masked secret.
+func (h *handlers) readMatching(ctx context.Context, req mcp.CallToolRequest) (*mcp.CallToolResult, error) {
+	if result := h.requireInit(); result != nil {
+		return result, nil
+	}
+
+	var err error
+	path, err := req.RequireString("path")
+	if err != nil {
+		return mcp.NewToolResultError("path is required"), nil
+	}
+	pattern, err := req.RequireString("pattern")
+	if err != nil {
+		return mcp.NewToolResultError("pattern is required"), nil
+	}
+	around := getInt(req, "context", 2)
+	if around < 0 {
+		return mcp.NewToolResultError(fmt.Sprintf("context must be >= 0, got %d", around)), nil
+	}
+	version := getInt(req, "version", 0)
+	author := getString(req, "author", "mcp")
+
+	l := log.Event("mcp:read_matching", "search").Author(author).Path(path).Detail("pattern", pattern)
+	defer func() { l.Write(err) }()
+
+	var doc *store.Document
+	if version > 0 {
+		doc, err = h.svc.Version(ctx, path, version)
+	} else {
+		doc, _, err = h.svc.Resolve(ctx, path, getBool(req, "include_deleted", false))
+	}
+	if err != nil {
+		return mcp.NewToolResultError(fmt.Sprintf("read %q: %v", path, err)), nil
+	}
+	l.Resolved(doc.Path)
+	scanned := h.redacted(doc)
+	content := scanned.Content
+
+	matches, err := grep.Document(*scanned, pattern, grep.Options{
+		IgnoreCase:    getBool(req, "ignore_case", false),
+		Invert:        getBool(req, "invert", false),
+		Multiline:     getBool(req, "multiline", false),
+		MaxLineLength: h.svc.MaxLineLength(),
+	})
+	if err != nil {
+		return mcp.NewToolResultError(err.Error()), nil
+	}
+
+	excerpts := grep.Excerpts(content, matches, around)
+	n := 0
+	for _, ex := range excerpts {
+		n += len(ex.Matched)
+	}
+	l.Detail("count", n)
+
+	if excerpts == nil {
+		excerpts = []grep.Excerpt{}
+	}
+	return jsonResult(matchingJSON{
+		Path:     doc.Path,
+		Key:      doc.Key,
+		Version:  doc.Version,
+		Lines:    lineCount(content),
+		Matches:  n,
+		Excerpts: excerpts,
+	})
+}
+
+// lineCount returns the number of lines in content, not counting the empty
+// remainder after a final newline.
+func lineCount(content string) int {
+	n := strings.Count(content, "\n")
+	if content != "" && !strings.HasSuffix(content, "\n") {
+		n++
+	}
+	return n
+}
@@ -37,6 +37,10 @@ func (h *handlers) sedDocument(ctx context.Context, req mcp.CallToolRequest) (*m
 		return mcp.NewToolResultError("author is required"), nil
 	}
 
+	if result := h.guard.allow(ctx, len(expr)); result != nil {
+		return result, nil
+	}
+
 	opts := sed.Options{
 		Author:  author,
 		Message: getString(req, "message", ""),
@@ -50,6 +50,9 @@ func (h *handlers) syncFiles(ctx context.Context, req mcp.CallToolRequest) (*mcp
 
 	opts := sync.Options{
 		DryRun: getBool(req, "dry_run", false),
+		Prune:  getBool(req, "prune", false),
+		Safe:   getBool(req, "safe", false),
+		Mirror: h.svc.Mirrored(),
 		Author: author,
 		Msg:    getString(req, "message", ""),
 	}
@@ -60,11 +63,14 @@ func (h *handlers) syncFiles(ctx context.Context, req mcp.CallToolRequest) (*mcp
 		return mcp.NewToolResultError(err.Error()), nil
 	}
 
-	l.Detail("added", syncResult.Added).Detail("updated", syncResult.Updated)
+	l.Detail("added", syncResult.Added).Detail("updated", syncResult.Updated).Detail("deleted", syncResult.Deleted)
 
 	return jsonResult(map[string]any{
-		"updated": syncResult.Updated,
-		"added":   syncResult.Added,
-		"dry_run": opts.DryRun,
+		"updated":   syncResult.Updated,
+		"added":     syncResult.Added,
+		"deleted":   syncResult.Deleted,
+		"missing":   syncResult.Missing,
+		"conflicts": syncResult.Conflicts,
+		"dry_run":   opts.DryRun,
 	})
 }
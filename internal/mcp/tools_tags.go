@@ -15,6 +15,7 @@ import (
 
 	"github.com/jpl-au/llmd/internal/log"
 	"github.com/jpl-au/llmd/internal/store"
+	"github.com/jpl-au/llmd/internal/tag"
 	"github.com/mark3labs/mcp-go/mcp"
 )
 
@@ -80,6 +81,64 @@ func (h *handlers) tagRemove(ctx context.Context, req mcp.CallToolRequest) (*mcp
 	return mcp.NewToolResultText(fmt.Sprintf("removed tag %q from %s", tag, path)), nil
 }
 
+// findByTag handles llmd_find_by_tag tool calls.
+func (h *handlers) findByTag(ctx context.Context, req mcp.CallToolRequest) (*mcp.CallToolResult, error) {
+	if result := h.requireInit(); result != nil {
+		return result, nil
+	}
+
+	var err error
+	t, err := req.RequireString("tag")
+	if err != nil {
+		return mcp.NewToolResultError("tag is required"), nil
+	}
+	prefix := getString(req, "prefix", "")
+	author := getString(req, "author", "mcp")
+
+	l := log.Event("mcp:find_by_tag", "list").Author(author).Path(prefix).Detail("tag", t)
+	defer func() { l.Write(err) }()
+
+	docs, err := tag.Find(ctx, h.svc, prefix, t, getBool(req, "include_deleted", false))
+	if err != nil {
+		return mcp.NewToolResultError(err.Error()), nil
+	}
+	l.Detail("count", len(docs))
+
+	return jsonResult(docs)
+}
+
+// tagRename handles llmd_tag_rename tool calls.
+func (h *handlers) tagRename(ctx context.Context, req mcp.CallToolRequest) (*mcp.CallToolResult, error) {
+	if result := h.requireInit(); result != nil {
+		return result, nil
+	}
+
+	var err error
+	from, err := req.RequireString("from")
+	if err != nil {
+		return mcp.NewToolResultError("from is required"), nil
+	}
+	to, err := req.RequireString("to")
+	if err != nil {
+		return mcp.NewToolResultError("to is required"), nil
+	}
+	author, err := req.RequireString("author")
+	if err != nil {
+		return mcp.NewToolResultError("author is required"), nil
+	}
+
+	l := log.Event("mcp:tag_rename", "rename_tag").Author(author).Detail("from", from).Detail("to", to)
+	defer func() { l.Write(err) }()
+
+	count, err := h.svc.RenameTag(ctx, from, to, store.NewTagOptions())
+	if err != nil {
+		return mcp.NewToolResultError(err.Error()), nil
+	}
+	l.Detail("count", count)
+
+	return mcp.NewToolResultText(fmt.Sprintf("renamed tag %q to %q on %d documents", from, to, count)), nil
+}
+
 // listTags handles llmd_tags tool calls.
 func (h *handlers) listTags(ctx context.Context, req mcp.CallToolRequest) (*mcp.CallToolResult, error) {
 	if result := h.requireInit(); result != nil {
@@ -18,6 +18,18 @@ import (
 	"github.com/mark3labs/mcp-go/mcp"
 )
 
+// hasArg reports whether the MCP request includes the named argument, for
+// optional parameters whose absence means something other than their zero
+// value.
+func hasArg(req mcp.CallToolRequest, name string) bool {
+	args, ok := req.Params.Arguments.(map[string]any)
+	if !ok {
+		return false
+	}
+	_, ok = args[name]
+	return ok
+}
+
 // getString extracts a string parameter from the MCP request, returning the
 // provided default if the parameter is missing or cannot be parsed as a string.
 //
@@ -116,3 +128,48 @@ func jsonResult(v any) (*mcp.CallToolResult, error) {
 	}
 	return mcp.NewToolResultText(string(data)), nil
 }
+
+// pageJSON is the result of llmd_list and llmd_search when called with
+// limit or offset: one page of documents, and enough to fetch the next.
+// Paging is opt-in so callers that expect a bare array keep getting one.
+type pageJSON struct {
+	Documents any  `json:"documents"` // The array the tool returns unpaged
+	Total     int  `json:"total"`     // Matches across all pages
+	Offset    int  `json:"offset"`    // Position of the first document in this page
+	HasMore   bool `json:"has_more"`  // Whether a later page has more documents
+}
+
+// paged reports whether req asks for a page of results rather than all of
+// them, returning its limit and offset.
+func paged(req mcp.CallToolRequest) (limit, offset int, ok bool) {
+	limit, offset = getInt(req, "limit", 0), getInt(req, "offset", 0)
+	return limit, offset, limit != 0 || offset != 0
+}
+
+// pageResult returns docs, holding n documents, as the page of total
+// results starting at offset.
+func pageResult(docs any, n, total, offset int) (*mcp.CallToolResult, error) {
+	return jsonResult(pageJSON{
+		Documents: docs,
+		Total:     total,
+		Offset:    offset,
+		HasMore:   offset+n < total,
+	})
+}
+
+// redacted returns doc with its content masked when redact.mcp is on. Every
+// tool and resource that returns document content passes it through here,
+// so none of them can show what llmd_read masks.
+func (h *handlers) redacted(doc *store.Document) *store.Document {
+	masked := *doc
+	masked.Content = h.mask(doc.Content)
+	return &masked
+}
+
+// mask returns text with redact.patterns masked when redact.mcp is on.
+func (h *handlers) mask(text string) string {
+	if h.redact == nil {
+		return text
+	}
+	return h.redact.Apply(text)
+}
@@ -0,0 +1,111 @@
+// Package mdlink extracts markdown links that point at other llmd documents.
+//
+// Prose often cross-references other documents with ordinary markdown links
+// ("see [auth](docs/api/auth)"). Those references are invisible to the link
+// graph and go stale silently when a target is moved or deleted. This package
+// finds them so they can be checked or turned into formal links.
+package mdlink
+
+import (
+	"path"
+	"regexp"
+	"strings"
+
+	"github.com/jpl-au/llmd/internal/edit"
+	"github.com/jpl-au/llmd/internal/validate"
+)
+
+// LinkTag is the tag on links created from markdown references.
+const LinkTag = "markdown"
+
+// inlineLink matches [text](target) and [text](target "title"). Images
+// (![alt](src)) are excluded by the caller checking the preceding byte.
+var inlineLink = regexp.MustCompile(`\[([^\]]*)\]\(\s*<?([^)\s>]+)>?(?:\s+"[^"]*")?\s*\)`)
+
+// Ref is a markdown link found in a document.
+type Ref struct {
+	Line   int    // 1-indexed line of the link
+	Text   string // Link text
+	Target string // Target as written, without any #fragment or ?query
+}
+
+// Extract returns the document references in content, in order. Links to
+// URLs (anything with a scheme), in-page anchors, images and links inside
+// fenced code blocks or inline code are skipped.
+func Extract(content string) []Ref {
+	var refs []Ref
+	fence := ""
+	for i, line := range strings.Split(content, "\n") {
+		if f := edit.FenceMarker(line); f != "" {
+			switch {
+			case fence == "":
+				fence = f
+			case strings.HasPrefix(f, fence):
+				fence = ""
+			}
+			continue
+		}
+		if fence != "" {
+			continue
+		}
+		line = stripInlineCode(line)
+		for _, m := range inlineLink.FindAllStringSubmatchIndex(line, -1) {
+			if m[0] > 0 && line[m[0]-1] == '!' {
+				continue
+			}
+			target := line[m[4]:m[5]]
+			if j := strings.IndexAny(target, "#?"); j >= 0 {
+				target = target[:j]
+			}
+			if target == "" || hasScheme(target) {
+				continue
+			}
+			refs = append(refs, Ref{Line: i + 1, Text: line[m[2]:m[3]], Target: target})
+		}
+	}
+	return refs
+}
+
+// Resolve maps a link target in document from to a document path. Targets
+// starting with "./" or "../" are relative to from's directory; anything
+// else is a store path ("docs/api/auth", "/docs/api/auth.md"), matching how
+// llmd paths are written elsewhere. A ".md" extension is ignored.
+func Resolve(from, target string) (string, error) {
+	if strings.HasPrefix(target, "./") || strings.HasPrefix(target, "../") {
+		target = path.Join(path.Dir(from), target)
+	}
+	return validate.Path(target, 0)
+}
+
+// hasScheme reports whether target is a URL such as https://... or mailto:.
+func hasScheme(target string) bool {
+	i := strings.IndexByte(target, ':')
+	if i <= 0 {
+		return false
+	}
+	for _, c := range target[:i] {
+		if !(c >= 'a' && c <= 'z' || c >= 'A' && c <= 'Z' || c >= '0' && c <= '9' || c == '+' || c == '-' || c == '.') {
+			return false
+		}
+	}
+	return true
+}
+
+// stripInlineCode blanks out `code spans` so links shown as examples are
+// not treated as references. Lengths are preserved.
+func stripInlineCode(line string) string {
+	if !strings.Contains(line, "`") {
+		return line
+	}
+	b := []byte(line)
+	in := false
+	for i, c := range b {
+		switch {
+		case c == '`':
+			in = !in
+		case in:
+			b[i] = ' '
+		}
+	}
+	return string(b)
+}
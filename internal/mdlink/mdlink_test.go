@@ -0,0 +1,57 @@
+package mdlink
+
+import (
+	"testing"
+)
+
+func TestExtract(t *testing.T) {
+	content := "# Doc\n" +
+		"See [auth](docs/api/auth) and [setup](./setup.md#install).\n" +
+		"External [site](https://example.com) and [mail](mailto:a@b.c) and [top](#top).\n" +
+		"![diagram](images/arch.png)\n" +
+		"Inline `[not](docs/code)` example.\n" +
+		"```\n" +
+		"[fenced](docs/fenced)\n" +
+		"```\n" +
+		"Titled [spec](/docs/spec \"Spec\").\n"
+
+	got := Extract(content)
+	want := []Ref{
+		{Line: 2, Text: "auth", Target: "docs/api/auth"},
+		{Line: 2, Text: "setup", Target: "./setup.md"},
+		{Line: 9, Text: "spec", Target: "/docs/spec"},
+	}
+	if len(got) != len(want) {
+		t.Fatalf("Extract() = %+v, want %+v", got, want)
+	}
+	for i := range want {
+		if got[i] != want[i] {
+			t.Errorf("Extract()[%d] = %+v, want %+v", i, got[i], want[i])
+		}
+	}
+}
+
+func TestResolve(t *testing.T) {
+	tests := []struct {
+		from, target, want string
+		wantErr            bool
+	}{
+		{from: "docs/readme", target: "docs/api/auth", want: "docs/api/auth"},
+		{from: "docs/readme", target: "/docs/api/auth.md", want: "docs/api/auth"},
+		{from: "docs/api/auth", target: "./tokens", want: "docs/api/tokens"},
+		{from: "docs/api/auth", target: "../readme.md", want: "docs/readme"},
+		{from: "readme", target: "../../x", wantErr: true},
+	}
+	for _, tt := range tests {
+		got, err := Resolve(tt.from, tt.target)
+		if tt.wantErr {
+			if err == nil {
+				t.Errorf("Resolve(%q, %q) = %q, want error", tt.from, tt.target, got)
+			}
+			continue
+		}
+		if err != nil || got != tt.want {
+			t.Errorf("Resolve(%q, %q) = %q, %v, want %q", tt.from, tt.target, got, err, tt.want)
+		}
+	}
+}
@@ -0,0 +1,178 @@
+// Package mv plans and applies document moves.
+//
+// Targets maps the sources of an "mv" to their destinations and Run applies
+// the batch atomically with Service.MoveMany. The CLI "mv" command and the
+// MCP llmd_move tool both go through Run, so they resolve the same sources
+// and dest to the same renames. NewPlan computes the same
+// mapping and PlanMoves reports the moves that would fail, letting callers
+// preview a batch (mv --dry-run) before touching anything.
+package mv
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"path"
+	"strings"
+
+	"github.com/jpl-au/llmd/internal/service"
+	"github.com/jpl-au/llmd/internal/store"
+)
+
+// Reasons a planned move would fail.
+const (
+	ReasonExists    = "destination exists"
+	ReasonDuplicate = "duplicate destination"
+	ReasonNotFound  = "source not found"
+)
+
+// Move is a single source -> target rename.
+type Move struct {
+	From string `json:"from"`
+	To   string `json:"to"`
+}
+
+// Collision is a planned move that would fail, and why.
+type Collision struct {
+	From   string `json:"from"`
+	To     string `json:"to"`
+	Reason string `json:"reason"`
+}
+
+// Plan is the full set of renames a move would perform.
+type Plan struct {
+	Moves      []Move      `json:"moves"`
+	Collisions []Collision `json:"collisions"`
+}
+
+// Ops converts moves to the store operations Service.MoveMany applies.
+func Ops(moves []Move) []store.MoveOp {
+	ops := make([]store.MoveOp, len(moves))
+	for i, m := range moves {
+		ops[i] = store.MoveOp{From: m.From, To: m.To}
+	}
+	return ops
+}
+
+// Targets maps sources to their destinations. With several sources, or a
+// dest ending in "/", each source moves under dest keeping its base name
+// (docs/readme -> archive/readme); otherwise the single source is renamed
+// to dest.
+func Targets(sources []string, dest string) ([]Move, error) {
+	if dest == "" {
+		return nil, errors.New("destination path cannot be empty")
+	}
+	prefixMode := len(sources) > 1 || strings.HasSuffix(dest, "/")
+	destPrefix := strings.TrimSuffix(dest, "/")
+
+	moves := make([]Move, 0, len(sources))
+	for _, src := range sources {
+		if src == "" {
+			return nil, errors.New("source path cannot be empty")
+		}
+		target := dest
+		if prefixMode {
+			target = path.Join(destPrefix, path.Base(src))
+		}
+		moves = append(moves, Move{From: src, To: target})
+	}
+	return moves, nil
+}
+
+// Run moves sources to dest as Targets maps them, in one transaction: if
+// any move fails none are applied. It returns the moves performed.
+func Run(ctx context.Context, svc service.Service, sources []string, dest string) ([]Move, error) {
+	moves, err := Targets(sources, dest)
+	if err != nil {
+		return nil, err
+	}
+	if err := svc.MoveMany(ctx, Ops(moves)); err != nil {
+		return nil, err
+	}
+	return moves, nil
+}
+
+// NewPlan maps sources to targets as Targets does and checks the moves as
+// PlanMoves does, without moving anything.
+func NewPlan(ctx context.Context, svc service.Service, sources []string, dest string) (Plan, error) {
+	moves, err := Targets(sources, dest)
+	if err != nil {
+		return Plan{}, err
+	}
+	return PlanMoves(ctx, svc, moves)
+}
+
+// PlanMoves checks each move against the store as it will be when the move
+// runs. MoveMany applies a batch in order, so a path an earlier move
+// vacates is free for a later one (a -> b, c -> a) and a path it fills is
+// taken. A move collides if its source does not exist at that point, or
+// its target holds a document: one already in the store, or one an earlier
+// move in the batch puts there.
+func PlanMoves(ctx context.Context, svc service.Service, moves []Move) (Plan, error) {
+	plan := Plan{Moves: moves, Collisions: []Collision{}}
+	b := batch{svc: svc, filled: map[string]bool{}, vacated: map[string]bool{}}
+	for _, m := range moves {
+		reason, err := b.check(ctx, m)
+		if err != nil {
+			return plan, err
+		}
+		if reason != "" {
+			plan.Collisions = append(plan.Collisions, Collision{From: m.From, To: m.To, Reason: reason})
+		} else {
+			b.apply(m)
+		}
+	}
+	return plan, nil
+}
+
+// batch tracks how the moves planned so far change the store's paths.
+type batch struct {
+	svc     service.Service
+	filled  map[string]bool // Paths an earlier move moved a document to
+	vacated map[string]bool // Paths an earlier move moved a document from
+}
+
+// exists reports whether p holds a document once the earlier moves run.
+func (b batch) exists(ctx context.Context, p string) (bool, error) {
+	if b.filled[p] {
+		return true, nil
+	}
+	if b.vacated[p] {
+		return false, nil
+	}
+	ok, err := b.svc.Exists(ctx, p)
+	if err != nil {
+		return false, fmt.Errorf("checking %s: %w", p, err)
+	}
+	return ok, nil
+}
+
+// check returns why m would fail, or "" if it would succeed.
+func (b batch) check(ctx context.Context, m Move) (string, error) {
+	ok, err := b.exists(ctx, m.From)
+	if err != nil {
+		return "", err
+	}
+	if !ok {
+		return ReasonNotFound, nil
+	}
+	if b.filled[m.To] {
+		return ReasonDuplicate, nil
+	}
+	ok, err = b.exists(ctx, m.To)
+	if err != nil {
+		return "", err
+	}
+	if ok {
+		return ReasonExists, nil
+	}
+	return "", nil
+}
+
+// apply records m as done.
+func (b batch) apply(m Move) {
+	delete(b.filled, m.From)
+	b.vacated[m.From] = true
+	delete(b.vacated, m.To)
+	b.filled[m.To] = true
+}
@@ -0,0 +1,132 @@
+package mv_test
+
+import (
+	"context"
+	"testing"
+
+	"github.com/jpl-au/llmd/internal/document"
+	"github.com/jpl-au/llmd/internal/mv"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestTargets(t *testing.T) {
+	moves, err := mv.Targets([]string{"docs/a"}, "docs/b")
+	require.NoError(t, err)
+	assert.Equal(t, []mv.Move{{From: "docs/a", To: "docs/b"}}, moves)
+
+	// A trailing slash or several sources move into the prefix
+	moves, err = mv.Targets([]string{"docs/a"}, "archive/")
+	require.NoError(t, err)
+	assert.Equal(t, []mv.Move{{From: "docs/a", To: "archive/a"}}, moves)
+
+	moves, err = mv.Targets([]string{"docs/a", "notes/b"}, "archive")
+	require.NoError(t, err)
+	assert.Equal(t, []mv.Move{{From: "docs/a", To: "archive/a"}, {From: "notes/b", To: "archive/b"}}, moves)
+
+	_, err = mv.Targets([]string{"docs/a"}, "")
+	assert.Error(t, err)
+	_, err = mv.Targets([]string{""}, "docs/b")
+	assert.Error(t, err)
+}
+
+func TestRun(t *testing.T) {
+	tests := []struct {
+		name    string
+		sources []string
+		dest    string
+		want    []string
+	}{
+		{"rename", []string{"a"}, "b", []string{"b", "c", "d"}},
+		{"into prefix", []string{"a"}, "b/", []string{"b/a", "c", "d"}},
+		{"many into prefix", []string{"a", "c", "d"}, "dest/", []string{"dest/a", "dest/c", "dest/d"}},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			svc, err := document.NewMemory()
+			require.NoError(t, err)
+			defer svc.Close()
+			ctx := context.Background()
+			for _, p := range []string{"a", "c", "d"} {
+				require.NoError(t, svc.Write(ctx, p, "content", "tester", ""))
+			}
+
+			moves, err := mv.Run(ctx, svc, tt.sources, tt.dest)
+			require.NoError(t, err)
+			assert.Len(t, moves, len(tt.sources))
+
+			paths, err := svc.Glob(ctx, "")
+			require.NoError(t, err)
+			assert.ElementsMatch(t, tt.want, paths)
+		})
+	}
+
+	t.Run("atomic", func(t *testing.T) {
+		svc, err := document.NewMemory()
+		require.NoError(t, err)
+		defer svc.Close()
+		ctx := context.Background()
+		require.NoError(t, svc.Write(ctx, "a", "content", "tester", ""))
+
+		_, err = mv.Run(ctx, svc, []string{"a", "missing"}, "dest/")
+		require.Error(t, err)
+		ok, err := svc.Exists(ctx, "a")
+		require.NoError(t, err)
+		assert.True(t, ok)
+	})
+}
+
+func TestNewPlan(t *testing.T) {
+	svc, err := document.NewMemory()
+	require.NoError(t, err)
+	defer svc.Close()
+	ctx := context.Background()
+
+	for _, p := range []string{"docs/a", "docs/b", "notes/b", "archive/a"} {
+		require.NoError(t, svc.Write(ctx, p, "content", "tester", ""))
+	}
+
+	plan, err := mv.NewPlan(ctx, svc, []string{"docs/a", "docs/b", "notes/b", "docs/missing"}, "archive/")
+	require.NoError(t, err)
+	assert.Len(t, plan.Moves, 4)
+	assert.Equal(t, []mv.Collision{
+		{From: "docs/a", To: "archive/a", Reason: mv.ReasonExists},
+		{From: "notes/b", To: "archive/b", Reason: mv.ReasonDuplicate},
+		{From: "docs/missing", To: "archive/missing", Reason: mv.ReasonNotFound},
+	}, plan.Collisions)
+
+	// Planning moves nothing
+	ok, err := svc.Exists(ctx, "docs/b")
+	require.NoError(t, err)
+	assert.True(t, ok)
+
+	plan, err = mv.NewPlan(ctx, svc, []string{"docs/b"}, "docs/c")
+	require.NoError(t, err)
+	assert.Empty(t, plan.Collisions)
+}
+
+func TestPlanMoves_Chain(t *testing.T) {
+	svc, err := document.NewMemory()
+	require.NoError(t, err)
+	defer svc.Close()
+	ctx := context.Background()
+
+	for _, p := range []string{"a", "c", "d"} {
+		require.NoError(t, svc.Write(ctx, p, p, "tester", ""))
+	}
+
+	// a is vacated before c moves into it, and b is filled before d tries
+	moves := []mv.Move{{From: "a", To: "b"}, {From: "c", To: "a"}, {From: "d", To: "b"}, {From: "c", To: "e"}}
+	plan, err := mv.PlanMoves(ctx, svc, moves)
+	require.NoError(t, err)
+	assert.Equal(t, []mv.Collision{
+		{From: "d", To: "b", Reason: mv.ReasonDuplicate},
+		{From: "c", To: "e", Reason: mv.ReasonNotFound},
+	}, plan.Collisions)
+
+	// The plan agrees with what MoveMany does
+	require.NoError(t, svc.MoveMany(ctx, mv.Ops(moves[:2])))
+	doc, err := svc.Latest(ctx, "a", false)
+	require.NoError(t, err)
+	assert.Equal(t, "c", doc.Content)
+}
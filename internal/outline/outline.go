@@ -0,0 +1,79 @@
+// Package outline lists a document's headings as a nested outline.
+//
+// Reading a large document just to find where to edit wastes an LLM's
+// context budget. The outline gives the headings and their line ranges, which
+// is enough to choose a section for "cat --section", "edit --section" or a
+// line-range edit, at a fraction of the size.
+package outline
+
+import (
+	"context"
+	"fmt"
+	"io"
+	"strings"
+
+	"github.com/jpl-au/llmd/internal/edit"
+	"github.com/jpl-au/llmd/internal/service"
+)
+
+// Section is one heading in the outline. Subsections are nested under the
+// nearest preceding heading of a higher level.
+type Section struct {
+	Level     int        `json:"level"`
+	Heading   string     `json:"heading"`
+	StartLine int        `json:"start_line"`
+	EndLine   int        `json:"end_line"`
+	Sections  []*Section `json:"sections,omitempty"`
+}
+
+// Result is the outline of one document.
+type Result struct {
+	Path     string     `json:"path"`
+	Sections []*Section `json:"sections"`
+}
+
+// Run builds the outline of the document at path (or key) and writes it to w
+// as indented headings with their line ranges.
+func Run(ctx context.Context, w io.Writer, svc service.Service, path string) (Result, error) {
+	doc, _, err := svc.Resolve(ctx, path, false)
+	if err != nil {
+		return Result{Path: path}, err
+	}
+
+	result := Result{Path: doc.Path, Sections: Build(doc.Content)}
+	if len(result.Sections) == 0 {
+		fmt.Fprintf(w, "%s has no headings\n", doc.Path)
+		return result, nil
+	}
+	write(w, result.Sections, 0)
+	return result, nil
+}
+
+// Build returns the headings in content as a tree. It never returns nil, so
+// a document without headings encodes as an empty JSON array.
+func Build(content string) []*Section {
+	roots := []*Section{}
+	var stack []*Section
+	for _, s := range edit.Sections(content) {
+		n := &Section{Level: s.Level, Heading: s.Text, StartLine: s.Line, EndLine: s.End}
+		for len(stack) > 0 && stack[len(stack)-1].Level >= n.Level {
+			stack = stack[:len(stack)-1]
+		}
+		if len(stack) == 0 {
+			roots = append(roots, n)
+		} else {
+			parent := stack[len(stack)-1]
+			parent.Sections = append(parent.Sections, n)
+		}
+		stack = append(stack, n)
+	}
+	return roots
+}
+
+func write(w io.Writer, sections []*Section, depth int) {
+	for _, s := range sections {
+		fmt.Fprintf(w, "%s%s %s (lines %d-%d)\n",
+			strings.Repeat("  ", depth), strings.Repeat("#", s.Level), s.Heading, s.StartLine, s.EndLine)
+		write(w, s.Sections, depth+1)
+	}
+}
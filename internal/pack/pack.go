@@ -0,0 +1,185 @@
+// Package pack assembles documents into one bundle that fits a token budget.
+//
+// The bundle is what an assistant is handed as context: each document is
+// wrapped in a <document path="..."> element, so the model can tell where one
+// ends and the next begins and cite paths back. The path is escaped as an XML
+// attribute and the content is held in a CDATA section, so neither can close
+// the element early, while the content still reads as written. Documents are
+// taken in the order named, then the matches of a full-text query most
+// relevant first, and added while the estimated total stays within the
+// budget. A document that does not fit is skipped, and later, smaller ones
+// may still go in; with Truncate it is cut at a heading instead, keeping as
+// many of its leading sections as fit.
+//
+// Token counts are estimates from package tokens, so leave the model some
+// headroom rather than packing to its exact context size.
+package pack
+
+import (
+	"context"
+	"encoding/xml"
+	"fmt"
+	"io"
+	"slices"
+	"strings"
+
+	"github.com/jpl-au/llmd/internal/edit"
+	"github.com/jpl-au/llmd/internal/resolve"
+	"github.com/jpl-au/llmd/internal/service"
+	"github.com/jpl-au/llmd/internal/store"
+	"github.com/jpl-au/llmd/internal/tokens"
+)
+
+// Options configures a pack.
+type Options struct {
+	Search   string // Full-text query whose matches are added, most relevant first
+	Budget   int    // Token limit for the bundle
+	Method   string // Token estimator (default tokens.DefaultMethod)
+	Truncate bool   // Cut a document that does not fit at a section boundary
+}
+
+// Part is one document in the bundle.
+type Part struct {
+	Path      string `json:"path"`
+	Tokens    int    `json:"tokens"`
+	Truncated bool   `json:"truncated,omitempty"`
+}
+
+// Result describes the bundle.
+type Result struct {
+	Budget    int      `json:"budget"`
+	Tokens    int      `json:"tokens"`
+	Documents []Part   `json:"documents"`
+	Skipped   []string `json:"skipped"` // Documents that did not fit
+	Content   string   `json:"content"`
+}
+
+// Run packs the documents named by targets, as resolve.Documents selects
+// them, and the matches of opts.Search into a bundle of at most opts.Budget
+// estimated tokens, and writes the bundle to w.
+func Run(ctx context.Context, w io.Writer, svc service.Service, targets []string, opts Options) (Result, error) {
+	result := Result{Budget: opts.Budget, Documents: []Part{}, Skipped: []string{}}
+	if opts.Budget <= 0 {
+		return result, fmt.Errorf("budget must be > 0, got %d", opts.Budget)
+	}
+	if len(targets) == 0 && opts.Search == "" {
+		return result, fmt.Errorf("requires documents or a search query")
+	}
+	method := opts.Method
+	if method == "" {
+		method = tokens.DefaultMethod
+	}
+	estimate, ok := tokens.Estimators[method]
+	if !ok {
+		return result, fmt.Errorf("unknown method %q (available: %s)", method, strings.Join(tokens.Methods(), ", "))
+	}
+
+	docs, err := sources(ctx, svc, targets, opts.Search)
+	if err != nil {
+		return result, err
+	}
+
+	var b strings.Builder
+	for _, d := range docs {
+		// Documents after the first are separated by a blank line, which
+		// counts against the budget with the document it precedes
+		sep := ""
+		if b.Len() > 0 {
+			sep = "\n"
+		}
+		cost := func(s string) int { return estimate(sep + s) }
+
+		left := opts.Budget - result.Tokens
+		text := wrap(d.Path, d.Content, false)
+		n := cost(text)
+		truncated := false
+		if n > left && opts.Truncate {
+			text, n = cut(d, left, cost)
+			truncated = true
+		}
+		if text == "" || n > left {
+			result.Skipped = append(result.Skipped, d.Path)
+			continue
+		}
+		b.WriteString(sep)
+		b.WriteString(text)
+		result.Tokens += n
+		result.Documents = append(result.Documents, Part{Path: d.Path, Tokens: n, Truncated: truncated})
+	}
+
+	result.Content = b.String()
+	_, err = io.WriteString(w, result.Content)
+	return result, err
+}
+
+// sources returns the named documents followed by the search matches, each
+// document once.
+func sources(ctx context.Context, svc service.Service, targets []string, query string) ([]*store.Document, error) {
+	var docs []*store.Document
+	if len(targets) > 0 {
+		var err error
+		if docs, err = resolve.Documents(ctx, svc, targets); err != nil {
+			return nil, err
+		}
+	}
+	if query == "" {
+		return docs, nil
+	}
+
+	matches, err := svc.SearchRanked(ctx, query, "")
+	if err != nil {
+		return nil, fmt.Errorf("search %q: %w", query, err)
+	}
+	for i := range matches {
+		named := slices.ContainsFunc(docs, func(d *store.Document) bool { return d.Path == matches[i].Path })
+		if !named {
+			docs = append(docs, &matches[i])
+		}
+	}
+	return docs, nil
+}
+
+// cut returns the longest run of d's leading sections that fits in budget
+// tokens, wrapped, with its estimate; "" if not even the first fits. The
+// content before the first heading counts as a section.
+func cut(d *store.Document, budget int, estimate tokens.Estimator) (string, int) {
+	lines := strings.SplitAfter(d.Content, "\n")
+	var starts []int
+	for _, h := range edit.Headings(d.Content) {
+		if h.Line > 1 {
+			starts = append(starts, h.Line-1)
+		}
+	}
+
+	// Try the cut points from the last back, keeping the longest that fits
+	for i := len(starts) - 1; i >= 0; i-- {
+		kept := strings.Join(lines[:starts[i]], "")
+		if strings.TrimSpace(kept) == "" {
+			break
+		}
+		text := wrap(d.Path, kept, true)
+		if n := estimate(text); n <= budget {
+			return text, n
+		}
+	}
+	return "", 0
+}
+
+// wrap returns content as an element of the bundle. A "]]>" in content,
+// which would end the CDATA section, is split across two sections.
+func wrap(path, content string, truncated bool) string {
+	var b strings.Builder
+	b.WriteString(`<document path="`)
+	_ = xml.EscapeText(&b, []byte(path)) // A strings.Builder never fails
+	b.WriteString(`"`)
+	if truncated {
+		b.WriteString(` truncated="true"`)
+	}
+	b.WriteString(">\n<![CDATA[\n")
+	b.WriteString(strings.ReplaceAll(content, "]]>", "]]]]><![CDATA[>"))
+	if !strings.HasSuffix(content, "\n") {
+		b.WriteString("\n")
+	}
+	b.WriteString("]]>\n</document>\n")
+	return b.String()
+}
@@ -0,0 +1,115 @@
+package pack
+
+import (
+	"context"
+	"encoding/xml"
+	"io"
+	"strings"
+	"testing"
+
+	"github.com/jpl-au/llmd/internal/document"
+	"github.com/jpl-au/llmd/internal/tokens"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestRun(t *testing.T) {
+	svc, err := document.NewMemory()
+	require.NoError(t, err)
+	defer svc.Close()
+	ctx := context.Background()
+
+	guide := "# Guide\nIntro text.\n## Install\nRun the installer.\n## Usage\nRun it often, with many options and flags.\n"
+	docs := map[string]string{
+		"docs/small": "A small deploy note.\n",
+		"docs/guide": guide,
+		"docs/big":   "deploy " + strings.Repeat("word ", 100) + "\n",
+	}
+	for p, c := range docs {
+		require.NoError(t, svc.Write(ctx, p, c, "tester", ""))
+	}
+	cost := func(path string) int { return tokens.Text(wrap(path, docs[path], false)) }
+
+	t.Run("in order within budget", func(t *testing.T) {
+		budget := cost("docs/small") + cost("docs/guide")
+		r, err := Run(ctx, io.Discard, svc, []string{"docs/small", "docs/big", "docs/guide"}, Options{Budget: budget})
+		require.NoError(t, err)
+		assert.Equal(t, []Part{
+			{Path: "docs/small", Tokens: cost("docs/small")},
+			{Path: "docs/guide", Tokens: cost("docs/guide")},
+		}, r.Documents)
+		assert.Equal(t, []string{"docs/big"}, r.Skipped)
+		assert.Equal(t, budget, r.Tokens)
+		assert.Equal(t, wrap("docs/small", docs["docs/small"], false)+"\n"+wrap("docs/guide", guide, false), r.Content)
+	})
+
+	t.Run("truncate at a section", func(t *testing.T) {
+		kept := "# Guide\nIntro text.\n## Install\nRun the installer.\n"
+		budget := tokens.Text(wrap("docs/guide", kept, true))
+		r, err := Run(ctx, io.Discard, svc, []string{"docs/guide"}, Options{Budget: budget, Truncate: true})
+		require.NoError(t, err)
+		require.Len(t, r.Documents, 1)
+		assert.True(t, r.Documents[0].Truncated)
+		assert.Equal(t, wrap("docs/guide", kept, true), r.Content)
+
+		// Not even the first section fits
+		r, err = Run(ctx, io.Discard, svc, []string{"docs/guide"}, Options{Budget: 5, Truncate: true})
+		require.NoError(t, err)
+		assert.Empty(t, r.Documents)
+		assert.Equal(t, []string{"docs/guide"}, r.Skipped)
+	})
+
+	t.Run("search adds ranked matches once", func(t *testing.T) {
+		r, err := Run(ctx, io.Discard, svc, []string{"docs/small"}, Options{Budget: 1000, Search: "deploy"})
+		require.NoError(t, err)
+		var paths []string
+		for _, p := range r.Documents {
+			paths = append(paths, p.Path)
+		}
+		assert.Equal(t, []string{"docs/small", "docs/big"}, paths)
+	})
+
+	t.Run("separators count against the budget", func(t *testing.T) {
+		// One token per byte, so the estimate of the bundle is exact
+		tokens.Estimators["bytes"] = func(s string) int { return len(s) }
+		defer delete(tokens.Estimators, "bytes")
+
+		budget := len(wrap("docs/small", docs["docs/small"], false)) + len(wrap("docs/guide", guide, false))
+		r, err := Run(ctx, io.Discard, svc, []string{"docs/small", "docs/guide"}, Options{Budget: budget, Method: "bytes"})
+		require.NoError(t, err)
+		assert.Equal(t, []string{"docs/guide"}, r.Skipped, "no room for the separator")
+		assert.Equal(t, len(r.Content), r.Tokens)
+
+		r, err = Run(ctx, io.Discard, svc, []string{"docs/small", "docs/guide"}, Options{Budget: budget + 1, Method: "bytes"})
+		require.NoError(t, err)
+		assert.Empty(t, r.Skipped)
+		assert.Equal(t, len(r.Content), r.Tokens)
+	})
+
+	t.Run("escapes paths and content", func(t *testing.T) {
+		path := `docs/a"b<c>`
+		content := "Ends early: </document>]]> and more.\n"
+		require.NoError(t, svc.Write(ctx, path, content, "tester", ""))
+		r, err := Run(ctx, io.Discard, svc, []string{path}, Options{Budget: 1000})
+		require.NoError(t, err)
+		assert.Equal(t, "<document path=\"docs/a&#34;b&lt;c&gt;\">\n<![CDATA[\n"+
+			"Ends early: </document>]]]]><![CDATA[> and more.\n]]>\n</document>\n", r.Content)
+
+		var got struct {
+			Path    string `xml:"path,attr"`
+			Content string `xml:",chardata"`
+		}
+		require.NoError(t, xml.Unmarshal([]byte(r.Content), &got))
+		assert.Equal(t, path, got.Path)
+		assert.Equal(t, strings.TrimSpace(content), strings.TrimSpace(got.Content))
+	})
+
+	t.Run("invalid", func(t *testing.T) {
+		_, err := Run(ctx, io.Discard, svc, []string{"docs/small"}, Options{})
+		assert.Error(t, err, "budget required")
+		_, err = Run(ctx, io.Discard, svc, nil, Options{Budget: 10})
+		assert.Error(t, err, "sources required")
+		_, err = Run(ctx, io.Discard, svc, []string{"docs/small"}, Options{Budget: 10, Method: "exact"})
+		assert.Error(t, err)
+	})
+}
@@ -0,0 +1,338 @@
+// Package patch applies unified diffs to document content.
+//
+// LLMs often express changes as unified diffs, which are far smaller than a
+// full rewrite. Hunks are located by their context rather than trusted line
+// numbers: the header position is only a starting point for the search, and
+// trailing whitespace differences are tolerated. Line counts in "@@" headers
+// are ignored because hand-written diffs frequently get them wrong.
+package patch
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"io"
+	"regexp"
+	"slices"
+	"strconv"
+	"strings"
+
+	"github.com/jpl-au/llmd/internal/service"
+)
+
+var (
+	// ErrInvalidPatch is returned when the input cannot be parsed as a diff.
+	ErrInvalidPatch = errors.New("invalid patch")
+	// ErrHunkFailed is returned when a hunk's context cannot be found.
+	ErrHunkFailed = errors.New("hunk does not apply")
+)
+
+// hunkHeader matches "@@ -start[,count] +start[,count] @@".
+var hunkHeader = regexp.MustCompile(`^@@ -(\d+)(?:,(\d+))? \+(\d+)(?:,(\d+))? @@`)
+
+// Options configures a patch operation.
+type Options struct {
+	Author  string // Author attribution
+	Message string // Version message
+}
+
+// Result contains the outcome of a patch operation.
+type Result struct {
+	Path  string `json:"path"`
+	Hunks int    `json:"hunks"`
+}
+
+// Hunk is a single "@@" section of a unified diff.
+type Hunk struct {
+	Header   string   // The "@@" line as written
+	OldStart int      // 1-indexed start line in the original; 0 if unknown
+	OldLines int      // Line count from the header (informational only)
+	Lines    []string // Body lines including their ' ', '-' or '+' prefix
+}
+
+// HunkError reports a hunk that could not be applied.
+type HunkError struct {
+	Index int  // 1-indexed position of the hunk in the patch
+	Hunk  Hunk // The offending hunk
+}
+
+func (e *HunkError) Error() string {
+	return fmt.Sprintf("%v: hunk %d\n%s\n%s", ErrHunkFailed, e.Index, e.Hunk.Header, strings.Join(e.Hunk.Lines, "\n"))
+}
+
+func (e *HunkError) Unwrap() error { return ErrHunkFailed }
+
+// Run applies a unified diff to a document and writes a new version.
+// path can be a document path or a key.
+func Run(ctx context.Context, w io.Writer, svc service.Service, path, diff string, opts Options) (Result, error) {
+	result := Result{Path: path}
+
+	hunks, err := Parse(diff)
+	if err != nil {
+		return result, err
+	}
+
+	doc, _, err := svc.Resolve(ctx, path, false)
+	if err != nil {
+		return result, err
+	}
+	path = doc.Path
+	result.Path = path
+
+	content, err := Apply(doc.Content, hunks)
+	if err != nil {
+		return result, err
+	}
+
+	if err := svc.Write(ctx, path, content, opts.Author, opts.Message); err != nil {
+		return result, err
+	}
+
+	result.Hunks = len(hunks)
+	fmt.Fprintf(w, "Patched %s (%d hunks)\n", path, len(hunks))
+	return result, nil
+}
+
+// Parse extracts hunks from a unified diff. File headers ("---", "+++",
+// "diff", "index") and any preamble before the first hunk are skipped.
+// A header of just "@@" is accepted, in which case the hunk is located
+// purely by its context; such a hunk must have context or removed lines,
+// as a bare insertion has nothing to say where it goes.
+func Parse(diff string) ([]Hunk, error) {
+	diff = strings.TrimSuffix(strings.ReplaceAll(diff, "\r\n", "\n"), "\n")
+	lines := strings.Split(diff, "\n")
+
+	var hunks []Hunk
+	var cur *Hunk
+	flush := func() {
+		if cur == nil {
+			return
+		}
+		hunks = append(hunks, *cur)
+		cur = nil
+	}
+
+	for i, line := range lines {
+		if strings.HasPrefix(line, "@@") {
+			flush()
+			h, err := parseHeader(line)
+			if err != nil {
+				return nil, err
+			}
+			cur = &h
+			continue
+		}
+		if cur == nil {
+			continue
+		}
+		if isFileHeader(lines, i) {
+			flush()
+			continue
+		}
+		switch {
+		case line == "":
+			// Editors often strip the space from blank context lines
+			cur.Lines = append(cur.Lines, "")
+		case line[0] == ' ', line[0] == '-', line[0] == '+':
+			cur.Lines = append(cur.Lines, line)
+		case line[0] == '\\':
+			// "\ No newline at end of file" - the document keeps its own ending
+		default:
+			flush()
+		}
+	}
+	flush()
+
+	if len(hunks) == 0 {
+		return nil, fmt.Errorf("%w: no hunks found", ErrInvalidPatch)
+	}
+	for i, h := range hunks {
+		if !slices.ContainsFunc(h.Lines, func(l string) bool { return l != "" }) {
+			return nil, fmt.Errorf("%w: hunk %d is empty", ErrInvalidPatch, i+1)
+		}
+		if bare(h.Header) && len(original(h)) == 0 {
+			return nil, fmt.Errorf("%w: hunk %d only adds lines, so needs a line number in its header (e.g. \"@@ -0,0 +1 @@\" for the top)",
+				ErrInvalidPatch, i+1)
+		}
+	}
+	return hunks, nil
+}
+
+// bare reports whether a hunk header is just "@@", without line numbers.
+func bare(header string) bool {
+	return strings.TrimSpace(strings.Trim(header, "@")) == ""
+}
+
+// parseHeader reads the line numbers from a "@@" line.
+func parseHeader(line string) (Hunk, error) {
+	h := Hunk{Header: line}
+	if bare(line) {
+		return h, nil
+	}
+	m := hunkHeader.FindStringSubmatch(line)
+	if m == nil {
+		return h, fmt.Errorf("%w: malformed hunk header %q", ErrInvalidPatch, line)
+	}
+	h.OldStart, _ = strconv.Atoi(m[1])
+	h.OldLines = 1
+	if m[2] != "" {
+		h.OldLines, _ = strconv.Atoi(m[2])
+	}
+	return h, nil
+}
+
+// isFileHeader reports whether lines[i] starts a new file's headers rather
+// than being a removed line that happens to begin with "--".
+func isFileHeader(lines []string, i int) bool {
+	line := lines[i]
+	if strings.HasPrefix(line, "diff ") || strings.HasPrefix(line, "index ") {
+		return true
+	}
+	return strings.HasPrefix(line, "--- ") && i+1 < len(lines) && strings.HasPrefix(lines[i+1], "+++ ")
+}
+
+// Apply applies hunks to content in order. Each hunk must match after the
+// previous one; the first hunk that cannot be placed is returned as a
+// *HunkError.
+func Apply(content string, hunks []Hunk) (string, error) {
+	trailing := strings.HasSuffix(content, "\n")
+	lines := strings.Split(content, "\n")
+	if trailing || content == "" {
+		lines = lines[:len(lines)-1]
+	}
+
+	offset := 0 // Net lines added by earlier hunks
+	minPos := 0 // Hunks may not overlap earlier ones
+	for i, h := range hunks {
+		pos, h, ok := place(lines, h, offset, minPos)
+		if !ok {
+			return "", &HunkError{Index: i + 1, Hunk: hunks[i]}
+		}
+		old := original(h)
+
+		// Keep the document's own context lines so whitespace fuzz in the
+		// patch does not leak into the result.
+		var out []string
+		n := pos
+		for _, l := range h.Lines {
+			switch prefix(l) {
+			case ' ':
+				out = append(out, lines[n])
+				n++
+			case '-':
+				n++
+			case '+':
+				out = append(out, l[1:])
+			}
+		}
+
+		merged := make([]string, 0, len(lines)-len(old)+len(out))
+		merged = append(merged, lines[:pos]...)
+		merged = append(merged, out...)
+		merged = append(merged, lines[pos+len(old):]...)
+		lines = merged
+
+		offset += len(out) - len(old)
+		minPos = pos + len(out)
+	}
+
+	result := strings.Join(lines, "\n")
+	if trailing || (content == "" && len(lines) > 0) {
+		result += "\n"
+	}
+	return result, nil
+}
+
+// place finds where h applies in lines, returning the hunk as matched. A
+// hunk ending in blank lines is matched with them first, as they are
+// context that tells apart otherwise identical places. Only if that fails
+// are they dropped one at a time, since editors and chat clients often add
+// a blank line after a diff that is not part of it.
+func place(lines []string, h Hunk, offset, minPos int) (int, Hunk, bool) {
+	for {
+		if pos, ok := locate(lines, original(h), h, offset, minPos); ok {
+			return pos, h, true
+		}
+		n := len(h.Lines)
+		if n < 2 || h.Lines[n-1] != "" {
+			return 0, h, false
+		}
+		h.Lines = h.Lines[:n-1]
+	}
+}
+
+// original returns the lines a hunk expects to find: its context and
+// removed lines, without prefixes.
+func original(h Hunk) []string {
+	var old []string
+	for _, l := range h.Lines {
+		if p := prefix(l); p == ' ' || p == '-' {
+			old = append(old, text(l))
+		}
+	}
+	return old
+}
+
+// prefix returns the diff marker of a hunk line. Blank lines are context.
+func prefix(l string) byte {
+	if l == "" {
+		return ' '
+	}
+	return l[0]
+}
+
+// text returns a hunk line without its diff marker.
+func text(l string) string {
+	if l == "" {
+		return ""
+	}
+	return l[1:]
+}
+
+// locate finds where old occurs in lines, searching outwards from the
+// position the hunk header suggests. An exact match anywhere is preferred
+// over one that differs only in trailing whitespace.
+func locate(lines, old []string, h Hunk, offset, minPos int) (int, bool) {
+	if len(old) == 0 {
+		// Pure insertion: "-N,0" means after line N
+		pos := max(h.OldStart+offset, minPos)
+		return pos, pos <= len(lines)
+	}
+
+	last := len(lines) - len(old)
+	if last < minPos {
+		return 0, false
+	}
+	expected := minPos
+	if h.OldStart > 0 {
+		expected = min(max(h.OldStart-1+offset, minPos), last)
+	}
+
+	for _, eq := range []func(a, b string) bool{exact, fuzzy} {
+		for d := 0; expected-d >= minPos || expected+d <= last; d++ {
+			if p := expected - d; p >= minPos && matches(lines[p:], old, eq) {
+				return p, true
+			}
+			if p := expected + d; p <= last && matches(lines[p:], old, eq) {
+				return p, true
+			}
+		}
+	}
+	return 0, false
+}
+
+// matches reports whether lines begins with old under the comparison eq.
+func matches(lines, old []string, eq func(a, b string) bool) bool {
+	for i, l := range old {
+		if !eq(lines[i], l) {
+			return false
+		}
+	}
+	return true
+}
+
+func exact(a, b string) bool { return a == b }
+
+func fuzzy(a, b string) bool {
+	return strings.TrimRight(a, " \t\r") == strings.TrimRight(b, " \t\r")
+}
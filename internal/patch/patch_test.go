@@ -0,0 +1,204 @@
+package patch
+
+import (
+	"errors"
+	"strings"
+	"testing"
+)
+
+func TestParse(t *testing.T) {
+	tests := []struct {
+		name      string
+		diff      string
+		wantHunks int
+		wantStart int
+		wantErr   bool
+	}{
+		{
+			name:      "git style with file headers",
+			diff:      "diff --git a/x b/x\nindex 123..456\n--- a/x\n+++ b/x\n@@ -2,2 +2,2 @@\n b\n-c\n+C\n",
+			wantHunks: 1,
+			wantStart: 2,
+		},
+		{
+			name:      "two hunks",
+			diff:      "@@ -1 +1 @@\n-a\n+A\n@@ -5,1 +5,1 @@\n-e\n+E\n",
+			wantHunks: 2,
+			wantStart: 1,
+		},
+		{
+			name:      "bare header",
+			diff:      "@@\n-a\n+A\n",
+			wantHunks: 1,
+			wantStart: 0,
+		},
+		{
+			name:      "removed line that looks like a header",
+			diff:      "@@ -1,2 +1,1 @@\n--- rule\n a\n",
+			wantHunks: 1,
+			wantStart: 1,
+		},
+		{name: "no hunks", diff: "just some text\n", wantErr: true},
+		{name: "malformed header", diff: "@@ -x +y @@\n-a\n", wantErr: true},
+		{name: "empty hunk", diff: "@@ -1 +1 @@\n", wantErr: true},
+		{name: "bare header insertion without context", diff: "@@\n+a\n+b\n", wantErr: true},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			hunks, err := Parse(tt.diff)
+			if tt.wantErr {
+				if !errors.Is(err, ErrInvalidPatch) {
+					t.Errorf("Parse() error = %v, want ErrInvalidPatch", err)
+				}
+				return
+			}
+			if err != nil {
+				t.Fatalf("Parse() error = %v", err)
+			}
+			if len(hunks) != tt.wantHunks {
+				t.Fatalf("Parse() = %d hunks, want %d", len(hunks), tt.wantHunks)
+			}
+			if hunks[0].OldStart != tt.wantStart {
+				t.Errorf("Parse() OldStart = %d, want %d", hunks[0].OldStart, tt.wantStart)
+			}
+		})
+	}
+}
+
+func TestApply(t *testing.T) {
+	const doc = "a\nb\nc\nd\ne\n"
+
+	tests := []struct {
+		name    string
+		content string
+		diff    string
+		want    string
+		wantErr bool
+	}{
+		{
+			name:    "replace line",
+			content: doc,
+			diff:    "@@ -2,3 +2,3 @@\n b\n-c\n+C\n d\n",
+			want:    "a\nb\nC\nd\ne\n",
+		},
+		{
+			name:    "insert and delete",
+			content: doc,
+			diff:    "@@ -1,3 +1,3 @@\n a\n+a2\n b\n-c\n",
+			want:    "a\na2\nb\nd\ne\n",
+		},
+		{
+			name:    "multiple hunks shift later positions",
+			content: doc,
+			diff:    "@@ -1,1 +1,2 @@\n a\n+a2\n@@ -4,2 +5,2 @@\n d\n-e\n+E\n",
+			want:    "a\na2\nb\nc\nd\nE\n",
+		},
+		{
+			name:    "wrong line numbers are found by context",
+			content: doc,
+			diff:    "@@ -40,2 +40,2 @@\n d\n-e\n+E\n",
+			want:    "a\nb\nc\nd\nE\n",
+		},
+		{
+			name:    "bare header",
+			content: doc,
+			diff:    "@@\n c\n-d\n+D\n",
+			want:    "a\nb\nc\nD\ne\n",
+		},
+		{
+			name:    "trailing whitespace fuzz keeps document context",
+			content: "a  \nb\n",
+			diff:    "@@ -1,2 +1,2 @@\n a\n-b\n+B\n",
+			want:    "a  \nB\n",
+		},
+		{
+			name:    "nearest match wins",
+			content: "x\ny\nx\ny\n",
+			diff:    "@@ -3,2 +3,2 @@\n x\n-y\n+Y\n",
+			want:    "x\ny\nx\nY\n",
+		},
+		{
+			name:    "no trailing newline preserved",
+			content: "a\nb",
+			diff:    "@@ -2 +2 @@\n-b\n+B\n",
+			want:    "a\nB",
+		},
+		{
+			name:    "add to empty document",
+			content: "",
+			diff:    "@@ -0,0 +1,2 @@\n+a\n+b\n",
+			want:    "a\nb\n",
+		},
+		{
+			name:    "blank context line without space",
+			content: "a\n\nb\n",
+			diff:    "@@ -1,3 +1,3 @@\n a\n\n-b\n+B\n",
+			want:    "a\n\nB\n",
+		},
+		{
+			name:    "trailing blank context line picks the place",
+			content: "a\nx\ny\na\nx\n\nz\n",
+			diff:    "@@\n a\n-x\n+X\n\n",
+			want:    "a\nx\ny\na\nX\n\nz\n",
+		},
+		{
+			name:    "trailing blank line after the diff is ignored",
+			content: doc,
+			diff:    "@@ -2,3 +2,3 @@\n b\n-c\n+C\n d\n\n\n",
+			want:    "a\nb\nC\nd\ne\n",
+		},
+		{
+			name:    "context not found",
+			content: doc,
+			diff:    "@@ -2,2 +2,2 @@\n b\n-z\n+Z\n",
+			wantErr: true,
+		},
+		{
+			name:    "hunks out of order",
+			content: doc,
+			diff:    "@@ -4 +4 @@\n-d\n+D\n@@ -1 +1 @@\n-a\n+A\n",
+			wantErr: true,
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			hunks, err := Parse(tt.diff)
+			if err != nil {
+				t.Fatalf("Parse() error = %v", err)
+			}
+			got, err := Apply(tt.content, hunks)
+			if tt.wantErr {
+				if !errors.Is(err, ErrHunkFailed) {
+					t.Errorf("Apply() error = %v, want ErrHunkFailed", err)
+				}
+				return
+			}
+			if err != nil {
+				t.Fatalf("Apply() error = %v", err)
+			}
+			if got != tt.want {
+				t.Errorf("Apply() = %q, want %q", got, tt.want)
+			}
+		})
+	}
+}
+
+func TestHunkError_ReportsHunk(t *testing.T) {
+	hunks, err := Parse("@@ -1 +1 @@\n-a\n+A\n@@ -3 +3 @@\n-missing\n+found\n")
+	if err != nil {
+		t.Fatalf("Parse() error = %v", err)
+	}
+
+	_, err = Apply("a\nb\nc\n", hunks)
+
+	var he *HunkError
+	if !errors.As(err, &he) {
+		t.Fatalf("Apply() error = %v, want *HunkError", err)
+	}
+	if he.Index != 2 {
+		t.Errorf("HunkError.Index = %d, want 2", he.Index)
+	}
+	if !strings.Contains(err.Error(), "-missing") {
+		t.Errorf("error %q does not include the failing hunk", err)
+	}
+}
@@ -19,10 +19,45 @@
 // This ensures correct backslash handling on each platform.
 package path
 
-import "errors"
+import (
+	"errors"
+	"strings"
+)
 
 // ErrInvalid indicates the provided document path is invalid.
 var ErrInvalid = errors.New("invalid document path")
 
 // ErrTooLong indicates the document path exceeds the configured maximum length.
 var ErrTooLong = errors.New("document path too long")
+
+// Under reports whether path is prefix itself or anywhere beneath it. The
+// prefix must end at a segment boundary: "docs2/x" is not under "docs".
+// An empty prefix contains everything.
+func Under(path, prefix string) bool {
+	prefix = cleanPrefix(prefix)
+	return prefix == "" || path == prefix || strings.HasPrefix(path, prefix+"/")
+}
+
+// InScope reports whether a listing of prefix includes path: direct children
+// only (see Direct), or everything beneath prefix when recursive. ls and grep
+// both filter with it so that their -R/-r behaviour cannot drift apart.
+func InScope(path, prefix string, recursive bool) bool {
+	if recursive {
+		return Under(path, prefix)
+	}
+	return Direct(path, prefix)
+}
+
+// Depth returns how many segments path lies below prefix: 1 for a direct
+// child, 2 for a grandchild, and 0 for prefix itself. The result is only
+// meaningful when path is Under prefix.
+func Depth(path, prefix string) int {
+	prefix = cleanPrefix(prefix)
+	if path == prefix {
+		return 0
+	}
+	if prefix != "" {
+		path = strings.TrimPrefix(path, prefix+"/")
+	}
+	return strings.Count(path, "/") + 1
+}
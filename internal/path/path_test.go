@@ -46,6 +46,62 @@ func TestNormalise(t *testing.T) {
 	}
 }
 
+func TestInScope(t *testing.T) {
+	tests := []struct {
+		path      string
+		prefix    string
+		recursive bool
+		want      bool
+	}{
+		{"docs/readme", "docs/", false, true},
+		{"docs/api/auth", "docs/", false, false},
+		{"docs/api/auth", "docs/", true, true},
+		{"docs/api/auth", "docs", true, true},
+		{"docs", "docs/", true, true},
+
+		// Prefix as a substring but not at a directory boundary
+		{"docs2/x", "docs/", false, false},
+		{"docs2/x", "docs/", true, false},
+		{"docs2/x", "docs", true, false},
+		{"docs/readme", "docs/rea", true, false},
+
+		// Root level: everything when recursive, top level otherwise
+		{"readme", "", false, true},
+		{"docs/readme", "", false, false},
+		{"docs/readme", "", true, true},
+		{"readme", "", true, true},
+	}
+
+	for _, tt := range tests {
+		got := InScope(tt.path, tt.prefix, tt.recursive)
+		if got != tt.want {
+			t.Errorf("InScope(%q, %q, %v) = %v, want %v", tt.path, tt.prefix, tt.recursive, got, tt.want)
+		}
+	}
+}
+
+func TestDepth(t *testing.T) {
+	tests := []struct {
+		path   string
+		prefix string
+		want   int
+	}{
+		{"docs/readme", "docs/", 1},
+		{"docs/readme", "docs", 1},
+		{"docs/api/auth", "docs/", 2},
+		{"docs/api/v1/auth", "docs", 3},
+		{"docs", "docs/", 0},
+		{"readme", "", 1},
+		{"docs/api/auth", "", 3},
+	}
+
+	for _, tt := range tests {
+		if got := Depth(tt.path, tt.prefix); got != tt.want {
+			t.Errorf("Depth(%q, %q) = %d, want %d", tt.path, tt.prefix, got, tt.want)
+		}
+	}
+}
+
 func TestDirect(t *testing.T) {
 	tests := []struct {
 		path   string
@@ -73,6 +129,15 @@ func TestDirect(t *testing.T) {
 
 		// No match
 		{"notes/meeting", "docs", false},
+
+		// Prefix as a substring but not at a directory boundary
+		{"docs2/x", "docs/", false},
+		{"docs2", "docs", false},
+		{"docsx", "docs/", false},
+
+		// Root-level documents with a trailing-slash-only prefix
+		{"readme", "/", true},
+		{"docs/readme", "/", false},
 	}
 
 	for _, tt := range tests {
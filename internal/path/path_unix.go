@@ -62,10 +62,7 @@ func Normalise(p string) (string, error) {
 //   - "readme" -> true (top level)
 //   - "docs/readme" -> false (nested)
 func Direct(path, prefix string) bool {
-	// Normalise prefix: convert backslashes and remove trailing slash
-	// (filepath.ToSlash won't convert backslashes on Unix)
-	prefix = strings.ReplaceAll(prefix, "\\", "/")
-	prefix = strings.TrimSuffix(prefix, "/")
+	prefix = cleanPrefix(prefix)
 
 	// Exact match
 	if path == prefix {
@@ -85,3 +82,10 @@ func Direct(path, prefix string) bool {
 	// Direct child = no "/" in the remainder
 	return !strings.Contains(remainder, "/")
 }
+
+// cleanPrefix normalises a listing prefix from raw user input: backslashes
+// become forward slashes and a trailing slash is removed.
+func cleanPrefix(prefix string) string {
+	// filepath.ToSlash won't convert backslashes on Unix
+	return strings.TrimSuffix(strings.ReplaceAll(prefix, "\\", "/"), "/")
+}
@@ -58,9 +58,7 @@ func Normalise(p string) (string, error) {
 //   - "readme" -> true (top level)
 //   - "docs/readme" -> false (nested)
 func Direct(path, prefix string) bool {
-	// Normalise prefix: convert backslashes and remove trailing slash
-	prefix = filepath.ToSlash(prefix)
-	prefix = strings.TrimSuffix(prefix, "/")
+	prefix = cleanPrefix(prefix)
 
 	// Exact match
 	if path == prefix {
@@ -80,3 +78,9 @@ func Direct(path, prefix string) bool {
 	// Direct child = no "/" in the remainder
 	return !strings.Contains(remainder, "/")
 }
+
+// cleanPrefix normalises a listing prefix from raw user input: backslashes
+// become forward slashes and a trailing slash is removed.
+func cleanPrefix(prefix string) string {
+	return strings.TrimSuffix(filepath.ToSlash(prefix), "/")
+}
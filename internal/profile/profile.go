@@ -0,0 +1,76 @@
+// Package profile serves the net/http/pprof endpoints for a running server.
+//
+// llmd serve can run for days under real workloads (large greps, bulk
+// imports), which is where performance problems show up. With --pprof the
+// standard profiling handlers are served on their own listener so a CPU or
+// heap profile can be taken without rebuilding:
+//
+//	go tool pprof http://localhost:6060/debug/pprof/profile
+//
+// Design: The handlers are mounted on a private mux rather than
+// http.DefaultServeMux, and on a separate listener from the MCP and REST
+// servers, so profiling is never reachable through the document API and
+// never touches the stdio transport that carries JSON-RPC.
+package profile
+
+import (
+	"context"
+	"errors"
+	"log/slog"
+	"net"
+	"net/http"
+	"net/http/pprof"
+	"time"
+)
+
+// DefaultHost is the host Start listens on when addr has none (":6060").
+const DefaultHost = "localhost"
+
+// shutdownTimeout bounds how long an in-flight profile may run after the
+// server is asked to stop.
+const shutdownTimeout = 5 * time.Second
+
+// Handler returns a handler serving the pprof endpoints under /debug/pprof/.
+func Handler() http.Handler {
+	mux := http.NewServeMux()
+	mux.HandleFunc("/debug/pprof/", pprof.Index)
+	mux.HandleFunc("/debug/pprof/cmdline", pprof.Cmdline)
+	mux.HandleFunc("/debug/pprof/profile", pprof.Profile)
+	mux.HandleFunc("/debug/pprof/symbol", pprof.Symbol)
+	mux.HandleFunc("/debug/pprof/trace", pprof.Trace)
+	return mux
+}
+
+// Start listens on addr and serves the pprof endpoints in the background
+// until ctx is cancelled. The listener is opened before Start returns, so
+// an address already in use is reported to the caller rather than lost in
+// the background.
+func Start(ctx context.Context, addr string) error {
+	// Heap profiles hold document content and the store passphrase, so a
+	// bare port must not mean every interface as it does for net.Listen
+	if host, port, err := net.SplitHostPort(addr); err == nil && host == "" {
+		addr = net.JoinHostPort(DefaultHost, port)
+	}
+	ln, err := net.Listen("tcp", addr)
+	if err != nil {
+		return err
+	}
+
+	srv := &http.Server{
+		Handler:           Handler(),
+		ReadHeaderTimeout: 10 * time.Second,
+	}
+	go func() {
+		if err := srv.Serve(ln); err != nil && !errors.Is(err, http.ErrServerClosed) {
+			slog.Error("pprof server failed", "error", err)
+		}
+	}()
+	go func() {
+		<-ctx.Done()
+		shutdownCtx, cancel := context.WithTimeout(context.Background(), shutdownTimeout)
+		defer cancel()
+		_ = srv.Shutdown(shutdownCtx)
+	}()
+	slog.Info("pprof server ready", "addr", ln.Addr().String())
+	return nil
+}
@@ -0,0 +1,44 @@
+package profile
+
+import (
+	"context"
+	"net"
+	"net/http"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestStart_BarePort(t *testing.T) {
+	ln, err := net.Listen("tcp", "127.0.0.1:0")
+	require.NoError(t, err)
+	_, port, err := net.SplitHostPort(ln.Addr().String())
+	require.NoError(t, err)
+	require.NoError(t, ln.Close())
+
+	ctx, cancel := context.WithCancel(context.Background())
+	defer cancel()
+	require.NoError(t, Start(ctx, ":"+port))
+
+	resp, err := http.Get("http://127.0.0.1:" + port + "/debug/pprof/")
+	require.NoError(t, err)
+	resp.Body.Close()
+	assert.Equal(t, http.StatusOK, resp.StatusCode)
+
+	// Claiming the port on another interface succeeds only if the profiler
+	// is not listening there
+	addrs, err := net.InterfaceAddrs()
+	require.NoError(t, err)
+	for _, a := range addrs {
+		ipnet, ok := a.(*net.IPNet)
+		if !ok || ipnet.IP.IsLoopback() || ipnet.IP.To4() == nil {
+			continue
+		}
+		other, err := net.Listen("tcp", net.JoinHostPort(ipnet.IP.String(), port))
+		require.NoError(t, err, "port %s is taken on %s", port, ipnet.IP)
+		require.NoError(t, other.Close())
+		return
+	}
+	t.Skip("no non-loopback IPv4 interface to check")
+}
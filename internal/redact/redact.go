@@ -0,0 +1,68 @@
+// Package redact masks secrets in document content on its way out.
+//
+// Redaction is an output filter: stored content is never changed, so the
+// same document reads in full with "cat" and masked with "cat --redact" or
+// through an MCP server configured with redact.mcp. The patterns come from
+// config (redact.patterns); without any, Defaults covers common secrets.
+//
+// Design: Each match is replaced by Mask followed by as many newlines as the
+// match spanned, so a multi-line secret such as a private key block leaves
+// the line numbering intact and "cat -n" or "cat --lines" still point at the
+// same lines as grep and edit.
+package redact
+
+import (
+	"fmt"
+	"regexp"
+	"strings"
+)
+
+// Mask replaces each redacted match.
+const Mask = "[REDACTED]"
+
+// Defaults are the patterns used when config sets none: email addresses,
+// private key blocks, bearer tokens and the API keys of common services.
+var Defaults = []string{
+	`[A-Za-z0-9._%+-]+@[A-Za-z0-9.-]+\.[A-Za-z]{2,}`,                            // Email address
+	`(?s)-----BEGIN [A-Z ]*PRIVATE KEY-----.*?-----END [A-Z ]*PRIVATE KEY-----`, // PEM private key
+	`(?i)\bbearer\s+[A-Za-z0-9._~+/-]{16,}=*`,                                   // HTTP bearer token
+	`\b(?:AKIA|ASIA)[0-9A-Z]{16}\b`,                                             // AWS access key ID
+	`\bgh[pousr]_[A-Za-z0-9]{36,}\b`,                                            // GitHub token
+	`\bxox[abprs]-[A-Za-z0-9-]{10,}`,                                            // Slack token
+	`\bsk-[A-Za-z0-9_-]{20,}`,                                                   // OpenAI-style secret key
+}
+
+// Redactor masks the matches of a set of patterns.
+type Redactor struct {
+	patterns []*regexp.Regexp
+}
+
+// New compiles patterns, using Defaults if there are none.
+func New(patterns []string) (*Redactor, error) {
+	if len(patterns) == 0 {
+		patterns = Defaults
+	}
+	r := &Redactor{patterns: make([]*regexp.Regexp, 0, len(patterns))}
+	for _, p := range patterns {
+		re, err := regexp.Compile(p)
+		if err != nil {
+			return nil, fmt.Errorf("invalid redaction pattern %q: %w", p, err)
+		}
+		r.patterns = append(r.patterns, re)
+	}
+	return r, nil
+}
+
+// Apply returns s with every match of every pattern masked. Patterns are
+// applied in order, each to the output of the last.
+func (r *Redactor) Apply(s string) string {
+	for _, re := range r.patterns {
+		s = re.ReplaceAllStringFunc(s, mask)
+	}
+	return s
+}
+
+// mask replaces a match, keeping its line breaks.
+func mask(match string) string {
+	return Mask + strings.Repeat("\n", strings.Count(match, "\n"))
+}
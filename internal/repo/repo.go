@@ -13,6 +13,7 @@
 package repo
 
 import (
+	"context"
 	"errors"
 	"fmt"
 	"os"
@@ -61,6 +62,13 @@ var ErrNotInitialised = errors.New("llmd not initialised (run 'llmd init')")
 //   - local: add database to .gitignore (not committed)
 //   - dir: target directory (empty for current directory)
 func Init(force bool, db string, local bool, dir string) error {
+	return InitEncrypted(force, db, local, dir, "")
+}
+
+// InitEncrypted is Init for a store whose document content is encrypted
+// with a key derived from passphrase. An empty passphrase creates a
+// plaintext store, as Init does.
+func InitEncrypted(force bool, db string, local bool, dir, passphrase string) error {
 	if dir == "" {
 		dir = "."
 	}
@@ -93,6 +101,11 @@ func Init(force bool, db string, local bool, dir string) error {
 	if err := s.Init(); err != nil {
 		return fmt.Errorf("init store: %w", err)
 	}
+	if passphrase != "" {
+		if err := s.EnableEncryption(context.Background(), passphrase); err != nil {
+			return fmt.Errorf("encrypt store: %w", err)
+		}
+	}
 
 	// Create .gitignore if it doesn't exist.
 	// Only create on first init - subsequent inits (for additional databases)
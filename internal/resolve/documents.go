@@ -0,0 +1,72 @@
+// documents.go expands path, key, glob and prefix arguments into documents.
+//
+// Separated from resolve.go, which explains how one argument is read, because
+// this is shared plumbing: wc, tokens and pack each take a mix of targets and
+// need the same expansion, errors and de-duplication.
+
+package resolve
+
+import (
+	"context"
+	"fmt"
+	"slices"
+	"strings"
+
+	"github.com/jpl-au/llmd/internal/glob"
+	"github.com/jpl-au/llmd/internal/service"
+	"github.com/jpl-au/llmd/internal/store"
+)
+
+// Documents loads the latest version of every document named by targets,
+// for commands that report on a set of documents. A target may be a path,
+// a key, a glob pattern or a prefix ending in "/"; a pattern or prefix
+// matching nothing is an error. Each document appears once, in the order
+// first named.
+func Documents(ctx context.Context, svc service.Service, targets []string) ([]*store.Document, error) {
+	var docs []*store.Document
+	var seen []string
+	add := func(d *store.Document) {
+		if !slices.Contains(seen, d.Path) {
+			seen = append(seen, d.Path)
+			docs = append(docs, d)
+		}
+	}
+
+	for _, t := range targets {
+		switch {
+		case glob.IsPattern(t):
+			paths, err := svc.Glob(ctx, t)
+			if err != nil {
+				return nil, err
+			}
+			if len(paths) == 0 {
+				return nil, fmt.Errorf("%s: no documents match", t)
+			}
+			for _, p := range paths {
+				d, err := svc.Latest(ctx, p, false)
+				if err != nil {
+					return nil, fmt.Errorf("%s: %w", p, err)
+				}
+				add(d)
+			}
+		case strings.HasSuffix(t, "/"):
+			list, err := svc.List(ctx, t, false, false)
+			if err != nil {
+				return nil, err
+			}
+			if len(list) == 0 {
+				return nil, fmt.Errorf("%s: no documents under prefix", t)
+			}
+			for i := range list {
+				add(&list[i])
+			}
+		default:
+			d, _, err := svc.Resolve(ctx, t, false)
+			if err != nil {
+				return nil, fmt.Errorf("%s: %w", t, err)
+			}
+			add(d)
+		}
+	}
+	return docs, nil
+}
@@ -0,0 +1,68 @@
+// Package resolve reports how a path-or-key argument is interpreted.
+//
+// Every command taking "<path|key>" goes through Service.Resolve, which
+// prefers a path when an 8-character input could also be a key. That
+// choice is silent, so a document whose path happens to look like a key
+// can hide the version the user meant. Run makes the decision visible.
+package resolve
+
+import (
+	"context"
+	"fmt"
+	"io"
+
+	"github.com/jpl-au/llmd/internal/service"
+)
+
+// How an input was interpreted.
+const (
+	AsPath = "path"
+	AsKey  = "key"
+)
+
+// Result describes how an input resolved.
+type Result struct {
+	Input         string `json:"input"`
+	ResolvedPath  string `json:"resolved_path"`
+	Key           string `json:"key"`
+	Version       int    `json:"version"`
+	InterpretedAs string `json:"interpreted_as"`
+
+	// Shadows is set when the input resolved as a path but is also a version
+	// key: the path of the document that key belongs to. That version can
+	// then only be reached through its own path, with cat -v.
+	Shadows string `json:"shadows,omitempty"`
+}
+
+// Run resolves input as every "<path|key>" argument is resolved and writes
+// the interpretation to w.
+func Run(ctx context.Context, w io.Writer, svc service.Service, input string, includeDeleted bool) (Result, error) {
+	result := Result{Input: input}
+
+	doc, isKey, err := svc.Resolve(ctx, input, includeDeleted)
+	if err != nil {
+		return result, err
+	}
+	result.ResolvedPath = doc.Path
+	result.Key = doc.Key
+	result.Version = doc.Version
+	result.InterpretedAs = AsPath
+	if isKey {
+		result.InterpretedAs = AsKey
+	}
+
+	// Resolve only looks up the key when the path misses, so check whether
+	// a path won over a key of the same name
+	if !isKey && len(input) == 8 {
+		if keyDoc, err := svc.ByKey(ctx, input); err == nil {
+			result.Shadows = keyDoc.Path
+			fmt.Fprintf(w, "%s -> %s v%d (key %s, as %s; shadows key of %s v%d)\n",
+				input, result.ResolvedPath, result.Version, result.Key, result.InterpretedAs, keyDoc.Path, keyDoc.Version)
+			return result, nil
+		}
+	}
+
+	fmt.Fprintf(w, "%s -> %s v%d (key %s, as %s)\n",
+		input, result.ResolvedPath, result.Version, result.Key, result.InterpretedAs)
+	return result, nil
+}
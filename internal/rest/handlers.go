@@ -0,0 +1,280 @@
+// handlers.go implements the REST endpoints.
+//
+// Each handler mirrors an MCP tool: read-only endpoints default the author
+// to "http" for the audit log, while writes reject requests without one.
+// Document paths are taken from the remainder of the URL, so
+// /documents/docs/api/auth addresses "docs/api/auth".
+
+package rest
+
+import (
+	"fmt"
+	"io"
+	"net/http"
+	"strconv"
+
+	"github.com/jpl-au/llmd/internal/log"
+	"github.com/jpl-au/llmd/internal/store"
+)
+
+// defaultAuthor attributes read requests that do not identify themselves.
+const defaultAuthor = "http"
+
+// author returns the request's author from the header or query string.
+func author(r *http.Request) string {
+	if a := r.Header.Get(HeaderAuthor); a != "" {
+		return a
+	}
+	return r.URL.Query().Get("author")
+}
+
+// readAuthor returns the author for logging a read-only request.
+func readAuthor(r *http.Request) string {
+	if a := author(r); a != "" {
+		return a
+	}
+	return defaultAuthor
+}
+
+// queryBool parses an optional boolean query parameter.
+func queryBool(r *http.Request, name string) (bool, error) {
+	v := r.URL.Query().Get(name)
+	if v == "" {
+		return false, nil
+	}
+	b, err := strconv.ParseBool(v)
+	if err != nil {
+		return false, fmt.Errorf("%w: %s must be true or false, got %q", errBadRequest, name, v)
+	}
+	return b, nil
+}
+
+// queryInt parses an optional non-negative integer query parameter.
+func queryInt(r *http.Request, name string) (int, error) {
+	v := r.URL.Query().Get(name)
+	if v == "" {
+		return 0, nil
+	}
+	n, err := strconv.Atoi(v)
+	if err != nil || n < 0 {
+		return 0, fmt.Errorf("%w: %s must be a non-negative integer, got %q", errBadRequest, name, v)
+	}
+	return n, nil
+}
+
+// toJSON converts documents to their API representation.
+func toJSON(docs []store.Document, content bool) []store.DocJSON {
+	out := make([]store.DocJSON, len(docs))
+	for i := range docs {
+		out[i] = docs[i].ToJSON(content)
+	}
+	return out
+}
+
+// list handles GET /documents?prefix=&tag=&deleted=&deleted_only=.
+func (h *handlers) list(w http.ResponseWriter, r *http.Request) {
+	q := r.URL.Query()
+	prefix, tag := q.Get("prefix"), q.Get("tag")
+
+	var err error
+	l := log.Event("http:list", "list").Author(readAuthor(r)).Path(prefix).Detail("tag", tag)
+	defer func() { l.Write(err) }()
+
+	deleted, err := queryBool(r, "deleted")
+	if err != nil {
+		writeError(w, err)
+		return
+	}
+	deletedOnly, err := queryBool(r, "deleted_only")
+	if err != nil {
+		writeError(w, err)
+		return
+	}
+
+	var docs []store.Document
+	if tag != "" {
+		docs, err = h.svc.ListByTag(r.Context(), prefix, tag, deleted, deletedOnly, store.NewTagOptions())
+	} else {
+		docs, err = h.svc.List(r.Context(), prefix, deleted, deletedOnly)
+	}
+	if err != nil {
+		writeError(w, err)
+		return
+	}
+
+	l.Detail("count", len(docs))
+	writeJSON(w, http.StatusOK, toJSON(docs, false))
+}
+
+// read handles GET /documents/{path}?version=&deleted=. The path may also
+// be a version key.
+func (h *handlers) read(w http.ResponseWriter, r *http.Request) {
+	p := r.PathValue("path")
+
+	var err error
+	l := log.Event("http:read", "read").Author(readAuthor(r)).Path(p)
+	defer func() { l.Write(err) }()
+
+	version, err := queryInt(r, "version")
+	if err != nil {
+		writeError(w, err)
+		return
+	}
+	deleted, err := queryBool(r, "deleted")
+	if err != nil {
+		writeError(w, err)
+		return
+	}
+
+	var doc *store.Document
+	if version > 0 {
+		doc, err = h.svc.Version(r.Context(), p, version)
+	} else {
+		doc, _, err = h.svc.Resolve(r.Context(), p, deleted)
+	}
+	if err != nil {
+		writeError(w, fmt.Errorf("read %q: %w", p, err))
+		return
+	}
+
+	l.Resolved(doc.Path)
+	writeJSON(w, http.StatusOK, doc.ToJSON(true))
+}
+
+// write handles PUT /documents/{path}. The request body is the new content.
+// Responds with the new version's metadata.
+func (h *handlers) write(w http.ResponseWriter, r *http.Request) {
+	p := r.PathValue("path")
+	a := author(r)
+
+	var err error
+	l := log.Event("http:write", "write").Author(a).Path(p)
+	defer func() { l.Write(err) }()
+
+	if a == "" {
+		err = fmt.Errorf("%w: author is required (%s header or author query parameter)", errBadRequest, HeaderAuthor)
+		writeError(w, err)
+		return
+	}
+	msg := r.Header.Get(HeaderMessage)
+	if msg == "" {
+		msg = r.URL.Query().Get("message")
+	}
+
+	body := r.Body
+	if h.opts.MaxBody > 0 {
+		body = http.MaxBytesReader(w, r.Body, h.opts.MaxBody)
+	}
+	content, err := io.ReadAll(body)
+	if err != nil {
+		writeError(w, fmt.Errorf("read body: %w", err))
+		return
+	}
+
+	if err = h.svc.Write(r.Context(), p, string(content), a, msg); err != nil {
+		writeError(w, fmt.Errorf("write %q: %w", p, err))
+		return
+	}
+
+	doc, err := h.svc.Latest(r.Context(), p, false)
+	if err != nil {
+		writeError(w, fmt.Errorf("write %q: %w", p, err))
+		return
+	}
+	writeJSON(w, http.StatusOK, doc.ToJSON(false))
+}
+
+// history handles GET /history/{path}?limit=&deleted=.
+func (h *handlers) history(w http.ResponseWriter, r *http.Request) {
+	p := r.PathValue("path")
+
+	var err error
+	l := log.Event("http:history", "history").Author(readAuthor(r)).Path(p)
+	defer func() { l.Write(err) }()
+
+	limit, err := queryInt(r, "limit")
+	if err != nil {
+		writeError(w, err)
+		return
+	}
+	deleted, err := queryBool(r, "deleted")
+	if err != nil {
+		writeError(w, err)
+		return
+	}
+
+	doc, _, err := h.svc.Resolve(r.Context(), p, deleted)
+	if err != nil {
+		writeError(w, fmt.Errorf("history %q: %w", p, err))
+		return
+	}
+	l.Resolved(doc.Path)
+
+	docs, err := h.svc.History(r.Context(), doc.Path, limit, deleted)
+	if err != nil {
+		writeError(w, fmt.Errorf("history %q: %w", doc.Path, err))
+		return
+	}
+
+	l.Detail("count", len(docs))
+	writeJSON(w, http.StatusOK, toJSON(docs, false))
+}
+
+// links handles GET /links/{path}?tag=&direction=.
+func (h *handlers) links(w http.ResponseWriter, r *http.Request) {
+	p := r.PathValue("path")
+	tag := r.URL.Query().Get("tag")
+	direction := r.URL.Query().Get("direction")
+
+	var err error
+	l := log.Event("http:links", "list").Author(readAuthor(r)).Path(p).Detail("tag", tag).Detail("direction", direction)
+	defer func() { l.Write(err) }()
+
+	links, err := h.svc.ListLinks(r.Context(), p, tag, store.NewLinkOptions().WithDirection(direction))
+	if err != nil {
+		writeError(w, fmt.Errorf("links %q: %w", p, err))
+		return
+	}
+
+	out := make([]store.LinkJSON, len(links))
+	for i := range links {
+		out[i] = links[i].ToJSON()
+	}
+	l.Detail("count", len(out))
+	writeJSON(w, http.StatusOK, out)
+}
+
+// search handles GET /search?q=&prefix=&deleted=&deleted_only=.
+func (h *handlers) search(w http.ResponseWriter, r *http.Request) {
+	q := r.URL.Query()
+	query, prefix := q.Get("q"), q.Get("prefix")
+
+	var err error
+	l := log.Event("http:search", "search").Author(readAuthor(r)).Path(prefix).Detail("query", query)
+	defer func() { l.Write(err) }()
+
+	if query == "" {
+		err = fmt.Errorf("%w: q is required", errBadRequest)
+		writeError(w, err)
+		return
+	}
+	deleted, err := queryBool(r, "deleted")
+	if err != nil {
+		writeError(w, err)
+		return
+	}
+	deletedOnly, err := queryBool(r, "deleted_only")
+	if err != nil {
+		writeError(w, err)
+		return
+	}
+
+	docs, err := h.svc.Search(r.Context(), query, prefix, deleted, deletedOnly)
+	if err != nil {
+		writeError(w, err)
+		return
+	}
+
+	l.Detail("count", len(docs))
+	writeJSON(w, http.StatusOK, toJSON(docs, true))
+}
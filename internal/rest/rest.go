@@ -0,0 +1,189 @@
+// Package rest exposes the document store as a small JSON-over-HTTP API.
+//
+// This complements the MCP server (stdio, for LLMs) and the CLI (for people
+// and scripts) with an interface web tooling can call directly. Responses
+// use the same shapes as the CLI's -o json output and the MCP tools
+// (store.DocJSON, store.LinkJSON), so clients can move between them.
+//
+// Design: Plain net/http with Go's pattern routing; the API is small enough
+// that a framework would add more than it saves. Writes require an author,
+// supplied by the X-LLMD-Author header or an author query parameter, for the
+// same audit reasons MCP requires one. Sentinel errors from the service
+// layer map to HTTP status codes so clients can branch without parsing text.
+//
+// Security: There is no authentication; the author header is attribution,
+// not identity. An address without a host listens on localhost only, so
+// exposing the API to other machines takes an explicit host such as
+// 0.0.0.0, ideally behind a proxy that authenticates.
+package rest
+
+import (
+	"context"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"log/slog"
+	"net"
+	"net/http"
+	"time"
+
+	"github.com/jpl-au/llmd/internal/config"
+	"github.com/jpl-au/llmd/internal/document"
+	"github.com/jpl-au/llmd/internal/edit"
+	"github.com/jpl-au/llmd/internal/path"
+	"github.com/jpl-au/llmd/internal/service"
+	"github.com/jpl-au/llmd/internal/store"
+	"github.com/jpl-au/llmd/internal/validate"
+)
+
+// Request headers recognised by write endpoints.
+const (
+	HeaderAuthor  = "X-LLMD-Author"
+	HeaderMessage = "X-LLMD-Message"
+)
+
+// shutdownTimeout bounds how long in-flight requests may run after the
+// server is asked to stop.
+const shutdownTimeout = 5 * time.Second
+
+// Options configures the HTTP handler.
+type Options struct {
+	MaxBody int64 // Largest accepted request body in bytes (0 = no limit)
+}
+
+// ServeOptions configures which store Serve opens.
+type ServeOptions struct {
+	Ephemeral bool // Serve an in-memory store that is discarded on exit
+	ReadOnly  bool // Open the store read-only; changes fail with 403
+}
+
+// DefaultHost is the host Serve listens on when addr has none (":8080").
+const DefaultHost = "localhost"
+
+// Serve listens on addr and serves the API for database db until ctx is
+// cancelled, then shuts down gracefully.
+func Serve(ctx context.Context, addr, db string, opts ServeOptions) error {
+	if opts.Ephemeral && opts.ReadOnly {
+		return errors.New("an ephemeral store cannot be read-only")
+	}
+
+	var svc *document.Service
+	var err error
+	if opts.Ephemeral {
+		svc, err = document.NewMemory()
+	} else {
+		svc, err = document.NewWithOptions(db, document.Options{ReadOnly: opts.ReadOnly})
+	}
+	if err != nil {
+		return fmt.Errorf("open store: %w", err)
+	}
+	defer svc.Close()
+
+	cfg, err := config.Load()
+	if err != nil {
+		return err
+	}
+
+	// The API is unauthenticated, so a bare port must not mean every
+	// interface as it does for net.Listen
+	if host, port, err := net.SplitHostPort(addr); err == nil && host == "" {
+		addr = net.JoinHostPort(DefaultHost, port)
+	}
+	ln, err := net.Listen("tcp", addr)
+	if err != nil {
+		return err
+	}
+
+	srv := &http.Server{
+		Handler:           NewHandler(svc, Options{MaxBody: cfg.MaxContent()}),
+		ReadHeaderTimeout: 10 * time.Second,
+	}
+
+	errc := make(chan error, 1)
+	go func() { errc <- srv.Serve(ln) }()
+	slog.Info("llmd HTTP server ready", "addr", ln.Addr().String())
+
+	select {
+	case err := <-errc:
+		return err
+	case <-ctx.Done():
+	}
+
+	shutdownCtx, cancel := context.WithTimeout(context.Background(), shutdownTimeout)
+	defer cancel()
+	if err := srv.Shutdown(shutdownCtx); err != nil {
+		return err
+	}
+	slog.Info("server stopped")
+	return nil
+}
+
+// NewHandler returns the API routes backed by svc.
+func NewHandler(svc service.Service, opts Options) http.Handler {
+	h := &handlers{svc: svc, opts: opts}
+
+	mux := http.NewServeMux()
+	mux.HandleFunc("GET /documents", h.list)
+	mux.HandleFunc("GET /documents/{path...}", h.read)
+	mux.HandleFunc("PUT /documents/{path...}", h.write)
+	mux.HandleFunc("GET /history/{path...}", h.history)
+	mux.HandleFunc("GET /links/{path...}", h.links)
+	mux.HandleFunc("GET /search", h.search)
+	return mux
+}
+
+// handlers holds the service shared by all endpoints.
+type handlers struct {
+	svc  service.Service
+	opts Options
+}
+
+// errorBody is the JSON shape of every error response, matching the CLI's
+// -o json error output.
+type errorBody struct {
+	Error string `json:"error"`
+}
+
+// writeJSON encodes v as the response body with the given status.
+func writeJSON(w http.ResponseWriter, status int, v any) {
+	w.Header().Set("Content-Type", "application/json")
+	w.WriteHeader(status)
+	_ = json.NewEncoder(w).Encode(v)
+}
+
+// writeError reports err with the status its sentinel maps to.
+func writeError(w http.ResponseWriter, err error) {
+	writeJSON(w, statusFor(err), errorBody{Error: err.Error()})
+}
+
+// statusFor maps service errors to HTTP status codes. Anything unrecognised
+// is a server error rather than the client's fault.
+func statusFor(err error) int {
+	var tooLarge *http.MaxBytesError
+	switch {
+	case errors.Is(err, store.ErrNotFound):
+		return http.StatusNotFound
+	case errors.Is(err, store.ErrAlreadyExists):
+		return http.StatusConflict
+	case errors.Is(err, store.ErrReadOnly):
+		return http.StatusForbidden
+	case errors.Is(err, store.ErrContentTooLarge),
+		errors.Is(err, validate.ErrContentTooLarge),
+		errors.As(err, &tooLarge):
+		return http.StatusRequestEntityTooLarge
+	case errors.Is(err, validate.ErrInvalidPath),
+		errors.Is(err, validate.ErrPathTooLong),
+		errors.Is(err, path.ErrInvalid),
+		errors.Is(err, path.ErrTooLong),
+		errors.Is(err, edit.ErrInvalidLineRange),
+		errors.Is(err, store.ErrInvalidDirection),
+		errors.Is(err, validate.ErrInvalidContentType),
+		errors.Is(err, document.ErrInvalidAuthor),
+		errors.Is(err, errBadRequest):
+		return http.StatusBadRequest
+	}
+	return http.StatusInternalServerError
+}
+
+// errBadRequest marks malformed requests (missing author, bad parameters).
+var errBadRequest = errors.New("bad request")
@@ -0,0 +1,146 @@
+package rest_test
+
+import (
+	"encoding/json"
+	"io"
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"testing"
+
+	"github.com/jpl-au/llmd/internal/document"
+	"github.com/jpl-au/llmd/internal/rest"
+	"github.com/jpl-au/llmd/internal/store"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+// setupServer creates a temporary store and serves the API over it.
+func setupServer(t *testing.T) *httptest.Server {
+	t.Helper()
+
+	svc, err := document.NewMemory()
+	require.NoError(t, err, "creating service")
+	t.Cleanup(func() { svc.Close() })
+
+	srv := httptest.NewServer(rest.NewHandler(svc, rest.Options{MaxBody: 64}))
+	t.Cleanup(srv.Close)
+	return srv
+}
+
+// do sends a request and returns the status and body.
+func do(t *testing.T, method, url, body string, header map[string]string) (int, string) {
+	t.Helper()
+	req, err := http.NewRequest(method, url, strings.NewReader(body))
+	require.NoError(t, err)
+	for k, v := range header {
+		req.Header.Set(k, v)
+	}
+	resp, err := http.DefaultClient.Do(req)
+	require.NoError(t, err)
+	defer resp.Body.Close()
+	data, err := io.ReadAll(resp.Body)
+	require.NoError(t, err)
+	return resp.StatusCode, string(data)
+}
+
+func TestREST_WriteAndRead(t *testing.T) {
+	srv := setupServer(t)
+	author := map[string]string{rest.HeaderAuthor: "tester"}
+
+	status, body := do(t, http.MethodPut, srv.URL+"/documents/docs/readme", "# Hello", author)
+	require.Equal(t, http.StatusOK, status, body)
+	var written store.DocJSON
+	require.NoError(t, json.Unmarshal([]byte(body), &written))
+	assert.Equal(t, "docs/readme", written.Path)
+	assert.Equal(t, 1, written.Version)
+	assert.Equal(t, "tester", written.Author)
+
+	// Author may also come from the query string
+	status, body = do(t, http.MethodPut, srv.URL+"/documents/docs/readme?author=other&message=second", "# Hello again", nil)
+	require.Equal(t, http.StatusOK, status, body)
+
+	status, body = do(t, http.MethodGet, srv.URL+"/documents/docs/readme", "", nil)
+	require.Equal(t, http.StatusOK, status, body)
+	var doc store.DocJSON
+	require.NoError(t, json.Unmarshal([]byte(body), &doc))
+	assert.Equal(t, "# Hello again", doc.Content)
+	assert.Equal(t, "other", doc.Author)
+	assert.Equal(t, "second", doc.Message)
+
+	status, body = do(t, http.MethodGet, srv.URL+"/documents/docs/readme?version=1", "", nil)
+	require.Equal(t, http.StatusOK, status, body)
+	require.NoError(t, json.Unmarshal([]byte(body), &doc))
+	assert.Equal(t, "# Hello", doc.Content)
+
+	status, body = do(t, http.MethodGet, srv.URL+"/history/docs/readme", "", nil)
+	require.Equal(t, http.StatusOK, status, body)
+	var history []store.DocJSON
+	require.NoError(t, json.Unmarshal([]byte(body), &history))
+	require.Len(t, history, 2)
+	assert.Equal(t, 2, history[0].Version)
+	assert.Empty(t, history[0].Content, "history omits content")
+}
+
+func TestREST_ListAndSearch(t *testing.T) {
+	srv := setupServer(t)
+	author := map[string]string{rest.HeaderAuthor: "tester"}
+	do(t, http.MethodPut, srv.URL+"/documents/docs/a", "alpha content", author)
+	do(t, http.MethodPut, srv.URL+"/documents/notes/b", "bravo content", author)
+
+	status, body := do(t, http.MethodGet, srv.URL+"/documents?prefix=docs/", "", nil)
+	require.Equal(t, http.StatusOK, status, body)
+	var docs []store.DocJSON
+	require.NoError(t, json.Unmarshal([]byte(body), &docs))
+	require.Len(t, docs, 1)
+	assert.Equal(t, "docs/a", docs[0].Path)
+
+	status, body = do(t, http.MethodGet, srv.URL+"/search?q=bravo", "", nil)
+	require.Equal(t, http.StatusOK, status, body)
+	require.NoError(t, json.Unmarshal([]byte(body), &docs))
+	require.Len(t, docs, 1)
+	assert.Equal(t, "notes/b", docs[0].Path)
+	assert.Equal(t, "bravo content", docs[0].Content)
+
+	status, body = do(t, http.MethodGet, srv.URL+"/links/docs/a", "", nil)
+	require.Equal(t, http.StatusOK, status, body)
+	assert.JSONEq(t, "[]", body)
+}
+
+func TestREST_Errors(t *testing.T) {
+	srv := setupServer(t)
+	author := map[string]string{rest.HeaderAuthor: "tester"}
+
+	tests := []struct {
+		name   string
+		method string
+		path   string
+		body   string
+		header map[string]string
+		want   int
+	}{
+		{"write without author", http.MethodPut, "/documents/docs/x", "content", nil, http.StatusBadRequest},
+		{"body too large", http.MethodPut, "/documents/docs/x", strings.Repeat("x", 65), author, http.StatusRequestEntityTooLarge},
+		{"invalid path", http.MethodPut, "/documents/docs/a%00b", "content", author, http.StatusBadRequest},
+		{"read missing", http.MethodGet, "/documents/docs/missing", "", nil, http.StatusNotFound},
+		{"history missing", http.MethodGet, "/history/docs/missing", "", nil, http.StatusNotFound},
+		{"bad version", http.MethodGet, "/documents/docs/x?version=abc", "", nil, http.StatusBadRequest},
+		{"bad bool", http.MethodGet, "/documents?deleted=maybe", "", nil, http.StatusBadRequest},
+		{"search without query", http.MethodGet, "/search", "", nil, http.StatusBadRequest},
+		{"bad link direction", http.MethodGet, "/links/docs/x?direction=sideways", "", nil, http.StatusBadRequest},
+		{"wrong method", http.MethodPost, "/documents/docs/x", "content", author, http.StatusMethodNotAllowed},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			status, body := do(t, tt.method, srv.URL+tt.path, tt.body, tt.header)
+			assert.Equal(t, tt.want, status, body)
+			if status != http.StatusMethodNotAllowed {
+				var e struct {
+					Error string `json:"error"`
+				}
+				require.NoError(t, json.Unmarshal([]byte(body), &e), body)
+				assert.NotEmpty(t, e.Error)
+			}
+		})
+	}
+}
@@ -85,7 +85,7 @@ func Run(ctx context.Context, w io.Writer, svc service.Service, target string, v
 	message := opts.Message
 	if message == "" {
 		if usedKey {
-			message = fmt.Sprintf("Revert to %s", target)
+			message = fmt.Sprintf("Revert to %s", doc.Key)
 		} else {
 			message = fmt.Sprintf("Revert to v%d", doc.Version)
 		}
@@ -125,3 +125,28 @@ func Run(ctx context.Context, w io.Writer, svc service.Service, target string, v
 	fmt.Fprintf(w, "Reverted %s to v%d (now v%d)\n", doc.Path, doc.Version, newDoc.Version)
 	return result, nil
 }
+
+// Undo reverts a document to the version immediately before its latest,
+// equivalent to Run with version latest-1. target may be a path or key;
+// a key selects the document, not the version to undo to.
+func Undo(ctx context.Context, w io.Writer, svc service.Service, target string, opts Options) (Result, error) {
+	doc, _, err := svc.Resolve(ctx, target, false)
+	if err != nil {
+		if errors.Is(err, store.ErrNotFound) {
+			return Result{}, fmt.Errorf("not found: %s", target)
+		}
+		return Result{}, err
+	}
+
+	// Newest first, so the second entry is the version to go back to
+	docs, err := svc.History(ctx, doc.Path, 2, false)
+	if err != nil {
+		return Result{}, err
+	}
+	if len(docs) < 2 {
+		return Result{}, fmt.Errorf("nothing to undo: %s has only one version", doc.Path)
+	}
+
+	opts.Key = ""
+	return Run(ctx, w, svc, doc.Path, docs[1].Version, opts)
+}
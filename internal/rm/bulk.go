@@ -0,0 +1,98 @@
+package rm
+
+import (
+	"context"
+	"fmt"
+	"io"
+	"slices"
+
+	"github.com/jpl-au/llmd/internal/service"
+	"github.com/jpl-au/llmd/internal/store"
+)
+
+// ConfirmThreshold is the largest number of documents a glob or tag delete
+// removes without --yes. Larger selections need --yes, or --dry-run to
+// review them first, so a loose pattern cannot silently empty the store.
+const ConfirmThreshold = 10
+
+// BulkOptions selects the documents of a glob or tag delete. Given both,
+// only documents matching the glob and having the tag are selected.
+type BulkOptions struct {
+	Glob   string // Path glob (e.g. "docs/tmp/**")
+	Tag    string // Tag the documents must have
+	DryRun bool   // Report the selection without deleting
+	Yes    bool   // Confirm deleting more than ConfirmThreshold documents
+}
+
+// BulkResult contains the outcome of a glob or tag delete.
+type BulkResult struct {
+	Glob   string   `json:"glob,omitempty"`
+	Tag    string   `json:"tag,omitempty"`
+	DryRun bool     `json:"dry_run,omitempty"`
+	Count  int      `json:"count"`
+	Paths  []string `json:"paths"`
+}
+
+// RunBulk soft-deletes every document selected by opts in one transaction,
+// so a failure part way leaves all of them in place.
+func RunBulk(ctx context.Context, w io.Writer, svc service.Service, opts BulkOptions) (BulkResult, error) {
+	result := BulkResult{Glob: opts.Glob, Tag: opts.Tag, DryRun: opts.DryRun, Paths: []string{}}
+	if opts.Glob == "" && opts.Tag == "" {
+		return result, fmt.Errorf("requires --glob or --tag")
+	}
+
+	paths, err := Select(ctx, svc, opts.Glob, opts.Tag)
+	if err != nil {
+		return result, err
+	}
+	result.Count = len(paths)
+	result.Paths = paths
+
+	if len(paths) == 0 {
+		fmt.Fprintln(w, "No matching documents")
+		return result, nil
+	}
+
+	if opts.DryRun {
+		for _, p := range paths {
+			fmt.Fprintf(w, "Would delete %s\n", p)
+		}
+		fmt.Fprintf(w, "%d document(s) would be deleted\n", len(paths))
+		return result, nil
+	}
+
+	if len(paths) > ConfirmThreshold && !opts.Yes {
+		return result, fmt.Errorf("%d documents match (more than %d): re-run with --dry-run to list them or --yes to delete them", len(paths), ConfirmThreshold)
+	}
+
+	if err := svc.DeleteMany(ctx, paths); err != nil {
+		return result, err
+	}
+	for _, p := range paths {
+		fmt.Fprintf(w, "Deleted %s\n", p)
+	}
+	fmt.Fprintf(w, "%d document(s) deleted\n", len(paths))
+	return result, nil
+}
+
+// Select returns the sorted paths of the active documents matching glob and
+// having tag. An empty glob or tag does not constrain the selection.
+func Select(ctx context.Context, svc service.Service, glob, tag string) ([]string, error) {
+	// Glob with an empty pattern lists every active document, which also
+	// drops tags left behind by deleted documents.
+	paths, err := svc.Glob(ctx, glob)
+	if err != nil {
+		return nil, fmt.Errorf("glob %q: %w", glob, err)
+	}
+	if tag != "" {
+		tagged, err := svc.PathsWithTag(ctx, tag, store.NewTagOptions())
+		if err != nil {
+			return nil, fmt.Errorf("tag %q: %w", tag, err)
+		}
+		paths = slices.DeleteFunc(paths, func(p string) bool {
+			return !slices.Contains(tagged, p)
+		})
+	}
+	slices.Sort(paths)
+	return paths, nil
+}
@@ -3,7 +3,6 @@ package sed_test
 import (
 	"bytes"
 	"context"
-	"os"
 	"testing"
 
 	"github.com/jpl-au/llmd/internal/document"
@@ -13,30 +12,14 @@ import (
 	"github.com/stretchr/testify/require"
 )
 
-// setupService creates a temporary service and returns it along with a cleanup function.
+// setupService creates an in-memory service and returns it along with a cleanup function.
 func setupService(t *testing.T) (service.Service, func()) {
 	t.Helper()
 
-	tmpDir, err := os.MkdirTemp("", "llmd-sed-test-*")
-	require.NoError(t, err, "creating temp dir")
-
-	cwd, err := os.Getwd()
-	require.NoError(t, err, "getting cwd")
-
-	require.NoError(t, os.Chdir(tmpDir), "chdir to temp")
-
-	require.NoError(t, document.Init(true, "", false, ""), "init document service")
-
-	svc, err := document.New("")
+	svc, err := document.NewMemory()
 	require.NoError(t, err, "creating service")
 
-	cleanup := func() {
-		svc.Close()
-		_ = os.Chdir(cwd)
-		os.RemoveAll(tmpDir)
-	}
-
-	return svc, cleanup
+	return svc, func() { svc.Close() }
 }
 
 func TestRun_ResolvesKeyToPath(t *testing.T) {
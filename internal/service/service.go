@@ -38,6 +38,11 @@ type Service interface {
 	// Returns store.ErrNotFound if the version doesn't exist.
 	Version(ctx context.Context, path string, version int) (*store.Document, error)
 
+	// VersionAsOf returns the version of a document that was current at t.
+	// Returns store.ErrNotFound if the document did not exist, or was
+	// deleted, at that time.
+	VersionAsOf(ctx context.Context, path string, t time.Time) (*store.Document, error)
+
 	// ByKey retrieves a document by its unique 8-char key.
 	// Returns store.ErrNotFound if no document exists with that key.
 	ByKey(ctx context.Context, key string) (*store.Document, error)
@@ -68,6 +73,15 @@ type Service interface {
 	// Use "" for all documents. Set deletedOnly to list only deleted docs.
 	List(ctx context.Context, prefix string, includeDeleted, deletedOnly bool) ([]store.Document, error)
 
+	// ListContaining is List restricted to documents whose content contains
+	// text, so a content scan such as grep loads only possible matches.
+	// ignoreCase folds ASCII letters only; other characters match exactly.
+	ListContaining(ctx context.Context, prefix, text string, ignoreCase, includeDeleted, deletedOnly bool) ([]store.Document, error)
+
+	// ListAsOf returns documents matching a path prefix as they were at t,
+	// omitting those created after t or already deleted by then.
+	ListAsOf(ctx context.Context, prefix string, t time.Time) ([]store.Document, error)
+
 	// ListByTag returns documents matching a path prefix and having the specified tag.
 	// More efficient than List followed by filtering when tag filtering is needed.
 	ListByTag(ctx context.Context, prefix, tag string, includeDeleted, deletedOnly bool, opts store.TagOptions) ([]store.Document, error)
@@ -77,10 +91,19 @@ type Service interface {
 	// If sync_files is enabled, also writes to .llmd/files/<path>.
 	Write(ctx context.Context, path, content, author, message string) error
 
+	// WriteType is Write with an explicit content type (e.g. "application/json").
+	// Write keeps the previous version's type; new documents default to
+	// text/markdown.
+	WriteType(ctx context.Context, path, content, contentType, author, message string) error
+
 	// Delete soft-deletes a document (can be restored).
 	// Returns store.ErrNotFound if the document doesn't exist.
 	Delete(ctx context.Context, path string) error
 
+	// DeleteMany soft-deletes several documents in one transaction: if any
+	// is missing (store.ErrNotFound) none are deleted.
+	DeleteMany(ctx context.Context, paths []string) error
+
 	// DeleteVersion soft-deletes a specific version of a document.
 	// Other versions remain accessible. Returns store.ErrNotFound if the version doesn't exist.
 	DeleteVersion(ctx context.Context, path string, version int) error
@@ -93,11 +116,24 @@ type Service interface {
 	// Returns store.ErrAlreadyExists if destination exists.
 	Move(ctx context.Context, from, to string) error
 
+	// MoveMany applies several renames in one transaction, in order: if any
+	// fails (missing source, existing destination) none are applied.
+	MoveMany(ctx context.Context, ops []store.MoveOp) error
+
 	// Search performs full-text search across document content using FTS5.
 	// Query supports standard FTS5 syntax: "word1 word2" (AND), "word1 OR word2",
 	// "word*" (prefix), "\"exact phrase\"". Use prefix to limit to a path prefix.
 	Search(ctx context.Context, query, prefix string, includeDeleted, deletedOnly bool) ([]store.Document, error)
 
+	// SearchRanked is Search over active documents, ordered by relevance
+	// with the most relevant first.
+	SearchRanked(ctx context.Context, query, prefix string) ([]store.Document, error)
+
+	// SearchPage is Search ordered by relevance, returning at most limit
+	// documents (0 = all) after skipping offset, plus the total number of
+	// matches so callers can tell whether more pages remain.
+	SearchPage(ctx context.Context, query, prefix string, includeDeleted, deletedOnly bool, limit, offset int) ([]store.Document, int, error)
+
 	// History returns version history for a document, newest first.
 	// Set limit to 0 for all versions.
 	History(ctx context.Context, path string, limit int, includeDeleted bool) ([]store.Document, error)
@@ -110,9 +146,11 @@ type Service interface {
 	// Creates a new version with the replacement applied.
 	Edit(ctx context.Context, path string, opts edit.Options) error
 
-	// EditLineRange replaces a range of lines in a document.
-	// Line numbers are 1-indexed. Creates a new version.
-	EditLineRange(ctx context.Context, path, replacement string, opts edit.LineRangeOptions) error
+	// EditLineRange replaces a range of lines in a document, or inserts
+	// content (append, prepend, before a line) per opts.Mode.
+	// Line numbers are 1-indexed. Creates a new version unless opts.DryRun
+	// is set, in which case the returned result carries a preview instead.
+	EditLineRange(ctx context.Context, path, replacement string, opts edit.LineRangeOptions) (edit.Result, error)
 
 	// Diff compares document versions or against filesystem.
 	// See diff.Options for comparison modes.
@@ -138,10 +176,26 @@ type Service interface {
 	// PathsWithTag returns all document paths having a specific tag.
 	PathsWithTag(ctx context.Context, tag string, opts store.TagOptions) ([]string, error)
 
+	// TagCounts returns each tag with the number of documents having it.
+	TagCounts(ctx context.Context, opts store.TagOptions) (map[string]int, error)
+
+	// TagSets returns the complete tag list of every document having a tag,
+	// keyed by path.
+	TagSets(ctx context.Context, tag string, opts store.TagOptions) (map[string][]string, error)
+
+	// RenameTag renames a tag on every document that has it, in a single
+	// transaction. Returns the number of documents affected.
+	RenameTag(ctx context.Context, oldTag, newTag string, opts store.TagOptions) (int64, error)
+
 	// FilesDir returns the path to the .llmd directory.
 	// Used for filesystem sync operations.
 	FilesDir() string
 
+	// Mirrored reports whether every write is mirrored to FilesDir
+	// (sync.files). Without it the directory holds only the files a user
+	// put there, so a missing file says nothing about the document.
+	Mirrored() bool
+
 	// Exists checks if a document exists without fetching content.
 	// More efficient than Latest() when you only need to check existence.
 	Exists(ctx context.Context, path string) (bool, error)
@@ -152,6 +206,13 @@ type Service interface {
 	// Returns store.ErrAlreadyExists if destination exists.
 	Copy(ctx context.Context, from, to, copier string) error
 
+	// CopyPrefix copies every document under fromPrefix to the same
+	// relative path under toPrefix, each starting at version 1, in one
+	// transaction. Returns the number copied, store.ErrAlreadyExists if any
+	// destination exists (nothing is copied), or store.ErrNotFound if
+	// fromPrefix holds no documents.
+	CopyPrefix(ctx context.Context, fromPrefix, toPrefix, copier string) (int, error)
+
 	// Count returns the number of documents matching a path prefix.
 	// Use "" to count all documents.
 	Count(ctx context.Context, prefix string) (int64, error)
@@ -168,6 +229,7 @@ type Service interface {
 	// Tx runs a function within a database transaction.
 	// If fn returns nil, the transaction is committed.
 	// If fn returns an error, the transaction is rolled back.
+	// fn may be retried when another process holds the database lock.
 	//
 	// Example:
 	//
@@ -199,6 +261,9 @@ type Service interface {
 	// ListLinksByTag returns all links with a specific tag.
 	ListLinksByTag(ctx context.Context, tag string, opts store.LinkOptions) ([]store.Link, error)
 
+	// ListAllLinks returns every link in the store.
+	ListAllLinks(ctx context.Context, opts store.LinkOptions) ([]store.Link, error)
+
 	// ListOrphanLinkPaths returns document paths with no links.
 	ListOrphanLinkPaths(ctx context.Context, opts store.LinkOptions) ([]string, error)
 
@@ -215,6 +280,16 @@ type Service interface {
 	// info without loading full document content.
 	ListMeta(ctx context.Context, prefix string, includeDeleted bool) ([]store.DocumentMeta, error)
 
+	// Recent returns metadata for the active documents changed after since
+	// (zero = any time), most recently changed first, at most limit of them
+	// (0 = all).
+	Recent(ctx context.Context, prefix string, since time.Time, limit int) ([]store.DocumentMeta, error)
+
+	// AuditLog returns every version written in [since, until) under a
+	// prefix, oldest first, with author, message and size. A zero time
+	// leaves that end of the range open.
+	AuditLog(ctx context.Context, since, until time.Time, prefix string) ([]store.AuditEntry, error)
+
 	// CountDeleted returns the count of soft-deleted documents, enabling
 	// vacuum preview and trash management without loading document data.
 	CountDeleted(ctx context.Context, prefix string) (int64, error)
@@ -235,6 +310,10 @@ type Service interface {
 	// and operational visibility.
 	Stats(ctx context.Context) (*store.Stats, error)
 
+	// Duplicates groups active documents under a prefix whose latest
+	// versions have identical content. Use "" for all documents.
+	Duplicates(ctx context.Context, prefix string) ([]store.DuplicateGroup, error)
+
 	// DeleteLinksForPath soft-deletes all links involving a document,
 	// enabling cleanup when documents are removed or reorganised.
 	DeleteLinksForPath(ctx context.Context, path string, opts store.LinkOptions) error
@@ -242,4 +321,27 @@ type Service interface {
 	// Checkpoint flushes the WAL to the main database file, removing
 	// the -wal and -shm files. Useful before backup or distribution.
 	Checkpoint(ctx context.Context) error
+
+	// Verify checks database integrity, link consistency and content
+	// hashes without modifying anything, returning the problems found.
+	Verify(ctx context.Context) ([]store.Issue, error)
+
+	// Reindex rebuilds the full-text search index, returning the number of
+	// document versions indexed. Use it when find returns stale results.
+	Reindex(ctx context.Context) (int64, error)
+
+	// Encrypted reports whether document content is encrypted at rest. An
+	// encrypted store has no full-text index: Search fails and grep scans
+	// every document.
+	Encrypted() bool
+
+	// SchemaVersion returns the highest schema migration applied.
+	SchemaVersion(ctx context.Context) (int, error)
+
+	// PendingMigrations returns the schema migrations not yet applied.
+	PendingMigrations(ctx context.Context) ([]store.Migration, error)
+
+	// MigrateTo applies pending schema migrations up to version, returning
+	// those applied. Migrations cannot be undone.
+	MigrateTo(ctx context.Context, version int) ([]store.Migration, error)
 }
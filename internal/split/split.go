@@ -0,0 +1,168 @@
+// Package split breaks a markdown document into one document per section.
+//
+// Large documents are awkward for LLMs: they blow the context budget when
+// read whole and can exceed limits.max_content when written back. Splitting
+// at heading boundaries keeps each piece coherent, and a "part-of" link from
+// every section back to the original records where the pieces came from.
+// "next" links chain the sections in document order, so they stay connected
+// even once the original is deleted.
+package split
+
+import (
+	"context"
+	"fmt"
+	"io"
+	"strings"
+
+	"github.com/jpl-au/llmd/internal/edit"
+	"github.com/jpl-au/llmd/internal/service"
+	"github.com/jpl-au/llmd/internal/store"
+)
+
+// LinkTag is the tag on links from each section to the document it came from.
+const LinkTag = "part-of"
+
+// NextTag is the tag on links from each section to the one after it.
+const NextTag = "next"
+
+// DefaultLevel splits at "##" headings, the usual top level below a title.
+const DefaultLevel = 2
+
+// Options configures a split operation.
+type Options struct {
+	Level   int    // Split at headings of this level or higher (default DefaultLevel)
+	Prefix  string // Where sections are written (default: the document's path)
+	Delete  bool   // Soft-delete the original once the sections exist
+	DryRun  bool   // Report the sections without writing anything
+	Author  string // Who is performing the split
+	Message string // Version message for the new sections
+}
+
+// Section is one piece of a split document.
+type Section struct {
+	Path    string `json:"path"`
+	Heading string `json:"heading,omitempty"` // Empty for text before the first heading
+	Bytes   int    `json:"bytes"`
+	content string
+}
+
+// Result contains the outcome of a split operation.
+type Result struct {
+	Path     string    `json:"path"`
+	Sections []Section `json:"sections"`
+	Deleted  bool      `json:"deleted,omitempty"`
+	DryRun   bool      `json:"dry_run,omitempty"`
+}
+
+// Run splits the document at path into sections written under opts.Prefix
+// as section-1, section-2, and so on. Nothing is written if any target path
+// already exists, so a failed split does not leave half its sections behind.
+func Run(ctx context.Context, w io.Writer, svc service.Service, path string, opts Options) (Result, error) {
+	result := Result{Path: path, DryRun: opts.DryRun}
+	if opts.Level == 0 {
+		opts.Level = DefaultLevel
+	}
+	if opts.Level < 1 || opts.Level > 6 {
+		return result, fmt.Errorf("heading level must be between 1 and 6, got %d", opts.Level)
+	}
+
+	doc, _, err := svc.Resolve(ctx, path, false)
+	if err != nil {
+		return result, err
+	}
+	result.Path = doc.Path
+
+	prefix := strings.TrimSuffix(opts.Prefix, "/")
+	if prefix == "" {
+		prefix = doc.Path
+	}
+
+	parts := Sections(doc.Content, opts.Level)
+	if len(parts) < 2 {
+		return result, fmt.Errorf("%s has no level %d headings to split at", doc.Path, opts.Level)
+	}
+	for i := range parts {
+		parts[i].Path = fmt.Sprintf("%s/section-%d", prefix, i+1)
+		exists, err := svc.Exists(ctx, parts[i].Path)
+		if err != nil {
+			return result, err
+		}
+		if exists {
+			return result, fmt.Errorf("%s: %w", parts[i].Path, store.ErrAlreadyExists)
+		}
+	}
+	result.Sections = parts
+
+	if opts.DryRun {
+		for _, p := range parts {
+			fmt.Fprintf(w, "Would write %s (%d bytes)\n", p.Path, p.Bytes)
+		}
+		return result, nil
+	}
+
+	msg := opts.Message
+	if msg == "" {
+		msg = "Split from " + doc.Path
+	}
+	for _, p := range parts {
+		if err := svc.Write(ctx, p.Path, p.content, opts.Author, msg); err != nil {
+			return result, fmt.Errorf("write %s: %w", p.Path, err)
+		}
+		if _, err := svc.Link(ctx, p.Path, doc.Path, LinkTag, store.NewLinkOptions()); err != nil {
+			return result, fmt.Errorf("link %s: %w", p.Path, err)
+		}
+		fmt.Fprintln(w, p.Path)
+	}
+	for i := 1; i < len(parts); i++ {
+		if _, err := svc.Link(ctx, parts[i-1].Path, parts[i].Path, NextTag, store.NewLinkOptions()); err != nil {
+			return result, fmt.Errorf("link %s: %w", parts[i-1].Path, err)
+		}
+	}
+
+	if opts.Delete {
+		if err := svc.Delete(ctx, doc.Path); err != nil {
+			return result, fmt.Errorf("delete %s: %w", doc.Path, err)
+		}
+		result.Deleted = true
+	}
+	return result, nil
+}
+
+// Sections divides content at ATX headings ("# ", "## ", ...) of the given
+// level or higher. Each section starts with its heading line; text before the
+// first such heading, if not blank, becomes a section of its own. Headings
+// inside fenced code blocks are ignored.
+func Sections(content string, level int) []Section {
+	var sections []Section
+	var cur strings.Builder
+	heading := ""
+	fence := ""
+
+	flush := func() {
+		s := cur.String()
+		if strings.TrimSpace(s) != "" {
+			sections = append(sections, Section{Heading: heading, Bytes: len(s), content: s})
+		}
+		cur.Reset()
+	}
+
+	for _, line := range strings.SplitAfter(content, "\n") {
+		trimmed := strings.TrimRight(line, "\r\n")
+		if f := edit.FenceMarker(trimmed); f != "" {
+			switch {
+			case fence == "":
+				fence = f
+			case strings.HasPrefix(f, fence):
+				fence = ""
+			}
+		} else if fence == "" {
+			if n, text := edit.HeadingLevel(trimmed); n > 0 && n <= level {
+				flush()
+				heading = text
+			}
+		}
+		cur.WriteString(line)
+	}
+	flush()
+	return sections
+}
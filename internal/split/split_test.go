@@ -0,0 +1,73 @@
+package split_test
+
+import (
+	"bytes"
+	"context"
+	"testing"
+
+	"github.com/jpl-au/llmd/internal/document"
+	"github.com/jpl-au/llmd/internal/split"
+	"github.com/jpl-au/llmd/internal/store"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestSections(t *testing.T) {
+	content := "# Title\nintro\n\n## One\nfirst\n```\n## not a heading\n```\n## Two ##\nsecond\n### Sub\nnested\n"
+
+	t.Run("level 2", func(t *testing.T) {
+		got := split.Sections(content, 2)
+		require.Len(t, got, 3)
+		assert.Equal(t, "Title", got[0].Heading)
+		assert.Equal(t, "One", got[1].Heading)
+		assert.Equal(t, "Two", got[2].Heading, "closing hashes are trimmed")
+		assert.Equal(t, len("## Two ##\nsecond\n### Sub\nnested\n"), got[2].Bytes, "deeper headings stay in their section")
+	})
+
+	t.Run("level 1", func(t *testing.T) {
+		assert.Len(t, split.Sections(content, 1), 1)
+	})
+
+	t.Run("preamble", func(t *testing.T) {
+		got := split.Sections("preface\n## A\na\n", 2)
+		require.Len(t, got, 2)
+		assert.Equal(t, "", got[0].Heading)
+		assert.Equal(t, "A", got[1].Heading)
+	})
+
+	t.Run("not a heading", func(t *testing.T) {
+		assert.Len(t, split.Sections("##nospace\n    ## indented code\n", 2), 1)
+	})
+}
+
+func TestRun(t *testing.T) {
+	ctx := context.Background()
+	svc, err := document.NewMemory()
+	require.NoError(t, err)
+	defer svc.Close()
+
+	require.NoError(t, svc.Write(ctx, "docs/big", "## A\nalpha\n## B\nbeta\n", "tester", ""))
+
+	var buf bytes.Buffer
+	result, err := split.Run(ctx, &buf, svc, "docs/big", split.Options{Author: "tester"})
+	require.NoError(t, err)
+	require.Len(t, result.Sections, 2)
+	assert.False(t, result.Deleted)
+	assert.Equal(t, "docs/big/section-1\ndocs/big/section-2\n", buf.String())
+
+	doc, err := svc.Latest(ctx, "docs/big/section-2", false)
+	require.NoError(t, err)
+	assert.Equal(t, "## B\nbeta\n", doc.Content)
+
+	links, err := svc.ListLinks(ctx, "docs/big/section-1", split.LinkTag, store.NewLinkOptions())
+	require.NoError(t, err)
+	require.Len(t, links, 1)
+	assert.Equal(t, "docs/big", links[0].ToPath)
+
+	// Splitting again would overwrite the sections, so it is refused
+	_, err = split.Run(ctx, &buf, svc, "docs/big", split.Options{Author: "tester", Delete: true})
+	assert.ErrorIs(t, err, store.ErrAlreadyExists)
+	exists, err := svc.Exists(ctx, "docs/big")
+	require.NoError(t, err)
+	assert.True(t, exists, "a refused split leaves the original alone")
+}
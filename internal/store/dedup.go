@@ -0,0 +1,122 @@
+// dedup.go implements detection of documents with identical content.
+//
+// Separated from read.go because duplicate detection depends on a derived
+// column, content_hash, which write.go fills in and migration 4 backfills.
+//
+// Design: Each version records the SHA-256 of its content, so exact
+// duplicates among the latest versions are a GROUP BY over an indexed
+// column rather than a comparison of every pair. Encrypted stores record no
+// hash, since equal hashes would reveal which documents share content to
+// anyone reading the file; Duplicates decrypts and hashes their content
+// instead.
+
+package store
+
+import (
+	"context"
+	"crypto/sha256"
+	"encoding/hex"
+	"fmt"
+	"slices"
+	"strings"
+)
+
+// DuplicateGroup is a set of documents whose latest versions have
+// identical content.
+type DuplicateGroup struct {
+	Hash  string   `json:"hash"`  // SHA-256 of the shared content (hex)
+	Paths []string `json:"paths"` // Sorted
+}
+
+// contentHash returns the hex SHA-256 of content.
+func contentHash(content string) string {
+	sum := sha256.Sum256([]byte(content))
+	return hex.EncodeToString(sum[:])
+}
+
+// hashContent returns the content_hash recorded for content: its hash, or
+// "" in an encrypted store.
+func (s *SQLiteStore) hashContent(content string) string {
+	if s.aead != nil {
+		return ""
+	}
+	return contentHash(content)
+}
+
+// Duplicates returns the groups of active documents under prefix whose
+// latest versions have identical content, ordered by their first path.
+func (s *SQLiteStore) Duplicates(ctx context.Context, prefix string) ([]DuplicateGroup, error) {
+	if s.aead != nil {
+		return s.duplicatesSealed(ctx, prefix)
+	}
+
+	rows, err := s.db.QueryContext(ctx, `
+		WITH latest AS (
+			SELECT d.path, d.content_hash
+			FROM documents d
+			INNER JOIN (
+				SELECT path, MAX(version) AS max_version FROM documents
+				WHERE deleted_at IS NULL AND path LIKE ?
+				GROUP BY path
+			) m ON d.path = m.path AND d.version = m.max_version
+			WHERE d.deleted_at IS NULL AND d.content_hash != ''
+		)
+		SELECT content_hash, path FROM latest
+		WHERE content_hash IN (
+			SELECT content_hash FROM latest GROUP BY content_hash HAVING COUNT(*) > 1
+		)
+		ORDER BY content_hash, path`, prefix+"%")
+	if err != nil {
+		return nil, fmt.Errorf("find duplicates: %w", err)
+	}
+	defer rows.Close()
+
+	var groups []DuplicateGroup
+	for rows.Next() {
+		var hash, path string
+		if err := rows.Scan(&hash, &path); err != nil {
+			return nil, fmt.Errorf("scan duplicate: %w", err)
+		}
+		if n := len(groups); n > 0 && groups[n-1].Hash == hash {
+			groups[n-1].Paths = append(groups[n-1].Paths, path)
+			continue
+		}
+		groups = append(groups, DuplicateGroup{Hash: hash, Paths: []string{path}})
+	}
+	if err := rows.Err(); err != nil {
+		return nil, fmt.Errorf("find duplicates: %w", err)
+	}
+	sortGroups(groups)
+	return groups, nil
+}
+
+// duplicatesSealed is Duplicates for an encrypted store, hashing the
+// decrypted content of each document.
+func (s *SQLiteStore) duplicatesSealed(ctx context.Context, prefix string) ([]DuplicateGroup, error) {
+	docs, err := s.List(ctx, prefix, false, false)
+	if err != nil {
+		return nil, err
+	}
+	byHash := make(map[string][]string)
+	for _, d := range docs {
+		h := contentHash(d.Content)
+		byHash[h] = append(byHash[h], d.Path)
+	}
+
+	var groups []DuplicateGroup
+	for h, paths := range byHash {
+		if len(paths) > 1 {
+			groups = append(groups, DuplicateGroup{Hash: h, Paths: paths})
+		}
+	}
+	sortGroups(groups)
+	return groups, nil
+}
+
+// sortGroups orders groups by their first path. Paths within each group
+// are already sorted, as both queries return them in path order.
+func sortGroups(groups []DuplicateGroup) {
+	slices.SortFunc(groups, func(a, b DuplicateGroup) int {
+		return strings.Compare(a.Paths[0], b.Paths[0])
+	})
+}
@@ -0,0 +1,248 @@
+// encrypt.go implements optional encryption of document content at rest.
+//
+// Separated from read.go and write.go because key management is a concern
+// of the store as a whole: the key is derived once when the store opens and
+// every read and write of content passes through seal and unseal.
+//
+// Design: Only the content column is encrypted. Paths, authors, messages,
+// tags and links stay readable so listings, history and link checks work
+// unchanged, which also means they are not confidential. Content is sealed
+// with AES-256-GCM under a key derived from a passphrase with Argon2id; the
+// salt, KDF parameters and a sealed check value are kept in the encryption
+// table, never the key. Each value gets a fresh random nonce and is stored as
+// base64 text behind sealedPrefix, so the column stays TEXT.
+//
+// Consequences of encrypting:
+//   - FTS5 cannot index ciphertext, so EnableEncryption drops documents_fts
+//     and Search and Reindex return ErrNoSearchIndex. grep still works: it
+//     decrypts and scans every document.
+//   - SQL cannot filter on content, so ListContaining returns every
+//     candidate and leaves the filtering to its caller.
+//   - Sizes are derived from the ciphertext length and count bytes, not
+//     characters.
+//
+// Encryption can only be enabled on an empty store; there is no conversion
+// of existing content and no passphrase change. A lost passphrase means the
+// content is lost.
+
+package store
+
+import (
+	"context"
+	"crypto/aes"
+	"crypto/cipher"
+	"crypto/rand"
+	"database/sql"
+	"encoding/base64"
+	"errors"
+	"fmt"
+	"strings"
+	"time"
+
+	"golang.org/x/crypto/argon2"
+)
+
+// encryptionSchema holds the key derivation parameters of an encrypted
+// store. A store is encrypted exactly when the table has its row.
+const encryptionSchema = `CREATE TABLE IF NOT EXISTS encryption (
+    id INTEGER PRIMARY KEY CHECK (id = 1), -- Single row
+    kdf TEXT NOT NULL,                     -- Key derivation function (argon2id)
+    salt BLOB NOT NULL,                    -- Random salt for the KDF
+    time INTEGER NOT NULL,                 -- Argon2id passes
+    memory INTEGER NOT NULL,               -- Argon2id memory in KiB
+    threads INTEGER NOT NULL,              -- Argon2id parallelism
+    check_value TEXT NOT NULL,             -- checkText sealed with the key
+    created_at INTEGER NOT NULL            -- Unix timestamp when enabled
+)`
+
+// Key derivation and sealing parameters. The Argon2id values are those
+// RFC 9106 recommends for memory-constrained use: about 64MB and a fraction
+// of a second per open.
+const (
+	kdfName      = "argon2id"
+	kdfTime      = 1
+	kdfMemory    = 64 * 1024
+	kdfThreads   = 4
+	keyLen       = 32 // AES-256
+	saltLen      = 16
+	sealedPrefix = "llmd:enc:1:"
+	checkText    = "llmd"
+)
+
+// sealOverhead is the bytes GCM adds to each value: the nonce and the tag.
+const sealOverhead = 12 + 16
+
+// kdfParams are the stored inputs, besides the passphrase, to the key.
+type kdfParams struct {
+	salt    []byte
+	time    uint32
+	memory  uint32
+	threads uint8
+}
+
+// aead derives the key from passphrase and returns its cipher.
+func (p kdfParams) aead(passphrase string) (cipher.AEAD, error) {
+	key := argon2.IDKey([]byte(passphrase), p.salt, p.time, p.memory, p.threads, keyLen)
+	block, err := aes.NewCipher(key)
+	if err != nil {
+		return nil, err
+	}
+	return cipher.NewGCM(block)
+}
+
+// Encrypted reports whether the store encrypts document content.
+func (s *SQLiteStore) Encrypted() bool {
+	return s.aead != nil
+}
+
+// EnableEncryption makes the store encrypt the content of every document
+// written from now on, with a key derived from passphrase, and drops the
+// full-text index, which would otherwise hold the content in plaintext. The
+// store must not contain any documents yet.
+func (s *SQLiteStore) EnableEncryption(ctx context.Context, passphrase string) error {
+	if passphrase == "" {
+		return errors.New("passphrase cannot be empty")
+	}
+	if s.aead != nil {
+		return errors.New("store is already encrypted")
+	}
+
+	p := kdfParams{salt: make([]byte, saltLen), time: kdfTime, memory: kdfMemory, threads: kdfThreads}
+	if _, err := rand.Read(p.salt); err != nil {
+		return fmt.Errorf("generate salt: %w", err)
+	}
+	aead, err := p.aead(passphrase)
+	if err != nil {
+		return fmt.Errorf("derive key: %w", err)
+	}
+	check, err := seal(aead, checkText)
+	if err != nil {
+		return err
+	}
+
+	err = s.Tx(ctx, func(tx *sql.Tx) error {
+		var n int
+		if err := tx.QueryRowContext(ctx, `SELECT COUNT(*) FROM documents`).Scan(&n); err != nil {
+			return fmt.Errorf("count documents: %w", err)
+		}
+		if n > 0 {
+			return errors.New("encryption can only be enabled on an empty store")
+		}
+		if _, err := tx.ExecContext(ctx, encryptionSchema); err != nil {
+			return fmt.Errorf("create encryption table: %w", err)
+		}
+		_, err := tx.ExecContext(ctx, `INSERT INTO encryption (id, kdf, salt, time, memory, threads, check_value, created_at)
+			VALUES (1, ?, ?, ?, ?, ?, ?, ?)`,
+			kdfName, p.salt, p.time, p.memory, p.threads, check, time.Now().Unix())
+		if err != nil {
+			return fmt.Errorf("record encryption: %w", err)
+		}
+		for _, q := range []string{
+			`DROP TRIGGER IF EXISTS documents_fts_insert`,
+			`DROP TRIGGER IF EXISTS documents_fts_delete`,
+			`DROP TRIGGER IF EXISTS documents_fts_update`,
+			`DROP TABLE IF EXISTS documents_fts`,
+		} {
+			if _, err := tx.ExecContext(ctx, q); err != nil {
+				return fmt.Errorf("drop search index: %w", err)
+			}
+		}
+		return nil
+	})
+	if err != nil {
+		return err
+	}
+	s.aead = aead
+	return nil
+}
+
+// unlock derives the content key of an encrypted store from passphrase,
+// returning ErrEncrypted if there is none and ErrPassphrase if it is wrong.
+// A plaintext store ignores the passphrase.
+func (s *SQLiteStore) unlock(ctx context.Context, passphrase string) error {
+	ok, err := tableExists(ctx, s.db, "encryption")
+	if err != nil || !ok {
+		return err
+	}
+	var p kdfParams
+	var kdf, check string
+	err = s.db.QueryRowContext(ctx, `SELECT kdf, salt, time, memory, threads, check_value FROM encryption WHERE id = 1`).
+		Scan(&kdf, &p.salt, &p.time, &p.memory, &p.threads, &check)
+	if errors.Is(err, sql.ErrNoRows) {
+		return nil
+	}
+	if err != nil {
+		return fmt.Errorf("read encryption: %w", err)
+	}
+	if kdf != kdfName {
+		return fmt.Errorf("unsupported key derivation %q", kdf)
+	}
+	if passphrase == "" {
+		return ErrEncrypted
+	}
+
+	aead, err := p.aead(passphrase)
+	if err != nil {
+		return fmt.Errorf("derive key: %w", err)
+	}
+	if got, err := unseal(aead, check); err != nil || got != checkText {
+		return ErrPassphrase
+	}
+	s.aead = aead
+	return nil
+}
+
+// sealContent encrypts content for storage. Without a key it is returned
+// unchanged.
+func (s *SQLiteStore) sealContent(content string) (string, error) {
+	if s.aead == nil {
+		return content, nil
+	}
+	return seal(s.aead, content)
+}
+
+// unsealContent reverses sealContent.
+func (s *SQLiteStore) unsealContent(stored string) (string, error) {
+	if s.aead == nil {
+		return stored, nil
+	}
+	return unseal(s.aead, stored)
+}
+
+// sizeExpr returns the SQL expression for the size of the content in
+// column col. Sealed content is the base64 of nonce, ciphertext and tag, so
+// the plaintext byte count follows from its length.
+func (s *SQLiteStore) sizeExpr(col string) string {
+	if s.aead == nil {
+		return "length(" + col + ")"
+	}
+	return fmt.Sprintf("max((length(%s) - %d) * 3 / 4 - %d, 0)", col, len(sealedPrefix), sealOverhead)
+}
+
+// seal encrypts plaintext under a fresh random nonce.
+func seal(aead cipher.AEAD, plaintext string) (string, error) {
+	nonce := make([]byte, aead.NonceSize(), aead.NonceSize()+len(plaintext)+aead.Overhead())
+	if _, err := rand.Read(nonce); err != nil {
+		return "", fmt.Errorf("generate nonce: %w", err)
+	}
+	sealed := aead.Seal(nonce, nonce, []byte(plaintext), nil)
+	return sealedPrefix + base64.RawStdEncoding.EncodeToString(sealed), nil
+}
+
+// unseal decrypts a value produced by seal.
+func unseal(aead cipher.AEAD, stored string) (string, error) {
+	enc, ok := strings.CutPrefix(stored, sealedPrefix)
+	if !ok {
+		return "", errors.New("decrypt content: not encrypted")
+	}
+	sealed, err := base64.RawStdEncoding.DecodeString(enc)
+	if err != nil || len(sealed) < aead.NonceSize() {
+		return "", errors.New("decrypt content: malformed")
+	}
+	n := aead.NonceSize()
+	plain, err := aead.Open(nil, sealed[:n], sealed[n:], nil)
+	if err != nil {
+		return "", fmt.Errorf("decrypt content: %w", err)
+	}
+	return string(plain), nil
+}
@@ -27,6 +27,10 @@ type Reader interface {
 	// Version retrieves a specific historical version for audit or rollback.
 	Version(ctx context.Context, path string, version int) (*Document, error)
 
+	// VersionAsOf retrieves the version that was current at time t, for
+	// point-in-time snapshots across documents whose version numbers differ.
+	VersionAsOf(ctx context.Context, path string, t time.Time) (*Document, error)
+
 	// ByKey retrieves a document by its unique 8-char key. Returns ErrNotFound
 	// if no document exists with that key.
 	ByKey(ctx context.Context, key string) (*Document, error)
@@ -35,6 +39,15 @@ type Reader interface {
 	// enables listing trash contents separately from active documents.
 	List(ctx context.Context, prefix string, includeDeleted bool, deletedOnly bool) ([]Document, error)
 
+	// ListContaining is List restricted to documents whose content contains
+	// text, letting content scans skip documents that cannot match.
+	// ignoreCase folds ASCII letters only.
+	ListContaining(ctx context.Context, prefix, text string, ignoreCase, includeDeleted, deletedOnly bool) ([]Document, error)
+
+	// ListAsOf returns documents matching a path prefix as they were at time
+	// t, for listing and searching a point-in-time snapshot of the store.
+	ListAsOf(ctx context.Context, prefix string, t time.Time) ([]Document, error)
+
 	// ListPaths returns only paths without content, enabling efficient
 	// glob matching without loading full documents into memory.
 	ListPaths(ctx context.Context, prefix string) ([]string, error)
@@ -48,6 +61,14 @@ type Reader interface {
 	// and admin tools that need size/version info without content.
 	ListMeta(ctx context.Context, prefix string, includeDeleted bool) ([]DocumentMeta, error)
 
+	// Recent returns metadata for active documents changed after since,
+	// most recently changed first.
+	Recent(ctx context.Context, prefix string, since time.Time, limit int) ([]DocumentMeta, error)
+
+	// AuditLog returns every version written in [since, until) under a
+	// prefix, oldest first, as a cross-document change ledger.
+	AuditLog(ctx context.Context, since, until time.Time, prefix string) ([]AuditEntry, error)
+
 	// History returns version history for auditing changes over time.
 	History(ctx context.Context, path string, limit int, includeDeleted bool) ([]Document, error)
 
@@ -82,6 +103,10 @@ type Reader interface {
 	// Stats returns aggregate database statistics for capacity planning
 	// and operational dashboards.
 	Stats(ctx context.Context) (*Stats, error)
+
+	// Duplicates groups active documents under a prefix whose latest
+	// versions have identical content, for consolidating redundant docs.
+	Duplicates(ctx context.Context, prefix string) ([]DuplicateGroup, error)
 }
 
 // Writer defines operations that modify documents.
@@ -93,22 +118,39 @@ type Writer interface {
 	// recovery via Restore until Vacuum permanently removes it.
 	Delete(ctx context.Context, path string, opts DeleteOptions) error
 
+	// DeleteMany soft-deletes several documents atomically: all or none.
+	DeleteMany(ctx context.Context, paths []string, opts DeleteOptions) error
+
 	// Restore recovers a soft-deleted document to active status.
 	Restore(ctx context.Context, path string, opts RestoreOptions) error
 
 	// Move renames a document, preserving all version history.
 	Move(ctx context.Context, src, dst string, opts MoveOptions) error
 
+	// MoveMany applies several renames atomically: all or none.
+	MoveMany(ctx context.Context, ops []MoveOp, opts MoveOptions) error
+
 	// Copy duplicates a document, creating version 1 at the destination
 	// while preserving the source document unchanged. The copier parameter
 	// tracks who performed the copy operation (distinct from the content author).
 	Copy(ctx context.Context, from, to, copier string, opts CopyOptions) error
+
+	// CopyPrefix copies every document under one prefix to another,
+	// atomically, returning the destination paths.
+	CopyPrefix(ctx context.Context, fromPrefix, toPrefix, copier string, opts CopyOptions) ([]string, error)
 }
 
 // Searcher defines search operations.
 type Searcher interface {
 	// Search performs full-text search across document paths and content.
 	Search(ctx context.Context, query, prefix string, includeDeleted bool, deletedOnly bool) ([]Document, error)
+
+	// SearchRanked searches active documents, most relevant first.
+	SearchRanked(ctx context.Context, query, prefix string) ([]Document, error)
+
+	// SearchPage returns one page of relevance-ordered search results and
+	// the total number of matches.
+	SearchPage(ctx context.Context, query, prefix string, includeDeleted, deletedOnly bool, limit, offset int) ([]Document, int, error)
 }
 
 // Tagger defines operations for managing tags on documents.
@@ -125,8 +167,18 @@ type Tagger interface {
 	// PathsWithTag finds documents with a specific tag for batch operations.
 	PathsWithTag(ctx context.Context, tag string, opts TagOptions) ([]string, error)
 
+	// TagCounts returns each tag with the number of documents that have it.
+	TagCounts(ctx context.Context, opts TagOptions) (map[string]int, error)
+
+	// TagSets returns the complete tags of every document having a tag.
+	TagSets(ctx context.Context, tag string, opts TagOptions) (map[string][]string, error)
+
 	// ListByTag returns documents matching a path prefix that have a specific tag.
 	ListByTag(ctx context.Context, prefix, tag string, includeDeleted, deletedOnly bool, opts TagOptions) ([]Document, error)
+
+	// RenameTag renames a tag across all documents in one transaction,
+	// merging into the new tag where a document already has it.
+	RenameTag(ctx context.Context, oldTag, newTag string, opts TagOptions) (int64, error)
 }
 
 // Linker defines operations for managing links between documents.
@@ -147,6 +199,9 @@ type Linker interface {
 	// ListLinksByTag returns all links with a tag for relationship analysis.
 	ListLinksByTag(ctx context.Context, tag string, opts LinkOptions) ([]Link, error)
 
+	// ListAllLinks returns every live link, for exporting the link graph.
+	ListAllLinks(ctx context.Context, opts LinkOptions) ([]Link, error)
+
 	// ListOrphanLinkPaths finds documents with no links, helping identify
 	// disconnected content that may need attention.
 	ListOrphanLinkPaths(ctx context.Context, opts LinkOptions) ([]string, error)
@@ -169,6 +224,28 @@ type Maintainer interface {
 
 	// Vacuum permanently removes soft-deleted data.
 	Vacuum(ctx context.Context, olderThan *time.Duration, path string) (int64, error)
+
+	// Verify checks database integrity, link consistency and content
+	// hashes, returning the problems found (nil when the store is
+	// consistent).
+	Verify(ctx context.Context) ([]Issue, error)
+
+	// Reindex rebuilds the full-text search index from the documents table,
+	// returning the number of document versions indexed.
+	Reindex(ctx context.Context) (int64, error)
+
+	// Encrypted reports whether document content is encrypted at rest.
+	Encrypted() bool
+
+	// SchemaVersion returns the highest schema migration applied.
+	SchemaVersion(ctx context.Context) (int, error)
+
+	// PendingMigrations returns the schema migrations not yet applied.
+	PendingMigrations(ctx context.Context) ([]Migration, error)
+
+	// MigrateTo applies pending schema migrations up to version, returning
+	// those applied. Migrations cannot be undone.
+	MigrateTo(ctx context.Context, version int) ([]Migration, error)
 }
 
 // Store defines the persistence interface for documents. All operations are
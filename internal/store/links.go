@@ -14,14 +14,21 @@ package store
 import (
 	"context"
 	"database/sql"
+	"errors"
 	"fmt"
+	"strings"
 	"time"
 
 	"github.com/jpl-au/llmd/internal/validate"
 )
 
-// Link creates a relationship between documents. Avoids generating unused IDs
-// by checking for existing links first.
+// linkColumns is the column list scanLinks expects.
+const linkColumns = `id, from_path, from_source, to_path, to_source, tag, note, weight, created_at`
+
+// Link creates a relationship between documents, or updates the note and
+// weight of an existing one where opts sets them; those left nil keep their
+// stored values. Avoids generating unused IDs by reusing the existing row,
+// live or soft-deleted, when there is one.
 func (s *SQLiteStore) Link(ctx context.Context, from, to, tag string, opts LinkOptions) (string, error) {
 	if _, err := validate.Path(from, opts.MaxPath); err != nil {
 		return "", err
@@ -33,51 +40,34 @@ func (s *SQLiteStore) Link(ctx context.Context, from, to, tag string, opts LinkO
 		return "", err
 	}
 
-	// First try to restore a soft-deleted link (avoids generating unused IDs)
-	result, err := s.db.ExecContext(ctx, `
-		UPDATE links SET deleted_at = NULL
-		WHERE from_path = ? AND from_source = ? AND to_path = ? AND to_source = ? AND tag = ? AND deleted_at IS NOT NULL
-	`, from, opts.FromSource, to, opts.ToSource, tag)
-	if err != nil {
-		return "", fmt.Errorf("restoring link: %w", err)
-	}
-	n, err := result.RowsAffected()
-	if err != nil {
-		return "", fmt.Errorf("checking rows affected: %w", err)
-	}
-	if n > 0 {
-		// Restored existing link, return its ID
-		var id string
-		err := s.db.QueryRowContext(ctx, `
-			SELECT id FROM links
-			WHERE from_path = ? AND from_source = ? AND to_path = ? AND to_source = ? AND tag = ? AND deleted_at IS NULL
-		`, from, opts.FromSource, to, opts.ToSource, tag).Scan(&id)
-		if err != nil {
-			return "", fmt.Errorf("fetching restored link ID: %w", err)
-		}
+	// Restore a soft-deleted link or refresh a live one. A nil note or
+	// weight binds NULL, which COALESCE turns into the stored value.
+	var id string
+	err := s.db.QueryRowContext(ctx, `
+		UPDATE links SET deleted_at = NULL, note = COALESCE(?, note), weight = COALESCE(?, weight)
+		WHERE from_path = ? AND from_source = ? AND to_path = ? AND to_source = ? AND tag = ?
+		RETURNING id
+	`, opts.Note, opts.Weight, from, opts.FromSource, to, opts.ToSource, tag).Scan(&id)
+	if err == nil {
 		return id, nil
 	}
-
-	// Check if active link already exists
-	var existingID string
-	err = s.db.QueryRowContext(ctx, `
-		SELECT id FROM links
-		WHERE from_path = ? AND from_source = ? AND to_path = ? AND to_source = ? AND tag = ? AND deleted_at IS NULL
-	`, from, opts.FromSource, to, opts.ToSource, tag).Scan(&existingID)
-	if err == nil {
-		return existingID, nil // Link already exists
+	if !errors.Is(err, sql.ErrNoRows) {
+		return "", fmt.Errorf("updating link: %w", err)
 	}
 
 	// Insert new link
-	now := time.Now().Unix()
-	id, err := genID()
-	if err != nil {
-		return "", err
+	note, weight := "", 0
+	if opts.Note != nil {
+		note = *opts.Note
 	}
-	_, err = s.db.ExecContext(ctx, `
-		INSERT INTO links (id, from_path, from_source, to_path, to_source, tag, created_at, deleted_at)
-		VALUES (?, ?, ?, ?, ?, ?, ?, NULL)
-	`, id, from, opts.FromSource, to, opts.ToSource, tag, now)
+	if opts.Weight != nil {
+		weight = *opts.Weight
+	}
+	now := time.Now().Unix()
+	id, err = insertWithID(ctx, s.db, "links.id", `
+		INSERT INTO links (id, from_path, from_source, to_path, to_source, tag, note, weight, created_at, deleted_at)
+		VALUES (?, ?, ?, ?, ?, ?, ?, ?, ?, NULL)
+	`, from, opts.FromSource, to, opts.ToSource, tag, note, weight, now)
 	if err != nil {
 		return "", fmt.Errorf("creating link: %w", err)
 	}
@@ -123,18 +113,33 @@ func (s *SQLiteStore) UnlinkByTag(ctx context.Context, tag string, opts LinkOpti
 	return n, nil
 }
 
-// ListLinks finds connections for a document in either direction,
-// enabling relationship discovery regardless of link direction.
+// ListLinks finds connections for a document. By default links are found in
+// either direction, enabling relationship discovery regardless of how they
+// were created; opts.Direction narrows this to outgoing or incoming links.
+// Heavier links come first, then the most recently created.
 func (s *SQLiteStore) ListLinks(ctx context.Context, path, tag string, opts LinkOptions) ([]Link, error) {
-	query := `SELECT id, from_path, from_source, to_path, to_source, tag, created_at FROM links
-		WHERE ((from_path = ? AND from_source = ?) OR (to_path = ? AND to_source = ?)) AND deleted_at IS NULL`
-	args := []any{path, opts.FromSource, path, opts.ToSource}
+	query := `SELECT ` + linkColumns + ` FROM links WHERE `
+	var args []any
+	switch opts.Direction {
+	case "", DirectionBoth:
+		query += `((from_path = ? AND from_source = ?) OR (to_path = ? AND to_source = ?))`
+		args = []any{path, opts.FromSource, path, opts.ToSource}
+	case DirectionOut:
+		query += `from_path = ? AND from_source = ?`
+		args = []any{path, opts.FromSource}
+	case DirectionIn:
+		query += `to_path = ? AND to_source = ?`
+		args = []any{path, opts.ToSource}
+	default:
+		return nil, fmt.Errorf("%w %q (valid: %s)", ErrInvalidDirection, opts.Direction, strings.Join(Directions, ", "))
+	}
+	query += ` AND deleted_at IS NULL`
 
 	if tag != "" {
 		query += ` AND tag = ?`
 		args = append(args, tag)
 	}
-	query += ` ORDER BY created_at DESC`
+	query += ` ORDER BY weight DESC, created_at DESC`
 
 	rows, err := s.db.QueryContext(ctx, query, args...)
 	if err != nil {
@@ -149,7 +154,7 @@ func (s *SQLiteStore) ListLinks(ctx context.Context, path, tag string, opts Link
 // and bulk operations on link categories.
 func (s *SQLiteStore) ListLinksByTag(ctx context.Context, tag string, opts LinkOptions) ([]Link, error) {
 	rows, err := s.db.QueryContext(ctx, `
-		SELECT id, from_path, from_source, to_path, to_source, tag, created_at FROM links
+		SELECT `+linkColumns+` FROM links
 		WHERE tag = ? AND from_source = ? AND to_source = ? AND deleted_at IS NULL
 		ORDER BY from_path, to_path
 	`, tag, opts.FromSource, opts.ToSource)
@@ -161,6 +166,22 @@ func (s *SQLiteStore) ListLinksByTag(ctx context.Context, tag string, opts LinkO
 	return scanLinks(rows)
 }
 
+// ListAllLinks returns every live link, ordered by endpoints, so the whole
+// link graph can be exported in one query.
+func (s *SQLiteStore) ListAllLinks(ctx context.Context, opts LinkOptions) ([]Link, error) {
+	rows, err := s.db.QueryContext(ctx, `
+		SELECT `+linkColumns+` FROM links
+		WHERE from_source = ? AND to_source = ? AND deleted_at IS NULL
+		ORDER BY from_path, to_path, tag
+	`, opts.FromSource, opts.ToSource)
+	if err != nil {
+		return nil, fmt.Errorf("list all links: %w", err)
+	}
+	defer rows.Close()
+
+	return scanLinks(rows)
+}
+
 // ListOrphanLinkPaths identifies documents with no links, helping users
 // find isolated content that may need linking or removal. Uses opts.FromSource
 // to determine which source table to check for orphans.
@@ -203,12 +224,35 @@ func (s *SQLiteStore) DeleteLinksForPath(ctx context.Context, path string, opts
 	return err
 }
 
+// liveEndpoints restricts a links query to links whose documents both
+// exist, so restoring one end never resurrects an edge to a deleted document.
+const liveEndpoints = `
+	AND EXISTS (SELECT 1 FROM documents d WHERE d.path = links.from_path AND d.deleted_at IS NULL)
+	AND EXISTS (SELECT 1 FROM documents d WHERE d.path = links.to_path AND d.deleted_at IS NULL)`
+
+// restoreLinks un-deletes a document's links in both directions, the
+// inverse of DeleteLinksForPath. Links whose other endpoint is still
+// deleted stay deleted, so restoring one document never resurrects an edge
+// to a document that does not exist. Restore runs it in the transaction
+// that restores the document, so the two cannot diverge.
+func restoreLinks(ctx context.Context, tx execer, path string) error {
+	_, err := tx.ExecContext(ctx, `
+		UPDATE links SET deleted_at = NULL
+		WHERE ((from_path = ? AND from_source = 'documents') OR (to_path = ? AND to_source = 'documents'))
+			AND deleted_at IS NOT NULL
+	`+liveEndpoints, path, path)
+	if err != nil {
+		return fmt.Errorf("restoring links for %s: %w", path, err)
+	}
+	return nil
+}
+
 // scanLinks iterates over query results, collecting links into a slice.
 func scanLinks(rows *sql.Rows) ([]Link, error) {
 	var links []Link
 	for rows.Next() {
 		var l Link
-		if err := rows.Scan(&l.ID, &l.FromPath, &l.FromSource, &l.ToPath, &l.ToSource, &l.Tag, &l.CreatedAt); err != nil {
+		if err := rows.Scan(&l.ID, &l.FromPath, &l.FromSource, &l.ToPath, &l.ToSource, &l.Tag, &l.Note, &l.Weight, &l.CreatedAt); err != nil {
 			return nil, fmt.Errorf("scan link: %w", err)
 		}
 		links = append(links, l)
@@ -0,0 +1,324 @@
+// migrate.go implements versioned schema migrations.
+//
+// Separated from schema.go because the schema files describe the current
+// schema, while migrations describe how a store created by an older llmd
+// reaches it. CREATE TABLE IF NOT EXISTS leaves an existing table alone, so
+// a new column or index needs both: the schema file for new stores and a
+// migration for existing ones.
+//
+// Design: Each migration has a version number, applied in order in its own
+// transaction, which also records it in schema_version. A store's version is
+// the highest recorded, or 0 for stores that predate version tracking.
+// Migrations must be no-ops against a schema the schema files just created,
+// because Init runs them after the schema files: that is what lets Init
+// upgrade an existing store and initialise a new one the same way. Stores
+// are migrated to the latest version when opened writable, so callers never
+// see an old schema; MigrateTo lets the db migrate command step through
+// them explicitly.
+
+package store
+
+import (
+	"context"
+	"database/sql"
+	"errors"
+	"fmt"
+	"strings"
+	"time"
+)
+
+// versionSchema records applied migrations, one row per version.
+const versionSchema = `CREATE TABLE IF NOT EXISTS schema_version (
+    version INTEGER PRIMARY KEY,  -- Migration number
+    description TEXT NOT NULL,    -- What the migration changed
+    applied_at INTEGER NOT NULL   -- Unix timestamp when it was applied
+)`
+
+// Migration is one step in the evolution of the schema.
+type Migration struct {
+	Version     int    `json:"version"`
+	Description string `json:"description"`
+	up          func(ctx context.Context, tx *sql.Tx) error
+}
+
+// migrations lists every schema change in order. Versions are never
+// renumbered or removed; append new migrations at the end.
+var migrations = []Migration{
+	{1, "add documents.content_type, links.note and links.weight", func(ctx context.Context, tx *sql.Tx) error {
+		for _, c := range []struct{ table, column, def string }{
+			{"documents", "content_type", "TEXT NOT NULL DEFAULT 'text/markdown'"},
+			{"links", "note", "TEXT NOT NULL DEFAULT ''"},
+			{"links", "weight", "INTEGER NOT NULL DEFAULT 0"},
+		} {
+			if err := addColumn(ctx, tx, c.table, c.column, c.def); err != nil {
+				return err
+			}
+		}
+		return nil
+	}},
+	{2, "rebuild the search index with diacritic folding", rebuildFTS},
+	{3, "add covering index for latest-version listings", func(ctx context.Context, tx *sql.Tx) error {
+		if ok, err := tableExists(ctx, tx, "documents"); err != nil || !ok {
+			return err
+		}
+		for _, q := range []string{
+			`CREATE INDEX IF NOT EXISTS idx_documents_deleted_path_version ON documents(deleted_at, path, version)`,
+			`DROP INDEX IF EXISTS idx_documents_deleted`, // Leading column of the new index
+		} {
+			if _, err := tx.ExecContext(ctx, q); err != nil {
+				return err
+			}
+		}
+		return nil
+	}},
+	{4, "add documents.content_hash for duplicate detection", addContentHash},
+}
+
+// LatestSchemaVersion returns the version a fully migrated store is at.
+func LatestSchemaVersion() int {
+	return migrations[len(migrations)-1].Version
+}
+
+// SchemaVersion returns the store's schema version: the highest migration
+// applied, or 0 if none has been recorded.
+func (s *SQLiteStore) SchemaVersion(ctx context.Context) (int, error) {
+	return schemaVersion(ctx, s.db)
+}
+
+// MigrateTo applies the pending migrations up to and including version, in
+// order, and returns those applied. Migrations cannot be undone, so a
+// version below the current one is an error.
+func (s *SQLiteStore) MigrateTo(ctx context.Context, version int) ([]Migration, error) {
+	applied, err := migrate(ctx, s.db, version)
+	if err != nil || version < LatestSchemaVersion() {
+		return applied, err
+	}
+	// The statements are written against the latest schema
+	return applied, s.prepare()
+}
+
+// PendingMigrations returns the migrations not yet applied, in order.
+func (s *SQLiteStore) PendingMigrations(ctx context.Context) ([]Migration, error) {
+	current, err := schemaVersion(ctx, s.db)
+	if err != nil {
+		return nil, err
+	}
+	var pending []Migration
+	for _, m := range migrations {
+		if m.Version > current {
+			pending = append(pending, m)
+		}
+	}
+	return pending, nil
+}
+
+// checkMigrated returns ErrMigrationPending if the store has a schema that
+// is behind the latest version. An empty database has nothing to migrate.
+func (s *SQLiteStore) checkMigrated(ctx context.Context) error {
+	var tables int
+	if err := s.db.QueryRowContext(ctx, `SELECT COUNT(*) FROM sqlite_master WHERE type = 'table'`).Scan(&tables); err != nil {
+		return fmt.Errorf("inspect schema: %w", err)
+	}
+	if tables == 0 {
+		return nil
+	}
+	current, err := schemaVersion(ctx, s.db)
+	if err != nil {
+		return err
+	}
+	if current < LatestSchemaVersion() {
+		return fmt.Errorf("schema version %d, latest is %d: %w", current, LatestSchemaVersion(), ErrMigrationPending)
+	}
+	return nil
+}
+
+// schemaVersion reads the current version, treating a missing
+// schema_version table as version 0.
+func schemaVersion(ctx context.Context, db *sql.DB) (int, error) {
+	ok, err := tableExists(ctx, db, "schema_version")
+	if err != nil || !ok {
+		return 0, err
+	}
+	var version int
+	err = db.QueryRowContext(ctx, `SELECT COALESCE(MAX(version), 0) FROM schema_version`).Scan(&version)
+	if err != nil {
+		return 0, fmt.Errorf("read schema version: %w", err)
+	}
+	return version, nil
+}
+
+// migrate applies the migrations after the current version up to target.
+// An empty database has no schema to migrate yet and is left alone; Init
+// creates the schema and migrates it.
+func migrate(ctx context.Context, db *sql.DB, target int) ([]Migration, error) {
+	if target < 0 || target > LatestSchemaVersion() {
+		return nil, fmt.Errorf("unknown schema version %d (latest is %d)", target, LatestSchemaVersion())
+	}
+	var tables int
+	if err := db.QueryRowContext(ctx, `SELECT COUNT(*) FROM sqlite_master WHERE type = 'table'`).Scan(&tables); err != nil {
+		return nil, fmt.Errorf("inspect schema: %w", err)
+	}
+	if tables == 0 {
+		return nil, nil
+	}
+	if _, err := db.ExecContext(ctx, versionSchema); err != nil {
+		return nil, fmt.Errorf("create schema_version: %w", err)
+	}
+	current, err := schemaVersion(ctx, db)
+	if err != nil {
+		return nil, err
+	}
+	if target < current {
+		return nil, fmt.Errorf("schema is at version %d: migrations cannot be undone", current)
+	}
+
+	var applied []Migration
+	for _, m := range migrations {
+		if m.Version <= current || m.Version > target {
+			continue
+		}
+		if err := apply(ctx, db, m); err != nil {
+			return applied, fmt.Errorf("migration %d (%s): %w", m.Version, m.Description, err)
+		}
+		applied = append(applied, m)
+	}
+	return applied, nil
+}
+
+// apply runs m and records it in one transaction, so a failed migration
+// leaves neither its changes nor its version behind.
+func apply(ctx context.Context, db *sql.DB, m Migration) error {
+	tx, err := db.BeginTx(ctx, nil)
+	if err != nil {
+		return err
+	}
+	defer func() { _ = tx.Rollback() }() // no-op after commit
+
+	if err := m.up(ctx, tx); err != nil {
+		return err
+	}
+	if _, err := tx.ExecContext(ctx, `INSERT INTO schema_version (version, description, applied_at) VALUES (?, ?, ?)`,
+		m.Version, m.Description, time.Now().Unix()); err != nil {
+		return err
+	}
+	return tx.Commit()
+}
+
+// queryer is the query method shared by *sql.DB and *sql.Tx.
+type queryer interface {
+	QueryRowContext(ctx context.Context, query string, args ...any) *sql.Row
+}
+
+// tableExists reports whether the database has a table named name.
+func tableExists(ctx context.Context, db queryer, name string) (bool, error) {
+	var n int
+	err := db.QueryRowContext(ctx, `SELECT COUNT(*) FROM sqlite_master WHERE type = 'table' AND name = ?`, name).Scan(&n)
+	if err != nil {
+		return false, fmt.Errorf("inspect schema: %w", err)
+	}
+	return n > 0, nil
+}
+
+// addColumn adds column to table unless it already has it. A table that
+// does not exist is skipped; the schema files create it complete.
+func addColumn(ctx context.Context, tx *sql.Tx, table, column, def string) error {
+	var cols, found int
+	err := tx.QueryRowContext(ctx, `SELECT COUNT(*), COALESCE(SUM(name = ?), 0) FROM pragma_table_info(?)`,
+		column, table).Scan(&cols, &found)
+	if err != nil {
+		return fmt.Errorf("inspect %s: %w", table, err)
+	}
+	if cols == 0 || found > 0 {
+		return nil
+	}
+	if _, err := tx.ExecContext(ctx, fmt.Sprintf("ALTER TABLE %s ADD COLUMN %s %s", table, column, def)); err != nil {
+		return fmt.Errorf("add %s.%s: %w", table, column, err)
+	}
+	return nil
+}
+
+// addContentHash adds documents.content_hash with its index and fills it in
+// for existing versions. Sealed content is left unhashed, as encrypted
+// stores keep no hashes (see dedup.go).
+func addContentHash(ctx context.Context, tx *sql.Tx) error {
+	if ok, err := tableExists(ctx, tx, "documents"); err != nil || !ok {
+		return err
+	}
+	if err := addColumn(ctx, tx, "documents", "content_hash", "TEXT NOT NULL DEFAULT ''"); err != nil {
+		return err
+	}
+	if _, err := tx.ExecContext(ctx, `CREATE INDEX IF NOT EXISTS idx_documents_content_hash ON documents(content_hash)`); err != nil {
+		return err
+	}
+
+	rows, err := tx.QueryContext(ctx, `SELECT id, content FROM documents WHERE content_hash = ''`)
+	if err != nil {
+		return fmt.Errorf("read content: %w", err)
+	}
+	hashes := make(map[int64]string)
+	for rows.Next() {
+		var id int64
+		var content string
+		if err := rows.Scan(&id, &content); err != nil {
+			rows.Close()
+			return fmt.Errorf("scan content: %w", err)
+		}
+		if !strings.HasPrefix(content, sealedPrefix) {
+			hashes[id] = contentHash(content)
+		}
+	}
+	rows.Close()
+	if err := rows.Err(); err != nil {
+		return fmt.Errorf("read content: %w", err)
+	}
+	for id, h := range hashes {
+		if _, err := tx.ExecContext(ctx, `UPDATE documents SET content_hash = ? WHERE id = ?`, h, id); err != nil {
+			return fmt.Errorf("hash content: %w", err)
+		}
+	}
+	return nil
+}
+
+// ftsSchema is the schema file defining documents_fts.
+const ftsSchema = "sql/002_documents_fts.sql"
+
+// DefaultTokenizer is the FTS5 tokenizer documents_fts is created with: it
+// folds case across Unicode and strips accents, so "cafe" matches "café".
+const DefaultTokenizer = "unicode61 remove_diacritics 2"
+
+// rebuildFTS recreates documents_fts if it was built without the default
+// tokenizer.
+func rebuildFTS(ctx context.Context, tx *sql.Tx) error {
+	return retokenize(ctx, tx, DefaultTokenizer)
+}
+
+// retokenize recreates documents_fts with tokenizer unless it already uses
+// it. FTS5 fixes the tokenizer when the table is created, so the table is
+// dropped, recreated from its schema file and rebuilt from documents. A store
+// without the table (such as an encrypted one) is left alone.
+func retokenize(ctx context.Context, tx *sql.Tx, tokenizer string) error {
+	var def string
+	err := tx.QueryRowContext(ctx, `SELECT sql FROM sqlite_master WHERE type = 'table' AND name = 'documents_fts'`).Scan(&def)
+	if errors.Is(err, sql.ErrNoRows) || strings.Contains(def, "tokenize='"+tokenizer+"'") {
+		return nil
+	}
+	if err != nil {
+		return fmt.Errorf("inspect documents_fts: %w", err)
+	}
+
+	data, err := schemas.ReadFile(ftsSchema)
+	if err != nil {
+		return fmt.Errorf("read %s: %w", ftsSchema, err)
+	}
+	schema := strings.Replace(string(data), "tokenize='"+DefaultTokenizer+"'", "tokenize='"+tokenizer+"'", 1)
+	for _, q := range []string{
+		`DROP TABLE documents_fts`,
+		schema,
+		`INSERT INTO documents_fts(documents_fts) VALUES('rebuild')`,
+	} {
+		if _, err := tx.ExecContext(ctx, q); err != nil {
+			return fmt.Errorf("rebuild documents_fts: %w", err)
+		}
+	}
+	return nil
+}
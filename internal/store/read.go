@@ -17,20 +17,17 @@ import (
 	"errors"
 	"fmt"
 	"strings"
+	"time"
 )
 
 // Latest returns the highest version of a document at the given path.
 // The includeDeleted flag enables reading soft-deleted documents for recovery
 // workflows - without it, deleted documents are invisible to prevent accidental use.
 func (s *SQLiteStore) Latest(ctx context.Context, path string, includeDeleted bool) (*Document, error) {
-	query := `SELECT id, key, path, content, version, author, message, created_at, deleted_at
-		FROM documents WHERE path = ?`
-	if !includeDeleted {
-		query += ` AND deleted_at IS NULL`
+	if includeDeleted {
+		return s.scanDocument(s.queryRow(ctx, s.stmts.latestAny, latestAnyQuery, path))
 	}
-	query += ` ORDER BY version DESC LIMIT 1`
-
-	return s.scanDocument(s.db.QueryRowContext(ctx, query, path))
+	return s.scanDocument(s.queryRow(ctx, s.stmts.latest, latestQuery, path))
 }
 
 // Version returns a specific historical version of a document.
@@ -38,26 +35,54 @@ func (s *SQLiteStore) Latest(ctx context.Context, path string, includeDeleted bo
 // version queries don't filter by deleted_at because you may need to examine
 // the exact state at a point in time regardless of current deletion status.
 func (s *SQLiteStore) Version(ctx context.Context, path string, version int) (*Document, error) {
-	query := `SELECT id, key, path, content, version, author, message, created_at, deleted_at
+	query := `SELECT id, key, path, content, version, author, message, created_at, deleted_at, content_type
 		FROM documents WHERE path = ? AND version = ?`
 	return s.scanDocument(s.db.QueryRowContext(ctx, query, path, version))
 }
 
+// VersionAsOf returns the latest version of a document created at or before
+// t, reconstructing what the document looked like at that moment. Versions
+// that had already been deleted by t are skipped, so a document deleted
+// before t yields ErrNotFound, as does one created after it. Deletions that
+// happened after t are ignored because they were not yet visible.
+func (s *SQLiteStore) VersionAsOf(ctx context.Context, path string, t time.Time) (*Document, error) {
+	query := `SELECT id, key, path, content, version, author, message, created_at, deleted_at, content_type
+		FROM documents WHERE path = ? AND created_at <= ?
+		AND (deleted_at IS NULL OR deleted_at > ?)
+		ORDER BY version DESC LIMIT 1`
+	at := t.Unix()
+	return s.scanDocument(s.db.QueryRowContext(ctx, query, path, at, at))
+}
+
 // ByKey retrieves a document by its 8-character unique key.
 // Keys provide stable external references that survive renames - useful for
 // URLs, cross-references, and integrations that need permanent document IDs.
 func (s *SQLiteStore) ByKey(ctx context.Context, key string) (*Document, error) {
-	query := `SELECT id, key, path, content, version, author, message, created_at, deleted_at
-		FROM documents WHERE key = ?`
-	return s.scanDocument(s.db.QueryRowContext(ctx, query, key))
+	return s.scanDocument(s.queryRow(ctx, s.stmts.byKey, byKeyQuery, key))
 }
 
 // List returns the latest version of all documents matching a path prefix.
 // The subquery finds max versions per path first, then joins to get full documents.
 // This two-step approach is more efficient than alternatives for SQLite.
 func (s *SQLiteStore) List(ctx context.Context, prefix string, includeDeleted bool, deletedOnly bool) ([]Document, error) {
+	return s.listLatest(ctx, prefix, "", false, includeDeleted, deletedOnly)
+}
+
+// ListContaining is List restricted to documents whose content contains
+// text, so a caller scanning content (grep) loads only the candidates that
+// can match. With ignoreCase the comparison folds ASCII letters only, as
+// SQLite's LIKE does; other characters must match exactly. An encrypted
+// store cannot compare content in SQL and returns every document List
+// would, so callers must still check each one.
+func (s *SQLiteStore) ListContaining(ctx context.Context, prefix, text string, ignoreCase, includeDeleted, deletedOnly bool) ([]Document, error) {
+	return s.listLatest(ctx, prefix, text, ignoreCase, includeDeleted, deletedOnly)
+}
+
+// listLatest backs List and ListContaining. An empty text applies no
+// content filter.
+func (s *SQLiteStore) listLatest(ctx context.Context, prefix, text string, ignoreCase, includeDeleted, deletedOnly bool) ([]Document, error) {
 	var b strings.Builder
-	b.WriteString(`SELECT d.id, d.key, d.path, d.content, d.version, d.author, d.message, d.created_at, d.deleted_at
+	b.WriteString(`SELECT d.id, d.key, d.path, d.content, d.version, d.author, d.message, d.created_at, d.deleted_at, d.content_type
 		FROM documents d
 		INNER JOIN (
 			SELECT path, MAX(version) as max_version FROM documents`)
@@ -85,11 +110,25 @@ func (s *SQLiteStore) List(ctx context.Context, prefix string, includeDeleted bo
 	b.WriteString(` GROUP BY path
 		) latest ON d.path = latest.path AND d.version = latest.max_version`)
 
+	var where []string
 	switch {
 	case deletedOnly:
-		b.WriteString(` WHERE d.deleted_at IS NOT NULL`)
+		where = append(where, `d.deleted_at IS NOT NULL`)
 	case !includeDeleted:
-		b.WriteString(` WHERE d.deleted_at IS NULL`)
+		where = append(where, `d.deleted_at IS NULL`)
+	}
+	switch {
+	case text == "" || s.aead != nil:
+	case ignoreCase:
+		where = append(where, `d.content LIKE ? ESCAPE '\'`)
+		args = append(args, "%"+escapeLike(text)+"%")
+	default:
+		where = append(where, `instr(d.content, ?) > 0`)
+		args = append(args, text)
+	}
+	if len(where) > 0 {
+		b.WriteString(` WHERE `)
+		b.WriteString(strings.Join(where, ` AND `))
 	}
 
 	b.WriteString(` ORDER BY d.path`)
@@ -103,6 +142,51 @@ func (s *SQLiteStore) List(ctx context.Context, prefix string, includeDeleted bo
 	return s.scanDocuments(rows)
 }
 
+// escapeLike escapes the LIKE wildcards in s so it matches literally.
+func escapeLike(s string) string {
+	return strings.NewReplacer(`\`, `\\`, `%`, `\%`, `_`, `\_`).Replace(s)
+}
+
+// ListAsOf returns, for each path matching prefix, the version that
+// VersionAsOf would return at t. Documents created after t, or already
+// deleted by then, are absent, so the result is the listing as it would
+// have appeared at that moment. A deletion after t was not yet visible, so
+// DeletedAt is cleared on every returned document.
+func (s *SQLiteStore) ListAsOf(ctx context.Context, prefix string, t time.Time) ([]Document, error) {
+	at := t.Unix()
+	var b strings.Builder
+	b.WriteString(`SELECT d.id, d.key, d.path, d.content, d.version, d.author, d.message, d.created_at, d.deleted_at, d.content_type
+		FROM documents d
+		INNER JOIN (
+			SELECT path, MAX(version) as max_version FROM documents
+			WHERE created_at <= ? AND (deleted_at IS NULL OR deleted_at > ?)`)
+	args := []any{at, at}
+
+	if prefix != "" {
+		b.WriteString(` AND path LIKE ?`)
+		args = append(args, prefix+"%")
+	}
+
+	b.WriteString(` GROUP BY path
+		) asof ON d.path = asof.path AND d.version = asof.max_version
+		ORDER BY d.path`)
+
+	rows, err := s.db.QueryContext(ctx, b.String(), args...)
+	if err != nil {
+		return nil, fmt.Errorf("list documents as of: %w", err)
+	}
+	defer rows.Close()
+
+	docs, err := s.scanDocuments(rows)
+	if err != nil {
+		return nil, err
+	}
+	for i := range docs {
+		docs[i].DeletedAt = nil
+	}
+	return docs, nil
+}
+
 // ListPaths returns only document paths without content.
 // This enables efficient glob matching and tree displays without loading
 // potentially large document content into memory.
@@ -137,7 +221,7 @@ func (s *SQLiteStore) ListPaths(ctx context.Context, prefix string) ([]string, e
 // The limit parameter prevents unbounded queries on documents with many versions.
 // Used for audit trails, version selection UIs, and rollback decisions.
 func (s *SQLiteStore) History(ctx context.Context, path string, limit int, includeDeleted bool) ([]Document, error) {
-	query := `SELECT id, key, path, content, version, author, message, created_at, deleted_at
+	query := `SELECT id, key, path, content, version, author, message, created_at, deleted_at, content_type
 		FROM documents WHERE path = ?`
 	args := []any{path}
 
@@ -186,7 +270,7 @@ func (s *SQLiteStore) Meta(ctx context.Context, path string) (*DocumentMeta, err
 
 	// Query filters deleted_at IS NULL, so we don't need to scan it
 	err := s.db.QueryRowContext(ctx, `
-		SELECT key, path, version, author, message, created_at, length(content)
+		SELECT key, path, version, author, message, created_at, `+s.sizeExpr("content")+`
 		FROM documents
 		WHERE path = ? AND deleted_at IS NULL
 		ORDER BY version DESC LIMIT 1
@@ -211,7 +295,7 @@ func (s *SQLiteStore) Meta(ctx context.Context, path string) (*DocumentMeta, err
 // before operations that require the document to exist.
 func (s *SQLiteStore) Exists(ctx context.Context, path string) (bool, error) {
 	var n int
-	err := s.db.QueryRowContext(ctx, `SELECT 1 FROM documents WHERE path = ? AND deleted_at IS NULL LIMIT 1`, path).Scan(&n)
+	err := s.queryRow(ctx, s.stmts.exists, existsQuery, path).Scan(&n)
 	if errors.Is(err, sql.ErrNoRows) {
 		return false, nil
 	}
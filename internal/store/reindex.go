@@ -0,0 +1,36 @@
+// reindex.go implements rebuilding the full-text search index.
+//
+// Separated from search.go because this is a recovery operation, not a
+// query: it is only needed when the index has drifted from the documents
+// table, for example after rows were written with the triggers bypassed.
+//
+// Design: documents_fts is an external-content table, so FTS5's own
+// 'rebuild' command discards the index and re-reads every row of documents.
+// That covers soft-deleted versions too, which search relies on for -D/-A.
+
+package store
+
+import (
+	"context"
+	"database/sql"
+	"fmt"
+)
+
+// Reindex rebuilds the FTS index from the documents table and returns the
+// number of document versions indexed.
+func (s *SQLiteStore) Reindex(ctx context.Context) (int64, error) {
+	if s.aead != nil {
+		return 0, ErrNoSearchIndex
+	}
+	var n int64
+	err := s.Tx(ctx, func(tx *sql.Tx) error {
+		if _, err := tx.ExecContext(ctx, `INSERT INTO documents_fts(documents_fts) VALUES('rebuild')`); err != nil {
+			return fmt.Errorf("rebuilding search index: %w", err)
+		}
+		if err := tx.QueryRowContext(ctx, `SELECT COUNT(*) FROM documents`).Scan(&n); err != nil {
+			return fmt.Errorf("counting documents: %w", err)
+		}
+		return nil
+	})
+	return n, err
+}
@@ -23,6 +23,7 @@
 package store
 
 import (
+	"context"
 	"database/sql"
 	"embed"
 	"errors"
@@ -43,6 +44,24 @@ var (
 	ErrAlreadyExists = errors.New("document already exists")
 	// ErrContentTooLarge is returned when document content exceeds the configured limit.
 	ErrContentTooLarge = errors.New("document content too large")
+	// ErrReadOnly is returned for any change attempted on a store opened
+	// read-only.
+	ErrReadOnly = errors.New("store is read-only")
+	// ErrInvalidDirection is returned by ListLinks for a direction other
+	// than those in Directions.
+	ErrInvalidDirection = errors.New("invalid link direction")
+	// ErrEncrypted is returned when opening an encrypted store without a
+	// passphrase.
+	ErrEncrypted = errors.New("store is encrypted: passphrase required")
+	// ErrPassphrase is returned when opening an encrypted store with the
+	// wrong passphrase.
+	ErrPassphrase = errors.New("incorrect passphrase")
+	// ErrNoSearchIndex is returned by Search and Reindex on an encrypted
+	// store, which keeps no full-text index.
+	ErrNoSearchIndex = errors.New("full-text search is unavailable: store content is encrypted (use grep)")
+	// ErrMigrationPending is returned when opening a store read-only whose
+	// schema is older than this llmd, since migrating it would be a write.
+	ErrMigrationPending = errors.New("store needs migration: run llmd db migrate")
 )
 
 // ExecEmbedded executes all .sql files from an embedded filesystem in alphabetical order.
@@ -77,7 +96,12 @@ func ExecEmbedded(db *sql.DB, fsys embed.FS, dir string) error {
 	return nil
 }
 
-// execSchema executes the embedded core schema files.
+// execSchema executes the embedded core schema files, then records the
+// store as migrated to the latest schema version.
 func execSchema(db *sql.DB) error {
-	return ExecEmbedded(db, schemas, "sql")
+	if err := ExecEmbedded(db, schemas, "sql"); err != nil {
+		return err
+	}
+	_, err := migrate(context.Background(), db, LatestSchemaVersion())
+	return err
 }
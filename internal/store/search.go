@@ -12,6 +12,7 @@ package store
 
 import (
 	"context"
+	"fmt"
 	"strings"
 )
 
@@ -20,8 +21,71 @@ import (
 // prefix* matching, and "phrase" queries. Results are filtered by path prefix
 // and deletion status according to the flags.
 func (s *SQLiteStore) Search(ctx context.Context, query string, prefix string, includeDeleted bool, deletedOnly bool) ([]Document, error) {
+	return s.search(ctx, query, prefix, includeDeleted, deletedOnly, false)
+}
+
+// SearchRanked is Search over active documents, ordered by relevance: FTS5's
+// rank (BM25 by default), most relevant first, with ties in path order.
+func (s *SQLiteStore) SearchRanked(ctx context.Context, query string, prefix string) ([]Document, error) {
+	return s.search(ctx, query, prefix, false, false, true)
+}
+
+// SearchPage is Search ordered as SearchRanked, returning at most limit
+// documents after skipping offset of them, and the number of matches in
+// total, so a caller with little room for results can fetch them a page at
+// a time. A limit of 0 returns every match after offset.
+func (s *SQLiteStore) SearchPage(ctx context.Context, query, prefix string, includeDeleted, deletedOnly bool, limit, offset int) ([]Document, int, error) {
+	if s.aead != nil {
+		return nil, 0, ErrNoSearchIndex
+	}
+	if limit < 0 || offset < 0 {
+		return nil, 0, fmt.Errorf("limit and offset must be >= 0, got %d and %d", limit, offset)
+	}
+	q, args := searchQuery(query, prefix, includeDeleted, deletedOnly)
+
+	var total int
+	if err := s.db.QueryRowContext(ctx, `SELECT COUNT(*) FROM (`+q+`)`, args...).Scan(&total); err != nil {
+		return nil, 0, err
+	}
+
+	// SQLite reads a negative LIMIT as no limit
+	if limit == 0 {
+		limit = -1
+	}
+	rows, err := s.db.QueryContext(ctx, q+` ORDER BY documents_fts.rank, d.path LIMIT ? OFFSET ?`, append(args, limit, offset)...)
+	if err != nil {
+		return nil, 0, err
+	}
+	defer rows.Close()
+
+	docs, err := s.scanDocuments(rows)
+	return docs, total, err
+}
+
+// search backs Search and SearchRanked.
+func (s *SQLiteStore) search(ctx context.Context, query, prefix string, includeDeleted, deletedOnly, ranked bool) ([]Document, error) {
+	if s.aead != nil {
+		return nil, ErrNoSearchIndex
+	}
+	q, args := searchQuery(query, prefix, includeDeleted, deletedOnly)
+	if ranked {
+		q += ` ORDER BY documents_fts.rank, d.path`
+	}
+
+	rows, err := s.db.QueryContext(ctx, q, args...)
+	if err != nil {
+		return nil, err
+	}
+	defer rows.Close()
+
+	return s.scanDocuments(rows)
+}
+
+// searchQuery returns the unordered query selecting the latest version of
+// each document matching query, and its arguments.
+func searchQuery(query, prefix string, includeDeleted, deletedOnly bool) (string, []any) {
 	var b strings.Builder
-	b.WriteString(`SELECT d.id, d.key, d.path, d.content, d.version, d.author, d.message, d.created_at, d.deleted_at
+	b.WriteString(`SELECT d.id, d.key, d.path, d.content, d.version, d.author, d.message, d.created_at, d.deleted_at, d.content_type
 		FROM documents_fts
 		JOIN documents d ON documents_fts.rowid = d.id
 		INNER JOIN (
@@ -51,17 +115,36 @@ func (s *SQLiteStore) Search(ctx context.Context, query string, prefix string, i
 		) latest ON d.path = latest.path AND d.version = latest.max_version
 		WHERE documents_fts MATCH ?`)
 
-	args = append(args, query)
-
 	// Note: No need to re-filter by deleted_at here - the subquery already
 	// determined the "latest" version considering deletion status, and the
 	// join limits results to exactly those versions.
 
-	rows, err := s.db.QueryContext(ctx, b.String(), args...)
-	if err != nil {
-		return nil, err
+	return b.String(), append(args, query)
+}
+
+// LiteralQuery quotes each whitespace-separated term of query as an FTS5
+// string, so punctuation such as "C++", "foo-bar" or "a:b" is matched as
+// text rather than parsed as query syntax. Terms are still implicitly ANDed;
+// operators, prefix* and column filters lose their meaning.
+func LiteralQuery(query string) string {
+	terms := strings.Fields(query)
+	if len(terms) == 0 {
+		return `""`
 	}
-	defer rows.Close()
+	for i, t := range terms {
+		terms[i] = `"` + strings.ReplaceAll(t, `"`, `""`) + `"`
+	}
+	return strings.Join(terms, " ")
+}
 
-	return s.scanDocuments(rows)
+// IsQueryError reports whether err came from FTS5 rejecting the query
+// syntax, as opposed to a failure of the store itself. Bare punctuation is
+// the usual cause: "C++" is a syntax error and "foo-bar" reads as a filter
+// on a column named "bar".
+func IsQueryError(err error) bool {
+	if err == nil {
+		return false
+	}
+	msg := err.Error()
+	return strings.Contains(msg, "fts5:") || strings.Contains(msg, "no such column")
 }
@@ -7,17 +7,31 @@ CREATE TABLE IF NOT EXISTS documents (
     id INTEGER PRIMARY KEY AUTOINCREMENT,  -- Internal row ID
     key TEXT NOT NULL,                     -- 8-char unique identifier for external reference
     path TEXT NOT NULL,                    -- Virtual path (e.g., "docs/readme")
-    content TEXT NOT NULL,                 -- Document content (markdown)
+    content TEXT NOT NULL,                 -- Document content (markdown unless content_type says otherwise)
     version INTEGER NOT NULL DEFAULT 1,    -- Version number, increments on each write
     author TEXT NOT NULL,                  -- Who created this version
     message TEXT,                          -- Optional commit message
     created_at INTEGER NOT NULL,           -- Unix timestamp of creation
     deleted_at INTEGER,                    -- Unix timestamp of soft delete, NULL if active
+    content_type TEXT NOT NULL DEFAULT 'text/markdown', -- MIME type of content
+    content_hash TEXT NOT NULL DEFAULT '', -- SHA-256 of content (hex), '' in encrypted stores
     UNIQUE(path, version)
 );
 
-CREATE INDEX IF NOT EXISTS idx_documents_key ON documents(key);
+-- Keys are stable external references, so the database enforces uniqueness;
+-- inserts regenerate the key on the rare collision.
+CREATE UNIQUE INDEX IF NOT EXISTS idx_documents_key_unique ON documents(key);
 CREATE INDEX IF NOT EXISTS idx_documents_path ON documents(path);
 CREATE INDEX IF NOT EXISTS idx_documents_path_version ON documents(path, version DESC);
-CREATE INDEX IF NOT EXISTS idx_documents_deleted ON documents(deleted_at);
 CREATE INDEX IF NOT EXISTS idx_documents_path_deleted ON documents(path, deleted_at);
+
+-- Covers the latest-version subquery behind every listing:
+--   SELECT path, MAX(version) FROM documents WHERE deleted_at IS NULL GROUP BY path
+-- deleted_at leads so the planner can seek to the active (or deleted) rows;
+-- path then version let it group and take the maximum in index order
+-- without touching the table or building a temporary B-tree. It also
+-- serves deleted_at-only lookups such as vacuum's.
+CREATE INDEX IF NOT EXISTS idx_documents_deleted_path_version ON documents(deleted_at, path, version);
+
+-- Groups identical content for duplicate detection (see dedup.go).
+CREATE INDEX IF NOT EXISTS idx_documents_content_hash ON documents(content_hash);
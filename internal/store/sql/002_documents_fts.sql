@@ -6,12 +6,21 @@
 -- index enables the -D (deleted only) and -A (include all) search flags to work.
 -- Conditional triggers that skip soft-deleted docs would break these features.
 -- The minor index bloat is cleaned up when vacuum hard-deletes old documents.
+--
+-- Tokenizer: unicode61 folds case for all of Unicode, not just ASCII, and
+-- strips accents so "cafe" matches "café" and vice versa. remove_diacritics 2
+-- also folds letters carrying several diacritics (Vietnamese "ệ"), which the
+-- default of 1 leaves alone. Stores created before the tokenizer was set are
+-- rebuilt by migration 2 in migrate.go, and the search.tokenizer config key
+-- swaps it for another (see retokenize). Encrypted stores drop this table
+-- (see encrypt.go).
 
 CREATE VIRTUAL TABLE IF NOT EXISTS documents_fts USING fts5(
     path,
     content,
     content=documents,
-    content_rowid=id
+    content_rowid=id,
+    tokenize='unicode61 remove_diacritics 2'
 );
 
 CREATE TRIGGER IF NOT EXISTS documents_fts_insert AFTER INSERT ON documents BEGIN
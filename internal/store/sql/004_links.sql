@@ -11,6 +11,8 @@ CREATE TABLE IF NOT EXISTS links (
     to_path TEXT NOT NULL,                      -- Target document path
     to_source TEXT NOT NULL DEFAULT 'documents',    -- Target table
     tag TEXT NOT NULL DEFAULT '',               -- Optional link type label
+    note TEXT NOT NULL DEFAULT '',              -- Optional free-text annotation
+    weight INTEGER NOT NULL DEFAULT 0,          -- Display order, heavier first
     created_at INTEGER NOT NULL,                -- Unix timestamp of creation
     deleted_at INTEGER,                         -- Unix timestamp of soft delete, NULL if active
     UNIQUE(from_path, from_source, to_path, to_source, tag)
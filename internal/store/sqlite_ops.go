@@ -13,21 +13,27 @@ package store
 
 import (
 	"context"
+	"crypto/cipher"
 	"crypto/rand"
 	"database/sql"
 	"encoding/base32"
 	"errors"
 	"fmt"
+	"net/url"
 	"strings"
+	"time"
 
-	// Register sqlite driver
-	_ "modernc.org/sqlite"
+	// Also registers the sqlite driver
+	"modernc.org/sqlite"
+	sqlite3 "modernc.org/sqlite/lib"
 )
 
 // SQLiteStore implements Store using SQLite with WAL mode for concurrent access.
 // It provides versioned document storage with full-text search capabilities.
 type SQLiteStore struct {
-	db *sql.DB
+	db    *sql.DB
+	stmts stmts       // Prepared hot-path queries; see stmt.go
+	aead  cipher.AEAD // Content key of an encrypted store, nil if plaintext; see encrypt.go
 }
 
 // Compile-time interface compliance check. This ensures SQLiteStore implements
@@ -36,59 +42,190 @@ type SQLiteStore struct {
 // when the method is called. This is especially valuable when interfaces change.
 var _ Store = (*SQLiteStore)(nil)
 
-// Open opens the SQLite database file at `path` and returns a configured
-// SQLiteStore. The caller should call Close on the returned store.
+// DefaultBusyTimeout is how long a connection waits for another's lock when
+// OpenOptions does not say otherwise.
+const DefaultBusyTimeout = 5 * time.Second
+
+// OpenOptions configures a database connection.
+type OpenOptions struct {
+	BusyTimeout time.Duration // Lock wait before SQLITE_BUSY (0 = DefaultBusyTimeout)
+	ReadOnly    bool          // Refuse writes at the connection level
+	NoMigrate   bool          // Leave the schema at its version (see MigrateTo)
+	Passphrase  string        // Unlocks an encrypted store (see EnableEncryption)
+	Tokenizer   string        // FTS5 tokenizer for search (empty = DefaultTokenizer)
+}
+
+// Open opens the SQLite database file at `path` with default options.
+// The caller should call Close on the returned store.
+func Open(path string) (*SQLiteStore, error) {
+	return OpenWithOptions(path, OpenOptions{})
+}
+
+// OpenWithOptions opens the SQLite database file at `path` and returns a
+// configured SQLiteStore. The caller should call Close on the returned store.
 //
 // The pragma configuration balances durability, performance, and concurrency
 // for llmd's usage pattern (frequent small writes, occasional bulk imports,
 // read-heavy LLM workflows).
-func Open(path string) (*SQLiteStore, error) {
-	db, err := sql.Open("sqlite", path)
-	if err != nil {
-		return nil, fmt.Errorf("open database %s: %w", path, err)
+func OpenWithOptions(path string, opts OpenOptions) (*SQLiteStore, error) {
+	busy := opts.BusyTimeout
+	if busy <= 0 {
+		busy = DefaultBusyTimeout
+	}
+
+	// Pragmas are passed in the DSN rather than executed once, because
+	// database/sql pools connections and a PRAGMA only affects the connection
+	// it runs on. The driver applies these to every new connection.
+	q := url.Values{}
+
+	// Read-only: query_only makes SQLite itself reject any statement that
+	// would change the file, so nothing slips past the service-level checks.
+	// The journal mode is left as the store was created (WAL), since
+	// switching it is a write, and transactions stay deferred so readers
+	// never take the write lock.
+	if opts.ReadOnly {
+		q.Add("_pragma", "query_only(1)")
+		q.Add("_pragma", fmt.Sprintf("busy_timeout(%d)", busy.Milliseconds()))
+		s, err := open(path, q, opts.Passphrase)
+		if err != nil {
+			return nil, err
+		}
+		// As below, NoMigrate runs unprepared so db migrate can report
+		// what is pending.
+		if opts.NoMigrate {
+			return s, nil
+		}
+		// The statements are written against the latest schema, so an
+		// older store would fail to prepare with a confusing "no such
+		// column" rather than saying what is wrong.
+		if err := s.checkMigrated(context.Background()); err != nil {
+			s.Close()
+			return nil, fmt.Errorf("open database %s: %w", path, err)
+		}
+		if err := s.prepare(); err != nil {
+			s.Close()
+			return nil, fmt.Errorf("open database %s: %w", path, err)
+		}
+		return s, nil
 	}
 
 	// WAL mode: Allows concurrent readers while writing. Without this, readers
 	// block writers and vice versa. Critical for MCP server scenarios where
 	// an LLM might read while the user writes. Trade-off: Creates -wal and
 	// -shm files alongside the database.
-	if _, err := db.Exec(`PRAGMA journal_mode=WAL`); err != nil {
-		db.Close()
-		return nil, fmt.Errorf("setting WAL mode: %w", err)
-	}
+	q.Add("_pragma", "journal_mode(WAL)")
 
 	// Busy timeout: How long to wait when another connection holds a lock.
-	// 5 seconds is generous - most operations complete in milliseconds. This
-	// prevents "database is locked" errors during concurrent access without
-	// waiting forever on a stuck connection.
-	if _, err := db.Exec(`PRAGMA busy_timeout=5000`); err != nil {
-		db.Close()
-		return nil, fmt.Errorf("setting busy timeout: %w", err)
-	}
+	// The 5 second default is generous - most operations complete in
+	// milliseconds. This prevents "database is locked" errors during
+	// concurrent access without waiting forever on a stuck connection.
+	q.Add("_pragma", fmt.Sprintf("busy_timeout(%d)", busy.Milliseconds()))
 
 	// Synchronous NORMAL: With WAL mode, NORMAL is safe against corruption
 	// (WAL provides the durability guarantee). FULL would fsync on every
 	// commit, which is ~10x slower. The only risk with NORMAL is losing the
 	// last transaction on OS crash - acceptable for a document store where
 	// users can re-run the command.
-	if _, err := db.Exec(`PRAGMA synchronous=NORMAL`); err != nil {
+	q.Add("_pragma", "synchronous(NORMAL)")
+
+	// Immediate transactions: Take the write lock at BEGIN. Transactions here
+	// read before they write (e.g. MAX(version) then INSERT), and a deferred
+	// transaction that tries to upgrade after another writer commits fails
+	// with SQLITE_BUSY at once instead of waiting out the busy timeout.
+	q.Set("_txlock", "immediate")
+
+	s, err := open(path, q, opts.Passphrase)
+	if err != nil {
+		return nil, err
+	}
+	// A store left at an older version has statements that may not prepare
+	// against its schema; it runs unprepared until MigrateTo brings it up.
+	if opts.NoMigrate {
+		return s, nil
+	}
+	// Bring stores created by an older llmd up to date. Read-only opens
+	// cannot, so they rely on the store having been opened writable since.
+	if _, err := migrate(context.Background(), s.db, LatestSchemaVersion()); err != nil {
+		s.Close()
+		return nil, fmt.Errorf("upgrade database %s: %w", path, err)
+	}
+	// Read-only opens search with whatever tokenizer the index was built
+	// with, as rebuilding it is a write.
+	if opts.Tokenizer != "" {
+		err := s.Tx(context.Background(), func(tx *sql.Tx) error {
+			return retokenize(context.Background(), tx, opts.Tokenizer)
+		})
+		if err != nil {
+			s.Close()
+			return nil, fmt.Errorf("set search tokenizer of %s: %w", path, err)
+		}
+	}
+	if err := s.prepare(); err != nil {
+		s.Close()
+		return nil, fmt.Errorf("open database %s: %w", path, err)
+	}
+	return s, nil
+}
+
+// open connects to the database at path with the DSN parameters in q and,
+// if the store is encrypted, unlocks it with passphrase.
+func open(path string, q url.Values, passphrase string) (*SQLiteStore, error) {
+	db, err := sql.Open("sqlite", path+"?"+q.Encode())
+	if err != nil {
+		return nil, fmt.Errorf("open database %s: %w", path, err)
+	}
+	// sql.Open is lazy; connect now so bad paths and pragmas fail here
+	if err := db.Ping(); err != nil {
+		db.Close()
+		return nil, fmt.Errorf("open database %s: %w", path, err)
+	}
+
+	s := &SQLiteStore{db: db}
+	if err := s.unlock(context.Background(), passphrase); err != nil {
 		db.Close()
-		return nil, fmt.Errorf("setting synchronous mode: %w", err)
+		return nil, fmt.Errorf("open database %s: %w", path, err)
+	}
+	return s, nil
+}
+
+// OpenMemory returns an initialised store held entirely in memory. Nothing
+// touches disk and the data is gone once the store is closed, which suits
+// tests and scratch sessions.
+//
+// Each SQLite connection to ":memory:" sees its own private database, so the
+// pool is limited to a single connection that is never recycled. Concurrent
+// callers queue for it instead of waiting on a lock.
+func OpenMemory() (*SQLiteStore, error) {
+	db, err := sql.Open("sqlite", ":memory:")
+	if err != nil {
+		return nil, fmt.Errorf("open memory database: %w", err)
 	}
+	db.SetMaxOpenConns(1)
+	db.SetMaxIdleConns(1)
+	db.SetConnMaxLifetime(0)
+	db.SetConnMaxIdleTime(0)
 
-	return &SQLiteStore{db: db}, nil
+	s := &SQLiteStore{db: db}
+	if err := s.Init(); err != nil {
+		db.Close()
+		return nil, fmt.Errorf("init memory database: %w", err)
+	}
+	return s, nil
 }
 
 // Init creates tables and indexes if they don't exist. Safe to call multiple
 // times; uses IF NOT EXISTS to avoid errors on existing databases.
 func (s *SQLiteStore) Init() error {
-	return execSchema(s.db)
+	if err := execSchema(s.db); err != nil {
+		return err
+	}
+	return s.prepare()
 }
 
-// Close releases the database connection. Call before program exit to ensure
-// all pending writes are flushed.
+// Close releases the prepared statements and the database connection. Call
+// before program exit to ensure all pending writes are flushed.
 func (s *SQLiteStore) Close() error {
-	return s.db.Close()
+	return errors.Join(s.stmts.close(), s.db.Close())
 }
 
 // DB exposes the underlying connection for extensions that need custom tables.
@@ -109,7 +246,7 @@ func scanDoc(sc scanner) (Document, error) {
 	var msg sql.NullString
 	var del sql.NullInt64
 
-	err := sc.Scan(&d.ID, &d.Key, &d.Path, &d.Content, &d.Version, &d.Author, &msg, &d.CreatedAt, &del)
+	err := sc.Scan(&d.ID, &d.Key, &d.Path, &d.Content, &d.Version, &d.Author, &msg, &d.CreatedAt, &del, &d.ContentType)
 	if err != nil {
 		return d, err
 	}
@@ -132,6 +269,9 @@ func (s *SQLiteStore) scanDocument(row *sql.Row) (*Document, error) {
 	if err != nil {
 		return nil, fmt.Errorf("scan document: %w", err)
 	}
+	if d.Content, err = s.unsealContent(d.Content); err != nil {
+		return nil, fmt.Errorf("%s v%d: %w", d.Path, d.Version, err)
+	}
 	return &d, nil
 }
 
@@ -143,6 +283,9 @@ func (s *SQLiteStore) scanDocuments(rows *sql.Rows) ([]Document, error) {
 		if err != nil {
 			return nil, fmt.Errorf("scan document: %w", err)
 		}
+		if d.Content, err = s.unsealContent(d.Content); err != nil {
+			return nil, fmt.Errorf("%s v%d: %w", d.Path, d.Version, err)
+		}
 		docs = append(docs, d)
 	}
 	return docs, rows.Err()
@@ -161,6 +304,10 @@ func (s *SQLiteStore) scanDocuments(rows *sql.Rows) ([]Document, error) {
 //
 // Context cancellation will abort the transaction at the next database call.
 //
+// If the transaction fails because another connection holds the database
+// lock, it is retried a bounded number of times with backoff, so fn may run
+// more than once and must not have side effects outside the transaction.
+//
 // Callers focus on business logic; Tx handles the ceremony:
 //
 //	err := s.Tx(ctx, func(tx *sql.Tx) error {
@@ -183,6 +330,21 @@ func (s *SQLiteStore) scanDocuments(rows *sql.Rows) ([]Document, error) {
 //	})
 //	return count, err
 func (s *SQLiteStore) Tx(ctx context.Context, fn func(tx *sql.Tx) error) error {
+	var err error
+	for attempt := 1; ; attempt++ {
+		if err = s.tx(ctx, fn); !isBusy(err) || attempt == busyAttempts {
+			return err
+		}
+		select {
+		case <-ctx.Done():
+			return err
+		case <-time.After(time.Duration(attempt) * busyBackoff):
+		}
+	}
+}
+
+// tx runs fn in a single transaction attempt.
+func (s *SQLiteStore) tx(ctx context.Context, fn func(tx *sql.Tx) error) error {
 	tx, err := s.db.BeginTx(ctx, nil)
 	if err != nil {
 		return fmt.Errorf("begin transaction: %w", err)
@@ -198,6 +360,29 @@ func (s *SQLiteStore) Tx(ctx context.Context, fn func(tx *sql.Tx) error) error {
 	return nil
 }
 
+// Retry policy for transactions that fail with SQLITE_BUSY. The busy timeout
+// already waits for most locks; these attempts cover the cases it cannot,
+// such as the timeout expiring under sustained contention from another
+// process.
+const (
+	busyAttempts = 3
+	busyBackoff  = 100 * time.Millisecond
+)
+
+// isBusy reports whether err is SQLite refusing a lock held by another
+// connection (SQLITE_BUSY or SQLITE_LOCKED, including extended codes).
+func isBusy(err error) bool {
+	var se *sqlite.Error
+	if !errors.As(err, &se) {
+		return false
+	}
+	switch se.Code() & 0xff {
+	case sqlite3.SQLITE_BUSY, sqlite3.SQLITE_LOCKED:
+		return true
+	}
+	return false
+}
+
 // genID creates a unique 8-character identifier using crypto/rand for security.
 // Used for document keys, tag IDs, and link IDs to enable direct lookups.
 func genID() (string, error) {
@@ -207,3 +392,37 @@ func genID() (string, error) {
 	}
 	return strings.ToLower(base32.StdEncoding.EncodeToString(b)), nil
 }
+
+// idAttempts bounds how many identifiers insertWithID tries. With 40 bits of
+// randomness a single collision is already rare; several in a row means
+// something other than chance is wrong.
+const idAttempts = 5
+
+// execer is satisfied by both *sql.DB and *sql.Tx.
+type execer interface {
+	ExecContext(ctx context.Context, query string, args ...any) (sql.Result, error)
+}
+
+// insertWithID runs an INSERT whose first parameter is a generated
+// identifier, regenerating it when it collides with an existing row.
+// column names the unique column as SQLite reports it (e.g. "documents.key")
+// so unrelated constraint failures are returned rather than retried.
+//
+// Inside a transaction the failed INSERT only aborts its own statement, so
+// retrying does not disturb earlier work in the same transaction.
+func insertWithID(ctx context.Context, ex execer, column, query string, args ...any) (string, error) {
+	for range idAttempts {
+		id, err := genID()
+		if err != nil {
+			return "", err
+		}
+		_, err = ex.ExecContext(ctx, query, append([]any{id}, args...)...)
+		if err == nil {
+			return id, nil
+		}
+		if !strings.Contains(err.Error(), "UNIQUE constraint failed: "+column) {
+			return "", err
+		}
+	}
+	return "", fmt.Errorf("generate unique %s: %d attempts collided", column, idAttempts)
+}
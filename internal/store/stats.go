@@ -13,6 +13,7 @@ package store
 import (
 	"context"
 	"database/sql"
+	"fmt"
 	"strings"
 	"time"
 )
@@ -50,7 +51,25 @@ func (s *SQLiteStore) ListDeletedPaths(ctx context.Context, prefix string) ([]st
 // queries for dashboards and admin tools that need document info without
 // loading full content.
 func (s *SQLiteStore) ListMeta(ctx context.Context, prefix string, includeDeleted bool) ([]DocumentMeta, error) {
-	q := `SELECT d.key, d.path, d.version, d.author, d.message, d.created_at, d.deleted_at, length(d.content)
+	return s.listMeta(ctx, prefix, includeDeleted, false, time.Time{}, 0)
+}
+
+// Recent returns metadata for the active documents under prefix whose
+// latest version was written after since (zero = any time), most recently
+// changed first, at most limit of them (0 = all). An assistant picking up a
+// store uses it to see what has been happening.
+func (s *SQLiteStore) Recent(ctx context.Context, prefix string, since time.Time, limit int) ([]DocumentMeta, error) {
+	if limit < 0 {
+		return nil, fmt.Errorf("limit must be >= 0, got %d", limit)
+	}
+	return s.listMeta(ctx, prefix, false, true, since, limit)
+}
+
+// listMeta backs ListMeta and Recent. Documents are in path order, or
+// newest first when recent is set; a non-zero since keeps only those
+// written after it.
+func (s *SQLiteStore) listMeta(ctx context.Context, prefix string, includeDeleted, recent bool, since time.Time, limit int) ([]DocumentMeta, error) {
+	q := `SELECT d.key, d.path, d.version, d.author, d.message, d.created_at, d.deleted_at, ` + s.sizeExpr("d.content") + `
 		FROM documents d
 		INNER JOIN (
 			SELECT path, MAX(version) as max_version FROM documents`
@@ -74,11 +93,27 @@ func (s *SQLiteStore) ListMeta(ctx context.Context, prefix string, includeDelete
 	q += ` GROUP BY path
 		) latest ON d.path = latest.path AND d.version = latest.max_version`
 
+	var where []string
 	if !includeDeleted {
-		q += ` WHERE d.deleted_at IS NULL`
+		where = append(where, `d.deleted_at IS NULL`)
+	}
+	if !since.IsZero() {
+		where = append(where, `d.created_at > ?`)
+		args = append(args, since.Unix())
+	}
+	if len(where) > 0 {
+		q += ` WHERE ` + strings.Join(where, ` AND `)
 	}
 
-	q += ` ORDER BY d.path`
+	if recent {
+		q += ` ORDER BY d.created_at DESC, d.path`
+	} else {
+		q += ` ORDER BY d.path`
+	}
+	if limit > 0 {
+		q += ` LIMIT ?`
+		args = append(args, limit)
+	}
 
 	rows, err := s.db.QueryContext(ctx, q, args...)
 	if err != nil {
@@ -237,3 +272,52 @@ func (s *SQLiteStore) Stats(ctx context.Context) (*Stats, error) {
 
 	return &st, nil
 }
+
+// AuditLog returns every version written in [since, until) under prefix,
+// oldest first, across all documents. A zero since or until leaves that end
+// open. Unlike History it spans documents, and unlike the audit event log it
+// is read from the versions themselves, so it cannot miss a write. Deleted
+// versions are included and flagged.
+func (s *SQLiteStore) AuditLog(ctx context.Context, since, until time.Time, prefix string) ([]AuditEntry, error) {
+	q := `SELECT key, path, version, author, message, created_at, deleted_at IS NOT NULL, ` + s.sizeExpr("content") + `
+		FROM documents`
+
+	var args []any
+	var conditions []string
+
+	if !since.IsZero() {
+		conditions = append(conditions, `created_at >= ?`)
+		args = append(args, since.Unix())
+	}
+	if !until.IsZero() {
+		conditions = append(conditions, `created_at < ?`)
+		args = append(args, until.Unix())
+	}
+	if prefix != "" {
+		conditions = append(conditions, `path LIKE ?`)
+		args = append(args, prefix+"%")
+	}
+
+	if len(conditions) > 0 {
+		q += ` WHERE ` + strings.Join(conditions, ` AND `)
+	}
+	q += ` ORDER BY created_at, id`
+
+	rows, err := s.db.QueryContext(ctx, q, args...)
+	if err != nil {
+		return nil, err
+	}
+	defer rows.Close()
+
+	var entries []AuditEntry
+	for rows.Next() {
+		var e AuditEntry
+		var msg sql.NullString
+		if err := rows.Scan(&e.Key, &e.Path, &e.Version, &e.Author, &msg, &e.CreatedAt, &e.Deleted, &e.Size); err != nil {
+			return nil, err
+		}
+		e.Message = msg.String
+		entries = append(entries, e)
+	}
+	return entries, rows.Err()
+}
@@ -0,0 +1,94 @@
+// stmt.go caches prepared statements for the store's hottest queries.
+//
+// Separated from read.go because statement lifetime is a connection
+// concern: statements are prepared when the store opens and closed with it,
+// while the queries that use them stay with the other reads.
+//
+// Design: Only fixed queries that run once per document in batch operations
+// (export, import, mv checks, path/key resolution) are cached. Queries
+// assembled from optional conditions, such as List, vary per call and are
+// still built and run directly. A store opened before its schema exists (a
+// fresh file ahead of Init) has nothing to prepare against; its statements
+// are prepared by Init, and until then queries run unprepared.
+
+package store
+
+import (
+	"context"
+	"database/sql"
+	"errors"
+	"fmt"
+)
+
+// Cached queries. Each has a matching field in stmts.
+const (
+	latestQuery = `SELECT id, key, path, content, version, author, message, created_at, deleted_at, content_type
+		FROM documents WHERE path = ? AND deleted_at IS NULL ORDER BY version DESC LIMIT 1`
+	latestAnyQuery = `SELECT id, key, path, content, version, author, message, created_at, deleted_at, content_type
+		FROM documents WHERE path = ? ORDER BY version DESC LIMIT 1`
+	byKeyQuery = `SELECT id, key, path, content, version, author, message, created_at, deleted_at, content_type
+		FROM documents WHERE key = ?`
+	existsQuery = `SELECT 1 FROM documents WHERE path = ? AND deleted_at IS NULL LIMIT 1`
+)
+
+// stmts holds the prepared statements. A nil field means not prepared.
+type stmts struct {
+	latest    *sql.Stmt // latestQuery
+	latestAny *sql.Stmt // latestAnyQuery, including deleted versions
+	byKey     *sql.Stmt // byKeyQuery
+	exists    *sql.Stmt // existsQuery
+}
+
+// prepare prepares the cached statements, replacing any already prepared.
+// If the schema does not exist yet it leaves them unprepared.
+func (s *SQLiteStore) prepare() error {
+	var n int
+	err := s.db.QueryRow(`SELECT COUNT(*) FROM sqlite_master WHERE type = 'table' AND name = 'documents'`).Scan(&n)
+	if err != nil {
+		return fmt.Errorf("inspect schema: %w", err)
+	}
+	if n == 0 {
+		return nil
+	}
+
+	var st stmts
+	for _, p := range []struct {
+		dst   **sql.Stmt
+		query string
+	}{
+		{&st.latest, latestQuery},
+		{&st.latestAny, latestAnyQuery},
+		{&st.byKey, byKeyQuery},
+		{&st.exists, existsQuery},
+	} {
+		if *p.dst, err = s.db.Prepare(p.query); err != nil {
+			_ = st.close()
+			return fmt.Errorf("prepare statement: %w", err)
+		}
+	}
+	if err := s.stmts.close(); err != nil {
+		return err
+	}
+	s.stmts = st
+	return nil
+}
+
+// close closes every prepared statement.
+func (st *stmts) close() error {
+	var errs []error
+	for _, stmt := range []*sql.Stmt{st.latest, st.latestAny, st.byKey, st.exists} {
+		if stmt != nil {
+			errs = append(errs, stmt.Close())
+		}
+	}
+	*st = stmts{}
+	return errors.Join(errs...)
+}
+
+// queryRow runs query through stmt if it is prepared, otherwise directly.
+func (s *SQLiteStore) queryRow(ctx context.Context, stmt *sql.Stmt, query string, args ...any) *sql.Row {
+	if stmt != nil {
+		return stmt.QueryRowContext(ctx, args...)
+	}
+	return s.db.QueryRowContext(ctx, query, args...)
+}
@@ -20,6 +20,8 @@ type Document struct {
 	Message   string // Commit message for this version
 	CreatedAt int64  // Unix timestamp of creation
 	DeletedAt *int64 // Unix timestamp of deletion, nil if not deleted
+
+	ContentType string // MIME type of Content, "text/markdown" by default
 }
 
 // DocumentMeta contains document metadata without content.
@@ -36,6 +38,46 @@ type DocumentMeta struct {
 	Size      int64  // Content length in bytes
 }
 
+// AuditEntry is one version in the change ledger returned by AuditLog:
+// who wrote which version of which document, when, and how large it was.
+type AuditEntry struct {
+	Key       string // Unique 8-char identifier of the version
+	Path      string // Document path
+	Version   int    // Version number
+	Author    string // Author of the version
+	Message   string // Version message
+	CreatedAt int64  // Unix timestamp the version was written
+	Size      int64  // Content length in bytes
+	Deleted   bool   // Version has since been soft-deleted
+}
+
+// AuditEntryJSON is the API-friendly representation of an AuditEntry with
+// an RFC3339 timestamp.
+type AuditEntryJSON struct {
+	CreatedAt string `json:"created_at"`
+	Path      string `json:"path"`
+	Version   int    `json:"version"`
+	Key       string `json:"key"`
+	Author    string `json:"author"`
+	Message   string `json:"message"`
+	Size      int64  `json:"size"`
+	Deleted   bool   `json:"deleted,omitempty"`
+}
+
+// ToJSON converts an AuditEntry to its API representation.
+func (e *AuditEntry) ToJSON() AuditEntryJSON {
+	return AuditEntryJSON{
+		CreatedAt: time.Unix(e.CreatedAt, 0).UTC().Format(time.RFC3339),
+		Path:      e.Path,
+		Version:   e.Version,
+		Key:       e.Key,
+		Author:    e.Author,
+		Message:   e.Message,
+		Size:      e.Size,
+		Deleted:   e.Deleted,
+	}
+}
+
 // Link represents a connection between two documents, enabling relationship
 // tracking and graph-based navigation. Tags allow categorising link types.
 type Link struct {
@@ -45,6 +87,8 @@ type Link struct {
 	ToPath     string // Target document path
 	ToSource   string // Target table
 	Tag        string // Optional link type (empty string for untagged)
+	Note       string // Optional free-text annotation
+	Weight     int    // Display order; heavier links are listed first
 	CreatedAt  int64  // Unix timestamp of creation
 	DeletedAt  *int64 // Unix timestamp of deletion, nil if not deleted
 }
@@ -58,6 +102,8 @@ type LinkJSON struct {
 	ToPath     string `json:"to_path"`
 	ToSource   string `json:"to_source,omitempty"`
 	Tag        string `json:"tag,omitempty"`
+	Note       string `json:"note,omitempty"`
+	Weight     int    `json:"weight,omitempty"`
 	CreatedAt  string `json:"created_at"`
 }
 
@@ -70,6 +116,8 @@ func (l *Link) ToJSON() LinkJSON {
 		ToPath:     l.ToPath,
 		ToSource:   l.ToSource,
 		Tag:        l.Tag,
+		Note:       l.Note,
+		Weight:     l.Weight,
 		CreatedAt:  time.Unix(l.CreatedAt, 0).UTC().Format(time.RFC3339),
 	}
 }
@@ -91,11 +139,26 @@ func (o TagOptions) WithSource(s string) TagOptions {
 	return o
 }
 
+// Link directions for ListLinks, relative to the document being queried.
+// Links are stored from one document to another; "out" links start at the
+// document and "in" links end at it.
+const (
+	DirectionBoth = "both"
+	DirectionOut  = "out"
+	DirectionIn   = "in"
+)
+
+// Directions lists the valid link directions.
+var Directions = []string{DirectionBoth, DirectionOut, DirectionIn}
+
 // LinkOptions configures a link operation.
 type LinkOptions struct {
-	FromSource string // Source table for "from" endpoint
-	ToSource   string // Source table for "to" endpoint
-	MaxPath    int    // Max path length for validation
+	FromSource string  // Source table for "from" endpoint
+	ToSource   string  // Source table for "to" endpoint
+	MaxPath    int     // Max path length for validation
+	Direction  string  // ListLinks direction (empty = DirectionBoth)
+	Note       *string // Link: free-text annotation (nil = keep, or none for a new link)
+	Weight     *int    // Link: display order, heavier first (nil = keep, or 0)
 }
 
 // NewLinkOptions returns LinkOptions with sensible defaults.
@@ -118,30 +181,52 @@ func (o LinkOptions) WithToSource(s string) LinkOptions {
 	return o
 }
 
+// WithNote sets the annotation Link stores on the link. Without it,
+// linking again keeps the existing note.
+func (o LinkOptions) WithNote(n string) LinkOptions {
+	o.Note = &n
+	return o
+}
+
+// WithWeight sets the weight Link stores on the link. Without it, linking
+// again keeps the existing weight.
+func (o LinkOptions) WithWeight(w int) LinkOptions {
+	o.Weight = &w
+	return o
+}
+
+// WithDirection sets which links ListLinks returns.
+func (o LinkOptions) WithDirection(d string) LinkOptions {
+	o.Direction = d
+	return o
+}
+
 // DocJSON is the API-friendly representation of a Document. It uses RFC3339
 // timestamps and allows optional content omission for bandwidth efficiency.
 type DocJSON struct {
-	Key       string `json:"key"`
-	Path      string `json:"path"`
-	Content   string `json:"content,omitempty"`
-	Version   int    `json:"version"`
-	Author    string `json:"author"`
-	Message   string `json:"message,omitempty"`
-	CreatedAt string `json:"created_at"`
-	Deleted   bool   `json:"deleted,omitempty"`
+	Key         string `json:"key"`
+	Path        string `json:"path"`
+	Content     string `json:"content,omitempty"`
+	ContentType string `json:"content_type"`
+	Version     int    `json:"version"`
+	Author      string `json:"author"`
+	Message     string `json:"message,omitempty"`
+	CreatedAt   string `json:"created_at"`
+	Deleted     bool   `json:"deleted,omitempty"`
 }
 
 // ToJSON converts a Document to its API representation. The content parameter
 // controls whether to include document content, allowing efficient listings.
 func (d *Document) ToJSON(content bool) DocJSON {
 	j := DocJSON{
-		Key:       d.Key,
-		Path:      d.Path,
-		Version:   d.Version,
-		Author:    d.Author,
-		Message:   d.Message,
-		CreatedAt: time.Unix(d.CreatedAt, 0).UTC().Format(time.RFC3339),
-		Deleted:   d.DeletedAt != nil,
+		Key:         d.Key,
+		Path:        d.Path,
+		ContentType: d.ContentType,
+		Version:     d.Version,
+		Author:      d.Author,
+		Message:     d.Message,
+		CreatedAt:   time.Unix(d.CreatedAt, 0).UTC().Format(time.RFC3339),
+		Deleted:     d.DeletedAt != nil,
 	}
 	if content {
 		j.Content = d.Content
@@ -161,6 +246,10 @@ type WriteOptions struct {
 	Message    string
 	MaxPath    int   // 0 means no limit (not recommended for writes)
 	MaxContent int64 // 0 means no limit (not recommended for writes)
+
+	// ContentType records the MIME type of the content. Empty keeps the
+	// type of the previous version, or text/markdown for a new document.
+	ContentType string
 }
 
 // DeleteOptions configures a delete operation.
@@ -183,6 +272,12 @@ type MoveOptions struct {
 	MaxPath int
 }
 
+// MoveOp is one rename in a MoveMany batch.
+type MoveOp struct {
+	From string // Current document path
+	To   string // New document path
+}
+
 // CopyOptions configures a copy operation.
 type CopyOptions struct {
 	MaxPath int
@@ -3,36 +3,28 @@ package store_test
 import (
 	"context"
 	"database/sql"
-	"os"
+	"fmt"
 	"path/filepath"
+	"strings"
+	"sync"
 	"testing"
 	"time"
 
 	"github.com/jpl-au/llmd/internal/store"
+	"github.com/jpl-au/llmd/internal/validate"
 	"github.com/stretchr/testify/assert"
 	"github.com/stretchr/testify/require"
 )
 
-// setupStore creates a temporary SQLite store for testing.
+// setupStore creates an in-memory SQLite store for testing.
 // Returns the store and a cleanup function.
-func setupStore(t *testing.T) (*store.SQLiteStore, func()) {
+func setupStore(t testing.TB) (*store.SQLiteStore, func()) {
 	t.Helper()
 
-	tmpDir, err := os.MkdirTemp("", "llmd-store-test-*")
+	s, err := store.OpenMemory()
 	require.NoError(t, err)
 
-	dbPath := filepath.Join(tmpDir, "test.db")
-	s, err := store.Open(dbPath)
-	require.NoError(t, err)
-
-	require.NoError(t, s.Init())
-
-	cleanup := func() {
-		s.Close()
-		os.RemoveAll(tmpDir)
-	}
-
-	return s, cleanup
+	return s, func() { s.Close() }
 }
 
 // writeOpts returns WriteOptions with test defaults.
@@ -99,6 +91,90 @@ func TestStore_VersionIncrement(t *testing.T) {
 	assert.Equal(t, "bob", v2.Author)
 }
 
+func TestStore_VersionAsOf(t *testing.T) {
+	s, cleanup := setupStore(t)
+	defer cleanup()
+	ctx := context.Background()
+
+	path := "docs/evolving"
+	require.NoError(t, s.Write(ctx, path, "v1", writeOpts("alice", "")))
+	require.NoError(t, s.Write(ctx, path, "v2", writeOpts("alice", "")))
+	require.NoError(t, s.Write(ctx, path, "v3", writeOpts("alice", "")))
+
+	// Writes land in the same second; spread them out so times differ
+	require.NoError(t, s.Tx(ctx, func(tx *sql.Tx) error {
+		_, err := tx.ExecContext(ctx, `UPDATE documents SET created_at = version * 100, deleted_at = 400 WHERE path = ?`, path)
+		return err
+	}))
+
+	tests := []struct {
+		at      int64
+		want    int
+		wantErr bool
+	}{
+		{at: 50, wantErr: true},   // before creation
+		{at: 100, want: 1},        // inclusive of the creation time
+		{at: 250, want: 2},        // between versions
+		{at: 399, want: 3},        // deletion not yet happened
+		{at: 400, wantErr: true},  // deleted by then
+		{at: 1000, wantErr: true}, // still deleted
+	}
+	for _, tt := range tests {
+		doc, err := s.VersionAsOf(ctx, path, time.Unix(tt.at, 0))
+		if tt.wantErr {
+			assert.ErrorIs(t, err, store.ErrNotFound, "at %d", tt.at)
+			continue
+		}
+		require.NoError(t, err, "at %d", tt.at)
+		assert.Equal(t, tt.want, doc.Version, "at %d", tt.at)
+	}
+}
+
+func TestStore_ListAsOf(t *testing.T) {
+	s, cleanup := setupStore(t)
+	defer cleanup()
+	ctx := context.Background()
+
+	require.NoError(t, s.Write(ctx, "docs/a", "a1", writeOpts("alice", "")))
+	require.NoError(t, s.Write(ctx, "docs/a", "a2", writeOpts("alice", "")))
+	require.NoError(t, s.Write(ctx, "docs/b", "b1", writeOpts("alice", "")))
+	require.NoError(t, s.Write(ctx, "notes/c", "c1", writeOpts("alice", "")))
+
+	// a: v1 at 100, v2 at 300. b: created at 200, deleted at 400. c: at 100
+	require.NoError(t, s.Tx(ctx, func(tx *sql.Tx) error {
+		if _, err := tx.ExecContext(ctx, `UPDATE documents SET created_at = version * 200 - 100 WHERE path = 'docs/a'`); err != nil {
+			return err
+		}
+		if _, err := tx.ExecContext(ctx, `UPDATE documents SET created_at = 200, deleted_at = 400 WHERE path = 'docs/b'`); err != nil {
+			return err
+		}
+		_, err := tx.ExecContext(ctx, `UPDATE documents SET created_at = 100 WHERE path = 'notes/c'`)
+		return err
+	}))
+
+	tests := []struct {
+		at     int64
+		prefix string
+		want   []string // path@content
+	}{
+		{at: 50, want: nil},
+		{at: 150, want: []string{"docs/a@a1", "notes/c@c1"}},
+		{at: 250, want: []string{"docs/a@a1", "docs/b@b1", "notes/c@c1"}},
+		{at: 350, prefix: "docs/", want: []string{"docs/a@a2", "docs/b@b1"}},
+		{at: 450, prefix: "docs/", want: []string{"docs/a@a2"}},
+	}
+	for _, tt := range tests {
+		docs, err := s.ListAsOf(ctx, tt.prefix, time.Unix(tt.at, 0))
+		require.NoError(t, err, "at %d", tt.at)
+		var got []string
+		for _, d := range docs {
+			got = append(got, d.Path+"@"+d.Content)
+			assert.Nil(t, d.DeletedAt, "at %d: %s", tt.at, d.Path)
+		}
+		assert.Equal(t, tt.want, got, "at %d", tt.at)
+	}
+}
+
 func TestStore_ByKey(t *testing.T) {
 	s, cleanup := setupStore(t)
 	defer cleanup()
@@ -163,6 +239,47 @@ func TestStore_List(t *testing.T) {
 	assert.Len(t, notes, 1)
 }
 
+func TestStore_ListContaining(t *testing.T) {
+	s, cleanup := setupStore(t)
+	defer cleanup()
+	ctx := context.Background()
+
+	require.NoError(t, s.Write(ctx, "docs/a", "TODO: first", writeOpts("alice", "")))
+	require.NoError(t, s.Write(ctx, "docs/b", "todo: second", writeOpts("alice", "")))
+	require.NoError(t, s.Write(ctx, "docs/c", "100% done", writeOpts("alice", "")))
+	require.NoError(t, s.Write(ctx, "notes/d", "TODO elsewhere", writeOpts("alice", "")))
+	require.NoError(t, s.Write(ctx, "docs/e", "TODO: deleted", writeOpts("alice", "")))
+	require.NoError(t, s.Delete(ctx, "docs/e", store.DeleteOptions{}))
+
+	paths := func(docs []store.Document) []string {
+		var ps []string
+		for _, d := range docs {
+			ps = append(ps, d.Path)
+		}
+		return ps
+	}
+
+	docs, err := s.ListContaining(ctx, "docs/", "TODO", false, false, false)
+	require.NoError(t, err)
+	assert.Equal(t, []string{"docs/a"}, paths(docs))
+
+	docs, err = s.ListContaining(ctx, "docs/", "TODO", true, false, false)
+	require.NoError(t, err)
+	assert.Equal(t, []string{"docs/a", "docs/b"}, paths(docs))
+
+	docs, err = s.ListContaining(ctx, "docs/", "TODO", false, true, false)
+	require.NoError(t, err)
+	assert.Equal(t, []string{"docs/a", "docs/e"}, paths(docs))
+
+	// LIKE wildcards in the text match literally
+	docs, err = s.ListContaining(ctx, "", "0%", true, false, false)
+	require.NoError(t, err)
+	assert.Equal(t, []string{"docs/c"}, paths(docs))
+	docs, err = s.ListContaining(ctx, "", "_", true, false, false)
+	require.NoError(t, err)
+	assert.Empty(t, docs)
+}
+
 func TestStore_ListPaths(t *testing.T) {
 	s, cleanup := setupStore(t)
 	defer cleanup()
@@ -202,6 +319,141 @@ func TestStore_History(t *testing.T) {
 	assert.Len(t, limited, 2)
 }
 
+func TestStore_ContentType(t *testing.T) {
+	s, cleanup := setupStore(t)
+	defer cleanup()
+	ctx := context.Background()
+
+	// New documents default to markdown
+	require.NoError(t, s.Write(ctx, "docs/readme", "# Hi", writeOpts("alice", "")))
+	doc, err := s.Latest(ctx, "docs/readme", false)
+	require.NoError(t, err)
+	assert.Equal(t, "text/markdown", doc.ContentType)
+
+	// An explicit type is recorded and kept by later writes that omit it
+	opts := writeOpts("alice", "")
+	opts.ContentType = "application/json"
+	require.NoError(t, s.Write(ctx, "config/ci", `{"a": 1}`, opts))
+	require.NoError(t, s.Write(ctx, "config/ci", `{"a": 2}`, writeOpts("bob", "")))
+	doc, err = s.Latest(ctx, "config/ci", false)
+	require.NoError(t, err)
+	assert.Equal(t, 2, doc.Version)
+	assert.Equal(t, "application/json", doc.ContentType)
+	assert.Equal(t, "application/json", doc.ToJSON(false).ContentType)
+
+	// Copies keep the source's type
+	require.NoError(t, s.Copy(ctx, "config/ci", "config/ci-copy", "alice", store.CopyOptions{}))
+	doc, err = s.Latest(ctx, "config/ci-copy", false)
+	require.NoError(t, err)
+	assert.Equal(t, "application/json", doc.ContentType)
+
+	// Malformed types are rejected
+	opts.ContentType = "json"
+	err = s.Write(ctx, "config/bad", "{}", opts)
+	assert.ErrorIs(t, err, validate.ErrInvalidContentType)
+}
+
+func TestStore_MoveMany(t *testing.T) {
+	s, cleanup := setupStore(t)
+	defer cleanup()
+	ctx := context.Background()
+
+	for _, p := range []string{"docs/a", "docs/b", "archive/b"} {
+		require.NoError(t, s.Write(ctx, p, p, writeOpts("alice", "")))
+	}
+	opts := store.MoveOptions{}
+
+	// The second move collides, so the first is rolled back
+	err := s.MoveMany(ctx, []store.MoveOp{
+		{From: "docs/a", To: "archive/a"},
+		{From: "docs/b", To: "archive/b"},
+	}, opts)
+	require.ErrorIs(t, err, store.ErrAlreadyExists)
+	assert.Contains(t, err.Error(), "docs/b")
+
+	exists, err := s.Exists(ctx, "docs/a")
+	require.NoError(t, err)
+	assert.True(t, exists, "first move should be rolled back")
+	exists, err = s.Exists(ctx, "archive/a")
+	require.NoError(t, err)
+	assert.False(t, exists)
+
+	// Later ops see earlier ones, so a chain through a freed path works
+	require.NoError(t, s.MoveMany(ctx, []store.MoveOp{
+		{From: "docs/a", To: "archive/a"},
+		{From: "docs/b", To: "docs/a"},
+	}, opts))
+	doc, err := s.Latest(ctx, "docs/a", false)
+	require.NoError(t, err)
+	assert.Equal(t, "docs/b", doc.Content)
+}
+
+func TestStore_DeleteMany(t *testing.T) {
+	s, cleanup := setupStore(t)
+	defer cleanup()
+	ctx := context.Background()
+
+	for _, p := range []string{"docs/a", "docs/b"} {
+		require.NoError(t, s.Write(ctx, p, p, writeOpts("alice", "")))
+	}
+	opts := store.DeleteOptions{}
+
+	// The second path is missing, so the first delete is rolled back
+	err := s.DeleteMany(ctx, []string{"docs/a", "docs/missing"}, opts)
+	require.ErrorIs(t, err, store.ErrNotFound)
+	assert.Contains(t, err.Error(), "docs/missing")
+
+	exists, err := s.Exists(ctx, "docs/a")
+	require.NoError(t, err)
+	assert.True(t, exists, "first delete should be rolled back")
+
+	require.NoError(t, s.DeleteMany(ctx, []string{"docs/a", "docs/b"}, opts))
+	for _, p := range []string{"docs/a", "docs/b"} {
+		exists, err := s.Exists(ctx, p)
+		require.NoError(t, err)
+		assert.False(t, exists, p)
+	}
+}
+
+func TestStore_AuditLog(t *testing.T) {
+	s, cleanup := setupStore(t)
+	defer cleanup()
+	ctx := context.Background()
+
+	require.NoError(t, s.Write(ctx, "docs/a", "A1", writeOpts("alice", "first")))
+	require.NoError(t, s.Write(ctx, "notes/x", "X1", writeOpts("bob", "")))
+	require.NoError(t, s.Write(ctx, "docs/a", "A2!", writeOpts("carol", "second")))
+	require.NoError(t, s.Delete(ctx, "notes/x", store.DeleteOptions{}))
+
+	// All versions across documents, oldest first
+	all, err := s.AuditLog(ctx, time.Time{}, time.Time{}, "")
+	require.NoError(t, err)
+	require.Len(t, all, 3)
+	assert.Equal(t, "docs/a", all[0].Path)
+	assert.Equal(t, 1, all[0].Version)
+	assert.Equal(t, "alice", all[0].Author)
+	assert.Equal(t, "first", all[0].Message)
+	assert.Equal(t, int64(2), all[0].Size)
+	assert.Equal(t, "notes/x", all[1].Path)
+	assert.True(t, all[1].Deleted)
+	assert.Equal(t, "carol", all[2].Author)
+	assert.Equal(t, int64(3), all[2].Size)
+
+	// Prefix scope
+	docs, err := s.AuditLog(ctx, time.Time{}, time.Time{}, "docs/")
+	require.NoError(t, err)
+	assert.Len(t, docs, 2)
+
+	// Range is half-open: until excludes, since includes
+	now := time.Now()
+	none, err := s.AuditLog(ctx, time.Time{}, now.Add(-time.Hour), "")
+	require.NoError(t, err)
+	assert.Empty(t, none)
+	recent, err := s.AuditLog(ctx, now.Add(-time.Hour), now.Add(time.Hour), "")
+	require.NoError(t, err)
+	assert.Len(t, recent, 3)
+}
+
 func TestStore_Count(t *testing.T) {
 	s, cleanup := setupStore(t)
 	defer cleanup()
@@ -348,6 +600,41 @@ func TestStore_DeleteVersion(t *testing.T) {
 	assert.Equal(t, 3, latest.Version)
 }
 
+func TestStore_DeleteLatestVersion(t *testing.T) {
+	s, cleanup := setupStore(t)
+	defer cleanup()
+	ctx := context.Background()
+
+	path := "docs/multiversion"
+	require.NoError(t, s.Write(ctx, path, "v1", writeOpts("alice", "")))
+	require.NoError(t, s.Write(ctx, path, "v2", writeOpts("alice", "")))
+	require.NoError(t, s.Write(ctx, path, "v3", writeOpts("alice", "")))
+
+	require.NoError(t, s.DeleteVersion(ctx, path, 3, store.DeleteVersionOptions{}))
+
+	// Latest falls back to the highest remaining version
+	latest, err := s.Latest(ctx, path, false)
+	require.NoError(t, err)
+	assert.Equal(t, 2, latest.Version)
+	assert.Equal(t, "v2", latest.Content)
+
+	docs, err := s.List(ctx, "docs/", false, false)
+	require.NoError(t, err)
+	require.Len(t, docs, 1, "document stays listed")
+	assert.Equal(t, 2, docs[0].Version)
+
+	// The next write numbers from the true maximum so v3 is never reused
+	require.NoError(t, s.Write(ctx, path, "v4", writeOpts("alice", "")))
+	latest, err = s.Latest(ctx, path, false)
+	require.NoError(t, err)
+	assert.Equal(t, 4, latest.Version)
+
+	v3, err := s.Version(ctx, path, 3)
+	require.NoError(t, err)
+	assert.NotNil(t, v3.DeletedAt)
+	assert.NotEqual(t, v3.Key, latest.Key)
+}
+
 func TestStore_ListDeletedOnly(t *testing.T) {
 	s, cleanup := setupStore(t)
 	defer cleanup()
@@ -460,6 +747,80 @@ func TestStore_CopyAlreadyExists(t *testing.T) {
 	assert.ErrorIs(t, err, store.ErrAlreadyExists)
 }
 
+func TestStore_CopyPrefix(t *testing.T) {
+	s, cleanup := setupStore(t)
+	defer cleanup()
+	ctx := context.Background()
+
+	require.NoError(t, s.Write(ctx, "docs/a", "A1", writeOpts("alice", "")))
+	require.NoError(t, s.Write(ctx, "docs/a", "A2", writeOpts("alice", "")))
+	require.NoError(t, s.Write(ctx, "docs/sub/b", "B", writeOpts("alice", "")))
+	require.NoError(t, s.Write(ctx, "docsx/c", "C", writeOpts("alice", "")))
+	require.NoError(t, s.Write(ctx, "archive/sub/b", "old", writeOpts("alice", "")))
+	opts := store.CopyOptions{}
+
+	// One destination exists, so nothing is copied
+	_, err := s.CopyPrefix(ctx, "docs/", "archive/", "bob", opts)
+	require.ErrorIs(t, err, store.ErrAlreadyExists)
+	assert.Contains(t, err.Error(), "archive/sub/b")
+	exists, err := s.Exists(ctx, "archive/a")
+	require.NoError(t, err)
+	assert.False(t, exists, "earlier copy should be rolled back")
+
+	// A prefix without a slash still matches whole segments only
+	copied, err := s.CopyPrefix(ctx, "docs", "snap", "bob", opts)
+	require.NoError(t, err)
+	assert.Equal(t, []string{"snap/a", "snap/sub/b"}, copied)
+
+	doc, err := s.Latest(ctx, "snap/a", false)
+	require.NoError(t, err)
+	assert.Equal(t, "A2", doc.Content)
+	assert.Equal(t, 1, doc.Version)
+	assert.Equal(t, "bob", doc.Author)
+
+	_, err = s.CopyPrefix(ctx, "missing/", "other/", "bob", opts)
+	assert.ErrorIs(t, err, store.ErrNotFound)
+}
+
+func TestStore_Duplicates(t *testing.T) {
+	s, cleanup := setupStore(t)
+	defer cleanup()
+	ctx := context.Background()
+
+	for p, content := range map[string]string{
+		"docs/a":   "same",
+		"docs/b":   "same",
+		"notes/c":  "same",
+		"docs/d":   "other",
+		"docs/e":   "other",
+		"docs/f":   "unique",
+		"docs/old": "before",
+	} {
+		require.NoError(t, s.Write(ctx, p, content, writeOpts("alice", "")))
+	}
+	// Only latest versions of active documents count
+	require.NoError(t, s.Write(ctx, "docs/old", "same", writeOpts("alice", "")))
+	require.NoError(t, s.Write(ctx, "docs/e", "changed", writeOpts("alice", "")))
+	require.NoError(t, s.Delete(ctx, "notes/c", store.DeleteOptions{}))
+
+	groups, err := s.Duplicates(ctx, "")
+	require.NoError(t, err)
+	require.Len(t, groups, 1)
+	assert.Equal(t, []string{"docs/a", "docs/b", "docs/old"}, groups[0].Paths)
+	assert.Len(t, groups[0].Hash, 64)
+
+	// A copy carries its source's hash
+	require.NoError(t, s.Copy(ctx, "docs/f", "notes/f", "bob", store.CopyOptions{}))
+	groups, err = s.Duplicates(ctx, "")
+	require.NoError(t, err)
+	require.Len(t, groups, 2)
+	assert.Equal(t, []string{"docs/f", "notes/f"}, groups[1].Paths)
+
+	groups, err = s.Duplicates(ctx, "notes/")
+	require.NoError(t, err)
+	assert.Empty(t, groups)
+}
+
 // --- Tag Tests ---
 
 func TestStore_Tags(t *testing.T) {
@@ -534,6 +895,89 @@ func TestStore_ListAllTags(t *testing.T) {
 	assert.Len(t, allTags, 2)
 }
 
+func TestStore_TagCounts(t *testing.T) {
+	s, cleanup := setupStore(t)
+	defer cleanup()
+	ctx := context.Background()
+
+	require.NoError(t, s.Write(ctx, "docs/a", "A", writeOpts("alice", "")))
+	require.NoError(t, s.Write(ctx, "docs/b", "B", writeOpts("alice", "")))
+
+	opts := store.NewTagOptions()
+	require.NoError(t, s.Tag(ctx, "docs/a", "tag1", opts))
+	require.NoError(t, s.Tag(ctx, "docs/b", "tag1", opts))
+	require.NoError(t, s.Tag(ctx, "docs/b", "tag2", opts))
+	require.NoError(t, s.Tag(ctx, "docs/a", "gone", opts))
+	require.NoError(t, s.Untag(ctx, "docs/a", "gone", opts))
+
+	counts, err := s.TagCounts(ctx, opts)
+	require.NoError(t, err)
+	assert.Equal(t, map[string]int{"tag1": 2, "tag2": 1}, counts)
+}
+
+func TestStore_TagSets(t *testing.T) {
+	s, cleanup := setupStore(t)
+	defer cleanup()
+	ctx := context.Background()
+
+	require.NoError(t, s.Write(ctx, "docs/a", "A", writeOpts("alice", "")))
+	require.NoError(t, s.Write(ctx, "docs/b", "B", writeOpts("alice", "")))
+
+	opts := store.NewTagOptions()
+	require.NoError(t, s.Tag(ctx, "docs/a", "api", opts))
+	require.NoError(t, s.Tag(ctx, "docs/a", "v1", opts))
+	require.NoError(t, s.Tag(ctx, "docs/a", "old", opts))
+	require.NoError(t, s.Untag(ctx, "docs/a", "old", opts))
+	require.NoError(t, s.Tag(ctx, "docs/b", "v1", opts))
+
+	sets, err := s.TagSets(ctx, "api", opts)
+	require.NoError(t, err)
+	assert.Equal(t, map[string][]string{"docs/a": {"api", "v1"}}, sets)
+}
+
+func TestStore_RenameTag(t *testing.T) {
+	s, cleanup := setupStore(t)
+	defer cleanup()
+	ctx := context.Background()
+
+	for _, p := range []string{"docs/a", "docs/b", "docs/c"} {
+		require.NoError(t, s.Write(ctx, p, "content", writeOpts("alice", "")))
+	}
+
+	opts := store.NewTagOptions()
+	require.NoError(t, s.Tag(ctx, "docs/a", "draf", opts))
+	require.NoError(t, s.Tag(ctx, "docs/b", "draf", opts))
+	require.NoError(t, s.Tag(ctx, "docs/b", "draft", opts))
+	// A removed draft tag on docs/c is restored rather than duplicated
+	require.NoError(t, s.Tag(ctx, "docs/c", "draf", opts))
+	require.NoError(t, s.Tag(ctx, "docs/c", "draft", opts))
+	require.NoError(t, s.Untag(ctx, "docs/c", "draft", opts))
+
+	n, err := s.RenameTag(ctx, "draf", "draft", opts)
+	require.NoError(t, err)
+	assert.Equal(t, int64(3), n)
+
+	paths, err := s.PathsWithTag(ctx, "draft", opts)
+	require.NoError(t, err)
+	assert.Equal(t, []string{"docs/a", "docs/b", "docs/c"}, paths)
+
+	paths, err = s.PathsWithTag(ctx, "draf", opts)
+	require.NoError(t, err)
+	assert.Empty(t, paths)
+
+	tags, err := s.ListTags(ctx, "docs/b", opts)
+	require.NoError(t, err)
+	assert.Equal(t, []string{"draft"}, tags)
+
+	// Renaming a tag nobody has affects nothing
+	n, err = s.RenameTag(ctx, "missing", "other", opts)
+	require.NoError(t, err)
+	assert.Zero(t, n)
+
+	_, err = s.RenameTag(ctx, "draft", "draft", opts)
+	assert.Error(t, err)
+}
+
 // --- Link Tests ---
 
 func TestStore_Links(t *testing.T) {
@@ -564,6 +1008,24 @@ func TestStore_Links(t *testing.T) {
 	require.NoError(t, err)
 	assert.Len(t, byTag, 1)
 
+	// Direction narrows to links starting or ending at the document
+	for _, tt := range []struct {
+		path, direction string
+		want            int
+	}{
+		{"docs/a", store.DirectionOut, 1},
+		{"docs/a", store.DirectionIn, 0},
+		{"docs/b", store.DirectionOut, 0},
+		{"docs/b", store.DirectionIn, 1},
+		{"docs/b", store.DirectionBoth, 1},
+	} {
+		links, err := s.ListLinks(ctx, tt.path, "", opts.WithDirection(tt.direction))
+		require.NoError(t, err)
+		assert.Len(t, links, tt.want, "%s %s", tt.path, tt.direction)
+	}
+	_, err = s.ListLinks(ctx, "docs/a", "", opts.WithDirection("sideways"))
+	assert.ErrorIs(t, err, store.ErrInvalidDirection)
+
 	// Unlink by ID
 	require.NoError(t, s.UnlinkByID(ctx, id))
 
@@ -572,60 +1034,560 @@ func TestStore_Links(t *testing.T) {
 	assert.Len(t, links, 0)
 }
 
-func TestStore_UnlinkByTag(t *testing.T) {
+func TestStore_LinkMetadata(t *testing.T) {
 	s, cleanup := setupStore(t)
 	defer cleanup()
 	ctx := context.Background()
 
-	require.NoError(t, s.Write(ctx, "docs/a", "A", writeOpts("alice", "")))
-	require.NoError(t, s.Write(ctx, "docs/b", "B", writeOpts("alice", "")))
-	require.NoError(t, s.Write(ctx, "docs/c", "C", writeOpts("alice", "")))
-
+	for _, p := range []string{"docs/a", "docs/b", "docs/c", "docs/d"} {
+		require.NoError(t, s.Write(ctx, p, p, writeOpts("alice", "")))
+	}
 	opts := store.NewLinkOptions()
 
-	// Create multiple links with same tag
-	_, err := s.Link(ctx, "docs/a", "docs/b", "depends-on", opts)
+	_, err := s.Link(ctx, "docs/a", "docs/b", "", opts)
 	require.NoError(t, err)
-	_, err = s.Link(ctx, "docs/a", "docs/c", "depends-on", opts)
+	_, err = s.Link(ctx, "docs/a", "docs/c", "", opts.WithNote("supersedes").WithWeight(10))
+	require.NoError(t, err)
+	_, err = s.Link(ctx, "docs/a", "docs/d", "", opts.WithWeight(5))
 	require.NoError(t, err)
 
-	// Unlink all with tag
-	count, err := s.UnlinkByTag(ctx, "depends-on", opts)
+	links, err := s.ListLinks(ctx, "docs/a", "", opts)
 	require.NoError(t, err)
-	assert.Equal(t, int64(2), count)
+	require.Len(t, links, 3)
+	assert.Equal(t, []string{"docs/c", "docs/d", "docs/b"}, []string{links[0].ToPath, links[1].ToPath, links[2].ToPath}, "heaviest first")
+	assert.Equal(t, "supersedes", links[0].Note)
+	assert.Equal(t, 10, links[0].Weight)
 
-	links, err := s.ListLinksByTag(ctx, "depends-on", opts)
+	// Linking again keeps the ID and replaces the metadata
+	id, err := s.Link(ctx, "docs/a", "docs/b", "", opts.WithNote("see also").WithWeight(20))
 	require.NoError(t, err)
-	assert.Len(t, links, 0)
-}
+	assert.Equal(t, links[2].ID, id)
+	links, err = s.ListLinks(ctx, "docs/a", "", opts)
+	require.NoError(t, err)
+	assert.Equal(t, "docs/b", links[0].ToPath)
+	assert.Equal(t, "see also", links[0].Note)
 
-func TestStore_ListOrphanLinkPaths(t *testing.T) {
-	s, cleanup := setupStore(t)
-	defer cleanup()
-	ctx := context.Background()
+	// Re-linking without metadata keeps it, and setting one field leaves
+	// the other alone
+	_, err = s.Link(ctx, "docs/a", "docs/b", "", opts)
+	require.NoError(t, err)
+	_, err = s.Link(ctx, "docs/a", "docs/c", "", opts.WithWeight(1))
+	require.NoError(t, err)
+	links, err = s.ListLinks(ctx, "docs/a", "", opts)
+	require.NoError(t, err)
+	require.Len(t, links, 3)
+	assert.Equal(t, "docs/b", links[0].ToPath)
+	assert.Equal(t, "see also", links[0].Note)
+	assert.Equal(t, 20, links[0].Weight)
+	assert.Equal(t, "docs/c", links[2].ToPath)
+	assert.Equal(t, "supersedes", links[2].Note)
+	assert.Equal(t, 1, links[2].Weight)
+
+	// Soft-deleted links come back with their metadata
+	require.NoError(t, s.UnlinkByID(ctx, links[0].ID))
+	_, err = s.Link(ctx, "docs/a", "docs/b", "", opts)
+	require.NoError(t, err)
+	links, err = s.ListLinks(ctx, "docs/a", "", opts)
+	require.NoError(t, err)
+	assert.Equal(t, "see also", links[0].Note)
+}
 
-	require.NoError(t, s.Write(ctx, "docs/linked", "content", writeOpts("alice", "")))
-	require.NoError(t, s.Write(ctx, "docs/also-linked", "content", writeOpts("alice", "")))
-	require.NoError(t, s.Write(ctx, "docs/orphan", "content", writeOpts("alice", "")))
+func TestOpen_AddsLinkColumns(t *testing.T) {
+	path := filepath.Join(t.TempDir(), "old.db")
 
-	opts := store.NewLinkOptions()
+	// A links table as created before notes and weights existed
+	db, err := sql.Open("sqlite", path)
+	require.NoError(t, err)
+	_, err = db.Exec(`CREATE TABLE links (
+		id TEXT PRIMARY KEY, from_path TEXT NOT NULL, from_source TEXT NOT NULL DEFAULT 'documents',
+		to_path TEXT NOT NULL, to_source TEXT NOT NULL DEFAULT 'documents', tag TEXT NOT NULL DEFAULT '',
+		created_at INTEGER NOT NULL, deleted_at INTEGER)`)
+	require.NoError(t, err)
+	_, err = db.Exec(`INSERT INTO links (id, from_path, to_path, created_at) VALUES ('abcd1234', 'docs/a', 'docs/b', 1)`)
+	require.NoError(t, err)
+	require.NoError(t, db.Close())
 
-	// Create link between two docs
-	_, err := s.Link(ctx, "docs/linked", "docs/also-linked", "", opts)
+	s, err := store.Open(path)
 	require.NoError(t, err)
+	defer s.Close()
 
-	// Find orphans
-	orphans, err := s.ListOrphanLinkPaths(ctx, opts)
+	links, err := s.ListLinks(context.Background(), "docs/a", "", store.NewLinkOptions())
 	require.NoError(t, err)
-	assert.Equal(t, []string{"docs/orphan"}, orphans)
+	require.Len(t, links, 1)
+	assert.Equal(t, "", links[0].Note)
+	assert.Equal(t, 0, links[0].Weight)
 }
 
-// --- Search Tests ---
-
-func TestStore_Search(t *testing.T) {
-	s, cleanup := setupStore(t)
-	defer cleanup()
-	ctx := context.Background()
+func TestOpen_AddsContentType(t *testing.T) {
+	path := filepath.Join(t.TempDir(), "old.db")
+
+	// A documents table as created before content types were recorded
+	db, err := sql.Open("sqlite", path)
+	require.NoError(t, err)
+	_, err = db.Exec(`CREATE TABLE documents (
+		id INTEGER PRIMARY KEY AUTOINCREMENT, key TEXT NOT NULL, path TEXT NOT NULL,
+		content TEXT NOT NULL, version INTEGER NOT NULL DEFAULT 1, author TEXT NOT NULL,
+		message TEXT, created_at INTEGER NOT NULL, deleted_at INTEGER, UNIQUE(path, version))`)
+	require.NoError(t, err)
+	_, err = db.Exec(`INSERT INTO documents (key, path, content, author, created_at) VALUES ('abcd1234', 'docs/a', 'A', 'alice', 1)`)
+	require.NoError(t, err)
+	require.NoError(t, db.Close())
+
+	s, err := store.Open(path)
+	require.NoError(t, err)
+	defer s.Close()
+
+	doc, err := s.Latest(context.Background(), "docs/a", false)
+	require.NoError(t, err)
+	assert.Equal(t, "text/markdown", doc.ContentType)
+}
+
+func TestOpen_RebuildsFTSTokenizer(t *testing.T) {
+	path := filepath.Join(t.TempDir(), "old.db")
+	ctx := context.Background()
+
+	s, err := store.Open(path)
+	require.NoError(t, err)
+	require.NoError(t, s.Init())
+	require.NoError(t, s.Write(ctx, "docs/vi", "Tiếng Việt", writeOpts("alice", "")))
+
+	// The search index as created with the default tokenizer, which leaves
+	// letters carrying more than one diacritic alone, before schema versions
+	// were recorded
+	_, err = s.DB().Exec(`DROP TABLE schema_version`)
+	require.NoError(t, err)
+	_, err = s.DB().Exec(`DROP TABLE documents_fts`)
+	require.NoError(t, err)
+	_, err = s.DB().Exec(`CREATE VIRTUAL TABLE documents_fts USING fts5(path, content, content=documents, content_rowid=id)`)
+	require.NoError(t, err)
+	_, err = s.DB().Exec(`INSERT INTO documents_fts(documents_fts) VALUES('rebuild')`)
+	require.NoError(t, err)
+	results, err := s.Search(ctx, "viet", "", false, false)
+	require.NoError(t, err)
+	require.Empty(t, results)
+	require.NoError(t, s.Close())
+
+	s, err = store.Open(path)
+	require.NoError(t, err)
+	defer s.Close()
+
+	results, err = s.Search(ctx, "viet", "", false, false)
+	require.NoError(t, err)
+	require.Len(t, results, 1)
+	assert.Equal(t, "docs/vi", results[0].Path)
+
+	// Triggers keep working against the recreated table
+	require.NoError(t, s.Write(ctx, "docs/bar", "Crème brûlée", writeOpts("alice", "")))
+	results, err = s.Search(ctx, "creme", "", false, false)
+	require.NoError(t, err)
+	require.Len(t, results, 1)
+}
+
+func TestOpen_PreparesAfterInit(t *testing.T) {
+	path := filepath.Join(t.TempDir(), "new.db")
+	ctx := context.Background()
+
+	// A fresh file has no schema to prepare against until Init
+	s, err := store.Open(path)
+	require.NoError(t, err)
+	require.NoError(t, s.Init())
+	require.NoError(t, s.Write(ctx, "docs/a", "A", writeOpts("alice", "")))
+
+	doc, err := s.Latest(ctx, "docs/a", false)
+	require.NoError(t, err)
+	byKey, err := s.ByKey(ctx, doc.Key)
+	require.NoError(t, err)
+	assert.Equal(t, "docs/a", byKey.Path)
+	ok, err := s.Exists(ctx, "docs/a")
+	require.NoError(t, err)
+	assert.True(t, ok)
+	require.NoError(t, s.Close())
+
+	// Reopened read-only, the statements are prepared at open
+	s, err = store.OpenWithOptions(path, store.OpenOptions{ReadOnly: true})
+	require.NoError(t, err)
+	doc, err = s.Latest(ctx, "docs/a", true)
+	require.NoError(t, err)
+	assert.Equal(t, "A", doc.Content)
+	_, err = s.Latest(ctx, "docs/missing", false)
+	assert.ErrorIs(t, err, store.ErrNotFound)
+	assert.NoError(t, s.Close())
+}
+
+// BenchmarkStore_Latest reads the same document in a tight loop, as export
+// and mv checks do per document.
+func BenchmarkStore_Latest(b *testing.B) {
+	path := filepath.Join(b.TempDir(), "bench.db")
+	ctx := context.Background()
+
+	s, err := store.Open(path)
+	require.NoError(b, err)
+	defer s.Close()
+	require.NoError(b, s.Init())
+	for i := range 100 {
+		require.NoError(b, s.Write(ctx, fmt.Sprintf("docs/%03d", i), "content", writeOpts("bench", "")))
+	}
+
+	for b.Loop() {
+		if _, err := s.Latest(ctx, "docs/050", false); err != nil {
+			b.Fatal(err)
+		}
+	}
+}
+
+func TestStore_Encryption(t *testing.T) {
+	path := filepath.Join(t.TempDir(), "secret.db")
+	ctx := context.Background()
+	const content = "# Plan\nThe launch codes are 0000."
+
+	s, err := store.Open(path)
+	require.NoError(t, err)
+	require.NoError(t, s.Init())
+	require.NoError(t, s.EnableEncryption(ctx, "hunter2"))
+	assert.True(t, s.Encrypted())
+	require.NoError(t, s.Write(ctx, "secret/plan", content, writeOpts("alice", "")))
+	assert.Error(t, s.EnableEncryption(ctx, "again"), "already encrypted")
+
+	var raw, hash string
+	require.NoError(t, s.DB().QueryRow(`SELECT content, content_hash FROM documents`).Scan(&raw, &hash))
+	assert.NotContains(t, raw, "launch")
+	assert.Empty(t, hash, "hashes would reveal equal content")
+
+	doc, err := s.Latest(ctx, "secret/plan", false)
+	require.NoError(t, err)
+	assert.Equal(t, content, doc.Content)
+
+	// Content cannot be filtered in SQL, so every document is a candidate
+	docs, err := s.ListContaining(ctx, "", "nowhere", false, false, false)
+	require.NoError(t, err)
+	require.Len(t, docs, 1)
+	assert.Equal(t, content, docs[0].Content)
+
+	meta, err := s.Meta(ctx, "secret/plan")
+	require.NoError(t, err)
+	assert.Equal(t, int64(len(content)), meta.Size)
+
+	_, err = s.Search(ctx, "launch", "", false, false)
+	assert.ErrorIs(t, err, store.ErrNoSearchIndex)
+	_, err = s.Reindex(ctx)
+	assert.ErrorIs(t, err, store.ErrNoSearchIndex)
+
+	// Without hashes, duplicates are found from the decrypted content
+	require.NoError(t, s.Write(ctx, "secret/copy", content, writeOpts("alice", "")))
+	groups, err := s.Duplicates(ctx, "")
+	require.NoError(t, err)
+	require.Len(t, groups, 1)
+	assert.Equal(t, []string{"secret/copy", "secret/plan"}, groups[0].Paths)
+	require.NoError(t, s.Close())
+
+	_, err = store.Open(path)
+	assert.ErrorIs(t, err, store.ErrEncrypted)
+	_, err = store.OpenWithOptions(path, store.OpenOptions{Passphrase: "wrong"})
+	assert.ErrorIs(t, err, store.ErrPassphrase)
+
+	s, err = store.OpenWithOptions(path, store.OpenOptions{Passphrase: "hunter2", ReadOnly: true})
+	require.NoError(t, err)
+	defer s.Close()
+	doc, err = s.Latest(ctx, "secret/plan", false)
+	require.NoError(t, err)
+	assert.Equal(t, content, doc.Content)
+}
+
+func TestStore_EnableEncryptionRequiresEmptyStore(t *testing.T) {
+	s, cleanup := setupStore(t)
+	defer cleanup()
+	ctx := context.Background()
+
+	require.NoError(t, s.Write(ctx, "doc", "plain", writeOpts("alice", "")))
+	assert.Error(t, s.EnableEncryption(ctx, "hunter2"))
+	assert.False(t, s.Encrypted())
+}
+
+func TestStore_MigrateTo(t *testing.T) {
+	path := filepath.Join(t.TempDir(), "old.db")
+	ctx := context.Background()
+	latest := store.LatestSchemaVersion()
+
+	s, err := store.Open(path)
+	require.NoError(t, err)
+	require.NoError(t, s.Init())
+	v, err := s.SchemaVersion(ctx)
+	require.NoError(t, err)
+	assert.Equal(t, latest, v)
+
+	_, err = s.MigrateTo(ctx, latest+1)
+	assert.Error(t, err)
+	_, err = s.MigrateTo(ctx, latest-1)
+	assert.Error(t, err, "migrations cannot be undone")
+
+	// A store from before versions were recorded
+	_, err = s.DB().Exec(`DROP TABLE schema_version`)
+	require.NoError(t, err)
+	require.NoError(t, s.Close())
+
+	s, err = store.OpenWithOptions(path, store.OpenOptions{NoMigrate: true})
+	require.NoError(t, err)
+	defer s.Close()
+
+	v, err = s.SchemaVersion(ctx)
+	require.NoError(t, err)
+	assert.Equal(t, 0, v)
+	pending, err := s.PendingMigrations(ctx)
+	require.NoError(t, err)
+	assert.Len(t, pending, latest)
+
+	applied, err := s.MigrateTo(ctx, 1)
+	require.NoError(t, err)
+	require.Len(t, applied, 1)
+	assert.Equal(t, 1, applied[0].Version)
+
+	applied, err = s.MigrateTo(ctx, latest)
+	require.NoError(t, err)
+	assert.Len(t, applied, latest-1)
+	v, err = s.SchemaVersion(ctx)
+	require.NoError(t, err)
+	assert.Equal(t, latest, v)
+
+	applied, err = s.MigrateTo(ctx, latest)
+	require.NoError(t, err)
+	assert.Empty(t, applied)
+}
+
+func TestOpen_ReadOnlyNeedsMigration(t *testing.T) {
+	path := filepath.Join(t.TempDir(), "old.db")
+
+	s, err := store.Open(path)
+	require.NoError(t, err)
+	require.NoError(t, s.Init())
+	require.NoError(t, s.Write(context.Background(), "docs/a", "A", writeOpts("alice", "")))
+	// A store from before documents.content_type was added
+	_, err = s.DB().Exec(`DELETE FROM schema_version`)
+	require.NoError(t, err)
+	_, err = s.DB().Exec(`ALTER TABLE documents DROP COLUMN content_type`)
+	require.NoError(t, err)
+	require.NoError(t, s.Close())
+
+	_, err = store.OpenWithOptions(path, store.OpenOptions{ReadOnly: true})
+	require.ErrorIs(t, err, store.ErrMigrationPending)
+
+	// db migrate --dry-run can still report what is pending
+	s, err = store.OpenWithOptions(path, store.OpenOptions{ReadOnly: true, NoMigrate: true})
+	require.NoError(t, err)
+	pending, err := s.PendingMigrations(context.Background())
+	require.NoError(t, err)
+	assert.Len(t, pending, store.LatestSchemaVersion())
+	require.NoError(t, s.Close())
+
+	// Opening writable migrates, after which read-only opens work
+	s, err = store.Open(path)
+	require.NoError(t, err)
+	require.NoError(t, s.Close())
+
+	s, err = store.OpenWithOptions(path, store.OpenOptions{ReadOnly: true})
+	require.NoError(t, err)
+	defer s.Close()
+	doc, err := s.Latest(context.Background(), "docs/a", false)
+	require.NoError(t, err)
+	assert.Equal(t, "A", doc.Content)
+}
+
+func TestOpen_AddsIndexes(t *testing.T) {
+	path := filepath.Join(t.TempDir(), "old.db")
+
+	s, err := store.Open(path)
+	require.NoError(t, err)
+	require.NoError(t, s.Init())
+	// The indexes as created before the covering index was added, at
+	// schema version 2
+	_, err = s.DB().Exec(`DELETE FROM schema_version WHERE version > 2`)
+	require.NoError(t, err)
+	_, err = s.DB().Exec(`DROP INDEX idx_documents_deleted_path_version`)
+	require.NoError(t, err)
+	_, err = s.DB().Exec(`CREATE INDEX idx_documents_deleted ON documents(deleted_at)`)
+	require.NoError(t, err)
+	require.NoError(t, s.Close())
+
+	s, err = store.Open(path)
+	require.NoError(t, err)
+	defer s.Close()
+
+	indexes := map[string]bool{}
+	rows, err := s.DB().Query(`SELECT name FROM sqlite_master WHERE type = 'index' AND tbl_name = 'documents'`)
+	require.NoError(t, err)
+	defer rows.Close()
+	for rows.Next() {
+		var name string
+		require.NoError(t, rows.Scan(&name))
+		indexes[name] = true
+	}
+	assert.True(t, indexes["idx_documents_deleted_path_version"])
+	assert.False(t, indexes["idx_documents_deleted"])
+}
+
+func TestOpen_BackfillsContentHash(t *testing.T) {
+	path := filepath.Join(t.TempDir(), "old.db")
+	ctx := context.Background()
+
+	s, err := store.Open(path)
+	require.NoError(t, err)
+	require.NoError(t, s.Init())
+	require.NoError(t, s.Write(ctx, "docs/a", "same", writeOpts("alice", "")))
+	require.NoError(t, s.Write(ctx, "docs/b", "same", writeOpts("alice", "")))
+	// Versions written before content_hash existed, at schema version 3
+	_, err = s.DB().Exec(`UPDATE documents SET content_hash = ''`)
+	require.NoError(t, err)
+	_, err = s.DB().Exec(`DELETE FROM schema_version WHERE version > 3`)
+	require.NoError(t, err)
+	require.NoError(t, s.Close())
+
+	s, err = store.Open(path)
+	require.NoError(t, err)
+	defer s.Close()
+
+	groups, err := s.Duplicates(ctx, "")
+	require.NoError(t, err)
+	require.Len(t, groups, 1)
+	assert.Equal(t, []string{"docs/a", "docs/b"}, groups[0].Paths)
+}
+
+// TestStore_ListPlan checks that the latest-version subquery behind List is
+// answered from the covering index alone.
+func TestStore_ListPlan(t *testing.T) {
+	s, cleanup := setupStore(t)
+	defer cleanup()
+
+	for _, where := range []string{
+		`deleted_at IS NULL`,
+		`path LIKE 'docs/%' AND deleted_at IS NULL`,
+	} {
+		rows, err := s.DB().Query(`EXPLAIN QUERY PLAN SELECT path, MAX(version) FROM documents WHERE ` + where + ` GROUP BY path`)
+		require.NoError(t, err)
+		var plan []string
+		for rows.Next() {
+			var id, parent, unused int
+			var detail string
+			require.NoError(t, rows.Scan(&id, &parent, &unused, &detail))
+			plan = append(plan, detail)
+		}
+		require.NoError(t, rows.Close())
+
+		joined := strings.Join(plan, "\n")
+		assert.Contains(t, joined, "COVERING INDEX idx_documents_deleted_path_version", where)
+		assert.NotContains(t, joined, "TEMP B-TREE", where)
+	}
+}
+
+// BenchmarkStore_List lists a store of 2,000 documents of 2KB with five
+// versions each, as ls -R does.
+func BenchmarkStore_List(b *testing.B) {
+	s, cleanup := setupStore(b)
+	defer cleanup()
+	ctx := context.Background()
+
+	body := strings.Repeat("lorem ipsum ", 170)
+	for i := range 2000 {
+		p := fmt.Sprintf("docs/%02d/%04d", i%20, i)
+		for v := range 5 {
+			require.NoError(b, s.Write(ctx, p, fmt.Sprintf("version %d\n%s", v, body), writeOpts("bench", "")))
+		}
+	}
+
+	for b.Loop() {
+		docs, err := s.List(ctx, "docs/", false, false)
+		if err != nil {
+			b.Fatal(err)
+		}
+		if len(docs) != 2000 {
+			b.Fatalf("got %d documents, want 2000", len(docs))
+		}
+	}
+}
+
+func TestStore_Restore_Links(t *testing.T) {
+	s, cleanup := setupStore(t)
+	defer cleanup()
+	ctx := context.Background()
+
+	require.NoError(t, s.Write(ctx, "docs/a", "A", writeOpts("alice", "")))
+	require.NoError(t, s.Write(ctx, "docs/b", "B", writeOpts("alice", "")))
+	require.NoError(t, s.Write(ctx, "docs/c", "C", writeOpts("alice", "")))
+
+	opts := store.NewLinkOptions()
+	_, err := s.Link(ctx, "docs/a", "docs/b", "", opts)
+	require.NoError(t, err)
+	_, err = s.Link(ctx, "docs/c", "docs/a", "", opts)
+	require.NoError(t, err)
+
+	// Delete cascades to links, so Restore alone must bring them back
+	require.NoError(t, s.Delete(ctx, "docs/a", store.DeleteOptions{}))
+	require.NoError(t, s.Delete(ctx, "docs/c", store.DeleteOptions{}))
+	require.NoError(t, s.Restore(ctx, "docs/a", store.RestoreOptions{}))
+
+	links, err := s.ListLinks(ctx, "docs/a", "", opts)
+	require.NoError(t, err)
+	require.Len(t, links, 1, "link to still-deleted docs/c stays deleted")
+	assert.Equal(t, "docs/b", links[0].ToPath)
+
+	require.NoError(t, s.Restore(ctx, "docs/c", store.RestoreOptions{}))
+	links, err = s.ListLinks(ctx, "docs/a", "", opts)
+	require.NoError(t, err)
+	assert.Len(t, links, 2)
+}
+
+func TestStore_UnlinkByTag(t *testing.T) {
+	s, cleanup := setupStore(t)
+	defer cleanup()
+	ctx := context.Background()
+
+	require.NoError(t, s.Write(ctx, "docs/a", "A", writeOpts("alice", "")))
+	require.NoError(t, s.Write(ctx, "docs/b", "B", writeOpts("alice", "")))
+	require.NoError(t, s.Write(ctx, "docs/c", "C", writeOpts("alice", "")))
+
+	opts := store.NewLinkOptions()
+
+	// Create multiple links with same tag
+	_, err := s.Link(ctx, "docs/a", "docs/b", "depends-on", opts)
+	require.NoError(t, err)
+	_, err = s.Link(ctx, "docs/a", "docs/c", "depends-on", opts)
+	require.NoError(t, err)
+
+	// Unlink all with tag
+	count, err := s.UnlinkByTag(ctx, "depends-on", opts)
+	require.NoError(t, err)
+	assert.Equal(t, int64(2), count)
+
+	links, err := s.ListLinksByTag(ctx, "depends-on", opts)
+	require.NoError(t, err)
+	assert.Len(t, links, 0)
+}
+
+func TestStore_ListOrphanLinkPaths(t *testing.T) {
+	s, cleanup := setupStore(t)
+	defer cleanup()
+	ctx := context.Background()
+
+	require.NoError(t, s.Write(ctx, "docs/linked", "content", writeOpts("alice", "")))
+	require.NoError(t, s.Write(ctx, "docs/also-linked", "content", writeOpts("alice", "")))
+	require.NoError(t, s.Write(ctx, "docs/orphan", "content", writeOpts("alice", "")))
+
+	opts := store.NewLinkOptions()
+
+	// Create link between two docs
+	_, err := s.Link(ctx, "docs/linked", "docs/also-linked", "", opts)
+	require.NoError(t, err)
+
+	// Find orphans
+	orphans, err := s.ListOrphanLinkPaths(ctx, opts)
+	require.NoError(t, err)
+	assert.Equal(t, []string{"docs/orphan"}, orphans)
+}
+
+// --- Search Tests ---
+
+func TestStore_Search(t *testing.T) {
+	s, cleanup := setupStore(t)
+	defer cleanup()
+	ctx := context.Background()
 
 	require.NoError(t, s.Write(ctx, "docs/go", "Go is a statically typed language", writeOpts("alice", "")))
 	require.NoError(t, s.Write(ctx, "docs/rust", "Rust is a systems programming language", writeOpts("alice", "")))
@@ -642,6 +1604,145 @@ func TestStore_Search(t *testing.T) {
 	assert.Len(t, results, 2)
 }
 
+func TestStore_SearchRanked(t *testing.T) {
+	s, cleanup := setupStore(t)
+	defer cleanup()
+	ctx := context.Background()
+
+	require.NoError(t, s.Write(ctx, "docs/a", "setup mentions deploy once among many other words here", writeOpts("alice", "")))
+	require.NoError(t, s.Write(ctx, "docs/b", "deploy deploy deploy", writeOpts("alice", "")))
+	require.NoError(t, s.Write(ctx, "docs/c", "deploy guide: deploy steps", writeOpts("alice", "")))
+	require.NoError(t, s.Write(ctx, "docs/gone", "deploy deploy deploy deploy", writeOpts("alice", "")))
+	require.NoError(t, s.Delete(ctx, "docs/gone", store.DeleteOptions{}))
+
+	results, err := s.SearchRanked(ctx, "deploy", "")
+	require.NoError(t, err)
+	var paths []string
+	for _, d := range results {
+		paths = append(paths, d.Path)
+	}
+	assert.Equal(t, []string{"docs/b", "docs/c", "docs/a"}, paths)
+}
+
+func TestStore_SearchPage(t *testing.T) {
+	s, cleanup := setupStore(t)
+	defer cleanup()
+	ctx := context.Background()
+
+	require.NoError(t, s.Write(ctx, "docs/a", "setup mentions deploy once among many other words here", writeOpts("alice", "")))
+	require.NoError(t, s.Write(ctx, "docs/b", "deploy deploy deploy", writeOpts("alice", "")))
+	require.NoError(t, s.Write(ctx, "docs/c", "deploy guide: deploy steps", writeOpts("alice", "")))
+	require.NoError(t, s.Write(ctx, "docs/gone", "deploy deploy deploy deploy", writeOpts("alice", "")))
+	require.NoError(t, s.Delete(ctx, "docs/gone", store.DeleteOptions{}))
+
+	page := func(includeDeleted bool, limit, offset int) ([]string, int) {
+		t.Helper()
+		docs, total, err := s.SearchPage(ctx, "deploy", "", includeDeleted, false, limit, offset)
+		require.NoError(t, err)
+		var paths []string
+		for _, d := range docs {
+			paths = append(paths, d.Path)
+		}
+		return paths, total
+	}
+
+	paths, total := page(false, 2, 0)
+	assert.Equal(t, []string{"docs/b", "docs/c"}, paths)
+	assert.Equal(t, 3, total)
+
+	paths, total = page(false, 2, 2)
+	assert.Equal(t, []string{"docs/a"}, paths)
+	assert.Equal(t, 3, total)
+
+	paths, _ = page(false, 0, 1)
+	assert.Equal(t, []string{"docs/c", "docs/a"}, paths, "limit 0 returns the rest")
+
+	paths, total = page(false, 2, 5)
+	assert.Empty(t, paths)
+	assert.Equal(t, 3, total)
+
+	paths, total = page(true, 1, 0)
+	assert.Equal(t, []string{"docs/gone"}, paths)
+	assert.Equal(t, 4, total)
+
+	_, _, err := s.SearchPage(ctx, "deploy", "", false, false, -1, 0)
+	assert.Error(t, err)
+}
+
+func TestStore_Search_LiteralQuery(t *testing.T) {
+	s, cleanup := setupStore(t)
+	defer cleanup()
+	ctx := context.Background()
+
+	require.NoError(t, s.Write(ctx, "docs/cpp", "Notes on C++ and the foo-bar tool", writeOpts("alice", "")))
+	require.NoError(t, s.Write(ctx, "docs/kv", `Use key:value pairs, or say "hi"`, writeOpts("alice", "")))
+
+	_, err := s.Search(ctx, "C++", "", false, false)
+	assert.True(t, store.IsQueryError(err), "raw C++ should be an FTS5 query error, got %v", err)
+	_, err = s.Search(ctx, "foo-bar", "", false, false)
+	assert.True(t, store.IsQueryError(err), "raw foo-bar should be an FTS5 query error, got %v", err)
+
+	tests := []struct {
+		query string
+		want  string
+	}{
+		{"C++", "docs/cpp"},
+		{"foo-bar", "docs/cpp"},
+		{"key:value", "docs/kv"},
+		{`"hi"`, "docs/kv"},
+		{`say "hi`, "docs/kv"},
+	}
+	for _, tt := range tests {
+		results, err := s.Search(ctx, store.LiteralQuery(tt.query), "", false, false)
+		require.NoError(t, err, "query %q", tt.query)
+		require.Len(t, results, 1, "query %q", tt.query)
+		assert.Equal(t, tt.want, results[0].Path, "query %q", tt.query)
+	}
+}
+
+func TestStore_Search_Accents(t *testing.T) {
+	s, cleanup := setupStore(t)
+	defer cleanup()
+	ctx := context.Background()
+
+	require.NoError(t, s.Write(ctx, "docs/fr", "Le café est à côté de l'hôtel", writeOpts("alice", "")))
+	require.NoError(t, s.Write(ctx, "docs/de", "ÜBER die Straße", writeOpts("alice", "")))
+	require.NoError(t, s.Write(ctx, "docs/en", "The cafe is next to the hotel", writeOpts("alice", "")))
+	require.NoError(t, s.Write(ctx, "docs/vi", "Tiếng Việt có dấu", writeOpts("alice", "")))
+
+	tests := []struct {
+		query string
+		want  []string
+	}{
+		{"cafe", []string{"docs/en", "docs/fr"}},
+		{"café", []string{"docs/en", "docs/fr"}},
+		{"CAFÉ", []string{"docs/en", "docs/fr"}},
+		{"cote", []string{"docs/fr"}},
+		{"hôtel", []string{"docs/en", "docs/fr"}},
+		{"uber", []string{"docs/de"}},
+		{"über", []string{"docs/de"}},
+		{"viet", []string{"docs/vi"}},
+		{"tieng", []string{"docs/vi"}},
+		{"việt", []string{"docs/vi"}},
+	}
+	for _, tt := range tests {
+		results, err := s.Search(ctx, tt.query, "", false, false)
+		require.NoError(t, err, "query %q", tt.query)
+		var got []string
+		for _, d := range results {
+			got = append(got, d.Path)
+		}
+		assert.ElementsMatch(t, tt.want, got, "query %q", tt.query)
+	}
+}
+
+func TestLiteralQuery(t *testing.T) {
+	assert.Equal(t, `"C++"`, store.LiteralQuery("C++"))
+	assert.Equal(t, `"foo-bar" "a:b"`, store.LiteralQuery("  foo-bar   a:b "))
+	assert.Equal(t, `"say" """hi"""`, store.LiteralQuery(`say "hi"`))
+	assert.Equal(t, `""`, store.LiteralQuery(""))
+}
+
 // --- Vacuum Tests ---
 
 func TestStore_Vacuum(t *testing.T) {
@@ -706,6 +1807,27 @@ func TestStore_EmptyContent(t *testing.T) {
 	assert.Equal(t, "", doc.Content)
 }
 
+func TestStore_ContentLimit(t *testing.T) {
+	s, cleanup := setupStore(t)
+	defer cleanup()
+	ctx := context.Background()
+
+	opts := writeOpts("alice", "")
+	opts.MaxContent = 10
+
+	// Exactly at the limit is allowed
+	require.NoError(t, s.Write(ctx, "docs/limit", "0123456789", opts))
+
+	// One byte over is rejected, and the error says by how much
+	err := s.Write(ctx, "docs/limit", "0123456789x", opts)
+	require.ErrorIs(t, err, validate.ErrContentTooLarge)
+	assert.ErrorContains(t, err, "11 bytes exceeds the 10 byte limit by 1")
+
+	doc, err := s.Latest(ctx, "docs/limit", false)
+	require.NoError(t, err)
+	assert.Equal(t, 1, doc.Version, "rejected write must not add a version")
+}
+
 func TestStore_SpecialCharactersInPath(t *testing.T) {
 	s, cleanup := setupStore(t)
 	defer cleanup()
@@ -748,6 +1870,90 @@ func TestStore_UniqueKeys(t *testing.T) {
 	}
 }
 
+func TestStore_ConcurrentWriteKeys(t *testing.T) {
+	s, cleanup := setupStore(t)
+	defer cleanup()
+	ctx := context.Background()
+
+	const writers, perWriter = 8, 25
+	var wg sync.WaitGroup
+	errs := make(chan error, writers*perWriter)
+	for w := range writers {
+		wg.Add(1)
+		go func() {
+			defer wg.Done()
+			for i := range perWriter {
+				// Half the writers share a path to contend on versions too
+				path := fmt.Sprintf("docs/w%d/doc%d", w%(writers/2), i)
+				errs <- s.Write(ctx, path, "content", writeOpts("alice", ""))
+			}
+		}()
+	}
+	wg.Wait()
+	close(errs)
+	for err := range errs {
+		require.NoError(t, err)
+	}
+
+	var total, distinct int
+	require.NoError(t, s.DB().QueryRowContext(ctx, `SELECT COUNT(*), COUNT(DISTINCT key) FROM documents`).Scan(&total, &distinct))
+	assert.Equal(t, writers*perWriter, total)
+	assert.Equal(t, total, distinct, "every version has its own key")
+}
+
+func TestStore_ConcurrentStores(t *testing.T) {
+	ctx := context.Background()
+	dbPath := filepath.Join(t.TempDir(), "test.db")
+
+	s, err := store.Open(dbPath)
+	require.NoError(t, err)
+	defer s.Close()
+	require.NoError(t, s.Init())
+
+	// A second store on the same file stands in for another process
+	other, err := store.OpenWithOptions(dbPath, store.OpenOptions{BusyTimeout: 10 * time.Second})
+	require.NoError(t, err)
+	defer other.Close()
+
+	const perStore = 50
+	var wg sync.WaitGroup
+	errs := make(chan error, 2*perStore)
+	for n, st := range []*store.SQLiteStore{s, other} {
+		wg.Add(1)
+		go func() {
+			defer wg.Done()
+			for i := range perStore {
+				// Both stores append versions to the same document
+				errs <- st.Write(ctx, "docs/shared", fmt.Sprintf("store %d write %d", n, i), writeOpts("alice", ""))
+			}
+		}()
+	}
+	wg.Wait()
+	close(errs)
+	for err := range errs {
+		require.NoError(t, err)
+	}
+
+	history, err := s.History(ctx, "docs/shared", 0, false)
+	require.NoError(t, err)
+	assert.Len(t, history, 2*perStore)
+	assert.Equal(t, 2*perStore, history[0].Version, "versions are contiguous")
+}
+
+func TestStore_DuplicateKeyRejected(t *testing.T) {
+	s, cleanup := setupStore(t)
+	defer cleanup()
+	ctx := context.Background()
+
+	require.NoError(t, s.Write(ctx, "docs/a", "A", writeOpts("alice", "")))
+	doc, err := s.Latest(ctx, "docs/a", false)
+	require.NoError(t, err)
+
+	_, err = s.DB().ExecContext(ctx, `INSERT INTO documents (key, path, content, version, author, created_at)
+		VALUES (?, 'docs/b', 'B', 1, 'alice', 0)`, doc.Key)
+	assert.ErrorContains(t, err, "UNIQUE constraint failed: documents.key")
+}
+
 func TestStore_Transaction(t *testing.T) {
 	s, cleanup := setupStore(t)
 	defer cleanup()
@@ -769,3 +1975,128 @@ func TestStore_Transaction(t *testing.T) {
 	exists, _ := s.Exists(ctx, "docs/tx-test")
 	assert.False(t, exists)
 }
+
+func TestStore_Verify(t *testing.T) {
+	s, cleanup := setupStore(t)
+	defer cleanup()
+	ctx := context.Background()
+
+	require.NoError(t, s.Write(ctx, "docs/a", "A", writeOpts("alice", "")))
+	require.NoError(t, s.Write(ctx, "docs/b", "B", writeOpts("alice", "")))
+	id, err := s.Link(ctx, "docs/a", "docs/b", "", store.NewLinkOptions())
+	require.NoError(t, err)
+
+	issues, err := s.Verify(ctx)
+	require.NoError(t, err)
+	assert.Empty(t, issues)
+
+	// Remove the target behind the link's back, as a bad merge might
+	_, err = s.DB().ExecContext(ctx, `DELETE FROM documents WHERE path = 'docs/b'`)
+	require.NoError(t, err)
+
+	issues, err = s.Verify(ctx)
+	require.NoError(t, err)
+	require.Len(t, issues, 1)
+	assert.Equal(t, store.CheckDanglingLink, issues[0].Check)
+	assert.Contains(t, issues[0].Detail, id)
+	assert.Contains(t, issues[0].Detail, "missing docs/b")
+}
+
+func TestStore_VerifyContentHash(t *testing.T) {
+	s, cleanup := setupStore(t)
+	defer cleanup()
+	ctx := context.Background()
+
+	require.NoError(t, s.Write(ctx, "docs/a", "original", writeOpts("alice", "")))
+	require.NoError(t, s.Write(ctx, "docs/a", "second", writeOpts("alice", "")))
+
+	// Change the first version's content behind the store's back
+	_, err := s.DB().ExecContext(ctx, `UPDATE documents SET content = 'tampered' WHERE path = 'docs/a' AND version = 1`)
+	require.NoError(t, err)
+
+	issues, err := s.Verify(ctx)
+	require.NoError(t, err)
+	require.Len(t, issues, 1)
+	assert.Equal(t, store.CheckContentHash, issues[0].Check)
+	assert.Equal(t, "docs/a v1: content does not match its recorded hash", issues[0].Detail)
+}
+
+func TestStore_Reindex(t *testing.T) {
+	s, cleanup := setupStore(t)
+	defer cleanup()
+	ctx := context.Background()
+
+	require.NoError(t, s.Write(ctx, "docs/a", "searchable words", writeOpts("alice", "")))
+	require.NoError(t, s.Write(ctx, "docs/b", "other text", writeOpts("alice", "")))
+
+	// Empty the index behind the triggers' back, as a direct insert might leave it
+	_, err := s.DB().ExecContext(ctx, `INSERT INTO documents_fts(documents_fts) VALUES('delete-all')`)
+	require.NoError(t, err)
+	results, err := s.Search(ctx, "searchable", "", false, false)
+	require.NoError(t, err)
+	require.Empty(t, results)
+
+	n, err := s.Reindex(ctx)
+	require.NoError(t, err)
+	assert.Equal(t, int64(2), n)
+
+	results, err = s.Search(ctx, "searchable", "", false, false)
+	require.NoError(t, err)
+	require.Len(t, results, 1)
+	assert.Equal(t, "docs/a", results[0].Path)
+}
+
+func TestStore_Recent(t *testing.T) {
+	s, cleanup := setupStore(t)
+	defer cleanup()
+	ctx := context.Background()
+
+	require.NoError(t, s.Write(ctx, "docs/a", "a1", writeOpts("alice", "first")))
+	require.NoError(t, s.Write(ctx, "docs/a", "a2", writeOpts("bob", "second")))
+	require.NoError(t, s.Write(ctx, "docs/b", "b1", writeOpts("alice", "")))
+	require.NoError(t, s.Write(ctx, "docs/gone", "g1", writeOpts("alice", "")))
+	require.NoError(t, s.Write(ctx, "notes/c", "c1", writeOpts("carol", "")))
+
+	// a: v1 at 100, v2 at 400. b and c at 200. gone at 500, then deleted
+	require.NoError(t, s.Tx(ctx, func(tx *sql.Tx) error {
+		if _, err := tx.ExecContext(ctx, `UPDATE documents SET created_at = version * 300 - 200 WHERE path = 'docs/a'`); err != nil {
+			return err
+		}
+		if _, err := tx.ExecContext(ctx, `UPDATE documents SET created_at = 500, deleted_at = 600 WHERE path = 'docs/gone'`); err != nil {
+			return err
+		}
+		_, err := tx.ExecContext(ctx, `UPDATE documents SET created_at = 200 WHERE path IN ('docs/b', 'notes/c')`)
+		return err
+	}))
+
+	recent := func(prefix string, since int64, limit int) []string {
+		t.Helper()
+		var at time.Time
+		if since > 0 {
+			at = time.Unix(since, 0)
+		}
+		metas, err := s.Recent(ctx, prefix, at, limit)
+		require.NoError(t, err)
+		var got []string
+		for _, m := range metas {
+			got = append(got, fmt.Sprintf("%s@%d", m.Path, m.Version))
+		}
+		return got
+	}
+
+	assert.Equal(t, []string{"docs/a@2", "docs/b@1", "notes/c@1"}, recent("", 0, 0))
+	assert.Equal(t, []string{"docs/a@2", "docs/b@1"}, recent("", 0, 2))
+	assert.Equal(t, []string{"docs/a@2", "docs/b@1"}, recent("docs/", 0, 0))
+	assert.Equal(t, []string{"docs/a@2"}, recent("", 200, 0))
+	assert.Empty(t, recent("", 400, 0))
+
+	metas, err := s.Recent(ctx, "", time.Time{}, 1)
+	require.NoError(t, err)
+	require.Len(t, metas, 1)
+	assert.Equal(t, "bob", metas[0].Author)
+	assert.Equal(t, "second", metas[0].Message)
+	assert.Equal(t, int64(2), metas[0].Size)
+
+	_, err = s.Recent(ctx, "", time.Time{}, -1)
+	assert.Error(t, err)
+}
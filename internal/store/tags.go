@@ -12,6 +12,8 @@ package store
 
 import (
 	"context"
+	"database/sql"
+	"errors"
 	"fmt"
 	"strings"
 	"time"
@@ -22,7 +24,7 @@ import (
 // ListByTag returns the latest version of documents matching the given prefix and tag.
 func (s *SQLiteStore) ListByTag(ctx context.Context, prefix, tag string, includeDeleted, deletedOnly bool, opts TagOptions) ([]Document, error) {
 	var b strings.Builder
-	b.WriteString(`SELECT d.id, d.key, d.path, d.content, d.version, d.author, d.message, d.created_at, d.deleted_at
+	b.WriteString(`SELECT d.id, d.key, d.path, d.content, d.version, d.author, d.message, d.created_at, d.deleted_at, d.content_type
 		FROM documents d
 		INNER JOIN (
 			SELECT path, MAX(version) as max_version FROM documents`)
@@ -108,14 +110,10 @@ func (s *SQLiteStore) Tag(ctx context.Context, path, tag string, opts TagOptions
 
 	// Insert new tag
 	now := time.Now().Unix()
-	id, err := genID()
-	if err != nil {
-		return err
-	}
-	_, err = s.db.ExecContext(ctx, `
+	_, err = insertWithID(ctx, s.db, "tags.id", `
 		INSERT INTO tags (id, path, source, tag, created_at, deleted_at)
 		VALUES (?, ?, ?, ?, ?, NULL)
-	`, id, path, opts.Source, tag, now)
+	`, path, opts.Source, tag, now)
 	if err != nil {
 		return fmt.Errorf("adding tag: %w", err)
 	}
@@ -147,6 +145,71 @@ func (s *SQLiteStore) Untag(ctx context.Context, path, tag string, opts TagOptio
 	return nil
 }
 
+// RenameTag renames oldTag to newTag on every document that has it, in a
+// single transaction, and returns the number of documents affected.
+//
+// A document that already has newTag keeps that tag and loses oldTag, so a
+// rename merges the two rather than creating a duplicate. A soft-deleted
+// newTag on such a document is restored. Tags removed from documents are
+// not renamed; they keep oldTag until vacuum.
+func (s *SQLiteStore) RenameTag(ctx context.Context, oldTag, newTag string, opts TagOptions) (int64, error) {
+	if err := validate.Tag(oldTag); err != nil {
+		return 0, err
+	}
+	if err := validate.Tag(newTag); err != nil {
+		return 0, err
+	}
+	if oldTag == newTag {
+		return 0, errors.New("old and new tag are the same")
+	}
+
+	// Documents that have oldTag and already hold a row for newTag, active or not
+	const merged = `path IN (
+		SELECT path FROM tags WHERE source = ? AND tag = ? AND deleted_at IS NULL
+		INTERSECT
+		SELECT path FROM tags WHERE source = ? AND tag = ?)`
+
+	var count int64
+	err := s.Tx(ctx, func(tx *sql.Tx) error {
+		count = 0
+		now := time.Now().Unix()
+
+		if _, err := tx.ExecContext(ctx, `UPDATE tags SET deleted_at = NULL
+			WHERE source = ? AND tag = ? AND `+merged,
+			opts.Source, newTag, opts.Source, oldTag, opts.Source, newTag); err != nil {
+			return fmt.Errorf("restoring tag: %w", err)
+		}
+		result, err := tx.ExecContext(ctx, `UPDATE tags SET deleted_at = ?
+			WHERE source = ? AND tag = ? AND deleted_at IS NULL AND `+merged,
+			now, opts.Source, oldTag, opts.Source, oldTag, opts.Source, newTag)
+		if err != nil {
+			return fmt.Errorf("merging tag: %w", err)
+		}
+		n, err := result.RowsAffected()
+		if err != nil {
+			return fmt.Errorf("checking rows affected: %w", err)
+		}
+		count += n
+
+		result, err = tx.ExecContext(ctx, `UPDATE tags SET tag = ?
+			WHERE source = ? AND tag = ? AND deleted_at IS NULL`,
+			newTag, opts.Source, oldTag)
+		if err != nil {
+			return fmt.Errorf("renaming tag: %w", err)
+		}
+		n, err = result.RowsAffected()
+		if err != nil {
+			return fmt.Errorf("checking rows affected: %w", err)
+		}
+		count += n
+		return nil
+	})
+	if err != nil {
+		return 0, fmt.Errorf("rename tag %s to %s: %w", oldTag, newTag, err)
+	}
+	return count, nil
+}
+
 // ListTags returns all unique tags, optionally filtered by document path.
 // When path is empty, returns all tags in the system for discovery and autocomplete.
 // When path is provided, returns only tags on that specific document.
@@ -177,6 +240,51 @@ func (s *SQLiteStore) ListTags(ctx context.Context, path string, opts TagOptions
 	return tags, rows.Err()
 }
 
+// TagSets returns the full tag set of every document having tag, keyed by
+// path. A self-join on the tags table fetches all the sets in one query
+// rather than one ListTags call per document.
+func (s *SQLiteStore) TagSets(ctx context.Context, tag string, opts TagOptions) (map[string][]string, error) {
+	rows, err := s.db.QueryContext(ctx, `SELECT t.path, t.tag FROM tags t
+		INNER JOIN tags f ON f.path = t.path AND f.source = t.source AND f.tag = ? AND f.deleted_at IS NULL
+		WHERE t.source = ? AND t.deleted_at IS NULL
+		ORDER BY t.path, t.tag`, tag, opts.Source)
+	if err != nil {
+		return nil, fmt.Errorf("tag sets for %s: %w", tag, err)
+	}
+	defer rows.Close()
+
+	sets := make(map[string][]string)
+	for rows.Next() {
+		var path, t string
+		if err := rows.Scan(&path, &t); err != nil {
+			return nil, fmt.Errorf("scan tag: %w", err)
+		}
+		sets[path] = append(sets[path], t)
+	}
+	return sets, rows.Err()
+}
+
+// TagCounts returns each tag with the number of documents that have it.
+// Gives an overview of how tagging is distributed across the store.
+func (s *SQLiteStore) TagCounts(ctx context.Context, opts TagOptions) (map[string]int, error) {
+	rows, err := s.db.QueryContext(ctx, `SELECT tag, COUNT(*) FROM tags WHERE source = ? AND deleted_at IS NULL GROUP BY tag`, opts.Source)
+	if err != nil {
+		return nil, fmt.Errorf("count tags: %w", err)
+	}
+	defer rows.Close()
+
+	counts := make(map[string]int)
+	for rows.Next() {
+		var tag string
+		var n int
+		if err := rows.Scan(&tag, &n); err != nil {
+			return nil, fmt.Errorf("scan tag count: %w", err)
+		}
+		counts[tag] = n
+	}
+	return counts, rows.Err()
+}
+
 // PathsWithTag returns all document paths with a specific tag.
 // Enables tag-based navigation - "show me all documents tagged 'urgent'".
 func (s *SQLiteStore) PathsWithTag(ctx context.Context, tag string, opts TagOptions) ([]string, error) {
@@ -0,0 +1,139 @@
+// verify.go implements consistency checks over the SQLite store.
+//
+// Separated from stats.go because these queries validate rather than
+// summarise: they look for corruption and broken references that the
+// normal read paths would silently skip over.
+//
+// Design: Verify reports problems as data rather than failing on the first
+// one, so operators get the full picture in a single run. Only failures to
+// run a check at all are returned as errors.
+
+package store
+
+import (
+	"context"
+	"fmt"
+)
+
+// Checks performed by Verify, used as Issue.Check.
+const (
+	CheckIntegrity    = "integrity"     // SQLite page and index structure
+	CheckDanglingLink = "dangling_link" // Live link to a missing document
+	CheckContentHash  = "content_hash"  // Version whose content no longer matches its hash
+)
+
+// Issue is one problem found by Verify.
+type Issue struct {
+	Check  string `json:"check"`  // Which check found it (CheckIntegrity, CheckDanglingLink, CheckContentHash)
+	Detail string `json:"detail"` // Human-readable description
+}
+
+// Verify runs SQLite's integrity check, looks for live links whose
+// endpoints no longer exist and recomputes the content hash of every
+// version that has one. It returns nil when the store is consistent.
+func (s *SQLiteStore) Verify(ctx context.Context) ([]Issue, error) {
+	var issues []Issue
+	for _, check := range []func(context.Context) ([]Issue, error){
+		s.checkIntegrity, s.checkLinks, s.checkHashes,
+	} {
+		found, err := check(ctx)
+		if err != nil {
+			return nil, err
+		}
+		issues = append(issues, found...)
+	}
+	return issues, nil
+}
+
+// checkIntegrity runs PRAGMA integrity_check, which reports a single "ok"
+// row for a healthy database and one row per problem otherwise.
+func (s *SQLiteStore) checkIntegrity(ctx context.Context) ([]Issue, error) {
+	rows, err := s.db.QueryContext(ctx, `PRAGMA integrity_check`)
+	if err != nil {
+		return nil, fmt.Errorf("integrity check: %w", err)
+	}
+	defer rows.Close()
+
+	var issues []Issue
+	for rows.Next() {
+		var msg string
+		if err := rows.Scan(&msg); err != nil {
+			return nil, fmt.Errorf("integrity check: %w", err)
+		}
+		if msg != "ok" {
+			issues = append(issues, Issue{Check: CheckIntegrity, Detail: msg})
+		}
+	}
+	return issues, rows.Err()
+}
+
+// checkLinks finds live links with an endpoint in the documents table that
+// has no live document. Links to other sources belong to extensions and
+// are not checked.
+func (s *SQLiteStore) checkLinks(ctx context.Context) ([]Issue, error) {
+	rows, err := s.db.QueryContext(ctx, `SELECT id, from_path, to_path, from_missing, to_missing FROM (
+			SELECT id, from_path, to_path,
+				from_source = 'documents' AND NOT EXISTS (
+					SELECT 1 FROM documents d WHERE d.path = l.from_path AND d.deleted_at IS NULL
+				) AS from_missing,
+				to_source = 'documents' AND NOT EXISTS (
+					SELECT 1 FROM documents d WHERE d.path = l.to_path AND d.deleted_at IS NULL
+				) AS to_missing
+			FROM links l WHERE deleted_at IS NULL
+		) WHERE from_missing OR to_missing
+		ORDER BY id`)
+	if err != nil {
+		return nil, fmt.Errorf("check links: %w", err)
+	}
+	defer rows.Close()
+
+	var issues []Issue
+	for rows.Next() {
+		var id, from, to string
+		var fromMissing, toMissing bool
+		if err := rows.Scan(&id, &from, &to, &fromMissing, &toMissing); err != nil {
+			return nil, fmt.Errorf("check links: %w", err)
+		}
+		missing := to
+		switch {
+		case fromMissing && toMissing:
+			missing = from + " and " + to
+		case fromMissing:
+			missing = from
+		}
+		issues = append(issues, Issue{
+			Check:  CheckDanglingLink,
+			Detail: fmt.Sprintf("link %s (%s -> %s): missing %s", id, from, to, missing),
+		})
+	}
+	return issues, rows.Err()
+}
+
+// checkHashes recomputes the hash of every version's content and reports
+// those that differ from the recorded content_hash, which means the content
+// changed without going through Write. Encrypted stores record no hash
+// (see hashContent), so their rows are skipped.
+func (s *SQLiteStore) checkHashes(ctx context.Context) ([]Issue, error) {
+	rows, err := s.db.QueryContext(ctx, `SELECT path, version, content, content_hash FROM documents
+		WHERE content_hash != '' ORDER BY path, version`)
+	if err != nil {
+		return nil, fmt.Errorf("check content hashes: %w", err)
+	}
+	defer rows.Close()
+
+	var issues []Issue
+	for rows.Next() {
+		var path, content, hash string
+		var version int
+		if err := rows.Scan(&path, &version, &content, &hash); err != nil {
+			return nil, fmt.Errorf("check content hashes: %w", err)
+		}
+		if contentHash(content) != hash {
+			issues = append(issues, Issue{
+				Check:  CheckContentHash,
+				Detail: fmt.Sprintf("%s v%d: content does not match its recorded hash", path, version),
+			})
+		}
+	}
+	return issues, rows.Err()
+}
@@ -13,9 +13,11 @@ package store
 import (
 	"context"
 	"database/sql"
+	"errors"
 	"fmt"
 	"time"
 
+	"github.com/jpl-au/llmd/internal/contenttype"
 	"github.com/jpl-au/llmd/internal/validate"
 )
 
@@ -31,6 +33,16 @@ func (s *SQLiteStore) Write(ctx context.Context, path, content string, opts Writ
 	if err := validate.Content(content, opts.MaxContent); err != nil {
 		return err
 	}
+	if opts.ContentType != "" {
+		if err := validate.ContentType(opts.ContentType); err != nil {
+			return err
+		}
+	}
+	hash := s.hashContent(content)
+	content, err = s.sealContent(content)
+	if err != nil {
+		return err
+	}
 
 	return s.Tx(ctx, func(tx *sql.Tx) error {
 		var maxVer int
@@ -39,14 +51,21 @@ func (s *SQLiteStore) Write(ctx context.Context, path, content string, opts Writ
 			return fmt.Errorf("get max version: %w", err)
 		}
 
-		id, err := genID()
-		if err != nil {
-			return err
+		// An edit rewrites content without restating its type, so the
+		// type carries over from the previous version unless given.
+		ct := opts.ContentType
+		if ct == "" {
+			err := tx.QueryRowContext(ctx, `SELECT content_type FROM documents WHERE path = ? ORDER BY version DESC LIMIT 1`, path).Scan(&ct)
+			if errors.Is(err, sql.ErrNoRows) {
+				ct = contenttype.Default
+			} else if err != nil {
+				return fmt.Errorf("get content type: %w", err)
+			}
 		}
 
-		_, err = tx.ExecContext(ctx, `INSERT INTO documents (key, path, content, version, author, message, created_at)
-			VALUES (?, ?, ?, ?, ?, ?, ?)`,
-			id, path, content, maxVer+1, opts.Author, opts.Message, time.Now().Unix())
+		_, err = insertWithID(ctx, tx, "documents.key", `INSERT INTO documents (key, path, content, version, author, message, created_at, content_type, content_hash)
+			VALUES (?, ?, ?, ?, ?, ?, ?, ?, ?)`,
+			path, content, maxVer+1, opts.Author, opts.Message, time.Now().Unix(), ct, hash)
 		if err != nil {
 			return fmt.Errorf("insert document: %w", err)
 		}
@@ -64,7 +83,38 @@ func (s *SQLiteStore) Delete(ctx context.Context, path string, opts DeleteOption
 		return err
 	}
 	now := time.Now().Unix()
-	result, err := s.db.ExecContext(ctx, `UPDATE documents SET deleted_at = ? WHERE path = ? AND deleted_at IS NULL`,
+	return s.Tx(ctx, func(tx *sql.Tx) error {
+		return deleteTx(ctx, tx, path, now)
+	})
+}
+
+// DeleteMany soft-deletes several documents in one transaction, so either
+// all of them are deleted or none are. A missing or already deleted path
+// fails the batch with an error naming it that wraps ErrNotFound.
+func (s *SQLiteStore) DeleteMany(ctx context.Context, paths []string, opts DeleteOptions) error {
+	valid := make([]string, len(paths))
+	for i, p := range paths {
+		p, err := validate.Path(p, opts.MaxPath)
+		if err != nil {
+			return err
+		}
+		valid[i] = p
+	}
+
+	now := time.Now().Unix()
+	return s.Tx(ctx, func(tx *sql.Tx) error {
+		for _, p := range valid {
+			if err := deleteTx(ctx, tx, p, now); err != nil {
+				return fmt.Errorf("delete %s: %w", p, err)
+			}
+		}
+		return nil
+	})
+}
+
+// deleteTx soft-deletes every version of path within tx, with its links.
+func deleteTx(ctx context.Context, tx *sql.Tx, path string, now int64) error {
+	result, err := tx.ExecContext(ctx, `UPDATE documents SET deleted_at = ? WHERE path = ? AND deleted_at IS NULL`,
 		now, path)
 	if err != nil {
 		return fmt.Errorf("delete %s: %w", path, err)
@@ -78,7 +128,7 @@ func (s *SQLiteStore) Delete(ctx context.Context, path string, opts DeleteOption
 	}
 
 	// Cascade soft-delete to associated links (both directions)
-	_, err = s.db.ExecContext(ctx, `
+	_, err = tx.ExecContext(ctx, `
 		UPDATE links SET deleted_at = ?
 		WHERE (from_path = ? OR to_path = ?) AND deleted_at IS NULL
 	`, now, path, path)
@@ -126,25 +176,18 @@ func (s *SQLiteStore) Restore(ctx context.Context, path string, opts RestoreOpti
 	if err != nil {
 		return err
 	}
-	result, err := s.db.ExecContext(ctx, `UPDATE documents SET deleted_at = NULL WHERE path = ? AND deleted_at IS NOT NULL`, path)
-	if err != nil {
-		return fmt.Errorf("restore %s: %w", path, err)
-	}
-	rows, err := result.RowsAffected()
-	if err != nil {
-		return fmt.Errorf("restore %s: %w", path, err)
-	}
-	if rows == 0 {
-		return ErrNotFound
-	}
-
-	// Cascade restore to associated links (both directions)
-	_, err = s.db.ExecContext(ctx, `
-		UPDATE links SET deleted_at = NULL
-		WHERE (from_path = ? OR to_path = ?) AND deleted_at IS NOT NULL
-	`, path, path)
-	if err != nil {
-		return fmt.Errorf("restoring links for %s: %w", path, err)
-	}
-	return nil
+	return s.Tx(ctx, func(tx *sql.Tx) error {
+		result, err := tx.ExecContext(ctx, `UPDATE documents SET deleted_at = NULL WHERE path = ? AND deleted_at IS NOT NULL`, path)
+		if err != nil {
+			return fmt.Errorf("restore %s: %w", path, err)
+		}
+		rows, err := result.RowsAffected()
+		if err != nil {
+			return fmt.Errorf("restore %s: %w", path, err)
+		}
+		if rows == 0 {
+			return ErrNotFound
+		}
+		return restoreLinks(ctx, tx, path)
+	})
 }
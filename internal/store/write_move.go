@@ -15,6 +15,7 @@ import (
 	"database/sql"
 	"errors"
 	"fmt"
+	"strings"
 	"time"
 
 	"github.com/jpl-au/llmd/internal/validate"
@@ -33,43 +34,76 @@ func (s *SQLiteStore) Move(ctx context.Context, src, dst string, opts MoveOption
 	}
 
 	return s.Tx(ctx, func(tx *sql.Tx) error {
-		// Check destination doesn't exist
-		var n int
-		if err := tx.QueryRowContext(ctx, `SELECT COUNT(*) FROM documents WHERE path = ? AND deleted_at IS NULL`, dst).Scan(&n); err != nil {
-			return fmt.Errorf("check destination %s: %w", dst, err)
-		}
-		if n > 0 {
-			return ErrAlreadyExists
-		}
+		return moveTx(ctx, tx, src, dst)
+	})
+}
 
-		res, err := tx.ExecContext(ctx, `UPDATE documents SET path = ? WHERE path = ?`, dst, src)
+// MoveMany applies several renames in one transaction, in order, so either
+// all of them happen or none do. Each later op sees the earlier ones, so a
+// chain such as a -> b, c -> a is allowed. A failing op is named in the
+// error, which wraps ErrNotFound or ErrAlreadyExists as Move would.
+func (s *SQLiteStore) MoveMany(ctx context.Context, ops []MoveOp, opts MoveOptions) error {
+	valid := make([]MoveOp, len(ops))
+	for i, op := range ops {
+		src, err := validate.Path(op.From, opts.MaxPath)
 		if err != nil {
-			return fmt.Errorf("move %s to %s: %w", src, dst, err)
+			return err
 		}
-		rows, err := res.RowsAffected()
+		dst, err := validate.Path(op.To, opts.MaxPath)
 		if err != nil {
-			return fmt.Errorf("move %s to %s: %w", src, dst, err)
-		}
-		if rows == 0 {
-			return ErrNotFound
-		}
-
-		// Update tags to point to new path
-		if _, err := tx.ExecContext(ctx, `UPDATE tags SET path = ? WHERE path = ?`, dst, src); err != nil {
-			return fmt.Errorf("update tags for move %s to %s: %w", src, dst, err)
+			return err
 		}
+		valid[i] = MoveOp{From: src, To: dst}
+	}
 
-		// Update links to point to new path (both directions)
-		if _, err := tx.ExecContext(ctx, `UPDATE links SET from_path = ? WHERE from_path = ?`, dst, src); err != nil {
-			return fmt.Errorf("update link sources for move %s to %s: %w", src, dst, err)
-		}
-		if _, err := tx.ExecContext(ctx, `UPDATE links SET to_path = ? WHERE to_path = ?`, dst, src); err != nil {
-			return fmt.Errorf("update link targets for move %s to %s: %w", src, dst, err)
+	return s.Tx(ctx, func(tx *sql.Tx) error {
+		for _, op := range valid {
+			if err := moveTx(ctx, tx, op.From, op.To); err != nil {
+				return fmt.Errorf("move %s to %s: %w", op.From, op.To, err)
+			}
 		}
 		return nil
 	})
 }
 
+// moveTx renames src to dst within tx, carrying tags and links with it.
+func moveTx(ctx context.Context, tx *sql.Tx, src, dst string) error {
+	// Check destination doesn't exist
+	var n int
+	if err := tx.QueryRowContext(ctx, `SELECT COUNT(*) FROM documents WHERE path = ? AND deleted_at IS NULL`, dst).Scan(&n); err != nil {
+		return fmt.Errorf("check destination %s: %w", dst, err)
+	}
+	if n > 0 {
+		return ErrAlreadyExists
+	}
+
+	res, err := tx.ExecContext(ctx, `UPDATE documents SET path = ? WHERE path = ?`, dst, src)
+	if err != nil {
+		return fmt.Errorf("move %s to %s: %w", src, dst, err)
+	}
+	rows, err := res.RowsAffected()
+	if err != nil {
+		return fmt.Errorf("move %s to %s: %w", src, dst, err)
+	}
+	if rows == 0 {
+		return ErrNotFound
+	}
+
+	// Update tags to point to new path
+	if _, err := tx.ExecContext(ctx, `UPDATE tags SET path = ? WHERE path = ?`, dst, src); err != nil {
+		return fmt.Errorf("update tags for move %s to %s: %w", src, dst, err)
+	}
+
+	// Update links to point to new path (both directions)
+	if _, err := tx.ExecContext(ctx, `UPDATE links SET from_path = ? WHERE from_path = ?`, dst, src); err != nil {
+		return fmt.Errorf("update link sources for move %s to %s: %w", src, dst, err)
+	}
+	if _, err := tx.ExecContext(ctx, `UPDATE links SET to_path = ? WHERE to_path = ?`, dst, src); err != nil {
+		return fmt.Errorf("update link targets for move %s to %s: %w", src, dst, err)
+	}
+	return nil
+}
+
 // Copy duplicates a document to a new path, creating version 1 at the destination.
 // Returns ErrNotFound if source doesn't exist, ErrAlreadyExists if destination exists.
 func (s *SQLiteStore) Copy(ctx context.Context, from, to, copier string, opts CopyOptions) error {
@@ -82,42 +116,114 @@ func (s *SQLiteStore) Copy(ctx context.Context, from, to, copier string, opts Co
 		return err
 	}
 
+	now := time.Now().Unix()
 	return s.Tx(ctx, func(tx *sql.Tx) error {
-		// Check destination doesn't exist
-		var n int
-		if err := tx.QueryRowContext(ctx, `SELECT COUNT(*) FROM documents WHERE path = ? AND deleted_at IS NULL`, to).Scan(&n); err != nil {
-			return fmt.Errorf("check destination %s: %w", to, err)
+		return copyTx(ctx, tx, from, to, copier, now)
+	})
+}
+
+// CopyPrefix copies every document under fromPrefix to the same relative
+// path under toPrefix, in one transaction: if any destination already holds
+// a document, nothing is copied and the error, naming it, wraps
+// ErrAlreadyExists. Both prefixes are treated as directories, so "docs"
+// copies docs/a but not docsx/a. Returns the copied destination paths, or
+// ErrNotFound if there are no documents under fromPrefix.
+func (s *SQLiteStore) CopyPrefix(ctx context.Context, fromPrefix, toPrefix, copier string, opts CopyOptions) ([]string, error) {
+	fromPrefix = dirPrefix(fromPrefix)
+	toPrefix = dirPrefix(toPrefix)
+	if fromPrefix == "/" || toPrefix == "/" {
+		return nil, errors.New("copy prefix cannot be empty")
+	}
+	if fromPrefix == toPrefix {
+		return nil, errors.New("source and destination prefixes are the same")
+	}
+
+	var copied []string
+	now := time.Now().Unix()
+	err := s.Tx(ctx, func(tx *sql.Tx) error {
+		// Select before copying, so a destination nested in the source
+		// (docs/ -> docs/backup/) is not copied into itself.
+		rows, err := tx.QueryContext(ctx, `SELECT DISTINCT path FROM documents
+			WHERE deleted_at IS NULL AND path LIKE ? ORDER BY path`, fromPrefix+"%")
+		if err != nil {
+			return fmt.Errorf("list %s: %w", fromPrefix, err)
 		}
-		if n > 0 {
-			return ErrAlreadyExists
+		var sources []string
+		for rows.Next() {
+			var p string
+			if err := rows.Scan(&p); err != nil {
+				rows.Close()
+				return fmt.Errorf("scan path: %w", err)
+			}
+			// LIKE treats _ and % in the prefix as wildcards
+			if strings.HasPrefix(p, fromPrefix) {
+				sources = append(sources, p)
+			}
 		}
-
-		// Get source document content
-		var content string
-		err := tx.QueryRowContext(ctx, `
-			SELECT content FROM documents
-			WHERE path = ? AND deleted_at IS NULL
-			ORDER BY version DESC LIMIT 1
-		`, from).Scan(&content)
-		if errors.Is(err, sql.ErrNoRows) {
-			return ErrNotFound
+		rows.Close()
+		if err := rows.Err(); err != nil {
+			return fmt.Errorf("list %s: %w", fromPrefix, err)
 		}
-		if err != nil {
-			return fmt.Errorf("read source %s: %w", from, err)
+		if len(sources) == 0 {
+			return ErrNotFound
 		}
 
-		// Create copy at version 1, using copier as author to track who performed the copy
-		id, err := genID()
-		if err != nil {
-			return err
-		}
-		_, err = tx.ExecContext(ctx, `
-			INSERT INTO documents (key, path, content, version, author, message, created_at)
-			VALUES (?, ?, ?, 1, ?, ?, ?)
-		`, id, to, content, copier, "Copied from "+from, time.Now().Unix())
-		if err != nil {
-			return fmt.Errorf("copy %s to %s: %w", from, to, err)
+		copied = make([]string, 0, len(sources))
+		for _, src := range sources {
+			dst, err := validate.Path(toPrefix+strings.TrimPrefix(src, fromPrefix), opts.MaxPath)
+			if err != nil {
+				return err
+			}
+			if err := copyTx(ctx, tx, src, dst, copier, now); err != nil {
+				return fmt.Errorf("copy %s to %s: %w", src, dst, err)
+			}
+			copied = append(copied, dst)
 		}
 		return nil
 	})
+	if err != nil {
+		return nil, err
+	}
+	return copied, nil
+}
+
+// dirPrefix returns prefix ending in exactly one "/".
+func dirPrefix(prefix string) string {
+	return strings.TrimRight(prefix, "/") + "/"
+}
+
+// copyTx copies the latest version of from to version 1 of to within tx.
+func copyTx(ctx context.Context, tx *sql.Tx, from, to, copier string, now int64) error {
+	// Check destination doesn't exist
+	var n int
+	if err := tx.QueryRowContext(ctx, `SELECT COUNT(*) FROM documents WHERE path = ? AND deleted_at IS NULL`, to).Scan(&n); err != nil {
+		return fmt.Errorf("check destination %s: %w", to, err)
+	}
+	if n > 0 {
+		return ErrAlreadyExists
+	}
+
+	// Get source document content, type and hash
+	var content, ct, hash string
+	err := tx.QueryRowContext(ctx, `
+		SELECT content, content_type, content_hash FROM documents
+		WHERE path = ? AND deleted_at IS NULL
+		ORDER BY version DESC LIMIT 1
+	`, from).Scan(&content, &ct, &hash)
+	if errors.Is(err, sql.ErrNoRows) {
+		return ErrNotFound
+	}
+	if err != nil {
+		return fmt.Errorf("read source %s: %w", from, err)
+	}
+
+	// Create copy at version 1, using copier as author to track who performed the copy
+	_, err = insertWithID(ctx, tx, "documents.key", `
+		INSERT INTO documents (key, path, content, version, author, message, created_at, content_type, content_hash)
+		VALUES (?, ?, ?, 1, ?, ?, ?, ?, ?)
+	`, to, content, copier, "Copied from "+from, now, ct, hash)
+	if err != nil {
+		return fmt.Errorf("copy %s to %s: %w", from, to, err)
+	}
+	return nil
 }
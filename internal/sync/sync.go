@@ -22,30 +22,39 @@ import (
 // Options configures a sync operation.
 type Options struct {
 	DryRun bool   // Show what would be synced without syncing
+	Prune  bool   // Soft-delete documents whose files are missing
+	Mirror bool   // The files directory mirrors the store (sync.files)
+	Safe   bool   // Skip files that conflict with database changes
 	Author string // Author for synced documents
 	Msg    string // Commit message for synced documents
 }
 
 // Result contains the outcome of a sync operation.
 type Result struct {
-	Updated int // Number of documents updated
-	Added   int // Number of documents added
+	Updated   int      // Number of documents updated
+	Added     int      // Number of documents added
+	Deleted   int      // Number of documents soft-deleted by Prune
+	Missing   []string // Documents with no file on disk, left alone without Prune
+	Conflicts []string // Documents changed both on disk and in the database
+	Paths     []string // Documents written or deleted, in order
 }
 
 // Changes represents detected filesystem changes.
 type Changes struct {
-	Changed []string // Paths of documents that were modified
-	Added   []string // Paths of new documents
+	Changed   []string // Paths of documents that were modified
+	Added     []string // Paths of new documents
+	Missing   []string // Paths of documents whose files were removed
+	Conflicts []string // Paths changed on disk and in the database since last sync
 }
 
 // Empty returns true if there are no changes.
 func (c Changes) Empty() bool {
-	return len(c.Changed) == 0 && len(c.Added) == 0
+	return c.Total() == 0
 }
 
 // Total returns the total number of changes.
 func (c Changes) Total() int {
-	return len(c.Changed) + len(c.Added)
+	return len(c.Changed) + len(c.Added) + len(c.Missing) + len(c.Conflicts)
 }
 
 // Run executes the sync operation, importing filesystem changes into the database.
@@ -53,13 +62,19 @@ func (c Changes) Total() int {
 func Run(ctx context.Context, w io.Writer, svc service.Service, filesDir string, db map[string]string, opts Options) (Result, error) {
 	var result Result
 
+	// Without the mirror most documents have no file, and pruning would
+	// delete every one of them.
+	if opts.Prune && !opts.Mirror {
+		return result, fmt.Errorf("prune requires the file mirror: set sync.files to true")
+	}
+
 	root, err := os.OpenRoot(filesDir)
 	if err != nil {
 		return result, fmt.Errorf("opening files directory: %w", err)
 	}
 	defer root.Close()
 
-	changes, err := detectChangesInRoot(root, db)
+	changes, err := detectChangesInRoot(root, db, opts.Mirror)
 	if err != nil {
 		return result, err
 	}
@@ -76,39 +91,88 @@ func Run(ctx context.Context, w io.Writer, svc service.Service, filesDir string,
 	prog := progress.New("Syncing", changes.Total())
 	defer prog.Done()
 
-	for _, p := range changes.Changed {
+	// importFile writes the file's content as a new version and records it
+	// as the last-synced state, even when the mirror is not otherwise kept.
+	importFile := func(p, verb string) error {
 		content, err := readFileInRoot(root, p)
 		if err != nil {
-			return result, fmt.Errorf("reading %s: %w", p, err)
+			return fmt.Errorf("reading %s: %w", p, err)
 		}
-
 		if opts.DryRun {
-			fmt.Fprintf(w, "Would update: %s\n", p)
-		} else {
-			if err := svc.Write(ctx, p, content, opts.Author, msg); err != nil {
-				return result, fmt.Errorf("updating %s: %w", p, err)
-			}
+			fmt.Fprintf(w, "Would %s: %s\n", verb, p)
+			return nil
+		}
+		if err := svc.Write(ctx, p, content, opts.Author, msg); err != nil {
+			return fmt.Errorf("%s %s: %w", verb, p, err)
+		}
+		if err := writeState(root, p, content); err != nil {
+			return fmt.Errorf("recording sync state for %s: %w", p, err)
+		}
+		return nil
+	}
+
+	for _, p := range changes.Changed {
+		if err := importFile(p, "update"); err != nil {
+			return result, err
+		}
+		if !opts.DryRun {
 			fmt.Fprintf(w, "Updated: %s\n", p)
 			result.Updated++
+			result.Paths = append(result.Paths, p)
 		}
 		prog.Increment()
 		prog.Print()
 	}
 
-	for _, p := range changes.Added {
-		content, err := readFileInRoot(root, p)
-		if err != nil {
-			return result, fmt.Errorf("reading %s: %w", p, err)
-		}
-
-		if opts.DryRun {
-			fmt.Fprintf(w, "Would add: %s\n", p)
+	// Both sides changed. Importing keeps the database edit in history but
+	// buries it under the file's content, so Safe leaves these for the user.
+	for _, p := range changes.Conflicts {
+		result.Conflicts = append(result.Conflicts, p)
+		if opts.Safe {
+			fmt.Fprintf(w, "Conflict: %s (skipped)\n", p)
 		} else {
-			if err := svc.Write(ctx, p, content, opts.Author, msg); err != nil {
-				return result, fmt.Errorf("adding %s: %w", p, err)
+			if err := importFile(p, "update"); err != nil {
+				return result, err
 			}
+			if !opts.DryRun {
+				fmt.Fprintf(w, "Updated: %s (conflict: database also changed)\n", p)
+				result.Updated++
+				result.Paths = append(result.Paths, p)
+			}
+		}
+		prog.Increment()
+		prog.Print()
+	}
+
+	for _, p := range changes.Added {
+		if err := importFile(p, "add"); err != nil {
+			return result, err
+		}
+		if !opts.DryRun {
 			fmt.Fprintf(w, "Added: %s\n", p)
 			result.Added++
+			result.Paths = append(result.Paths, p)
+		}
+		prog.Increment()
+		prog.Print()
+	}
+
+	// Without Prune the database stays authoritative for removals; a
+	// missing file is more often a partial mirror than an intent to delete.
+	for _, p := range changes.Missing {
+		switch {
+		case !opts.Prune:
+			fmt.Fprintf(w, "Missing: %s\n", p)
+			result.Missing = append(result.Missing, p)
+		case opts.DryRun:
+			fmt.Fprintf(w, "Would delete: %s\n", p)
+		default:
+			if err := svc.Delete(ctx, p); err != nil {
+				return result, fmt.Errorf("deleting %s: %w", p, err)
+			}
+			fmt.Fprintf(w, "Deleted: %s\n", p)
+			result.Deleted++
+			result.Paths = append(result.Paths, p)
 		}
 		prog.Increment()
 		prog.Print()
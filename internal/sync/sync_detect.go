@@ -2,7 +2,7 @@
 //
 // Separated from sync.go to isolate the directory scanning and diff logic.
 // Change detection compares the filesystem state against the database to find
-// added, changed, and (implicitly) deleted files.
+// added, changed, and deleted files.
 //
 // Security: Uses os.Root (Go 1.24+) to prevent path traversal attacks. All
 // filesystem access is confined to the sync directory - even maliciously
@@ -15,6 +15,7 @@ import (
 	"fmt"
 	"os"
 	"path/filepath"
+	"slices"
 	"strings"
 
 	"github.com/jpl-au/llmd/internal/path"
@@ -24,7 +25,9 @@ import (
 const MaxScanDepth = 100
 
 // detectChangesInRoot scans the os.Root for changes compared to the database.
-func detectChangesInRoot(root *os.Root, db map[string]string) (Changes, error) {
+// Documents are only reported missing when mirror is set, since otherwise
+// the directory was never expected to hold every document.
+func detectChangesInRoot(root *os.Root, db map[string]string, mirror bool) (Changes, error) {
 	var changes Changes
 
 	files, err := scanRootDir(root, "", 0)
@@ -32,6 +35,7 @@ func detectChangesInRoot(root *os.Root, db map[string]string) (Changes, error) {
 		return changes, err
 	}
 
+	seen := make(map[string]bool, len(files))
 	for _, rel := range files {
 		docPath := strings.TrimSuffix(rel, ".md")
 		docPath = strings.TrimSuffix(docPath, ".MD")
@@ -45,13 +49,19 @@ func detectChangesInRoot(root *os.Root, db map[string]string) (Changes, error) {
 			continue
 		}
 
+		seen[docPath] = true
+
 		content, err := readFileInRoot(root, docPath)
 		if err != nil {
 			return changes, err
 		}
 
 		if stored, exists := db[docPath]; exists {
-			if content != stored {
+			switch {
+			case content == stored:
+			case conflicted(root, docPath, content, stored):
+				changes.Conflicts = append(changes.Conflicts, docPath)
+			default:
 				changes.Changed = append(changes.Changed, docPath)
 			}
 		} else {
@@ -59,9 +69,44 @@ func detectChangesInRoot(root *os.Root, db map[string]string) (Changes, error) {
 		}
 	}
 
+	for docPath := range db {
+		// The scan never visits hidden directories, and a file that was
+		// never synced cannot have been removed, so neither says anything
+		// about whether the user meant to delete the document
+		if !mirror || seen[docPath] || hidden(docPath) {
+			continue
+		}
+		if _, ok := readState(root, docPath); ok {
+			changes.Missing = append(changes.Missing, docPath)
+		}
+	}
+	slices.Sort(changes.Missing)
+
 	return changes, nil
 }
 
+// conflicted reports whether both the file and the database have moved on
+// from the content last synced between them. Without recorded state there
+// is no common ancestor, so the file is assumed to be the only change.
+func conflicted(root *os.Root, docPath, file, stored string) bool {
+	base, ok := readState(root, docPath)
+	if !ok {
+		return false
+	}
+	return hashContent(file) != base && hashContent(stored) != base
+}
+
+// hidden reports whether any component of a document path is skipped by
+// scanRootDir.
+func hidden(docPath string) bool {
+	for part := range strings.SplitSeq(docPath, "/") {
+		if strings.HasPrefix(part, ".") {
+			return true
+		}
+	}
+	return false
+}
+
 // scanRootDir recursively finds all markdown files within an os.Root.
 // Depth is limited by MaxScanDepth to prevent DoS on deeply nested trees.
 func scanRootDir(root *os.Root, dir string, depth int) ([]string, error) {
@@ -77,6 +77,9 @@ func WriteFile(filesDir, path, content string) error {
 	if _, err := f.WriteString(content); err != nil {
 		return fmt.Errorf("writing file %s: %w", name, err)
 	}
+	if err := writeState(root, path, content); err != nil {
+		return fmt.Errorf("recording sync state for %s: %w", path, err)
+	}
 	return nil
 }
 
@@ -94,10 +97,10 @@ func RemoveFile(filesDir, path string) error {
 
 	name := path + ".md"
 	err = root.Remove(name)
-	if errors.Is(err, fs.ErrNotExist) {
-		return nil
+	if err != nil && !errors.Is(err, fs.ErrNotExist) {
+		return err
 	}
-	return err
+	return removeState(root, path)
 }
 
 // MoveFile moves a file in the filesystem mirror.
@@ -141,6 +144,9 @@ func MoveFile(filesDir, src, dst string) error {
 	if err := root.Remove(srcName); err != nil {
 		return fmt.Errorf("removing source file: %w", err)
 	}
+	if err := moveState(root, src, dst); err != nil {
+		return fmt.Errorf("moving sync state: %w", err)
+	}
 	return nil
 }
 
@@ -0,0 +1,97 @@
+// sync_state.go records the content each mirror file had when llmd last
+// wrote or imported it.
+//
+// Separated from sync_fs.go because this is bookkeeping about the mirror,
+// not the mirror itself. The recorded hash is the common ancestor for
+// three-way conflict detection: if both the file and the database differ
+// from it, both sides changed since the last sync.
+//
+// Design: One small file per document under a hidden directory rather than
+// a database table, so the state travels with the mirror it describes and
+// existing databases need no schema change. scanRootDir skips hidden
+// directories, so state files are never mistaken for documents. The
+// directory carries its own .gitignore because the state is per-checkout.
+
+package sync
+
+import (
+	"crypto/sha256"
+	"encoding/hex"
+	"errors"
+	"io"
+	"io/fs"
+	"os"
+	"path/filepath"
+	"strings"
+)
+
+// stateDir holds last-synced hashes, relative to the files directory.
+const stateDir = ".sync"
+
+// stateName returns the state file name for a document path.
+func stateName(path string) string {
+	return filepath.Join(stateDir, filepath.FromSlash(path)+".sha256")
+}
+
+// hashContent returns the hex SHA-256 of content.
+func hashContent(content string) string {
+	sum := sha256.Sum256([]byte(content))
+	return hex.EncodeToString(sum[:])
+}
+
+// writeState records content as the last-synced state of path.
+func writeState(root *os.Root, path, content string) error {
+	if err := mkdirAllInRoot(root, filepath.Dir(stateName(path))); err != nil {
+		return err
+	}
+	ignore := filepath.Join(stateDir, ".gitignore")
+	if _, err := root.Stat(ignore); errors.Is(err, fs.ErrNotExist) {
+		if err := writeDestFile(root, ignore, []byte("*\n")); err != nil {
+			return err
+		}
+	}
+	return writeDestFile(root, stateName(path), []byte(hashContent(content)))
+}
+
+// readState returns the last-synced hash of path. ok is false when no state
+// was recorded, e.g. for files mirrored before state tracking existed.
+func readState(root *os.Root, path string) (hash string, ok bool) {
+	f, err := root.Open(stateName(path))
+	if err != nil {
+		return "", false
+	}
+	defer f.Close()
+
+	data, err := io.ReadAll(f)
+	if err != nil {
+		return "", false
+	}
+	return strings.TrimSpace(string(data)), true
+}
+
+// removeState forgets the last-synced state of path.
+func removeState(root *os.Root, path string) error {
+	err := root.Remove(stateName(path))
+	if errors.Is(err, fs.ErrNotExist) {
+		return nil
+	}
+	return err
+}
+
+// moveState carries the last-synced state of src over to dst.
+func moveState(root *os.Root, src, dst string) error {
+	data, err := readSourceFile(root, stateName(src))
+	if errors.Is(err, fs.ErrNotExist) {
+		return nil
+	}
+	if err != nil {
+		return err
+	}
+	if err := mkdirAllInRoot(root, filepath.Dir(stateName(dst))); err != nil {
+		return err
+	}
+	if err := writeDestFile(root, stateName(dst), data); err != nil {
+		return err
+	}
+	return removeState(root, src)
+}
@@ -0,0 +1,163 @@
+// watch.go implements continuous sync by watching the files directory.
+//
+// Separated from sync.go because watching is a long-running loop around
+// Run rather than a different way of detecting changes. Each batch of
+// filesystem events triggers an ordinary sync, so watch mode inherits the
+// same conflict, prune, and path safety rules.
+//
+// Design: Editors often save by writing a temp file and renaming it, or
+// write in several chunks, producing a burst of events per save. Events are
+// debounced so one save becomes one version. Mirror writes made by Run
+// itself also raise events; the follow-up sync finds nothing to do.
+
+package sync
+
+import (
+	"context"
+	"fmt"
+	"io"
+	"os"
+	"path/filepath"
+	"strings"
+	"time"
+
+	"github.com/fsnotify/fsnotify"
+	"github.com/jpl-au/llmd/internal/service"
+)
+
+// DefaultDebounce is how long Watch waits after the last event before
+// syncing.
+const DefaultDebounce = 500 * time.Millisecond
+
+// WatchOptions configures a watch.
+type WatchOptions struct {
+	Options
+	Debounce time.Duration // Quiet period before syncing (0 = DefaultDebounce)
+
+	// OnSync is called after each batch with the result of the sync.
+	OnSync func(Result, error)
+}
+
+// Watch syncs filesDir into the database whenever files change, until ctx
+// is cancelled. An initial sync picks up changes made while not watching.
+func Watch(ctx context.Context, w io.Writer, svc service.Service, filesDir string, opts WatchOptions) error {
+	if opts.DryRun {
+		return fmt.Errorf("watch cannot be combined with dry run")
+	}
+	debounce := opts.Debounce
+	if debounce <= 0 {
+		debounce = DefaultDebounce
+	}
+
+	watcher, err := fsnotify.NewWatcher()
+	if err != nil {
+		return fmt.Errorf("creating watcher: %w", err)
+	}
+	defer watcher.Close()
+
+	if err := watchTree(watcher, filesDir, 0); err != nil {
+		return err
+	}
+
+	syncNow := func() {
+		result, err := runOnce(ctx, w, svc, filesDir, opts.Options)
+		if opts.OnSync != nil {
+			opts.OnSync(result, err)
+		}
+	}
+	syncNow()
+
+	// A stopped timer with a drained channel: armed by the first event
+	timer := time.NewTimer(debounce)
+	if !timer.Stop() {
+		<-timer.C
+	}
+
+	for {
+		select {
+		case <-ctx.Done():
+			return nil
+		case ev, ok := <-watcher.Events:
+			if !ok {
+				return nil
+			}
+			if !relevant(filesDir, ev) {
+				continue
+			}
+			// New directories are not watched automatically
+			if ev.Has(fsnotify.Create) {
+				if info, err := os.Stat(ev.Name); err == nil && info.IsDir() {
+					if err := watchTree(watcher, ev.Name, 0); err != nil {
+						fmt.Fprintf(w, "Watch: %v\n", err)
+					}
+				}
+			}
+			timer.Reset(debounce)
+		case err, ok := <-watcher.Errors:
+			if !ok {
+				return nil
+			}
+			fmt.Fprintf(w, "Watch: %v\n", err)
+		case <-timer.C:
+			syncNow()
+		}
+	}
+}
+
+// runOnce syncs against the database's current content.
+func runOnce(ctx context.Context, w io.Writer, svc service.Service, filesDir string, opts Options) (Result, error) {
+	docs, err := svc.List(ctx, "", false, false)
+	if err != nil {
+		return Result{}, fmt.Errorf("list documents: %w", err)
+	}
+	db := make(map[string]string, len(docs))
+	for _, d := range docs {
+		db[d.Path] = d.Content
+	}
+	return Run(ctx, w, svc, filesDir, db, opts)
+}
+
+// watchTree adds dir and its non-hidden subdirectories to the watcher,
+// mirroring what scanRootDir visits.
+func watchTree(watcher *fsnotify.Watcher, dir string, depth int) error {
+	if depth > MaxScanDepth {
+		return fmt.Errorf("directory depth exceeds limit of %d", MaxScanDepth)
+	}
+	if err := watcher.Add(dir); err != nil {
+		return fmt.Errorf("watching %s: %w", dir, err)
+	}
+	entries, err := os.ReadDir(dir)
+	if err != nil {
+		return fmt.Errorf("reading %s: %w", dir, err)
+	}
+	for _, e := range entries {
+		if !e.IsDir() || strings.HasPrefix(e.Name(), ".") {
+			continue
+		}
+		if err := watchTree(watcher, filepath.Join(dir, e.Name()), depth+1); err != nil {
+			return err
+		}
+	}
+	return nil
+}
+
+// relevant reports whether an event could change what Run would sync:
+// markdown files and directories, outside hidden paths. Editor swap and
+// backup files are ignored so they do not trigger empty syncs.
+func relevant(filesDir string, ev fsnotify.Event) bool {
+	if ev.Op == fsnotify.Chmod {
+		return false
+	}
+	rel, err := filepath.Rel(filesDir, ev.Name)
+	if err != nil {
+		return false
+	}
+	if hidden(filepath.ToSlash(rel)) {
+		return false
+	}
+	if strings.HasSuffix(strings.ToLower(rel), ".md") {
+		return true
+	}
+	// Directory creation or removal; anything with an extension is a file
+	return filepath.Ext(rel) == ""
+}
@@ -1,17 +1,21 @@
 // Package tag provides document tagging operations for the CLI layer.
 //
-// This package orchestrates tag add/remove/list operations, handling
+// This package orchestrates tag add/remove/list/rename operations, handling
 // output formatting. The service layer handles path/key resolution.
 
 package tag
 
 import (
+	"cmp"
 	"context"
 	"fmt"
 	"io"
+	"slices"
+	"strings"
 
 	"github.com/jpl-au/llmd/internal/service"
 	"github.com/jpl-au/llmd/internal/store"
+	"github.com/jpl-au/llmd/internal/validate"
 )
 
 // Result contains the outcome of a tag operation.
@@ -62,6 +66,142 @@ func Remove(ctx context.Context, w io.Writer, svc service.Service, path, tag str
 	return result, nil
 }
 
+// BulkResult contains the outcome of tagging several documents at once.
+type BulkResult struct {
+	Tag   string   `json:"tag"`
+	Paths []string `json:"paths"`
+	Count int      `json:"count"` // Documents tagged
+}
+
+// AddGlob adds a tag to every document whose path matches pattern.
+func AddGlob(ctx context.Context, w io.Writer, svc service.Service, pattern, tag string) (BulkResult, error) {
+	paths, err := svc.Glob(ctx, pattern)
+	if err != nil {
+		return BulkResult{Tag: tag, Paths: []string{}}, err
+	}
+	return addAll(ctx, w, svc, paths, tag)
+}
+
+// AddSearch adds a tag to every document matching the full-text query.
+func AddSearch(ctx context.Context, w io.Writer, svc service.Service, query, tag string) (BulkResult, error) {
+	docs, err := svc.Search(ctx, query, "", false, false)
+	if err != nil {
+		return BulkResult{Tag: tag, Paths: []string{}}, err
+	}
+	paths := make([]string, len(docs))
+	for i, d := range docs {
+		paths[i] = d.Path
+	}
+	return addAll(ctx, w, svc, paths, tag)
+}
+
+// addAll tags each of paths in turn. The tag is validated up front so an
+// invalid tag fails before any document is touched.
+func addAll(ctx context.Context, w io.Writer, svc service.Service, paths []string, tag string) (BulkResult, error) {
+	result := BulkResult{Tag: tag, Paths: []string{}}
+	if err := validate.Tag(tag); err != nil {
+		return result, err
+	}
+
+	for _, p := range paths {
+		if err := svc.Tag(ctx, p, tag, store.NewTagOptions()); err != nil {
+			return result, err
+		}
+		result.Paths = append(result.Paths, p)
+		fmt.Fprintf(w, "Added tag %q to %s\n", tag, p)
+	}
+	result.Count = len(result.Paths)
+
+	noun := "documents"
+	if result.Count == 1 {
+		noun = "document"
+	}
+	fmt.Fprintf(w, "Tagged %d %s\n", result.Count, noun)
+	return result, nil
+}
+
+// Count is a tag and the number of documents that have it.
+type Count struct {
+	Tag   string `json:"tag"`
+	Count int    `json:"count"`
+}
+
+// Counts lists every tag with the number of documents that have it, most
+// used first; tags used equally often are in alphabetical order.
+func Counts(ctx context.Context, w io.Writer, svc service.Service) ([]Count, error) {
+	m, err := svc.TagCounts(ctx, store.NewTagOptions())
+	if err != nil {
+		return nil, err
+	}
+
+	counts := make([]Count, 0, len(m))
+	for t, n := range m {
+		counts = append(counts, Count{Tag: t, Count: n})
+	}
+	slices.SortFunc(counts, func(a, b Count) int {
+		if c := cmp.Compare(b.Count, a.Count); c != 0 {
+			return c
+		}
+		return strings.Compare(a.Tag, b.Tag)
+	})
+
+	for _, c := range counts {
+		fmt.Fprintf(w, "%s\t%d\n", c.Tag, c.Count)
+	}
+	return counts, nil
+}
+
+// Tagged is a document with its complete tag list.
+type Tagged struct {
+	store.DocJSON
+	Tags []string `json:"tags"`
+}
+
+// Find returns the documents under prefix that have tag, each with all of
+// its tags, so a caller tagging one document can see related ones and how
+// they are organised.
+func Find(ctx context.Context, svc service.Service, prefix, tag string, includeDeleted bool) ([]Tagged, error) {
+	docs, err := svc.ListByTag(ctx, prefix, tag, includeDeleted, false, store.NewTagOptions())
+	if err != nil {
+		return nil, err
+	}
+	sets, err := svc.TagSets(ctx, tag, store.NewTagOptions())
+	if err != nil {
+		return nil, err
+	}
+
+	result := make([]Tagged, len(docs))
+	for i := range docs {
+		result[i] = Tagged{DocJSON: docs[i].ToJSON(false), Tags: sets[docs[i].Path]}
+	}
+	return result, nil
+}
+
+// RenameResult contains the outcome of a tag rename.
+type RenameResult struct {
+	From  string `json:"from"`
+	To    string `json:"to"`
+	Count int64  `json:"count"` // Documents affected
+}
+
+// Rename renames a tag across every document that has it.
+func Rename(ctx context.Context, w io.Writer, svc service.Service, from, to string) (RenameResult, error) {
+	result := RenameResult{From: from, To: to}
+
+	count, err := svc.RenameTag(ctx, from, to, store.NewTagOptions())
+	if err != nil {
+		return result, err
+	}
+	result.Count = count
+
+	noun := "documents"
+	if count == 1 {
+		noun = "document"
+	}
+	fmt.Fprintf(w, "Renamed tag %q to %q on %d %s\n", from, to, count, noun)
+	return result, nil
+}
+
 // List lists tags for a document or all tags if path is empty.
 // Path can be a document path or key.
 func List(ctx context.Context, w io.Writer, svc service.Service, path string) (Result, error) {
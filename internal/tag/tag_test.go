@@ -3,7 +3,6 @@ package tag_test
 import (
 	"bytes"
 	"context"
-	"os"
 	"testing"
 
 	"github.com/jpl-au/llmd/internal/document"
@@ -13,30 +12,14 @@ import (
 	"github.com/stretchr/testify/require"
 )
 
-// setupService creates a temporary service and returns it along with a cleanup function.
+// setupService creates an in-memory service and returns it along with a cleanup function.
 func setupService(t *testing.T) (service.Service, func()) {
 	t.Helper()
 
-	tmpDir, err := os.MkdirTemp("", "llmd-tag-test-*")
-	require.NoError(t, err, "creating temp dir")
-
-	cwd, err := os.Getwd()
-	require.NoError(t, err, "getting cwd")
-
-	require.NoError(t, os.Chdir(tmpDir), "chdir to temp")
-
-	require.NoError(t, document.Init(true, "", false, ""), "init document service")
-
-	svc, err := document.New("")
+	svc, err := document.NewMemory()
 	require.NoError(t, err, "creating service")
 
-	cleanup := func() {
-		svc.Close()
-		_ = os.Chdir(cwd)
-		os.RemoveAll(tmpDir)
-	}
-
-	return svc, cleanup
+	return svc, func() { svc.Close() }
 }
 
 func TestAdd_ResolvesKeyToPath(t *testing.T) {
@@ -144,3 +127,32 @@ func TestList_EmptyPath(t *testing.T) {
 	assert.Equal(t, "", result.Path)
 	assert.Len(t, result.Tags, 2)
 }
+
+func TestFind_IncludesFullTagSets(t *testing.T) {
+	svc, cleanup := setupService(t)
+	defer cleanup()
+	ctx := context.Background()
+
+	for _, p := range []string{"docs/a", "docs/b", "notes/c"} {
+		require.NoError(t, svc.Write(ctx, p, "content", "tester", "initial"))
+	}
+	var buf bytes.Buffer
+	for _, pt := range [][2]string{
+		{"docs/a", "api"}, {"docs/a", "v1"},
+		{"docs/b", "api"}, {"docs/b", "draft"}, {"docs/b", "v2"},
+		{"notes/c", "api"},
+	} {
+		_, err := tag.Add(ctx, &buf, svc, pt[0], pt[1])
+		require.NoError(t, err)
+	}
+
+	docs, err := tag.Find(ctx, svc, "docs/", "api", false)
+	require.NoError(t, err)
+	require.Len(t, docs, 2)
+
+	assert.Equal(t, "docs/a", docs[0].Path)
+	assert.Equal(t, []string{"api", "v1"}, docs[0].Tags)
+	assert.Equal(t, "docs/b", docs[1].Path)
+	assert.Equal(t, []string{"api", "draft", "v2"}, docs[1].Tags)
+	assert.Empty(t, docs[0].Content, "Find should not return content")
+}
@@ -0,0 +1,171 @@
+// Package template creates documents from reusable skeletons.
+//
+// Templates are ordinary documents stored under the reserved .templates/
+// prefix, so they are versioned, searchable and editable with the usual
+// commands. Creating a document from one copies its content, filling in a
+// few {{placeholders}} along the way.
+package template
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"io"
+	"path"
+	"regexp"
+	"slices"
+	"strings"
+	"time"
+
+	norm "github.com/jpl-au/llmd/internal/path"
+	"github.com/jpl-au/llmd/internal/service"
+	"github.com/jpl-au/llmd/internal/store"
+)
+
+// Prefix is where templates live: the template "meeting" is the document
+// .templates/meeting.
+const Prefix = ".templates"
+
+// Options configures creating a document from a template.
+type Options struct {
+	Template string // Template name, without the Prefix
+	Force    bool   // Add a version if the document already exists
+	Author   string // Who is creating the document
+	Message  string // Version message (default "From template <name>")
+}
+
+// Result contains the outcome of creating a document from a template.
+type Result struct {
+	Path     string `json:"path"`
+	Template string `json:"template"`
+}
+
+// Vars returns the placeholder values for a document created at docPath by
+// author at time now.
+//
+//	{{date}}    2006-01-02
+//	{{time}}    15:04
+//	{{author}}  the author
+//	{{path}}    the new document's path
+//	{{title}}   the last element of the path
+func Vars(docPath, author string, now time.Time) map[string]string {
+	return map[string]string{
+		"date":   now.Format(time.DateOnly),
+		"time":   now.Format("15:04"),
+		"author": author,
+		"path":   docPath,
+		"title":  path.Base(docPath),
+	}
+}
+
+// Expand replaces each {{name}} in content with vars[name]. Placeholders
+// with no value are left as they are, so a template can contain literal
+// braces meant for something else.
+func Expand(content string, vars map[string]string) string {
+	pairs := make([]string, 0, len(vars)*2)
+	for k, v := range vars {
+		pairs = append(pairs, "{{"+k+"}}", v)
+	}
+	return strings.NewReplacer(pairs...).Replace(content)
+}
+
+// placeholder matches {{name}} where name could be set with --set. Other
+// brace forms, such as {{include:...}} directives, are not placeholders.
+var placeholder = regexp.MustCompile(`\{\{([A-Za-z0-9_.-]+)\}\}`)
+
+// varName matches the names a placeholder may use.
+var varName = regexp.MustCompile(`^[A-Za-z0-9_.-]+$`)
+
+// ParseVars parses key=value pairs, as given to write --set. The value may
+// be empty and may itself contain "=".
+func ParseVars(pairs []string) (map[string]string, error) {
+	vars := make(map[string]string, len(pairs))
+	for _, p := range pairs {
+		k, v, ok := strings.Cut(p, "=")
+		if !ok {
+			return nil, fmt.Errorf("invalid variable %q: expected key=value", p)
+		}
+		if !varName.MatchString(k) {
+			return nil, fmt.Errorf("invalid variable name %q: use letters, digits, '_', '.' or '-'", k)
+		}
+		vars[k] = v
+	}
+	return vars, nil
+}
+
+// Unresolved returns the names of placeholders left in content, in order of
+// first appearance.
+func Unresolved(content string) []string {
+	var names []string
+	for _, m := range placeholder.FindAllStringSubmatch(content, -1) {
+		if !slices.Contains(names, m[1]) {
+			names = append(names, m[1])
+		}
+	}
+	return names
+}
+
+// Run creates the document at docPath from the named template.
+func Run(ctx context.Context, w io.Writer, svc service.Service, docPath string, opts Options) (Result, error) {
+	result := Result{Path: docPath, Template: opts.Template}
+	if opts.Template == "" {
+		return result, fmt.Errorf("template name is required")
+	}
+
+	docPath, err := norm.Normalise(docPath)
+	if err != nil {
+		return result, err
+	}
+	result.Path = docPath
+
+	tmpl, err := svc.Latest(ctx, Prefix+"/"+opts.Template, false)
+	if errors.Is(err, store.ErrNotFound) {
+		return result, fmt.Errorf("template %q not found (see 'llmd template ls')", opts.Template)
+	}
+	if err != nil {
+		return result, err
+	}
+
+	if !opts.Force {
+		exists, err := svc.Exists(ctx, docPath)
+		if err != nil {
+			return result, err
+		}
+		if exists {
+			return result, fmt.Errorf("%s: %w (use --force to add a version)", docPath, store.ErrAlreadyExists)
+		}
+	}
+
+	content := Expand(tmpl.Content, Vars(docPath, opts.Author, time.Now()))
+	msg := opts.Message
+	if msg == "" {
+		msg = "From template " + opts.Template
+	}
+	if err := svc.Write(ctx, docPath, content, opts.Author, msg); err != nil {
+		return result, err
+	}
+
+	fmt.Fprintf(w, "Created %s from template %s\n", docPath, opts.Template)
+	return result, nil
+}
+
+// ListResult contains the available template names.
+type ListResult struct {
+	Templates []string `json:"templates"`
+}
+
+// List writes the names of the available templates, one per line.
+func List(ctx context.Context, w io.Writer, svc service.Service) (ListResult, error) {
+	result := ListResult{Templates: []string{}}
+	paths, err := svc.ListPaths(ctx, Prefix+"/")
+	if err != nil {
+		return result, err
+	}
+	for _, p := range paths {
+		if name, ok := strings.CutPrefix(p, Prefix+"/"); ok {
+			result.Templates = append(result.Templates, name)
+			fmt.Fprintln(w, name)
+		}
+	}
+	return result, nil
+}
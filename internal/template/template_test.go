@@ -0,0 +1,56 @@
+package template
+
+import (
+	"testing"
+	"time"
+)
+
+func TestExpand(t *testing.T) {
+	now := time.Date(2026, 3, 4, 9, 30, 0, 0, time.UTC)
+	vars := Vars("docs/notes/today", "alice", now)
+
+	tests := []struct {
+		name, in, want string
+	}{
+		{"date", "# Meeting {{date}}", "# Meeting 2026-03-04"},
+		{"time", "{{time}}", "09:30"},
+		{"author", "By {{author}}", "By alice"},
+		{"path and title", "{{path}} ({{title}})", "docs/notes/today (today)"},
+		{"repeated", "{{date}}/{{date}}", "2026-03-04/2026-03-04"},
+		{"unknown left alone", "{{owner}} {{ date }}", "{{owner}} {{ date }}"},
+		{"no placeholders", "plain text", "plain text"},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			if got := Expand(tt.in, vars); got != tt.want {
+				t.Errorf("Expand(%q) = %q, want %q", tt.in, got, tt.want)
+			}
+		})
+	}
+}
+
+func TestParseVars(t *testing.T) {
+	vars, err := ParseVars([]string{"a=1", "b=x=y", "empty="})
+	if err != nil {
+		t.Fatalf("ParseVars() error = %v", err)
+	}
+	want := map[string]string{"a": "1", "b": "x=y", "empty": ""}
+	for k, v := range want {
+		if vars[k] != v {
+			t.Errorf("vars[%q] = %q, want %q", k, vars[k], v)
+		}
+	}
+
+	for _, bad := range []string{"novalue", "=1", "a b=1", "a}}=1"} {
+		if _, err := ParseVars([]string{bad}); err == nil {
+			t.Errorf("ParseVars(%q) = nil error, want error", bad)
+		}
+	}
+}
+
+func TestUnresolved(t *testing.T) {
+	got := Unresolved("{{a}} {{b}} {{a}} {{include:docs/x}} {{ c }}")
+	if len(got) != 2 || got[0] != "a" || got[1] != "b" {
+		t.Errorf("Unresolved() = %v, want [a b]", got)
+	}
+}
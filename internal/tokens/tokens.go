@@ -0,0 +1,147 @@
+// Package tokens estimates how many LLM tokens documents will take.
+//
+// Exact counts depend on each model's tokenizer, which llmd does not ship,
+// so the count is estimated. An Estimator turns content into a token count;
+// Estimators holds the built-in ones by name, and a caller can add its own
+// before Run looks them up.
+//
+// Design: The default, "text", approximates a byte-pair tokenizer: a run of
+// letters or digits costs one token per four characters (rounded up), as
+// common English words are one token and long identifiers split; every
+// other non-space character, which includes punctuation, markup and every
+// non-ASCII character such as those of CJK text, costs one token. That
+// keeps markdown, code and non-English text from being badly undercounted,
+// which a flat characters-divided-by-four estimate does. "chars" is that
+// flat estimate and "words" the other common rule of thumb, for comparison.
+package tokens
+
+import (
+	"context"
+	"fmt"
+	"io"
+	"slices"
+	"strings"
+	"unicode"
+	"unicode/utf8"
+
+	"github.com/jpl-au/llmd/internal/resolve"
+	"github.com/jpl-au/llmd/internal/service"
+)
+
+// DefaultMethod is the estimator used when none is named.
+const DefaultMethod = "text"
+
+// Estimator returns the estimated token count of content.
+type Estimator func(content string) int
+
+// Estimators holds the available estimators by name.
+var Estimators = map[string]Estimator{
+	"text":  Text,
+	"chars": Chars,
+	"words": Words,
+}
+
+// Methods returns the names of the available estimators, sorted.
+func Methods() []string {
+	names := make([]string, 0, len(Estimators))
+	for name := range Estimators {
+		names = append(names, name)
+	}
+	slices.Sort(names)
+	return names
+}
+
+// Options configures an estimate.
+type Options struct {
+	Method string // Estimator name (default DefaultMethod)
+	Budget int    // If > 0, report whether the total fits in this many tokens
+}
+
+// Estimate is the token count of one document.
+type Estimate struct {
+	Path   string `json:"path"`
+	Tokens int    `json:"tokens"`
+}
+
+// Result contains the per-document estimates and their total.
+type Result struct {
+	Method    string     `json:"method"`
+	Documents []Estimate `json:"documents"`
+	Total     int        `json:"total"`
+	Budget    int        `json:"budget,omitempty"`
+	Fits      *bool      `json:"fits,omitempty"` // Set when Budget is
+}
+
+// Run estimates the tokens of every document named by targets, as
+// resolve.Documents selects them, and writes one line per document and a
+// total.
+func Run(ctx context.Context, w io.Writer, svc service.Service, targets []string, opts Options) (Result, error) {
+	method := opts.Method
+	if method == "" {
+		method = DefaultMethod
+	}
+	estimate, ok := Estimators[method]
+	if !ok {
+		return Result{}, fmt.Errorf("unknown method %q (available: %s)", method, strings.Join(Methods(), ", "))
+	}
+	if opts.Budget < 0 {
+		return Result{}, fmt.Errorf("budget must be >= 0, got %d", opts.Budget)
+	}
+
+	docs, err := resolve.Documents(ctx, svc, targets)
+	if err != nil {
+		return Result{}, err
+	}
+
+	result := Result{Method: method, Documents: make([]Estimate, len(docs)), Budget: opts.Budget}
+	for i, d := range docs {
+		n := estimate(d.Content)
+		result.Documents[i] = Estimate{Path: d.Path, Tokens: n}
+		result.Total += n
+		fmt.Fprintf(w, "%8d %s\n", n, d.Path)
+	}
+	if len(docs) > 1 {
+		fmt.Fprintf(w, "%8d total\n", result.Total)
+	}
+
+	if opts.Budget > 0 {
+		fits := result.Total <= opts.Budget
+		result.Fits = &fits
+		if fits {
+			fmt.Fprintf(w, "Fits in %d tokens (%d to spare)\n", opts.Budget, opts.Budget-result.Total)
+		} else {
+			fmt.Fprintf(w, "Exceeds %d tokens by %d\n", opts.Budget, result.Total-opts.Budget)
+		}
+	}
+	return result, nil
+}
+
+// Text estimates tokens as a byte-pair tokenizer splits text: a quarter
+// token per ASCII letter or digit in each run of them, rounded up, and one
+// token per other non-space character.
+func Text(content string) int {
+	n, run := 0, 0
+	for _, r := range content {
+		switch {
+		case r < utf8.RuneSelf && (unicode.IsLetter(r) || unicode.IsDigit(r)):
+			run++
+			continue
+		case unicode.IsSpace(r):
+		default:
+			n++
+		}
+		n += (run + 3) / 4
+		run = 0
+	}
+	return n + (run+3)/4
+}
+
+// Chars estimates one token per four characters.
+func Chars(content string) int {
+	return (utf8.RuneCountInString(content) + 3) / 4
+}
+
+// Words estimates four tokens per three words.
+func Words(content string) int {
+	return (len(strings.Fields(content))*4 + 2) / 3
+}
@@ -0,0 +1,32 @@
+package tokens
+
+import (
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+)
+
+func TestEstimators(t *testing.T) {
+	tests := []struct {
+		name, in           string
+		text, chars, words int
+	}{
+		{"empty", "", 0, 0, 0},
+		{"sentence", "Hello, world!", 6, 4, 3},
+		{"markdown", "# Title\n- item", 5, 4, 6},
+		{"identifier", "getDocumentByPath()", 7, 5, 2},
+		{"cjk", "日本語", 3, 1, 2},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			assert.Equal(t, tt.text, Text(tt.in), "text")
+			assert.Equal(t, tt.chars, Chars(tt.in), "chars")
+			assert.Equal(t, tt.words, Words(tt.in), "words")
+		})
+	}
+}
+
+func TestMethods(t *testing.T) {
+	assert.Equal(t, []string{"chars", "text", "words"}, Methods())
+	assert.Contains(t, Estimators, DefaultMethod)
+}
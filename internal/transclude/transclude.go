@@ -0,0 +1,105 @@
+// Package transclude inlines other documents at {{include:path}} directives.
+//
+// Shared boilerplate (licence headers, common warnings) can live in one
+// document and be included by many. The directive is what gets stored; it is
+// only expanded when a reader asks for it, so the included document stays the
+// single source and later edits to it show up everywhere it is included.
+package transclude
+
+import (
+	"context"
+	"fmt"
+	"regexp"
+	"slices"
+	"strings"
+
+	"github.com/jpl-au/llmd/internal/config"
+	"github.com/jpl-au/llmd/internal/service"
+	"github.com/jpl-au/llmd/internal/validate"
+)
+
+// MaxDepth bounds how deeply includes may nest, so a chain too long to be
+// deliberate is reported rather than followed.
+const MaxDepth = 32
+
+// directive matches {{include:path}}, allowing spaces around the path.
+var directive = regexp.MustCompile(`\{\{include:\s*([^{}\s]+)\s*\}\}`)
+
+// Expand returns content with every include directive replaced by the latest
+// content of the document it names, expanded in turn. path is the document
+// content came from, used to detect a document that includes itself. An
+// include cycle, a missing document or a result larger than maxSize bytes
+// (0 = config.DefaultMaxContent) is an error.
+//
+// Each document is expanded once per call and reused wherever it is
+// included again, so documents that share includes cost one lookup each.
+// maxSize bounds the output, which sharing can still make grow
+// exponentially: a document including the next twice, 30 deep, expands to
+// a billion copies of the last.
+func Expand(ctx context.Context, svc service.Service, path, content string, maxSize int64) (string, error) {
+	if maxSize <= 0 {
+		maxSize = config.DefaultMaxContent
+	}
+	e := &expander{svc: svc, maxSize: maxSize, done: map[string]string{}}
+	return e.expand(ctx, content, []string{path})
+}
+
+// expander holds the state of one Expand call.
+type expander struct {
+	svc     service.Service
+	maxSize int64
+	done    map[string]string // Expanded content by include target
+}
+
+func (e *expander) expand(ctx context.Context, content string, stack []string) (string, error) {
+	if len(stack) > MaxDepth {
+		return "", fmt.Errorf("includes nested more than %d deep: %s", MaxDepth, strings.Join(stack, " -> "))
+	}
+
+	var b strings.Builder
+	last := 0
+	for _, m := range directive.FindAllStringSubmatchIndex(content, -1) {
+		target := content[m[2]:m[3]]
+		// A finished expansion cannot lead back to the stack: if it did, the
+		// cycle would have been found while expanding it
+		inner, ok := e.done[target]
+		if !ok {
+			doc, err := e.svc.Latest(ctx, target, false)
+			if err != nil {
+				return "", fmt.Errorf("include %q in %s: %w", target, stack[len(stack)-1], err)
+			}
+			if slices.Contains(stack, doc.Path) {
+				return "", fmt.Errorf("include cycle: %s -> %s", strings.Join(stack, " -> "), doc.Path)
+			}
+			if inner, err = e.expand(ctx, doc.Content, append(slices.Clip(stack), doc.Path)); err != nil {
+				return "", err
+			}
+			e.done[target] = inner
+		}
+		b.WriteString(content[last:m[0]])
+		// The directive usually sits on its own line, which already ends in
+		// a newline; keeping the included document's too would add a blank.
+		b.WriteString(strings.TrimSuffix(inner, "\n"))
+		last = m[1]
+		if err := e.checkSize(b.Len(), stack); err != nil {
+			return "", err
+		}
+	}
+	if last == 0 {
+		return content, nil
+	}
+	b.WriteString(content[last:])
+	if err := e.checkSize(b.Len(), stack); err != nil {
+		return "", err
+	}
+	return b.String(), nil
+}
+
+// checkSize returns an error if n bytes of expanded content exceed the limit.
+func (e *expander) checkSize(n int, stack []string) error {
+	if int64(n) > e.maxSize {
+		return fmt.Errorf("%w: expanding includes in %s exceeds the %d byte limit (limits.max_content)",
+			validate.ErrContentTooLarge, stack[0], e.maxSize)
+	}
+	return nil
+}
@@ -0,0 +1,111 @@
+package transclude_test
+
+import (
+	"context"
+	"fmt"
+	"strings"
+	"testing"
+
+	"github.com/jpl-au/llmd/internal/document"
+	"github.com/jpl-au/llmd/internal/service"
+	"github.com/jpl-au/llmd/internal/store"
+	"github.com/jpl-au/llmd/internal/transclude"
+	"github.com/jpl-au/llmd/internal/validate"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestExpand(t *testing.T) {
+	ctx := context.Background()
+	svc, err := document.NewMemory()
+	require.NoError(t, err)
+	defer svc.Close()
+
+	write := func(path, content string) {
+		t.Helper()
+		require.NoError(t, svc.Write(ctx, path, content, "tester", ""))
+	}
+	write("shared/licence", "MIT licence\n")
+	write("shared/footer", "---\n{{include:shared/licence}}\n")
+	write("loop/a", "a\n{{include:loop/b}}\n")
+	write("loop/b", "b\n{{include: loop/a }}\n")
+	write("self", "{{include:self}}")
+
+	t.Run("nested", func(t *testing.T) {
+		got, err := transclude.Expand(ctx, svc, "docs/x", "# X\n{{include:shared/footer}}\nend\n", 0)
+		require.NoError(t, err)
+		assert.Equal(t, "# X\n---\nMIT licence\nend\n", got)
+	})
+
+	t.Run("inline", func(t *testing.T) {
+		got, err := transclude.Expand(ctx, svc, "docs/x", "Licence: {{include:shared/licence}}.", 0)
+		require.NoError(t, err)
+		assert.Equal(t, "Licence: MIT licence.", got)
+	})
+
+	t.Run("no directives", func(t *testing.T) {
+		got, err := transclude.Expand(ctx, svc, "docs/x", "{{date}} {{include:}}", 0)
+		require.NoError(t, err)
+		assert.Equal(t, "{{date}} {{include:}}", got)
+	})
+
+	t.Run("cycle", func(t *testing.T) {
+		_, err := transclude.Expand(ctx, svc, "loop/a", "a\n{{include:loop/b}}\n", 0)
+		require.Error(t, err)
+		assert.Contains(t, err.Error(), "include cycle: loop/a -> loop/b -> loop/a")
+	})
+
+	t.Run("self", func(t *testing.T) {
+		_, err := transclude.Expand(ctx, svc, "self", "{{include:self}}", 0)
+		assert.ErrorContains(t, err, "include cycle")
+	})
+
+	t.Run("missing", func(t *testing.T) {
+		_, err := transclude.Expand(ctx, svc, "docs/x", "{{include:nope}}", 0)
+		assert.ErrorIs(t, err, store.ErrNotFound)
+		assert.ErrorContains(t, err, `include "nope" in docs/x`)
+	})
+}
+
+// countingService counts the documents looked up through it.
+type countingService struct {
+	service.Service
+	lookups int
+}
+
+func (c *countingService) Latest(ctx context.Context, path string, includeDeleted bool) (*store.Document, error) {
+	c.lookups++
+	return c.Service.Latest(ctx, path, includeDeleted)
+}
+
+func TestExpand_SharedIncludes(t *testing.T) {
+	ctx := context.Background()
+	svc, err := document.NewMemory()
+	require.NoError(t, err)
+	defer svc.Close()
+
+	// Each document includes the next twice, doubling the expansion
+	const depth = 25
+	for i := range depth {
+		content := "leaf\n"
+		if i < depth-1 {
+			next := fmt.Sprintf("{{include:t/d%d}}\n", i+1)
+			content = next + next
+		}
+		require.NoError(t, svc.Write(ctx, fmt.Sprintf("t/d%d", i), content, "tester", ""))
+	}
+
+	t.Run("each document looked up once", func(t *testing.T) {
+		c := &countingService{Service: svc}
+		got, err := transclude.Expand(ctx, c, "t/d14", "{{include:t/d15}}\n", 0)
+		require.NoError(t, err)
+		assert.Equal(t, strings.Repeat("leaf\n", 1<<9), got)
+		assert.Equal(t, depth-15, c.lookups)
+	})
+
+	t.Run("size limit", func(t *testing.T) {
+		_, err := transclude.Expand(ctx, svc, "t/x", "{{include:t/d0}}\n", 1<<20)
+		assert.ErrorIs(t, err, validate.ErrContentTooLarge)
+		assert.ErrorContains(t, err, "expanding includes in t/x")
+	})
+}
@@ -10,6 +10,12 @@
 
 package validate
 
+import (
+	"fmt"
+	"mime"
+	"strings"
+)
+
 // Content validates document content size.
 //
 // Validation rules:
@@ -18,9 +24,28 @@ package validate
 // Note: Only size is validated, not content format. Documents can contain any
 // UTF-8 text. The maxLen default (100MB via service config) prevents accidental
 // storage of huge files that would bloat the SQLite database.
+//
+// The error reports the actual and allowed sizes so a caller (often an LLM)
+// can tell how much to trim, or how many parts to split the document into.
 func Content(content string, maxLen int64) error {
-	if maxLen > 0 && int64(len(content)) > maxLen {
-		return ErrContentTooLarge
+	if n := int64(len(content)); maxLen > 0 && n > maxLen {
+		return fmt.Errorf("%w: %d bytes exceeds the %d byte limit by %d (limits.max_content)",
+			ErrContentTooLarge, n, maxLen, n-maxLen)
+	}
+	return nil
+}
+
+// ContentType validates a document content type such as "application/json".
+//
+// Validation rules:
+//   - Must parse as a media type (type/subtype, optional parameters)
+//
+// Note: The type is only recorded, never enforced against the content, so
+// any well-formed type is accepted, including ones llmd has no mapping for.
+func ContentType(ct string) error {
+	typ, _, err := mime.ParseMediaType(ct)
+	if err != nil || !strings.Contains(typ, "/") {
+		return fmt.Errorf("%w: %q (want type/subtype, e.g. application/json)", ErrInvalidContentType, ct)
 	}
 	return nil
 }
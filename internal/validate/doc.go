@@ -17,6 +17,7 @@
 // Tag validates tag strings (labels, not hierarchical identifiers).
 // Link validates relationships between documents.
 // Content validates document body size limits.
+// ContentType validates the media type recorded for a document.
 //
 // # Error Handling
 //
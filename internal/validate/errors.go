@@ -13,9 +13,10 @@ package validate
 import "errors"
 
 var (
-	ErrInvalidPath     = errors.New("invalid path")
-	ErrPathTooLong     = errors.New("path too long")
-	ErrContentTooLarge = errors.New("content too large")
-	ErrInvalidTag      = errors.New("invalid tag")
-	ErrInvalidLink     = errors.New("invalid link")
+	ErrInvalidPath        = errors.New("invalid path")
+	ErrPathTooLong        = errors.New("path too long")
+	ErrContentTooLarge    = errors.New("content too large")
+	ErrInvalidTag         = errors.New("invalid tag")
+	ErrInvalidLink        = errors.New("invalid link")
+	ErrInvalidContentType = errors.New("invalid content type")
 )
@@ -0,0 +1,46 @@
+// Package verify checks a store for corruption and broken references.
+//
+// A database committed to version control can be damaged by bad merges or
+// partial copies without any command noticing, since reads skip what they
+// cannot find. Verify gives operators and CI an explicit health check.
+package verify
+
+import (
+	"context"
+	"fmt"
+	"io"
+
+	"github.com/jpl-au/llmd/internal/service"
+	"github.com/jpl-au/llmd/internal/store"
+)
+
+// Result reports the outcome of a verification run.
+type Result struct {
+	OK     bool          `json:"ok"`
+	Issues []store.Issue `json:"issues"`
+}
+
+// Run verifies the store and writes a summary to w, one line per issue.
+// Issues are reported in the result rather than as an error; err is only
+// set when a check could not be run.
+func Run(ctx context.Context, w io.Writer, svc service.Service) (Result, error) {
+	issues, err := svc.Verify(ctx)
+	if err != nil {
+		return Result{}, err
+	}
+
+	result := Result{OK: len(issues) == 0, Issues: issues}
+	if result.Issues == nil {
+		result.Issues = []store.Issue{}
+	}
+
+	if result.OK {
+		fmt.Fprintln(w, "OK: no issues found")
+		return result, nil
+	}
+	for _, i := range issues {
+		fmt.Fprintf(w, "%s: %s\n", i.Check, i.Detail)
+	}
+	fmt.Fprintf(w, "%d issue(s) found\n", len(issues))
+	return result, nil
+}
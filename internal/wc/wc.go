@@ -0,0 +1,97 @@
+// Package wc counts the lines, words and characters of documents.
+//
+// Counts are taken from the stored content, so they match what cat prints
+// with --raw. Lines are counted as cat -n numbers them: a final line
+// without a trailing newline still counts, where Unix wc would not count
+// it. Words are runs of non-space characters and characters are Unicode
+// code points, not bytes.
+package wc
+
+import (
+	"context"
+	"fmt"
+	"io"
+	"strings"
+	"unicode/utf8"
+
+	"github.com/jpl-au/llmd/internal/resolve"
+	"github.com/jpl-au/llmd/internal/service"
+)
+
+// Options selects the counts to print. With none set, all three are
+// printed. JSON output always carries all three.
+type Options struct {
+	Lines bool
+	Words bool
+	Chars bool
+}
+
+// Count holds the counts of one document, or the total of several.
+type Count struct {
+	Path  string `json:"path"`
+	Lines int    `json:"lines"`
+	Words int    `json:"words"`
+	Chars int    `json:"chars"`
+}
+
+// Run counts every document named by targets, as resolve.Documents
+// selects them, and writes one line per document, plus a total when there
+// is more than one.
+func Run(ctx context.Context, w io.Writer, svc service.Service, targets []string, opts Options) ([]Count, error) {
+	if !opts.Lines && !opts.Words && !opts.Chars {
+		opts = Options{Lines: true, Words: true, Chars: true}
+	}
+
+	docs, err := resolve.Documents(ctx, svc, targets)
+	if err != nil {
+		return nil, err
+	}
+	counts := make([]Count, len(docs))
+	for i, d := range docs {
+		counts[i] = Of(d.Content)
+		counts[i].Path = d.Path
+	}
+
+	for _, c := range counts {
+		writeCount(w, c, opts)
+	}
+	if len(counts) > 1 {
+		total := Count{Path: "total"}
+		for _, c := range counts {
+			total.Lines += c.Lines
+			total.Words += c.Words
+			total.Chars += c.Chars
+		}
+		writeCount(w, total, opts)
+	}
+	return counts, nil
+}
+
+// Of returns the counts of content, leaving Path empty.
+func Of(content string) Count {
+	lines := strings.Count(content, "\n")
+	if content != "" && !strings.HasSuffix(content, "\n") {
+		lines++
+	}
+	return Count{
+		Lines: lines,
+		Words: len(strings.Fields(content)),
+		Chars: utf8.RuneCountInString(content),
+	}
+}
+
+// writeCount writes the selected counts of c in wc's column layout.
+func writeCount(w io.Writer, c Count, opts Options) {
+	var b strings.Builder
+	if opts.Lines {
+		fmt.Fprintf(&b, "%7d ", c.Lines)
+	}
+	if opts.Words {
+		fmt.Fprintf(&b, "%7d ", c.Words)
+	}
+	if opts.Chars {
+		fmt.Fprintf(&b, "%7d ", c.Chars)
+	}
+	b.WriteString(c.Path)
+	fmt.Fprintln(w, b.String())
+}